@@ -5,6 +5,8 @@
 
 package acl
 
+import "github.com/hashicorp/consul/sentinel"
+
 const (
 	WildcardPartitionName = ""
 	DefaultPartitionName  = ""
@@ -16,9 +18,13 @@ const (
 const DefaultNamespaceName = "default"
 
 type EnterpriseConfig struct {
-	// no fields in CE
+	// SentinelEvaluator evaluates the conditions of rules. Rules with a
+	// condition never grant access when it is nil.
+	SentinelEvaluator sentinel.Evaluator
 }
 
-func (_ *EnterpriseConfig) Close() {
-	// do nothing
+func (c *EnterpriseConfig) Close() {
+	if c.SentinelEvaluator != nil {
+		c.SentinelEvaluator.Close()
+	}
 }
@@ -5,11 +5,18 @@
 
 package acl
 
+import "github.com/hashicorp/consul/sentinel"
+
 // AuthorizerContext contains extra information that can be
 // used in the determination of an ACL enforcement decision.
 type AuthorizerContext struct {
 	// Peer is the name of the peer that the resource was imported from.
 	Peer string
+
+	// SentinelScope returns the details of the request that the conditions of
+	// rules are evaluated against. When it is nil, conditions that reference
+	// the request fail.
+	SentinelScope sentinel.ScopeFn
 }
 
 func (c *AuthorizerContext) PeerOrEmpty() string {
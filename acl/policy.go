@@ -63,6 +63,7 @@ type PolicyRules struct {
 	IdentityPrefixes      []*IdentityRule      `hcl:"identity_prefix,expand"`
 	Keys                  []*KeyRule           `hcl:"key,expand"`
 	KeyPrefixes           []*KeyRule           `hcl:"key_prefix,expand"`
+	KeyGlobs              []*KeyRule           `hcl:"key_glob,expand"`
 	Nodes                 []*NodeRule          `hcl:"node,expand"`
 	NodePrefixes          []*NodeRule          `hcl:"node_prefix,expand"`
 	Services              []*ServiceRule       `hcl:"service,expand"`
@@ -224,6 +225,17 @@ func (pr *PolicyRules) Validate(conf *Config) error {
 			return fmt.Errorf("Invalid key_prefix enterprise policy: %#v, got error: %v", kp, err)
 		}
 	}
+	for _, kp := range pr.KeyGlobs {
+		if kp.Prefix == "" {
+			return fmt.Errorf("Invalid key_glob policy: %#v, the pattern must not be empty", kp)
+		}
+		if !isPolicyValid(kp.Policy, true) {
+			return fmt.Errorf("Invalid key_glob policy: %#v", kp)
+		}
+		if err := kp.EnterpriseRule.Validate(kp.Policy, conf); err != nil {
+			return fmt.Errorf("Invalid key_glob enterprise policy: %#v, got error: %v", kp, err)
+		}
+	}
 
 	// Validate the node policies
 	for _, np := range pr.Nodes {
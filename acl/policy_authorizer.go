@@ -4,6 +4,8 @@
 package acl
 
 import (
+	"sort"
+
 	"github.com/armon/go-radix"
 )
 
@@ -26,6 +28,9 @@ type policyAuthorizer struct {
 	// keyRules contains the key exact-match policies
 	keyRules *radix.Tree
 
+	// keyGlobRules contains the key glob policies, sorted by pattern
+	keyGlobRules []*policyAuthorizerGlobRule
+
 	// nodeRules contains the node exact-match policies
 	nodeRules *radix.Tree
 
@@ -77,6 +82,12 @@ type policyAuthorizerRadixLeaf struct {
 	prefix *policyAuthorizerRule
 }
 
+// policyAuthorizerGlobRule is a key_glob rule along with its compiled pattern
+type policyAuthorizerGlobRule struct {
+	glob *keyGlob
+	policyAuthorizerRule
+}
+
 // getPolicy first attempts to get an exact match for the segment from the "exact" tree and then falls
 // back to getting the policy for the longest prefix from the "prefix" tree
 func getPolicy(segment string, tree *radix.Tree) (policy *policyAuthorizerRule, found bool) {
@@ -242,6 +253,24 @@ func (p *policyAuthorizer) loadRules(policy *PolicyRules) error {
 		}
 	}
 
+	// Load the key policy (glob matches)
+	for _, kp := range policy.KeyGlobs {
+		al, err := AccessLevelFromString(kp.Policy)
+		if err != nil {
+			return err
+		}
+		p.keyGlobRules = append(p.keyGlobRules, &policyAuthorizerGlobRule{
+			glob: compileKeyGlob(kp.Prefix),
+			policyAuthorizerRule: policyAuthorizerRule{
+				access:         al,
+				EnterpriseRule: kp.EnterpriseRule,
+			},
+		})
+	}
+	sort.Slice(p.keyGlobRules, func(i, j int) bool {
+		return p.keyGlobRules[i].glob.pattern < p.keyGlobRules[j].glob.pattern
+	})
+
 	// Load the node policy (exact matches)
 	for _, np := range policy.Nodes {
 		if err := insertPolicyIntoRadix(np.Name, np.Policy, &np.EnterpriseRule, p.nodeRules, false); err != nil {
@@ -662,9 +691,56 @@ func (p *policyAuthorizer) TrafficPermissionsWrite(prefix string, _ *AuthorizerC
 	return Default
 }
 
+// getKeyPolicy returns the rule that applies to the key. Key rules take
+// precedence in the following order:
+//
+//   - An exact match key rule.
+//   - A matching key_glob rule with a policy of deny.
+//   - The most specific of the longest matching key_prefix rule and the other
+//     matching key_glob rules. The specificity of a prefix is its length and
+//     the specificity of a glob the number of literal characters in it. When
+//     rules are equally specific the most restrictive one applies.
+func (p *policyAuthorizer) getKeyPolicy(key string) (policy *policyAuthorizerRule, found bool) {
+	if raw, ok := p.keyRules.Get(key); ok {
+		if leaf := raw.(*policyAuthorizerRadixLeaf); leaf.exact != nil {
+			return leaf.exact, true
+		}
+	}
+
+	specificity := 0
+	p.keyRules.WalkPath(key, func(path string, raw interface{}) bool {
+		if leaf := raw.(*policyAuthorizerRadixLeaf); leaf.prefix != nil {
+			policy = leaf.prefix
+			specificity = len(path)
+		}
+		return false
+	})
+
+	var denied *policyAuthorizerRule
+	for _, rule := range p.keyGlobRules {
+		if !rule.glob.match(key) {
+			continue
+		}
+		if rule.access == AccessDeny {
+			denied = &rule.policyAuthorizerRule
+			break
+		}
+		if policy == nil || rule.glob.literals > specificity ||
+			(rule.glob.literals == specificity && rule.access < policy.access) {
+			policy = &rule.policyAuthorizerRule
+			specificity = rule.glob.literals
+		}
+	}
+	if denied != nil {
+		return denied, true
+	}
+
+	return policy, policy != nil
+}
+
 // KeyRead returns if a key is allowed to be read
 func (p *policyAuthorizer) KeyRead(key string, _ *AuthorizerContext) EnforcementDecision {
-	if rule, ok := getPolicy(key, p.keyRules); ok {
+	if rule, ok := p.getKeyPolicy(key); ok {
 		return enforce(rule.access, AccessRead)
 	}
 	return Default
@@ -672,7 +748,7 @@ func (p *policyAuthorizer) KeyRead(key string, _ *AuthorizerContext) Enforcement
 
 // KeyList returns if a key is allowed to be listed
 func (p *policyAuthorizer) KeyList(key string, _ *AuthorizerContext) EnforcementDecision {
-	if rule, ok := getPolicy(key, p.keyRules); ok {
+	if rule, ok := p.getKeyPolicy(key); ok {
 		return enforce(rule.access, AccessList)
 	}
 	return Default
@@ -680,7 +756,7 @@ func (p *policyAuthorizer) KeyList(key string, _ *AuthorizerContext) Enforcement
 
 // KeyWrite returns if a key is allowed to be written
 func (p *policyAuthorizer) KeyWrite(key string, entCtx *AuthorizerContext) EnforcementDecision {
-	if rule, ok := getPolicy(key, p.keyRules); ok {
+	if rule, ok := p.getKeyPolicy(key); ok {
 		decision := enforce(rule.access, AccessWrite)
 		if decision == Allow {
 			return defaultIsAllow(p.enterprisePolicyAuthorizer.enforce(&rule.EnterpriseRule, entCtx))
@@ -696,13 +772,16 @@ func (p *policyAuthorizer) KeyWrite(key string, entCtx *AuthorizerContext) Enfor
 // the KV can be removed. For that reason we must be able to
 // delete everything under the prefix. First we must have "write"
 // on the prefix itself
-func (p *policyAuthorizer) KeyWritePrefix(prefix string, _ *AuthorizerContext) EnforcementDecision {
+func (p *policyAuthorizer) KeyWritePrefix(prefix string, entCtx *AuthorizerContext) EnforcementDecision {
 	// Conditions for Allow:
 	//   * The longest prefix match rule that would apply to the given prefix
 	//     grants AccessWrite
 	//   AND
 	//   * There are no rules (exact or prefix match) within/under the given prefix
 	//     that would NOT grant AccessWrite.
+	//   AND
+	//   * There are no glob rules that may match keys under the given prefix
+	//     that would NOT grant AccessWrite.
 	//
 	// Conditions for Deny:
 	//   * The longest prefix match rule that would apply to the given prefix
@@ -710,12 +789,17 @@ func (p *policyAuthorizer) KeyWritePrefix(prefix string, _ *AuthorizerContext) E
 	//   OR
 	//   * There is 1+ rules (exact or prefix match) within/under the given prefix
 	//     that do NOT grant AccessWrite.
+	//   OR
+	//   * There is 1+ glob rules that may match keys under the given prefix
+	//     that do NOT grant AccessWrite.
 	//
 	// Conditions for Default:
 	//   * There is no prefix match rule that would appy to the given prefix.
 	//   AND
 	//   * There are no rules (exact or prefix match) within/under the given prefix
 	//     that would NOT grant AccessWrite.
+	//
+	// Rules with a condition only grant AccessWrite when the condition holds.
 
 	baseAccess := Default
 
@@ -729,7 +813,7 @@ func (p *policyAuthorizer) KeyWritePrefix(prefix string, _ *AuthorizerContext) E
 			if rule.prefix.access != AccessWrite {
 				baseAccess = Deny
 			} else {
-				baseAccess = Allow
+				baseAccess = defaultIsAllow(p.enterprisePolicyAuthorizer.enforce(&rule.prefix.EnterpriseRule, entCtx))
 			}
 		}
 		return false
@@ -748,11 +832,13 @@ func (p *policyAuthorizer) KeyWritePrefix(prefix string, _ *AuthorizerContext) E
 	p.keyRules.WalkPrefix(prefix, func(path string, leaf interface{}) bool {
 		rule := leaf.(*policyAuthorizerRadixLeaf)
 
-		if rule.prefix != nil && rule.prefix.access != AccessWrite {
+		if rule.prefix != nil && (rule.prefix.access != AccessWrite ||
+			p.enterprisePolicyAuthorizer.enforce(&rule.prefix.EnterpriseRule, entCtx) == Deny) {
 			withinPrefixAccess = Deny
 			return true
 		}
-		if rule.exact != nil && rule.exact.access != AccessWrite {
+		if rule.exact != nil && (rule.exact.access != AccessWrite ||
+			p.enterprisePolicyAuthorizer.enforce(&rule.exact.EnterpriseRule, entCtx) == Deny) {
 			withinPrefixAccess = Deny
 			return true
 		}
@@ -766,6 +852,18 @@ func (p *policyAuthorizer) KeyWritePrefix(prefix string, _ *AuthorizerContext) E
 		return Deny
 	}
 
+	// Glob rules are not anchored at a prefix, so check each one that may
+	// match a key under the prefix.
+	for _, rule := range p.keyGlobRules {
+		if !rule.glob.matchPrefix(prefix) {
+			continue
+		}
+		if rule.access != AccessWrite ||
+			p.enterprisePolicyAuthorizer.enforce(&rule.EnterpriseRule, entCtx) == Deny {
+			return Deny
+		}
+	}
+
 	// either Default or Allow at this point. Allow if there was a prefix rule
 	// that was applicable and it granted write access. Default if there was
 	// no applicable rule.
@@ -5,14 +5,36 @@
 
 package acl
 
-// enterprisePolicyAuthorizer stub
-type enterprisePolicyAuthorizer struct{}
+import "github.com/hashicorp/consul/sentinel"
 
-func (authz *enterprisePolicyAuthorizer) init(*Config) {
-	// nothing to do
+// enterprisePolicyAuthorizer enforces the conditions of rules.
+type enterprisePolicyAuthorizer struct {
+	sentinel sentinel.Evaluator
 }
 
-func (authz *enterprisePolicyAuthorizer) enforce(_ *EnterpriseRule, _ *AuthorizerContext) EnforcementDecision {
+func (authz *enterprisePolicyAuthorizer) init(conf *Config) {
+	if conf != nil {
+		authz.sentinel = conf.SentinelEvaluator
+	}
+}
+
+// enforce returns Deny if the rule has a condition that fails for the request
+// and Default otherwise.
+func (authz *enterprisePolicyAuthorizer) enforce(rule *EnterpriseRule, ctx *AuthorizerContext) EnforcementDecision {
+	if rule == nil || rule.Condition == "" {
+		return Default
+	}
+	if authz.sentinel == nil {
+		return Deny
+	}
+
+	var scope map[string]interface{}
+	if ctx != nil && ctx.SentinelScope != nil {
+		scope = ctx.SentinelScope()
+	}
+	if !authz.sentinel.Execute(rule.Condition, sentinel.EnforcementLevelHardMandatory, scope) {
+		return Deny
+	}
 	return Default
 }
 
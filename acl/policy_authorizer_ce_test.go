@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build !consulent
+
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/sentinel"
+)
+
+func TestPolicySourceParse_Conditions(t *testing.T) {
+	conf := &Config{EnterpriseConfig: EnterpriseConfig{SentinelEvaluator: sentinel.New(nil)}}
+
+	cases := map[string]struct {
+		rules     string
+		expectErr string
+	}{
+		"key rule": {
+			rules: `key_prefix "app/" { policy = "write" condition = "value_size < 65536" }`,
+		},
+		"not a write rule": {
+			rules:     `key_prefix "app/" { policy = "read" condition = "value_size < 65536" }`,
+			expectErr: "a condition may only be set on rules with a policy of",
+		},
+		"invalid condition": {
+			rules:     `key "app/config" { policy = "write" condition = "size < 10" }`,
+			expectErr: `unknown variable "size"`,
+		},
+		"service rule": {
+			rules:     `service "web" { policy = "write" condition = "true" }`,
+			expectErr: "conditions are only supported on key rules",
+		},
+		"node rule": {
+			rules:     `node_prefix "" { policy = "write" condition = "true" }`,
+			expectErr: "conditions are only supported on key rules",
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			_, err := NewPolicyFromSource(tc.rules, conf, nil)
+			if tc.expectErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectErr)
+			}
+		})
+	}
+}
+
+func TestPolicyAuthorizer_Conditions(t *testing.T) {
+	policy, err := NewPolicyFromSource(`
+		key_prefix "" {
+			policy = "read"
+		}
+		key_prefix "app/" {
+			policy = "write"
+			condition = "value_size < 10 && cidrmatch(source_ip, \"10.0.0.0/8\")"
+		}
+		key "app/unrestricted" {
+			policy = "write"
+		}
+	`, nil, nil)
+	require.NoError(t, err)
+
+	conf := &Config{EnterpriseConfig: EnterpriseConfig{SentinelEvaluator: sentinel.New(nil)}}
+	authz, err := NewPolicyAuthorizer([]*Policy{policy}, conf)
+	require.NoError(t, err)
+
+	ctx := func(key, value, sourceIP string) *AuthorizerContext {
+		return &AuthorizerContext{
+			SentinelScope: func() map[string]interface{} {
+				scope := sentinel.ScopeKVUpsert(key, []byte(value), 0)
+				return sentinel.AddRequest(scope, sentinel.Request{SourceIP: sourceIP})
+			},
+		}
+	}
+
+	t.Run("condition holds", func(t *testing.T) {
+		require.Equal(t, Allow, authz.KeyWrite("app/config", ctx("app/config", "small", "10.1.2.3")))
+	})
+	t.Run("condition fails", func(t *testing.T) {
+		require.Equal(t, Deny, authz.KeyWrite("app/config", ctx("app/config", "much too large", "10.1.2.3")))
+		require.Equal(t, Deny, authz.KeyWrite("app/config", ctx("app/config", "small", "192.168.1.1")))
+	})
+	t.Run("no scope", func(t *testing.T) {
+		require.Equal(t, Deny, authz.KeyWrite("app/config", nil))
+	})
+	t.Run("reads are unconditional", func(t *testing.T) {
+		require.Equal(t, Allow, authz.KeyRead("app/config", nil))
+	})
+	t.Run("rule without condition", func(t *testing.T) {
+		require.Equal(t, Allow, authz.KeyWrite("app/unrestricted", nil))
+	})
+	t.Run("write prefix", func(t *testing.T) {
+		require.Equal(t, Allow, authz.KeyWritePrefix("app/", ctx("app/", "", "10.1.2.3")))
+		require.Equal(t, Deny, authz.KeyWritePrefix("app/", ctx("app/", "", "192.168.1.1")))
+	})
+
+	t.Run("no evaluator", func(t *testing.T) {
+		authz, err := NewPolicyAuthorizer([]*Policy{policy}, nil)
+		require.NoError(t, err)
+		require.Equal(t, Deny, authz.KeyWrite("app/config", ctx("app/config", "small", "10.1.2.3")))
+	})
+}
@@ -540,6 +540,81 @@ func TestPolicyAuthorizer(t *testing.T) {
 				{name: "AllAllowed", prefix: "*", check: checkAllowIntentionWrite},
 			},
 		},
+		"Key Globs": {
+			policy: &Policy{PolicyRules: PolicyRules{
+				Keys: []*KeyRule{
+					{
+						Prefix: "app/web/secrets/public",
+						Policy: PolicyRead,
+					},
+				},
+				KeyPrefixes: []*KeyRule{
+					{
+						Prefix: "app/",
+						Policy: PolicyRead,
+					},
+					{
+						Prefix: "app/web/secrets/shared/",
+						Policy: PolicyWrite,
+					},
+					{
+						Prefix: "app/api/config/legacy/",
+						Policy: PolicyList,
+					},
+					{
+						Prefix: "scratch/",
+						Policy: PolicyWrite,
+					},
+				},
+				KeyGlobs: []*KeyRule{
+					{
+						Prefix: "app/*/config/**",
+						Policy: PolicyWrite,
+					},
+					{
+						Prefix: "app/*/secrets/**",
+						Policy: PolicyDeny,
+					},
+					{
+						Prefix: "app/?/config/**",
+						Policy: PolicyRead,
+					},
+					{
+						Prefix: "scratch/*/tmp",
+						Policy: PolicyWrite,
+					},
+				},
+			}},
+			checks: []aclCheck{
+				// the key_prefix applies where no glob matches
+				{name: "PrefixRead", prefix: "app/web/readme", check: checkAllowKeyRead},
+				{name: "PrefixWrite", prefix: "app/web/readme", check: checkDenyKeyWrite},
+				// a more specific glob overrides a less specific prefix
+				{name: "GlobWrite", prefix: "app/web/config/port", check: checkAllowKeyWrite},
+				{name: "GlobNested", prefix: "app/web/config/db/port", check: checkAllowKeyWrite},
+				// * does not match across a /
+				{name: "GlobSegment", prefix: "app/web/v2/config/port", check: checkDenyKeyWrite},
+				// a more specific prefix overrides a less specific glob
+				{name: "PrefixOverGlobList", prefix: "app/api/config/legacy/port", check: checkAllowKeyList},
+				{name: "PrefixOverGlobWrite", prefix: "app/api/config/legacy/port", check: checkDenyKeyWrite},
+				// equally specific rules use the most restrictive policy
+				{name: "EquallySpecific", prefix: "app/x/config/port", check: checkDenyKeyWrite},
+				{name: "EquallySpecificRead", prefix: "app/x/config/port", check: checkAllowKeyRead},
+				// a denying glob overrides all prefix rules
+				{name: "GlobDeny", prefix: "app/web/secrets/db", check: checkDenyKeyRead},
+				{name: "GlobDenyPrefix", prefix: "app/web/secrets/shared/db", check: checkDenyKeyRead},
+				// but not exact matches
+				{name: "ExactOverGlob", prefix: "app/web/secrets/public", check: checkAllowKeyRead},
+				{name: "NoMatch", prefix: "other", check: checkDefaultKeyRead},
+				// globs never grant recursive writes, only key_prefix rules do
+				{name: "WritePrefixGlob", prefix: "app/web/config/", check: checkDenyKeyWritePrefix},
+				{name: "WritePrefix", prefix: "scratch/", check: checkAllowKeyWritePrefix},
+				// recursive writes are denied if a glob may match a key under
+				// the prefix without granting write
+				{name: "WritePrefixGlobDeny", prefix: "app/web/", check: checkDenyKeyWritePrefix},
+				{name: "WritePrefixGlobDenyDeep", prefix: "app/web/secrets/shared/d", check: checkDenyKeyWritePrefix},
+			},
+		},
 		"Intention Wildcards - all default": {
 			policy: &Policy{PolicyRules: PolicyRules{
 				Services: []*ServiceRule{
@@ -14,11 +14,25 @@ import (
 // EnterprisePolicyMeta stub
 type EnterprisePolicyMeta struct{}
 
-// EnterpriseRule stub
-type EnterpriseRule struct{}
+// EnterpriseRule holds the condition of a rule.
+type EnterpriseRule struct {
+	// Condition is an expression that must evaluate to true for the rule to
+	// grant write access. It is evaluated against the details of the request,
+	// such as the key being written and the source IP of the client. It is only
+	// supported on key, key_prefix and key_glob rules.
+	Condition string `hcl:"condition"`
+}
 
-func (r *EnterpriseRule) Validate(string, *Config) error {
-	// nothing to validate
+func (r *EnterpriseRule) Validate(policy string, conf *Config) error {
+	if r.Condition == "" {
+		return nil
+	}
+	if policy != PolicyWrite {
+		return fmt.Errorf("a condition may only be set on rules with a policy of %q", PolicyWrite)
+	}
+	if conf != nil && conf.SentinelEvaluator != nil {
+		return conf.SentinelEvaluator.Compile(r.Condition)
+	}
 	return nil
 }
 
@@ -37,5 +51,45 @@ func decodeRules(rules string, _ *Config, _ *EnterprisePolicyMeta) (*Policy, err
 		return nil, fmt.Errorf("Failed to parse ACL rules: %v", err)
 	}
 
+	if err := validateConditions(&p.PolicyRules); err != nil {
+		return nil, err
+	}
+
 	return p, nil
 }
+
+// validateConditions ensures that only the rules for which conditions are
+// enforced have one.
+func validateConditions(pr *PolicyRules) error {
+	for _, id := range pr.Identities {
+		if id.Condition != "" {
+			return fmt.Errorf("Invalid identity policy: %#v, conditions are only supported on key rules", id)
+		}
+	}
+	for _, id := range pr.IdentityPrefixes {
+		if id.Condition != "" {
+			return fmt.Errorf("Invalid identity_prefix policy: %#v, conditions are only supported on key rules", id)
+		}
+	}
+	for _, np := range pr.Nodes {
+		if np.Condition != "" {
+			return fmt.Errorf("Invalid node policy: %#v, conditions are only supported on key rules", np)
+		}
+	}
+	for _, np := range pr.NodePrefixes {
+		if np.Condition != "" {
+			return fmt.Errorf("Invalid node_prefix policy: %#v, conditions are only supported on key rules", np)
+		}
+	}
+	for _, sp := range pr.Services {
+		if sp.Condition != "" {
+			return fmt.Errorf("Invalid service policy: %#v, conditions are only supported on key rules", sp)
+		}
+	}
+	for _, sp := range pr.ServicePrefixes {
+		if sp.Condition != "" {
+			return fmt.Errorf("Invalid service_prefix policy: %#v, conditions are only supported on key rules", sp)
+		}
+	}
+	return nil
+}
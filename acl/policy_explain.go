@@ -0,0 +1,240 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package acl
+
+// ExplainedRule identifies the policy rule that produced an enforcement
+// decision.
+type ExplainedRule struct {
+	// PolicyIndex is the index of the policy containing the rule within the
+	// policies passed to ExplainPolicies.
+	PolicyIndex int
+
+	// Resource is the resource type of the rule's block. It may differ from
+	// the requested resource when the decision fell back to another resource,
+	// for example mesh requests falling back to the operator rule.
+	Resource Resource
+
+	// Segment is the name or prefix the rule applies to. It is empty for
+	// resources that are not segmented, such as operator.
+	Segment string
+
+	// Prefix is true if the rule was declared as a prefix rule, such as
+	// service_prefix.
+	Prefix bool
+
+	// Glob is true if the rule was declared as a key_glob rule, in which
+	// case Segment is the pattern.
+	Glob bool
+
+	// Policy is the access level granted by the rule.
+	Policy string
+
+	// Intentions is the intentions access level granted by service and
+	// identity rules.
+	Intentions string
+}
+
+// explainCandidate is a single rule and a policy holding only that rule, used
+// to check whether the rule applies to a request on its own.
+type explainCandidate struct {
+	ExplainedRule
+	rules PolicyRules
+}
+
+// ExplainPolicies returns the decision the policies make for the given
+// request, along with the rule that produced it. The decision is made by an
+// authorizer for the merged policies, exactly as they would be enforced. The
+// rule is nil when no rule applies and the decision is Default.
+//
+// When several policies contain an equivalent rule the first is reported.
+func ExplainPolicies(policies []*Policy, entConf *Config, rsc Resource, segment string, access string, ctx *AuthorizerContext) (EnforcementDecision, *ExplainedRule, error) {
+	// Split out the rules before merging, as merging may update rules of the
+	// earlier policies in place.
+	var candidates []explainCandidate
+	for idx, policy := range policies {
+		if policy != nil {
+			candidates = append(candidates, explainCandidates(idx, &policy.PolicyRules)...)
+		}
+	}
+
+	authz, err := NewPolicyAuthorizer(policies, entConf)
+	if err != nil {
+		return Deny, nil, err
+	}
+	decision, err := Enforce(authz, rsc, segment, access, ctx)
+	if err != nil || decision == Default {
+		return decision, nil, err
+	}
+
+	// Every rule that reaches the same decision on its own is a candidate. The
+	// merged authorizer consults the most specific of them, so that is the one
+	// that produced the decision.
+	var best *ExplainedRule
+	for _, candidate := range candidates {
+		candidateAuthz, err := NewPolicyAuthorizer([]*Policy{{PolicyRules: candidate.rules}}, entConf)
+		if err != nil {
+			return Deny, nil, err
+		}
+		candidateDecision, err := Enforce(candidateAuthz, rsc, segment, access, ctx)
+		if err != nil {
+			return Deny, nil, err
+		}
+		if candidateDecision != decision {
+			continue
+		}
+
+		rule := candidate.ExplainedRule
+		if best == nil || rule.moreSpecificThan(best, rsc) {
+			best = &rule
+		}
+	}
+
+	return decision, best, nil
+}
+
+// moreSpecificThan reports whether r takes precedence over other when both
+// apply to a request for the given resource. Rules for the requested resource
+// take precedence over those it falls back to, exact rules over prefix and
+// glob rules, globs denying access over prefix rules and other globs, and
+// otherwise more specific prefixes and globs over less specific ones.
+func (r *ExplainedRule) moreSpecificThan(other *ExplainedRule, rsc Resource) bool {
+	if r.matchesResource(rsc) != other.matchesResource(rsc) {
+		return r.matchesResource(rsc)
+	}
+	if r.exact() != other.exact() {
+		return r.exact()
+	}
+	if r.deniesByGlob() != other.deniesByGlob() {
+		return r.deniesByGlob()
+	}
+	return r.specificity() > other.specificity()
+}
+
+func (r *ExplainedRule) exact() bool {
+	return !r.Prefix && !r.Glob
+}
+
+func (r *ExplainedRule) deniesByGlob() bool {
+	return r.Glob && r.Policy == PolicyDeny
+}
+
+// specificity mirrors how the authorizer weighs key prefixes against globs.
+func (r *ExplainedRule) specificity() int {
+	if r.Glob {
+		return compileKeyGlob(r.Segment).literals
+	}
+	return len(r.Segment)
+}
+
+func (r *ExplainedRule) matchesResource(rsc Resource) bool {
+	if rsc == ResourceIntention {
+		// intentions are granted by service rules
+		return r.Resource == ResourceService
+	}
+	return r.Resource == rsc
+}
+
+// explainCandidates splits the rules into one candidate per rule.
+func explainCandidates(idx int, p *PolicyRules) []explainCandidate {
+	var out []explainCandidate
+	add := func(rsc Resource, segment string, prefix bool, policy, intentions string, rules PolicyRules) {
+		out = append(out, explainCandidate{
+			ExplainedRule: ExplainedRule{
+				PolicyIndex: idx,
+				Resource:    rsc,
+				Segment:     segment,
+				Prefix:      prefix,
+				Policy:      policy,
+				Intentions:  intentions,
+			},
+			rules: rules,
+		})
+	}
+
+	if p.ACL != "" {
+		add(ResourceACL, "", false, p.ACL, "", PolicyRules{ACL: p.ACL})
+	}
+	for _, r := range p.Agents {
+		rule := *r
+		add(ResourceAgent, r.Node, false, r.Policy, "", PolicyRules{Agents: []*AgentRule{&rule}})
+	}
+	for _, r := range p.AgentPrefixes {
+		rule := *r
+		add(ResourceAgent, r.Node, true, r.Policy, "", PolicyRules{AgentPrefixes: []*AgentRule{&rule}})
+	}
+	for _, r := range p.Identities {
+		rule := *r
+		add(ResourceIdentity, r.Name, false, r.Policy, r.Intentions, PolicyRules{Identities: []*IdentityRule{&rule}})
+	}
+	for _, r := range p.IdentityPrefixes {
+		rule := *r
+		add(ResourceIdentity, r.Name, true, r.Policy, r.Intentions, PolicyRules{IdentityPrefixes: []*IdentityRule{&rule}})
+	}
+	for _, r := range p.Keys {
+		rule := *r
+		add(ResourceKey, r.Prefix, false, r.Policy, "", PolicyRules{Keys: []*KeyRule{&rule}})
+	}
+	for _, r := range p.KeyPrefixes {
+		rule := *r
+		add(ResourceKey, r.Prefix, true, r.Policy, "", PolicyRules{KeyPrefixes: []*KeyRule{&rule}})
+	}
+	for _, r := range p.KeyGlobs {
+		rule := *r
+		add(ResourceKey, r.Prefix, false, r.Policy, "", PolicyRules{KeyGlobs: []*KeyRule{&rule}})
+		out[len(out)-1].Glob = true
+	}
+	for _, r := range p.Nodes {
+		rule := *r
+		add(ResourceNode, r.Name, false, r.Policy, "", PolicyRules{Nodes: []*NodeRule{&rule}})
+	}
+	for _, r := range p.NodePrefixes {
+		rule := *r
+		add(ResourceNode, r.Name, true, r.Policy, "", PolicyRules{NodePrefixes: []*NodeRule{&rule}})
+	}
+	for _, r := range p.Services {
+		rule := *r
+		add(ResourceService, r.Name, false, r.Policy, r.Intentions, PolicyRules{Services: []*ServiceRule{&rule}})
+	}
+	for _, r := range p.ServicePrefixes {
+		rule := *r
+		add(ResourceService, r.Name, true, r.Policy, r.Intentions, PolicyRules{ServicePrefixes: []*ServiceRule{&rule}})
+	}
+	for _, r := range p.Sessions {
+		rule := *r
+		add(ResourceSession, r.Node, false, r.Policy, "", PolicyRules{Sessions: []*SessionRule{&rule}})
+	}
+	for _, r := range p.SessionPrefixes {
+		rule := *r
+		add(ResourceSession, r.Node, true, r.Policy, "", PolicyRules{SessionPrefixes: []*SessionRule{&rule}})
+	}
+	for _, r := range p.Events {
+		rule := *r
+		add(ResourceEvent, r.Event, false, r.Policy, "", PolicyRules{Events: []*EventRule{&rule}})
+	}
+	for _, r := range p.EventPrefixes {
+		rule := *r
+		add(ResourceEvent, r.Event, true, r.Policy, "", PolicyRules{EventPrefixes: []*EventRule{&rule}})
+	}
+	for _, r := range p.PreparedQueries {
+		rule := *r
+		add(ResourceQuery, r.Prefix, false, r.Policy, "", PolicyRules{PreparedQueries: []*PreparedQueryRule{&rule}})
+	}
+	for _, r := range p.PreparedQueryPrefixes {
+		rule := *r
+		add(ResourceQuery, r.Prefix, true, r.Policy, "", PolicyRules{PreparedQueryPrefixes: []*PreparedQueryRule{&rule}})
+	}
+	if p.Keyring != "" {
+		add(ResourceKeyring, "", false, p.Keyring, "", PolicyRules{Keyring: p.Keyring})
+	}
+	if p.Operator != "" {
+		add(ResourceOperator, "", false, p.Operator, "", PolicyRules{Operator: p.Operator})
+	}
+	if p.Mesh != "" {
+		add(ResourceMesh, "", false, p.Mesh, "", PolicyRules{Mesh: p.Mesh})
+	}
+	if p.Peering != "" {
+		add(ResourcePeering, "", false, p.Peering, "", PolicyRules{Peering: p.Peering})
+	}
+	return out
+}
@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainPolicies(t *testing.T) {
+	type testCase struct {
+		policies []string
+		resource Resource
+		segment  string
+		access   string
+
+		expectDecision EnforcementDecision
+		expectRule     *ExplainedRule
+	}
+
+	cases := map[string]testCase{
+		"no rules apply": {
+			policies:       []string{`service "db" { policy = "write" }`},
+			resource:       ResourceService,
+			segment:        "web",
+			access:         "read",
+			expectDecision: Default,
+		},
+		"exact rule": {
+			policies:       []string{`service "web" { policy = "read" }`},
+			resource:       ResourceService,
+			segment:        "web",
+			access:         "read",
+			expectDecision: Allow,
+			expectRule:     &ExplainedRule{Resource: ResourceService, Segment: "web", Policy: "read"},
+		},
+		"exact rule takes precedence over prefix in another policy": {
+			policies: []string{
+				`service_prefix "" { policy = "write" }`,
+				`service "web" { policy = "read" }`,
+			},
+			resource:       ResourceService,
+			segment:        "web",
+			access:         "write",
+			expectDecision: Deny,
+			expectRule:     &ExplainedRule{PolicyIndex: 1, Resource: ResourceService, Segment: "web", Policy: "read"},
+		},
+		"longest prefix": {
+			policies: []string{
+				`key_prefix "" { policy = "read" }`,
+				`key_prefix "app/" { policy = "write" }`,
+			},
+			resource:       ResourceKey,
+			segment:        "app/config",
+			access:         "write",
+			expectDecision: Allow,
+			expectRule:     &ExplainedRule{PolicyIndex: 1, Resource: ResourceKey, Segment: "app/", Prefix: true, Policy: "write"},
+		},
+		"glob more specific than prefix": {
+			policies: []string{
+				`key_prefix "app/" { policy = "read" }`,
+				`key_glob "app/*/config" { policy = "write" }`,
+			},
+			resource:       ResourceKey,
+			segment:        "app/web/config",
+			access:         "write",
+			expectDecision: Allow,
+			expectRule:     &ExplainedRule{PolicyIndex: 1, Resource: ResourceKey, Segment: "app/*/config", Glob: true, Policy: "write"},
+		},
+		"glob deny takes precedence over longer prefix": {
+			policies: []string{
+				`key_glob "app/*/secrets/**" { policy = "deny" }`,
+				`key_prefix "app/web/secrets/" { policy = "read" }`,
+			},
+			resource:       ResourceKey,
+			segment:        "app/web/secrets/db",
+			access:         "read",
+			expectDecision: Deny,
+			expectRule:     &ExplainedRule{Resource: ResourceKey, Segment: "app/*/secrets/**", Glob: true, Policy: "deny"},
+		},
+		"deny in a later policy wins the merge": {
+			policies: []string{
+				`node "web-1" { policy = "write" }`,
+				`node "web-1" { policy = "deny" }`,
+			},
+			resource:       ResourceNode,
+			segment:        "web-1",
+			access:         "read",
+			expectDecision: Deny,
+			expectRule:     &ExplainedRule{PolicyIndex: 1, Resource: ResourceNode, Segment: "web-1", Policy: "deny"},
+		},
+		"mesh falls back to operator": {
+			policies:       []string{`operator = "write"`},
+			resource:       ResourceMesh,
+			access:         "write",
+			expectDecision: Allow,
+			expectRule:     &ExplainedRule{Resource: ResourceOperator, Policy: "write"},
+		},
+		"mesh rule takes precedence over operator": {
+			policies:       []string{`operator = "write"`, `mesh = "read"`},
+			resource:       ResourceMesh,
+			access:         "write",
+			expectDecision: Deny,
+			expectRule:     &ExplainedRule{PolicyIndex: 1, Resource: ResourceMesh, Policy: "read"},
+		},
+		"intentions from service rule": {
+			policies: []string{
+				`service_prefix "" { policy = "read" }`,
+				`service "web" { policy = "read" intentions = "write" }`,
+			},
+			resource:       ResourceIntention,
+			segment:        "web",
+			access:         "write",
+			expectDecision: Allow,
+			expectRule:     &ExplainedRule{PolicyIndex: 1, Resource: ResourceService, Segment: "web", Policy: "read", Intentions: "write"},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			var policies []*Policy
+			for _, rules := range tc.policies {
+				p, err := NewPolicyFromSource(rules, nil, nil)
+				require.NoError(t, err)
+				policies = append(policies, p)
+			}
+
+			decision, rule, err := ExplainPolicies(policies, nil, tc.resource, tc.segment, tc.access, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectDecision, decision)
+			require.Equal(t, tc.expectRule, rule)
+		})
+	}
+
+	t.Run("invalid access", func(t *testing.T) {
+		p, err := NewPolicyFromSource(`service "web" { policy = "read" }`, nil, nil)
+		require.NoError(t, err)
+
+		_, _, err = ExplainPolicies([]*Policy{p}, nil, ResourceService, "web", "fly", nil)
+		require.ErrorContains(t, err, "Invalid access level")
+	})
+}
@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package acl
+
+type globTokenKind int
+
+const (
+	globLiteral globTokenKind = iota
+	// globAny is "?" and matches a single character other than "/".
+	globAny
+	// globStar is "*" and matches any sequence of characters other than "/".
+	globStar
+	// globDoubleStar is "**" and matches any sequence of characters.
+	globDoubleStar
+)
+
+type globToken struct {
+	kind globTokenKind
+	char rune
+}
+
+// keyGlob is a compiled key_glob pattern.
+type keyGlob struct {
+	pattern string
+	tokens  []globToken
+
+	// literals is the number of literal characters in the pattern. A glob
+	// with more literal characters is more specific than one with fewer.
+	literals int
+}
+
+func compileKeyGlob(pattern string) *keyGlob {
+	g := &keyGlob{pattern: pattern}
+
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '?':
+			g.tokens = append(g.tokens, globToken{kind: globAny})
+		case '*':
+			kind := globStar
+			for i+1 < len(runes) && runes[i+1] == '*' {
+				kind = globDoubleStar
+				i++
+			}
+			g.tokens = append(g.tokens, globToken{kind: kind})
+		default:
+			g.tokens = append(g.tokens, globToken{kind: globLiteral, char: runes[i]})
+			g.literals++
+		}
+	}
+	return g
+}
+
+// match reports whether the glob matches the whole key.
+func (g *keyGlob) match(key string) bool {
+	states := g.run(key)
+	return states != nil && states[len(g.tokens)]
+}
+
+// matchPrefix reports whether the glob may match a key beginning with the
+// given prefix.
+func (g *keyGlob) matchPrefix(prefix string) bool {
+	return g.run(prefix) != nil
+}
+
+// run feeds the input to the glob and returns the set of positions within the
+// pattern that it could have reached, or nil if there are none.
+func (g *keyGlob) run(input string) []bool {
+	states := make([]bool, len(g.tokens)+1)
+	g.enter(states, 0)
+
+	for _, r := range input {
+		next := make([]bool, len(g.tokens)+1)
+		reached := false
+		for pos, active := range states {
+			if !active || pos == len(g.tokens) {
+				continue
+			}
+
+			tok := g.tokens[pos]
+			switch {
+			case tok.kind == globLiteral && tok.char == r,
+				tok.kind == globAny && r != '/':
+				g.enter(next, pos+1)
+				reached = true
+			case tok.kind == globStar && r != '/',
+				tok.kind == globDoubleStar:
+				g.enter(next, pos)
+				reached = true
+			}
+		}
+		if !reached {
+			return nil
+		}
+		states = next
+	}
+	return states
+}
+
+// enter marks the position as reached, along with the positions following
+// any wildcards that may match an empty sequence.
+func (g *keyGlob) enter(states []bool, pos int) {
+	for {
+		states[pos] = true
+		if pos == len(g.tokens) {
+			return
+		}
+		if kind := g.tokens[pos].kind; kind != globStar && kind != globDoubleStar {
+			return
+		}
+		pos++
+	}
+}
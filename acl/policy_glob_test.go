@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyGlob(t *testing.T) {
+	type testCase struct {
+		pattern  string
+		key      string
+		match    bool
+		prefix   bool
+		literals int
+	}
+
+	cases := []testCase{
+		{pattern: "app/*/config", key: "app/web/config", match: true, prefix: true, literals: 11},
+		{pattern: "app/*/config", key: "app//config", match: true, prefix: true, literals: 11},
+		{pattern: "app/*/config", key: "app/web/v2/config", match: false, prefix: false, literals: 11},
+		{pattern: "app/*/config", key: "app/web/config/port", match: false, prefix: false, literals: 11},
+		{pattern: "app/*/config", key: "app/", match: false, prefix: true, literals: 11},
+		{pattern: "app/*/config", key: "ap", match: false, prefix: true, literals: 11},
+		{pattern: "app/*/config", key: "", match: false, prefix: true, literals: 11},
+		{pattern: "app/*/config", key: "other/", match: false, prefix: false, literals: 11},
+		{pattern: "app/**", key: "app/web/v2/config", match: true, prefix: true, literals: 4},
+		{pattern: "app/**", key: "app/", match: true, prefix: true, literals: 4},
+		{pattern: "app/**/config", key: "app/web/v2/config", match: true, prefix: true, literals: 11},
+		{pattern: "app/**/config", key: "app/config", match: false, prefix: true, literals: 11},
+		{pattern: "app/?/config", key: "app/a/config", match: true, prefix: true, literals: 11},
+		{pattern: "app/?/config", key: "app/ab/config", match: false, prefix: false, literals: 11},
+		{pattern: "app/?/config", key: "app/é/config", match: true, prefix: true, literals: 11},
+		{pattern: "*.json", key: "config.json", match: true, prefix: true, literals: 5},
+		{pattern: "*.json", key: "app/config.json", match: false, prefix: false, literals: 5},
+		{pattern: "***", key: "a/b", match: true, prefix: true, literals: 0},
+		{pattern: "app/web", key: "app/web", match: true, prefix: true, literals: 7},
+		{pattern: "app/web", key: "app/web/", match: false, prefix: false, literals: 7},
+	}
+
+	for _, tc := range cases {
+		glob := compileKeyGlob(tc.pattern)
+		require.Equal(t, tc.match, glob.match(tc.key), "match(%q, %q)", tc.pattern, tc.key)
+		require.Equal(t, tc.prefix, glob.matchPrefix(tc.key), "matchPrefix(%q, %q)", tc.pattern, tc.key)
+		require.Equal(t, tc.literals, glob.literals, "literals(%q)", tc.pattern)
+	}
+}
@@ -14,6 +14,7 @@ type policyRulesMergeContext struct {
 	keyringRule              string
 	keyRules                 map[string]*KeyRule
 	keyPrefixRules           map[string]*KeyRule
+	keyGlobRules             map[string]*KeyRule
 	meshRule                 string
 	peeringRule              string
 	nodeRules                map[string]*NodeRule
@@ -40,6 +41,7 @@ func (p *policyRulesMergeContext) init() {
 	p.keyringRule = ""
 	p.keyRules = make(map[string]*KeyRule)
 	p.keyPrefixRules = make(map[string]*KeyRule)
+	p.keyGlobRules = make(map[string]*KeyRule)
 	p.meshRule = ""
 	p.peeringRule = ""
 	p.nodeRules = make(map[string]*NodeRule)
@@ -164,6 +166,17 @@ func (p *policyRulesMergeContext) merge(policy *PolicyRules) {
 		}
 	}
 
+	for _, kp := range policy.KeyGlobs {
+		update := true
+		if permission, found := p.keyGlobRules[kp.Prefix]; found {
+			update = takesPrecedenceOver(kp.Policy, permission.Policy)
+		}
+
+		if update {
+			p.keyGlobRules[kp.Prefix] = kp
+		}
+	}
+
 	for _, np := range policy.Nodes {
 		update := true
 		if permission, found := p.nodeRules[np.Name]; found {
@@ -329,6 +342,11 @@ func (p *policyRulesMergeContext) fill(merged *PolicyRules) {
 		merged.KeyPrefixes = append(merged.KeyPrefixes, policy)
 	}
 
+	merged.KeyGlobs = []*KeyRule{}
+	for _, policy := range p.keyGlobRules {
+		merged.KeyGlobs = append(merged.KeyGlobs, policy)
+	}
+
 	merged.Nodes = []*NodeRule{}
 	for _, policy := range p.nodeRules {
 		merged.Nodes = append(merged.Nodes, policy)
@@ -439,6 +439,18 @@ func TestPolicySourceParse(t *testing.T) {
 			RulesJSON: `{ "key_prefix": { "foo": { "policy": "nope" }}}`,
 			Err:       "Invalid key_prefix policy",
 		},
+		{
+			Name:      "Bad Policy - Key Glob",
+			Rules:     `key_glob "foo/*" { policy = "nope" }`,
+			RulesJSON: `{ "key_glob": { "foo/*": { "policy": "nope" }}}`,
+			Err:       "Invalid key_glob policy",
+		},
+		{
+			Name:      "Bad Policy - Key Glob Empty",
+			Rules:     `key_glob "" { policy = "read" }`,
+			RulesJSON: `{ "key_glob": { "": { "policy": "read" }}}`,
+			Err:       "Invalid key_glob policy",
+		},
 		{
 			Name:      "Bad Policy - Node",
 			Rules:     `node "foo" { policy = "nope" }`,
@@ -541,6 +553,17 @@ func TestPolicySourceParse(t *testing.T) {
 			RulesJSON: `{ "mesh": "" }`,
 			Expected:  &Policy{PolicyRules: PolicyRules{Mesh: ""}},
 		},
+		{
+			Name:      "Key Globs",
+			Rules:     `key_glob "app/*/config/**" { policy = "write" } key_glob "app/*/secrets/**" { policy = "deny" }`,
+			RulesJSON: `{ "key_glob": { "app/*/config/**": { "policy": "write" }, "app/*/secrets/**": { "policy": "deny" }}}`,
+			Expected: &Policy{PolicyRules: PolicyRules{
+				KeyGlobs: []*KeyRule{
+					{Prefix: "app/*/config/**", Policy: PolicyWrite},
+					{Prefix: "app/*/secrets/**", Policy: PolicyDeny},
+				},
+			}},
+		},
 		{
 			Name:      "Peering Empty",
 			Rules:     `peering = ""`,
@@ -988,6 +1011,12 @@ func TestMergePolicies(t *testing.T) {
 							Policy: PolicyList,
 						},
 					},
+					KeyGlobs: []*KeyRule{
+						{
+							Prefix: "app/*/config",
+							Policy: PolicyWrite,
+						},
+					},
 				}},
 				{PolicyRules: PolicyRules{
 					Keys: []*KeyRule{
@@ -1018,6 +1047,16 @@ func TestMergePolicies(t *testing.T) {
 							Policy: PolicyRead,
 						},
 					},
+					KeyGlobs: []*KeyRule{
+						{
+							Prefix: "app/*/config",
+							Policy: PolicyRead,
+						},
+						{
+							Prefix: "app/*/secrets",
+							Policy: PolicyDeny,
+						},
+					},
 				}},
 			},
 			expected: &Policy{PolicyRules: PolicyRules{
@@ -1057,6 +1096,16 @@ func TestMergePolicies(t *testing.T) {
 						Policy: PolicyList,
 					},
 				},
+				KeyGlobs: []*KeyRule{
+					{
+						Prefix: "app/*/config",
+						Policy: PolicyWrite,
+					},
+					{
+						Prefix: "app/*/secrets",
+						Policy: PolicyDeny,
+					},
+				},
 			}},
 		},
 		{
@@ -1441,6 +1490,7 @@ func TestMergePolicies(t *testing.T) {
 			require.ElementsMatch(t, exp.EventPrefixes, act.EventPrefixes)
 			require.ElementsMatch(t, exp.Keys, act.Keys)
 			require.ElementsMatch(t, exp.KeyPrefixes, act.KeyPrefixes)
+			require.ElementsMatch(t, exp.KeyGlobs, act.KeyGlobs)
 			require.ElementsMatch(t, exp.Nodes, act.Nodes)
 			require.ElementsMatch(t, exp.NodePrefixes, act.NodePrefixes)
 			require.ElementsMatch(t, exp.PreparedQueries, act.PreparedQueries)
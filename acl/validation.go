@@ -62,6 +62,24 @@ func ValidatePolicyName(name string) error {
 	return nil
 }
 
+// ValidateTemplatedPolicyName returns nil if the provided name can be used as
+// the Name of a user-defined templated policy otherwise a useful error is
+// returned.
+func ValidateTemplatedPolicyName(name string) error {
+	if len(name) < 1 || len(name) > PolicyNameMaxLength {
+		return fmt.Errorf("Invalid Templated Policy: invalid Name. Length must be greater than 0 and less than %d", PolicyNameMaxLength)
+	}
+
+	if strings.HasPrefix(name, "/") || strings.HasPrefix(name, ReservedBuiltinPrefix) {
+		return fmt.Errorf("Invalid Templated Policy: invalid Name. Names cannot be prefixed with '/' or '%s'", ReservedBuiltinPrefix)
+	}
+
+	if !validPolicyName.MatchString(name) {
+		return fmt.Errorf("Invalid Templated Policy: invalid Name. Only alphanumeric characters, a single '/', '-' and '_' are allowed")
+	}
+	return nil
+}
+
 // IsValidRoleName returns true if the provided name can be used as an
 // ACLRole Name.
 func IsValidRoleName(name string) bool {
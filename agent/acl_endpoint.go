@@ -4,10 +4,12 @@
 package agent
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/structs"
@@ -295,6 +297,7 @@ func (s *HTTPHandlers) ACLTokenList(resp http.ResponseWriter, req *http.Request)
 	args.Role = req.URL.Query().Get("role")
 	args.AuthMethod = req.URL.Query().Get("authmethod")
 	args.ServiceName = req.URL.Query().Get("servicename")
+	_, args.BreakGlass = req.URL.Query()["breakglass"]
 	if err := parseACLAuthMethodEnterpriseMeta(req, &args.ACLAuthMethodEnterpriseMeta); err != nil {
 		return nil, err
 	}
@@ -334,6 +337,14 @@ func (s *HTTPHandlers) ACLTokenCRUD(resp http.ResponseWriter, req *http.Request)
 		tokenAccessorID = tokenAccessorID[:len(tokenAccessorID)-6]
 		fn = s.ACLTokenClone
 	}
+	if strings.HasSuffix(tokenAccessorID, "/rotate") && req.Method == "PUT" {
+		tokenAccessorID = tokenAccessorID[:len(tokenAccessorID)-7]
+		fn = s.ACLTokenRotate
+	}
+	if strings.HasSuffix(tokenAccessorID, "/lineage") && req.Method == "GET" {
+		tokenAccessorID = tokenAccessorID[:len(tokenAccessorID)-8]
+		fn = s.ACLTokenLineage
+	}
 	if tokenAccessorID == "" && req.Method != "PUT" {
 		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Missing token AccessorID"}
 	}
@@ -431,6 +442,49 @@ func (s *HTTPHandlers) ACLTokenGet(resp http.ResponseWriter, req *http.Request,
 	return out.Token, nil
 }
 
+func (s *HTTPHandlers) ACLTokenLineage(resp http.ResponseWriter, req *http.Request, tokenAccessorID string) (interface{}, error) {
+	if s.checkACLDisabled() {
+		return nil, aclDisabled
+	}
+
+	args := structs.ACLTokenGetRequest{
+		Datacenter:  s.agent.config.Datacenter,
+		TokenID:     tokenAccessorID,
+		TokenIDType: structs.ACLTokenAccessor,
+	}
+
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	if err := s.parseEntMeta(req, &args.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	if args.Datacenter == "" {
+		args.Datacenter = s.agent.config.Datacenter
+	}
+
+	var out structs.ACLTokenLineageResponse
+	defer setMeta(resp, &out.QueryMeta)
+	if err := s.agent.RPC(req.Context(), "ACL.TokenLineage", &args, &out); err != nil {
+		if errors.Is(err, acl.ErrNotFound) || strings.Contains(err.Error(), acl.ErrNotFound.Error()) {
+			return nil, HTTPError{StatusCode: http.StatusNotFound, Reason: err.Error()}
+		}
+		return nil, err
+	}
+
+	return &structs.ACLTokenLineageInfo{
+		ACLTokenExpanded: &structs.ACLTokenExpanded{
+			ACLToken:          out.Token,
+			ExpandedTokenInfo: out.ExpandedTokenInfo,
+		},
+		IssuedBy:              out.IssuedBy,
+		BindingRules:          out.BindingRules,
+		MissingBindingRuleIDs: out.MissingBindingRuleIDs,
+	}, nil
+}
+
 func (s *HTTPHandlers) ACLTokenSet(_ http.ResponseWriter, req *http.Request, tokenAccessorID string) (interface{}, error) {
 	return s.aclTokenSetInternal(req, tokenAccessorID, false)
 }
@@ -519,6 +573,89 @@ func (s *HTTPHandlers) ACLTokenClone(resp http.ResponseWriter, req *http.Request
 	return &out, nil
 }
 
+func (s *HTTPHandlers) ACLTokenRotate(resp http.ResponseWriter, req *http.Request, tokenAccessorID string) (interface{}, error) {
+	if s.checkACLDisabled() {
+		return nil, aclDisabled
+	}
+
+	args := structs.ACLTokenRotateRequest{
+		Datacenter: s.agent.config.Datacenter,
+		AccessorID: tokenAccessorID,
+	}
+	s.parseToken(req, &args.Token)
+	if err := s.parseEntMeta(req, &args.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	// The body is optional, without one the current SecretID is invalidated
+	// immediately.
+	if req.ContentLength > 0 {
+		var body struct {
+			GracePeriod string
+		}
+		if err := lib.DecodeJSON(req.Body, &body); err != nil {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request decoding failed: %v", err)}
+		}
+		if body.GracePeriod != "" {
+			gracePeriod, err := time.ParseDuration(body.GracePeriod)
+			if err != nil {
+				return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Invalid GracePeriod: %v", err)}
+			}
+			args.GracePeriod = gracePeriod
+		}
+	}
+
+	var out structs.ACLToken
+	if err := s.agent.RPC(req.Context(), "ACL.TokenRotate", &args, &out); err != nil {
+		if errors.Is(err, acl.ErrNotFound) || strings.Contains(err.Error(), acl.ErrNotFound.Error()) {
+			return nil, HTTPError{StatusCode: http.StatusNotFound, Reason: err.Error()}
+		}
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+func (s *HTTPHandlers) ACLTokenBreakGlass(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.checkACLDisabled() {
+		return nil, aclDisabled
+	}
+
+	args := structs.ACLTokenBreakGlassRequest{
+		Datacenter: s.agent.config.Datacenter,
+	}
+	s.parseDC(req, &args.Datacenter)
+	s.parseToken(req, &args.Token)
+	if err := s.parseEntMeta(req, &args.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		Reason        string
+		TTL           string
+		ApproverToken string
+	}
+	if err := lib.DecodeJSON(req.Body, &body); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request decoding failed: %v", err)}
+	}
+	args.Reason = body.Reason
+	args.ApproverToken = body.ApproverToken
+	if body.TTL != "" {
+		ttl, err := time.ParseDuration(body.TTL)
+		if err != nil {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Invalid TTL: %v", err)}
+		}
+		args.TTL = ttl
+	}
+
+	var out structs.ACLToken
+	if err := s.agent.RPC(req.Context(), "ACL.TokenBreakGlass", &args, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
 func (s *HTTPHandlers) ACLRoleList(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	if s.checkACLDisabled() {
 		return nil, aclDisabled
@@ -1021,6 +1158,62 @@ func (s *HTTPHandlers) ACLLogin(resp http.ResponseWriter, req *http.Request) (in
 	return &out, nil
 }
 
+type aclOIDCAuthURLResponse struct {
+	AuthURL string
+}
+
+func (s *HTTPHandlers) ACLOIDCAuthURL(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.checkACLDisabled() {
+		return nil, aclDisabled
+	}
+
+	args := &structs.ACLOIDCAuthURLRequest{
+		Datacenter: s.agent.config.Datacenter,
+		Auth:       &structs.ACLOIDCAuthURLParams{},
+	}
+	s.parseDC(req, &args.Datacenter)
+	if err := s.parseEntMeta(req, &args.Auth.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	if err := s.rewordUnknownEnterpriseFieldError(lib.DecodeJSON(req.Body, &args.Auth)); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Failed to decode request body: %v", err)}
+	}
+
+	var out string
+	if err := s.agent.RPC(req.Context(), "ACL.OIDCAuthURL", args, &out); err != nil {
+		return nil, err
+	}
+
+	return &aclOIDCAuthURLResponse{AuthURL: out}, nil
+}
+
+func (s *HTTPHandlers) ACLOIDCCallback(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.checkACLDisabled() {
+		return nil, aclDisabled
+	}
+
+	args := &structs.ACLOIDCCallbackRequest{
+		Datacenter: s.agent.config.Datacenter,
+		Auth:       &structs.ACLOIDCCallbackParams{},
+	}
+	s.parseDC(req, &args.Datacenter)
+	if err := s.parseEntMeta(req, &args.Auth.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	if err := s.rewordUnknownEnterpriseFieldError(lib.DecodeJSON(req.Body, &args.Auth)); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Failed to decode request body: %v", err)}
+	}
+
+	var out structs.ACLToken
+	if err := s.agent.RPC(req.Context(), "ACL.OIDCCallback", args, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
 func (s *HTTPHandlers) ACLLogout(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	if s.checkACLDisabled() {
 		return nil, aclDisabled
@@ -1134,6 +1327,59 @@ func (s *HTTPHandlers) ACLAuthorize(resp http.ResponseWriter, req *http.Request)
 	return responses, nil
 }
 
+// ACLAuthorizeExplain is like ACLAuthorize, but also returns the policy rule
+// or default that produced each decision. The same reasoning about leaking
+// security relevant information applies, as the token's own policies can
+// already be read with it.
+func (s *HTTPHandlers) ACLAuthorizeExplain(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	const maxRequests = 64
+
+	if s.checkACLDisabled() {
+		return nil, aclDisabled
+	}
+
+	request := structs.RemoteACLAuthorizationRequest{
+		Datacenter: s.agent.config.Datacenter,
+		QueryOptions: structs.QueryOptions{
+			AllowStale:        true,
+			RequireConsistent: false,
+		},
+	}
+
+	s.parseToken(req, &request.Token)
+	s.parseDC(req, &request.Datacenter)
+
+	if err := decodeBody(req.Body, &request.Requests); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Failed to decode request body: %v", err)}
+	}
+
+	if len(request.Requests) > maxRequests {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Refusing to process more than %d authorizations at once", maxRequests)}
+	}
+
+	if len(request.Requests) == 0 {
+		return make([]structs.ACLAuthorizationExplanation, 0), nil
+	}
+
+	// Reject unknown resources and access levels before asking the servers.
+	if _, err := structs.CreateACLAuthorizationResponses(acl.DenyAll(), request.Requests); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: err.Error()}
+	}
+
+	// Explaining a decision needs the policies themselves rather than the
+	// compiled authorizer, so this is always answered by the servers.
+	var explanations []structs.ACLAuthorizationExplanation
+	if err := s.agent.RPC(req.Context(), "ACL.AuthorizeExplain", &request, &explanations); err != nil {
+		return nil, err
+	}
+
+	if explanations == nil {
+		explanations = make([]structs.ACLAuthorizationExplanation, 0)
+	}
+
+	return explanations, nil
+}
+
 func (s *HTTPHandlers) ACLTemplatedPoliciesList(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	if s.checkACLDisabled() {
 		return nil, aclDisabled
@@ -1169,9 +1415,78 @@ func (s *HTTPHandlers) ACLTemplatedPoliciesList(resp http.ResponseWriter, req *h
 		}
 	}
 
+	// User-defined templated policies are stored by the servers.
+	args := structs.ACLTemplatedPolicyListRequest{
+		Datacenter: s.agent.config.Datacenter,
+	}
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var out structs.ACLTemplatedPolicyListResponse
+	defer setMeta(resp, &out.QueryMeta)
+	if err := s.agent.RPC(req.Context(), "ACL.TemplatedPolicyList", &args, &out); err != nil {
+		return nil, err
+	}
+
+	for _, tp := range out.TemplatedPolicies {
+		templatedPolicies[tp.Name] = api.ACLTemplatedPolicyResponse{
+			TemplateName: tp.Name,
+			Description:  tp.Description,
+			Schema:       tp.Schema,
+			Template:     tp.Template,
+		}
+	}
+
 	return templatedPolicies, nil
 }
 
+// aclTemplatedPolicyBase returns the builtin or user-defined templated policy
+// with the given name. User-defined templated policies are fetched from the
+// servers.
+func (s *HTTPHandlers) aclTemplatedPolicyBase(req *http.Request, templateName string) (*structs.ACLTemplatedPolicyBase, error) {
+	if baseTemplate, ok := structs.GetACLTemplatedPolicyBase(templateName); ok {
+		return baseTemplate, nil
+	}
+
+	args := structs.ACLTemplatedPolicyGetRequest{
+		Datacenter: s.agent.config.Datacenter,
+		Name:       templateName,
+	}
+	s.parseToken(req, &args.Token)
+
+	var out structs.ACLTemplatedPolicyResponse
+	if err := s.agent.RPC(req.Context(), "ACL.TemplatedPolicyRead", &args, &out); err != nil {
+		return nil, err
+	}
+	if out.TemplatedPolicy == nil {
+		return nil, nil
+	}
+	return out.TemplatedPolicy.Base(), nil
+}
+
+// ACLTemplatedPolicyCRUD handles reads, updates and deletions of templated
+// policies by name. Only user-defined templated policies can be modified.
+func (s *HTTPHandlers) ACLTemplatedPolicyCRUD(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.checkACLDisabled() {
+		return nil, aclDisabled
+	}
+
+	switch req.Method {
+	case "GET":
+		return s.ACLTemplatedPolicyRead(resp, req)
+
+	case "PUT":
+		return s.ACLTemplatedPolicyWrite(resp, req)
+
+	case "DELETE":
+		return s.ACLTemplatedPolicyDelete(resp, req)
+
+	default:
+		return nil, MethodNotAllowedError{req.Method, []string{"GET", "PUT", "DELETE"}}
+	}
+}
+
 func (s *HTTPHandlers) ACLTemplatedPolicyRead(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	if s.checkACLDisabled() {
 		return nil, aclDisabled
@@ -1202,18 +1517,120 @@ func (s *HTTPHandlers) ACLTemplatedPolicyRead(resp http.ResponseWriter, req *htt
 		return nil, err
 	}
 
-	baseTemplate, ok := structs.GetACLTemplatedPolicyBase(templateName)
-	if !ok {
+	baseTemplate, err := s.aclTemplatedPolicyBase(req, templateName)
+	if err != nil {
+		return nil, err
+	}
+	if baseTemplate == nil {
 		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Invalid templated policy Name: %s", templateName)}
 	}
 
 	return api.ACLTemplatedPolicyResponse{
 		TemplateName: baseTemplate.TemplateName,
+		Description:  baseTemplate.Description,
 		Schema:       baseTemplate.Schema,
 		Template:     baseTemplate.Template,
 	}, nil
 }
 
+func (s *HTTPHandlers) ACLTemplatedPolicyCreate(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.checkACLDisabled() {
+		return nil, aclDisabled
+	}
+
+	args := structs.ACLTemplatedPolicySetRequest{
+		Datacenter: s.agent.config.Datacenter,
+	}
+	s.parseToken(req, &args.Token)
+
+	if err := lib.DecodeJSON(req.Body, &args.TemplatedPolicy); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Templated policy decoding failed: %v", err)}
+	}
+
+	if args.TemplatedPolicy.ID != "" {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Cannot specify the ID when creating a new templated policy"}
+	}
+
+	var out structs.ACLTemplatedPolicyDefinition
+	if err := s.agent.RPC(req.Context(), "ACL.TemplatedPolicySet", args, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+func (s *HTTPHandlers) ACLTemplatedPolicyWrite(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	templateName := strings.TrimPrefix(req.URL.Path, "/v1/acl/templated-policy/name/")
+	if templateName == "" {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Missing templated policy Name"}
+	}
+
+	args := structs.ACLTemplatedPolicySetRequest{
+		Datacenter: s.agent.config.Datacenter,
+	}
+	s.parseToken(req, &args.Token)
+
+	if err := lib.DecodeJSON(req.Body, &args.TemplatedPolicy); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Templated policy decoding failed: %v", err)}
+	}
+
+	if args.TemplatedPolicy.Name != "" && args.TemplatedPolicy.Name != templateName {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Templated policy Name in URL and payload do not match"}
+	}
+	args.TemplatedPolicy.Name = templateName
+
+	// Updates are addressed by name so look up the ID of the existing
+	// templated policy.
+	readArgs := structs.ACLTemplatedPolicyGetRequest{
+		Datacenter: args.Datacenter,
+		Name:       templateName,
+	}
+	readArgs.Token = args.Token
+
+	var existing structs.ACLTemplatedPolicyResponse
+	if err := s.agent.RPC(req.Context(), "ACL.TemplatedPolicyRead", &readArgs, &existing); err != nil {
+		return nil, err
+	}
+	if existing.TemplatedPolicy == nil {
+		return nil, HTTPError{StatusCode: http.StatusNotFound, Reason: fmt.Sprintf("Cannot find templated policy %q to update", templateName)}
+	}
+
+	if args.TemplatedPolicy.ID != "" && args.TemplatedPolicy.ID != existing.TemplatedPolicy.ID {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Templated policy ID in payload does not match the existing templated policy"}
+	}
+	args.TemplatedPolicy.ID = existing.TemplatedPolicy.ID
+
+	var out structs.ACLTemplatedPolicyDefinition
+	if err := s.agent.RPC(req.Context(), "ACL.TemplatedPolicySet", args, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+func (s *HTTPHandlers) ACLTemplatedPolicyDelete(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	templateName := strings.TrimPrefix(req.URL.Path, "/v1/acl/templated-policy/name/")
+	if templateName == "" {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Missing templated policy Name"}
+	}
+
+	args := structs.ACLTemplatedPolicyDeleteRequest{
+		Datacenter: s.agent.config.Datacenter,
+		Name:       templateName,
+	}
+	s.parseToken(req, &args.Token)
+
+	var ignored string
+	if err := s.agent.RPC(req.Context(), "ACL.TemplatedPolicyDelete", args, &ignored); err != nil {
+		if strings.Contains(err.Error(), acl.ErrNotFound.Error()) {
+			return nil, HTTPError{StatusCode: http.StatusNotFound, Reason: "Cannot find templated policy to delete"}
+		}
+		return nil, err
+	}
+
+	return true, nil
+}
+
 func (s *HTTPHandlers) ACLTemplatedPolicyPreview(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	if s.checkACLDisabled() {
 		return nil, aclDisabled
@@ -1244,8 +1661,11 @@ func (s *HTTPHandlers) ACLTemplatedPolicyPreview(resp http.ResponseWriter, req *
 		return nil, err
 	}
 
-	baseTemplate, ok := structs.GetACLTemplatedPolicyBase(templateName)
-	if !ok {
+	baseTemplate, err := s.aclTemplatedPolicyBase(req, templateName)
+	if err != nil {
+		return nil, err
+	}
+	if baseTemplate == nil {
 		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("templated policy %q does not exist", templateName)}
 	}
 
@@ -1266,7 +1686,7 @@ func (s *HTTPHandlers) ACLTemplatedPolicyPreview(resp http.ResponseWriter, req *
 		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("validation error for templated policy: %q: %s", templatedPolicy.TemplateName, err.Error())}
 	}
 
-	renderedPolicy, err := templatedPolicy.SyntheticPolicy(&entMeta)
+	renderedPolicy, err := templatedPolicy.SyntheticPolicyFromBase(baseTemplate, &entMeta)
 
 	if err != nil {
 		return nil, HTTPError{StatusCode: http.StatusInternalServerError, Reason: fmt.Sprintf("Failed to generate synthetic policy: %q: %s", templatedPolicy.TemplateName, err.Error())}
@@ -1037,6 +1037,50 @@ func TestACL_HTTP(t *testing.T) {
 			require.Len(t, token.Policies, 1)
 			require.Equal(t, structs.ACLPolicyGlobalManagementID, token.Policies[0].ID)
 		})
+		t.Run("Break-glass", func(t *testing.T) {
+			requester := tokenMap[idMap["token-test"]]
+
+			body := map[string]string{
+				"Reason":        "incident",
+				"TTL":           "10m",
+				"ApproverToken": "root",
+			}
+			req, _ := http.NewRequest("PUT", "/v1/acl/token/break-glass", jsonBody(body))
+			req.Header.Add("X-Consul-Token", requester.SecretID)
+			resp := httptest.NewRecorder()
+			obj, err := a.srv.ACLTokenBreakGlass(resp, req)
+			require.NoError(t, err)
+			token, ok := obj.(*structs.ACLToken)
+			require.True(t, ok)
+			require.True(t, token.Local)
+			require.NotNil(t, token.ExpirationTime)
+			require.Equal(t, "incident", token.BreakGlass.Reason)
+			require.Equal(t, requester.AccessorID, token.BreakGlass.RequestedBy)
+
+			req, _ = http.NewRequest("GET", "/v1/acl/tokens?breakglass", nil)
+			req.Header.Add("X-Consul-Token", "root")
+			resp = httptest.NewRecorder()
+			raw, err := a.srv.ACLTokenList(resp, req)
+			require.NoError(t, err)
+			tokens, ok := raw.(structs.ACLTokenListStubs)
+			require.True(t, ok)
+			require.Len(t, tokens, 1)
+			require.Equal(t, token.AccessorID, tokens[0].AccessorID)
+
+			body["TTL"] = "soon"
+			req, _ = http.NewRequest("PUT", "/v1/acl/token/break-glass", jsonBody(body))
+			req.Header.Add("X-Consul-Token", requester.SecretID)
+			resp = httptest.NewRecorder()
+			_, err = a.srv.ACLTokenBreakGlass(resp, req)
+			require.Error(t, err)
+			require.True(t, isHTTPBadRequest(err))
+
+			req, _ = http.NewRequest("DELETE", "/v1/acl/token/"+token.AccessorID, nil)
+			req.Header.Add("X-Consul-Token", "root")
+			resp = httptest.NewRecorder()
+			_, err = a.srv.ACLTokenCRUD(resp, req)
+			require.NoError(t, err)
+		})
 		t.Run("Create with Accessor", func(t *testing.T) {
 			tokenInput := &structs.ACLToken{
 				AccessorID:  "56e8e6a3-708b-4a2f-8ab3-b973cce39108",
@@ -1990,6 +2034,34 @@ func TestACL_LoginProcedure_HTTP(t *testing.T) {
 			}
 		})
 
+		t.Run("Lineage", func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/v1/acl/token/"+idMap["token-test-1"]+"/lineage", nil)
+			req.Header.Add("X-Consul-Token", "root")
+			resp := httptest.NewRecorder()
+			raw, err := a.srv.ACLTokenCRUD(resp, req)
+			require.NoError(t, err)
+			lineage, ok := raw.(*structs.ACLTokenLineageInfo)
+			require.True(t, ok)
+
+			require.Equal(t, idMap["token-test-1"], lineage.AccessorID)
+			require.Equal(t, "test", lineage.IssuedBy.Name)
+			require.Len(t, lineage.BindingRules, 1)
+			require.Equal(t, idMap["rule-test"], lineage.BindingRules[0].ID)
+			require.Equal(t, []string{idMap["rule-test"]}, lineage.Lineage.BindingRuleIDs)
+			require.Len(t, lineage.ExpandedPolicies, 1)
+		})
+
+		t.Run("Lineage of an unknown token", func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/v1/acl/token/cc61bd64-a1f6-4f16-9b8c-1cfdc7e2d4a8/lineage", nil)
+			req.Header.Add("X-Consul-Token", "root")
+			resp := httptest.NewRecorder()
+			_, err := a.srv.ACLTokenCRUD(resp, req)
+			require.Error(t, err)
+			var httpErr HTTPError
+			require.ErrorAs(t, err, &httpErr)
+			require.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+		})
+
 		t.Run("Logout", func(t *testing.T) {
 			tok := tokenMap[idMap["token-test-1"]]
 			req, _ := http.NewRequest("POST", "/v1/acl/logout", nil)
@@ -846,6 +846,15 @@ func (a *Agent) Start(ctx context.Context) error {
 		a.apiServers.Start(srv)
 	}
 
+	// Start the SPIFFE bundle endpoint.
+	spiffeBundleServers, err := a.listenSPIFFEBundle()
+	if err != nil {
+		return err
+	}
+	for _, srv := range spiffeBundleServers {
+		a.apiServers.Start(srv)
+	}
+
 	// Start grpc and grpc_tls servers.
 	if err := a.listenAndServeGRPC(proxyWatcher); err != nil {
 		return err
@@ -1681,17 +1690,17 @@ func (a *Agent) RPC(ctx context.Context, method string, args interface{}, reply
 		}
 	}
 
-	// audit log only on consul clients
-	_, ok := a.delegate.(*consul.Client)
-	if ok {
-		a.writeAuditRPCEvent(method, "OperationStart")
-	}
+	// audit log only on consul clients, servers audit the RPCs they handle
+	_, isClient := a.delegate.(*consul.Client)
 
 	a.endpointsLock.RUnlock()
 
-	defer func() {
-		a.writeAuditRPCEvent(method, "OperationComplete")
-	}()
+	if isClient && a.baseDeps.Auditor != nil && a.baseDeps.Auditor.RPCEnabled() {
+		start := time.Now()
+		err := a.delegate.RPC(ctx, method, args, reply)
+		a.writeAuditRPCEvent(method, args, start, err)
+		return err
+	}
 	return a.delegate.RPC(ctx, method, args, reply)
 }
 
@@ -7,8 +7,10 @@ package agent
 
 import (
 	"context"
+	"time"
 
 	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/config"
 	"github.com/hashicorp/consul/agent/consul"
 	"github.com/hashicorp/consul/agent/proxycfg"
@@ -69,6 +71,13 @@ func (a *Agent) registerEntCache() {}
 
 func (*Agent) fillEnterpriseProxyDataSources(*proxycfg.DataSources) {}
 
-func (a *Agent) writeAuditRPCEvent(_ string, _ string) interface{} {
-	return nil
+// writeAuditRPCEvent records an RPC made by a client agent in the audit log.
+func (a *Agent) writeAuditRPCEvent(method string, args interface{}, start time.Time, err error) {
+	auditor := a.baseDeps.Auditor
+	if !auditor.Matches(method) {
+		return
+	}
+	ev := audit.NewRPCEvent(method, args, start, err)
+	ev.Auth.AccessorID = a.aclAccessorID(ev.Auth.SecretID)
+	auditor.Write(ev)
 }
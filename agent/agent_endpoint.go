@@ -1497,6 +1497,12 @@ func (s *HTTPHandlers) AgentToken(resp http.ResponseWriter, req *http.Request) (
 		return nil, err
 	}
 
+	// Figure out the target token.
+	target := strings.TrimPrefix(req.URL.Path, "/v1/agent/token/")
+	if target == "renew" {
+		return s.agentTokenRenew(req)
+	}
+
 	// The body is just the token, but it's in a JSON object so we can add
 	// fields to this later if needed.
 	var args api.AgentToken
@@ -1504,9 +1510,6 @@ func (s *HTTPHandlers) AgentToken(resp http.ResponseWriter, req *http.Request) (
 		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request decode failed: %v", err)}
 	}
 
-	// Figure out the target token.
-	target := strings.TrimPrefix(req.URL.Path, "/v1/agent/token/")
-
 	err = s.agent.tokens.WithPersistenceLock(func() error {
 		triggerAntiEntropySync := false
 		switch target {
@@ -1552,6 +1555,80 @@ func (s *HTTPHandlers) AgentToken(resp http.ResponseWriter, req *http.Request) (
 	return nil, nil
 }
 
+// agentTokenRenew asks the servers whether any of the tokens in the agent's
+// token store have been rotated and swaps in the new SecretIDs for those that
+// have. The agent recovery token is never a real ACL token so it is skipped.
+func (s *HTTPHandlers) agentTokenRenew(req *http.Request) (interface{}, error) {
+	type storedToken struct {
+		name   string
+		token  string
+		update func(string, token_store.TokenSource) bool
+	}
+
+	var stored []storedToken
+	add := func(name string, get func() (string, token_store.TokenSource), update func(string, token_store.TokenSource) bool) {
+		if tok, _ := get(); tok != "" {
+			stored = append(stored, storedToken{name: name, token: tok, update: update})
+		}
+	}
+	add("default", s.agent.tokens.UserTokenAndSource, s.agent.tokens.UpdateUserToken)
+	add("agent", s.agent.tokens.AgentTokenAndSource, s.agent.tokens.UpdateAgentToken)
+	add("replication", s.agent.tokens.ReplicationTokenAndSource, s.agent.tokens.UpdateReplicationToken)
+	add("config_file_service_registration", s.agent.tokens.ConfigFileRegistrationTokenAndSource, s.agent.tokens.UpdateConfigFileRegistrationToken)
+	add("dns", s.agent.tokens.DNSTokenAndSource, s.agent.tokens.UpdateDNSToken)
+
+	renewed := make(map[string]string)
+	for _, st := range stored {
+		args := structs.ACLTokenRenewRequest{
+			Datacenter:   s.agent.config.Datacenter,
+			QueryOptions: structs.QueryOptions{Token: st.token},
+		}
+		var out structs.ACLTokenRenewResponse
+		if err := s.agent.RPC(req.Context(), "ACL.TokenRenew", &args, &out); err != nil {
+			if acl.IsErrNotFound(err) {
+				s.agent.logger.Warn("Unable to renew agent's ACL token, token not found", "token", st.name)
+				continue
+			}
+			return nil, err
+		}
+		if out.Rotated && out.SecretID != "" {
+			renewed[st.name] = out.SecretID
+		}
+	}
+
+	result := api.AgentTokenRenewResult{Renewed: []string{}}
+	if len(renewed) == 0 {
+		return result, nil
+	}
+
+	err := s.agent.tokens.WithPersistenceLock(func() error {
+		triggerAntiEntropySync := false
+		for _, st := range stored {
+			secretID, ok := renewed[st.name]
+			if !ok {
+				continue
+			}
+			// Renewed tokens are recorded as coming from the API so that they
+			// are persisted and take precedence over the configured secret.
+			if st.update(secretID, token_store.TokenSourceAPI) && (st.name == "default" || st.name == "agent") {
+				triggerAntiEntropySync = true
+			}
+			result.Renewed = append(result.Renewed, st.name)
+		}
+
+		if triggerAntiEntropySync {
+			s.agent.sync.SyncFull.Trigger()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.agent.logger.Info("Renewed agent's rotated ACL tokens", "tokens", result.Renewed)
+	return result, nil
+}
+
 // AgentConnectCARoots returns the trusted CA roots.
 func (s *HTTPHandlers) AgentConnectCARoots(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	var args structs.DCSpecificRequest
@@ -6639,6 +6639,94 @@ func TestAgent_Token(t *testing.T) {
 	})
 }
 
+func TestAgent_TokenRenew(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, `
+		primary_datacenter = "dc1"
+
+		acl {
+			enabled = true
+			default_policy = "deny"
+
+			tokens {
+				initial_management = "root"
+			}
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	doRequest := func(t *testing.T, method, url string, body io.Reader, token string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, url, body)
+		require.NoError(t, err)
+		req.Header.Add("X-Consul-Token", token)
+
+		resp := httptest.NewRecorder()
+		a.srv.h.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := doRequest(t, "PUT", "/v1/acl/policy", jsonBody(&structs.ACLPolicy{
+		Name:  "agent",
+		Rules: `node_prefix "" { policy = "write" }`,
+	}), "root")
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	resp = doRequest(t, "PUT", "/v1/acl/token", jsonBody(&structs.ACLToken{
+		Policies: []structs.ACLTokenPolicyLink{{Name: "agent"}},
+	}), "root")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var agentToken structs.ACLToken
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&agentToken))
+
+	a.tokens.UpdateAgentToken(agentToken.SecretID, token.TokenSourceAPI)
+
+	renew := func(t *testing.T, token string) (int, api.AgentTokenRenewResult) {
+		resp := doRequest(t, "PUT", "/v1/agent/token/renew", nil, token)
+		var out api.AgentTokenRenewResult
+		if resp.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		}
+		return resp.Code, out
+	}
+
+	t.Run("permission denied", func(t *testing.T) {
+		code, _ := renew(t, "")
+		require.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("nothing rotated", func(t *testing.T) {
+		code, out := renew(t, "root")
+		require.Equal(t, http.StatusOK, code)
+		require.Empty(t, out.Renewed)
+		require.Equal(t, agentToken.SecretID, a.tokens.AgentToken())
+	})
+
+	t.Run("rotated", func(t *testing.T) {
+		resp := doRequest(t, "PUT", "/v1/acl/token/"+agentToken.AccessorID+"/rotate",
+			jsonBody(map[string]string{"GracePeriod": "1h"}), "root")
+		require.Equal(t, http.StatusOK, resp.Code)
+		var rotated structs.ACLToken
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&rotated))
+		require.NotEqual(t, agentToken.SecretID, rotated.SecretID)
+
+		code, out := renew(t, "root")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, []string{"agent"}, out.Renewed)
+		require.Equal(t, rotated.SecretID, a.tokens.AgentToken())
+
+		// Renewing again is a no-op.
+		code, out = renew(t, "root")
+		require.Equal(t, http.StatusOK, code)
+		require.Empty(t, out.Renewed)
+	})
+}
+
 func TestAgentConnectCARoots_empty(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -7838,6 +7926,96 @@ func TestAgentConnectAuthorize_deny(t *testing.T) {
 	assert.Contains(t, obj.Reason, "Matched")
 }
 
+func TestAgentConnectAuthorize_l7(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	target := "db"
+
+	// L7 intentions require the destination to use an HTTP protocol.
+	for _, entry := range []structs.ConfigEntry{
+		&structs.ServiceConfigEntry{
+			Kind:     structs.ServiceDefaults,
+			Name:     target,
+			Protocol: "http",
+		},
+		&structs.ServiceIntentionsConfigEntry{
+			Kind: structs.ServiceIntentions,
+			Name: target,
+			Sources: []*structs.SourceIntention{
+				{
+					Name: "web",
+					Permissions: []*structs.IntentionPermission{
+						{
+							Action: structs.IntentionActionDeny,
+							HTTP: &structs.IntentionHTTPPermission{
+								PathPrefix: "/admin",
+							},
+						},
+						{
+							Action: structs.IntentionActionAllow,
+							HTTP: &structs.IntentionHTTPPermission{
+								PathPrefix: "/",
+								Methods:    []string{"GET"},
+							},
+						},
+					},
+				},
+			},
+		},
+	} {
+		req := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry:      entry,
+		}
+		var reply bool
+		require.NoError(t, a.RPC(context.Background(), "ConfigEntry.Apply", &req, &reply))
+	}
+
+	authorize := func(t *testing.T, httpReq *structs.ConnectAuthorizeHTTPRequest) *connectAuthorizeResp {
+		args := &structs.ConnectAuthorizeRequest{
+			Target:        target,
+			ClientCertURI: connect.TestSpiffeIDService(t, "web").URI().String(),
+			HTTP:          httpReq,
+		}
+		req, _ := http.NewRequest("POST", "/v1/agent/connect/authorize", jsonReader(args))
+		resp := httptest.NewRecorder()
+		a.srv.h.ServeHTTP(resp, req)
+		require.Equal(t, 200, resp.Code)
+
+		obj := &connectAuthorizeResp{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(obj))
+		return obj
+	}
+
+	// Connections matching an L7 intention are denied without a request.
+	obj := authorize(t, nil)
+	require.False(t, obj.Authorized)
+	require.Contains(t, obj.Reason, "Matched L7 intention")
+
+	// The first matching permission applies.
+	obj = authorize(t, &structs.ConnectAuthorizeHTTPRequest{Method: "GET", Path: "/admin/users"})
+	require.False(t, obj.Authorized)
+	require.Contains(t, obj.Reason, "Matched L7 intention")
+
+	obj = authorize(t, &structs.ConnectAuthorizeHTTPRequest{Method: "GET", Path: "/users"})
+	require.True(t, obj.Authorized)
+	require.Contains(t, obj.Reason, "Matched L7 intention")
+
+	// The default behavior applies when no permission matches, it allows
+	// everything since ACLs are disabled.
+	obj = authorize(t, &structs.ConnectAuthorizeHTTPRequest{Method: "POST", Path: "/users"})
+	require.True(t, obj.Authorized)
+	require.Contains(t, obj.Reason, "without matching permission")
+}
+
 // Test when there is an intention allowing service with a different trust
 // domain. We allow this because migration between trust domains shouldn't cause
 // an outage even if we have stale info about current trusted domains. It's safe
@@ -0,0 +1,254 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package audit records API requests and RPCs handled by an agent as JSON
+// lines, for compliance and forensics.
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+)
+
+// Config configures the audit log.
+type Config struct {
+	// Enabled turns on auditing of HTTP API requests.
+	Enabled bool
+
+	// RPCEnabled additionally audits RPCs handled by servers, and RPCs made
+	// by client agents.
+	RPCEnabled bool
+
+	// Include, when not empty, restricts auditing to the endpoints matching
+	// at least one of the patterns. Exclude skips the endpoints matching any
+	// of its patterns and takes precedence over Include. Patterns are matched
+	// against the HTTP path or the RPC method name, and "*" matches any
+	// sequence of characters.
+	Include []string
+	Exclude []string
+
+	// Sinks are the destinations audit entries are written to.
+	Sinks []SinkConfig
+}
+
+// SinkConfig configures a single audit sink.
+type SinkConfig struct {
+	Name              string
+	Type              string
+	Format            string
+	Path              string
+	DeliveryGuarantee string
+	Mode              os.FileMode
+	RotateBytes       int
+	RotateDuration    time.Duration
+	RotateMaxFiles    int
+}
+
+func (c SinkConfig) isDevice() bool {
+	return strings.HasPrefix(c.Path, "/dev/")
+}
+
+// Validate returns an error if the sink configuration is not supported.
+func (c SinkConfig) Validate() error {
+	if c.Type != SinkTypeFile {
+		return fmt.Errorf("audit sink %q: type must be %q", c.Name, SinkTypeFile)
+	}
+	if c.Format != SinkFormatJSON {
+		return fmt.Errorf("audit sink %q: format must be %q", c.Name, SinkFormatJSON)
+	}
+	if c.DeliveryGuarantee != DeliveryGuaranteeBestEffort {
+		return fmt.Errorf("audit sink %q: delivery_guarantee must be %q", c.Name, DeliveryGuaranteeBestEffort)
+	}
+	if c.Path == "" {
+		return fmt.Errorf("audit sink %q: path is required", c.Name)
+	}
+	if strings.HasSuffix(c.Path, "/") {
+		return fmt.Errorf("audit sink %q: path must include a file name", c.Name)
+	}
+	if c.RotateBytes < 0 {
+		return fmt.Errorf("audit sink %q: rotate_bytes cannot be negative", c.Name)
+	}
+	if c.RotateDuration < 0 {
+		return fmt.Errorf("audit sink %q: rotate_duration cannot be negative", c.Name)
+	}
+	if !c.isDevice() && c.RotateBytes == 0 && c.RotateDuration == 0 {
+		return fmt.Errorf("audit sink %q: at least one of rotate_bytes or rotate_duration must be set", c.Name)
+	}
+	return nil
+}
+
+// Validate returns an error if the audit configuration is not supported.
+func (c Config) Validate() error {
+	var errs []error
+	for _, s := range c.Sinks {
+		if err := s.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Auditor filters audit events and writes them to the configured sinks.
+type Auditor struct {
+	logger     hclog.Logger
+	hasher     *Hasher
+	sinks      []Sink
+	include    []string
+	exclude    []string
+	rpcEnabled bool
+	now        func() time.Time
+}
+
+// New returns an Auditor for cfg, or nil if auditing is disabled. The salt
+// used to hash sensitive values is persisted in dataDir.
+func New(cfg Config, dataDir string, logger hclog.Logger) (*Auditor, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	hasher, err := LoadHasher(dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Auditor{
+		logger:     logger,
+		hasher:     hasher,
+		include:    cfg.Include,
+		exclude:    cfg.Exclude,
+		rpcEnabled: cfg.RPCEnabled,
+		now:        time.Now,
+	}
+	for _, sc := range cfg.Sinks {
+		sink, err := newFileSink(sc)
+		if err != nil {
+			a.Close()
+			return nil, err
+		}
+		a.sinks = append(a.sinks, sink)
+	}
+	return a, nil
+}
+
+// RPCEnabled returns true if RPCs should be audited.
+func (a *Auditor) RPCEnabled() bool {
+	return a.rpcEnabled
+}
+
+// Hash returns the salted hash of input, as it would appear in the audit log.
+func (a *Auditor) Hash(input string) string {
+	return a.hasher.Hash(input)
+}
+
+// Matches returns true if events for endpoint pass the include and exclude
+// filters.
+func (a *Auditor) Matches(endpoint string) bool {
+	for _, pattern := range a.exclude {
+		if matchGlob(pattern, endpoint) {
+			return false
+		}
+	}
+	if len(a.include) == 0 {
+		return true
+	}
+	for _, pattern := range a.include {
+		if matchGlob(pattern, endpoint) {
+			return true
+		}
+	}
+	return false
+}
+
+// Write hashes the sensitive fields of ev and writes it to every sink if it
+// passes the filters. Delivery is best-effort: sink errors are logged and
+// do not fail the audited operation.
+func (a *Auditor) Write(ev *Event) {
+	if !a.Matches(ev.Request.Endpoint) {
+		return
+	}
+
+	now := a.now()
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		a.logger.Error("failed to generate audit event ID", "error", err)
+	}
+	ev.ID = id
+	ev.Version = eventVersion
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = now
+	}
+	if ev.Stage == "" {
+		ev.Stage = StageOperationComplete
+	}
+	ev.Auth.SecretID = a.hasher.Hash(ev.Auth.SecretID)
+	for k, v := range ev.Request.QueryParams {
+		if _, ok := sensitiveQueryParams[k]; ok {
+			ev.Request.QueryParams[k] = a.hasher.Hash(v)
+		}
+	}
+
+	entry := &Entry{
+		CreatedAt: now,
+		EventType: "audit",
+		Payload:   ev,
+	}
+	for _, sink := range a.sinks {
+		if err := sink.Write(entry); err != nil {
+			a.logger.Error("failed to write audit event",
+				"endpoint", ev.Request.Endpoint,
+				"error", err,
+			)
+		}
+	}
+}
+
+// Close closes all sinks.
+func (a *Auditor) Close() error {
+	var errs []error
+	for _, sink := range a.sinks {
+		if err := sink.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// matchGlob reports whether s matches pattern, where "*" matches any
+// sequence of characters, including "/" and ".".
+func matchGlob(pattern, s string) bool {
+	px, sx := 0, 0
+	nextPx, nextSx := -1, -1
+	for px < len(pattern) || sx < len(s) {
+		if px < len(pattern) {
+			switch c := pattern[px]; c {
+			case '*':
+				// Try to match the empty sequence first, remembering where
+				// to resume if the rest of the pattern does not match.
+				nextPx, nextSx = px, sx+1
+				px++
+				continue
+			default:
+				if sx < len(s) && s[sx] == c {
+					px++
+					sx++
+					continue
+				}
+			}
+		}
+		if nextSx > 0 && nextSx <= len(s) {
+			px, sx = nextPx, nextSx
+			continue
+		}
+		return false
+	}
+	return true
+}
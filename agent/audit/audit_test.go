@@ -0,0 +1,231 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		pattern string
+		input   string
+		match   bool
+	}{
+		{"/v1/kv/foo", "/v1/kv/foo", true},
+		{"/v1/kv/foo", "/v1/kv/foobar", false},
+		{"/v1/kv/*", "/v1/kv/foo/bar", true},
+		{"/v1/kv/*", "/v1/kv/", true},
+		{"/v1/kv/*", "/v1/kv", false},
+		{"*", "", true},
+		{"*", "KVS.Apply", true},
+		{"KVS.*", "KVS.Apply", true},
+		{"KVS.*", "Catalog.Register", false},
+		{"*.Register", "Catalog.Register", true},
+		{"/v1/*/service/*", "/v1/catalog/service/web", true},
+		{"/v1/*/service/*", "/v1/catalog/services", false},
+		{"*a*b", "xaxxbxb", true},
+		{"*a*b", "xaxxbx", false},
+	}
+	for _, tc := range cases {
+		require.Equal(t, tc.match, matchGlob(tc.pattern, tc.input), "pattern %q input %q", tc.pattern, tc.input)
+	}
+}
+
+func TestAuditor_Matches(t *testing.T) {
+	a := &Auditor{
+		include: []string{"/v1/kv/*", "KVS.*"},
+		exclude: []string{"/v1/kv/noisy/*"},
+	}
+	require.True(t, a.Matches("/v1/kv/foo"))
+	require.True(t, a.Matches("KVS.Apply"))
+	require.False(t, a.Matches("/v1/kv/noisy/foo"))
+	require.False(t, a.Matches("/v1/catalog/services"))
+
+	a = &Auditor{exclude: []string{"/v1/agent/*"}}
+	require.True(t, a.Matches("/v1/catalog/services"))
+	require.False(t, a.Matches("/v1/agent/self"))
+}
+
+func TestHasher(t *testing.T) {
+	dir := t.TempDir()
+
+	h1, err := LoadHasher(dir)
+	require.NoError(t, err)
+	h2, err := LoadHasher(dir)
+	require.NoError(t, err)
+
+	hash := h1.Hash("secret")
+	require.True(t, strings.HasPrefix(hash, hashPrefix))
+	require.NotContains(t, hash, "secret")
+	require.Equal(t, hash, h2.Hash("secret"), "salt should be persisted")
+	require.NotEqual(t, hash, h1.Hash("other"))
+	require.Empty(t, h1.Hash(""))
+
+	h3, err := LoadHasher(t.TempDir())
+	require.NoError(t, err)
+	require.NotEqual(t, hash, h3.Hash("secret"), "salts should differ between data directories")
+
+	info, err := os.Stat(filepath.Join(dir, saltFile))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestSinkConfig_Validate(t *testing.T) {
+	valid := SinkConfig{
+		Name:              "test",
+		Type:              SinkTypeFile,
+		Format:            SinkFormatJSON,
+		Path:              "/tmp/audit.json",
+		DeliveryGuarantee: DeliveryGuaranteeBestEffort,
+		RotateDuration:    time.Hour,
+	}
+	require.NoError(t, valid.Validate())
+
+	cases := map[string]struct {
+		modify func(c *SinkConfig)
+		err    string
+	}{
+		"type":        {func(c *SinkConfig) { c.Type = "syslog" }, `type must be "file"`},
+		"format":      {func(c *SinkConfig) { c.Format = "text" }, `format must be "json"`},
+		"delivery":    {func(c *SinkConfig) { c.DeliveryGuarantee = "enforced" }, `delivery_guarantee must be "best-effort"`},
+		"no path":     {func(c *SinkConfig) { c.Path = "" }, "path is required"},
+		"dir path":    {func(c *SinkConfig) { c.Path = "/tmp/" }, "path must include a file name"},
+		"no rotation": {func(c *SinkConfig) { c.RotateDuration = 0 }, "at least one of rotate_bytes or rotate_duration must be set"},
+		"device without rotation": {func(c *SinkConfig) {
+			c.Path = "/dev/stdout"
+			c.RotateDuration = 0
+		}, ""},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := valid
+			tc.modify(&c)
+			err := c.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestAuditor_Write(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.json")
+
+	a, err := New(Config{
+		Enabled: true,
+		Exclude: []string{"/v1/agent/*"},
+		Sinks: []SinkConfig{{
+			Name:              "file",
+			Type:              SinkTypeFile,
+			Format:            SinkFormatJSON,
+			Path:              path,
+			DeliveryGuarantee: DeliveryGuaranteeBestEffort,
+			RotateDuration:    time.Hour,
+		}},
+	}, dir, hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("PUT", "/v1/kv/foo?token=root&dc=dc2", nil)
+	req.Header.Set("User-Agent", "test")
+	ev := NewHTTPEvent(req, req.URL.Path, 403, time.Now())
+	ev.Auth.AccessorID = "accessor"
+	ev.Auth.SecretID = "root"
+	a.Write(ev)
+
+	a.Write(NewHTTPEvent(httptest.NewRequest("GET", "/v1/agent/self", nil), "/v1/agent/self", 200, time.Now()))
+
+	rpcArgs := &structs.KeyRequest{
+		Datacenter:   "dc1",
+		Key:          "foo",
+		QueryOptions: structs.QueryOptions{Token: "root"},
+	}
+	a.Write(NewRPCEvent("KVS.Get", rpcArgs, time.Now(), errors.New("boom")))
+
+	expiration := time.Now().Add(time.Hour).UTC()
+	a.Write(NewBreakGlassEvent("dc1", BreakGlass{
+		AccessorID:     "issued",
+		Reason:         "incident 42",
+		RequestedBy:    "requester",
+		ApprovedBy:     "approver",
+		ExpirationTime: expiration,
+	}))
+	require.NoError(t, a.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry Entry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, entries, 3, "the excluded endpoint should not be audited")
+
+	hash := a.Hash("root")
+
+	http := entries[0]
+	require.Equal(t, "audit", http.EventType)
+	require.NotEmpty(t, http.Payload.ID)
+	require.Equal(t, EventTypeHTTP, http.Payload.Type)
+	require.Equal(t, StageOperationComplete, http.Payload.Stage)
+	require.Equal(t, "accessor", http.Payload.Auth.AccessorID)
+	require.Equal(t, hash, http.Payload.Auth.SecretID)
+	require.Equal(t, "PUT", http.Payload.Request.Operation)
+	require.Equal(t, "/v1/kv/foo", http.Payload.Request.Endpoint)
+	require.Equal(t, "dc2", http.Payload.Request.Datacenter)
+	require.Equal(t, "test", http.Payload.Request.UserAgent)
+	require.Equal(t, map[string]string{"token": hash, "dc": "dc2"}, http.Payload.Request.QueryParams)
+	require.Equal(t, "403", http.Payload.Response.Status)
+	require.Equal(t, OutcomeError, http.Payload.Response.Outcome)
+
+	rpc := entries[1]
+	require.Equal(t, EventTypeRPC, rpc.Payload.Type)
+	require.Equal(t, "KVS.Get", rpc.Payload.Request.Endpoint)
+	require.Equal(t, "read", rpc.Payload.Request.Operation)
+	require.Equal(t, "dc1", rpc.Payload.Request.Datacenter)
+	require.Equal(t, hash, rpc.Payload.Auth.SecretID)
+	require.Equal(t, OutcomeError, rpc.Payload.Response.Outcome)
+	require.Equal(t, "boom", rpc.Payload.Response.Error)
+
+	bg := entries[2]
+	require.Equal(t, EventTypeBreakGlass, bg.Payload.Type)
+	require.Equal(t, BreakGlassEndpoint, bg.Payload.Request.Endpoint)
+	require.Equal(t, "requester", bg.Payload.Auth.AccessorID)
+	require.Empty(t, bg.Payload.Auth.SecretID)
+	require.False(t, bg.Payload.Timestamp.IsZero())
+	require.Equal(t, &BreakGlass{
+		AccessorID:     "issued",
+		Reason:         "incident 42",
+		RequestedBy:    "requester",
+		ApprovedBy:     "approver",
+		ExpirationTime: expiration,
+	}, bg.Payload.BreakGlass)
+}
+
+func TestNew_Disabled(t *testing.T) {
+	a, err := New(Config{}, t.TempDir(), hclog.NewNullLogger())
+	require.NoError(t, err)
+	require.Nil(t, a)
+}
@@ -0,0 +1,200 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package audit
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// EventTypeHTTP is the payload type for requests made to the HTTP API.
+	EventTypeHTTP = "HTTPEvent"
+
+	// EventTypeRPC is the payload type for RPCs handled by a server or made
+	// by a client agent on behalf of a caller.
+	EventTypeRPC = "RPCEvent"
+
+	// EventTypeBreakGlass is the payload type for the issuance of a
+	// break-glass token.
+	EventTypeBreakGlass = "BreakGlassEvent"
+
+	// BreakGlassEndpoint is the endpoint recorded for break-glass events.
+	// Include and exclude filters are matched against it.
+	BreakGlassEndpoint = "ACL.TokenBreakGlass"
+
+	// StageOperationComplete is recorded once the operation has finished
+	// and its outcome is known.
+	StageOperationComplete = "OperationComplete"
+
+	// OutcomeSuccess and OutcomeError describe how the operation ended.
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+
+	// eventVersion is the version of the payload format written to sinks.
+	eventVersion = "1"
+)
+
+// Entry is a single line written to an audit sink.
+type Entry struct {
+	CreatedAt time.Time `json:"created_at"`
+	EventType string    `json:"event_type"`
+	Payload   *Event    `json:"payload"`
+}
+
+// Event describes a single audited operation.
+type Event struct {
+	ID        string    `json:"id"`
+	Version   string    `json:"version"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Auth      Auth      `json:"auth"`
+	Request   Request   `json:"request"`
+	Response  Response  `json:"response"`
+	Stage     string    `json:"stage"`
+
+	// BreakGlass describes the issued token for break-glass events.
+	BreakGlass *BreakGlass `json:"break_glass,omitempty"`
+
+	// LatencyMS is how long the operation took to complete, in milliseconds.
+	LatencyMS float64 `json:"latency_ms"`
+}
+
+// Auth identifies the ACL token used for the operation.
+type Auth struct {
+	AccessorID string `json:"accessor_id,omitempty"`
+
+	// SecretID is the token presented by the caller. It is always replaced
+	// with its salted hash before the event is written to a sink.
+	SecretID string `json:"secret_id,omitempty"`
+}
+
+// Request describes the operation that was attempted.
+type Request struct {
+	// Operation is the HTTP method, or "read" or "write" for RPCs.
+	Operation string `json:"operation"`
+
+	// Endpoint is the HTTP path or the RPC method name. Include and exclude
+	// filters are matched against it.
+	Endpoint string `json:"endpoint"`
+
+	RemoteAddr string `json:"remote_addr,omitempty"`
+	UserAgent  string `json:"user_agent,omitempty"`
+	Host       string `json:"host,omitempty"`
+	Datacenter string `json:"datacenter,omitempty"`
+
+	// QueryParams holds the HTTP query parameters. Sensitive values are
+	// hashed before the event is written to a sink.
+	QueryParams map[string]string `json:"query_params,omitempty"`
+}
+
+// Response describes the outcome of the operation.
+type Response struct {
+	// Status is the HTTP status code for HTTP events.
+	Status  string `json:"status,omitempty"`
+	Outcome string `json:"outcome"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BreakGlass describes an issued break-glass token.
+type BreakGlass struct {
+	AccessorID     string    `json:"accessor_id"`
+	Reason         string    `json:"reason"`
+	RequestedBy    string    `json:"requested_by"`
+	ApprovedBy     string    `json:"approved_by"`
+	ExpirationTime time.Time `json:"expiration_time"`
+}
+
+// sensitiveQueryParams are the query parameters whose values are hashed.
+var sensitiveQueryParams = map[string]struct{}{
+	"token": {},
+}
+
+// NewHTTPEvent returns the event for req, started at start and completed with
+// the given status code. endpoint is the request path with any secrets
+// already redacted.
+func NewHTTPEvent(req *http.Request, endpoint string, status int, start time.Time) *Event {
+	ev := &Event{
+		Type:      EventTypeHTTP,
+		Timestamp: start,
+		Request: Request{
+			Operation:  req.Method,
+			Endpoint:   endpoint,
+			RemoteAddr: req.RemoteAddr,
+			UserAgent:  req.UserAgent(),
+			Host:       req.Host,
+		},
+		Response: Response{
+			Status:  strconv.Itoa(status),
+			Outcome: OutcomeSuccess,
+		},
+		LatencyMS: latencyMS(start),
+	}
+	if query := req.URL.Query(); len(query) > 0 {
+		ev.Request.QueryParams = make(map[string]string, len(query))
+		for k, v := range query {
+			ev.Request.QueryParams[k] = strings.Join(v, ",")
+		}
+		ev.Request.Datacenter = query.Get("dc")
+	}
+	if status >= http.StatusBadRequest {
+		ev.Response.Outcome = OutcomeError
+	}
+	return ev
+}
+
+// NewRPCEvent returns the event for an RPC to method with the given arguments,
+// started at start and completed with err. The token and datacenter are taken
+// from args when it carries them.
+func NewRPCEvent(method string, args interface{}, start time.Time, err error) *Event {
+	ev := &Event{
+		Type:      EventTypeRPC,
+		Timestamp: start,
+		Request: Request{
+			Operation: "unknown",
+			Endpoint:  method,
+		},
+		Response:  Response{Outcome: OutcomeSuccess},
+		LatencyMS: latencyMS(start),
+	}
+	if r, ok := args.(interface{ IsRead() bool }); ok {
+		ev.Request.Operation = "write"
+		if r.IsRead() {
+			ev.Request.Operation = "read"
+		}
+	}
+	if r, ok := args.(interface{ TokenSecret() string }); ok {
+		ev.Auth.SecretID = r.TokenSecret()
+	}
+	if r, ok := args.(interface{ RequestDatacenter() string }); ok {
+		ev.Request.Datacenter = r.RequestDatacenter()
+	}
+	if err != nil {
+		ev.Response.Outcome = OutcomeError
+		ev.Response.Error = err.Error()
+	}
+	return ev
+}
+
+// NewBreakGlassEvent returns the event for a break-glass token issued in the
+// given datacenter.
+func NewBreakGlassEvent(datacenter string, bg BreakGlass) *Event {
+	return &Event{
+		Type: EventTypeBreakGlass,
+		Auth: Auth{AccessorID: bg.RequestedBy},
+		Request: Request{
+			Operation:  "write",
+			Endpoint:   BreakGlassEndpoint,
+			Datacenter: datacenter,
+		},
+		Response:   Response{Outcome: OutcomeSuccess},
+		BreakGlass: &bg,
+	}
+}
+
+func latencyMS(start time.Time) float64 {
+	return float64(time.Since(start).Microseconds()) / 1000
+}
@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package audit
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// hashPrefix identifies the algorithm used to produce a hashed value.
+	hashPrefix = "hmac-sha256:"
+
+	// saltFile is the name of the file in the data directory holding the
+	// salt used to hash sensitive values.
+	saltFile = "audit-salt"
+
+	saltSize = 32
+)
+
+// Hasher hashes sensitive values with a salted HMAC so that they can be
+// correlated across audit events without being disclosed.
+type Hasher struct {
+	salt []byte
+}
+
+// NewHasher returns a Hasher using the given salt.
+func NewHasher(salt []byte) *Hasher {
+	return &Hasher{salt: salt}
+}
+
+// LoadHasher returns a Hasher using the salt persisted in dataDir, creating
+// it on first use. The salt must be stable across restarts for hashes to be
+// comparable with the output of the audit-hash endpoint. When dataDir is
+// empty a random salt is used for the lifetime of the process.
+func LoadHasher(dataDir string) (*Hasher, error) {
+	if dataDir == "" {
+		salt, err := newSalt()
+		if err != nil {
+			return nil, err
+		}
+		return NewHasher(salt), nil
+	}
+
+	path := filepath.Join(dataDir, saltFile)
+	raw, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		salt, err := hex.DecodeString(strings.TrimSpace(string(raw)))
+		if err != nil || len(salt) == 0 {
+			return nil, fmt.Errorf("invalid audit salt in %q", path)
+		}
+		return NewHasher(salt), nil
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("failed to read audit salt: %w", err)
+	}
+
+	salt, err := newSalt()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dataDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(salt)), 0600); err != nil {
+		return nil, fmt.Errorf("failed to persist audit salt: %w", err)
+	}
+	return NewHasher(salt), nil
+}
+
+func newSalt() ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate audit salt: %w", err)
+	}
+	return salt, nil
+}
+
+// Hash returns the salted hash of input. Empty values are returned as is.
+func (h *Hasher) Hash(input string) string {
+	if input == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, h.salt)
+	mac.Write([]byte(input))
+	return hashPrefix + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/hashicorp/consul/logging"
+)
+
+const (
+	SinkTypeFile = "file"
+
+	SinkFormatJSON = "json"
+
+	DeliveryGuaranteeBestEffort = "best-effort"
+)
+
+// Sink is a destination for audit entries.
+type Sink interface {
+	Write(entry *Entry) error
+	Close() error
+}
+
+// fileSink writes entries as JSON lines to a rotating log file.
+type fileSink struct {
+	lock sync.Mutex
+	out  io.WriteCloser
+}
+
+var _ Sink = (*fileSink)(nil)
+
+// newFileSink opens the file sink described by cfg. Paths under /dev, such as
+// /dev/stdout, are written to directly without rotation.
+func newFileSink(cfg SinkConfig) (*fileSink, error) {
+	if cfg.isDevice() {
+		f, err := os.OpenFile(cfg.Path, os.O_WRONLY|os.O_APPEND, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit sink %q: %w", cfg.Name, err)
+		}
+		return &fileSink{out: f}, nil
+	}
+
+	l, err := logging.NewLogFile(cfg.Path, cfg.RotateDuration, cfg.RotateBytes, cfg.RotateMaxFiles, cfg.Mode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit sink %q: %w", cfg.Name, err)
+	}
+	return &fileSink{out: l}, nil
+}
+
+func (s *fileSink) Write(entry *Entry) error {
+	buf, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	buf = append(buf, '\n')
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, err = s.out.Write(buf)
+	return err
+}
+
+func (s *fileSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.out.Close()
+}
@@ -8,13 +8,14 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/consul/agent/cache"
+	"github.com/hashicorp/consul/agent/cacheshim"
 	"github.com/hashicorp/consul/agent/structs"
 )
 
 // Recommended name for registration.
 const (
 	ConfigEntryListName = "config-entries"
-	ConfigEntryName     = "config-entry"
+	ConfigEntryName     = cacheshim.ConfigEntryName
 )
 
 // ConfigEntryList supports fetching discovering configuration entries
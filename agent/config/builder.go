@@ -28,6 +28,7 @@ import (
 	"github.com/hashicorp/memberlist"
 	"golang.org/x/time/rate"
 
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/cache"
 	"github.com/hashicorp/consul/agent/checks"
 	"github.com/hashicorp/consul/agent/connect/ca"
@@ -445,6 +446,7 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 	serverPort := b.portVal("ports.server", c.Ports.Server)
 	grpcPort := b.portVal("ports.grpc", c.Ports.GRPC)
 	grpcTlsPort := b.portVal("ports.grpc_tls", c.Ports.GRPCTLS)
+	spiffeBundlePort := b.portVal("ports.spiffe_bundle", c.Ports.SPIFFEBundle)
 	// default gRPC TLS port for servers is 8503
 	if c.Ports.GRPCTLS == nil && boolVal(c.ServerMode) {
 		grpcTlsPort = 8503
@@ -588,6 +590,7 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 	httpsAddrs := b.makeAddrs(b.expandAddrs("addresses.https", c.Addresses.HTTPS), clientAddrs, httpsPort)
 	grpcAddrs := b.makeAddrs(b.expandAddrs("addresses.grpc", c.Addresses.GRPC), clientAddrs, grpcPort)
 	grpcTlsAddrs := b.makeAddrs(b.expandAddrs("addresses.grpc_tls", c.Addresses.GRPCTLS), clientAddrs, grpcTlsPort)
+	spiffeBundleAddrs := b.makeAddrs(nil, clientAddrs, spiffeBundlePort)
 
 	for _, a := range dnsAddrs {
 		if x, ok := a.(*net.TCPAddr); ok {
@@ -740,6 +743,8 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 
 			// Common CA config
 			"leaf_cert_ttl":      "LeafCertTTL",
+			"min_leaf_cert_ttl":  "MinLeafCertTTL",
+			"max_leaf_cert_ttl":  "MaxLeafCertTTL",
 			"csr_max_per_second": "CSRMaxPerSecond",
 			"csr_max_concurrent": "CSRMaxConcurrent",
 			"private_key_type":   "PrivateKeyType",
@@ -928,6 +933,10 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		AllowWriteHTTPFrom:  b.cidrsVal("allow_write_http_from", c.HTTPConfig.AllowWriteHTTPFrom),
 		HTTPUseCache:        boolValWithDefault(c.HTTPConfig.UseCache, true),
 
+		// SPIFFE bundle endpoint
+		SPIFFEBundlePort:  spiffeBundlePort,
+		SPIFFEBundleAddrs: spiffeBundleAddrs,
+
 		// Telemetry
 		Telemetry: lib.TelemetryConfig{
 			CirconusAPIApp:                     stringVal(c.Telemetry.CirconusAPIApp),
@@ -985,11 +994,13 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		AutoEncryptIPSAN:                       autoEncryptIPSAN,
 		AutoEncryptAllowTLS:                    autoEncryptAllowTLS,
 		AutoConfig:                             autoConfig,
+		Audit:                                  b.auditVal(c.Audit),
 		Cloud:                                  b.cloudConfigVal(c),
 		ConnectEnabled:                         connectEnabled,
 		ConnectCAProvider:                      connectCAProvider,
 		ConnectCAConfig:                        connectCAConfig,
 		ConnectMeshGatewayWANFederationEnabled: connectMeshGatewayWANFederationEnabled,
+		ConnectSPIFFEBundleProfile:             stringValWithDefault(c.Connect.SPIFFEBundleProfile, "https_spiffe"),
 		ConnectSidecarMinPort:                  sidecarMinPort,
 		ConnectSidecarMaxPort:                  sidecarMaxPort,
 		ConnectTestCALeafRootChangeSpread:      b.durationVal("connect.test_ca_leaf_root_change_spread", c.Connect.TestCALeafRootChangeSpread),
@@ -1375,6 +1386,20 @@ func (b *builder) validate(rt RuntimeConfig) error {
 		}
 	}
 
+	switch rt.ConnectSPIFFEBundleProfile {
+	case "https_spiffe", "https_web":
+	default:
+		return fmt.Errorf("connect.spiffe_bundle_profile must be either \"https_spiffe\" or \"https_web\", got %q", rt.ConnectSPIFFEBundleProfile)
+	}
+	if rt.SPIFFEBundlePort > 0 {
+		if !rt.ConnectEnabled {
+			return fmt.Errorf("'ports.spiffe_bundle' requires 'connect.enabled = true'")
+		}
+		if rt.ConnectSPIFFEBundleProfile == "https_web" && rt.TLS.HTTPS.CertFile == "" {
+			return fmt.Errorf("'connect.spiffe_bundle_profile = \"https_web\"' requires a TLS certificate for HTTPS")
+		}
+	}
+
 	if rt.ConnectMeshGatewayWANFederationEnabled && !rt.ServerMode {
 		return fmt.Errorf("'connect.enable_mesh_gateway_wan_federation = true' requires 'server = true'")
 	}
@@ -1438,6 +1463,9 @@ func (b *builder) validate(rt RuntimeConfig) error {
 	if err := addrsUnique(inuse, "HTTPS", rt.HTTPSAddrs); err != nil {
 		return err
 	}
+	if err := addrsUnique(inuse, "SPIFFE bundle", rt.SPIFFEBundleAddrs); err != nil {
+		return err
+	}
 	if err := addrUnique(inuse, "RPC Advertise", rt.RPCAdvertiseAddr); err != nil {
 		return err
 	}
@@ -1469,10 +1497,11 @@ func (b *builder) validate(rt RuntimeConfig) error {
 
 	// Validate the given Connect CA provider config
 	validCAProviders := map[string]bool{
-		"":                       true,
-		structs.ConsulCAProvider: true,
-		structs.VaultCAProvider:  true,
-		structs.AWSCAProvider:    true,
+		"":                        true,
+		structs.ConsulCAProvider:  true,
+		structs.VaultCAProvider:   true,
+		structs.AWSCAProvider:     true,
+		structs.OfflineCAProvider: true,
 	}
 	if _, ok := validCAProviders[rt.ConnectCAProvider]; !ok {
 		return fmt.Errorf("%s is not a valid CA provider", rt.ConnectCAProvider)
@@ -1490,6 +1519,10 @@ func (b *builder) validate(rt RuntimeConfig) error {
 			if _, err := ca.ParseAWSCAConfig(rt.ConnectCAConfig); err != nil {
 				return err
 			}
+		case structs.OfflineCAProvider:
+			if _, err := ca.ParseOfflineCAConfig(rt.ConnectCAConfig); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -1540,6 +1573,12 @@ func (b *builder) validate(rt RuntimeConfig) error {
 		return err
 	}
 
+	if rt.Audit.Enabled {
+		if err := rt.Audit.Validate(); err != nil {
+			return err
+		}
+	}
+
 	if rt.AutoConfig.Enabled && rt.AutoEncryptTLS {
 		return fmt.Errorf("both auto_encrypt.tls and auto_config.enabled cannot be set to true.")
 	}
@@ -2388,6 +2427,45 @@ func (b *builder) makeAddrs(pri []net.Addr, sec []*net.IPAddr, port int) []net.A
 	return x
 }
 
+func (b *builder) auditVal(raw Audit) audit.Config {
+	val := audit.Config{
+		Enabled:    boolVal(raw.Enabled),
+		RPCEnabled: boolVal(raw.RPCEnabled),
+		Include:    raw.Include,
+		Exclude:    raw.Exclude,
+	}
+
+	names := make([]string, 0, len(raw.Sinks))
+	for name := range raw.Sinks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		s := raw.Sinks[name]
+		sink := audit.SinkConfig{
+			Name:              name,
+			Type:              stringValWithDefault(s.Type, audit.SinkTypeFile),
+			Format:            stringValWithDefault(s.Format, audit.SinkFormatJSON),
+			Path:              stringVal(s.Path),
+			DeliveryGuarantee: stringValWithDefault(s.DeliveryGuarantee, audit.DeliveryGuaranteeBestEffort),
+			Mode:              0600,
+			RotateBytes:       intVal(s.RotateBytes),
+			RotateDuration:    b.durationVal(fmt.Sprintf("audit.sink[%s].rotate_duration", name), s.RotateDuration),
+			RotateMaxFiles:    intVal(s.RotateMaxFiles),
+		}
+		if s.Mode != nil {
+			mode, err := strconv.ParseUint(*s.Mode, 8, 32)
+			if err != nil {
+				b.err = multierror.Append(b.err, fmt.Errorf("audit.sink[%s].mode: invalid file mode %q", name, *s.Mode))
+			}
+			sink.Mode = os.FileMode(mode)
+		}
+		val.Sinks = append(val.Sinks, sink)
+	}
+	return val
+}
+
 func (b *builder) autoConfigVal(raw AutoConfigRaw, agentPartition string) AutoConfig {
 	var val AutoConfig
 
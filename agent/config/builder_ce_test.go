@@ -23,12 +23,6 @@ func TestValidateEnterpriseConfigKeys(t *testing.T) {
 	stringVal := "string"
 
 	cases := map[string]testCase{
-		"read_replica": {
-			config: Config{
-				ReadReplica: &boolVal,
-			},
-			badKeys: []string{"read_replica (or the deprecated non_voting_server)"},
-		},
 		"segment": {
 			config: Config{
 				SegmentName: &stringVal,
@@ -41,28 +35,6 @@ func TestValidateEnterpriseConfigKeys(t *testing.T) {
 			},
 			badKeys: []string{"segments"},
 		},
-		"autopilot.redundancy_zone_tag": {
-			config: Config{
-				Autopilot: Autopilot{
-					RedundancyZoneTag: &stringVal,
-				},
-			},
-			badKeys: []string{"autopilot.redundancy_zone_tag"},
-		},
-		"autopilot.upgrade_version_tag": {
-			config: Config{
-				Autopilot: Autopilot{
-					UpgradeVersionTag: &stringVal,
-				},
-			},
-			badKeys: []string{"autopilot.upgrade_version_tag"},
-		},
-		"autopilot.disable_upgrade_migration": {
-			config: Config{
-				Autopilot: Autopilot{DisableUpgradeMigration: &boolVal},
-			},
-			badKeys: []string{"autopilot.disable_upgrade_migration"},
-		},
 		"dns_config.prefer_namespace": {
 			config: Config{
 				DNS: DNS{PreferNamespace: &boolVal},
@@ -132,7 +104,7 @@ func TestValidateEnterpriseConfigKeys(t *testing.T) {
 					},
 				},
 			},
-			badKeys: []string{"read_replica (or the deprecated non_voting_server)", "segment"},
+			badKeys: []string{"segment"},
 		},
 	}
 
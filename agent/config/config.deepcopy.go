@@ -12,6 +12,7 @@ import (
 	"encoding/asn1"
 	"github.com/armon/go-metrics"
 	"github.com/armon/go-metrics/prometheus"
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/types"
 	"math/big"
@@ -925,6 +926,18 @@ func (o *RuntimeConfig) DeepCopy() *RuntimeConfig {
 		cp.AutoConfig.Authorizer.ClaimAssertions = make([]string, len(o.AutoConfig.Authorizer.ClaimAssertions))
 		copy(cp.AutoConfig.Authorizer.ClaimAssertions, o.AutoConfig.Authorizer.ClaimAssertions)
 	}
+	if o.Audit.Include != nil {
+		cp.Audit.Include = make([]string, len(o.Audit.Include))
+		copy(cp.Audit.Include, o.Audit.Include)
+	}
+	if o.Audit.Exclude != nil {
+		cp.Audit.Exclude = make([]string, len(o.Audit.Exclude))
+		copy(cp.Audit.Exclude, o.Audit.Exclude)
+	}
+	if o.Audit.Sinks != nil {
+		cp.Audit.Sinks = make([]audit.SinkConfig, len(o.Audit.Sinks))
+		copy(cp.Audit.Sinks, o.Audit.Sinks)
+	}
 	if o.ConnectCAConfig != nil {
 		cp.ConnectCAConfig = make(map[string]interface{}, len(o.ConnectCAConfig))
 		for k2, v2 := range o.ConnectCAConfig {
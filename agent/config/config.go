@@ -628,6 +628,7 @@ type Connect struct {
 	CAProvider                      *string                `mapstructure:"ca_provider" json:"ca_provider,omitempty"`
 	CAConfig                        map[string]interface{} `mapstructure:"ca_config" json:"ca_config,omitempty"`
 	MeshGatewayWANFederationEnabled *bool                  `mapstructure:"enable_mesh_gateway_wan_federation" json:"enable_mesh_gateway_wan_federation,omitempty"`
+	SPIFFEBundleProfile             *string                `mapstructure:"spiffe_bundle_profile" json:"spiffe_bundle_profile,omitempty"`
 
 	// TestCALeafRootChangeSpread controls how long after a CA roots change before new leaf certs will be generated.
 	// This is only tuned in tests, generally set to 1ns to make tests deterministic with when to expect updated leaf
@@ -720,6 +721,7 @@ type Ports struct {
 	SidecarMaxPort *int `mapstructure:"sidecar_max_port" json:"sidecar_max_port,omitempty"`
 	ExposeMinPort  *int `mapstructure:"expose_min_port" json:"expose_min_port,omitempty" `
 	ExposeMaxPort  *int `mapstructure:"expose_max_port" json:"expose_max_port,omitempty"`
+	SPIFFEBundle   *int `mapstructure:"spiffe_bundle" json:"spiffe_bundle,omitempty"`
 }
 
 type UnixSocket struct {
@@ -813,6 +815,8 @@ type Audit struct {
 	Enabled    *bool                `mapstructure:"enabled"`
 	Sinks      map[string]AuditSink `mapstructure:"sink"`
 	RPCEnabled *bool                `mapstructure:"rpc_enabled"`
+	Include    []string             `mapstructure:"include"`
+	Exclude    []string             `mapstructure:"exclude"`
 }
 
 // AuditSink can be provided multiple times to define pipelines for auditing
@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/go-uuid"
 	"golang.org/x/time/rate"
 
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/cache"
 	"github.com/hashicorp/consul/agent/consul"
 	consulrate "github.com/hashicorp/consul/agent/consul/rate"
@@ -155,7 +156,8 @@ type RuntimeConfig struct {
 	AutopilotServerStabilizationTime time.Duration
 
 	// AutopilotUpgradeVersionTag is the node tag to use for version info when
-	// performing upgrade migrations. If left blank, the Consul version will be used.
+	// performing upgrade migrations. Upgrade migrations are only performed
+	// when it is set, leaving it blank disables them.
 	//
 	// (Enterprise-only)
 	//
@@ -494,6 +496,12 @@ type RuntimeConfig struct {
 	// process including how servers can authorize requests.
 	AutoConfig AutoConfig
 
+	// Audit configures the audit log of HTTP API requests and, optionally,
+	// RPCs, along with the sinks it is written to.
+	//
+	// hcl: audit { enabled = (true|false) rpc_enabled = (true|false) include = []string exclude = []string sink "name" { ... } }
+	Audit audit.Config
+
 	// ConnectEnabled opts the agent into connect. It should be set on all clients
 	// and servers in a cluster for correct connect operation.
 	ConnectEnabled bool
@@ -526,6 +534,13 @@ type RuntimeConfig struct {
 	// datacenters should exclusively traverse mesh gateways.
 	ConnectMeshGatewayWANFederationEnabled bool
 
+	// ConnectSPIFFEBundleProfile is the SPIFFE bundle endpoint profile the
+	// SPIFFE bundle endpoint is served with, either "https_spiffe" or
+	// "https_web".
+	//
+	// hcl: connect { spiffe_bundle_profile = string }
+	ConnectSPIFFEBundleProfile string
+
 	// ConnectTestCALeafRootChangeSpread is used to control how long the CA leaf
 	// cache with spread CSRs over when a root change occurs. For now we don't
 	// expose this in public config intentionally but could later with a rename.
@@ -781,6 +796,21 @@ type RuntimeConfig struct {
 	// flags: -https-port int
 	HTTPSPort int
 
+	// SPIFFEBundleAddrs contains the list of TCP addresses the SPIFFE bundle
+	// endpoint will bind to. If the endpoint is disabled (ports.spiffe_bundle
+	// <= 0) the list is empty.
+	//
+	// The addresses are taken from 'client_addr'.
+	//
+	// hcl: client_addr = string ports { spiffe_bundle = int }
+	SPIFFEBundleAddrs []net.Addr
+
+	// SPIFFEBundlePort is the port the SPIFFE bundle endpoint listens on. The
+	// default is -1. Setting this to a value <= 0 disables the endpoint.
+	//
+	// hcl: ports { spiffe_bundle = int }
+	SPIFFEBundlePort int
+
 	// KVMaxValueSize controls the max allowed value size. If not set defaults
 	// to raft's suggested max value size.
 	//
@@ -831,7 +861,7 @@ type RuntimeConfig struct {
 	NodeMeta map[string]string
 
 	// ReadReplica is whether this server will act as a non-voting member
-	// of the cluster to help provide read scalability. (Enterprise-only)
+	// of the cluster to help provide read scalability.
 	//
 	// hcl: non_voting_server = (true|false)
 	// flag: -non-voting-server
@@ -26,6 +26,7 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/cache"
 	"github.com/hashicorp/consul/agent/checks"
 	"github.com/hashicorp/consul/agent/consul"
@@ -3649,6 +3650,73 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 			}
 		},
 	})
+	run(t, testCase{
+		desc: "SPIFFE bundle endpoint invalid profile",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{
+				"connect": {
+					"enabled": true,
+					"spiffe_bundle_profile": "https"
+				}
+			}`},
+		hcl: []string{`
+			  connect {
+					enabled = true
+					spiffe_bundle_profile = "https"
+				}
+			`},
+		expectedErr: `connect.spiffe_bundle_profile must be either "https_spiffe" or "https_web", got "https"`,
+	})
+	run(t, testCase{
+		desc: "SPIFFE bundle endpoint requires connect",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{
+				"connect": {
+					"enabled": false
+				},
+				"ports": {
+					"spiffe_bundle": 8443
+				}
+			}`},
+		hcl: []string{`
+			  connect {
+					enabled = false
+				}
+				ports {
+					spiffe_bundle = 8443
+				}
+			`},
+		expectedErr: "'ports.spiffe_bundle' requires 'connect.enabled = true'",
+	})
+	run(t, testCase{
+		desc: "SPIFFE bundle endpoint web profile requires an HTTPS certificate",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{
+				"connect": {
+					"enabled": true,
+					"spiffe_bundle_profile": "https_web"
+				},
+				"ports": {
+					"spiffe_bundle": 8443
+				}
+			}`},
+		hcl: []string{`
+			  connect {
+					enabled = true
+					spiffe_bundle_profile = "https_web"
+				}
+				ports {
+					spiffe_bundle = 8443
+				}
+			`},
+		expectedErr: `'connect.spiffe_bundle_profile = "https_web"' requires a TLS certificate for HTTPS`,
+	})
 	run(t, testCase{
 		desc: "Connect AWS CA provider TTL validation",
 		args: []string{
@@ -4828,6 +4896,101 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 		},
 	})
 
+	// /////////////////////////////////
+	// Audit related tests
+	run(t, testCase{
+		desc: "audit sink defaults",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		hcl: []string{`
+				audit {
+					enabled = true
+					sink "stdout" {
+						path = "/dev/stdout"
+					}
+				}
+			`},
+		json: []string{`{
+				"audit": {
+					"enabled": true,
+					"sink": {
+						"stdout": {
+							"path": "/dev/stdout"
+						}
+					}
+				}
+			}`},
+		expected: func(rt *RuntimeConfig) {
+			rt.DataDir = dataDir
+			rt.Audit = audit.Config{
+				Enabled: true,
+				Sinks: []audit.SinkConfig{{
+					Name:              "stdout",
+					Type:              "file",
+					Format:            "json",
+					Path:              "/dev/stdout",
+					DeliveryGuarantee: "best-effort",
+					Mode:              0600,
+				}},
+			}
+		},
+	})
+	run(t, testCase{
+		desc: "audit sink unsupported type",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		hcl: []string{`
+				audit {
+					enabled = true
+					sink "remote" {
+						type = "syslog"
+						path = "/var/log/audit.json"
+						rotate_duration = "24h"
+					}
+				}
+			`},
+		json: []string{`{
+				"audit": {
+					"enabled": true,
+					"sink": {
+						"remote": {
+							"type": "syslog",
+							"path": "/var/log/audit.json",
+							"rotate_duration": "24h"
+						}
+					}
+				}
+			}`},
+		expectedErr: `audit sink "remote": type must be "file"`,
+	})
+	run(t, testCase{
+		desc: "audit sink requires rotation",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		hcl: []string{`
+				audit {
+					enabled = true
+					sink "file" {
+						path = "/var/log/audit.json"
+					}
+				}
+			`},
+		json: []string{`{
+				"audit": {
+					"enabled": true,
+					"sink": {
+						"file": {
+							"path": "/var/log/audit.json"
+						}
+					}
+				}
+			}`},
+		expectedErr: `audit sink "file": at least one of rotate_bytes or rotate_duration must be set`,
+	})
+
 	// /////////////////////////////////
 	// Auto Config related tests
 	run(t, testCase{
@@ -6488,6 +6651,25 @@ func TestLoad_FullConfig(t *testing.T) {
 				},
 			},
 		},
+		Audit: audit.Config{
+			Enabled:    true,
+			RPCEnabled: true,
+			Include:    []string{"/v1/kv/*", "KVS.*"},
+			Exclude:    []string{"/v1/kv/ignored*"},
+			Sinks: []audit.SinkConfig{
+				{
+					Name:              "pCdaQNRw",
+					Type:              "file",
+					Format:            "json",
+					Path:              "/tmp/audit/Mw9Zt8rq.json",
+					DeliveryGuarantee: "best-effort",
+					Mode:              0640,
+					RotateBytes:       4712,
+					RotateDuration:    18 * time.Hour,
+					RotateMaxFiles:    7,
+				},
+			},
+		},
 		ConnectEnabled:        true,
 		ConnectSidecarMinPort: 8888,
 		ConnectSidecarMaxPort: 9999,
@@ -6502,6 +6684,7 @@ func TestLoad_FullConfig(t *testing.T) {
 			"CSRMaxConcurrent":    float64(2),
 		},
 		ConnectMeshGatewayWANFederationEnabled: false,
+		ConnectSPIFFEBundleProfile:             "https_web",
 		Cloud: hcpconfig.CloudConfig{
 			ResourceID:   "N43DsscE",
 			ClientID:     "6WvsDZCP",
@@ -6571,6 +6754,8 @@ func TestLoad_FullConfig(t *testing.T) {
 		HTTPSHandshakeTimeout: 2391 * time.Millisecond,
 		HTTPSPort:             15127,
 		HTTPUseCache:          false,
+		SPIFFEBundleAddrs:     []net.Addr{tcpAddr("93.83.18.19:6173")},
+		SPIFFEBundlePort:      6173,
 		KVMaxValueSize:        1234567800,
 		LeaveDrainTime:        8265 * time.Second,
 		LeaveOnTerm:           true,
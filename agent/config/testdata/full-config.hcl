@@ -54,6 +54,19 @@ advertise_addr_wan = "78.63.37.19"
 advertise_reconnect_timeout = "0s"
 audit = {
     enabled = true
+    rpc_enabled = true
+    include = ["/v1/kv/*", "KVS.*"]
+    exclude = ["/v1/kv/ignored*"]
+    sink "pCdaQNRw" {
+        type = "file"
+        format = "json"
+        path = "/tmp/audit/Mw9Zt8rq.json"
+        delivery_guarantee = "best-effort"
+        mode = "0640"
+        rotate_bytes = 4712
+        rotate_duration = "18h"
+        rotate_max_files = 7
+    }
 }
 auto_config = {
     enabled = false
@@ -228,6 +241,7 @@ connect {
     }
     enable_mesh_gateway_wan_federation = false
     enabled = true
+    spiffe_bundle_profile = "https_web"
 }
 gossip_lan {
     gossip_nodes    = 6
@@ -344,6 +358,7 @@ ports {
     server = 3757
     grpc = 4881
     grpc_tls = 5201
+    spiffe_bundle = 6173
     proxy_min_port = 2000
     proxy_max_port = 3000
     sidecar_min_port = 8888
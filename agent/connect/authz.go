@@ -4,6 +4,11 @@
 package connect
 
 import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/structs"
 )
@@ -79,3 +84,120 @@ func IntentionMatch(
 	// The name and namespace match, so the destination is covered
 	return true
 }
+
+// AuthorizeIntentionPermissions evaluates the L7 permissions of an intention
+// against an HTTP request. The permissions are evaluated in order and the
+// first one matching the request decides whether it is allowed, like in the
+// RBAC filters generated for Envoy.
+//
+// The return value of `auth` is only valid if the second value `match` is true.
+// If `match` is false, no permission matches the request and the default
+// intention behavior applies.
+//
+// JWT requirements can only be verified by Envoy, so a permission requiring a
+// JWT is assumed to match the requests it denies and to never match the
+// requests it allows.
+func AuthorizeIntentionPermissions(
+	ixn *structs.Intention,
+	req *structs.ConnectAuthorizeHTTPRequest,
+) (bool, bool, error) {
+	for _, perm := range ixn.Permissions {
+		allow := perm.Action == structs.IntentionActionAllow
+		if ixn.JWT != nil || perm.JWT != nil {
+			if !allow {
+				return false, true, nil
+			}
+			continue
+		}
+
+		match, err := intentionHTTPPermissionMatch(perm.HTTP, req)
+		if err != nil {
+			return false, false, err
+		}
+		if match {
+			return allow, true, nil
+		}
+	}
+	return false, false, nil
+}
+
+// intentionHTTPPermissionMatch returns whether the request matches all of the
+// criteria of the permission.
+func intentionHTTPPermissionMatch(perm *structs.IntentionHTTPPermission, req *structs.ConnectAuthorizeHTTPRequest) (bool, error) {
+	if perm == nil {
+		return true, nil
+	}
+
+	switch {
+	case perm.PathExact != "":
+		if req.Path != perm.PathExact {
+			return false, nil
+		}
+	case perm.PathPrefix != "":
+		if !strings.HasPrefix(req.Path, perm.PathPrefix) {
+			return false, nil
+		}
+	case perm.PathRegex != "":
+		match, err := fullRegexMatch(perm.PathRegex, req.Path)
+		if err != nil || !match {
+			return false, err
+		}
+	}
+
+	for _, hdr := range perm.Header {
+		values, present := req.Header[http.CanonicalHeaderKey(hdr.Name)]
+		// Envoy matches the values of repeated headers joined with commas.
+		value := strings.Join(values, ",")
+
+		var match bool
+		switch {
+		case hdr.Exact != "":
+			match = present && value == hdr.Exact
+		case hdr.Regex != "":
+			var err error
+			if present {
+				match, err = fullRegexMatch(hdr.Regex, value)
+				if err != nil {
+					return false, err
+				}
+			}
+		case hdr.Prefix != "":
+			match = present && strings.HasPrefix(value, hdr.Prefix)
+		case hdr.Suffix != "":
+			match = present && strings.HasSuffix(value, hdr.Suffix)
+		case hdr.Present:
+			match = present
+		default:
+			continue // skip this impossible situation
+		}
+
+		if match == hdr.Invert {
+			return false, nil
+		}
+	}
+
+	if len(perm.Methods) > 0 {
+		var match bool
+		for _, method := range perm.Methods {
+			if method == req.Method {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// fullRegexMatch returns whether the regex matches the whole value, as the
+// regexes of the permissions are matched by Envoy.
+func fullRegexMatch(expr, value string) (bool, error) {
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return false, fmt.Errorf("invalid regex %q: %w", expr, err)
+	}
+	return re.MatchString(value), nil
+}
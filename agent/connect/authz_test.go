@@ -6,6 +6,7 @@ package connect
 import (
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"testing"
 )
 
@@ -184,3 +185,105 @@ func TestAuthorizeIntentionTarget(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthorizeIntentionPermissions(t *testing.T) {
+	perm := func(action structs.IntentionAction, http *structs.IntentionHTTPPermission) *structs.IntentionPermission {
+		return &structs.IntentionPermission{Action: action, HTTP: http}
+	}
+	req := &structs.ConnectAuthorizeHTTPRequest{
+		Method: "GET",
+		Path:   "/api/users",
+		Header: map[string][]string{
+			"X-Tenant": {"blue", "green"},
+		},
+	}
+
+	cases := []struct {
+		name        string
+		permissions []*structs.IntentionPermission
+		jwt         bool
+		auth        bool
+		match       bool
+	}{
+		{
+			name: "no match",
+			permissions: []*structs.IntentionPermission{
+				perm(structs.IntentionActionAllow, &structs.IntentionHTTPPermission{PathExact: "/api"}),
+				perm(structs.IntentionActionAllow, &structs.IntentionHTTPPermission{Methods: []string{"POST", "PUT"}}),
+			},
+		},
+		{
+			name: "first match wins",
+			permissions: []*structs.IntentionPermission{
+				perm(structs.IntentionActionDeny, &structs.IntentionHTTPPermission{PathPrefix: "/api/"}),
+				perm(structs.IntentionActionAllow, &structs.IntentionHTTPPermission{PathExact: "/api/users"}),
+			},
+			auth:  false,
+			match: true,
+		},
+		{
+			name: "regex must match the whole path",
+			permissions: []*structs.IntentionPermission{
+				perm(structs.IntentionActionDeny, &structs.IntentionHTTPPermission{PathRegex: "/api"}),
+				perm(structs.IntentionActionAllow, &structs.IntentionHTTPPermission{PathRegex: "/api/[a-z]+"}),
+			},
+			auth:  true,
+			match: true,
+		},
+		{
+			name: "header values are joined",
+			permissions: []*structs.IntentionPermission{
+				perm(structs.IntentionActionAllow, &structs.IntentionHTTPPermission{
+					Header: []structs.IntentionHTTPHeaderPermission{
+						{Name: "x-tenant", Exact: "blue,green"},
+						{Name: "X-Missing", Present: true, Invert: true},
+					},
+					Methods: []string{"GET"},
+				}),
+			},
+			auth:  true,
+			match: true,
+		},
+		{
+			name: "inverted header",
+			permissions: []*structs.IntentionPermission{
+				perm(structs.IntentionActionAllow, &structs.IntentionHTTPPermission{
+					Header: []structs.IntentionHTTPHeaderPermission{
+						{Name: "X-Tenant", Prefix: "blue", Invert: true},
+					},
+				}),
+			},
+		},
+		{
+			name: "jwt requirements only match denials",
+			permissions: []*structs.IntentionPermission{
+				perm(structs.IntentionActionAllow, nil),
+				perm(structs.IntentionActionDeny, nil),
+			},
+			jwt:   true,
+			auth:  false,
+			match: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ixn := &structs.Intention{Permissions: tc.permissions}
+			if tc.jwt {
+				ixn.JWT = &structs.IntentionJWTRequirement{}
+			}
+
+			auth, match, err := AuthorizeIntentionPermissions(ixn, req)
+			require.NoError(t, err)
+			assert.Equal(t, tc.auth, auth)
+			assert.Equal(t, tc.match, match)
+		})
+	}
+
+	_, _, err := AuthorizeIntentionPermissions(&structs.Intention{
+		Permissions: []*structs.IntentionPermission{
+			perm(structs.IntentionActionAllow, &structs.IntentionHTTPPermission{PathRegex: "("}),
+		},
+	}, req)
+	require.Error(t, err)
+}
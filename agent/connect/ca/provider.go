@@ -6,6 +6,9 @@ package ca
 import (
 	"crypto/x509"
 	"errors"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
 )
 
 //go:generate mockery --name Provider --inpackage
@@ -29,6 +32,41 @@ type PrimaryUsesIntermediate interface {
 	GenerateLeafSigningCert() (string, error)
 }
 
+// PrimaryUsesOfflineRoot is an optional interface that CA providers may
+// implement to indicate that the root private key is kept outside of Consul.
+// Such providers sign leaf certificates with an intermediate in the primary
+// datacenter, but cannot generate it themselves: the CSR returned by
+// SecondaryProvider.GenerateIntermediateCSR is signed by an operator and the
+// resulting certificate is given back with SecondaryProvider.SetIntermediate.
+type PrimaryUsesOfflineRoot interface {
+	// IntermediatePending returns true when a CSR was generated for which no
+	// intermediate has been set yet.
+	IntermediatePending() (bool, error)
+}
+
+// RevocationSigner is an optional interface that CA providers may implement to
+// sign certificate revocation lists and OCSP responses for the leaf
+// certificates they signed. Both are signed with the key of the active leaf
+// signing certificate.
+type RevocationSigner interface {
+	// SignRevocationList returns the PEM-encoded CRL created from the given
+	// template.
+	SignRevocationList(template *x509.RevocationList) (string, error)
+
+	// SignOCSPResponse returns the DER-encoded OCSP response created from the
+	// given template.
+	SignOCSPResponse(template ocsp.Response) ([]byte, error)
+}
+
+// LeafTTLSigner is an optional interface that CA providers may implement to
+// sign leaf certificates with a TTL other than the LeafCertTTL of their
+// configuration, for the services that override it.
+type LeafTTLSigner interface {
+	// SignWithTTL is the same as Provider.Sign but the certificate is valid
+	// for the given TTL.
+	SignWithTTL(csr *x509.CertificateRequest, ttl time.Duration) (string, error)
+}
+
 // ProviderConfig encapsulates all the data Consul passes to `Configure` on a
 // new provider instance. The provider must treat this as read-only and make
 // copies of any map or slice if it might modify them internally.
@@ -41,6 +41,10 @@ const (
 	// create.
 	AWSIntermediateTTL = 1 * 365 * 24 * time.Hour
 
+	// AWSMinLeafCertTTL is the shortest validity ACM Private CA is able to
+	// issue leaf certificates for.
+	AWSMinLeafCertTTL = 24 * time.Hour
+
 	// SignTimout is the maximum time we will spend waiting (polling) for a leaf
 	// certificate to be signed.
 	AWSSignTimeout = 45 * time.Second
@@ -78,7 +82,10 @@ type AWSProvider struct {
 	logger          hclog.Logger
 }
 
-var _ Provider = (*AWSProvider)(nil)
+var (
+	_ Provider      = (*AWSProvider)(nil)
+	_ LeafTTLSigner = (*AWSProvider)(nil)
+)
 
 // NewAWSProvider returns a new AWSProvider
 func NewAWSProvider(logger hclog.Logger) *AWSProvider {
@@ -576,6 +583,17 @@ func (a *AWSProvider) ActiveLeafSigningCert() (string, error) {
 
 // Sign implements Provider
 func (a *AWSProvider) Sign(csr *x509.CertificateRequest) (string, error) {
+	return a.SignWithTTL(csr, a.config.LeafCertTTL)
+}
+
+// SignWithTTL implements LeafTTLSigner. ACM Private CA cannot issue
+// certificates for less than AWSMinLeafCertTTL so shorter TTLs are rejected.
+func (a *AWSProvider) SignWithTTL(csr *x509.CertificateRequest, ttl time.Duration) (string, error) {
+	if ttl < AWSMinLeafCertTTL {
+		return "", fmt.Errorf("AWS PCA doesn't support certificates that are valid"+
+			" for less than 24 hours, LeafTTL of %s requested", ttl)
+	}
+
 	connect.HackSANExtensionForCSR(csr)
 
 	if a.rootPEM == "" {
@@ -586,7 +604,7 @@ func (a *AWSProvider) Sign(csr *x509.CertificateRequest) (string, error) {
 		"requester", csr.Subject.CommonName,
 	)
 
-	return a.signCSRRaw(csr, LeafTemplateARN, a.config.LeafCertTTL)
+	return a.signCSRRaw(csr, LeafTemplateARN, ttl)
 }
 
 // SignIntermediate implements Provider
@@ -713,7 +731,7 @@ func ParseAWSCAConfig(raw map[string]interface{}) (*structs.AWSCAProviderConfig,
 		return nil, err
 	}
 
-	if config.LeafCertTTL < 24*time.Hour {
+	if config.LeafCertTTL < AWSMinLeafCertTTL {
 		return nil, fmt.Errorf("AWS PCA doesn't support certificates that are valid"+
 			" for less than 24 hours, LeafTTL of %s configured", config.LeafCertTTL)
 	}
@@ -5,6 +5,7 @@ package ca
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/rand"
 	"crypto/sha256"
 	"crypto/x509"
@@ -18,6 +19,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-hclog"
+	"golang.org/x/crypto/ocsp"
 
 	"github.com/hashicorp/consul/agent/connect"
 	"github.com/hashicorp/consul/agent/structs"
@@ -52,7 +54,11 @@ type ConsulProvider struct {
 	sync.RWMutex
 }
 
-var _ Provider = (*ConsulProvider)(nil)
+var (
+	_ Provider         = (*ConsulProvider)(nil)
+	_ RevocationSigner = (*ConsulProvider)(nil)
+	_ LeafTTLSigner    = (*ConsulProvider)(nil)
+)
 
 // NewConsulProvider returns a new ConsulProvider that is ready to be used.
 func NewConsulProvider(delegate ConsulProviderStateDelegate, logger hclog.Logger) *ConsulProvider {
@@ -312,6 +318,12 @@ func (c *ConsulProvider) Cleanup(_ bool, _ map[string]interface{}) error {
 // Sign returns a new certificate valid for the given SpiffeIDService
 // using the current CA.
 func (c *ConsulProvider) Sign(csr *x509.CertificateRequest) (string, error) {
+	return c.SignWithTTL(csr, c.config.LeafCertTTL)
+}
+
+// SignWithTTL is the same as Sign but the certificate is valid for the given
+// TTL instead of the LeafCertTTL of the configuration.
+func (c *ConsulProvider) SignWithTTL(csr *x509.CertificateRequest, ttl time.Duration) (string, error) {
 	connect.HackSANExtensionForCSR(csr)
 
 	// Lock during the signing so we don't use the same index twice
@@ -388,7 +400,7 @@ func (c *ConsulProvider) Sign(csr *x509.CertificateRequest) (string, error) {
 			x509.ExtKeyUsageClientAuth,
 			x509.ExtKeyUsageServerAuth,
 		},
-		NotAfter:       effectiveNow.Add(c.config.LeafCertTTL),
+		NotAfter:       effectiveNow.Add(ttl),
 		NotBefore:      effectiveNow,
 		AuthorityKeyId: keyId,
 		SubjectKeyId:   subjectKeyID,
@@ -494,6 +506,70 @@ func (c *ConsulProvider) SignIntermediate(csr *x509.CertificateRequest) (string,
 	return buf.String(), nil
 }
 
+// SignRevocationList implements RevocationSigner.
+func (c *ConsulProvider) SignRevocationList(template *x509.RevocationList) (string, error) {
+	signer, caCert, err := c.leafSigner()
+	if err != nil {
+		return "", err
+	}
+
+	bs, err := x509.CreateRevocationList(rand.Reader, template, caCert, signer)
+	if err != nil {
+		return "", fmt.Errorf("error generating revocation list: %s", err)
+	}
+
+	var buf bytes.Buffer
+	if err := pem.Encode(&buf, &pem.Block{Type: "X509 CRL", Bytes: bs}); err != nil {
+		return "", fmt.Errorf("error encoding revocation list: %s", err)
+	}
+	return buf.String(), nil
+}
+
+// SignOCSPResponse implements RevocationSigner. The response is signed
+// directly by the leaf signing certificate.
+func (c *ConsulProvider) SignOCSPResponse(template ocsp.Response) ([]byte, error) {
+	signer, caCert, err := c.leafSigner()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ocsp.CreateResponse(caCert, caCert, template, signer)
+	if err != nil {
+		return nil, fmt.Errorf("error generating OCSP response: %s", err)
+	}
+	return resp, nil
+}
+
+// leafSigner returns the private key and the certificate used to sign leaf
+// certificates.
+func (c *ConsulProvider) leafSigner() (crypto.Signer, *x509.Certificate, error) {
+	providerState, err := c.getState()
+	if err != nil {
+		return nil, nil, err
+	}
+	if providerState.PrivateKey == "" {
+		return nil, nil, ErrNotInitialized
+	}
+
+	signer, err := connect.ParseSigner(providerState.PrivateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM, err := c.ActiveLeafSigningCert()
+	if err != nil {
+		return nil, nil, err
+	}
+	if certPEM == "" {
+		return nil, nil, ErrNotInitialized
+	}
+	caCert, err := connect.ParseCert(certPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing CA cert: %s", err)
+	}
+	return signer, caCert, nil
+}
+
 // CrossSignCA returns the given CA cert signed by the current active root.
 func (c *ConsulProvider) CrossSignCA(cert *x509.Certificate) (string, error) {
 	c.Lock()
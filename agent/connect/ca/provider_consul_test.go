@@ -5,11 +5,15 @@ package ca
 
 import (
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
 
 	"github.com/hashicorp/consul/agent/connect"
 	"github.com/hashicorp/consul/agent/consul/fsm"
@@ -537,3 +541,84 @@ func TestConsulCAProvider_MigrateOldID(t *testing.T) {
 		})
 	}
 }
+
+func TestConsulProvider_RevocationSigner(t *testing.T) {
+	t.Parallel()
+
+	conf := testConsulCAConfig()
+	delegate := newMockDelegate(t, conf)
+	provider := TestConsulProvider(t, delegate)
+	require.NoError(t, provider.Configure(testProviderConfig(conf)))
+	rootPEM, err := provider.GenerateCAChain()
+	require.NoError(t, err)
+	root, err := connect.ParseCert(rootPEM)
+	require.NoError(t, err)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	serial := big.NewInt(42)
+
+	t.Run("revocation list", func(t *testing.T) {
+		crlPEM, err := provider.SignRevocationList(&x509.RevocationList{
+			Number:     big.NewInt(1),
+			ThisUpdate: now,
+			NextUpdate: now.Add(time.Hour),
+			RevokedCertificates: []pkix.RevokedCertificate{
+				{SerialNumber: serial, RevocationTime: now},
+			},
+		})
+		require.NoError(t, err)
+
+		block, _ := pem.Decode([]byte(crlPEM))
+		require.NotNil(t, block)
+		require.Equal(t, "X509 CRL", block.Type)
+		crl, err := x509.ParseRevocationList(block.Bytes)
+		require.NoError(t, err)
+		require.NoError(t, crl.CheckSignatureFrom(root))
+		require.Len(t, crl.RevokedCertificates, 1)
+		require.Equal(t, serial, crl.RevokedCertificates[0].SerialNumber)
+	})
+
+	t.Run("ocsp response", func(t *testing.T) {
+		der, err := provider.SignOCSPResponse(ocsp.Response{
+			Status:       ocsp.Revoked,
+			SerialNumber: serial,
+			ThisUpdate:   now,
+			NextUpdate:   now.Add(time.Hour),
+			RevokedAt:    now,
+		})
+		require.NoError(t, err)
+
+		resp, err := ocsp.ParseResponse(der, root)
+		require.NoError(t, err)
+		require.Equal(t, ocsp.Revoked, resp.Status)
+		require.Equal(t, serial, resp.SerialNumber)
+	})
+}
+
+func TestConsulProvider_SignWithTTL(t *testing.T) {
+	t.Parallel()
+
+	conf := testConsulCAConfig()
+	conf.Config["LeafCertTTL"] = "72h"
+	delegate := newMockDelegate(t, conf)
+	provider := TestConsulProvider(t, delegate)
+	require.NoError(t, provider.Configure(testProviderConfig(conf)))
+	_, err := provider.GenerateCAChain()
+	require.NoError(t, err)
+
+	spiffeService := &connect.SpiffeIDService{
+		Host:       connect.TestClusterID + ".consul",
+		Namespace:  "default",
+		Datacenter: "dc1",
+		Service:    "foo",
+	}
+	raw, _ := connect.TestCSR(t, spiffeService)
+	csr, err := connect.ParseCSR(raw)
+	require.NoError(t, err)
+
+	cert, err := provider.SignWithTTL(csr, 2*time.Hour)
+	require.NoError(t, err)
+	parsed, err := connect.ParseCert(cert)
+	require.NoError(t, err)
+	require.Equal(t, 2*time.Hour, parsed.NotAfter.Sub(parsed.NotBefore))
+}
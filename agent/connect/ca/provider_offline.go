@@ -0,0 +1,301 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package ca
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/lib"
+)
+
+// ErrNoOfflineIntermediate is returned by the offline provider when it is asked
+// to sign a certificate before an intermediate signed by the offline root has
+// been set.
+var ErrNoOfflineIntermediate = errors.New("no intermediate certificate has been set for the offline CA provider; " +
+	"sign the CSR from 'consul connect ca csr' with the offline root and upload it with 'consul connect ca set-intermediate'")
+
+// OfflineProvider is a CA provider for a root CA whose private key never
+// touches Consul. Leaf certificates are signed by an intermediate whose key is
+// generated and kept by Consul like the built-in provider's keys, but whose
+// certificate is signed by an operator with the offline root and uploaded
+// with SetIntermediate.
+//
+// The embedded ConsulProvider is configured as a non-primary provider so that
+// it signs leaf certificates with the intermediate stored in the provider
+// state.
+type OfflineProvider struct {
+	*ConsulProvider
+
+	rootCert string
+}
+
+var (
+	_ Provider               = (*OfflineProvider)(nil)
+	_ PrimaryUsesOfflineRoot = (*OfflineProvider)(nil)
+	_ RevocationSigner       = (*OfflineProvider)(nil)
+	_ LeafTTLSigner          = (*OfflineProvider)(nil)
+)
+
+// NewOfflineProvider returns a new OfflineProvider that is ready to be used.
+func NewOfflineProvider(delegate ConsulProviderStateDelegate, logger hclog.Logger) *OfflineProvider {
+	return &OfflineProvider{ConsulProvider: NewConsulProvider(delegate, logger)}
+}
+
+// ParseOfflineCAConfig parses and validates the offline provider config.
+func ParseOfflineCAConfig(raw map[string]interface{}) (*structs.OfflineCAProviderConfig, error) {
+	config := structs.OfflineCAProviderConfig{
+		CommonCAProviderConfig: defaultCommonConfig(),
+	}
+	decodeConf := &mapstructure.DecoderConfig{
+		DecodeHook:       structs.ParseDurationFunc(),
+		Result:           &config,
+		WeaklyTypedInput: true,
+	}
+
+	decoder, err := mapstructure.NewDecoder(decodeConf)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := decoder.Decode(raw); err != nil {
+		return nil, fmt.Errorf("error decoding config: %s", err)
+	}
+
+	if config.RootCert == "" {
+		return nil, fmt.Errorf("must provide the certificate of the offline root CA")
+	}
+	root, err := connect.ParseCert(config.RootCert)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing root cert: %v", err)
+	}
+	if !root.IsCA {
+		return nil, fmt.Errorf("root cert is not a CA certificate")
+	}
+	if root.NotAfter.Before(time.Now()) {
+		return nil, fmt.Errorf("root cert expired on %s", root.NotAfter)
+	}
+
+	if err := config.CommonCAProviderConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// Configure sets up the provider using the given configuration.
+func (o *OfflineProvider) Configure(cfg ProviderConfig) error {
+	if !cfg.IsPrimary {
+		return fmt.Errorf("the offline CA provider can only be used in the primary datacenter")
+	}
+
+	config, err := ParseOfflineCAConfig(cfg.RawConfig)
+	if err != nil {
+		return err
+	}
+
+	c := o.ConsulProvider
+	c.config = &structs.ConsulCAProviderConfig{CommonCAProviderConfig: config.CommonCAProviderConfig}
+	// The ID only depends on the root so that changing any other setting keeps
+	// the intermediate that was uploaded for it.
+	c.id = hexStringHash("offline," + config.RootCert)
+	c.clusterID = cfg.ClusterID
+	c.isPrimary = false
+	c.spiffeID = connect.SpiffeIDSigningForCluster(c.clusterID)
+	o.rootCert = lib.EnsureTrailingNewline(config.RootCert)
+
+	providerState, err := c.Delegate.ProviderState(c.id)
+	if err != nil {
+		return err
+	}
+	if providerState != nil {
+		return nil
+	}
+
+	args := &structs.CARequest{
+		Op:            structs.CAOpSetProviderState,
+		ProviderState: &structs.CAConsulProviderState{ID: c.id, RootCert: o.rootCert},
+	}
+	if _, err := c.Delegate.ApplyCARequest(args); err != nil {
+		return err
+	}
+
+	c.logger.Debug("offline CA provider configured", "id", c.id)
+
+	return nil
+}
+
+// State implements Provider. The offline provider keeps its state in the same
+// table as the built-in provider, so there is nothing to persist here.
+func (o *OfflineProvider) State() (map[string]string, error) {
+	return nil, nil
+}
+
+// GenerateCAChain returns the certificate of the offline root.
+func (o *OfflineProvider) GenerateCAChain() (string, error) {
+	return o.rootCert, nil
+}
+
+// GenerateIntermediateCSR returns the CSR for the next intermediate. A new
+// private key is only generated when no CSR is pending, so the same CSR is
+// returned until a certificate signed for it is set with SetIntermediate.
+func (o *OfflineProvider) GenerateIntermediateCSR() (string, string, error) {
+	c := o.ConsulProvider
+	c.Lock()
+	defer c.Unlock()
+
+	providerState, err := c.getState()
+	if err != nil {
+		return "", "", err
+	}
+	if providerState.PendingCSR != "" {
+		return providerState.PendingCSR, "", nil
+	}
+
+	signer, pk, err := connect.GeneratePrivateKeyWithConfig(c.config.PrivateKeyType, c.config.PrivateKeyBits)
+	if err != nil {
+		return "", "", err
+	}
+
+	csr, err := connect.CreateCACSR(c.spiffeID, signer)
+	if err != nil {
+		return "", "", err
+	}
+
+	newState := *providerState
+	newState.PendingPrivateKey = pk
+	newState.PendingCSR = csr
+	args := &structs.CARequest{
+		Op:            structs.CAOpSetProviderState,
+		ProviderState: &newState,
+	}
+	if _, err := c.Delegate.ApplyCARequest(args); err != nil {
+		return "", "", err
+	}
+
+	c.logger.Info("generated a new intermediate CSR to be signed by the offline root")
+
+	return csr, "", nil
+}
+
+// SetIntermediate validates that the given intermediate was signed by the
+// offline root for the pending CSR and makes it the active leaf signing
+// certificate. The intermediatePEM may be followed by the certificates needed
+// to chain it back to rootPEM.
+func (o *OfflineProvider) SetIntermediate(intermediatePEM, rootPEM, _ string) error {
+	c := o.ConsulProvider
+	c.Lock()
+	defer c.Unlock()
+
+	providerState, err := c.getState()
+	if err != nil {
+		return err
+	}
+	if providerState.PendingPrivateKey == "" {
+		return fmt.Errorf("no intermediate CSR is pending; generate one with 'consul connect ca csr' first")
+	}
+
+	if err := validateOfflineIntermediate(intermediatePEM, rootPEM, c.spiffeID); err != nil {
+		return err
+	}
+	if err := validateIntermediateSignedByPrivateKey(intermediatePEM, providerState.PendingPrivateKey); err != nil {
+		return err
+	}
+
+	newState := *providerState
+	newState.PrivateKey = providerState.PendingPrivateKey
+	newState.IntermediateCert = intermediatePEM
+	newState.PendingPrivateKey = ""
+	newState.PendingCSR = ""
+	args := &structs.CARequest{
+		Op:            structs.CAOpSetProviderState,
+		ProviderState: &newState,
+	}
+	if _, err := c.Delegate.ApplyCARequest(args); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Sign returns a new certificate valid for the given SpiffeIDService signed by
+// the uploaded intermediate.
+func (o *OfflineProvider) Sign(csr *x509.CertificateRequest) (string, error) {
+	return o.SignWithTTL(csr, o.ConsulProvider.config.LeafCertTTL)
+}
+
+// SignWithTTL is the same as Sign but the certificate is valid for the given
+// TTL.
+func (o *OfflineProvider) SignWithTTL(csr *x509.CertificateRequest, ttl time.Duration) (string, error) {
+	providerState, err := o.ConsulProvider.getState()
+	if err != nil {
+		return "", err
+	}
+	if providerState.IntermediateCert == "" {
+		return "", ErrNoOfflineIntermediate
+	}
+	return o.ConsulProvider.SignWithTTL(csr, ttl)
+}
+
+// SignIntermediate is not supported because the intermediate of the primary
+// datacenter cannot sign further CA certificates.
+func (o *OfflineProvider) SignIntermediate(*x509.CertificateRequest) (string, error) {
+	return "", fmt.Errorf("the offline CA provider cannot sign intermediates for secondary datacenters")
+}
+
+// CrossSignCA is not supported because the root private key is not available.
+func (o *OfflineProvider) CrossSignCA(*x509.Certificate) (string, error) {
+	return "", fmt.Errorf("the offline CA provider does not support cross-signing")
+}
+
+// SupportsCrossSigning implements Provider
+func (o *OfflineProvider) SupportsCrossSigning() (bool, error) {
+	return false, nil
+}
+
+// IntermediatePending implements PrimaryUsesOfflineRoot.
+func (o *OfflineProvider) IntermediatePending() (bool, error) {
+	providerState, err := o.ConsulProvider.getState()
+	if err != nil {
+		return false, err
+	}
+	return providerState.PendingCSR != "", nil
+}
+
+// validateOfflineIntermediate checks that the first certificate of the bundle
+// is an intermediate CA for our trust domain and that it chains back to the
+// root using the other certificates of the bundle.
+func validateOfflineIntermediate(intermediatePEM, rootPEM string, spiffeID *connect.SpiffeIDSigning) error {
+	intermediate, chain, err := connect.ParseIntermediateCerts(intermediatePEM)
+	if err != nil {
+		return fmt.Errorf("error parsing intermediate PEM: %v", err)
+	}
+
+	if uriCount := len(intermediate.URIs); uriCount != 1 {
+		return fmt.Errorf("incoming intermediate cert has unexpected number of URIs: %d", uriCount)
+	}
+	if got, want := intermediate.URIs[0].String(), spiffeID.URI().String(); got != want {
+		return fmt.Errorf("incoming cert URI %q does not match current URI: %q", got, want)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM([]byte(rootPEM))
+	_, err = intermediate.Verify(x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: chain,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return fmt.Errorf("could not verify intermediate cert against root: %v", err)
+	}
+
+	return nil
+}
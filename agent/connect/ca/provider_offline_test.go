@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package ca
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// testOfflineRoot returns a built-in provider acting as the offline root the
+// operator signs intermediates with, and the PEM of its root certificate.
+func testOfflineRoot(t *testing.T) (*ConsulProvider, string) {
+	conf := testConsulCAConfig()
+	delegate := newMockDelegate(t, conf)
+	root := TestConsulProvider(t, delegate)
+	require.NoError(t, root.Configure(testProviderConfig(conf)))
+	rootPEM, err := root.GenerateCAChain()
+	require.NoError(t, err)
+	return root, rootPEM
+}
+
+func testOfflineProvider(t *testing.T, rootPEM string) *OfflineProvider {
+	conf := &structs.CAConfiguration{
+		ClusterID: connect.TestClusterID,
+		Provider:  structs.OfflineCAProvider,
+		Config: map[string]interface{}{
+			"RootCert":    rootPEM,
+			"LeafCertTTL": "1h",
+		},
+	}
+	conf.CreateIndex = 10
+	provider := NewOfflineProvider(newMockDelegate(t, conf), hclog.New(nil))
+	require.NoError(t, provider.Configure(testProviderConfig(conf)))
+	return provider
+}
+
+func TestParseOfflineCAConfig(t *testing.T) {
+	_, rootPEM := testOfflineRoot(t)
+
+	_, err := ParseOfflineCAConfig(map[string]interface{}{})
+	require.ErrorContains(t, err, "must provide the certificate of the offline root CA")
+
+	_, err = ParseOfflineCAConfig(map[string]interface{}{"RootCert": "not a cert"})
+	require.ErrorContains(t, err, "error parsing root cert")
+
+	leaf, _ := connect.TestLeaf(t, "web", connect.TestCA(t, nil))
+	_, err = ParseOfflineCAConfig(map[string]interface{}{"RootCert": leaf})
+	require.ErrorContains(t, err, "root cert is not a CA certificate")
+
+	config, err := ParseOfflineCAConfig(map[string]interface{}{"RootCert": rootPEM})
+	require.NoError(t, err)
+	require.Equal(t, rootPEM, config.RootCert)
+}
+
+func TestOfflineProvider(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	root, rootPEM := testOfflineRoot(t)
+	provider := testOfflineProvider(t, rootPEM)
+
+	chain, err := provider.GenerateCAChain()
+	require.NoError(t, err)
+	require.Equal(t, rootPEM, chain)
+
+	supported, err := provider.SupportsCrossSigning()
+	require.NoError(t, err)
+	require.False(t, supported)
+
+	spiffeService := &connect.SpiffeIDService{
+		Host:       connect.TestClusterID + ".consul",
+		Namespace:  "default",
+		Datacenter: "dc1",
+		Service:    "foo",
+	}
+	raw, _ := connect.TestCSR(t, spiffeService)
+	leafCSR, err := connect.ParseCSR(raw)
+	require.NoError(t, err)
+
+	// Nothing can be signed before an intermediate is set.
+	_, err = provider.Sign(leafCSR)
+	require.ErrorIs(t, err, ErrNoOfflineIntermediate)
+	active, err := provider.ActiveLeafSigningCert()
+	require.NoError(t, err)
+	require.Empty(t, active)
+	require.ErrorContains(t, provider.SetIntermediate(rootPEM, rootPEM, ""), "no intermediate CSR is pending")
+
+	// The same CSR is returned until an intermediate is set.
+	csrPEM, _, err := provider.GenerateIntermediateCSR()
+	require.NoError(t, err)
+	again, _, err := provider.GenerateIntermediateCSR()
+	require.NoError(t, err)
+	require.Equal(t, csrPEM, again)
+	pending, err := provider.IntermediatePending()
+	require.NoError(t, err)
+	require.True(t, pending)
+
+	csr, err := connect.ParseCSR(csrPEM)
+	require.NoError(t, err)
+	intermediatePEM, err := root.SignIntermediate(csr)
+	require.NoError(t, err)
+
+	t.Run("wrong root", func(t *testing.T) {
+		_, otherRootPEM := testOfflineRoot(t)
+		err := provider.SetIntermediate(intermediatePEM, otherRootPEM, "")
+		require.ErrorContains(t, err, "could not verify intermediate cert against root")
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		other := testOfflineProvider(t, rootPEM)
+		otherCSRPEM, _, err := other.GenerateIntermediateCSR()
+		require.NoError(t, err)
+		otherCSR, err := connect.ParseCSR(otherCSRPEM)
+		require.NoError(t, err)
+		otherIntermediate, err := root.SignIntermediate(otherCSR)
+		require.NoError(t, err)
+
+		err = provider.SetIntermediate(otherIntermediate, rootPEM, "")
+		require.ErrorContains(t, err, "intermediate cert is for a different private key")
+	})
+
+	require.NoError(t, provider.SetIntermediate(intermediatePEM, rootPEM, ""))
+
+	active, err = provider.ActiveLeafSigningCert()
+	require.NoError(t, err)
+	require.Equal(t, intermediatePEM, active)
+	pending, err = provider.IntermediatePending()
+	require.NoError(t, err)
+	require.False(t, pending)
+
+	leafPEM, err := provider.Sign(leafCSR)
+	require.NoError(t, err)
+	leaf, err := connect.ParseCert(leafPEM)
+	require.NoError(t, err)
+	require.Equal(t, spiffeService.URI(), leaf.URIs[0])
+
+	intermediate, err := connect.ParseCert(intermediatePEM)
+	require.NoError(t, err)
+	require.Equal(t, intermediate.SubjectKeyId, leaf.AuthorityKeyId)
+
+	// A new CSR uses a new key while the current intermediate keeps signing.
+	next, _, err := provider.GenerateIntermediateCSR()
+	require.NoError(t, err)
+	require.NotEqual(t, csrPEM, next)
+	_, err = provider.Sign(leafCSR)
+	require.NoError(t, err)
+}
+
+func TestOfflineProvider_SecondaryNotSupported(t *testing.T) {
+	_, rootPEM := testOfflineRoot(t)
+
+	conf := &structs.CAConfiguration{
+		ClusterID: connect.TestClusterID,
+		Provider:  structs.OfflineCAProvider,
+		Config:    map[string]interface{}{"RootCert": rootPEM},
+	}
+	provider := NewOfflineProvider(newMockDelegate(t, conf), hclog.New(nil))
+	cfg := testProviderConfig(conf)
+	cfg.IsPrimary = false
+	require.ErrorContains(t, provider.Configure(cfg), "can only be used in the primary datacenter")
+}
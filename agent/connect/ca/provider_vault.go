@@ -75,7 +75,10 @@ type VaultProvider struct {
 	isConsulMountedIntermediate bool
 }
 
-var _ Provider = (*VaultProvider)(nil)
+var (
+	_ Provider      = (*VaultProvider)(nil)
+	_ LeafTTLSigner = (*VaultProvider)(nil)
+)
 
 func NewVaultProvider(logger hclog.Logger) *VaultProvider {
 	return &VaultProvider{
@@ -419,7 +422,7 @@ func (v *VaultProvider) setupIntermediatePKIPath() error {
 		"allow_any_name":   true,
 		"allowed_uri_sans": "spiffe://*",
 		"key_type":         "any",
-		"max_ttl":          v.config.LeafCertTTL.String(),
+		"max_ttl":          v.leafCertMaxTTL().String(),
 		"no_store":         true,
 		"require_cn":       false,
 	})
@@ -702,10 +705,25 @@ func (v *VaultProvider) setDefaultIntermediateIssuer(vaultResp *vaultapi.Secret,
 	return nil
 }
 
+// leafCertMaxTTL returns the max TTL of the role that issues the leaf
+// certificates, which must allow the TTL overrides of the services.
+func (v *VaultProvider) leafCertMaxTTL() time.Duration {
+	if v.config.MaxLeafCertTTL > v.config.LeafCertTTL {
+		return v.config.MaxLeafCertTTL
+	}
+	return v.config.LeafCertTTL
+}
+
 // Sign calls the configured role in the intermediate PKI backend to issue
 // a new leaf certificate based on the provided CSR, with the issuing
 // intermediate CA cert attached.
 func (v *VaultProvider) Sign(csr *x509.CertificateRequest) (string, error) {
+	return v.SignWithTTL(csr, v.config.LeafCertTTL)
+}
+
+// SignWithTTL is the same as Sign but requests a certificate valid for the
+// given TTL.
+func (v *VaultProvider) SignWithTTL(csr *x509.CertificateRequest, ttl time.Duration) (string, error) {
 	connect.HackSANExtensionForCSR(csr)
 
 	var pemBuf bytes.Buffer
@@ -716,7 +734,7 @@ func (v *VaultProvider) Sign(csr *x509.CertificateRequest) (string, error) {
 	// Use the leaf cert role to sign a new cert for this CSR.
 	response, err := v.writeNamespaced(v.config.IntermediatePKINamespace, v.config.IntermediatePKIPath+"sign/"+VaultCALeafCertRole, map[string]interface{}{
 		"csr": pemBuf.String(),
-		"ttl": v.config.LeafCertTTL.String(),
+		"ttl": ttl.String(),
 	})
 	if err != nil {
 		return "", fmt.Errorf("error issuing cert: %v", err)
@@ -59,6 +59,33 @@ func ParseLeafCerts(pemValue string) (*x509.Certificate, *x509.CertPool, error)
 	return leaf, intermediates, nil
 }
 
+// ParseIntermediateCerts parses all of the x509 certificates from a PEM-encoded
+// value under the assumption that the first cert is an intermediate CA cert and
+// the rest are the CA certs needed to chain it back to a root.
+//
+// If no certificates are found this returns an error.
+func ParseIntermediateCerts(pemValue string) (*x509.Certificate, *x509.CertPool, error) {
+	certs, err := parseCerts(pemValue)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	intermediate := certs[0]
+	if !intermediate.IsCA {
+		return nil, nil, fmt.Errorf("first PEM-block should be a CA cert")
+	}
+
+	chain := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		if !cert.IsCA {
+			return nil, nil, fmt.Errorf("found an unexpected leaf cert after the first PEM-block")
+		}
+		chain.AddCert(cert)
+	}
+
+	return intermediate, chain, nil
+}
+
 // CertSubjects can be used in debugging to return the subject of each
 // certificate in the PEM bundle. Each subject is separated by a newline.
 func CertSubjects(pem string) string {
@@ -191,6 +218,16 @@ func EncodeSerialNumber(serial *big.Int) string {
 	return HexString(serial.Bytes())
 }
 
+// ParseSerialNumber parses a serial number encoded with EncodeSerialNumber.
+// The colons are optional.
+func ParseSerialNumber(serial string) (*big.Int, error) {
+	bs, err := hex.DecodeString(strings.ReplaceAll(serial, ":", ""))
+	if err != nil || len(bs) == 0 {
+		return nil, fmt.Errorf("invalid serial number %q", serial)
+	}
+	return new(big.Int).SetBytes(bs), nil
+}
+
 // EncodeSigningKeyID encodes the given AuthorityKeyId or SubjectKeyId into a
 // colon-hex encoded string suitable for using as a SigningKeyID value.
 func EncodeSigningKeyID(keyID []byte) string { return HexString(keyID) }
@@ -217,7 +254,13 @@ func IsHexString(input []byte) bool {
 // KeyInfoFromCert returns the key type and key bit length for the key used by
 // the certificate.
 func KeyInfoFromCert(cert *x509.Certificate) (keyType string, keyBits int, err error) {
-	switch k := cert.PublicKey.(type) {
+	return KeyInfoFromPublicKey(cert.PublicKey)
+}
+
+// KeyInfoFromPublicKey returns the key type and key bit length of the given
+// public key.
+func KeyInfoFromPublicKey(pub crypto.PublicKey) (keyType string, keyBits int, err error) {
+	switch k := pub.(type) {
 	case *ecdsa.PublicKey:
 		return "ec", k.Curve.Params().BitSize, nil
 	case *rsa.PublicKey:
@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package connect
+
+import (
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// SPIFFEBundleKeyUseX509SVID is the "use" of the keys of a SPIFFE bundle that
+// are X.509 SVID authorities.
+const SPIFFEBundleKeyUseX509SVID = "x509-svid"
+
+// SPIFFEBundle is a trust bundle in the format defined by the SPIFFE Trust
+// Domain and Bundle specification. It is served by the SPIFFE bundle endpoint
+// so that other SPIFFE-compliant meshes can federate with Consul.
+type SPIFFEBundle struct {
+	Keys []jose.JSONWebKey `json:"keys"`
+
+	// Sequence increases every time the bundle changes.
+	Sequence uint64 `json:"spiffe_sequence,omitempty"`
+
+	// RefreshHint is how often, in seconds, the consumers of the bundle
+	// should check for updates.
+	RefreshHint int64 `json:"spiffe_refresh_hint,omitempty"`
+}
+
+// NewSPIFFEBundle returns the SPIFFE bundle of the given CA roots. All the
+// trusted roots are X.509 SVID authorities of the bundle.
+func NewSPIFFEBundle(roots *structs.IndexedCARoots, refreshHint time.Duration) (*SPIFFEBundle, error) {
+	bundle := &SPIFFEBundle{
+		Keys:        make([]jose.JSONWebKey, 0, len(roots.Roots)),
+		Sequence:    roots.Index,
+		RefreshHint: int64(refreshHint.Seconds()),
+	}
+	for _, root := range roots.Roots {
+		cert, err := ParseCert(root.RootCert)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing root %s: %w", root.ID, err)
+		}
+		bundle.Keys = append(bundle.Keys, jose.JSONWebKey{
+			Key:          cert.PublicKey,
+			Certificates: []*x509.Certificate{cert},
+			Use:          SPIFFEBundleKeyUseX509SVID,
+		})
+	}
+	return bundle, nil
+}
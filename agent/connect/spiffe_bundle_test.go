@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package connect
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+func TestNewSPIFFEBundle(t *testing.T) {
+	ca1 := TestCA(t, nil)
+	ca2 := TestCA(t, ca1)
+	roots := &structs.IndexedCARoots{
+		ActiveRootID: ca2.ID,
+		TrustDomain:  TestTrustDomain,
+		Roots:        []*structs.CARoot{ca1, ca2},
+		QueryMeta:    structs.QueryMeta{Index: 42},
+	}
+
+	bundle, err := NewSPIFFEBundle(roots, 5*time.Minute)
+	require.NoError(t, err)
+
+	raw, err := json.Marshal(bundle)
+	require.NoError(t, err)
+
+	var decoded SPIFFEBundle
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+	require.Equal(t, uint64(42), decoded.Sequence)
+	require.Equal(t, int64(300), decoded.RefreshHint)
+	require.Len(t, decoded.Keys, 2)
+	for i, key := range decoded.Keys {
+		require.Equal(t, SPIFFEBundleKeyUseX509SVID, key.Use)
+		require.Len(t, key.Certificates, 1)
+
+		cert, err := ParseCert(roots.Roots[i].RootCert)
+		require.NoError(t, err)
+		require.Equal(t, cert.Raw, key.Certificates[0].Raw)
+	}
+}
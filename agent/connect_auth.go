@@ -133,9 +133,24 @@ func (a *Agent) ConnectAuthorize(token string,
 			return auth, reason, &meta, nil
 		}
 
-		// This is an L7 intention, so DENY.
+		// This is an L7 intention, so DENY unless the request to authorize
+		// matches one of its permissions.
 		reason = fmt.Sprintf("Matched L7 intention: %s", ixnMatch.String())
-		return false, reason, &meta, nil
+		if req.HTTP == nil {
+			return false, reason, &meta, nil
+		}
+
+		auth, match, err := connect.AuthorizeIntentionPermissions(ixnMatch, req.HTTP)
+		if err != nil {
+			return returnErr(err)
+		}
+		if match {
+			return auth, reason, &meta, nil
+		}
+
+		// No permission matches, so the default behavior applies.
+		reason = fmt.Sprintf("Matched L7 intention without matching permission: %s", ixnMatch.String())
+		return authz.IntentionDefaultAllow(nil) == acl.Allow, reason, &meta, nil
 	}
 
 	reason = "Default behavior configured by ACLs"
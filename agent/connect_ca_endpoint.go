@@ -4,10 +4,14 @@
 package agent
 
 import (
+	"encoding/base64"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/hashicorp/consul/agent/connect"
 	"github.com/hashicorp/consul/agent/consul"
 	"github.com/hashicorp/consul/agent/structs"
 )
@@ -54,6 +58,43 @@ func (s *HTTPHandlers) ConnectCARoots(resp http.ResponseWriter, req *http.Reques
 	return nil, nil
 }
 
+// GET /v1/connect/ca/spiffe-bundle
+func (s *HTTPHandlers) ConnectCASPIFFEBundle(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.DCSpecificRequest
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var reply structs.IndexedCARoots
+	defer setMeta(resp, &reply.QueryMeta)
+	if err := s.agent.RPC(req.Context(), "ConnectCA.Roots", &args, &reply); err != nil {
+		return nil, err
+	}
+
+	return connect.NewSPIFFEBundle(&reply, spiffeBundleRefreshHint)
+}
+
+// GET /v1/connect/ca/status
+func (s *HTTPHandlers) ConnectCAStatus(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.DCSpecificRequest
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var reply structs.CAStatus
+	defer setMeta(resp, &reply.QueryMeta)
+	if err := s.agent.RPC(req.Context(), "ConnectCA.Status", &args, &reply); err != nil {
+		return nil, err
+	}
+
+	// Only the proxies connected to this agent are known, and only the ones of
+	// the local datacenter can be compared with its roots.
+	if s.agent.xdsServer != nil && args.Datacenter == s.agent.config.Datacenter {
+		reply.LocalProxiesOnInactiveRoots = s.agent.xdsServer.ProxiesOnInactiveRoots()
+	}
+	return reply, nil
+}
+
 // /v1/connect/ca/configuration
 func (s *HTTPHandlers) ConnectCAConfiguration(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	switch req.Method {
@@ -107,3 +148,126 @@ func (s *HTTPHandlers) ConnectCAConfigurationSet(req *http.Request) (interface{}
 	}
 	return nil, err
 }
+
+// PUT /v1/connect/ca/intermediate-csr
+func (s *HTTPHandlers) ConnectCAIntermediateCSR(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.CAIntermediateRequest
+	s.parseDC(req, &args.Datacenter)
+	s.parseToken(req, &args.Token)
+
+	var csr string
+	if err := s.agent.RPC(req.Context(), "ConnectCA.IntermediateCSR", &args, &csr); err != nil {
+		return nil, err
+	}
+
+	return struct{ CSR string }{CSR: csr}, nil
+}
+
+// PUT /v1/connect/ca/intermediate
+func (s *HTTPHandlers) ConnectCASetIntermediate(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.CAIntermediateRequest
+	s.parseDC(req, &args.Datacenter)
+	s.parseToken(req, &args.Token)
+
+	var body struct {
+		Certificate string
+	}
+	if err := decodeBody(req.Body, &body); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request decode failed: %v", err)}
+	}
+	if body.Certificate == "" {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Missing intermediate certificate"}
+	}
+	args.Certificate = body.Certificate
+
+	var reply interface{}
+	if err := s.agent.RPC(req.Context(), "ConnectCA.SetIntermediate", &args, &reply); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
+
+// PUT /v1/connect/ca/revoke
+func (s *HTTPHandlers) ConnectCARevoke(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.CARevokeRequest
+	if err := decodeBody(req.Body, &args); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request decode failed: %v", err)}
+	}
+	s.parseDC(req, &args.Datacenter)
+	s.parseToken(req, &args.Token)
+	if err := s.parseEntMetaNoWildcard(req, &args.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+	if args.SerialNumber == "" && args.Service == "" && args.Node == "" {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "A serial number, service or node is required"}
+	}
+
+	var reply structs.CARevokeResponse
+	if err := s.agent.RPC(req.Context(), "ConnectCA.Revoke", &args, &reply); err != nil {
+		return nil, err
+	}
+	if reply.Certs == nil {
+		reply.Certs = make([]*structs.IssuedCert, 0)
+	}
+	return reply.Certs, nil
+}
+
+// GET /v1/connect/ca/crl
+func (s *HTTPHandlers) ConnectCARevocationList(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.DCSpecificRequest
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var reply structs.IndexedCARoots
+	defer setMeta(resp, &reply.QueryMeta)
+	if err := s.agent.RPC(req.Context(), "ConnectCA.Roots", &args, &reply); err != nil {
+		return nil, err
+	}
+	if reply.RevocationList == "" {
+		return nil, HTTPError{StatusCode: http.StatusNotFound, Reason: "No certificate is revoked"}
+	}
+
+	resp.Header().Set("Content-Type", "application/x-pem-file")
+	if _, err := resp.Write([]byte(reply.RevocationList)); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// GET /v1/connect/ca/ocsp/<request>
+// POST /v1/connect/ca/ocsp
+func (s *HTTPHandlers) ConnectCAOCSP(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.CAOCSPRequest
+	s.parseDC(req, &args.Datacenter)
+
+	switch req.Method {
+	case "GET":
+		// The request is base64 encoded in the path, as described in RFC 6960.
+		encoded := strings.TrimPrefix(req.URL.Path, "/v1/connect/ca/ocsp/")
+		der, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Invalid OCSP request: %v", err)}
+		}
+		args.Request = der
+	case "POST":
+		der, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request read failed: %v", err)}
+		}
+		args.Request = der
+	default:
+		return nil, MethodNotAllowedError{req.Method, []string{"GET", "POST"}}
+	}
+
+	var reply []byte
+	if err := s.agent.RPC(req.Context(), "ConnectCA.OCSP", &args, &reply); err != nil {
+		return nil, err
+	}
+
+	resp.Header().Set("Content-Type", "application/ocsp-response")
+	if _, err := resp.Write(reply); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
@@ -72,6 +72,36 @@ func TestConnectCARoots_list(t *testing.T) {
 	}
 }
 
+func TestConnectCASPIFFEBundle(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	// Rotate the CA so that the bundle contains two roots.
+	connect.TestCAConfigSet(t, a, nil)
+
+	req, _ := http.NewRequest("GET", "/v1/connect/ca/spiffe-bundle", nil)
+	resp := httptest.NewRecorder()
+	obj, err := a.srv.ConnectCASPIFFEBundle(resp, req)
+	require.NoError(t, err)
+
+	bundle := obj.(*connect.SPIFFEBundle)
+	require.Len(t, bundle.Keys, 2)
+	require.NotZero(t, bundle.Sequence)
+	require.Equal(t, int64(spiffeBundleRefreshHint.Seconds()), bundle.RefreshHint)
+	for _, key := range bundle.Keys {
+		require.Equal(t, connect.SPIFFEBundleKeyUseX509SVID, key.Use)
+		require.Len(t, key.Certificates, 1)
+		require.True(t, key.Certificates[0].IsCA)
+	}
+}
+
 func TestConnectCAConfig(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
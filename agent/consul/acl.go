@@ -22,6 +22,7 @@ import (
 	"github.com/hashicorp/consul/agent/structs/aclfilter"
 	"github.com/hashicorp/consul/agent/token"
 	"github.com/hashicorp/consul/logging"
+	"github.com/hashicorp/consul/sentinel"
 )
 
 var ACLCounters = []prometheus.CounterDefinition{
@@ -63,6 +64,24 @@ const (
 	// due to the data being more variable in its size.
 	aclBatchUpsertSize = 256 * 1024
 
+	// aclTokenSecretReapingBatchSize is the number of rotated tokens whose expired
+	// previous SecretID is removed in a single batch operation. Unlike deletions the
+	// whole token is written so this is kept well below aclBatchDeleteSize.
+	aclTokenSecretReapingBatchSize = 128
+
+	// aclTokenUsageFlushInterval is how often servers write the usage of the
+	// tokens they resolved.
+	aclTokenUsageFlushInterval = time.Minute
+
+	// aclTokenUsageGranularity is how far the recorded LastUsedAt of a token may
+	// lag behind. A token that is used all the time is written at most once per
+	// period.
+	aclTokenUsageGranularity = time.Hour
+
+	// aclTokenUsageBatchSize is the maximum number of tokens whose usage is
+	// written in a single batch operation.
+	aclTokenUsageBatchSize = 1024
+
 	// Maximum number of re-resolution requests to be made if the token is modified between
 	// resolving the token and resolving its policies that would remove one of its policies.
 	tokenPolicyResolutionMaxRetries = 5
@@ -140,6 +159,7 @@ type ACLResolverBackend interface {
 	ResolveIdentityFromToken(token string) (bool, structs.ACLIdentity, error)
 	ResolvePolicyFromID(policyID string) (bool, *structs.ACLPolicy, error)
 	ResolveRoleFromID(roleID string) (bool, *structs.ACLRole, error)
+	ResolveTemplatedPolicyFromName(name string) (bool, *structs.ACLTemplatedPolicyDefinition, error)
 	IsServerManagementToken(token string) bool
 	// TODO: separate methods for each RPC call (there are 4)
 	RPC(ctx context.Context, method string, args interface{}, reply interface{}) error
@@ -180,6 +200,10 @@ type ACLResolverConfig struct {
 
 	// Tokens is the token store of locally managed tokens
 	Tokens *token.Store
+
+	// TrackTokenUsage records the last time each token was resolved so that
+	// it can be persisted. This is only set on Servers.
+	TrackTokenUsage bool
 }
 
 const aclClientDisabledTTL = 30 * time.Second
@@ -280,6 +304,9 @@ type ACLResolver struct {
 	disabledLock sync.RWMutex
 
 	agentRecoveryAuthz acl.Authorizer
+
+	// tokenUsage is nil unless the usage of tokens is tracked.
+	tokenUsage *tokenUsageTracker
 }
 
 func agentRecoveryAuthorizer(nodeName string, entMeta *acl.EnterpriseMeta, aclConf *acl.Config) (acl.Authorizer, error) {
@@ -344,6 +371,11 @@ func NewACLResolver(config *ACLResolverConfig) (*ACLResolver, error) {
 		return nil, fmt.Errorf("failed to initialize the agent recovery authorizer")
 	}
 
+	var tokenUsage *tokenUsageTracker
+	if config.TrackTokenUsage {
+		tokenUsage = newTokenUsageTracker(aclTokenUsageGranularity)
+	}
+
 	return &ACLResolver{
 		config:             config.Config,
 		logger:             config.Logger.Named(logging.ACL),
@@ -354,6 +386,7 @@ func NewACLResolver(config *ACLResolverConfig) (*ACLResolver, error) {
 		down:               down,
 		tokens:             config.Tokens,
 		agentRecoveryAuthz: authz,
+		tokenUsage:         tokenUsage,
 	}, nil
 }
 
@@ -561,6 +594,59 @@ func (r *ACLResolver) fetchAndCacheRolesForIdentity(identity structs.ACLIdentity
 	return out, nil
 }
 
+func (r *ACLResolver) fetchAndCacheTemplatedPoliciesForIdentity(identity structs.ACLIdentity, names []string, cached map[string]*structs.TemplatedPolicyCacheEntry) (map[string]*structs.ACLTemplatedPolicyDefinition, error) {
+	req := structs.ACLTemplatedPolicyBatchGetRequest{
+		Datacenter: r.backend.ACLDatacenter(),
+		Names:      names,
+		QueryOptions: structs.QueryOptions{
+			Token:      identity.SecretToken(),
+			AllowStale: true,
+		},
+	}
+
+	var resp structs.ACLTemplatedPolicyBatchResponse
+	err := r.backend.RPC(context.Background(), "ACL.TemplatedPolicyResolve", &req, &resp)
+	if err == nil {
+		out := make(map[string]*structs.ACLTemplatedPolicyDefinition)
+		for _, templatedPolicy := range resp.TemplatedPolicies {
+			out[templatedPolicy.Name] = templatedPolicy
+		}
+
+		for _, name := range names {
+			r.cache.PutTemplatedPolicy(name, out[name])
+		}
+		return out, nil
+	}
+
+	if handledErr := r.maybeHandleIdentityErrorDuringFetch(identity, err); handledErr != nil {
+		return nil, handledErr
+	}
+
+	// other RPC error - use cache if available
+
+	extendCache := r.config.ACLDownPolicy == "extend-cache" || r.config.ACLDownPolicy == "async-cache"
+
+	out := make(map[string]*structs.ACLTemplatedPolicyDefinition)
+	insufficientCache := false
+	for _, name := range names {
+		if entry, ok := cached[name]; extendCache && ok {
+			r.cache.PutTemplatedPolicy(name, entry.TemplatedPolicy)
+			if entry.TemplatedPolicy != nil {
+				out[name] = entry.TemplatedPolicy
+			}
+		} else {
+			r.cache.PutTemplatedPolicy(name, nil)
+			insufficientCache = true
+		}
+	}
+
+	if insufficientCache {
+		return nil, ACLRemoteError{Err: err}
+	}
+
+	return out, nil
+}
+
 func (r *ACLResolver) maybeHandleIdentityErrorDuringFetch(identity structs.ACLIdentity, err error) error {
 	if acl.IsErrNotFound(err) {
 		// make sure to indicate that this identity is no longer valid within
@@ -642,10 +728,16 @@ func (r *ACLResolver) resolvePoliciesForIdentity(identity structs.ACLIdentity) (
 	nodeIdentities = nodeIdentities.Deduplicate()
 	templatedPolicies = templatedPolicies.Deduplicate()
 
+	// Collect the user-defined templates of the templated policies in effect.
+	templates, err := r.collectTemplatedPoliciesForIdentity(identity, templatedPolicies)
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate synthetic policies for all service identities in effect.
 	syntheticPolicies := r.synthesizePoliciesForServiceIdentities(serviceIdentities, identity.EnterpriseMetadata())
 	syntheticPolicies = append(syntheticPolicies, r.synthesizePoliciesForNodeIdentities(nodeIdentities, identity.EnterpriseMetadata())...)
-	syntheticPolicies = append(syntheticPolicies, r.synthesizePoliciesForTemplatedPolicies(templatedPolicies, identity.EnterpriseMetadata())...)
+	syntheticPolicies = append(syntheticPolicies, r.synthesizePoliciesForTemplatedPolicies(templatedPolicies, templates, identity.EnterpriseMetadata())...)
 
 	// For the new ACLs policy replication is mandatory for correct operation on servers. Therefore
 	// we only attempt to resolve policies locally
@@ -689,14 +781,25 @@ func (r *ACLResolver) synthesizePoliciesForNodeIdentities(nodeIdentities []*stru
 	return syntheticPolicies
 }
 
-func (r *ACLResolver) synthesizePoliciesForTemplatedPolicies(templatedPolicies []*structs.ACLTemplatedPolicy, entMeta *acl.EnterpriseMeta) []*structs.ACLPolicy {
+// synthesizePoliciesForTemplatedPolicies renders the templated policies in
+// effect. The templates map holds the user-defined templates by name, the
+// builtin templates are looked up directly.
+func (r *ACLResolver) synthesizePoliciesForTemplatedPolicies(templatedPolicies []*structs.ACLTemplatedPolicy, templates map[string]*structs.ACLTemplatedPolicyBase, entMeta *acl.EnterpriseMeta) []*structs.ACLPolicy {
 	if len(templatedPolicies) == 0 {
 		return nil
 	}
 
 	syntheticPolicies := make([]*structs.ACLPolicy, 0, len(templatedPolicies))
 	for _, tp := range templatedPolicies {
-		policy, err := tp.SyntheticPolicy(entMeta)
+		var (
+			policy *structs.ACLPolicy
+			err    error
+		)
+		if base, ok := templates[tp.TemplateName]; ok {
+			policy, err = tp.SyntheticPolicyFromBase(base, entMeta)
+		} else {
+			policy, err = tp.SyntheticPolicy(entMeta)
+		}
 		if err != nil {
 			r.logger.Warn(fmt.Sprintf("could not generate synthetic policy for templated policy: %q", tp.TemplateName), "error", err)
 			continue
@@ -707,6 +810,74 @@ func (r *ACLResolver) synthesizePoliciesForTemplatedPolicies(templatedPolicies [
 	return syntheticPolicies
 }
 
+// collectTemplatedPoliciesForIdentity returns the user-defined templates
+// referenced by the given templated policies keyed by their name. Templated
+// policies referencing builtin templates are skipped.
+func (r *ACLResolver) collectTemplatedPoliciesForIdentity(identity structs.ACLIdentity, templatedPolicies []*structs.ACLTemplatedPolicy) (map[string]*structs.ACLTemplatedPolicyBase, error) {
+	if len(templatedPolicies) == 0 {
+		return nil, nil
+	}
+
+	templates := make(map[string]*structs.ACLTemplatedPolicyBase)
+	seen := make(map[string]struct{})
+
+	var fetchNames []string
+	expCacheMap := make(map[string]*structs.TemplatedPolicyCacheEntry)
+
+	for _, tp := range templatedPolicies {
+		name := tp.TemplateName
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+
+		if _, ok := structs.GetACLTemplatedPolicyBase(name); ok {
+			continue
+		}
+
+		if done, templatedPolicy, err := r.backend.ResolveTemplatedPolicyFromName(name); done {
+			if err != nil && !acl.IsErrNotFound(err) {
+				return nil, err
+			}
+
+			if templatedPolicy != nil {
+				templates[name] = templatedPolicy.Base()
+			}
+			continue
+		}
+
+		entry := r.cache.GetTemplatedPolicy(name)
+		if entry == nil {
+			fetchNames = append(fetchNames, name)
+			continue
+		}
+
+		if entry.Age() >= r.config.ACLPolicyTTL {
+			fetchNames = append(fetchNames, name)
+			expCacheMap[name] = entry
+			continue
+		}
+
+		if entry.TemplatedPolicy != nil {
+			templates[name] = entry.TemplatedPolicy.Base()
+		}
+	}
+
+	if len(fetchNames) == 0 {
+		return templates, nil
+	}
+
+	fetched, err := r.fetchAndCacheTemplatedPoliciesForIdentity(identity, fetchNames, expCacheMap)
+	if err != nil {
+		return nil, err
+	}
+	for name, templatedPolicy := range fetched {
+		templates[name] = templatedPolicy.Base()
+	}
+
+	return templates, nil
+}
+
 func mergeStringSlice(a, b []string) []string {
 	out := make([]string, 0, len(a)+len(b))
 	out = append(out, a...)
@@ -1069,33 +1240,163 @@ func (r *ACLResolver) ResolveToken(tokenSecretID string) (resolver.Result, error
 		return resolver.Result{}, err
 	}
 
-	// Build the Authorizer
+	chain, err := r.authorizerChainForIdentity(identity, policies)
+	if err != nil {
+		if IsACLRemoteError(err) {
+			r.logger.Error("Error resolving identity defaults", "error", err)
+			return resolver.Result{Authorizer: r.down, ACLIdentity: identity}, nil
+		}
+		return resolver.Result{}, err
+	}
+
+	if r.tokenUsage != nil {
+		if token, ok := identity.(*structs.ACLToken); ok {
+			r.tokenUsage.record(token, time.Now())
+		}
+	}
+	return resolver.Result{Authorizer: acl.NewChainedAuthorizer(chain), ACLIdentity: identity}, nil
+}
+
+// authorizerChainForIdentity builds the chain of authorizers for the identity:
+// its compiled policies, followed by any defaults for the identity, followed
+// by the default policy.
+func (r *ACLResolver) authorizerChainForIdentity(identity structs.ACLIdentity, policies structs.ACLPolicies) ([]acl.Authorizer, error) {
 	var chain []acl.Authorizer
+
+	authz, err := policies.Compile(r.cache, r.aclConfForIdentity(identity))
+	if err != nil {
+		return nil, err
+	}
+	chain = append(chain, authz)
+
+	authz, err = r.resolveEnterpriseDefaultsForIdentity(identity)
+	if err != nil {
+		return nil, err
+	} else if authz != nil {
+		chain = append(chain, authz)
+	}
+
+	chain = append(chain, acl.RootAuthorizer(r.config.ACLDefaultPolicy))
+	return chain, nil
+}
+
+func (r *ACLResolver) aclConfForIdentity(identity structs.ACLIdentity) *acl.Config {
 	var conf acl.Config
 	if r.aclConf != nil {
 		conf = *r.aclConf
 	}
 	setEnterpriseConf(identity.EnterpriseMetadata(), &conf)
+	return &conf
+}
+
+// ExplainToken resolves the token the same way as ResolveToken and returns
+// the decision for each request, along with the part of the token's
+// authorizer chain, and the policy rule, that produced it.
+func (r *ACLResolver) ExplainToken(tokenSecretID string, requests []structs.ACLAuthorizationRequest) ([]structs.ACLAuthorizationExplanation, error) {
+	if !r.ACLsEnabled() {
+		return nil, acl.ErrDisabled
+	}
+
+	if acl.RootAuthorizer(tokenSecretID) != nil {
+		return nil, acl.ErrRootDenied
+	}
+
+	if tokenSecretID == "" {
+		tokenSecretID = anonymousToken
+	}
+
+	explanations := make([]structs.ACLAuthorizationExplanation, len(requests))
+
+	if _, authz, ok := r.resolveLocallyManagedToken(tokenSecretID); ok {
+		responses, err := structs.CreateACLAuthorizationResponses(authz, requests)
+		if err != nil {
+			return nil, err
+		}
+		for idx, resp := range responses {
+			explanations[idx] = structs.ACLAuthorizationExplanation{
+				ACLAuthorizationRequest: resp.ACLAuthorizationRequest,
+				Allow:                   resp.Allow,
+				DecidedBy:               structs.ACLDecisionSourceLocalToken,
+			}
+		}
+		return explanations, nil
+	}
 
-	authz, err := policies.Compile(r.cache, &conf)
+	identity, policies, err := r.resolveTokenToIdentityAndPolicies(tokenSecretID)
 	if err != nil {
-		return resolver.Result{}, err
+		r.handleACLDisabledError(err)
+		return nil, err
 	}
-	chain = append(chain, authz)
 
-	authz, err = r.resolveEnterpriseDefaultsForIdentity(identity)
+	chain, err := r.authorizerChainForIdentity(identity, policies)
 	if err != nil {
-		if IsACLRemoteError(err) {
-			r.logger.Error("Error resolving identity defaults", "error", err)
-			return resolver.Result{Authorizer: r.down, ACLIdentity: identity}, nil
+		return nil, err
+	}
+
+	// Parse the policies afresh rather than using the cache, as explaining
+	// them needs the rules of each one as written.
+	conf := r.aclConfForIdentity(identity)
+	parsed := make([]*acl.Policy, 0, len(policies))
+	for _, policy := range policies {
+		p, err := acl.NewPolicyFromSource(policy.Rules, conf, policy.EnterprisePolicyMeta())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %q: %v", policy.Name, err)
 		}
-		return resolver.Result{}, err
-	} else if authz != nil {
-		chain = append(chain, authz)
+		parsed = append(parsed, p)
 	}
 
-	chain = append(chain, acl.RootAuthorizer(r.config.ACLDefaultPolicy))
-	return resolver.Result{Authorizer: acl.NewChainedAuthorizer(chain), ACLIdentity: identity}, nil
+	var authzContext acl.AuthorizerContext
+	for idx, req := range requests {
+		req.FillAuthzContext(&authzContext)
+
+		explanation := structs.ACLAuthorizationExplanation{ACLAuthorizationRequest: req}
+
+		// Find the first authorizer in the chain to render a decision, as the
+		// ChainedAuthorizer does.
+		decision, link := acl.Deny, len(chain)-1
+		for i, authz := range chain {
+			d, err := acl.Enforce(authz, req.Resource, req.Segment, req.Access, &authzContext)
+			if err != nil {
+				return nil, err
+			}
+			if d != acl.Default {
+				decision, link = d, i
+				break
+			}
+		}
+		explanation.Allow = decision == acl.Allow
+
+		switch link {
+		case 0:
+			explanation.DecidedBy = structs.ACLDecisionSourcePolicy
+			_, rule, err := acl.ExplainPolicies(parsed, conf, req.Resource, req.Segment, req.Access, &authzContext)
+			if err != nil {
+				return nil, err
+			}
+			if rule != nil {
+				policy := policies[rule.PolicyIndex]
+				explanation.PolicyID = policy.ID
+				explanation.PolicyName = policy.Name
+				explanation.PolicyDescription = policy.Description
+				explanation.Rule = &structs.ACLExplainedRule{
+					Resource:   rule.Resource,
+					Segment:    rule.Segment,
+					Prefix:     rule.Prefix,
+					Glob:       rule.Glob,
+					Policy:     rule.Policy,
+					Intentions: rule.Intentions,
+				}
+			}
+		case len(chain) - 1:
+			explanation.DecidedBy = structs.ACLDecisionSourceDefaultPolicy
+		default:
+			explanation.DecidedBy = structs.ACLDecisionSourceIdentityDefaults
+		}
+
+		explanations[idx] = explanation
+	}
+
+	return explanations, nil
 }
 
 func (r *ACLResolver) ACLsEnabled() bool {
@@ -1170,6 +1471,39 @@ func filterACL(r *ACLResolver, tokenSecretID string, subj interface{}) error {
 	return nil
 }
 
+// sentinelRequest describes a request made with the given identity for the
+// scope that the conditions of ACL rules are evaluated against.
+func sentinelRequest(identity structs.ACLIdentity, sourceIP string) sentinel.Request {
+	req := sentinel.Request{
+		SourceIP: sourceIP,
+		Time:     time.Now(),
+	}
+	if identity == nil {
+		return req
+	}
+
+	req.Token.AccessorID = identity.ID()
+	req.Token.Local = identity.IsLocal()
+	for _, svcid := range identity.ServiceIdentityList() {
+		req.Token.ServiceIdentities = append(req.Token.ServiceIdentities, svcid.ServiceName)
+	}
+	for _, nodeid := range identity.NodeIdentityList() {
+		req.Token.NodeIdentities = append(req.Token.NodeIdentities, nodeid.NodeName)
+	}
+
+	if token, ok := identity.(*structs.ACLToken); ok {
+		req.Token.Description = token.Description
+		req.Token.AuthMethod = token.AuthMethod
+		for _, link := range token.Policies {
+			req.Token.Policies = append(req.Token.Policies, link.Name)
+		}
+		for _, link := range token.Roles {
+			req.Token.Roles = append(req.Token.Roles, link.Name)
+		}
+	}
+	return req
+}
+
 type partitionInfoNoop struct{}
 
 func (p *partitionInfoNoop) ExportsForPartition(partition string) acl.ExportedServices {
@@ -10,6 +10,8 @@ import (
 
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/logging"
+	"github.com/hashicorp/consul/sentinel"
 )
 
 // EnterpriseACLResolverDelegate stub
@@ -23,9 +25,12 @@ func serverPartitionInfo(s *Server) acl.ExportFetcher {
 	return &partitionInfoNoop{}
 }
 
-func newACLConfig(_ acl.ExportFetcher, _ hclog.Logger) *acl.Config {
+func newACLConfig(_ acl.ExportFetcher, logger hclog.Logger) *acl.Config {
 	return &acl.Config{
 		WildcardName: structs.WildcardSpecifier,
+		EnterpriseConfig: acl.EnterpriseConfig{
+			SentinelEvaluator: sentinel.New(logger.Named(logging.Sentinel)),
+		},
 	}
 }
 
@@ -23,6 +23,8 @@ var clientACLCacheConfig = &structs.ACLCachesConfig{
 	Authorizers: 256,
 	// Roles - number of ACL roles that can be cached
 	Roles: 128,
+	// TemplatedPolicies - number of user-defined templated policies that can be cached
+	TemplatedPolicies: 128,
 }
 
 type clientACLResolverBackend struct {
@@ -54,3 +56,8 @@ func (c *clientACLResolverBackend) ResolveRoleFromID(roleID string) (bool, *stru
 	// clients do no local role resolution at the moment
 	return false, nil, nil
 }
+
+func (c *clientACLResolverBackend) ResolveTemplatedPolicyFromName(name string) (bool, *structs.ACLTemplatedPolicyDefinition, error) {
+	// clients do no local templated policy resolution at the moment
+	return false, nil, nil
+}
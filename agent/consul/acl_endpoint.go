@@ -5,6 +5,7 @@ package consul
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"os"
@@ -17,6 +18,7 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/go-version"
 
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/acl/resolver"
@@ -34,6 +36,20 @@ const (
 	aclBootstrapReset = "acl-bootstrap-reset"
 )
 
+var (
+	// minACLTokenUsageVersion is the minimum version all the servers in a
+	// datacenter must run before token usage is written to Raft.
+	minACLTokenUsageVersion = version.Must(version.NewVersion("1.17.0"))
+
+	// minACLTemplatedPolicyVersion is the minimum version all the servers in
+	// a datacenter must run before templated policies can be written.
+	minACLTemplatedPolicyVersion = version.Must(version.NewVersion("1.17.0"))
+
+	// minACLTokenRotationVersion is the minimum version all the servers in a
+	// datacenter must run before the SecretID of a token can be changed.
+	minACLTokenRotationVersion = version.Must(version.NewVersion("1.17.0"))
+)
+
 var ACLEndpointSummaries = []prometheus.SummaryDefinition{
 	{
 		Name: []string{"acl", "token", "clone"},
@@ -99,6 +115,14 @@ var ACLEndpointSummaries = []prometheus.SummaryDefinition{
 		Name: []string{"acl", "logout"},
 		Help: "",
 	},
+	{
+		Name: []string{"acl", "oidc", "auth_url"},
+		Help: "",
+	},
+	{
+		Name: []string{"acl", "oidc", "callback"},
+		Help: "",
+	},
 }
 
 // ACL endpoint is used to manipulate ACLs
@@ -452,6 +476,92 @@ func (a *ACL) lookupExpandedTokenInfo(ws memdb.WatchSet, state *state.Store, tok
 	return tokenInfo, nil
 }
 
+// TokenLineage returns how a token issued by logging in with an auth method
+// was bound: the auth method, the binding rules that matched, and the roles,
+// policies and identities that determine the token's permissions.
+func (a *ACL) TokenLineage(args *structs.ACLTokenGetRequest, reply *structs.ACLTokenLineageResponse) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if err := a.srv.validateEnterpriseRequest(&args.EnterpriseMeta, false); err != nil {
+		return err
+	}
+
+	if !a.srv.LocalTokensEnabled() {
+		args.Datacenter = a.srv.config.PrimaryDatacenter
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.TokenLineage", args, reply); done {
+		return err
+	}
+
+	var authzContext acl.AuthorizerContext
+	authz, err := a.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, &authzContext)
+	if err != nil {
+		return err
+	}
+	if err := authz.ToAllowAuthorizer().ACLReadAllowed(&authzContext); err != nil {
+		return err
+	}
+
+	return a.srv.blockingQuery(&args.QueryOptions, &reply.QueryMeta,
+		func(ws memdb.WatchSet, state *state.Store) error {
+			index, token, err := state.ACLTokenGetByAccessor(ws, args.TokenID, &args.EnterpriseMeta)
+			if err != nil {
+				return err
+			}
+			if token == nil || token.IsExpired(time.Now()) {
+				return fmt.Errorf("token does not exist: %w", acl.ErrNotFound)
+			}
+			if token.AuthMethod == "" {
+				return fmt.Errorf("token %s was not issued by an auth method and has no lineage: %w", token.AccessorID, acl.ErrNotFound)
+			}
+
+			info, err := a.lookupExpandedTokenInfo(ws, state, token)
+			if err != nil {
+				return err
+			}
+
+			methodMeta := token.ACLAuthMethodEnterpriseMeta.ToEnterpriseMeta()
+			methodIndex, method, err := state.ACLAuthMethodGetByName(ws, token.AuthMethod, methodMeta)
+			if err != nil {
+				return err
+			}
+			index = lib.MaxUint64(index, methodIndex)
+
+			var rules structs.ACLBindingRules
+			var missing []string
+			if token.Lineage != nil {
+				for _, id := range token.Lineage.BindingRuleIDs {
+					ruleIndex, rule, err := state.ACLBindingRuleGetByID(ws, id, methodMeta)
+					if err != nil {
+						return err
+					}
+					index = lib.MaxUint64(index, ruleIndex)
+					if rule == nil {
+						missing = append(missing, id)
+						continue
+					}
+					rules = append(rules, rule)
+				}
+			}
+
+			a.srv.filterACLWithAuthorizer(authz, &token)
+
+			reply.Index, reply.Token = index, token
+			reply.Redacted = token.SecretID == aclfilter.RedactedToken
+			reply.ExpandedTokenInfo = info
+			reply.IssuedBy = nil
+			if method != nil {
+				reply.IssuedBy = method.Stub()
+			}
+			reply.BindingRules = rules
+			reply.MissingBindingRuleIDs = missing
+			return nil
+		})
+}
+
 func (a *ACL) TokenClone(args *structs.ACLTokenSetRequest, reply *structs.ACLToken) error {
 	if err := a.aclPreCheck(); err != nil {
 		return err
@@ -571,6 +681,230 @@ func (a *ACL) TokenSet(args *structs.ACLTokenSetRequest, reply *structs.ACLToken
 	return err
 }
 
+// TokenRotate replaces the SecretID of a token. The previous SecretID remains
+// valid for the requested grace period.
+func (a *ACL) TokenRotate(args *structs.ACLTokenRotateRequest, reply *structs.ACLToken) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if err := a.srv.validateEnterpriseRequest(&args.EnterpriseMeta, true); err != nil {
+		return err
+	}
+
+	// clients will not know whether the server has local token store. In the case
+	// where it doesn't we will transparently forward requests.
+	if !a.srv.LocalTokensEnabled() {
+		args.Datacenter = a.srv.config.PrimaryDatacenter
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.TokenRotate", args, reply); done {
+		return err
+	}
+
+	if err := a.srv.requireServersMinimumVersion(minACLTokenRotationVersion, "rotating tokens"); err != nil {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "token", "rotate"}, time.Now())
+
+	var authzContext acl.AuthorizerContext
+	authz, err := a.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, &authzContext)
+	if err != nil {
+		return err
+	} else if err := authz.ToAllowAuthorizer().ACLWriteAllowed(&authzContext); err != nil {
+		return err
+	}
+
+	_, token, err := a.srv.fsm.State().ACLTokenGetByAccessor(nil, args.AccessorID, &args.EnterpriseMeta)
+	if err != nil {
+		return err
+	} else if token == nil {
+		if ns := args.EnterpriseMeta.NamespaceOrEmpty(); ns != "" {
+			return fmt.Errorf("token not found in namespace %s: %w", ns, acl.ErrNotFound)
+		}
+		return fmt.Errorf("token does not exist: %w", acl.ErrNotFound)
+	} else if token.IsExpired(time.Now()) {
+		return fmt.Errorf("token is expired: %w", acl.ErrNotFound)
+	} else if !a.srv.InPrimaryDatacenter() && !token.Local {
+		// global token writes must be forwarded to the primary DC
+		args.Datacenter = a.srv.config.PrimaryDatacenter
+		return a.srv.forwardDC("ACL.TokenRotate", a.srv.config.PrimaryDatacenter, args, reply)
+	}
+
+	rotated, err := a.srv.aclTokenWriter().Rotate(args.AccessorID, args.GracePeriod, &args.EnterpriseMeta)
+	if err != nil {
+		return err
+	}
+
+	a.logger.Info("rotated ACL token secret",
+		"accessorID", acl.AliasIfAnonymousToken(rotated.AccessorID),
+		"grace_period", args.GracePeriod,
+	)
+
+	*reply = *rotated
+	return nil
+}
+
+// TokenBreakGlass issues a short-lived local token granting global-management
+// access. The request must be approved by a second token that is allowed to
+// write ACLs. Every break-glass token is written to the audit log and can be
+// listed with a dedicated token list filter. Like any other expiring token it
+// is deleted by the expired token reaper.
+func (a *ACL) TokenBreakGlass(args *structs.ACLTokenBreakGlassRequest, reply *structs.ACLToken) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if err := a.srv.validateEnterpriseRequest(&args.EnterpriseMeta, true); err != nil {
+		return err
+	}
+
+	// clients will not know whether the server has local token store. In the case
+	// where it doesn't we will transparently forward requests.
+	if !a.srv.LocalTokensEnabled() {
+		args.Datacenter = a.srv.config.PrimaryDatacenter
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.TokenBreakGlass", args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "token", "break_glass"}, time.Now())
+
+	if args.Reason == "" {
+		return fmt.Errorf("A reason is required for break-glass tokens")
+	}
+
+	requester, err := a.srv.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+	if requester.AccessorID() == "" || requester.AccessorID() == acl.AnonymousTokenID {
+		return acl.PermissionDeniedError{Cause: "Break-glass tokens cannot be requested anonymously"}
+	}
+
+	if args.ApproverToken == "" {
+		return acl.PermissionDeniedError{Cause: "Break-glass tokens require an approver token"}
+	}
+	var authzContext acl.AuthorizerContext
+	approver, err := a.srv.ResolveTokenAndDefaultMeta(args.ApproverToken, &args.EnterpriseMeta, &authzContext)
+	if err != nil {
+		return fmt.Errorf("failed to resolve the approver token: %w", err)
+	}
+	if approver.AccessorID() == requester.AccessorID() {
+		return acl.PermissionDeniedError{Cause: "Break-glass tokens cannot be approved by the requester's own token"}
+	}
+	if err := approver.ToAllowAuthorizer().ACLWriteAllowed(&authzContext); err != nil {
+		return acl.PermissionDeniedError{Cause: "The approver token is not allowed to write ACLs"}
+	}
+
+	token := &structs.ACLToken{
+		Description:   "Break-glass token: " + args.Reason,
+		Local:         true,
+		ExpirationTTL: args.TTL,
+		BreakGlass: &structs.ACLTokenBreakGlass{
+			Reason:      args.Reason,
+			RequestedBy: requester.AccessorID(),
+			ApprovedBy:  approver.AccessorID(),
+		},
+		EnterpriseMeta: args.EnterpriseMeta,
+	}
+
+	issued, err := a.srv.aclTokenWriter().CreateBreakGlass(token, a.srv.config.ACLBreakGlassMaxTTL)
+	if err != nil {
+		return err
+	}
+
+	a.logger.Warn("issued break-glass ACL token",
+		"accessorID", issued.AccessorID,
+		"reason", args.Reason,
+		"requested_by", issued.BreakGlass.RequestedBy,
+		"approved_by", issued.BreakGlass.ApprovedBy,
+		"expiration_time", issued.ExpirationTime,
+	)
+	metrics.IncrCounter([]string{"acl", "token", "break_glass", "issued"}, 1)
+	a.srv.auditBreakGlass(issued)
+
+	*reply = *issued
+	return nil
+}
+
+// TokenRenew returns the current SecretID of the request token. Holders of
+// a token use it to pick up a new SecretID while the previous one is still
+// within the grace period of a rotation.
+func (a *ACL) TokenRenew(args *structs.ACLTokenRenewRequest, reply *structs.ACLTokenRenewResponse) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.TokenRenew", args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "token", "renew"}, time.Now())
+
+	if args.Token == "" {
+		return fmt.Errorf("Missing token to renew")
+	}
+
+	_, token, err := a.srv.fsm.State().ACLTokenGetBySecret(nil, args.Token, nil)
+	if err != nil {
+		return err
+	} else if token == nil || token.IsExpired(time.Now()) {
+		return acl.ErrNotFound
+	}
+
+	reply.SecretID = token.SecretID
+	if token.SecretID != args.Token {
+		reply.Rotated = true
+		reply.PreviousSecretExpirationTime = token.PreviousSecretExpirationTime
+	}
+	return nil
+}
+
+// TokenUsageUpdate records when tokens were last used. It is called by the
+// servers themselves with the usage they collected while resolving tokens.
+func (a *ACL) TokenUsageUpdate(args *structs.ACLTokenUsageRequest, reply *bool) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.TokenUsageUpdate", args, reply); done {
+		return err
+	}
+
+	if err := a.srv.requireServersMinimumVersion(minACLTokenUsageVersion, "recording token usage"); err != nil {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "token", "usage"}, time.Now())
+
+	var authzContext acl.AuthorizerContext
+	authz, err := a.srv.ResolveTokenAndDefaultMeta(args.Token, nil, &authzContext)
+	if err != nil {
+		return err
+	} else if err := authz.ToAllowAuthorizer().ACLWriteAllowed(&authzContext); err != nil {
+		return err
+	}
+
+	if len(args.Usage) == 0 {
+		*reply = true
+		return nil
+	}
+	if len(args.Usage) > aclTokenUsageBatchSize {
+		return fmt.Errorf("Cannot record the usage of more than %d tokens at once", aclTokenUsageBatchSize)
+	}
+
+	// Usage is best effort, servers that don't know the message type can skip it.
+	if _, err := a.srv.raftApply(structs.ACLTokenUsageRequestType|structs.IgnoreUnknownTypeFlag, args); err != nil {
+		return fmt.Errorf("Failed to apply token usage request: %v", err)
+	}
+
+	*reply = true
+	return nil
+}
+
 func (a *ACL) TokenDelete(args *structs.ACLTokenDeleteRequest, reply *string) error {
 	if err := a.aclPreCheck(); err != nil {
 		return err
@@ -724,6 +1058,9 @@ func (a *ACL) TokenList(args *structs.ACLTokenListRequest, reply *structs.ACLTok
 				if token.IsExpired(now) {
 					continue
 				}
+				if args.BreakGlass && token.BreakGlass == nil {
+					continue
+				}
 				stubs = append(stubs, token.Stub())
 			}
 
@@ -913,159 +1250,474 @@ func (a *ACL) PolicySet(args *structs.ACLPolicySetRequest, reply *structs.ACLPol
 			return fmt.Errorf("Policy ID invalid UUID")
 		}
 
-		_, idMatch, err = state.ACLPolicyGetByID(nil, policy.ID, nil)
-		if err != nil {
-			return fmt.Errorf("acl policy lookup by id failed: %v", err)
-		}
+		_, idMatch, err = state.ACLPolicyGetByID(nil, policy.ID, nil)
+		if err != nil {
+			return fmt.Errorf("acl policy lookup by id failed: %v", err)
+		}
+	}
+	_, nameMatch, err = state.ACLPolicyGetByName(nil, policy.Name, &policy.EnterpriseMeta)
+	if err != nil {
+		return fmt.Errorf("acl policy lookup by name failed: %v", err)
+	}
+
+	if policy.ID == "" {
+		// with no policy ID one will be generated
+		var err error
+		policy.ID, err = lib.GenerateUUID(a.srv.checkPolicyUUID)
+		if err != nil {
+			return err
+		}
+
+		// validate the name is unique
+		if nameMatch != nil {
+			return fmt.Errorf("Invalid Policy: A Policy with Name %q already exists", policy.Name)
+		}
+	} else {
+		// Verify the policy exists
+		if idMatch == nil {
+			return fmt.Errorf("cannot find policy %s", policy.ID)
+		}
+
+		// Verify that the name isn't changing or that the name is not already used
+		if idMatch.Name != policy.Name && nameMatch != nil {
+			return fmt.Errorf("Invalid Policy: A policy with name %q already exists", policy.Name)
+		}
+
+		if builtinPolicy, ok := structs.ACLBuiltinPolicies[policy.ID]; ok {
+			if policy.Datacenters != nil || len(policy.Datacenters) > 0 {
+				return fmt.Errorf("Changing the Datacenters of the %s policy is not permitted", builtinPolicy.Name)
+			}
+
+			if policy.Rules != idMatch.Rules {
+				return fmt.Errorf("Changing the Rules for the builtin %s policy is not permitted", builtinPolicy.Name)
+			}
+		}
+	}
+
+	// validate the rules
+	_, err = acl.NewPolicyFromSource(policy.Rules, a.srv.aclConfig, policy.EnterprisePolicyMeta())
+	if err != nil {
+		return err
+	}
+
+	// validate the enterprise specific fields
+	if err = a.policyUpsertValidateEnterprise(policy, idMatch); err != nil {
+		return err
+	}
+
+	// calculate the hash for this policy
+	policy.SetHash(true)
+
+	req := &structs.ACLPolicyBatchSetRequest{
+		Policies: structs.ACLPolicies{policy},
+	}
+
+	_, err = a.srv.raftApply(structs.ACLPolicySetRequestType, req)
+	if err != nil {
+		return fmt.Errorf("Failed to apply policy upsert request: %v", err)
+	}
+
+	// Remove from the cache to prevent stale cache usage
+	a.srv.ACLResolver.cache.RemovePolicy(policy.ID)
+
+	if _, policy, err := a.srv.fsm.State().ACLPolicyGetByID(nil, policy.ID, &policy.EnterpriseMeta); err == nil && policy != nil {
+		*reply = *policy
+	}
+
+	return nil
+}
+
+func (a *ACL) PolicyDelete(args *structs.ACLPolicyDeleteRequest, reply *string) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if err := a.srv.validateEnterpriseRequest(&args.EnterpriseMeta, true); err != nil {
+		return err
+	}
+
+	if !a.srv.InPrimaryDatacenter() {
+		args.Datacenter = a.srv.config.PrimaryDatacenter
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.PolicyDelete", args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "policy", "delete"}, time.Now())
+
+	// Verify token is permitted to modify ACLs
+	var authzContext acl.AuthorizerContext
+
+	if authz, err := a.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, &authzContext); err != nil {
+		return err
+	} else if err := authz.ToAllowAuthorizer().ACLWriteAllowed(&authzContext); err != nil {
+		return err
+	}
+
+	_, policy, err := a.srv.fsm.State().ACLPolicyGetByID(nil, args.PolicyID, &args.EnterpriseMeta)
+	if err != nil {
+		return err
+	}
+
+	if policy == nil {
+		if ns := args.EnterpriseMeta.NamespaceOrEmpty(); ns != "" {
+			return fmt.Errorf("policy not found in namespace %s: %w", ns, acl.ErrNotFound)
+		}
+		return fmt.Errorf("policy does not exist: %w", acl.ErrNotFound)
+	}
+
+	if builtinPolicy, ok := structs.ACLBuiltinPolicies[policy.ID]; ok {
+		return fmt.Errorf("Delete operation not permitted on the builtin %s policy", builtinPolicy.Name)
+	}
+
+	req := structs.ACLPolicyBatchDeleteRequest{
+		PolicyIDs: []string{args.PolicyID},
+	}
+
+	_, err = a.srv.raftApply(structs.ACLPolicyDeleteRequestType, &req)
+	if err != nil {
+		return fmt.Errorf("Failed to apply policy delete request: %v", err)
+	}
+
+	a.srv.ACLResolver.cache.RemovePolicy(policy.ID)
+
+	*reply = policy.Name
+
+	return nil
+}
+
+func (a *ACL) PolicyList(args *structs.ACLPolicyListRequest, reply *structs.ACLPolicyListResponse) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if err := a.srv.validateEnterpriseRequest(&args.EnterpriseMeta, false); err != nil {
+		return err
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.PolicyList", args, reply); done {
+		return err
+	}
+
+	var authzContext acl.AuthorizerContext
+
+	authz, err := a.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, &authzContext)
+	if err != nil {
+		return err
+	} else if err := authz.ToAllowAuthorizer().ACLReadAllowed(&authzContext); err != nil {
+		return err
+	}
+
+	return a.srv.blockingQuery(&args.QueryOptions, &reply.QueryMeta,
+		func(ws memdb.WatchSet, state *state.Store) error {
+			index, policies, err := state.ACLPolicyList(ws, &args.EnterpriseMeta)
+			if err != nil {
+				return err
+			}
+
+			// filter down to just what the requester has permissions to see
+			a.srv.filterACLWithAuthorizer(authz, &policies)
+
+			var stubs structs.ACLPolicyListStubs
+			for _, policy := range policies {
+				stubs = append(stubs, policy.Stub())
+			}
+
+			reply.Index, reply.Policies = index, stubs
+			return nil
+		})
+}
+
+// PolicyResolve is used to retrieve a subset of the policies associated with a given token
+// The policy ids in the args simply act as a filter on the policy set assigned to the token
+func (a *ACL) PolicyResolve(args *structs.ACLPolicyBatchGetRequest, reply *structs.ACLPolicyBatchResponse) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.PolicyResolve", args, reply); done {
+		return err
+	}
+
+	// get full list of policies for this token
+	identity, policies, err := a.srv.ACLResolver.resolveTokenToIdentityAndPolicies(args.Token)
+	if err != nil {
+		return err
+	}
+
+	entIdentity, entPolicies, err := a.srv.ACLResolver.resolveEnterpriseIdentityAndPolicies(identity)
+	if err != nil {
+		return err
+	}
+
+	idMap := make(map[string]*structs.ACLPolicy)
+	for _, policyID := range identity.PolicyIDs() {
+		idMap[policyID] = nil
+	}
+	if entIdentity != nil {
+		for _, policyID := range entIdentity.PolicyIDs() {
+			idMap[policyID] = nil
+		}
+	}
+
+	for _, policy := range policies {
+		idMap[policy.ID] = policy
+	}
+	for _, policy := range entPolicies {
+		idMap[policy.ID] = policy
+	}
+
+	for _, policyID := range args.PolicyIDs {
+		if policy, ok := idMap[policyID]; ok {
+			// only add non-deleted policies
+			if policy != nil {
+				reply.Policies = append(reply.Policies, policy)
+			}
+		} else {
+			// send a permission denied to indicate that the request included
+			// policy ids not associated with this token
+			return acl.ErrPermissionDenied
+		}
+	}
+
+	a.srv.SetQueryMeta(&reply.QueryMeta, args.Token)
+
+	return nil
+}
+
+func (a *ACL) TemplatedPolicyRead(args *structs.ACLTemplatedPolicyGetRequest, reply *structs.ACLTemplatedPolicyResponse) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.TemplatedPolicyRead", args, reply); done {
+		return err
+	}
+
+	var authzContext acl.AuthorizerContext
+	authz, err := a.srv.ResolveTokenAndDefaultMeta(args.Token, nil, &authzContext)
+	if err != nil {
+		return err
+	} else if err := authz.ToAllowAuthorizer().ACLReadAllowed(&authzContext); err != nil {
+		return err
+	}
+
+	return a.srv.blockingQuery(&args.QueryOptions, &reply.QueryMeta,
+		func(ws memdb.WatchSet, state *state.Store) error {
+			index, templatedPolicy, err := state.ACLTemplatedPolicyGetByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			reply.Index, reply.TemplatedPolicy = index, templatedPolicy
+			if templatedPolicy == nil {
+				return errNotFound
+			}
+			return nil
+		})
+}
+
+func (a *ACL) TemplatedPolicyBatchRead(args *structs.ACLTemplatedPolicyBatchGetRequest, reply *structs.ACLTemplatedPolicyBatchResponse) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.TemplatedPolicyBatchRead", args, reply); done {
+		return err
+	}
+
+	var authzContext acl.AuthorizerContext
+	authz, err := a.srv.ResolveTokenAndDefaultMeta(args.Token, nil, &authzContext)
+	if err != nil {
+		return err
+	} else if err := authz.ToAllowAuthorizer().ACLReadAllowed(&authzContext); err != nil {
+		return err
+	}
+
+	return a.srv.blockingQuery(&args.QueryOptions, &reply.QueryMeta,
+		func(ws memdb.WatchSet, state *state.Store) error {
+			var (
+				index             uint64
+				templatedPolicies structs.ACLTemplatedPolicyDefinitions
+				err               error
+			)
+			if len(args.Names) > 0 {
+				index, templatedPolicies, err = state.ACLTemplatedPolicyBatchGetByName(ws, args.Names)
+			} else {
+				index, templatedPolicies, err = state.ACLTemplatedPolicyBatchGet(ws, args.IDs)
+			}
+			if err != nil {
+				return err
+			}
+
+			reply.Index, reply.TemplatedPolicies = index, templatedPolicies
+			return nil
+		})
+}
+
+func (a *ACL) TemplatedPolicySet(args *structs.ACLTemplatedPolicySetRequest, reply *structs.ACLTemplatedPolicyDefinition) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if !a.srv.InPrimaryDatacenter() {
+		args.Datacenter = a.srv.config.PrimaryDatacenter
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.TemplatedPolicySet", args, reply); done {
+		return err
+	}
+
+	if err := a.srv.requireServersMinimumVersion(minACLTemplatedPolicyVersion, "writing templated policies"); err != nil {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "templated_policy", "upsert"}, time.Now())
+
+	// Verify token is permitted to modify ACLs
+	var authzContext acl.AuthorizerContext
+	if authz, err := a.srv.ResolveTokenAndDefaultMeta(args.Token, nil, &authzContext); err != nil {
+		return err
+	} else if err := authz.ToAllowAuthorizer().ACLWriteAllowed(&authzContext); err != nil {
+		return err
+	}
+
+	templatedPolicy := &args.TemplatedPolicy
+	state := a.srv.fsm.State()
+
+	if err := templatedPolicy.Validate(); err != nil {
+		return err
+	}
+
+	if _, ok := structs.GetACLTemplatedPolicyBase(templatedPolicy.Name); ok {
+		return fmt.Errorf("Invalid Templated Policy: A builtin templated policy with Name %q already exists", templatedPolicy.Name)
 	}
-	_, nameMatch, err = state.ACLPolicyGetByName(nil, policy.Name, &policy.EnterpriseMeta)
+
+	_, nameMatch, err := state.ACLTemplatedPolicyGetByName(nil, templatedPolicy.Name)
 	if err != nil {
-		return fmt.Errorf("acl policy lookup by name failed: %v", err)
+		return fmt.Errorf("acl templated policy lookup by name failed: %v", err)
 	}
 
-	if policy.ID == "" {
-		// with no policy ID one will be generated
-		var err error
-		policy.ID, err = lib.GenerateUUID(a.srv.checkPolicyUUID)
+	if templatedPolicy.ID == "" {
+		// with no ID one will be generated
+		templatedPolicy.ID, err = lib.GenerateUUID(a.srv.checkTemplatedPolicyUUID)
 		if err != nil {
 			return err
 		}
 
 		// validate the name is unique
 		if nameMatch != nil {
-			return fmt.Errorf("Invalid Policy: A Policy with Name %q already exists", policy.Name)
+			return fmt.Errorf("Invalid Templated Policy: A Templated Policy with Name %q already exists", templatedPolicy.Name)
 		}
 	} else {
-		// Verify the policy exists
-		if idMatch == nil {
-			return fmt.Errorf("cannot find policy %s", policy.ID)
+		if _, err := uuid.ParseUUID(templatedPolicy.ID); err != nil {
+			return fmt.Errorf("Templated Policy ID invalid UUID")
 		}
 
-		// Verify that the name isn't changing or that the name is not already used
-		if idMatch.Name != policy.Name && nameMatch != nil {
-			return fmt.Errorf("Invalid Policy: A policy with name %q already exists", policy.Name)
+		_, idMatch, err := state.ACLTemplatedPolicyGetByID(nil, templatedPolicy.ID)
+		if err != nil {
+			return fmt.Errorf("acl templated policy lookup by id failed: %v", err)
 		}
 
-		if builtinPolicy, ok := structs.ACLBuiltinPolicies[policy.ID]; ok {
-			if policy.Datacenters != nil || len(policy.Datacenters) > 0 {
-				return fmt.Errorf("Changing the Datacenters of the %s policy is not permitted", builtinPolicy.Name)
-			}
-
-			if policy.Rules != idMatch.Rules {
-				return fmt.Errorf("Changing the Rules for the builtin %s policy is not permitted", builtinPolicy.Name)
-			}
+		// Verify the templated policy exists
+		if idMatch == nil {
+			return fmt.Errorf("cannot find templated policy %s", templatedPolicy.ID)
 		}
-	}
-
-	// validate the rules
-	_, err = acl.NewPolicyFromSource(policy.Rules, a.srv.aclConfig, policy.EnterprisePolicyMeta())
-	if err != nil {
-		return err
-	}
 
-	// validate the enterprise specific fields
-	if err = a.policyUpsertValidateEnterprise(policy, idMatch); err != nil {
-		return err
+		// Verify that the name isn't changing or that the name is not already used
+		if idMatch.Name != templatedPolicy.Name && nameMatch != nil {
+			return fmt.Errorf("Invalid Templated Policy: A Templated Policy with Name %q already exists", templatedPolicy.Name)
+		}
 	}
 
-	// calculate the hash for this policy
-	policy.SetHash(true)
+	// calculate the hash for this templated policy
+	templatedPolicy.SetHash(true)
 
-	req := &structs.ACLPolicyBatchSetRequest{
-		Policies: structs.ACLPolicies{policy},
+	req := &structs.ACLTemplatedPolicyBatchSetRequest{
+		TemplatedPolicies: structs.ACLTemplatedPolicyDefinitions{templatedPolicy},
 	}
 
-	_, err = a.srv.raftApply(structs.ACLPolicySetRequestType, req)
-	if err != nil {
-		return fmt.Errorf("Failed to apply policy upsert request: %v", err)
+	if _, err := a.srv.raftApply(structs.ACLTemplatedPolicySetRequestType, req); err != nil {
+		return fmt.Errorf("Failed to apply templated policy upsert request: %v", err)
 	}
 
 	// Remove from the cache to prevent stale cache usage
-	a.srv.ACLResolver.cache.RemovePolicy(policy.ID)
+	a.srv.ACLResolver.cache.RemoveTemplatedPolicy(templatedPolicy.Name)
 
-	if _, policy, err := a.srv.fsm.State().ACLPolicyGetByID(nil, policy.ID, &policy.EnterpriseMeta); err == nil && policy != nil {
-		*reply = *policy
+	if _, templatedPolicy, err := state.ACLTemplatedPolicyGetByID(nil, templatedPolicy.ID); err == nil && templatedPolicy != nil {
+		*reply = *templatedPolicy
 	}
 
 	return nil
 }
 
-func (a *ACL) PolicyDelete(args *structs.ACLPolicyDeleteRequest, reply *string) error {
+func (a *ACL) TemplatedPolicyDelete(args *structs.ACLTemplatedPolicyDeleteRequest, reply *string) error {
 	if err := a.aclPreCheck(); err != nil {
 		return err
 	}
 
-	if err := a.srv.validateEnterpriseRequest(&args.EnterpriseMeta, true); err != nil {
-		return err
-	}
-
 	if !a.srv.InPrimaryDatacenter() {
 		args.Datacenter = a.srv.config.PrimaryDatacenter
 	}
 
-	if done, err := a.srv.ForwardRPC("ACL.PolicyDelete", args, reply); done {
+	if done, err := a.srv.ForwardRPC("ACL.TemplatedPolicyDelete", args, reply); done {
 		return err
 	}
 
-	defer metrics.MeasureSince([]string{"acl", "policy", "delete"}, time.Now())
+	if err := a.srv.requireServersMinimumVersion(minACLTemplatedPolicyVersion, "writing templated policies"); err != nil {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "templated_policy", "delete"}, time.Now())
 
 	// Verify token is permitted to modify ACLs
 	var authzContext acl.AuthorizerContext
-
-	if authz, err := a.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, &authzContext); err != nil {
+	if authz, err := a.srv.ResolveTokenAndDefaultMeta(args.Token, nil, &authzContext); err != nil {
 		return err
 	} else if err := authz.ToAllowAuthorizer().ACLWriteAllowed(&authzContext); err != nil {
 		return err
 	}
 
-	_, policy, err := a.srv.fsm.State().ACLPolicyGetByID(nil, args.PolicyID, &args.EnterpriseMeta)
-	if err != nil {
-		return err
+	if _, ok := structs.GetACLTemplatedPolicyBase(args.Name); ok {
+		return fmt.Errorf("Delete operation not permitted on the builtin %s templated policy", args.Name)
 	}
 
-	if policy == nil {
-		if ns := args.EnterpriseMeta.NamespaceOrEmpty(); ns != "" {
-			return fmt.Errorf("policy not found in namespace %s: %w", ns, acl.ErrNotFound)
-		}
-		return fmt.Errorf("policy does not exist: %w", acl.ErrNotFound)
+	_, templatedPolicy, err := a.srv.fsm.State().ACLTemplatedPolicyGetByName(nil, args.Name)
+	if err != nil {
+		return err
 	}
 
-	if builtinPolicy, ok := structs.ACLBuiltinPolicies[policy.ID]; ok {
-		return fmt.Errorf("Delete operation not permitted on the builtin %s policy", builtinPolicy.Name)
+	if templatedPolicy == nil {
+		return fmt.Errorf("templated policy does not exist: %w", acl.ErrNotFound)
 	}
 
-	req := structs.ACLPolicyBatchDeleteRequest{
-		PolicyIDs: []string{args.PolicyID},
+	req := structs.ACLTemplatedPolicyBatchDeleteRequest{
+		TemplatedPolicyIDs: []string{templatedPolicy.ID},
 	}
 
-	_, err = a.srv.raftApply(structs.ACLPolicyDeleteRequestType, &req)
-	if err != nil {
-		return fmt.Errorf("Failed to apply policy delete request: %v", err)
+	if _, err := a.srv.raftApply(structs.ACLTemplatedPolicyDeleteRequestType, &req); err != nil {
+		return fmt.Errorf("Failed to apply templated policy delete request: %v", err)
 	}
 
-	a.srv.ACLResolver.cache.RemovePolicy(policy.ID)
+	a.srv.ACLResolver.cache.RemoveTemplatedPolicy(templatedPolicy.Name)
 
-	*reply = policy.Name
+	*reply = templatedPolicy.Name
 
 	return nil
 }
 
-func (a *ACL) PolicyList(args *structs.ACLPolicyListRequest, reply *structs.ACLPolicyListResponse) error {
+func (a *ACL) TemplatedPolicyList(args *structs.ACLTemplatedPolicyListRequest, reply *structs.ACLTemplatedPolicyListResponse) error {
 	if err := a.aclPreCheck(); err != nil {
 		return err
 	}
 
-	if err := a.srv.validateEnterpriseRequest(&args.EnterpriseMeta, false); err != nil {
-		return err
-	}
-
-	if done, err := a.srv.ForwardRPC("ACL.PolicyList", args, reply); done {
+	if done, err := a.srv.ForwardRPC("ACL.TemplatedPolicyList", args, reply); done {
 		return err
 	}
 
 	var authzContext acl.AuthorizerContext
-
-	authz, err := a.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, &authzContext)
+	authz, err := a.srv.ResolveTokenAndDefaultMeta(args.Token, nil, &authzContext)
 	if err != nil {
 		return err
 	} else if err := authz.ToAllowAuthorizer().ACLReadAllowed(&authzContext); err != nil {
@@ -1074,76 +1726,59 @@ func (a *ACL) PolicyList(args *structs.ACLPolicyListRequest, reply *structs.ACLP
 
 	return a.srv.blockingQuery(&args.QueryOptions, &reply.QueryMeta,
 		func(ws memdb.WatchSet, state *state.Store) error {
-			index, policies, err := state.ACLPolicyList(ws, &args.EnterpriseMeta)
+			index, templatedPolicies, err := state.ACLTemplatedPolicyList(ws)
 			if err != nil {
 				return err
 			}
 
-			// filter down to just what the requester has permissions to see
-			a.srv.filterACLWithAuthorizer(authz, &policies)
-
-			var stubs structs.ACLPolicyListStubs
-			for _, policy := range policies {
-				stubs = append(stubs, policy.Stub())
-			}
-
-			reply.Index, reply.Policies = index, stubs
+			reply.Index, reply.TemplatedPolicies = index, templatedPolicies
 			return nil
 		})
 }
 
-// PolicyResolve is used to retrieve a subset of the policies associated with a given token
-// The policy ids in the args simply act as a filter on the policy set assigned to the token
-func (a *ACL) PolicyResolve(args *structs.ACLPolicyBatchGetRequest, reply *structs.ACLPolicyBatchResponse) error {
+// TemplatedPolicyResolve is used to retrieve the user-defined templated policies
+// referenced by a given token or its roles. The names in the args simply act as
+// a filter on the templated policies of the token.
+func (a *ACL) TemplatedPolicyResolve(args *structs.ACLTemplatedPolicyBatchGetRequest, reply *structs.ACLTemplatedPolicyBatchResponse) error {
 	if err := a.aclPreCheck(); err != nil {
 		return err
 	}
 
-	if done, err := a.srv.ForwardRPC("ACL.PolicyResolve", args, reply); done {
-		return err
-	}
-
-	// get full list of policies for this token
-	identity, policies, err := a.srv.ACLResolver.resolveTokenToIdentityAndPolicies(args.Token)
-	if err != nil {
+	if done, err := a.srv.ForwardRPC("ACL.TemplatedPolicyResolve", args, reply); done {
 		return err
 	}
 
-	entIdentity, entPolicies, err := a.srv.ACLResolver.resolveEnterpriseIdentityAndPolicies(identity)
+	identity, roles, err := a.srv.ACLResolver.resolveTokenToIdentityAndRoles(args.Token)
 	if err != nil {
 		return err
 	}
 
-	idMap := make(map[string]*structs.ACLPolicy)
-	for _, policyID := range identity.PolicyIDs() {
-		idMap[policyID] = nil
-	}
-	if entIdentity != nil {
-		for _, policyID := range entIdentity.PolicyIDs() {
-			idMap[policyID] = nil
+	referenced := make(map[string]struct{})
+	if identity != nil {
+		for _, tp := range identity.TemplatedPolicyList() {
+			referenced[tp.TemplateName] = struct{}{}
 		}
 	}
-
-	for _, policy := range policies {
-		idMap[policy.ID] = policy
-	}
-	for _, policy := range entPolicies {
-		idMap[policy.ID] = policy
+	for _, role := range roles {
+		for _, tp := range role.TemplatedPolicies {
+			referenced[tp.TemplateName] = struct{}{}
+		}
 	}
 
-	for _, policyID := range args.PolicyIDs {
-		if policy, ok := idMap[policyID]; ok {
-			// only add non-deleted policies
-			if policy != nil {
-				reply.Policies = append(reply.Policies, policy)
-			}
-		} else {
+	for _, name := range args.Names {
+		if _, ok := referenced[name]; !ok {
 			// send a permission denied to indicate that the request included
-			// policy ids not associated with this token
+			// templated policies not associated with this token
 			return acl.ErrPermissionDenied
 		}
 	}
 
+	index, templatedPolicies, err := a.srv.fsm.State().ACLTemplatedPolicyBatchGetByName(nil, args.Names)
+	if err != nil {
+		return err
+	}
+	reply.Index, reply.TemplatedPolicies = index, templatedPolicies
+
 	a.srv.SetQueryMeta(&reply.QueryMeta, args.Token)
 
 	return nil
@@ -1390,8 +2025,11 @@ func (a *ACL) RoleSet(args *structs.ACLRoleSetRequest, reply *structs.ACLRole) e
 			return fmt.Errorf("templated policy is missing the template name field on this role")
 		}
 
-		baseTemplate, ok := structs.GetACLTemplatedPolicyBase(templatedPolicy.TemplateName)
-		if !ok {
+		_, baseTemplate, err := state.ACLTemplatedPolicyBaseGetByName(nil, templatedPolicy.TemplateName)
+		if err != nil {
+			return fmt.Errorf("acl templated policy lookup failed: %v", err)
+		}
+		if baseTemplate == nil {
 			return fmt.Errorf("templated policy with an invalid templated name: %s for this role", templatedPolicy.TemplateName)
 		}
 
@@ -1399,8 +2037,7 @@ func (a *ACL) RoleSet(args *structs.ACLRoleSetRequest, reply *structs.ACLRole) e
 			templatedPolicy.TemplateID = baseTemplate.TemplateID
 		}
 
-		err := templatedPolicy.ValidateTemplatedPolicy(baseTemplate.Schema)
-		if err != nil {
+		if err := templatedPolicy.ValidateTemplatedPolicy(baseTemplate.Schema); err != nil {
 			return fmt.Errorf("encountered role with invalid templated policy: %w", err)
 		}
 	}
@@ -1723,7 +2360,7 @@ func (a *ACL) BindingRuleSet(args *structs.ACLBindingRuleSetRequest, reply *stru
 		return fmt.Errorf("invalid Binding Rule: BindVars cannot be set when bind type is not templated-policy.")
 	}
 
-	if err := auth.IsValidBindingRule(rule.BindType, rule.BindName, rule.BindVars, blankID.ProjectedVarNames()); err != nil {
+	if err := auth.IsValidBindingRule(a.srv.fsm.State(), rule.BindType, rule.BindName, rule.BindVars, blankID.ProjectedVarNames()); err != nil {
 		return fmt.Errorf("Invalid Binding Rule: invalid BindName or BindVars: %w", err)
 	}
 
@@ -2145,6 +2782,155 @@ func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLToken) erro
 	return err
 }
 
+// oidcAuthState is stored by the auth method validator between the two
+// halves of the OIDC authorization code flow.
+type oidcAuthState struct {
+	ClientNonce string
+	Meta        map[string]string
+}
+
+// loadOIDCValidator loads the named auth method and ensures that it supports
+// the OIDC authorization code flow.
+func (a *ACL) loadOIDCValidator(methodName string, entMeta *acl.EnterpriseMeta) (*structs.ACLAuthMethod, authmethod.OIDCValidator, error) {
+	authMethod, validator, err := a.srv.loadAuthMethod(methodName, entMeta)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	oidcValidator, ok := validator.(authmethod.OIDCValidator)
+	if !ok || authMethod.Type != "oidc" {
+		return nil, nil, fmt.Errorf("auth method %q is not of type %q", methodName, "oidc")
+	}
+	return authMethod, oidcValidator, nil
+}
+
+// OIDCAuthURL starts an OIDC authorization code flow and returns the provider
+// URL the user should visit to authenticate.
+func (a *ACL) OIDCAuthURL(args *structs.ACLOIDCAuthURLRequest, reply *string) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if !a.srv.LocalTokensEnabled() {
+		return errAuthMethodsRequireTokenReplication
+	}
+
+	if args.Auth == nil {
+		return fmt.Errorf("Invalid OIDC auth URL request: Missing auth parameters")
+	}
+
+	// The nonce is what binds the callback to this client, an empty one would
+	// match any callback that also omits it.
+	if args.Auth.ClientNonce == "" {
+		return fmt.Errorf("Invalid OIDC auth URL request: Missing client nonce")
+	}
+
+	if err := a.srv.validateEnterpriseRequest(&args.Auth.EnterpriseMeta, true); err != nil {
+		return err
+	}
+
+	if args.Token != "" { // This shouldn't happen.
+		return errors.New("do not provide a token when logging in")
+	}
+
+	// The flow's state is held in memory by the leader's validator, so both
+	// halves must be handled by the leader.
+	if done, err := a.srv.ForwardRPC("ACL.OIDCAuthURL", args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "oidc", "auth_url"}, time.Now())
+
+	_, validator, err := a.loadOIDCValidator(args.Auth.AuthMethod, &args.Auth.EnterpriseMeta)
+	if err != nil {
+		return err
+	}
+
+	// Build the description now so that invalid metadata is rejected before
+	// the user is sent to the provider.
+	if _, err := auth.BuildTokenDescription("token created via OIDC login", args.Auth.Meta); err != nil {
+		return err
+	}
+
+	state := &oidcAuthState{
+		ClientNonce: args.Auth.ClientNonce,
+		Meta:        args.Auth.Meta,
+	}
+	authURL, err := validator.GetAuthCodeURL(context.Background(), args.Auth.RedirectURI, state)
+	if err != nil {
+		return err
+	}
+
+	*reply = authURL
+	return nil
+}
+
+// OIDCCallback completes an OIDC authorization code flow started with
+// OIDCAuthURL and returns a token for the verified identity.
+func (a *ACL) OIDCCallback(args *structs.ACLOIDCCallbackRequest, reply *structs.ACLToken) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if !a.srv.LocalTokensEnabled() {
+		return errAuthMethodsRequireTokenReplication
+	}
+
+	if args.Auth == nil {
+		return fmt.Errorf("Invalid OIDC callback request: Missing auth parameters")
+	}
+
+	if args.Auth.ClientNonce == "" {
+		return fmt.Errorf("Invalid OIDC callback request: Missing client nonce")
+	}
+
+	if err := a.srv.validateEnterpriseRequest(&args.Auth.EnterpriseMeta, true); err != nil {
+		return err
+	}
+
+	if args.Token != "" { // This shouldn't happen.
+		return errors.New("do not provide a token when logging in")
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.OIDCCallback", args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "oidc", "callback"}, time.Now())
+
+	authMethod, validator, err := a.loadOIDCValidator(args.Auth.AuthMethod, &args.Auth.EnterpriseMeta)
+	if err != nil {
+		return err
+	}
+
+	verifiedIdentity, payload, err := validator.ValidateAuthCode(context.Background(), args.Auth.State, args.Auth.Code)
+	if err != nil {
+		return err
+	}
+
+	state, ok := payload.(*oidcAuthState)
+	if !ok {
+		return fmt.Errorf("unexpected OIDC state payload %T", payload)
+	}
+
+	// The client nonce ties the callback to the client that requested the
+	// auth URL, so that an intercepted code cannot be redeemed elsewhere.
+	if subtle.ConstantTimeCompare([]byte(state.ClientNonce), []byte(args.Auth.ClientNonce)) != 1 {
+		return errors.New("invalid client nonce")
+	}
+
+	description, err := auth.BuildTokenDescription("token created via OIDC login", state.Meta)
+	if err != nil {
+		return err
+	}
+
+	token, err := a.srv.aclLogin().TokenForVerifiedIdentity(verifiedIdentity, authMethod, description)
+	if err == nil {
+		*reply = *token
+	}
+	return err
+}
+
 func (a *ACL) Logout(args *structs.ACLLogoutRequest, reply *bool) error {
 	if err := a.aclPreCheck(); err != nil {
 		return err
@@ -2201,3 +2987,33 @@ func (a *ACL) Authorize(args *structs.RemoteACLAuthorizationRequest, reply *[]st
 	*reply = responses
 	return nil
 }
+
+// AuthorizeExplain returns the decision for each of the authorization
+// requests, along with the policy rule or default that produced it.
+func (a *ACL) AuthorizeExplain(args *structs.RemoteACLAuthorizationRequest, reply *[]structs.ACLAuthorizationExplanation) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.AuthorizeExplain", args, reply); done {
+		return err
+	}
+
+	explanations, err := a.srv.ACLResolver.ExplainToken(args.Token, args.Requests)
+	if err != nil {
+		return err
+	}
+
+	*reply = explanations
+	return nil
+}
+
+// requireServersMinimumVersion returns an error unless all the servers in this
+// datacenter run at least minVersion. It keeps writes with new Raft message
+// types from reaching servers that are not able to apply them.
+func (s *Server) requireServersMinimumVersion(minVersion *version.Version, operation string) error {
+	if ok, _ := ServersInDCMeetMinimumVersion(s, s.config.Datacenter, minVersion); !ok {
+		return fmt.Errorf("all servers must be running at least Consul %s before %s", minVersion, operation)
+	}
+	return nil
+}
@@ -4,6 +4,9 @@
 package consul
 
 import (
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -19,6 +22,7 @@ import (
 	"github.com/hashicorp/consul-net-rpc/net/rpc"
 
 	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/consul/authmethod/certauth"
 	"github.com/hashicorp/consul/agent/consul/authmethod/kubeauth"
 	"github.com/hashicorp/consul/agent/consul/authmethod/testauth"
 	"github.com/hashicorp/consul/agent/structs"
@@ -545,6 +549,396 @@ func TestACLEndpoint_TokenClone(t *testing.T) {
 	})
 }
 
+func TestACLEndpoint_TokenRotate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, nil, false)
+	waitForLeaderEstablishment(t, srv)
+
+	p1, err := upsertTestPolicy(codec, TestDefaultInitialManagementToken, "dc1")
+	require.NoError(t, err)
+
+	t1, err := upsertTestToken(codec, TestDefaultInitialManagementToken, "dc1", func(t *structs.ACLToken) {
+		t.Policies = []structs.ACLTokenPolicyLink{
+			{ID: p1.ID},
+		}
+	})
+	require.NoError(t, err)
+
+	endpoint := ACL{srv: srv, logger: srv.logger}
+
+	rotate := func(t *testing.T, accessorID string, gracePeriod time.Duration) (*structs.ACLToken, error) {
+		req := structs.ACLTokenRotateRequest{
+			Datacenter:   "dc1",
+			AccessorID:   accessorID,
+			GracePeriod:  gracePeriod,
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		var out structs.ACLToken
+		if err := endpoint.TokenRotate(&req, &out); err != nil {
+			return nil, err
+		}
+		return &out, nil
+	}
+
+	renew := func(t *testing.T, secretID string) (*structs.ACLTokenRenewResponse, error) {
+		req := structs.ACLTokenRenewRequest{
+			Datacenter:   "dc1",
+			QueryOptions: structs.QueryOptions{Token: secretID},
+		}
+		var out structs.ACLTokenRenewResponse
+		if err := endpoint.TokenRenew(&req, &out); err != nil {
+			return nil, err
+		}
+		return &out, nil
+	}
+
+	var t2 *structs.ACLToken
+	t.Run("grace period", func(t *testing.T) {
+		t2, err = rotate(t, t1.AccessorID, time.Hour)
+		require.NoError(t, err)
+
+		require.Equal(t, t1.AccessorID, t2.AccessorID)
+		require.NotEqual(t, t1.SecretID, t2.SecretID)
+		require.Equal(t, t1.SecretID, t2.PreviousSecretID)
+		require.Equal(t, t1.Policies, t2.Policies)
+		require.NotNil(t, t2.RotationTime)
+		require.NotNil(t, t2.PreviousSecretExpirationTime)
+
+		// Both secrets resolve to the same token.
+		for _, secretID := range []string{t1.SecretID, t2.SecretID} {
+			authz, err := srv.ACLResolver.ResolveToken(secretID)
+			require.NoError(t, err)
+			require.Equal(t, t1.AccessorID, authz.AccessorID())
+		}
+
+		out, err := renew(t, t1.SecretID)
+		require.NoError(t, err)
+		require.True(t, out.Rotated)
+		require.Equal(t, t2.SecretID, out.SecretID)
+		require.NotNil(t, out.PreviousSecretExpirationTime)
+
+		out, err = renew(t, t2.SecretID)
+		require.NoError(t, err)
+		require.False(t, out.Rotated)
+		require.Equal(t, t2.SecretID, out.SecretID)
+	})
+
+	t.Run("no grace period", func(t *testing.T) {
+		t3, err := rotate(t, t2.AccessorID, 0)
+		require.NoError(t, err)
+		require.NotEqual(t, t2.SecretID, t3.SecretID)
+		require.Empty(t, t3.PreviousSecretID)
+		require.Nil(t, t3.PreviousSecretExpirationTime)
+
+		for _, secretID := range []string{t1.SecretID, t2.SecretID} {
+			_, err := srv.ACLResolver.ResolveToken(secretID)
+			require.True(t, acl.IsErrNotFound(err), "unexpected error: %v", err)
+
+			_, err = renew(t, secretID)
+			require.True(t, acl.IsErrNotFound(err), "unexpected error: %v", err)
+		}
+
+		authz, err := srv.ACLResolver.ResolveToken(t3.SecretID)
+		require.NoError(t, err)
+		require.Equal(t, t1.AccessorID, authz.AccessorID())
+	})
+
+	t.Run("negative grace period", func(t *testing.T) {
+		_, err := rotate(t, t1.AccessorID, -time.Second)
+		require.ErrorContains(t, err, "should be >= 0")
+	})
+
+	t.Run("anonymous token", func(t *testing.T) {
+		_, err := rotate(t, acl.AnonymousTokenID, time.Hour)
+		require.ErrorContains(t, err, "Cannot rotate the anonymous token")
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		_, err := rotate(t, "3ee60b2a-6fd0-4a4b-a6a1-2b93a0a4d0a8", time.Hour)
+		require.True(t, acl.IsErrNotFound(err), "unexpected error: %v", err)
+	})
+}
+
+func TestACLEndpoint_TokenRotate_MinimumVersion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, func(c *Config) {
+		c.Build = "1.16.0"
+	}, false)
+	waitForLeaderEstablishment(t, srv)
+
+	t1, err := upsertTestToken(codec, TestDefaultInitialManagementToken, "dc1", nil)
+	require.NoError(t, err)
+
+	endpoint := ACL{srv: srv, logger: srv.logger}
+	req := structs.ACLTokenRotateRequest{
+		Datacenter:   "dc1",
+		AccessorID:   t1.AccessorID,
+		GracePeriod:  time.Minute,
+		WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+	}
+	var out structs.ACLToken
+	err = endpoint.TokenRotate(&req, &out)
+	require.ErrorContains(t, err, "all servers must be running at least Consul 1.17.0 before rotating tokens")
+
+	_, token, err := srv.fsm.State().ACLTokenGetByAccessor(nil, t1.AccessorID, nil)
+	require.NoError(t, err)
+	require.Equal(t, t1.SecretID, token.SecretID)
+}
+
+func TestACLEndpoint_TokenLineage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, nil, false)
+	waitForLeaderEstablishment(t, srv)
+
+	endpoint := ACL{srv: srv, logger: srv.logger}
+
+	testSessionID := testauth.StartSession()
+	defer testauth.ResetSession(testSessionID)
+
+	testauth.InstallSessionToken(
+		testSessionID,
+		"fake-db",
+		"default", "db", "def456",
+	)
+
+	method, err := upsertTestAuthMethod(codec, TestDefaultInitialManagementToken, "dc1", testSessionID)
+	require.NoError(t, err)
+
+	rule, err := upsertTestBindingRule(
+		codec, TestDefaultInitialManagementToken, "dc1", method.Name,
+		"serviceaccount.namespace==default and serviceaccount.name==db",
+		structs.BindingRuleBindTypeService,
+		"method-${serviceaccount.name}",
+	)
+	require.NoError(t, err)
+
+	loginReq := structs.ACLLoginRequest{
+		Auth: &structs.ACLLoginParams{
+			AuthMethod:  method.Name,
+			BearerToken: "fake-db",
+		},
+		Datacenter: "dc1",
+	}
+	var login structs.ACLToken
+	require.NoError(t, endpoint.Login(&loginReq, &login))
+
+	require.Equal(t, &structs.ACLTokenLineage{
+		BindingRuleIDs: []string{rule.ID},
+		SelectableFields: map[string]string{
+			"serviceaccount.namespace": "default",
+			"serviceaccount.name":      "db",
+			"serviceaccount.uid":       structs.RedactedSelectableField,
+		},
+	}, login.Lineage)
+
+	lineage := func(t *testing.T, token, accessorID string) (*structs.ACLTokenLineageResponse, error) {
+		req := structs.ACLTokenGetRequest{
+			Datacenter:   "dc1",
+			TokenID:      accessorID,
+			TokenIDType:  structs.ACLTokenAccessor,
+			QueryOptions: structs.QueryOptions{Token: token},
+		}
+		var out structs.ACLTokenLineageResponse
+		if err := endpoint.TokenLineage(&req, &out); err != nil {
+			return nil, err
+		}
+		return &out, nil
+	}
+
+	t.Run("login token", func(t *testing.T) {
+		out, err := lineage(t, TestDefaultInitialManagementToken, login.AccessorID)
+		require.NoError(t, err)
+
+		require.Equal(t, login.AccessorID, out.Token.AccessorID)
+		require.False(t, out.Redacted)
+		require.Equal(t, method.Name, out.IssuedBy.Name)
+		require.Len(t, out.BindingRules, 1)
+		require.Equal(t, rule.ID, out.BindingRules[0].ID)
+		require.Empty(t, out.MissingBindingRuleIDs)
+		require.Len(t, out.ExpandedPolicies, 1)
+		require.Equal(t, "method-db", out.Token.ServiceIdentities[0].ServiceName)
+	})
+
+	t.Run("not a login token", func(t *testing.T) {
+		token, err := upsertTestToken(codec, TestDefaultInitialManagementToken, "dc1", nil)
+		require.NoError(t, err)
+
+		_, err = lineage(t, TestDefaultInitialManagementToken, token.AccessorID)
+		require.ErrorIs(t, err, acl.ErrNotFound)
+		require.Contains(t, err.Error(), "was not issued by an auth method")
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		_, err := lineage(t, TestDefaultInitialManagementToken, "cc61bd64-a1f6-4f16-9b8c-1cfdc7e2d4a8")
+		require.ErrorIs(t, err, acl.ErrNotFound)
+	})
+
+	t.Run("acl read is required", func(t *testing.T) {
+		token, err := upsertTestTokenWithPolicyRules(codec, TestDefaultInitialManagementToken, "dc1", `acl = "read"`)
+		require.NoError(t, err)
+
+		out, err := lineage(t, token.SecretID, login.AccessorID)
+		require.NoError(t, err)
+		require.True(t, out.Redacted)
+		require.Equal(t, aclfilter.RedactedToken, out.Token.SecretID)
+
+		token, err = upsertTestTokenWithPolicyRules(codec, TestDefaultInitialManagementToken, "dc1", `node_prefix "" { policy = "read" }`)
+		require.NoError(t, err)
+
+		_, err = lineage(t, token.SecretID, login.AccessorID)
+		require.True(t, acl.IsErrPermissionDenied(err), "unexpected error: %v", err)
+	})
+
+	t.Run("deleted binding rule", func(t *testing.T) {
+		req := structs.ACLBindingRuleDeleteRequest{
+			Datacenter:    "dc1",
+			BindingRuleID: rule.ID,
+			WriteRequest:  structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		var ignored bool
+		require.NoError(t, endpoint.BindingRuleDelete(&req, &ignored))
+
+		out, err := lineage(t, TestDefaultInitialManagementToken, login.AccessorID)
+		require.NoError(t, err)
+		require.Empty(t, out.BindingRules)
+		require.Equal(t, []string{rule.ID}, out.MissingBindingRuleIDs)
+	})
+}
+
+func TestACLEndpoint_TokenBreakGlass(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, func(c *Config) {
+		c.ACLTokenMinExpirationTTL = 10 * time.Millisecond
+		c.ACLBreakGlassMaxTTL = time.Hour
+	}, false)
+	waitForLeaderEstablishment(t, srv)
+
+	endpoint := ACL{srv: srv, logger: srv.logger}
+
+	requester, err := upsertTestTokenWithPolicyRules(codec, TestDefaultInitialManagementToken, "dc1", `node_prefix "" { policy = "read" }`)
+	require.NoError(t, err)
+
+	approver, err := upsertTestTokenWithPolicyRules(codec, TestDefaultInitialManagementToken, "dc1", `acl = "write"`)
+	require.NoError(t, err)
+
+	breakGlass := func(token, approverToken, reason string, ttl time.Duration) (*structs.ACLToken, error) {
+		req := structs.ACLTokenBreakGlassRequest{
+			Datacenter:    "dc1",
+			Reason:        reason,
+			TTL:           ttl,
+			ApproverToken: approverToken,
+			WriteRequest:  structs.WriteRequest{Token: token},
+		}
+		var out structs.ACLToken
+		if err := endpoint.TokenBreakGlass(&req, &out); err != nil {
+			return nil, err
+		}
+		return &out, nil
+	}
+
+	var issued *structs.ACLToken
+	t.Run("issue", func(t *testing.T) {
+		issued, err = breakGlass(requester.SecretID, approver.SecretID, "primary is on fire", 10*time.Minute)
+		require.NoError(t, err)
+
+		require.True(t, issued.Local)
+		require.Equal(t, []structs.ACLTokenPolicyLink{{
+			ID:   structs.ACLPolicyGlobalManagementID,
+			Name: "global-management",
+		}}, issued.Policies)
+		require.Equal(t, &structs.ACLTokenBreakGlass{
+			Reason:      "primary is on fire",
+			RequestedBy: requester.AccessorID,
+			ApprovedBy:  approver.AccessorID,
+		}, issued.BreakGlass)
+		require.NotNil(t, issued.ExpirationTime)
+		require.WithinDuration(t, issued.CreateTime.Add(10*time.Minute), *issued.ExpirationTime, time.Second)
+
+		_, stored, err := srv.fsm.State().ACLTokenGetByAccessor(nil, issued.AccessorID, nil)
+		require.NoError(t, err)
+		require.Equal(t, issued.BreakGlass, stored.BreakGlass)
+	})
+
+	t.Run("reason is required", func(t *testing.T) {
+		_, err := breakGlass(requester.SecretID, approver.SecretID, "", 10*time.Minute)
+		require.ErrorContains(t, err, "A reason is required for break-glass tokens")
+	})
+
+	t.Run("approver is required", func(t *testing.T) {
+		_, err := breakGlass(requester.SecretID, "", "reason", 10*time.Minute)
+		require.ErrorContains(t, err, "Break-glass tokens require an approver token")
+	})
+
+	t.Run("requester cannot approve", func(t *testing.T) {
+		_, err := breakGlass(approver.SecretID, approver.SecretID, "reason", 10*time.Minute)
+		require.True(t, acl.IsErrPermissionDenied(err), "unexpected error: %v", err)
+	})
+
+	t.Run("approver needs acl write", func(t *testing.T) {
+		_, err := breakGlass(approver.SecretID, requester.SecretID, "reason", 10*time.Minute)
+		require.True(t, acl.IsErrPermissionDenied(err), "unexpected error: %v", err)
+	})
+
+	t.Run("anonymous requester", func(t *testing.T) {
+		_, err := breakGlass("", approver.SecretID, "reason", 10*time.Minute)
+		require.True(t, acl.IsErrPermissionDenied(err), "unexpected error: %v", err)
+	})
+
+	t.Run("ttl is capped", func(t *testing.T) {
+		_, err := breakGlass(requester.SecretID, approver.SecretID, "reason", 2*time.Hour)
+		require.ErrorContains(t, err, "Break-glass token TTL cannot be more than")
+	})
+
+	t.Run("list", func(t *testing.T) {
+		req := structs.ACLTokenListRequest{
+			Datacenter:   "dc1",
+			IncludeLocal: true,
+			BreakGlass:   true,
+			QueryOptions: structs.QueryOptions{Token: TestDefaultInitialManagementToken},
+		}
+		var out structs.ACLTokenListResponse
+		require.NoError(t, endpoint.TokenList(&req, &out))
+		require.Len(t, out.Tokens, 1)
+		require.Equal(t, issued.AccessorID, out.Tokens[0].AccessorID)
+		require.Equal(t, issued.BreakGlass, out.Tokens[0].BreakGlass)
+	})
+
+	t.Run("expired tokens are reaped", func(t *testing.T) {
+		short, err := breakGlass(requester.SecretID, approver.SecretID, "short", time.Second)
+		require.NoError(t, err)
+
+		time.Sleep(time.Until(*short.ExpirationTime) + 100*time.Millisecond)
+
+		n, err := srv.reapExpiredACLTokens(true, false)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		_, stored, err := srv.fsm.State().ACLTokenGetByAccessor(nil, short.AccessorID, nil)
+		require.NoError(t, err)
+		require.Nil(t, stored)
+	})
+}
+
 func TestACLEndpoint_TokenSet(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -2398,69 +2792,263 @@ func TestACLEndpoint_PolicyList(t *testing.T) {
 		QueryOptions: structs.QueryOptions{Token: TestDefaultInitialManagementToken},
 	}
 
-	resp := structs.ACLPolicyListResponse{}
+	resp := structs.ACLPolicyListResponse{}
+
+	err = aclEp.PolicyList(&req, &resp)
+	require.NoError(t, err)
+
+	policies := []string{
+		structs.ACLPolicyGlobalManagementID,
+		structs.ACLPolicyGlobalReadOnlyID,
+		p1.ID,
+		p2.ID,
+	}
+	require.ElementsMatch(t, gatherIDs(t, resp.Policies), policies)
+}
+
+func TestACLEndpoint_PolicyResolve(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, nil, false)
+	waitForLeaderEstablishment(t, srv)
+
+	p1, err := upsertTestPolicy(codec, TestDefaultInitialManagementToken, "dc1")
+	require.NoError(t, err)
+
+	p2, err := upsertTestPolicy(codec, TestDefaultInitialManagementToken, "dc1")
+	require.NoError(t, err)
+
+	aclEp := ACL{srv: srv}
+
+	policies := []string{p1.ID, p2.ID}
+
+	// Assign the policies to a token
+	tokenUpsertReq := structs.ACLTokenSetRequest{
+		Datacenter: "dc1",
+		ACLToken: structs.ACLToken{
+			Policies: []structs.ACLTokenPolicyLink{
+				{
+					ID: p1.ID,
+				},
+				{
+					ID: p2.ID,
+				},
+			},
+		},
+		WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+	}
+	token := structs.ACLToken{}
+	err = aclEp.TokenSet(&tokenUpsertReq, &token)
+	require.NoError(t, err)
+	require.NotEmpty(t, token.SecretID)
+
+	resp := structs.ACLPolicyBatchResponse{}
+	req := structs.ACLPolicyBatchGetRequest{
+		Datacenter:   "dc1",
+		PolicyIDs:    []string{p1.ID, p2.ID},
+		QueryOptions: structs.QueryOptions{Token: token.SecretID},
+	}
+	err = aclEp.PolicyResolve(&req, &resp)
+	require.NoError(t, err)
+	require.ElementsMatch(t, gatherIDs(t, resp.Policies), policies)
+}
+
+func TestACLEndpoint_TemplatedPolicySet(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, nil, false)
+	waitForLeaderEstablishment(t, srv)
+	aclEp := ACL{srv: srv}
+
+	schema := `{"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"]}`
+
+	var templatedPolicyID string
+
+	t.Run("Create it", func(t *testing.T) {
+		req := structs.ACLTemplatedPolicySetRequest{
+			Datacenter: "dc1",
+			TemplatedPolicy: structs.ACLTemplatedPolicyDefinition{
+				Name:        "team-kv-namespace",
+				Description: "foobar",
+				Schema:      schema,
+				Template:    `key_prefix "teams/{{.Name}}/" { policy = "write" }`,
+			},
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		resp := structs.ACLTemplatedPolicyDefinition{}
+
+		require.NoError(t, aclEp.TemplatedPolicySet(&req, &resp))
+		require.NotEmpty(t, resp.ID)
+		require.NotEmpty(t, resp.Hash)
+
+		readReq := structs.ACLTemplatedPolicyGetRequest{
+			Datacenter:   "dc1",
+			Name:         "team-kv-namespace",
+			QueryOptions: structs.QueryOptions{Token: TestDefaultInitialManagementToken},
+		}
+		var readResp structs.ACLTemplatedPolicyResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.TemplatedPolicyRead", &readReq, &readResp))
+		require.NotNil(t, readResp.TemplatedPolicy)
+		require.Equal(t, resp.ID, readResp.TemplatedPolicy.ID)
+		require.Equal(t, "foobar", readResp.TemplatedPolicy.Description)
+
+		templatedPolicyID = resp.ID
+	})
+
+	t.Run("Name Dup", func(t *testing.T) {
+		req := structs.ACLTemplatedPolicySetRequest{
+			Datacenter: "dc1",
+			TemplatedPolicy: structs.ACLTemplatedPolicyDefinition{
+				Name:     "team-kv-namespace",
+				Template: `key_prefix "" { policy = "read" }`,
+			},
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		resp := structs.ACLTemplatedPolicyDefinition{}
+
+		require.Error(t, aclEp.TemplatedPolicySet(&req, &resp))
+	})
+
+	t.Run("Builtin Name", func(t *testing.T) {
+		req := structs.ACLTemplatedPolicySetRequest{
+			Datacenter: "dc1",
+			TemplatedPolicy: structs.ACLTemplatedPolicyDefinition{
+				Name:     api.ACLTemplatedPolicyServiceName,
+				Template: `key_prefix "" { policy = "read" }`,
+			},
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		resp := structs.ACLTemplatedPolicyDefinition{}
+
+		require.Error(t, aclEp.TemplatedPolicySet(&req, &resp))
+	})
+
+	t.Run("Invalid Template", func(t *testing.T) {
+		req := structs.ACLTemplatedPolicySetRequest{
+			Datacenter: "dc1",
+			TemplatedPolicy: structs.ACLTemplatedPolicyDefinition{
+				Name:     "broken",
+				Template: `key_prefix "" { policy = "bogus" }`,
+			},
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		resp := structs.ACLTemplatedPolicyDefinition{}
+
+		require.Error(t, aclEp.TemplatedPolicySet(&req, &resp))
+	})
+
+	t.Run("Update it", func(t *testing.T) {
+		req := structs.ACLTemplatedPolicySetRequest{
+			Datacenter: "dc1",
+			TemplatedPolicy: structs.ACLTemplatedPolicyDefinition{
+				ID:          templatedPolicyID,
+				Name:        "team-kv-namespace",
+				Description: "updated",
+				Schema:      schema,
+				Template:    `key_prefix "teams/{{.Name}}/" { policy = "write" }`,
+			},
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		resp := structs.ACLTemplatedPolicyDefinition{}
+
+		require.NoError(t, aclEp.TemplatedPolicySet(&req, &resp))
+		require.Equal(t, templatedPolicyID, resp.ID)
+		require.Equal(t, "updated", resp.Description)
+	})
+
+	t.Run("Use it in a token", func(t *testing.T) {
+		req := structs.ACLTokenSetRequest{
+			Datacenter: "dc1",
+			ACLToken: structs.ACLToken{
+				Description: "team token",
+				TemplatedPolicies: []*structs.ACLTemplatedPolicy{
+					{
+						TemplateName:      "team-kv-namespace",
+						TemplateVariables: &structs.ACLTemplatedPolicyVariables{Name: "web"},
+					},
+				},
+			},
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		token := structs.ACLToken{}
+		require.NoError(t, aclEp.TokenSet(&req, &token))
+		require.Len(t, token.TemplatedPolicies, 1)
+		require.Equal(t, templatedPolicyID, token.TemplatedPolicies[0].TemplateID)
+
+		authz, err := srv.ACLResolver.ResolveToken(token.SecretID)
+		require.NoError(t, err)
+		require.Equal(t, acl.Allow, authz.KeyWrite("teams/web/config", nil))
+		require.Equal(t, acl.Deny, authz.KeyWrite("teams/db/config", nil))
+
+		// Missing required variables are rejected by the schema
+		req.ACLToken.TemplatedPolicies[0].TemplateVariables = nil
+		require.Error(t, aclEp.TokenSet(&req, &structs.ACLToken{}))
+	})
+
+	t.Run("Delete it", func(t *testing.T) {
+		req := structs.ACLTemplatedPolicyDeleteRequest{
+			Datacenter:   "dc1",
+			Name:         "team-kv-namespace",
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		var resp string
+		require.NoError(t, aclEp.TemplatedPolicyDelete(&req, &resp))
 
-	err = aclEp.PolicyList(&req, &resp)
-	require.NoError(t, err)
+		listReq := structs.ACLTemplatedPolicyListRequest{
+			Datacenter:   "dc1",
+			QueryOptions: structs.QueryOptions{Token: TestDefaultInitialManagementToken},
+		}
+		var listResp structs.ACLTemplatedPolicyListResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.TemplatedPolicyList", &listReq, &listResp))
+		require.Empty(t, listResp.TemplatedPolicies)
 
-	policies := []string{
-		structs.ACLPolicyGlobalManagementID,
-		structs.ACLPolicyGlobalReadOnlyID,
-		p1.ID,
-		p2.ID,
-	}
-	require.ElementsMatch(t, gatherIDs(t, resp.Policies), policies)
+		// builtin templated policies cannot be deleted
+		req.Name = api.ACLTemplatedPolicyNodeName
+		require.Error(t, aclEp.TemplatedPolicyDelete(&req, &resp))
+	})
 }
 
-func TestACLEndpoint_PolicyResolve(t *testing.T) {
+func TestACLEndpoint_TemplatedPolicySet_MinimumVersion(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
 	}
 
 	t.Parallel()
 
-	_, srv, codec := testACLServerWithConfig(t, nil, false)
+	_, srv, _ := testACLServerWithConfig(t, func(c *Config) {
+		c.Build = "1.16.0"
+	}, false)
 	waitForLeaderEstablishment(t, srv)
-
-	p1, err := upsertTestPolicy(codec, TestDefaultInitialManagementToken, "dc1")
-	require.NoError(t, err)
-
-	p2, err := upsertTestPolicy(codec, TestDefaultInitialManagementToken, "dc1")
-	require.NoError(t, err)
-
 	aclEp := ACL{srv: srv}
 
-	policies := []string{p1.ID, p2.ID}
-
-	// Assign the policies to a token
-	tokenUpsertReq := structs.ACLTokenSetRequest{
+	setReq := structs.ACLTemplatedPolicySetRequest{
 		Datacenter: "dc1",
-		ACLToken: structs.ACLToken{
-			Policies: []structs.ACLTokenPolicyLink{
-				{
-					ID: p1.ID,
-				},
-				{
-					ID: p2.ID,
-				},
-			},
+		TemplatedPolicy: structs.ACLTemplatedPolicyDefinition{
+			Name:     "team-kv",
+			Template: `key_prefix "teams/" { policy = "read" }`,
 		},
 		WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
 	}
-	token := structs.ACLToken{}
-	err = aclEp.TokenSet(&tokenUpsertReq, &token)
-	require.NoError(t, err)
-	require.NotEmpty(t, token.SecretID)
+	var setResp structs.ACLTemplatedPolicyDefinition
+	err := aclEp.TemplatedPolicySet(&setReq, &setResp)
+	require.ErrorContains(t, err, "all servers must be running at least Consul 1.17.0 before writing templated policies")
 
-	resp := structs.ACLPolicyBatchResponse{}
-	req := structs.ACLPolicyBatchGetRequest{
+	deleteReq := structs.ACLTemplatedPolicyDeleteRequest{
 		Datacenter:   "dc1",
-		PolicyIDs:    []string{p1.ID, p2.ID},
-		QueryOptions: structs.QueryOptions{Token: token.SecretID},
+		Name:         "team-kv",
+		WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
 	}
-	err = aclEp.PolicyResolve(&req, &resp)
-	require.NoError(t, err)
-	require.ElementsMatch(t, gatherIDs(t, resp.Policies), policies)
+	var deleteResp string
+	err = aclEp.TemplatedPolicyDelete(&deleteReq, &deleteResp)
+	require.ErrorContains(t, err, "all servers must be running at least Consul 1.17.0 before writing templated policies")
 }
 
 func TestACLEndpoint_RoleRead(t *testing.T) {
@@ -4944,6 +5532,7 @@ func TestACLEndpoint_Login_with_MaxTokenTTL(t *testing.T) {
 	got.AccessorID = ""
 	got.SecretID = ""
 	got.Hash = nil
+	got.Lineage = nil
 
 	defaultEntMeta := structs.DefaultEnterpriseMetaInDefaultPartition()
 	expect := &structs.ACLToken{
@@ -5052,6 +5641,7 @@ func TestACLEndpoint_Login_with_TokenLocality(t *testing.T) {
 			got.AccessorID = ""
 			got.SecretID = ""
 			got.Hash = nil
+			got.Lineage = nil
 
 			defaultEntMeta := structs.DefaultEnterpriseMetaInDefaultPartition()
 			expect := &structs.ACLToken{
@@ -5377,6 +5967,357 @@ func TestACLEndpoint_Login_jwt(t *testing.T) {
 	}
 }
 
+func TestACLEndpoint_Login_cert(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, nil, false)
+	waitForLeaderEstablishment(t, srv)
+
+	aclEp := ACL{srv: srv}
+
+	ca := certauth.NewTestCA(t)
+	certPEM, keyPEM := ca.IssueClientCert(t, "web-client", func(cert *x509.Certificate) {
+		cert.DNSNames = []string{"web.example.org"}
+	})
+	pair, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	require.NoError(t, err)
+
+	method, err := upsertTestCustomizedAuthMethod(codec, TestDefaultInitialManagementToken, "dc1", func(method *structs.ACLAuthMethod) {
+		method.Type = "cert"
+		method.Config = map[string]interface{}{
+			"CACerts": []string{ca.CertPEM},
+		}
+	})
+	require.NoError(t, err)
+
+	loginToken, err := certauth.NewLoginToken(method.Name, []*x509.Certificate{leaf}, pair.PrivateKey.(crypto.Signer))
+	require.NoError(t, err)
+
+	t.Run("invalid bearer token", func(t *testing.T) {
+		req := structs.ACLLoginRequest{
+			Auth: &structs.ACLLoginParams{
+				AuthMethod:  method.Name,
+				BearerToken: "invalid",
+			},
+			Datacenter: "dc1",
+		}
+		resp := structs.ACLToken{}
+
+		require.Error(t, aclEp.Login(&req, &resp))
+	})
+
+	t.Run("valid bearer token no bindings", func(t *testing.T) {
+		req := structs.ACLLoginRequest{
+			Auth: &structs.ACLLoginParams{
+				AuthMethod:  method.Name,
+				BearerToken: loginToken,
+			},
+			Datacenter: "dc1",
+		}
+		resp := structs.ACLToken{}
+
+		testutil.RequireErrorContains(t, aclEp.Login(&req, &resp), "Permission denied")
+	})
+
+	_, err = upsertTestBindingRule(
+		codec, TestDefaultInitialManagementToken, "dc1", method.Name,
+		`"web.example.org" in dns_sans`,
+		structs.BindingRuleBindTypeService,
+		"${common_name}",
+	)
+	require.NoError(t, err)
+
+	t.Run("valid bearer token 1 service binding", func(t *testing.T) {
+		req := structs.ACLLoginRequest{
+			Auth: &structs.ACLLoginParams{
+				AuthMethod:  method.Name,
+				BearerToken: loginToken,
+			},
+			Datacenter: "dc1",
+		}
+		resp := structs.ACLToken{}
+
+		require.NoError(t, aclEp.Login(&req, &resp))
+
+		require.Equal(t, method.Name, resp.AuthMethod)
+		require.Len(t, resp.ServiceIdentities, 1)
+		require.Equal(t, "web-client", resp.ServiceIdentities[0].ServiceName)
+	})
+
+}
+
+func TestACLEndpoint_AuthorizeExplain(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, nil, false)
+	waitForLeaderEstablishment(t, srv)
+
+	policy, err := upsertTestPolicyWithRules(codec, TestDefaultInitialManagementToken, "dc1", `
+		service_prefix "" { policy = "read" }
+		key_prefix "app/" { policy = "write" }
+	`)
+	require.NoError(t, err)
+
+	token, err := upsertTestToken(codec, TestDefaultInitialManagementToken, "dc1", func(token *structs.ACLToken) {
+		token.Policies = []structs.ACLTokenPolicyLink{{ID: policy.ID}}
+		token.ServiceIdentities = []*structs.ACLServiceIdentity{{ServiceName: "web"}}
+	})
+	require.NoError(t, err)
+
+	req := structs.RemoteACLAuthorizationRequest{
+		Datacenter: "dc1",
+		Requests: []structs.ACLAuthorizationRequest{
+			{Resource: "service", Segment: "web", Access: "write"},
+			{Resource: "service", Segment: "db", Access: "write"},
+			{Resource: "key", Segment: "app/config", Access: "write"},
+			{Resource: "operator", Access: "read"},
+		},
+		QueryOptions: structs.QueryOptions{Token: token.SecretID},
+	}
+	var resp []structs.ACLAuthorizationExplanation
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.AuthorizeExplain", &req, &resp))
+	require.Len(t, resp, 4)
+
+	// write access to web is granted by the service identity's synthetic policy
+	require.True(t, resp[0].Allow)
+	require.Equal(t, structs.ACLDecisionSourcePolicy, resp[0].DecidedBy)
+	require.Contains(t, resp[0].PolicyDescription, "builtin/service")
+	require.Equal(t, &structs.ACLExplainedRule{Resource: "service", Segment: "web", Policy: "write"}, resp[0].Rule)
+
+	require.False(t, resp[1].Allow)
+	require.Equal(t, structs.ACLDecisionSourcePolicy, resp[1].DecidedBy)
+	require.Equal(t, policy.ID, resp[1].PolicyID)
+	require.Equal(t, policy.Name, resp[1].PolicyName)
+	require.Equal(t, &structs.ACLExplainedRule{Resource: "service", Prefix: true, Policy: "read"}, resp[1].Rule)
+
+	require.True(t, resp[2].Allow)
+	require.Equal(t, policy.ID, resp[2].PolicyID)
+	require.Equal(t, &structs.ACLExplainedRule{Resource: "key", Segment: "app/", Prefix: true, Policy: "write"}, resp[2].Rule)
+
+	require.False(t, resp[3].Allow)
+	require.Equal(t, structs.ACLDecisionSourceDefaultPolicy, resp[3].DecidedBy)
+	require.Nil(t, resp[3].Rule)
+
+	t.Run("unknown token", func(t *testing.T) {
+		req := req
+		req.Token = "c2b5e9b2-1a53-4e52-9e57-4a1ed2e4b2a6"
+		var resp []structs.ACLAuthorizationExplanation
+		err := msgpackrpc.CallWithCodec(codec, "ACL.AuthorizeExplain", &req, &resp)
+		testutil.RequireErrorContains(t, err, acl.ErrNotFound.Error())
+	})
+}
+
+func TestACLEndpoint_OIDC(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, nil, false)
+	waitForLeaderEstablishment(t, srv)
+
+	aclEp := ACL{srv: srv}
+
+	const redirectURI = "http://localhost:8550/oidc/callback"
+
+	// spin up a fake oidc server
+	oidcServer := oidcauthtest.Start(t)
+	oidcServer.SetClientCreds("abc", "def")
+	oidcServer.SetAllowedRedirectURIs([]string{redirectURI})
+	oidcServer.SetExpectedAuthCode("authcode")
+	oidcServer.SetCustomClaims(map[string]interface{}{
+		"first_name": "jeff2",
+		"groups":     []string{"foo", "bar"},
+	})
+
+	method, err := upsertTestCustomizedAuthMethod(codec, TestDefaultInitialManagementToken, "dc1", func(method *structs.ACLAuthMethod) {
+		method.Type = "oidc"
+		method.Config = map[string]interface{}{
+			"JWTSupportedAlgs":    []string{"ES256"},
+			"OIDCDiscoveryURL":    oidcServer.Addr(),
+			"OIDCDiscoveryCACert": oidcServer.CACert(),
+			"OIDCClientID":        "abc",
+			"OIDCClientSecret":    "def",
+			"AllowedRedirectURIs": []string{redirectURI},
+			"ClaimMappings":       map[string]string{"first_name": "name"},
+			"ListClaimMappings":   map[string]string{"groups": "groups"},
+		}
+	})
+	require.NoError(t, err)
+
+	_, err = upsertTestBindingRule(
+		codec, TestDefaultInitialManagementToken, "dc1", method.Name,
+		"value.name == jeff2 and foo in list.groups",
+		structs.BindingRuleBindTypeService,
+		"test--${value.name}",
+	)
+	require.NoError(t, err)
+
+	// authorize runs the first half of the flow and simulates the user
+	// authenticating with the provider in a browser.
+	authorize := func(t *testing.T, clientNonce string) (state, code string) {
+		req := structs.ACLOIDCAuthURLRequest{
+			Auth: &structs.ACLOIDCAuthURLParams{
+				AuthMethod:  method.Name,
+				RedirectURI: redirectURI,
+				ClientNonce: clientNonce,
+				Meta:        map[string]string{"host": "laptop"},
+			},
+			Datacenter: "dc1",
+		}
+		var authURL string
+		require.NoError(t, aclEp.OIDCAuthURL(&req, &authURL))
+		require.True(t, strings.HasPrefix(authURL, oidcServer.Addr()+"/auth?"))
+
+		redirect, err := oidcServer.Authorize(authURL)
+		require.NoError(t, err)
+		return redirect.Query().Get("state"), redirect.Query().Get("code")
+	}
+
+	t.Run("list without a token", func(t *testing.T) {
+		testSessionID := testauth.StartSession()
+		defer testauth.ResetSession(testSessionID)
+
+		// Auth methods of other types are not listed.
+		_, err := upsertTestAuthMethod(codec, TestDefaultInitialManagementToken, "dc1", testSessionID)
+		require.NoError(t, err)
+
+		internalEp := Internal{srv: srv}
+		req := structs.DCSpecificRequest{Datacenter: "dc1"}
+		var resp structs.ACLOIDCAuthMethodListResponse
+		require.NoError(t, internalEp.OIDCAuthMethods(&req, &resp))
+
+		require.Len(t, resp.AuthMethods, 1)
+		require.Equal(t, method.Name, resp.AuthMethods[0].Name)
+		require.Equal(t, "no-icon", resp.AuthMethods[0].Kind)
+	})
+
+	t.Run("login is not supported", func(t *testing.T) {
+		req := structs.ACLLoginRequest{
+			Auth: &structs.ACLLoginParams{
+				AuthMethod:  method.Name,
+				BearerToken: "invalid",
+			},
+			Datacenter: "dc1",
+		}
+		resp := structs.ACLToken{}
+
+		testutil.RequireErrorContains(t, aclEp.Login(&req, &resp), `incompatible with type "oidc"`)
+	})
+
+	t.Run("unauthorized redirect uri", func(t *testing.T) {
+		req := structs.ACLOIDCAuthURLRequest{
+			Auth: &structs.ACLOIDCAuthURLParams{
+				AuthMethod:  method.Name,
+				RedirectURI: "http://evil.example.com/oidc/callback",
+				ClientNonce: "nonce1",
+			},
+			Datacenter: "dc1",
+		}
+		var authURL string
+
+		testutil.RequireErrorContains(t, aclEp.OIDCAuthURL(&req, &authURL), "unauthorized redirect_uri")
+	})
+
+	t.Run("wrong auth method type", func(t *testing.T) {
+		testSessionID := testauth.StartSession()
+		defer testauth.ResetSession(testSessionID)
+
+		other, err := upsertTestAuthMethod(codec, TestDefaultInitialManagementToken, "dc1", testSessionID)
+		require.NoError(t, err)
+
+		req := structs.ACLOIDCAuthURLRequest{
+			Auth: &structs.ACLOIDCAuthURLParams{
+				AuthMethod:  other.Name,
+				RedirectURI: redirectURI,
+				ClientNonce: "nonce1",
+			},
+			Datacenter: "dc1",
+		}
+		var authURL string
+
+		testutil.RequireErrorContains(t, aclEp.OIDCAuthURL(&req, &authURL), `is not of type "oidc"`)
+	})
+
+	t.Run("missing client nonce", func(t *testing.T) {
+		authURLReq := structs.ACLOIDCAuthURLRequest{
+			Auth: &structs.ACLOIDCAuthURLParams{
+				AuthMethod:  method.Name,
+				RedirectURI: redirectURI,
+			},
+			Datacenter: "dc1",
+		}
+		var authURL string
+		testutil.RequireErrorContains(t, aclEp.OIDCAuthURL(&authURLReq, &authURL), "Missing client nonce")
+
+		state, code := authorize(t, "nonce1")
+		req := structs.ACLOIDCCallbackRequest{
+			Auth: &structs.ACLOIDCCallbackParams{
+				AuthMethod: method.Name,
+				State:      state,
+				Code:       code,
+			},
+			Datacenter: "dc1",
+		}
+		resp := structs.ACLToken{}
+		testutil.RequireErrorContains(t, aclEp.OIDCCallback(&req, &resp), "Missing client nonce")
+	})
+
+	t.Run("client nonce mismatch", func(t *testing.T) {
+		state, code := authorize(t, "nonce1")
+
+		req := structs.ACLOIDCCallbackRequest{
+			Auth: &structs.ACLOIDCCallbackParams{
+				AuthMethod:  method.Name,
+				State:       state,
+				Code:        code,
+				ClientNonce: "nonce2",
+			},
+			Datacenter: "dc1",
+		}
+		resp := structs.ACLToken{}
+
+		testutil.RequireErrorContains(t, aclEp.OIDCCallback(&req, &resp), "invalid client nonce")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		state, code := authorize(t, "nonce1")
+
+		req := structs.ACLOIDCCallbackRequest{
+			Auth: &structs.ACLOIDCCallbackParams{
+				AuthMethod:  method.Name,
+				State:       state,
+				Code:        code,
+				ClientNonce: "nonce1",
+			},
+			Datacenter: "dc1",
+		}
+		resp := structs.ACLToken{}
+
+		require.NoError(t, aclEp.OIDCCallback(&req, &resp))
+
+		require.Equal(t, method.Name, resp.AuthMethod)
+		require.Equal(t, `token created via OIDC login: {"host":"laptop"}`, resp.Description)
+		require.True(t, resp.Local)
+		require.Len(t, resp.ServiceIdentities, 1)
+		require.Equal(t, "test--jeff2", resp.ServiceIdentities[0].ServiceName)
+
+		// The state cannot be redeemed twice.
+		testutil.RequireErrorContains(t, aclEp.OIDCCallback(&req, &resp), "Expired or missing OAuth state")
+	})
+}
+
 func TestACLEndpoint_Logout(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -126,6 +126,43 @@ func (s *Server) fetchACLRoles(lastRemoteIndex uint64) (*structs.ACLRoleListResp
 	return &response, nil
 }
 
+func (s *Server) fetchACLTemplatedPoliciesBatch(ids []string) (*structs.ACLTemplatedPolicyBatchResponse, error) {
+	req := structs.ACLTemplatedPolicyBatchGetRequest{
+		Datacenter: s.config.PrimaryDatacenter,
+		IDs:        ids,
+		QueryOptions: structs.QueryOptions{
+			AllowStale: true,
+			Token:      s.tokens.ReplicationToken(),
+		},
+	}
+
+	var response structs.ACLTemplatedPolicyBatchResponse
+	if err := s.RPC(context.Background(), "ACL.TemplatedPolicyBatchRead", &req, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (s *Server) fetchACLTemplatedPolicies(lastRemoteIndex uint64) (*structs.ACLTemplatedPolicyListResponse, error) {
+	defer metrics.MeasureSince([]string{"leader", "replication", "acl", "templated_policy", "fetch"}, time.Now())
+
+	req := structs.ACLTemplatedPolicyListRequest{
+		Datacenter: s.config.PrimaryDatacenter,
+		QueryOptions: structs.QueryOptions{
+			AllowStale:    true,
+			MinQueryIndex: lastRemoteIndex,
+			Token:         s.tokens.ReplicationToken(),
+		},
+	}
+
+	var response structs.ACLTemplatedPolicyListResponse
+	if err := s.RPC(context.Background(), "ACL.TemplatedPolicyList", &req, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
 func (s *Server) fetchACLPoliciesBatch(policyIDs []string) (*structs.ACLPolicyBatchResponse, error) {
 	req := structs.ACLPolicyBatchGetRequest{
 		Datacenter: s.config.PrimaryDatacenter,
@@ -371,6 +408,11 @@ func (s *Server) replicateACLRoles(ctx context.Context, logger hclog.Logger, las
 	return s.replicateACLType(ctx, logger, tr, lastRemoteIndex)
 }
 
+func (s *Server) replicateACLTemplatedPolicies(ctx context.Context, logger hclog.Logger, lastRemoteIndex uint64) (uint64, bool, error) {
+	tr := &aclTemplatedPolicyReplicator{}
+	return s.replicateACLType(ctx, logger, tr, lastRemoteIndex)
+}
+
 func (s *Server) replicateACLType(ctx context.Context, logger hclog.Logger, tr aclTypeReplicator, lastRemoteIndex uint64) (uint64, bool, error) {
 	lenRemote, remoteIndex, err := tr.FetchRemote(s, lastRemoteIndex)
 	if err != nil {
@@ -500,6 +542,8 @@ func (s *Server) updateACLReplicationStatusIndex(replicationType structs.ACLRepl
 		s.aclReplicationStatus.ReplicatedIndex = index
 	case structs.ACLReplicateRoles:
 		s.aclReplicationStatus.ReplicatedRoleIndex = index
+	case structs.ACLReplicateTemplatedPolicies:
+		s.aclReplicationStatus.ReplicatedTemplatedPolicyIndex = index
 	default:
 		panic("unknown replication type: " + replicationType.SingularNoun())
 	}
@@ -528,9 +572,9 @@ func (s *Server) updateACLReplicationStatusRunning(replicationType structs.ACLRe
 	// The running state represents which type of overall replication has been
 	// configured. Though there are various types of internal plumbing for acl
 	// replication, to the end user there are only 3 distinctly configurable
-	// variants: legacy, policy, token. Roles replicate with policies so we
-	// round that up here.
-	if replicationType == structs.ACLReplicateRoles {
+	// variants: legacy, policy, token. Roles and templated policies replicate
+	// with policies so we round that up here.
+	if replicationType == structs.ACLReplicateRoles || replicationType == structs.ACLReplicateTemplatedPolicies {
 		replicationType = structs.ACLReplicatePolicies
 	}
 
@@ -108,6 +108,18 @@ func (r *aclTokenReplicator) PendingUpdateIsRedacted(i int) bool {
 }
 
 func (r *aclTokenReplicator) UpdateLocalBatch(ctx context.Context, srv *Server, start, end int) error {
+	// Older servers refuse to change the SecretID of a token, hold back
+	// rotated tokens until every server is able to apply them.
+	for _, token := range r.updated[start:end] {
+		if token.RotationTime == nil {
+			continue
+		}
+		if err := srv.requireServersMinimumVersion(minACLTokenRotationVersion, "replicating rotated tokens"); err != nil {
+			return err
+		}
+		break
+	}
+
 	req := structs.ACLTokenBatchSetRequest{
 		Tokens:            r.updated[start:end],
 		CAS:               false,
@@ -216,6 +228,113 @@ func (r *aclPolicyReplicator) UpdateLocalBatch(ctx context.Context, srv *Server,
 	return err
 }
 
+///////////////////////
+
+type aclTemplatedPolicyReplicator struct {
+	local   structs.ACLTemplatedPolicyDefinitions
+	remote  structs.ACLTemplatedPolicyDefinitions
+	updated []*structs.ACLTemplatedPolicyDefinition
+}
+
+var _ aclTypeReplicator = (*aclTemplatedPolicyReplicator)(nil)
+
+func (r *aclTemplatedPolicyReplicator) Type() structs.ACLReplicationType {
+	return structs.ACLReplicateTemplatedPolicies
+}
+func (r *aclTemplatedPolicyReplicator) SingularNoun() string { return "templated policy" }
+func (r *aclTemplatedPolicyReplicator) PluralNoun() string   { return "templated policies" }
+
+func (r *aclTemplatedPolicyReplicator) FetchRemote(srv *Server, lastRemoteIndex uint64) (int, uint64, error) {
+	r.remote = nil
+
+	remote, err := srv.fetchACLTemplatedPolicies(lastRemoteIndex)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	r.remote = remote.TemplatedPolicies
+	return len(remote.TemplatedPolicies), remote.QueryMeta.Index, nil
+}
+
+func (r *aclTemplatedPolicyReplicator) FetchLocal(srv *Server) (int, uint64, error) {
+	r.local = nil
+
+	idx, local, err := srv.fsm.State().ACLTemplatedPolicyList(nil)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	r.local = local
+	return len(local), idx, nil
+}
+
+func (r *aclTemplatedPolicyReplicator) SortState() (int, int) {
+	r.local.Sort()
+	r.remote.Sort()
+
+	return len(r.local), len(r.remote)
+}
+func (r *aclTemplatedPolicyReplicator) LocalMeta(i int) (id string, modIndex uint64, hash []byte) {
+	v := r.local[i]
+	return v.ID, v.ModifyIndex, v.Hash
+}
+func (r *aclTemplatedPolicyReplicator) RemoteMeta(i int) (id string, modIndex uint64, hash []byte) {
+	v := r.remote[i]
+	return v.ID, v.ModifyIndex, v.Hash
+}
+
+func (r *aclTemplatedPolicyReplicator) FetchUpdated(srv *Server, updates []string) (int, error) {
+	r.updated = nil
+
+	if len(updates) > 0 {
+		templatedPolicies, err := srv.fetchACLTemplatedPoliciesBatch(updates)
+		if err != nil {
+			return 0, err
+		}
+		r.updated = templatedPolicies.TemplatedPolicies
+	}
+
+	return len(r.updated), nil
+}
+
+func (r *aclTemplatedPolicyReplicator) DeleteLocalBatch(srv *Server, batch []string) error {
+	if err := srv.requireServersMinimumVersion(minACLTemplatedPolicyVersion, "replicating templated policies"); err != nil {
+		return err
+	}
+
+	req := structs.ACLTemplatedPolicyBatchDeleteRequest{
+		TemplatedPolicyIDs: batch,
+	}
+
+	_, err := srv.leaderRaftApply("ACL.TemplatedPolicyDelete", structs.ACLTemplatedPolicyDeleteRequestType, &req)
+	return err
+}
+
+func (r *aclTemplatedPolicyReplicator) LenPendingUpdates() int {
+	return len(r.updated)
+}
+
+func (r *aclTemplatedPolicyReplicator) PendingUpdateEstimatedSize(i int) int {
+	return r.updated[i].EstimateSize()
+}
+
+func (r *aclTemplatedPolicyReplicator) PendingUpdateIsRedacted(i int) bool {
+	return false
+}
+
+func (r *aclTemplatedPolicyReplicator) UpdateLocalBatch(ctx context.Context, srv *Server, start, end int) error {
+	if err := srv.requireServersMinimumVersion(minACLTemplatedPolicyVersion, "replicating templated policies"); err != nil {
+		return err
+	}
+
+	req := structs.ACLTemplatedPolicyBatchSetRequest{
+		TemplatedPolicies: r.updated[start:end],
+	}
+
+	_, err := srv.leaderRaftApply("ACL.TemplatedPolicySet", structs.ACLTemplatedPolicySetRequestType, &req)
+	return err
+}
+
 ////////////////////////////////
 
 type aclRoleReplicator struct {
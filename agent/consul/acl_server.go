@@ -32,6 +32,8 @@ var serverACLCacheConfig *structs.ACLCachesConfig = &structs.ACLCachesConfig{
 	ParsedPolicies: 512,
 	Authorizers:    1024,
 	Roles:          0,
+
+	TemplatedPolicies: 0,
 }
 
 func (s *Server) checkTokenUUID(id string) (bool, error) {
@@ -67,6 +69,17 @@ func (s *Server) checkPolicyUUID(id string) (bool, error) {
 	return !structs.ACLIDReserved(id), nil
 }
 
+func (s *Server) checkTemplatedPolicyUUID(id string) (bool, error) {
+	state := s.fsm.State()
+	if _, templatedPolicy, err := state.ACLTemplatedPolicyGetByID(nil, id); err != nil {
+		return false, err
+	} else if templatedPolicy != nil {
+		return false, nil
+	}
+
+	return !structs.ACLIDReserved(id), nil
+}
+
 func (s *Server) checkRoleUUID(id string) (bool, error) {
 	state := s.fsm.State()
 	if _, role, err := state.ACLRoleGetByID(nil, id, nil); err != nil {
@@ -184,6 +197,19 @@ func (s *serverACLResolverBackend) ResolvePolicyFromID(policyID string) (bool, *
 	return s.InPrimaryDatacenter() || index > 0, policy, acl.ErrNotFound
 }
 
+func (s *serverACLResolverBackend) ResolveTemplatedPolicyFromName(name string) (bool, *structs.ACLTemplatedPolicyDefinition, error) {
+	index, templatedPolicy, err := s.fsm.State().ACLTemplatedPolicyGetByName(nil, name)
+	if err != nil {
+		return true, nil, err
+	} else if templatedPolicy != nil {
+		return true, templatedPolicy, nil
+	}
+
+	// Like policies, user-defined templated policies are replicated so once
+	// replication has caught up they can be resolved locally.
+	return s.InPrimaryDatacenter() || index > 0, nil, acl.ErrNotFound
+}
+
 func (s *serverACLResolverBackend) ResolveRoleFromID(roleID string) (bool, *structs.ACLRole, error) {
 	index, role, err := s.fsm.State().ACLRoleGetByID(nil, roleID, nil)
 	if err != nil {
@@ -436,6 +436,8 @@ type ACLResolverTestDelegate struct {
 	testPolicies map[string]*structs.ACLPolicy
 	// testRoles is used by plainRoleResolveFn if not nil
 	testRoles map[string]*structs.ACLRole
+	// testTemplatedPolicies holds the user-defined templated policies by name
+	testTemplatedPolicies map[string]*structs.ACLTemplatedPolicyDefinition
 
 	testServerManagementToken string
 
@@ -467,6 +469,7 @@ func (d *ACLResolverTestDelegate) UseTestLocalData(data []interface{}) {
 	d.testTokens = make(map[string]*structs.ACLToken)
 	d.testPolicies = make(map[string]*structs.ACLPolicy)
 	d.testRoles = make(map[string]*structs.ACLRole)
+	d.testTemplatedPolicies = make(map[string]*structs.ACLTemplatedPolicyDefinition)
 
 	var rest []interface{}
 	for _, item := range data {
@@ -477,6 +480,8 @@ func (d *ACLResolverTestDelegate) UseTestLocalData(data []interface{}) {
 			d.testPolicies[x.ID] = x
 		case *structs.ACLRole:
 			d.testRoles[x.ID] = x
+		case *structs.ACLTemplatedPolicyDefinition:
+			d.testTemplatedPolicies[x.Name] = x
 		case string:
 			parts := strings.SplitN(x, ":", 2)
 			switch parts[0] {
@@ -666,6 +671,17 @@ func (d *ACLResolverTestDelegate) ResolveRoleFromID(roleID string) (bool, *struc
 	return testRoleForID(roleID)
 }
 
+func (d *ACLResolverTestDelegate) ResolveTemplatedPolicyFromName(name string) (bool, *structs.ACLTemplatedPolicyDefinition, error) {
+	if !d.localPolicies {
+		return false, nil, nil
+	}
+
+	if templatedPolicy := d.testTemplatedPolicies[name]; templatedPolicy != nil {
+		return true, templatedPolicy, nil
+	}
+	return true, nil, acl.ErrNotFound
+}
+
 func (d *ACLResolverTestDelegate) RPC(ctx context.Context, method string, args interface{}, reply interface{}) error {
 	switch method {
 	case "ACL.TokenRead":
@@ -686,6 +702,14 @@ func (d *ACLResolverTestDelegate) RPC(ctx context.Context, method string, args i
 			return d.roleResolveFn(args.(*structs.ACLRoleBatchGetRequest), reply.(*structs.ACLRoleBatchResponse))
 		}
 		panic("Bad Test Implementation: should provide a roleResolveFn to the ACLResolverTestDelegate")
+	case "ACL.TemplatedPolicyResolve":
+		req, resp := args.(*structs.ACLTemplatedPolicyBatchGetRequest), reply.(*structs.ACLTemplatedPolicyBatchResponse)
+		for _, name := range req.Names {
+			if templatedPolicy := d.testTemplatedPolicies[name]; templatedPolicy != nil {
+				resp.TemplatedPolicies = append(resp.TemplatedPolicies, templatedPolicy)
+			}
+		}
+		return nil
 	}
 	if handled, err := d.EnterpriseACLResolverTestDelegate.RPC(context.Background(), method, args, reply); handled {
 		return err
@@ -1415,6 +1439,80 @@ func TestACLResolver_DatacenterScoping(t *testing.T) {
 }
 
 // TODO(rb): replicate this sort of test but for roles
+func TestACLResolver_UserDefinedTemplatedPolicies(t *testing.T) {
+	t.Parallel()
+
+	data := []interface{}{
+		&structs.ACLTemplatedPolicyDefinition{
+			ID:       "4b7a1c5e-6c49-4d6b-9a15-8ab8c4ed2b31",
+			Name:     "team-kv-namespace",
+			Schema:   structs.ACLTemplatedPolicyServiceSchema,
+			Template: `key_prefix "teams/{{.Name}}/" { policy = "write" }`,
+		},
+		&structs.ACLRole{
+			ID:   "8c1d0ff3-4b5e-4d0a-a8a0-6b8a5e0d6a8e",
+			Name: "team-db",
+			TemplatedPolicies: []*structs.ACLTemplatedPolicy{
+				{TemplateName: "team-kv-namespace", TemplateVariables: &structs.ACLTemplatedPolicyVariables{Name: "db"}},
+			},
+		},
+		&structs.ACLToken{
+			AccessorID: "9d2f8d62-7a0e-4a8c-a7a8-0e3a3c1f6b6f",
+			SecretID:   "team-web",
+			TemplatedPolicies: []*structs.ACLTemplatedPolicy{
+				{TemplateName: "team-kv-namespace", TemplateVariables: &structs.ACLTemplatedPolicyVariables{Name: "web"}},
+				{TemplateName: api.ACLTemplatedPolicyDNSName},
+			},
+			Roles: []structs.ACLTokenRoleLink{
+				{ID: "8c1d0ff3-4b5e-4d0a-a8a0-6b8a5e0d6a8e"},
+			},
+		},
+		&structs.ACLToken{
+			AccessorID: "2e6a3b48-8f0b-4c3c-9a0a-0cd4d4d0ad5c",
+			SecretID:   "missing-template",
+			TemplatedPolicies: []*structs.ACLTemplatedPolicy{
+				{TemplateName: "deleted-template", TemplateVariables: &structs.ACLTemplatedPolicyVariables{Name: "web"}},
+			},
+		},
+	}
+
+	run := func(t *testing.T, delegate *ACLResolverTestDelegate) {
+		delegate.UseTestLocalData(data)
+		r := newTestACLResolver(t, delegate, nil)
+
+		authz, err := r.ResolveToken("team-web")
+		require.NoError(t, err)
+		require.Equal(t, acl.Allow, authz.KeyWrite("teams/web/config", nil))
+		require.Equal(t, acl.Allow, authz.KeyWrite("teams/db/config", nil))
+		require.Equal(t, acl.Deny, authz.KeyWrite("teams/api/config", nil))
+		require.Equal(t, acl.Allow, authz.NodeRead("any", nil))
+
+		authz, err = r.ResolveToken("missing-template")
+		require.NoError(t, err)
+		require.Equal(t, acl.Deny, authz.KeyWrite("teams/web/config", nil))
+	}
+
+	t.Run("Local", func(t *testing.T) {
+		run(t, &ACLResolverTestDelegate{
+			enabled:       true,
+			datacenter:    "dc1",
+			localTokens:   true,
+			localPolicies: true,
+			localRoles:    true,
+		})
+	})
+
+	t.Run("Remote", func(t *testing.T) {
+		delegate := &ACLResolverTestDelegate{
+			enabled:    true,
+			datacenter: "dc1",
+		}
+		delegate.tokenReadFn = delegate.plainTokenReadFn
+		delegate.roleResolveFn = delegate.plainRoleResolveFn
+		run(t, delegate)
+	})
+}
+
 func TestACLResolver_Client(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
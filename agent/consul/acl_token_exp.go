@@ -24,11 +24,17 @@ func (s *Server) reapExpiredTokens(ctx context.Context) error {
 			if _, err := s.reapExpiredLocalACLTokens(); err != nil {
 				s.logger.Error("error reaping expired local ACL tokens", "error", err)
 			}
+			if _, err := s.reapExpiredACLTokenSecrets(true); err != nil {
+				s.logger.Error("error reaping expired secrets of rotated local ACL tokens", "error", err)
+			}
 		}
 		if s.InPrimaryDatacenter() {
 			if _, err := s.reapExpiredGlobalACLTokens(); err != nil {
 				s.logger.Error("error reaping expired global ACL tokens", "error", err)
 			}
+			if _, err := s.reapExpiredACLTokenSecrets(false); err != nil {
+				s.logger.Error("error reaping expired secrets of rotated global ACL tokens", "error", err)
+			}
 		}
 	}
 }
@@ -117,6 +123,59 @@ func (s *Server) reapExpiredACLTokens(local, global bool) (int, error) {
 	return len(req.TokenIDs), nil
 }
 
+// reapExpiredACLTokenSecrets removes the previous SecretID from rotated tokens
+// once their grace period has passed.
+func (s *Server) reapExpiredACLTokenSecrets(local bool) (int, error) {
+	if !s.config.ACLsEnabled {
+		return 0, nil
+	}
+
+	tokens, err := s.fsm.State().ACLTokenListPreviousSecretExpired(local, time.Now(), aclTokenSecretReapingBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(tokens) == 0 {
+		return 0, nil
+	}
+
+	var (
+		secretIDs []string
+		req       = structs.ACLTokenBatchSetRequest{
+			// Skip tokens that were modified in the meantime, they will be
+			// picked up again on the next pass.
+			CAS:               true,
+			AllowMissingLinks: true,
+		}
+	)
+	for _, token := range tokens {
+		updated := token.Clone()
+		updated.PreviousSecretID = ""
+		updated.PreviousSecretExpirationTime = nil
+		updated.SetHash(true)
+
+		req.Tokens = append(req.Tokens, updated)
+		secretIDs = append(secretIDs, token.PreviousSecretID)
+	}
+
+	s.logger.Info("removing expired secrets of rotated ACL tokens",
+		"amount", len(req.Tokens),
+		"locality", localityName(local),
+	)
+
+	_, err = s.leaderRaftApply("ACL.TokenSet", structs.ACLTokenSetRequestType, &req)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to apply token secret expirations: %v", err)
+	}
+
+	// Purge the identities from the cache
+	for _, secretID := range secretIDs {
+		s.ACLResolver.cache.RemoveIdentityWithSecretToken(secretID)
+	}
+
+	return len(req.Tokens), nil
+}
+
 func localityName(local bool) string {
 	if local {
 		return "local"
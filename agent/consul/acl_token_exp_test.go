@@ -12,6 +12,7 @@ import (
 
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
 	"github.com/hashicorp/consul/testrpc"
 )
 
@@ -226,3 +227,60 @@ func testACLTokenReap_Primary(t *testing.T, local, global bool) {
 		})
 	})
 }
+
+func TestACLTokenReap_PreviousSecret(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.PrimaryDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLInitialManagementToken = "root"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	aclEp := ACL{srv: s1, logger: s1.logger}
+
+	token, err := upsertTestToken(codec, "root", "dc1", nil)
+	require.NoError(t, err)
+
+	// See the note in testACLTokenReap_Primary about the index granularity.
+	req := structs.ACLTokenRotateRequest{
+		Datacenter:   "dc1",
+		AccessorID:   token.AccessorID,
+		GracePeriod:  time.Second,
+		WriteRequest: structs.WriteRequest{Token: "root"},
+	}
+	var rotated structs.ACLToken
+	require.NoError(t, aclEp.TokenRotate(&req, &rotated))
+	require.Equal(t, token.SecretID, rotated.PreviousSecretID)
+
+	// Nothing to reap yet.
+	n, err := s1.reapExpiredACLTokenSecrets(false)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	// The leader's reaping routine clears the previous secret once the grace
+	// period has ended.
+	retry.Run(t, func(r *retry.R) {
+		_, reaped, err := s1.fsm.State().ACLTokenGetByAccessor(nil, token.AccessorID, nil)
+		require.NoError(r, err)
+		require.NotNil(r, reaped)
+		require.Equal(r, rotated.SecretID, reaped.SecretID)
+		require.Empty(r, reaped.PreviousSecretID)
+		require.Nil(r, reaped.PreviousSecretExpirationTime)
+		require.NotNil(r, reaped.RotationTime)
+	})
+
+	_, err = s1.ACLResolver.ResolveToken(token.SecretID)
+	require.True(t, acl.IsErrNotFound(err), "unexpected error: %v", err)
+}
@@ -0,0 +1,191 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package consul
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// tokenUsageTracker collects the time tokens were last resolved until a
+// server writes it to the state store. Usage is only collected at a coarse
+// granularity so that frequently used tokens don't turn into a steady stream
+// of Raft writes.
+type tokenUsageTracker struct {
+	granularity time.Duration
+
+	lock sync.Mutex
+	// pending is the usage waiting to be written, keyed by AccessorID.
+	pending map[string]pendingTokenUsage
+	// written is the usage handed out to be written within the last
+	// granularity period. Tokens served from the resolver cache still carry
+	// the LastUsedAt they had when they were cached, this keeps them from
+	// being written over and over.
+	written map[string]time.Time
+}
+
+type pendingTokenUsage struct {
+	lastUsedAt time.Time
+	local      bool
+}
+
+func newTokenUsageTracker(granularity time.Duration) *tokenUsageTracker {
+	return &tokenUsageTracker{
+		granularity: granularity,
+		pending:     make(map[string]pendingTokenUsage),
+		written:     make(map[string]time.Time),
+	}
+}
+
+// record notes that the token was used at the given time.
+func (t *tokenUsageTracker) record(token *structs.ACLToken, now time.Time) {
+	if token.AccessorID == "" || token.AccessorID == acl.AnonymousTokenID {
+		return
+	}
+	if token.LastUsedAt != nil && now.Sub(*token.LastUsedAt) < t.granularity {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if written, ok := t.written[token.AccessorID]; ok && now.Sub(written) < t.granularity {
+		return
+	}
+	t.pending[token.AccessorID] = pendingTokenUsage{lastUsedAt: now, local: token.Local}
+}
+
+// drain removes up to max entries from the pending usage and returns them
+// split by the locality of the tokens.
+func (t *tokenUsageTracker) drain(max int, now time.Time) (local, global []structs.ACLTokenUsage) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for accessorID, written := range t.written {
+		if now.Sub(written) >= t.granularity {
+			delete(t.written, accessorID)
+		}
+	}
+
+	for accessorID, usage := range t.pending {
+		if len(local)+len(global) >= max {
+			break
+		}
+
+		entry := structs.ACLTokenUsage{AccessorID: accessorID, LastUsedAt: usage.lastUsedAt}
+		if usage.local {
+			local = append(local, entry)
+		} else {
+			global = append(global, entry)
+		}
+		delete(t.pending, accessorID)
+		t.written[accessorID] = usage.lastUsedAt
+	}
+	return local, global
+}
+
+// requeue returns usage that could not be written to the pending usage.
+func (t *tokenUsageTracker) requeue(usage []structs.ACLTokenUsage, local bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, u := range usage {
+		delete(t.written, u.AccessorID)
+		if pending, ok := t.pending[u.AccessorID]; ok && pending.lastUsedAt.After(u.LastUsedAt) {
+			continue
+		}
+		t.pending[u.AccessorID] = pendingTokenUsage{lastUsedAt: u.LastUsedAt, local: local}
+	}
+}
+
+// runACLTokenUsageFlusher periodically writes the usage of tokens resolved by
+// this server. It runs on every server, not just the leader, because any
+// server may resolve a token.
+func (s *Server) runACLTokenUsageFlusher(ctx context.Context) {
+	ticker := time.NewTicker(aclTokenUsageFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.flushACLTokenUsage(); err != nil {
+				s.logger.Warn("failed to record ACL token usage", "error", err)
+			}
+		}
+	}
+}
+
+// flushACLTokenUsage writes a batch of pending token usage. Usage is always
+// recorded in this datacenter. The usage of global tokens is also recorded in
+// the primary datacenter so that it has a complete picture of which tokens
+// are still in use.
+func (s *Server) flushACLTokenUsage() error {
+	tracker := s.ACLResolver.tokenUsage
+	if !s.config.ACLsEnabled || tracker == nil {
+		return nil
+	}
+
+	secretID, err := s.GetSystemMetadata(structs.ServerManagementTokenAccessorID)
+	if err != nil {
+		return err
+	}
+	if secretID == "" {
+		// ACLs are not bootstrapped yet, keep the usage around until they are.
+		return nil
+	}
+
+	// Keep the usage around until every server is able to apply it.
+	if ok, _ := ServersInDCMeetMinimumVersion(s, s.config.Datacenter, minACLTokenUsageVersion); !ok {
+		return nil
+	}
+
+	local, global := tracker.drain(aclTokenUsageBatchSize, time.Now())
+	if len(local)+len(global) == 0 {
+		return nil
+	}
+
+	write := func(dc, token string, usage []structs.ACLTokenUsage) error {
+		req := structs.ACLTokenUsageRequest{
+			Datacenter:   dc,
+			Usage:        usage,
+			WriteRequest: structs.WriteRequest{Token: token},
+		}
+		var out bool
+		return s.RPC(context.Background(), "ACL.TokenUsageUpdate", &req, &out)
+	}
+
+	all := make([]structs.ACLTokenUsage, 0, len(local)+len(global))
+	all = append(all, local...)
+	all = append(all, global...)
+	if err := write(s.config.Datacenter, secretID, all); err != nil {
+		tracker.requeue(local, true)
+		tracker.requeue(global, false)
+		return err
+	}
+
+	if len(global) == 0 || s.InPrimaryDatacenter() {
+		return nil
+	}
+	if ok, _ := ServersInDCMeetMinimumVersion(s, s.config.PrimaryDatacenter, minACLTokenUsageVersion); !ok {
+		return nil
+	}
+
+	// The server management token is local to each datacenter, writes to the
+	// primary datacenter use the replication token instead.
+	replicationToken := s.tokens.ReplicationToken()
+	if replicationToken == "" {
+		return nil
+	}
+	if err := write(s.config.PrimaryDatacenter, replicationToken, global); err != nil {
+		return fmt.Errorf("failed to record usage of global tokens in the primary datacenter: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package consul
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/consul-net-rpc/net-rpc-msgpackrpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestTokenUsageTracker(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	global := &structs.ACLToken{AccessorID: "a0b1c2d3-0000-0000-0000-000000000001"}
+	local := &structs.ACLToken{AccessorID: "a0b1c2d3-0000-0000-0000-000000000002", Local: true}
+
+	t.Run("records and drains", func(t *testing.T) {
+		tracker := newTokenUsageTracker(time.Hour)
+		tracker.record(global, now)
+		tracker.record(local, now)
+		tracker.record(&structs.ACLToken{AccessorID: acl.AnonymousTokenID}, now)
+
+		l, g := tracker.drain(10, now)
+		require.Equal(t, []structs.ACLTokenUsage{{AccessorID: local.AccessorID, LastUsedAt: now}}, l)
+		require.Equal(t, []structs.ACLTokenUsage{{AccessorID: global.AccessorID, LastUsedAt: now}}, g)
+
+		l, g = tracker.drain(10, now)
+		require.Empty(t, l)
+		require.Empty(t, g)
+	})
+
+	t.Run("granularity", func(t *testing.T) {
+		tracker := newTokenUsageTracker(time.Hour)
+
+		// The stored usage is recent enough.
+		recent := global.Clone()
+		lastUsedAt := now.Add(-time.Minute)
+		recent.LastUsedAt = &lastUsedAt
+		tracker.record(recent, now)
+		l, g := tracker.drain(10, now)
+		require.Empty(t, l)
+		require.Empty(t, g)
+
+		// Usage that was written recently isn't written again, even if the
+		// token still carries an old LastUsedAt.
+		tracker.record(global, now)
+		_, g = tracker.drain(10, now)
+		require.Len(t, g, 1)
+
+		tracker.record(global, now.Add(time.Minute))
+		_, g = tracker.drain(10, now.Add(time.Minute))
+		require.Empty(t, g)
+
+		later := now.Add(2 * time.Hour)
+		tracker.record(global, later)
+		_, g = tracker.drain(10, later)
+		require.Equal(t, []structs.ACLTokenUsage{{AccessorID: global.AccessorID, LastUsedAt: later}}, g)
+	})
+
+	t.Run("batches", func(t *testing.T) {
+		tracker := newTokenUsageTracker(time.Hour)
+		tracker.record(global, now)
+		tracker.record(local, now)
+
+		l, g := tracker.drain(1, now)
+		require.Len(t, append(l, g...), 1)
+		l, g = tracker.drain(1, now)
+		require.Len(t, append(l, g...), 1)
+	})
+
+	t.Run("requeue", func(t *testing.T) {
+		tracker := newTokenUsageTracker(time.Hour)
+		tracker.record(local, now)
+
+		l, _ := tracker.drain(10, now)
+		require.Len(t, l, 1)
+		tracker.requeue(l, true)
+
+		l, _ = tracker.drain(10, now)
+		require.Equal(t, []structs.ACLTokenUsage{{AccessorID: local.AccessorID, LastUsedAt: now}}, l)
+	})
+}
+
+func TestACLTokenUsage_Flush(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.PrimaryDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLInitialManagementToken = "root"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	token, err := upsertTestToken(codec, "root", "dc1", nil)
+	require.NoError(t, err)
+	require.Nil(t, token.LastUsedAt)
+
+	before := time.Now()
+	_, err = s1.ACLResolver.ResolveToken(token.SecretID)
+	require.NoError(t, err)
+
+	require.NoError(t, s1.flushACLTokenUsage())
+
+	_, used, err := s1.fsm.State().ACLTokenGetByAccessor(nil, token.AccessorID, nil)
+	require.NoError(t, err)
+	require.NotNil(t, used.LastUsedAt)
+	require.False(t, used.LastUsedAt.Before(before))
+
+	// Recording the usage doesn't count as a modification of the token.
+	require.Equal(t, token.ModifyIndex, used.ModifyIndex)
+	require.Equal(t, token.Hash, used.Hash)
+
+	// Updating the token keeps its usage.
+	updated, err := upsertTestToken(codec, "root", "dc1", func(t *structs.ACLToken) {
+		t.AccessorID = token.AccessorID
+		t.SecretID = token.SecretID
+		t.Description = "updated"
+	})
+	require.NoError(t, err)
+	require.NotNil(t, updated.LastUsedAt)
+	require.True(t, updated.LastUsedAt.Equal(*used.LastUsedAt))
+}
+
+func TestACLTokenUsage_MinimumVersion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.PrimaryDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLInitialManagementToken = "root"
+		c.Build = "1.16.0"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	token, err := upsertTestToken(codec, "root", "dc1", nil)
+	require.NoError(t, err)
+
+	_, err = s1.ACLResolver.ResolveToken(token.SecretID)
+	require.NoError(t, err)
+
+	// The usage stays pending until all the servers are able to apply it.
+	require.NoError(t, s1.flushACLTokenUsage())
+	_, used, err := s1.fsm.State().ACLTokenGetByAccessor(nil, token.AccessorID, nil)
+	require.NoError(t, err)
+	require.Nil(t, used.LastUsedAt)
+	local, global := s1.ACLResolver.tokenUsage.drain(aclTokenUsageBatchSize, time.Now())
+	var pending []string
+	for _, u := range append(local, global...) {
+		pending = append(pending, u.AccessorID)
+	}
+	require.Contains(t, pending, token.AccessorID)
+
+	req := structs.ACLTokenUsageRequest{
+		Datacenter:   "dc1",
+		Usage:        []structs.ACLTokenUsage{{AccessorID: token.AccessorID, LastUsedAt: time.Now()}},
+		WriteRequest: structs.WriteRequest{Token: "root"},
+	}
+	var out bool
+	err = msgpackrpc.CallWithCodec(codec, "ACL.TokenUsageUpdate", &req, &out)
+	require.ErrorContains(t, err, "all servers must be running at least Consul 1.17.0")
+}
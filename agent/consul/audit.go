@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package consul
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/consul-net-rpc/net/rpc"
+
+	"github.com/hashicorp/consul/agent/audit"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// auditRPCInterceptor wraps next so that every RPC handled by the server is
+// written to the audit log once it completes. next may be nil.
+func (s *Server) auditRPCInterceptor(auditor *audit.Auditor, next rpc.ServerServiceCallInterceptor) rpc.ServerServiceCallInterceptor {
+	return func(reqServiceMethod string, argv, replyv reflect.Value, handler func() error) {
+		if !auditor.Matches(reqServiceMethod) {
+			if next != nil {
+				next(reqServiceMethod, argv, replyv, handler)
+			} else {
+				handler()
+			}
+			return
+		}
+
+		start := time.Now()
+		var err error
+		audited := func() error {
+			err = handler()
+			return err
+		}
+		if next != nil {
+			next(reqServiceMethod, argv, replyv, audited)
+		} else {
+			audited()
+		}
+
+		ev := audit.NewRPCEvent(reqServiceMethod, argv.Interface(), start, err)
+		ev.Auth.AccessorID = s.auditAccessorID(ev.Auth.SecretID)
+		auditor.Write(ev)
+	}
+}
+
+// auditAccessorID returns the accessor ID of the token with the given secret,
+// or an empty string if it cannot be resolved.
+func (s *Server) auditAccessorID(secretID string) string {
+	if s.ACLResolver == nil {
+		return ""
+	}
+	authz, err := s.ACLResolver.ResolveToken(secretID)
+	if err != nil {
+		return ""
+	}
+	return authz.AccessorID()
+}
+
+// auditBreakGlass writes the issuance of a break-glass token to the audit log,
+// if it is enabled.
+func (s *Server) auditBreakGlass(token *structs.ACLToken) {
+	if s.auditor == nil || token.BreakGlass == nil {
+		return
+	}
+
+	bg := audit.BreakGlass{
+		AccessorID:  token.AccessorID,
+		Reason:      token.BreakGlass.Reason,
+		RequestedBy: token.BreakGlass.RequestedBy,
+		ApprovedBy:  token.BreakGlass.ApprovedBy,
+	}
+	if token.ExpirationTime != nil {
+		bg.ExpirationTime = *token.ExpirationTime
+	}
+	s.auditor.Write(audit.NewBreakGlassEvent(s.config.Datacenter, bg))
+}
@@ -6,6 +6,8 @@ package auth
 import (
 	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/hashicorp/go-bexpr"
 	"github.com/hashicorp/go-memdb"
@@ -36,6 +38,7 @@ func NewBinder(store BinderStateStore, datacenter string) *Binder {
 type BinderStateStore interface {
 	ACLBindingRuleList(ws memdb.WatchSet, methodName string, entMeta *acl.EnterpriseMeta) (uint64, structs.ACLBindingRules, error)
 	ACLRoleGetByName(ws memdb.WatchSet, roleName string, entMeta *acl.EnterpriseMeta) (uint64, *structs.ACLRole, error)
+	ACLTemplatedPolicyBaseGetByName(ws memdb.WatchSet, name string) (uint64, *structs.ACLTemplatedPolicyBase, error)
 }
 
 // Bindings contains the ACL roles, service identities, node identities,
@@ -46,6 +49,10 @@ type Bindings struct {
 	NodeIdentities    []*structs.ACLNodeIdentity
 	TemplatedPolicies structs.ACLTemplatedPolicies
 	EnterpriseMeta    acl.EnterpriseMeta
+
+	// Lineage records the binding rules that matched and the identity fields
+	// they were matched on.
+	Lineage *structs.ACLTokenLineage
 }
 
 // None indicates that the resulting bindings would not give the created token
@@ -88,6 +95,7 @@ func (b *Binder) Bind(authMethod *structs.ACLAuthMethod, verifiedIdentity *authm
 	if len(matchingRules) == 0 {
 		return &bindings, nil
 	}
+	bindings.Lineage = lineage(matchingRules, verifiedIdentity)
 
 	// Compute role, service identity, node identity or templated policy names by interpolating
 	// the identity's projected variables into the rule BindName templates.
@@ -113,7 +121,7 @@ func (b *Binder) Bind(authMethod *structs.ACLAuthMethod, verifiedIdentity *authm
 			})
 
 		case structs.BindingRuleBindTypeTemplatedPolicy:
-			templatedPolicy, err := generateTemplatedPolicies(rule.BindName, rule.BindVars, verifiedIdentity.ProjectedVars)
+			templatedPolicy, err := generateTemplatedPolicies(b.store, rule.BindName, rule.BindVars, verifiedIdentity.ProjectedVars)
 			if err != nil {
 				return nil, err
 			}
@@ -143,7 +151,7 @@ func (b *Binder) Bind(authMethod *structs.ACLAuthMethod, verifiedIdentity *authm
 
 // IsValidBindingRule returns whether the given BindName and/or BindVars template produces valid
 // results when interpolating the auth method's available variables.
-func IsValidBindingRule(bindType, bindName string, bindVars *structs.ACLTemplatedPolicyVariables, availableVariables []string) error {
+func IsValidBindingRule(store BinderStateStore, bindType, bindName string, bindVars *structs.ACLTemplatedPolicyVariables, availableVariables []string) error {
 	if bindType == "" || bindName == "" {
 		return errors.New("bindType and bindName must not be empty")
 	}
@@ -165,11 +173,7 @@ func IsValidBindingRule(bindType, bindName string, bindVars *structs.ACLTemplate
 		}
 
 	case structs.BindingRuleBindTypeTemplatedPolicy:
-		// If user-defined templated policies are supported in the future,
-		// we will need to lookup state to ensure a template exists for given
-		// bindName. A possible solution is to rip out the check for templated
-		// policy into its own step which has access to the state store.
-		if _, err := generateTemplatedPolicies(bindName, bindVars, fakeVarMap); err != nil {
+		if _, err := generateTemplatedPolicies(store, bindName, bindVars, fakeVarMap); err != nil {
 			return fmt.Errorf("failed to validate bindType %q: %w", bindType, err)
 		}
 
@@ -201,12 +205,16 @@ func computeBindName(bindName string, projectedVars map[string]string, validate
 // bindVars with any given variables in projectedVars. The resulting template is validated
 // by the template's schema.
 func generateTemplatedPolicies(
+	store BinderStateStore,
 	bindName string,
 	bindVars *structs.ACLTemplatedPolicyVariables,
 	projectedVars map[string]string,
 ) (*structs.ACLTemplatedPolicy, error) {
-	baseTemplate, ok := structs.GetACLTemplatedPolicyBase(bindName)
-	if !ok {
+	_, baseTemplate, err := store.ACLTemplatedPolicyBaseGetByName(nil, bindName)
+	if err != nil {
+		return nil, err
+	}
+	if baseTemplate == nil {
 		return nil, fmt.Errorf("Bind name for templated-policy bind type does not match existing template name: %s", bindName)
 	}
 
@@ -245,6 +253,122 @@ func computeBindVars(bindVars *structs.ACLTemplatedPolicyVariables, projectedVar
 	return out, nil
 }
 
+// lineage records the matching rules along with a snapshot of the identity.
+// The snapshot holds the values of the fields the rules' selectors refer to,
+// and the names of the identity's other fields with their values redacted.
+func lineage(matchingRules structs.ACLBindingRules, verifiedIdentity *authmethod.Identity) *structs.ACLTokenLineage {
+	out := &structs.ACLTokenLineage{
+		SelectableFields: make(map[string]string),
+	}
+	for name := range verifiedIdentity.ProjectedVars {
+		out.SelectableFields[name] = structs.RedactedSelectableField
+	}
+
+	for _, rule := range matchingRules {
+		out.BindingRuleIDs = append(out.BindingRuleIDs, rule.ID)
+
+		for _, selector := range selectorFields(rule.Selector) {
+			if value, ok := selectableFieldValue(verifiedIdentity.SelectableFields, selector); ok {
+				out.SelectableFields[selector.String()] = value
+			}
+		}
+	}
+
+	if len(out.SelectableFields) == 0 {
+		out.SelectableFields = nil
+	}
+	return out
+}
+
+// selectorFields returns the fields the selector refers to.
+func selectorFields(selector string) []bexpr.Selector {
+	if selector == "" {
+		return nil
+	}
+	ast, err := bexpr.Parse("", []byte(selector))
+	if err != nil {
+		return nil
+	}
+
+	var fields []bexpr.Selector
+	var walk func(expr bexpr.Expression)
+	walk = func(expr bexpr.Expression) {
+		switch e := expr.(type) {
+		case *bexpr.UnaryExpression:
+			walk(e.Operand)
+		case *bexpr.BinaryExpression:
+			walk(e.Left)
+			walk(e.Right)
+		case *bexpr.MatchExpression:
+			fields = append(fields, e.Selector)
+		}
+	}
+	if expr, ok := ast.(bexpr.Expression); ok {
+		walk(expr)
+	}
+	return fields
+}
+
+// selectableFieldValue looks up the field the selector refers to the same way
+// the selector is evaluated, and formats its value.
+func selectableFieldValue(fields interface{}, selector bexpr.Selector) (string, bool) {
+	v := reflect.ValueOf(fields)
+	for _, part := range selector {
+		v = reflect.Indirect(v)
+		switch v.Kind() {
+		case reflect.Struct:
+			field, ok := structFieldBySelector(v, part)
+			if !ok {
+				return "", false
+			}
+			v = field
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return "", false
+			}
+			v = v.MapIndex(reflect.ValueOf(part).Convert(v.Type().Key()))
+		default:
+			return "", false
+		}
+		if !v.IsValid() {
+			return "", false
+		}
+	}
+
+	v = reflect.Indirect(v)
+	if !v.IsValid() {
+		return "", false
+	}
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		values := make([]string, v.Len())
+		for i := range values {
+			values[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(values, ","), true
+	}
+	return fmt.Sprint(v.Interface()), true
+}
+
+func structFieldBySelector(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldName := field.Name
+		if tag := field.Tag.Get("bexpr"); tag == "-" {
+			continue
+		} else if tag != "" {
+			fieldName = tag
+		}
+		if fieldName == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
 // doesSelectorMatch checks that a single selector matches the provided vars.
 func doesSelectorMatch(selector string, selectableVars interface{}) bool {
 	if selector == "" {
@@ -96,6 +96,72 @@ func TestBinder_Roles_Success(t *testing.T) {
 	}, result.Roles)
 }
 
+func TestBinder_Lineage(t *testing.T) {
+	store := testStateStore(t)
+	binder := &Binder{store: store}
+
+	authMethod := &structs.ACLAuthMethod{
+		Name: "test-auth-method",
+		Type: "testing",
+	}
+	require.NoError(t, store.ACLAuthMethodSet(0, authMethod))
+
+	bindingRules := structs.ACLBindingRules{
+		{
+			ID:         generateID(t),
+			Selector:   "tier==web and not (contractors in groups)",
+			BindType:   structs.BindingRuleBindTypeService,
+			BindName:   "web-service-${name}",
+			AuthMethod: authMethod.Name,
+		},
+		{
+			ID:         generateID(t),
+			BindType:   structs.BindingRuleBindTypeNode,
+			BindName:   "node-${name}",
+			AuthMethod: authMethod.Name,
+		},
+		{
+			ID:         generateID(t),
+			Selector:   "tier==db",
+			BindType:   structs.BindingRuleBindTypeService,
+			BindName:   "database-${name}",
+			AuthMethod: authMethod.Name,
+		},
+	}
+	require.NoError(t, store.ACLBindingRuleBatchSet(0, bindingRules))
+
+	result, err := binder.Bind(&structs.ACLAuthMethod{}, &authmethod.Identity{
+		SelectableFields: &struct {
+			Tier   string   `bexpr:"tier"`
+			Team   string   `bexpr:"team"`
+			Groups []string `bexpr:"groups"`
+			Secret string   `bexpr:"secret"`
+		}{
+			Tier:   "web",
+			Team:   "payments",
+			Groups: []string{"admins", "ops"},
+			Secret: "hunter2",
+		},
+		ProjectedVars: map[string]string{
+			"name":   "billing",
+			"secret": "hunter2",
+		},
+	})
+	require.NoError(t, err)
+
+	// Only the fields the matching rules selected on are recorded, the values
+	// of other identity fields are redacted or left out.
+	require.Equal(t, &structs.ACLTokenLineage{
+		BindingRuleIDs: []string{bindingRules[0].ID, bindingRules[1].ID},
+		SelectableFields: map[string]string{
+			"tier":   "web",
+			"groups": "admins,ops",
+			"name":   structs.RedactedSelectableField,
+			"secret": structs.RedactedSelectableField,
+		},
+	}, result.Lineage)
+}
+
 func TestBinder_Roles_NameValidation(t *testing.T) {
 	store := testStateStore(t)
 	binder := &Binder{store: store}
@@ -353,6 +419,7 @@ func Test_IsValidBindingRule(t *testing.T) {
 			t.Run(test.bindType+"--"+test.name, func(t *testing.T) {
 				t.Parallel()
 				err := IsValidBindingRule(
+					testStateStore(t),
 					test.bindType,
 					test.bindName,
 					test.bindVars,
@@ -49,6 +49,7 @@ func (l *Login) TokenForVerifiedIdentity(identity *authmethod.Identity, authMeth
 		TemplatedPolicies: bindings.TemplatedPolicies,
 		Roles:             bindings.Roles,
 		EnterpriseMeta:    bindings.EnterpriseMeta,
+		Lineage:           bindings.Lineage,
 	}
 	token.ACLAuthMethodEnterpriseMeta.FillWithEnterpriseMeta(&authMethod.EnterpriseMeta)
 
@@ -59,12 +59,46 @@ type TokenWriterStore interface {
 	ACLRoleGetByName(ws memdb.WatchSet, name string, entMeta *acl.EnterpriseMeta) (uint64, *structs.ACLRole, error)
 	ACLPolicyGetByID(ws memdb.WatchSet, id string, entMeta *acl.EnterpriseMeta) (uint64, *structs.ACLPolicy, error)
 	ACLPolicyGetByName(ws memdb.WatchSet, name string, entMeta *acl.EnterpriseMeta) (uint64, *structs.ACLPolicy, error)
+	ACLTemplatedPolicyBaseGetByName(ws memdb.WatchSet, name string) (uint64, *structs.ACLTemplatedPolicyBase, error)
 	ACLTokenUpsertValidateEnterprise(token *structs.ACLToken, existing *structs.ACLToken) error
 }
 
 // Create a new token. Setting fromLogin to true changes behavior slightly for
 // tokens created by login (as opposed to set manually via the API).
 func (w *TokenWriter) Create(token *structs.ACLToken, fromLogin bool) (*structs.ACLToken, error) {
+	// Break-glass tokens can only be created with CreateBreakGlass.
+	token.BreakGlass = nil
+
+	return w.create(token, fromLogin)
+}
+
+// CreateBreakGlass creates an emergency token granting global-management
+// access. The token must have a BreakGlass record and an ExpirationTTL no
+// longer than maxTTL.
+func (w *TokenWriter) CreateBreakGlass(token *structs.ACLToken, maxTTL time.Duration) (*structs.ACLToken, error) {
+	switch {
+	case token.BreakGlass == nil:
+		return nil, errors.New("BreakGlass field is required for break-glass tokens")
+	case token.BreakGlass.Reason == "":
+		return nil, errors.New("A reason is required for break-glass tokens")
+	case token.ExpirationTTL <= 0:
+		return nil, errors.New("Break-glass tokens require a TTL")
+	case token.ExpirationTTL > maxTTL:
+		return nil, fmt.Errorf("Break-glass token TTL cannot be more than %s (was %s)", maxTTL, token.ExpirationTTL)
+	case token.HasExpirationTime():
+		return nil, errors.New("Break-glass tokens cannot set an ExpirationTime")
+	}
+
+	token.Policies = []structs.ACLTokenPolicyLink{{ID: structs.ACLPolicyGlobalManagementID}}
+	token.Roles = nil
+	token.ServiceIdentities = nil
+	token.NodeIdentities = nil
+	token.TemplatedPolicies = nil
+
+	return w.create(token, false)
+}
+
+func (w *TokenWriter) create(token *structs.ACLToken, fromLogin bool) (*structs.ACLToken, error) {
 	if err := w.checkCanWriteToken(token); err != nil {
 		return nil, err
 	}
@@ -109,6 +143,11 @@ func (w *TokenWriter) Create(token *structs.ACLToken, fromLogin bool) (*structs.
 
 	token.CreateTime = time.Now()
 
+	// Rotation state is managed by the servers only.
+	token.RotationTime = nil
+	token.PreviousSecretID = ""
+	token.PreviousSecretExpirationTime = nil
+
 	// Ensure ExpirationTTL is valid if provided.
 	if token.ExpirationTTL < 0 {
 		return nil, fmt.Errorf("Token Expiration TTL '%s' should be > 0", token.ExpirationTTL)
@@ -148,6 +187,8 @@ func (w *TokenWriter) Create(token *structs.ACLToken, fromLogin bool) (*structs.
 		if token.AuthMethod != "" {
 			return nil, errors.New("AuthMethod field is disallowed outside of login")
 		}
+		// Lineage is only recorded by logins.
+		token.Lineage = nil
 	}
 
 	return w.write(token, nil, fromLogin)
@@ -213,9 +254,99 @@ func (w *TokenWriter) Update(token *structs.ACLToken) (*structs.ACLToken, error)
 
 	token.CreateTime = match.CreateTime
 
+	// Rotation state is managed by the servers only.
+	token.RotationTime = match.RotationTime
+	token.PreviousSecretID = match.PreviousSecretID
+	token.PreviousSecretExpirationTime = match.PreviousSecretExpirationTime
+
+	// Lineage is recorded when the token is issued and never changes.
+	token.Lineage = match.Lineage
+
+	// As is the break-glass record.
+	token.BreakGlass = match.BreakGlass
+
 	return w.write(token, match, false)
 }
 
+// Rotate replaces the SecretID of the token with the given AccessorID with a
+// newly generated one. The current SecretID remains valid for gracePeriod so
+// that consumers of the token can pick up the new SecretID without an outage.
+// Rotating a token again during the grace period invalidates the SecretID that
+// was replaced by the earlier rotation. Servers older than the rotation
+// support refuse to change a SecretID, so callers must first check that all
+// the servers in the datacenter are able to apply the rotation.
+func (w *TokenWriter) Rotate(accessorID string, gracePeriod time.Duration, entMeta *acl.EnterpriseMeta) (*structs.ACLToken, error) {
+	if gracePeriod < 0 {
+		return nil, fmt.Errorf("Token rotation grace period '%s' should be >= 0", gracePeriod)
+	}
+
+	_, match, err := w.Store.ACLTokenGetByAccessor(nil, accessorID, entMeta)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("Failed acl token lookup by accessor: %w", err)
+	case match == nil || match.IsExpired(time.Now()):
+		return nil, fmt.Errorf("Cannot find token %q: %w", accessorID, acl.ErrNotFound)
+	case match.AccessorID == acl.AnonymousTokenID:
+		return nil, errors.New("Cannot rotate the anonymous token")
+	}
+
+	if err := w.checkCanWriteToken(match); err != nil {
+		return nil, err
+	}
+
+	secretID, err := lib.GenerateUUID(w.CheckUUID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to generate SecretID: %w", err)
+	}
+
+	now := time.Now()
+	token := match.Clone()
+	token.SecretID = secretID
+	token.RotationTime = &now
+	token.PreviousSecretID = ""
+	token.PreviousSecretExpirationTime = nil
+	if gracePeriod > 0 {
+		expirationTime := now.Add(gracePeriod)
+		// There is no point in accepting the old SecretID for longer than the
+		// token itself is valid.
+		if token.HasExpirationTime() && token.ExpirationTime.Before(expirationTime) {
+			expirationTime = *token.ExpirationTime
+		}
+		token.PreviousSecretID = match.SecretID
+		token.PreviousSecretExpirationTime = &expirationTime
+	}
+	token.SetHash(true)
+
+	_, err = w.RaftApply(structs.ACLTokenSetRequestType, &structs.ACLTokenBatchSetRequest{
+		Tokens: structs.ACLTokens{token},
+		// Guard against racing with another update of the same token.
+		CAS:                 true,
+		AllowMissingLinks:   true,
+		AllowSecretRotation: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to apply token rotation request: %w", err)
+	}
+
+	// The SecretID replaced by an earlier rotation is no longer valid, and
+	// neither is the current one if there is no grace period.
+	if match.PreviousSecretID != "" {
+		w.ACLCache.RemoveIdentityWithSecretToken(match.PreviousSecretID)
+	}
+	if gracePeriod == 0 {
+		w.ACLCache.RemoveIdentityWithSecretToken(match.SecretID)
+	}
+
+	_, updatedToken, err := w.Store.ACLTokenGetByAccessor(nil, token.AccessorID, nil)
+	switch {
+	case err != nil || updatedToken == nil:
+		return nil, errors.New("Failed to retrieve token after rotation")
+	case updatedToken.SecretID != secretID:
+		return nil, fmt.Errorf("Token %q was modified concurrently, please retry the rotation", accessorID)
+	}
+	return updatedToken, nil
+}
+
 // Delete the ACL token with the given SecretID from the state store.
 func (w *TokenWriter) Delete(secretID string, fromLogout bool) error {
 	_, token, err := w.Store.ACLTokenGetBySecret(nil, secretID, nil)
@@ -239,6 +370,9 @@ func (w *TokenWriter) Delete(secretID string, fromLogout bool) error {
 	}
 
 	w.ACLCache.RemoveIdentityWithSecretToken(token.SecretID)
+	if token.PreviousSecretID != "" {
+		w.ACLCache.RemoveIdentityWithSecretToken(token.PreviousSecretID)
+	}
 	return nil
 }
 
@@ -460,16 +594,18 @@ func (w *TokenWriter) normalizeTemplatedPolicies(templatedPolicies structs.ACLTe
 			return nil, errors.New("templated policy is missing the template name field on this token")
 		}
 
-		tmp, ok := structs.GetACLTemplatedPolicyBase(templatedPolicy.TemplateName)
-		if !ok {
+		_, tmp, err := w.Store.ACLTemplatedPolicyBaseGetByName(nil, templatedPolicy.TemplateName)
+		if err != nil {
+			return nil, err
+		}
+		if tmp == nil {
 			return nil, fmt.Errorf("no such ACL templated policy with Name %q", templatedPolicy.TemplateName)
 		}
 
 		out := templatedPolicy.Clone()
 		out.TemplateID = tmp.TemplateID
 
-		err := templatedPolicy.ValidateTemplatedPolicy(tmp.Schema)
-		if err != nil {
+		if err := templatedPolicy.ValidateTemplatedPolicy(tmp.Schema); err != nil {
 			return nil, fmt.Errorf("validation error for templated policy %q: %w", templatedPolicy.TemplateName, err)
 		}
 		finalPolicies = append(finalPolicies, out)
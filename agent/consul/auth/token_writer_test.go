@@ -487,6 +487,124 @@ func TestTokenWriter_Create_Success(t *testing.T) {
 	require.NotNil(t, updated)
 }
 
+func TestTokenWriter_CreateBreakGlass(t *testing.T) {
+	aclCache := &MockACLCache{}
+	aclCache.On("RemoveIdentityWithSecretToken", mock.Anything)
+
+	store := testStateStore(t)
+	require.NoError(t, store.ACLPolicySet(0, &structs.ACLPolicy{
+		ID:    structs.ACLPolicyGlobalManagementID,
+		Name:  "global-management",
+		Rules: structs.ACLPolicyGlobalManagementRules,
+	}))
+
+	role := &structs.ACLRole{
+		ID:   generateID(t),
+		Name: generateID(t),
+	}
+	require.NoError(t, store.ACLRoleSet(0, role))
+
+	writer := buildTokenWriter(store, aclCache)
+
+	breakGlass := func() *structs.ACLTokenBreakGlass {
+		return &structs.ACLTokenBreakGlass{
+			Reason:      "incident",
+			RequestedBy: generateID(t),
+			ApprovedBy:  generateID(t),
+		}
+	}
+
+	testCases := map[string]struct {
+		token         structs.ACLToken
+		errorContains string
+	}{
+		"No break-glass record": {
+			token:         structs.ACLToken{ExpirationTTL: time.Hour},
+			errorContains: "BreakGlass field is required",
+		},
+		"No reason": {
+			token: structs.ACLToken{
+				ExpirationTTL: time.Hour,
+				BreakGlass:    &structs.ACLTokenBreakGlass{},
+			},
+			errorContains: "A reason is required",
+		},
+		"No TTL": {
+			token:         structs.ACLToken{BreakGlass: breakGlass()},
+			errorContains: "require a TTL",
+		},
+		"TTL too long": {
+			token: structs.ACLToken{
+				ExpirationTTL: 5 * time.Hour,
+				BreakGlass:    breakGlass(),
+			},
+			errorContains: "cannot be more than 4h0m0s",
+		},
+		"TTL too short": {
+			token: structs.ACLToken{
+				ExpirationTTL: time.Second,
+				BreakGlass:    breakGlass(),
+			},
+			errorContains: "cannot be less than 1m0s",
+		},
+		"Expiration time": {
+			token: structs.ACLToken{
+				ExpirationTTL:  time.Hour,
+				ExpirationTime: timePointer(time.Now().Add(time.Hour)),
+				BreakGlass:     breakGlass(),
+			},
+			errorContains: "cannot set an ExpirationTime",
+		},
+	}
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			token := tc.token
+			_, err := writer.CreateBreakGlass(&token, 4*time.Hour)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errorContains)
+		})
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		record := breakGlass()
+		token := &structs.ACLToken{
+			Local:         true,
+			ExpirationTTL: time.Hour,
+			BreakGlass:    record,
+			// The links of break-glass tokens are always replaced.
+			Roles: []structs.ACLTokenRoleLink{{ID: role.ID}},
+		}
+
+		created, err := writer.CreateBreakGlass(token, 4*time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, record, created.BreakGlass)
+		require.Equal(t, []structs.ACLTokenPolicyLink{
+			{ID: structs.ACLPolicyGlobalManagementID, Name: "global-management"},
+		}, created.Policies)
+		require.Empty(t, created.Roles)
+		require.InEpsilon(t, time.Hour, created.ExpirationTime.Sub(time.Now()), 0.1)
+
+		// The break-glass record survives updates.
+		update := created.Clone()
+		update.BreakGlass = nil
+		update.Description = "updated"
+		updated, err := writer.Update(update)
+		require.NoError(t, err)
+		require.Equal(t, record, updated.BreakGlass)
+	})
+
+	t.Run("Create ignores the break-glass record", func(t *testing.T) {
+		token := &structs.ACLToken{
+			Roles:      []structs.ACLTokenRoleLink{{ID: role.ID}},
+			BreakGlass: breakGlass(),
+		}
+
+		created, err := writer.Create(token, false)
+		require.NoError(t, err)
+		require.Nil(t, created.BreakGlass)
+	})
+}
+
 func TestTokenWriter_Update_Validation(t *testing.T) {
 	aclCache := &MockACLCache{}
 	aclCache.On("RemoveIdentityWithSecretToken", mock.Anything)
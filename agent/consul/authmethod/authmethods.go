@@ -58,6 +58,23 @@ type Validator interface {
 	Stop()
 }
 
+// OIDCValidator is implemented by validators that support the OIDC
+// authorization code flow, where the user authenticates with the provider in
+// a browser rather than presenting a bearer token.
+type OIDCValidator interface {
+	Validator
+
+	// GetAuthCodeURL returns the provider URL the user should visit to
+	// authenticate. The payload is stored with the generated state and
+	// returned by ValidateAuthCode.
+	GetAuthCodeURL(ctx context.Context, redirectURI string, payload interface{}) (string, error)
+
+	// ValidateAuthCode exchanges the state and code the provider redirected
+	// the user back with for an identity, and returns the payload passed to
+	// GetAuthCodeURL.
+	ValidateAuthCode(ctx context.Context, state, code string) (*Identity, interface{}, error)
+}
+
 type Identity struct {
 	// SelectableFields is the format of this Identity suitable for selection
 	// with a binding rule.
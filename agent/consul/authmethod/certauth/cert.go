@@ -0,0 +1,261 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package certauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/agent/consul/authmethod"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+const (
+	authMethodType string = "cert"
+
+	// maxLoginTokenLifetime is the longest a signed login token may be valid
+	// for, limiting the window in which it can be replayed.
+	maxLoginTokenLifetime = 5 * time.Minute
+
+	// clockSkewLeeway is the clock skew tolerated between the client that
+	// signed a login token and the server validating it.
+	clockSkewLeeway = time.Minute
+)
+
+func init() {
+	// register this as an available auth method type
+	authmethod.Register(authMethodType, func(logger hclog.Logger, method *structs.ACLAuthMethod) (authmethod.Validator, error) {
+		v, err := NewValidator(logger, method)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+}
+
+type Config struct {
+	// CACerts are the PEM encoded CA certificates that client certificates
+	// must chain to. Each entry may contain a bundle of several certificates.
+	CACerts []string `json:",omitempty"`
+}
+
+type Validator struct {
+	name   string
+	logger hclog.Logger
+
+	roots *x509.CertPool
+
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+}
+
+var _ authmethod.Validator = (*Validator)(nil)
+
+func NewValidator(logger hclog.Logger, method *structs.ACLAuthMethod) (*Validator, error) {
+	if method.Type != authMethodType {
+		return nil, fmt.Errorf("%q is not a cert auth method", method.Name)
+	}
+
+	var config Config
+	if err := authmethod.ParseConfig(method.Config, &config); err != nil {
+		return nil, err
+	}
+
+	if len(config.CACerts) == 0 {
+		return nil, fmt.Errorf("CACerts is required")
+	}
+	roots := x509.NewCertPool()
+	for i, bundle := range config.CACerts {
+		if !roots.AppendCertsFromPEM([]byte(bundle)) {
+			return nil, fmt.Errorf("CACerts[%d] does not contain a valid PEM encoded certificate", i)
+		}
+	}
+
+	return &Validator{
+		name:   method.Name,
+		logger: logger,
+		roots:  roots,
+		now:    time.Now,
+	}, nil
+}
+
+// Name implements authmethod.Validator.
+func (v *Validator) Name() string { return v.name }
+
+// Stop implements authmethod.Validator.
+func (v *Validator) Stop() {}
+
+// ValidateLogin implements authmethod.Validator. The login token is a JWT
+// signed with the client certificate's private key, carrying the certificate
+// chain in its x5c header, as created by NewLoginToken.
+func (v *Validator) ValidateLogin(ctx context.Context, loginToken string) (*authmethod.Identity, error) {
+	tok, err := jwt.ParseSigned(loginToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse login token: %w", err)
+	}
+	if len(tok.Headers) != 1 {
+		return nil, errors.New("login token must have exactly one signature")
+	}
+
+	chains, err := tok.Headers[0].Certificates(v.verifyOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify client certificate: %w", err)
+	}
+	leaf := chains[0][0]
+
+	var claims jwt.Claims
+	if err := tok.Claims(leaf.PublicKey, &claims); err != nil {
+		return nil, fmt.Errorf("failed to verify login token signature: %w", err)
+	}
+
+	now := v.now()
+	if claims.Expiry == nil || claims.IssuedAt == nil {
+		return nil, errors.New("login token must include the exp and iat claims")
+	}
+	if claims.Expiry.Time().Sub(claims.IssuedAt.Time()) > maxLoginTokenLifetime {
+		return nil, fmt.Errorf("login token must not be valid for longer than %s", maxLoginTokenLifetime)
+	}
+	expected := jwt.Expected{
+		Audience: jwt.Audience{v.name},
+		Time:     now,
+	}
+	if err := claims.ValidateWithLeeway(expected, clockSkewLeeway); err != nil {
+		return nil, fmt.Errorf("invalid login token: %w", err)
+	}
+
+	return v.identityFromCertificate(leaf), nil
+}
+
+func (v *Validator) verifyOptions() x509.VerifyOptions {
+	return x509.VerifyOptions{
+		Roots:       v.roots,
+		CurrentTime: v.now(),
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+}
+
+func (v *Validator) identityFromCertificate(cert *x509.Certificate) *authmethod.Identity {
+	fields := &certSelectableFields{
+		CommonName:          cert.Subject.CommonName,
+		SerialNumber:        connect.HexString(cert.SerialNumber.Bytes()),
+		OrganizationalUnits: cert.Subject.OrganizationalUnit,
+		DNSNames:            cert.DNSNames,
+		EmailAddresses:      cert.EmailAddresses,
+	}
+	for _, uri := range cert.URIs {
+		fields.URIs = append(fields.URIs, uri.String())
+	}
+
+	id := v.NewIdentity()
+	id.SelectableFields = fields
+	id.ProjectedVars["common_name"] = fields.CommonName
+	id.ProjectedVars["serial_number"] = fields.SerialNumber
+	id.ProjectedVars["organizational_unit"] = first(fields.OrganizationalUnits)
+	id.ProjectedVars["dns_san"] = first(fields.DNSNames)
+	id.ProjectedVars["email_san"] = first(fields.EmailAddresses)
+	id.ProjectedVars["uri_san"] = first(fields.URIs)
+	return id
+}
+
+// NewIdentity implements authmethod.Validator.
+func (v *Validator) NewIdentity() *authmethod.Identity {
+	return &authmethod.Identity{
+		SelectableFields: &certSelectableFields{},
+		ProjectedVars: map[string]string{
+			"common_name":         "",
+			"serial_number":       "",
+			"organizational_unit": "",
+			"dns_san":             "",
+			"email_san":           "",
+			"uri_san":             "",
+		},
+	}
+}
+
+type certSelectableFields struct {
+	CommonName          string   `bexpr:"common_name"`
+	SerialNumber        string   `bexpr:"serial_number"`
+	OrganizationalUnits []string `bexpr:"organizational_units"`
+	DNSNames            []string `bexpr:"dns_sans"`
+	EmailAddresses      []string `bexpr:"email_sans"`
+	URIs                []string `bexpr:"uri_sans"`
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// NewLoginToken creates a login token for the named cert auth method, proving
+// possession of the private key for the given certificate chain. The leaf
+// certificate must come first.
+func NewLoginToken(methodName string, chain []*x509.Certificate, key crypto.Signer) (string, error) {
+	if len(chain) == 0 {
+		return "", errors.New("no client certificate provided")
+	}
+
+	alg, err := signatureAlgorithm(key)
+	if err != nil {
+		return "", err
+	}
+
+	x5c := make([]string, 0, len(chain))
+	for _, cert := range chain {
+		x5c = append(x5c, base64.StdEncoding.EncodeToString(cert.Raw))
+	}
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: alg, Key: key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("x5c", x5c),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := jwt.Claims{
+		Subject:  chain[0].Subject.CommonName,
+		Audience: jwt.Audience{methodName},
+		IssuedAt: jwt.NewNumericDate(now),
+		Expiry:   jwt.NewNumericDate(now.Add(maxLoginTokenLifetime)),
+	}
+	return jwt.Signed(signer).Claims(claims).CompactSerialize()
+}
+
+func signatureAlgorithm(key crypto.Signer) (jose.SignatureAlgorithm, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return jose.RS256, nil
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return jose.ES256, nil
+		case elliptic.P384():
+			return jose.ES384, nil
+		case elliptic.P521():
+			return jose.ES512, nil
+		}
+		return "", fmt.Errorf("unsupported elliptic curve %s", k.Curve.Params().Name)
+	case ed25519.PrivateKey:
+		return jose.EdDSA, nil
+	default:
+		return "", fmt.Errorf("unsupported private key type %T", key)
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package certauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/hashicorp/consul/agent/consul/authmethod"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+func TestNewValidator(t *testing.T) {
+	ca := NewTestCA(t)
+
+	type testcase struct {
+		method    *structs.ACLAuthMethod
+		expectErr string
+	}
+
+	cases := map[string]testcase{
+		"wrong type": {
+			method: &structs.ACLAuthMethod{
+				Name:   "cert",
+				Type:   "jwt",
+				Config: map[string]interface{}{"CACerts": []string{ca.CertPEM}},
+			},
+			expectErr: `"cert" is not a cert auth method`,
+		},
+		"missing CACerts": {
+			method: &structs.ACLAuthMethod{
+				Name: "cert",
+				Type: "cert",
+			},
+			expectErr: "CACerts is required",
+		},
+		"invalid CACerts": {
+			method: &structs.ACLAuthMethod{
+				Name:   "cert",
+				Type:   "cert",
+				Config: map[string]interface{}{"CACerts": []string{ca.CertPEM, "not a cert"}},
+			},
+			expectErr: "CACerts[1] does not contain a valid PEM encoded certificate",
+		},
+		"unknown field": {
+			method: &structs.ACLAuthMethod{
+				Name:   "cert",
+				Type:   "cert",
+				Config: map[string]interface{}{"CACerts": []string{ca.CertPEM}, "Foo": "bar"},
+			},
+			expectErr: "invalid keys: Foo",
+		},
+		"valid": {
+			method: &structs.ACLAuthMethod{
+				Name:   "cert",
+				Type:   "cert",
+				Config: map[string]interface{}{"CACerts": []string{ca.CertPEM}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			v, err := NewValidator(hclog.NewNullLogger(), tc.method)
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.method.Name, v.Name())
+		})
+	}
+}
+
+func TestValidator_ValidateLogin(t *testing.T) {
+	ca := NewTestCA(t)
+	otherCA := NewTestCA(t)
+
+	v, err := NewValidator(hclog.NewNullLogger(), &structs.ACLAuthMethod{
+		Name:   "cert-method",
+		Type:   "cert",
+		Config: map[string]interface{}{"CACerts": []string{ca.CertPEM}},
+	})
+	require.NoError(t, err)
+
+	webURI, err := url.Parse("spiffe://example.org/web")
+	require.NoError(t, err)
+
+	chain, key := loadKeyPair(t, ca, "web-client", func(cert *x509.Certificate) {
+		cert.Subject.OrganizationalUnit = []string{"frontend", "payments"}
+		cert.DNSNames = []string{"web.example.org"}
+		cert.EmailAddresses = []string{"web@example.org"}
+		cert.URIs = []*url.URL{webURI}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		token, err := NewLoginToken("cert-method", chain, key)
+		require.NoError(t, err)
+
+		id, err := v.ValidateLogin(context.Background(), token)
+		require.NoError(t, err)
+
+		authmethod.RequireIdentityMatch(t, id, map[string]string{
+			"common_name":         "web-client",
+			"serial_number":       id.ProjectedVars["serial_number"],
+			"organizational_unit": "frontend",
+			"dns_san":             "web.example.org",
+			"email_san":           "web@example.org",
+			"uri_san":             "spiffe://example.org/web",
+		},
+			`common_name == "web-client"`,
+			`"payments" in organizational_units`,
+			`"web.example.org" in dns_sans`,
+			`"web@example.org" in email_sans`,
+			`"spiffe://example.org/web" in uri_sans`,
+		)
+		require.NotEmpty(t, id.ProjectedVars["serial_number"])
+	})
+
+	t.Run("wrong audience", func(t *testing.T) {
+		token, err := NewLoginToken("other-method", chain, key)
+		require.NoError(t, err)
+
+		_, err = v.ValidateLogin(context.Background(), token)
+		require.ErrorContains(t, err, "invalid login token")
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		token, err := NewLoginToken("cert-method", chain, key)
+		require.NoError(t, err)
+
+		v.now = func() time.Time { return time.Now().Add(maxLoginTokenLifetime + 2*clockSkewLeeway) }
+		defer func() { v.now = time.Now }()
+
+		_, err = v.ValidateLogin(context.Background(), token)
+		require.ErrorContains(t, err, "invalid login token")
+	})
+
+	t.Run("lifetime too long", func(t *testing.T) {
+		now := time.Now()
+		token := signLoginToken(t, chain, key, jwt.Claims{
+			Audience: jwt.Audience{"cert-method"},
+			IssuedAt: jwt.NewNumericDate(now),
+			Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+		})
+
+		_, err := v.ValidateLogin(context.Background(), token)
+		require.ErrorContains(t, err, "must not be valid for longer than 5m0s")
+	})
+
+	t.Run("missing expiry", func(t *testing.T) {
+		token := signLoginToken(t, chain, key, jwt.Claims{
+			Audience: jwt.Audience{"cert-method"},
+			IssuedAt: jwt.NewNumericDate(time.Now()),
+		})
+
+		_, err := v.ValidateLogin(context.Background(), token)
+		require.ErrorContains(t, err, "must include the exp and iat claims")
+	})
+
+	t.Run("untrusted CA", func(t *testing.T) {
+		otherChain, otherKey := loadKeyPair(t, otherCA, "web-client", nil)
+		token, err := NewLoginToken("cert-method", otherChain, otherKey)
+		require.NoError(t, err)
+
+		_, err = v.ValidateLogin(context.Background(), token)
+		require.ErrorContains(t, err, "failed to verify client certificate")
+	})
+
+	t.Run("not a client certificate", func(t *testing.T) {
+		serverChain, serverKey := loadKeyPair(t, ca, "web-server", func(cert *x509.Certificate) {
+			cert.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		})
+		token, err := NewLoginToken("cert-method", serverChain, serverKey)
+		require.NoError(t, err)
+
+		_, err = v.ValidateLogin(context.Background(), token)
+		require.ErrorContains(t, err, "failed to verify client certificate")
+	})
+
+	t.Run("signed by a different key", func(t *testing.T) {
+		_, otherKey := loadKeyPair(t, ca, "web-client", nil)
+		token, err := NewLoginToken("cert-method", chain, otherKey)
+		require.NoError(t, err)
+
+		_, err = v.ValidateLogin(context.Background(), token)
+		require.ErrorContains(t, err, "failed to verify login token signature")
+	})
+
+	t.Run("not a signed token", func(t *testing.T) {
+		_, err := v.ValidateLogin(context.Background(), "garbage")
+		require.ErrorContains(t, err, "failed to parse login token")
+	})
+}
+
+func loadKeyPair(t *testing.T, ca *TestCA, commonName string, modify func(*x509.Certificate)) ([]*x509.Certificate, crypto.Signer) {
+	t.Helper()
+
+	certPEM, keyPEM := ca.IssueClientCert(t, commonName, modify)
+	pair, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	require.NoError(t, err)
+
+	var chain []*x509.Certificate
+	for _, raw := range pair.Certificate {
+		cert, err := x509.ParseCertificate(raw)
+		require.NoError(t, err)
+		chain = append(chain, cert)
+	}
+	return chain, pair.PrivateKey.(crypto.Signer)
+}
+
+// signLoginToken signs arbitrary claims the way NewLoginToken does, for
+// exercising the claim validation.
+func signLoginToken(t *testing.T, chain []*x509.Certificate, key crypto.Signer, claims jwt.Claims) string {
+	t.Helper()
+
+	alg, err := signatureAlgorithm(key)
+	require.NoError(t, err)
+
+	x5c := []string{base64.StdEncoding.EncodeToString(chain[0].Raw)}
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: alg, Key: key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("x5c", x5c),
+	)
+	require.NoError(t, err)
+
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
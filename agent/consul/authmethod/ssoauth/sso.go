@@ -14,13 +14,15 @@ import (
 )
 
 func init() {
-	authmethod.Register("jwt", func(logger hclog.Logger, method *structs.ACLAuthMethod) (authmethod.Validator, error) {
+	factory := func(logger hclog.Logger, method *structs.ACLAuthMethod) (authmethod.Validator, error) {
 		v, err := NewValidator(logger, method)
 		if err != nil {
 			return nil, err
 		}
 		return v, nil
-	})
+	}
+	authmethod.Register("jwt", factory)
+	authmethod.Register("oidc", factory)
 }
 
 // Validator is the wrapper around the go-sso library that also conforms to the
@@ -33,7 +35,10 @@ type Validator struct {
 	oa         *oidcauth.Authenticator
 }
 
-var _ authmethod.Validator = (*Validator)(nil)
+var (
+	_ authmethod.Validator     = (*Validator)(nil)
+	_ authmethod.OIDCValidator = (*Validator)(nil)
+)
 
 func NewValidator(logger hclog.Logger, method *structs.ACLAuthMethod) (*Validator, error) {
 	if err := validateType(method.Type); err != nil {
@@ -78,6 +83,21 @@ func (v *Validator) ValidateLogin(ctx context.Context, loginToken string) (*auth
 	return v.identityFromClaims(c), nil
 }
 
+// GetAuthCodeURL implements authmethod.OIDCValidator.
+func (v *Validator) GetAuthCodeURL(ctx context.Context, redirectURI string, payload interface{}) (string, error) {
+	return v.oa.GetAuthCodeURL(ctx, redirectURI, payload)
+}
+
+// ValidateAuthCode implements authmethod.OIDCValidator.
+func (v *Validator) ValidateAuthCode(ctx context.Context, state, code string) (*authmethod.Identity, interface{}, error) {
+	c, payload, err := v.oa.ClaimsFromAuthCode(ctx, state, code)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return v.identityFromClaims(c), payload, nil
+}
+
 func (v *Validator) identityFromClaims(c *oidcauth.Claims) *authmethod.Identity {
 	id := v.NewIdentity()
 	id.SelectableFields = &fieldDetails{
@@ -130,6 +150,14 @@ type Config struct {
 	OIDCDiscoveryURL    string            `json:",omitempty"`
 	OIDCDiscoveryCACert string            `json:",omitempty"`
 
+	// just for type=oidc
+	OIDCClientID        string   `json:",omitempty"`
+	OIDCClientSecret    string   `json:",omitempty"`
+	OIDCScopes          []string `json:",omitempty"`
+	OIDCACRValues       []string `json:",omitempty"`
+	AllowedRedirectURIs []string `json:",omitempty"`
+	VerboseOIDCLogging  bool     `json:",omitempty"`
+
 	// just for type=jwt
 	JWKSURL              string        `json:",omitempty"`
 	JWKSCACert           string        `json:",omitempty"`
@@ -154,6 +182,14 @@ func (c *Config) convertForLibrary(methodType string) *oidcauth.Config {
 		OIDCDiscoveryURL:    c.OIDCDiscoveryURL,
 		OIDCDiscoveryCACert: c.OIDCDiscoveryCACert,
 
+		// just for type=oidc
+		OIDCClientID:        c.OIDCClientID,
+		OIDCClientSecret:    c.OIDCClientSecret,
+		OIDCScopes:          c.OIDCScopes,
+		OIDCACRValues:       c.OIDCACRValues,
+		AllowedRedirectURIs: c.AllowedRedirectURIs,
+		VerboseOIDCLogging:  c.VerboseOIDCLogging,
+
 		// just for type=jwt
 		JWKSURL:              c.JWKSURL,
 		JWKSCACert:           c.JWKSCACert,
@@ -13,10 +13,12 @@ import (
 )
 
 func validateType(typ string) error {
-	if typ != "jwt" {
-		return fmt.Errorf("type should be %q", "jwt")
+	switch typ {
+	case "jwt", "oidc":
+		return nil
+	default:
+		return fmt.Errorf("type should be %q or %q", "jwt", "oidc")
 	}
-	return nil
 }
 
 func (v *Validator) ssoEntMetaFromClaims(_ *oidcauth.Claims) *acl.EnterpriseMeta {
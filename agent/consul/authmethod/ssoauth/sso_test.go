@@ -64,6 +64,17 @@ func TestJWT_NewValidator(t *testing.T) {
 			method.Config["OIDCDiscoveryURL"] = oidcServer.Addr()
 			method.Config["OIDCDiscoveryCACert"] = oidcServer.CACert()
 		}), ""},
+		"oidc - missing client id": {makeAuthMethod("oidc", func(method AM) {
+			method.Config["OIDCDiscoveryURL"] = oidcServer.Addr()
+			method.Config["OIDCDiscoveryCACert"] = oidcServer.CACert()
+		}), "OIDCClientID"},
+		"normal oidc": {makeAuthMethod("oidc", func(method AM) {
+			method.Config["OIDCDiscoveryURL"] = oidcServer.Addr()
+			method.Config["OIDCDiscoveryCACert"] = oidcServer.CACert()
+			method.Config["OIDCClientID"] = "abc"
+			method.Config["OIDCClientSecret"] = "def"
+			method.Config["AllowedRedirectURIs"] = []string{"http://localhost:8550/oidc/callback"}
+		}), ""},
 	} {
 		tc := tc
 		t.Run(name, func(t *testing.T) {
@@ -193,6 +204,61 @@ func TestJWT_ValidateLogin(t *testing.T) {
 	}
 }
 
+func TestOIDC_ValidateAuthCode(t *testing.T) {
+	const redirectURI = "http://localhost:8550/oidc/callback"
+
+	oidcServer := oidcauthtest.Start(t)
+	oidcServer.SetClientCreds("abc", "def")
+	oidcServer.SetAllowedRedirectURIs([]string{redirectURI})
+	oidcServer.SetExpectedAuthCode("authcode")
+	oidcServer.SetCustomClaims(map[string]interface{}{
+		"first_name": "jeff2",
+		"groups":     []string{"foo", "bar"},
+	})
+
+	v, err := NewValidator(hclog.NewNullLogger(), &structs.ACLAuthMethod{
+		Name: "test-oidc",
+		Type: "oidc",
+		Config: map[string]interface{}{
+			"OIDCDiscoveryURL":    oidcServer.Addr(),
+			"OIDCDiscoveryCACert": oidcServer.CACert(),
+			"OIDCClientID":        "abc",
+			"OIDCClientSecret":    "def",
+			"AllowedRedirectURIs": []string{redirectURI},
+			"JWTSupportedAlgs":    []string{"ES256"},
+			"ClaimMappings":       map[string]string{"first_name": "name"},
+			"ListClaimMappings":   map[string]string{"groups": "groups"},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(v.Stop)
+
+	_, err = v.ValidateLogin(context.Background(), "not-a-jwt")
+	testutil.RequireErrorContains(t, err, `incompatible with type "oidc"`)
+
+	authURL, err := v.GetAuthCodeURL(context.Background(), redirectURI, "payload")
+	require.NoError(t, err)
+
+	redirect, err := oidcServer.Authorize(authURL)
+	require.NoError(t, err)
+
+	id, payload, err := v.ValidateAuthCode(context.Background(), redirect.Query().Get("state"), redirect.Query().Get("code"))
+	require.NoError(t, err)
+	require.Equal(t, "payload", payload)
+
+	authmethod.RequireIdentityMatch(t, id, map[string]string{
+		"value.name": "jeff2",
+	},
+		"value.name == jeff2",
+		"foo in list.groups",
+		"bar in list.groups",
+	)
+
+	// The state can only be redeemed once.
+	_, _, err = v.ValidateAuthCode(context.Background(), redirect.Query().Get("state"), redirect.Query().Get("code"))
+	testutil.RequireErrorContains(t, err, "Expired or missing OAuth state")
+}
+
 func TestNewIdentity(t *testing.T) {
 	// This is only based on claim mappings, so we'll just use the JWT type
 	// since that's cheaper to setup.
@@ -6,14 +6,16 @@
 package consul
 
 import (
-	"github.com/hashicorp/consul/agent/metadata"
 	autopilot "github.com/hashicorp/raft-autopilot"
+
+	"github.com/hashicorp/consul/agent/metadata"
+	"github.com/hashicorp/consul/agent/structs"
 )
 
 func (s *Server) autopilotPromoter() autopilot.Promoter {
-	return autopilot.DefaultPromoter()
+	return &zonePromoter{}
 }
 
-func (_ *Server) autopilotServerExt(_ *metadata.Server) interface{} {
-	return nil
+func (_ *Server) autopilotServerExt(srv *metadata.Server) interface{} {
+	return &structs.AutopilotServerExt{ReadReplica: srv.ReadReplica}
 }
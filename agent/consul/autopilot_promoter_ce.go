@@ -0,0 +1,461 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build !consulent
+
+package consul
+
+import (
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/raft"
+	autopilot "github.com/hashicorp/raft-autopilot"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// zonePromoter is the autopilot promoter used by Consul servers. It behaves
+// like autopilot's StablePromoter with three additions:
+//
+//   - Read replicas are never promoted, and are demoted if they somehow
+//     became voters.
+//
+//   - When a redundancy zone tag is configured, only one server per zone is a
+//     voter and the other servers of the zone are kept as hot standbys that are
+//     promoted if the zone's voter fails.
+//
+//   - When an upgrade version tag is configured and upgrade migration is not
+//     disabled, servers running a newer version are kept as non-voters until
+//     there are at least as many of them as there are voters on older
+//     versions. They are then promoted, leadership is moved to one of them and
+//     the old voters are demoted.
+type zonePromoter struct{}
+
+var _ autopilot.Promoter = (*zonePromoter)(nil)
+
+func autopilotConfigExt(c *autopilot.Config) *structs.AutopilotConfigExt {
+	if c != nil {
+		if ext, ok := c.Ext.(*structs.AutopilotConfigExt); ok && ext != nil {
+			return ext
+		}
+	}
+	return &structs.AutopilotConfigExt{}
+}
+
+func autopilotServerExt(srv *autopilot.ServerState) *structs.AutopilotServerExt {
+	if ext, ok := srv.Server.Ext.(*structs.AutopilotServerExt); ok && ext != nil {
+		return ext
+	}
+	return &structs.AutopilotServerExt{}
+}
+
+func (p *zonePromoter) GetServerExt(c *autopilot.Config, srv *autopilot.ServerState) interface{} {
+	conf := autopilotConfigExt(c)
+
+	ext := &structs.AutopilotServerExt{
+		UpgradeVersion: srv.Server.Version,
+		// This is set by the delegate from the server's Serf tags.
+		ReadReplica: autopilotServerExt(srv).ReadReplica,
+	}
+	if conf.RedundancyZoneTag != "" {
+		ext.RedundancyZone = srv.Server.Meta[conf.RedundancyZoneTag]
+	}
+	if conf.UpgradeVersionTag != "" {
+		if v := srv.Server.Meta[conf.UpgradeVersionTag]; v != "" {
+			ext.UpgradeVersion = v
+		}
+	}
+	return ext
+}
+
+func (p *zonePromoter) GetStateExt(c *autopilot.Config, s *autopilot.State) interface{} {
+	plan := planVoters(c, s, time.Now())
+
+	ext := &structs.AutopilotStateExt{
+		OptimisticFailureTolerance: s.FailureTolerance,
+		ReadReplicas:               plan.readReplicas,
+		Upgrade:                    plan.upgradeState(s),
+	}
+
+	if len(plan.zones) == 0 {
+		return ext
+	}
+
+	ext.RedundancyZones = make(map[string]structs.AutopilotZone)
+	for name, ids := range plan.zones {
+		zone := structs.AutopilotZone{Servers: ids}
+		healthy := 0
+		for _, id := range ids {
+			srv := s.Servers[id]
+			if srv.HasVotingRights() {
+				zone.Voters = append(zone.Voters, id)
+			}
+			if srv.Health.Healthy {
+				healthy++
+			}
+		}
+		// Losing a zone's voter is tolerated as long as another server of the
+		// zone can take its place.
+		if healthy > 0 {
+			zone.FailureTolerance = healthy - 1
+			ext.OptimisticFailureTolerance += healthy - 1
+		}
+		ext.RedundancyZones[name] = zone
+	}
+	return ext
+}
+
+func (p *zonePromoter) GetNodeTypes(c *autopilot.Config, s *autopilot.State) map[raft.ServerID]autopilot.NodeType {
+	plan := planVoters(c, s, time.Now())
+
+	types := make(map[raft.ServerID]autopilot.NodeType)
+	for id := range s.Servers {
+		zone := plan.zoneOf[id]
+		switch {
+		case plan.isReadReplica(id):
+			types[id] = structs.AutopilotNodeReadReplica
+		case zone == "":
+			types[id] = autopilot.NodeVoter
+		case plan.zoneVoters[zone] == id:
+			types[id] = structs.AutopilotNodeZoneVoter
+		case s.Servers[id].HasVotingRights():
+			types[id] = structs.AutopilotNodeZoneExtraVoter
+		default:
+			types[id] = structs.AutopilotNodeZoneStandby
+		}
+	}
+	return types
+}
+
+func (p *zonePromoter) FilterFailedServerRemovals(_ *autopilot.Config, _ *autopilot.State, failed *autopilot.FailedServers) *autopilot.FailedServers {
+	return failed
+}
+
+// CalculatePromotionsAndDemotions promotes the servers that should be voters
+// and, once all of them have voting rights, demotes the voters that should
+// not be. The leader is never demoted directly; if it should not be a voter,
+// leadership is transferred to one of the desired voters first.
+func (p *zonePromoter) CalculatePromotionsAndDemotions(c *autopilot.Config, s *autopilot.State) autopilot.RaftChanges {
+	var changes autopilot.RaftChanges
+
+	plan := planVoters(c, s, time.Now())
+
+	for _, id := range plan.voters {
+		if !s.Servers[id].HasVotingRights() {
+			changes.Promotions = append(changes.Promotions, id)
+		}
+	}
+	if len(changes.Promotions) > 0 {
+		return changes
+	}
+
+	desired := make(map[raft.ServerID]struct{}, len(plan.voters))
+	for _, id := range plan.voters {
+		desired[id] = struct{}{}
+	}
+	for _, id := range sortedServerIDs(s) {
+		if _, ok := desired[id]; ok {
+			continue
+		}
+		if !s.Servers[id].HasVotingRights() {
+			continue
+		}
+		if id == s.Leader {
+			if target := plan.leaderTarget(s); target != "" {
+				changes.Leader = target
+			}
+			continue
+		}
+		changes.Demotions = append(changes.Demotions, id)
+	}
+	return changes
+}
+
+// voterPlan is the set of servers that the promoter wants to be voters.
+type voterPlan struct {
+	voters       []raft.ServerID
+	readReplicas []raft.ServerID
+
+	// zones maps each redundancy zone to its servers, and zoneOf maps servers
+	// with a zone back to it.
+	zones      map[string][]raft.ServerID
+	zoneOf     map[raft.ServerID]string
+	zoneVoters map[string]raft.ServerID
+
+	// upgrade tracks the state of an upgrade migration. It is nil when the
+	// migration is not enabled.
+	upgrade *upgradePlan
+}
+
+type upgradePlan struct {
+	targetVersion string
+	target        map[raft.ServerID]struct{}
+
+	// targetReplicas are the read replicas on the target version. They are
+	// only reported and have no effect on the migration.
+	targetReplicas map[raft.ServerID]struct{}
+
+	// ready is true when there are enough servers on the target version to
+	// replace the voters on other versions.
+	ready bool
+}
+
+func (u *upgradePlan) isTarget(id raft.ServerID) bool {
+	_, ok := u.target[id]
+	if !ok {
+		_, ok = u.targetReplicas[id]
+	}
+	return ok
+}
+
+func (p *voterPlan) isReadReplica(id raft.ServerID) bool {
+	for _, replica := range p.readReplicas {
+		if replica == id {
+			return true
+		}
+	}
+	return false
+}
+
+func planVoters(c *autopilot.Config, s *autopilot.State, now time.Time) *voterPlan {
+	conf := autopilotConfigExt(c)
+	minStable := s.ServerStabilizationTime(c)
+
+	plan := &voterPlan{
+		zones:      make(map[string][]raft.ServerID),
+		zoneOf:     make(map[raft.ServerID]string),
+		zoneVoters: make(map[string]raft.ServerID),
+	}
+
+	// Read replicas are left out of everything else.
+	var ids []raft.ServerID
+	for _, id := range sortedServerIDs(s) {
+		if autopilotServerExt(s.Servers[id]).ReadReplica {
+			plan.readReplicas = append(plan.readReplicas, id)
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	// Upgrade migrations are opt-in so that clusters upgraded in place keep
+	// their voters. Operators opt in by tagging the servers with the version
+	// they are migrating to.
+	if conf.UpgradeVersionTag != "" && !conf.DisableUpgradeMigration {
+		plan.upgrade = planUpgrade(s, ids, plan.readReplicas, now, minStable)
+	}
+
+	// Only the servers in the pool are considered for voting rights. During an
+	// upgrade the pool switches to the servers on the target version once there
+	// are enough of them. Until then servers on the target version are not
+	// promoted, but the ones that already vote keep their voting rights so an
+	// in-place upgrade doesn't shrink the quorum.
+	inPool := func(id raft.ServerID) bool {
+		if plan.upgrade == nil || len(plan.upgrade.target) == len(ids) {
+			return true
+		}
+		if plan.upgrade.ready {
+			return plan.upgrade.isTarget(id)
+		}
+		return !plan.upgrade.isTarget(id) || s.Servers[id].HasVotingRights()
+	}
+
+	for _, id := range ids {
+		zone := autopilotServerExt(s.Servers[id]).RedundancyZone
+		if conf.RedundancyZoneTag == "" || zone == "" {
+			continue
+		}
+		plan.zoneOf[id] = zone
+		plan.zones[zone] = append(plan.zones[zone], id)
+	}
+
+	for _, id := range ids {
+		if _, ok := plan.zoneOf[id]; ok || !inPool(id) {
+			continue
+		}
+		srv := s.Servers[id]
+		if srv.HasVotingRights() || srv.Health.IsStable(now, minStable) {
+			plan.voters = append(plan.voters, id)
+		}
+	}
+
+	for zone, members := range plan.zones {
+		var voter, candidate raft.ServerID
+		for _, id := range members {
+			if !inPool(id) {
+				continue
+			}
+			srv := s.Servers[id]
+			switch {
+			case srv.HasVotingRights() && srv.Health.Healthy && voter == "":
+				voter = id
+			case srv.Health.IsStable(now, minStable) && candidate == "":
+				candidate = id
+			}
+		}
+		if voter == "" {
+			voter = candidate
+		}
+		if voter == "" {
+			// Keep an unhealthy voter rather than leave the zone without
+			// one when no other server can replace it.
+			for _, id := range members {
+				if inPool(id) && s.Servers[id].HasVotingRights() {
+					voter = id
+					break
+				}
+			}
+		}
+		if voter != "" {
+			plan.zoneVoters[zone] = voter
+			plan.voters = append(plan.voters, voter)
+		}
+	}
+
+	sort.Slice(plan.voters, func(i, j int) bool { return plan.voters[i] < plan.voters[j] })
+	return plan
+}
+
+// planUpgrade finds the newest version among the servers and whether enough
+// stable servers run it to replace the voters on other versions.
+func planUpgrade(s *autopilot.State, ids, replicas []raft.ServerID, now time.Time, minStable time.Duration) *upgradePlan {
+	var target *version.Version
+	versions := make(map[raft.ServerID]*version.Version)
+	for _, id := range ids {
+		v, err := version.NewVersion(autopilotServerExt(s.Servers[id]).UpgradeVersion)
+		if err != nil {
+			continue
+		}
+		versions[id] = v
+		if target == nil || v.GreaterThan(target) {
+			target = v
+		}
+	}
+
+	plan := &upgradePlan{
+		target:         make(map[raft.ServerID]struct{}),
+		targetReplicas: make(map[raft.ServerID]struct{}),
+	}
+	if target == nil {
+		return plan
+	}
+	plan.targetVersion = target.String()
+
+	for _, id := range replicas {
+		v, err := version.NewVersion(autopilotServerExt(s.Servers[id]).UpgradeVersion)
+		if err == nil && v.Equal(target) {
+			plan.targetReplicas[id] = struct{}{}
+		}
+	}
+
+	var stableTarget, otherVoters int
+	for _, id := range ids {
+		srv := s.Servers[id]
+		if v, ok := versions[id]; ok && v.Equal(target) {
+			plan.target[id] = struct{}{}
+			if srv.HasVotingRights() || srv.Health.IsStable(now, minStable) {
+				stableTarget++
+			}
+		} else if srv.HasVotingRights() {
+			otherVoters++
+		}
+	}
+	plan.ready = stableTarget > 0 && stableTarget >= otherVoters
+	return plan
+}
+
+// leaderTarget returns the desired voter leadership should be transferred
+// to, preferring the lowest ID among the healthy ones.
+func (p *voterPlan) leaderTarget(s *autopilot.State) raft.ServerID {
+	for _, id := range p.voters {
+		srv := s.Servers[id]
+		if srv.HasVotingRights() && srv.Health.Healthy {
+			return id
+		}
+	}
+	return ""
+}
+
+func (p *voterPlan) upgradeState(s *autopilot.State) *structs.AutopilotUpgrade {
+	if p.upgrade == nil {
+		return &structs.AutopilotUpgrade{Status: structs.AutopilotUpgradeDisabled}
+	}
+
+	u := &structs.AutopilotUpgrade{TargetVersion: p.upgrade.targetVersion}
+	zones := make(map[string]*structs.AutopilotZoneUpgradeVersions)
+	for _, id := range sortedServerIDs(s) {
+		voter := s.Servers[id].HasVotingRights()
+		target := p.upgrade.isTarget(id)
+
+		// Servers without a zone are only tracked in the totals.
+		zone := &structs.AutopilotZoneUpgradeVersions{}
+		if name, ok := p.zoneOf[id]; ok {
+			if zones[name] == nil {
+				zones[name] = zone
+			}
+			zone = zones[name]
+		}
+
+		switch {
+		case p.isReadReplica(id) && target:
+			u.TargetVersionReadReplicas = append(u.TargetVersionReadReplicas, id)
+		case p.isReadReplica(id):
+			u.OtherVersionReadReplicas = append(u.OtherVersionReadReplicas, id)
+		case target && voter:
+			u.TargetVersionVoters = append(u.TargetVersionVoters, id)
+			zone.TargetVersionVoters = append(zone.TargetVersionVoters, id)
+		case target:
+			u.TargetVersionNonVoters = append(u.TargetVersionNonVoters, id)
+			zone.TargetVersionNonVoters = append(zone.TargetVersionNonVoters, id)
+		case voter:
+			u.OtherVersionVoters = append(u.OtherVersionVoters, id)
+			zone.OtherVersionVoters = append(zone.OtherVersionVoters, id)
+		default:
+			u.OtherVersionNonVoters = append(u.OtherVersionNonVoters, id)
+			zone.OtherVersionNonVoters = append(zone.OtherVersionNonVoters, id)
+		}
+	}
+	if len(zones) > 0 {
+		u.RedundancyZones = make(map[string]structs.AutopilotZoneUpgradeVersions, len(zones))
+		for name, zone := range zones {
+			u.RedundancyZones[name] = *zone
+		}
+	}
+
+	switch {
+	case len(u.OtherVersionVoters) == 0 && len(u.OtherVersionNonVoters) == 0:
+		u.Status = structs.AutopilotUpgradeIdle
+	case !p.upgrade.ready:
+		u.Status = structs.AutopilotUpgradeAwaitNewVoters
+	case p.pendingPromotions(s):
+		u.Status = structs.AutopilotUpgradePromoting
+	case len(u.OtherVersionVoters) > 1 || (len(u.OtherVersionVoters) == 1 && u.OtherVersionVoters[0] != s.Leader):
+		u.Status = structs.AutopilotUpgradeDemoting
+	case len(u.OtherVersionVoters) == 1:
+		u.Status = structs.AutopilotUpgradeLeaderTransfer
+	case len(u.TargetVersionVoters)+len(u.TargetVersionNonVoters) < len(u.OtherVersionNonVoters):
+		u.Status = structs.AutopilotUpgradeAwaitNewServers
+	default:
+		u.Status = structs.AutopilotUpgradeAwaitServerRemoval
+	}
+	return u
+}
+
+func (p *voterPlan) pendingPromotions(s *autopilot.State) bool {
+	for _, id := range p.voters {
+		if !s.Servers[id].HasVotingRights() {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedServerIDs(s *autopilot.State) []raft.ServerID {
+	ids := make([]raft.ServerID, 0, len(s.Servers))
+	for id := range s.Servers {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
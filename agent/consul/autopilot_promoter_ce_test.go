@@ -0,0 +1,302 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build !consulent
+
+package consul
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	autopilot "github.com/hashicorp/raft-autopilot"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+type testPromoterServer struct {
+	id          string
+	version     string
+	zone        string
+	state       autopilot.RaftState
+	healthy     bool
+	readReplica bool
+}
+
+func testPromoterState(t *testing.T, conf *autopilot.Config, servers ...testPromoterServer) *autopilot.State {
+	t.Helper()
+
+	p := &zonePromoter{}
+	s := &autopilot.State{Servers: make(map[raft.ServerID]*autopilot.ServerState)}
+	for _, srv := range servers {
+		id := raft.ServerID(srv.id)
+		state := &autopilot.ServerState{
+			Server: autopilot.Server{
+				ID:      id,
+				Version: srv.version,
+				Meta:    map[string]string{"zone": srv.zone, "version": srv.version},
+			},
+			State: srv.state,
+			Health: autopilot.ServerHealth{
+				Healthy:     srv.healthy,
+				StableSince: time.Now().Add(-time.Hour),
+			},
+		}
+		state.Server.Ext = &structs.AutopilotServerExt{ReadReplica: srv.readReplica}
+		state.Server.Ext = p.GetServerExt(conf, state)
+		s.Servers[id] = state
+		if srv.state == autopilot.RaftLeader {
+			s.Leader = id
+		}
+	}
+	return s
+}
+
+func testPromoterConfig(ext structs.AutopilotConfigExt) *autopilot.Config {
+	return &autopilot.Config{ServerStabilizationTime: time.Second, Ext: &ext}
+}
+
+func TestZonePromoter_RedundancyZones(t *testing.T) {
+	p := &zonePromoter{}
+	conf := testPromoterConfig(structs.AutopilotConfigExt{RedundancyZoneTag: "zone"})
+
+	t.Run("promotes one server per zone", func(t *testing.T) {
+		s := testPromoterState(t, conf,
+			testPromoterServer{id: "a1", version: "1.17.0", zone: "a", state: autopilot.RaftLeader, healthy: true},
+			testPromoterServer{id: "a2", version: "1.17.0", zone: "a", state: autopilot.RaftNonVoter, healthy: true},
+			testPromoterServer{id: "b1", version: "1.17.0", zone: "b", state: autopilot.RaftNonVoter, healthy: true},
+			testPromoterServer{id: "b2", version: "1.17.0", zone: "b", state: autopilot.RaftNonVoter, healthy: true},
+			testPromoterServer{id: "c1", version: "1.17.0", state: autopilot.RaftNonVoter, healthy: true},
+		)
+		changes := p.CalculatePromotionsAndDemotions(conf, s)
+		require.Equal(t, []raft.ServerID{"b1", "c1"}, changes.Promotions)
+		require.Empty(t, changes.Demotions)
+
+		types := p.GetNodeTypes(conf, s)
+		require.Equal(t, structs.AutopilotNodeZoneVoter, types["a1"])
+		require.Equal(t, structs.AutopilotNodeZoneStandby, types["a2"])
+		require.Equal(t, structs.AutopilotNodeZoneVoter, types["b1"])
+		require.Equal(t, autopilot.NodeVoter, types["c1"])
+	})
+
+	t.Run("replaces failed zone voter", func(t *testing.T) {
+		s := testPromoterState(t, conf,
+			testPromoterServer{id: "a1", version: "1.17.0", zone: "a", state: autopilot.RaftLeader, healthy: true},
+			testPromoterServer{id: "b1", version: "1.17.0", zone: "b", state: autopilot.RaftVoter, healthy: false},
+			testPromoterServer{id: "b2", version: "1.17.0", zone: "b", state: autopilot.RaftNonVoter, healthy: true},
+		)
+		changes := p.CalculatePromotionsAndDemotions(conf, s)
+		require.Equal(t, []raft.ServerID{"b2"}, changes.Promotions)
+
+		// Once the standby votes, the failed voter is demoted.
+		s.Servers["b2"].State = autopilot.RaftVoter
+		changes = p.CalculatePromotionsAndDemotions(conf, s)
+		require.Empty(t, changes.Promotions)
+		require.Equal(t, []raft.ServerID{"b1"}, changes.Demotions)
+		require.Equal(t, structs.AutopilotNodeZoneExtraVoter, p.GetNodeTypes(conf, s)["b1"])
+	})
+
+	t.Run("state", func(t *testing.T) {
+		s := testPromoterState(t, conf,
+			testPromoterServer{id: "a1", version: "1.17.0", zone: "a", state: autopilot.RaftLeader, healthy: true},
+			testPromoterServer{id: "a2", version: "1.17.0", zone: "a", state: autopilot.RaftNonVoter, healthy: true},
+			testPromoterServer{id: "b1", version: "1.17.0", zone: "b", state: autopilot.RaftVoter, healthy: true},
+		)
+		s.FailureTolerance = 0
+
+		ext := p.GetStateExt(conf, s).(*structs.AutopilotStateExt)
+		require.Equal(t, 1, ext.OptimisticFailureTolerance)
+		require.Equal(t, map[string]structs.AutopilotZone{
+			"a": {Servers: []raft.ServerID{"a1", "a2"}, Voters: []raft.ServerID{"a1"}, FailureTolerance: 1},
+			"b": {Servers: []raft.ServerID{"b1"}, Voters: []raft.ServerID{"b1"}},
+		}, ext.RedundancyZones)
+		require.Equal(t, structs.AutopilotUpgradeDisabled, ext.Upgrade.Status)
+	})
+}
+
+func TestZonePromoter_UpgradeMigration(t *testing.T) {
+	p := &zonePromoter{}
+	conf := testPromoterConfig(structs.AutopilotConfigExt{UpgradeVersionTag: "version"})
+
+	s := testPromoterState(t, conf,
+		testPromoterServer{id: "old1", version: "1.16.0", state: autopilot.RaftLeader, healthy: true},
+		testPromoterServer{id: "old2", version: "1.16.0", state: autopilot.RaftVoter, healthy: true},
+		testPromoterServer{id: "old3", version: "1.16.0", state: autopilot.RaftVoter, healthy: true},
+		testPromoterServer{id: "new1", version: "1.17.0", state: autopilot.RaftNonVoter, healthy: true},
+		testPromoterServer{id: "new2", version: "1.17.0", state: autopilot.RaftNonVoter, healthy: true},
+	)
+
+	upgradeStatus := func() string {
+		return p.GetStateExt(conf, s).(*structs.AutopilotStateExt).Upgrade.Status
+	}
+
+	// Not enough new servers to replace the old voters yet.
+	changes := p.CalculatePromotionsAndDemotions(conf, s)
+	require.Empty(t, changes.Promotions)
+	require.Empty(t, changes.Demotions)
+	require.Equal(t, structs.AutopilotUpgradeAwaitNewVoters, upgradeStatus())
+
+	s.Servers["new3"] = &autopilot.ServerState{
+		Server: autopilot.Server{ID: "new3", Version: "1.17.0", Meta: map[string]string{"version": "1.17.0"}},
+		State:  autopilot.RaftNonVoter,
+		Health: autopilot.ServerHealth{Healthy: true, StableSince: time.Now().Add(-time.Hour)},
+	}
+	s.Servers["new3"].Server.Ext = p.GetServerExt(conf, s.Servers["new3"])
+	require.Equal(t, structs.AutopilotUpgradePromoting, upgradeStatus())
+
+	changes = p.CalculatePromotionsAndDemotions(conf, s)
+	require.Equal(t, []raft.ServerID{"new1", "new2", "new3"}, changes.Promotions)
+	require.Empty(t, changes.Demotions)
+
+	for _, id := range changes.Promotions {
+		s.Servers[id].State = autopilot.RaftVoter
+	}
+	require.Equal(t, structs.AutopilotUpgradeDemoting, upgradeStatus())
+
+	// The old followers are demoted and leadership moves to a new server.
+	changes = p.CalculatePromotionsAndDemotions(conf, s)
+	require.Empty(t, changes.Promotions)
+	require.Equal(t, []raft.ServerID{"old2", "old3"}, changes.Demotions)
+	require.Equal(t, raft.ServerID("new1"), changes.Leader)
+
+	s.Servers["old2"].State = autopilot.RaftNonVoter
+	s.Servers["old3"].State = autopilot.RaftNonVoter
+	require.Equal(t, structs.AutopilotUpgradeLeaderTransfer, upgradeStatus())
+
+	s.Servers["old1"].State = autopilot.RaftVoter
+	s.Servers["new1"].State = autopilot.RaftLeader
+	s.Leader = "new1"
+	changes = p.CalculatePromotionsAndDemotions(conf, s)
+	require.Equal(t, []raft.ServerID{"old1"}, changes.Demotions)
+
+	s.Servers["old1"].State = autopilot.RaftNonVoter
+	require.Equal(t, structs.AutopilotUpgradeAwaitServerRemoval, upgradeStatus())
+}
+
+func TestZonePromoter_ReadReplicas(t *testing.T) {
+	p := &zonePromoter{}
+	conf := testPromoterConfig(structs.AutopilotConfigExt{RedundancyZoneTag: "zone", UpgradeVersionTag: "version"})
+
+	s := testPromoterState(t, conf,
+		testPromoterServer{id: "a1", version: "1.17.0", zone: "a", state: autopilot.RaftLeader, healthy: true},
+		testPromoterServer{id: "r1", version: "1.17.0", zone: "b", state: autopilot.RaftNonVoter, healthy: true, readReplica: true},
+		testPromoterServer{id: "r2", version: "1.16.0", state: autopilot.RaftVoter, healthy: true, readReplica: true},
+	)
+
+	// Read replicas are never promoted, and are demoted if they vote.
+	changes := p.CalculatePromotionsAndDemotions(conf, s)
+	require.Empty(t, changes.Promotions)
+	require.Equal(t, []raft.ServerID{"r2"}, changes.Demotions)
+
+	types := p.GetNodeTypes(conf, s)
+	require.Equal(t, structs.AutopilotNodeReadReplica, types["r1"])
+	require.Equal(t, structs.AutopilotNodeReadReplica, types["r2"])
+
+	// They don't count towards zones or upgrades.
+	ext := p.GetStateExt(conf, s).(*structs.AutopilotStateExt)
+	require.Equal(t, []raft.ServerID{"r1", "r2"}, ext.ReadReplicas)
+	require.Equal(t, []string{"a"}, func() []string {
+		var zones []string
+		for name := range ext.RedundancyZones {
+			zones = append(zones, name)
+		}
+		return zones
+	}())
+	require.Equal(t, structs.AutopilotUpgradeIdle, ext.Upgrade.Status)
+	require.Equal(t, []raft.ServerID{"r1"}, ext.Upgrade.TargetVersionReadReplicas)
+	require.Equal(t, []raft.ServerID{"r2"}, ext.Upgrade.OtherVersionReadReplicas)
+}
+
+func TestZonePromoter_UpgradeMigrationDisabled(t *testing.T) {
+	p := &zonePromoter{}
+
+	cases := map[string]structs.AutopilotConfigExt{
+		// Upgrade migrations are opt-in, there is no migration without a tag.
+		"no upgrade version tag": {},
+		"disabled":               {UpgradeVersionTag: "version", DisableUpgradeMigration: true},
+	}
+	for name, ext := range cases {
+		t.Run(name, func(t *testing.T) {
+			conf := testPromoterConfig(ext)
+			s := testPromoterState(t, conf,
+				testPromoterServer{id: "old1", version: "1.16.0", state: autopilot.RaftLeader, healthy: true},
+				testPromoterServer{id: "new1", version: "1.17.0", state: autopilot.RaftNonVoter, healthy: true},
+			)
+
+			changes := p.CalculatePromotionsAndDemotions(conf, s)
+			require.Equal(t, []raft.ServerID{"new1"}, changes.Promotions)
+			require.Empty(t, changes.Demotions)
+			require.Equal(t, structs.AutopilotUpgradeDisabled, p.GetStateExt(conf, s).(*structs.AutopilotStateExt).Upgrade.Status)
+		})
+	}
+}
+
+func TestZonePromoter_UpgradeVersionTag(t *testing.T) {
+	p := &zonePromoter{}
+	conf := testPromoterConfig(structs.AutopilotConfigExt{UpgradeVersionTag: "build"})
+
+	srv := &autopilot.ServerState{
+		Server: autopilot.Server{ID: "a", Version: "1.17.0", Meta: map[string]string{"build": "0.2.0"}},
+	}
+	require.Equal(t, "0.2.0", p.GetServerExt(conf, srv).(*structs.AutopilotServerExt).UpgradeVersion)
+
+	srv.Server.Meta = nil
+	require.Equal(t, "1.17.0", p.GetServerExt(conf, srv).(*structs.AutopilotServerExt).UpgradeVersion)
+}
+
+func TestAutopilot_ReadReplicaNotPromoted(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.Datacenter = "dc1"
+		c.Bootstrap = true
+		c.AutopilotConfig.ServerStabilizationTime = 200 * time.Millisecond
+		c.ServerHealthInterval = 100 * time.Millisecond
+		c.AutopilotInterval = 100 * time.Millisecond
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	dir2, s2 := testServerWithConfig(t, func(c *Config) {
+		c.Datacenter = "dc1"
+		c.Bootstrap = false
+		c.ReadReplica = true
+	})
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+	joinLAN(t, s2, s1)
+
+	// Wait for the read replica to be healthy and stable for longer than
+	// the stabilization time.
+	retry.Run(t, func(r *retry.R) {
+		health := s1.autopilot.GetServerHealth(raft.ServerID(s2.config.NodeID))
+		if health == nil {
+			r.Fatal("nil health")
+		}
+		if !health.Healthy {
+			r.Fatalf("bad: %v", health)
+		}
+		if time.Since(health.StableSince) < 2*s1.config.AutopilotConfig.ServerStabilizationTime {
+			r.Fatal("stable period not elapsed")
+		}
+	})
+
+	// Make sure it was never promoted.
+	future := s1.raft.GetConfiguration()
+	require.NoError(t, future.Error())
+	servers := future.Configuration().Servers
+	require.Len(t, servers, 2)
+	require.Equal(t, raft.Nonvoter, servers[1].Suffrage)
+
+	state := s1.autopilot.GetState()
+	require.Equal(t, structs.AutopilotNodeReadReplica, state.Servers[raft.ServerID(s2.config.NodeID)].Server.NodeType)
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/hashicorp/consul/acl"
 	rpcRate "github.com/hashicorp/consul/agent/consul/rate"
+	"github.com/hashicorp/consul/agent/metadata"
 	"github.com/hashicorp/consul/agent/pool"
 	"github.com/hashicorp/consul/agent/router"
 	"github.com/hashicorp/consul/agent/structs"
@@ -288,9 +289,20 @@ func (c *Client) RPC(ctx context.Context, method string, args interface{}, reply
 	firstCheck := time.Now()
 	retryCount := 0
 	previousJitter := time.Duration(0)
+
+	// Use the zero value for RPCInfo if the request doesn't implement RPCInfo
+	info, _ := args.(structs.RPCInfo)
 TRY:
 	retryCount++
-	manager, server := c.router.FindLANRoute()
+	var manager *router.Manager
+	var server *metadata.Server
+	if retryCount == 1 && info != nil && info.IsRead() && info.AllowStaleRead() {
+		// Stale reads go to the nearest read replica first. Retries use the
+		// regular server rotation so a failed replica isn't retried.
+		manager, server = c.router.FindLANStaleReadRoute()
+	} else {
+		manager, server = c.router.FindLANRoute()
+	}
 	if server == nil {
 		return structs.ErrNoServers
 	}
@@ -311,8 +323,6 @@ TRY:
 	// Move off to another server, and see if we can retry.
 	manager.NotifyFailedServer(server)
 
-	// Use the zero value for RPCInfo if the request doesn't implement RPCInfo
-	info, _ := args.(structs.RPCInfo)
 	retryableMessages := []error{
 		// If we are chunking and it doesn't seem to have completed, try again.
 		ErrChunkingResubmit,
@@ -117,7 +117,7 @@ type Config struct {
 	// RaftConfig is the configuration used for Raft in the local DC
 	RaftConfig *raft.Config
 
-	// (Enterprise-only) ReadReplica is used to prevent this server from being added
+	// ReadReplica is used to prevent this server from being added
 	// as a voting member of the Raft cluster.
 	ReadReplica bool
 
@@ -274,6 +274,9 @@ type Config struct {
 	// on a token.
 	ACLTokenMinExpirationTTL time.Duration
 
+	// ACLBreakGlassMaxTTL is the maximum TTL of break-glass tokens.
+	ACLBreakGlassMaxTTL time.Duration
+
 	// ServerUp callback can be used to trigger a notification that
 	// a Consul server is now up and known about.
 	ServerUp func()
@@ -532,6 +535,7 @@ func DefaultConfig() *Config {
 		// Duration is stored as an int64. Setting the default max
 		// to the max possible duration (approx 290 years).
 		ACLTokenMaxExpirationTTL: 1<<63 - 1,
+		ACLBreakGlassMaxTTL:      4 * time.Hour,
 
 		// These are tuned to provide a total throughput of 128 updates
 		// per second. If you update these, you should update the client-side
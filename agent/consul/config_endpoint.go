@@ -17,6 +17,7 @@ import (
 
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/configentry"
+	"github.com/hashicorp/consul/agent/connect/ca"
 	"github.com/hashicorp/consul/agent/consul/state"
 	"github.com/hashicorp/consul/agent/structs"
 )
@@ -91,6 +92,9 @@ func (c *ConfigEntry) Apply(args *structs.ConfigEntryRequest, reply *bool) error
 	if err := args.Entry.Validate(); err != nil {
 		return err
 	}
+	if err := c.validateLeafCertTTL(args.Entry); err != nil {
+		return err
+	}
 
 	// Log any applicable warnings about the contents of the config entry.
 	if warnEntry, ok := args.Entry.(structs.WarningConfigEntry); ok {
@@ -126,6 +130,25 @@ func (c *ConfigEntry) Apply(args *structs.ConfigEntryRequest, reply *bool) error
 	return nil
 }
 
+// validateLeafCertTTL rejects the leaf certificate TTL overrides of
+// service-defaults that the CA provider is not able to issue.
+func (c *ConfigEntry) validateLeafCertTTL(entry structs.ConfigEntry) error {
+	svc, ok := entry.(*structs.ServiceConfigEntry)
+	if !ok || svc.LeafCert == nil || svc.LeafCert.TTL == 0 {
+		return nil
+	}
+
+	_, caConf, err := c.srv.fsm.State().CAConfig(nil)
+	if err != nil {
+		return err
+	}
+	if caConf != nil && caConf.Provider == structs.AWSCAProvider && svc.LeafCert.TTL < ca.AWSMinLeafCertTTL {
+		return fmt.Errorf("leaf cert TTL of %s is not supported by the %s CA provider, which cannot issue certificates"+
+			" valid for less than %s", svc.LeafCert.TTL, structs.AWSCAProvider, ca.AWSMinLeafCertTTL)
+	}
+	return nil
+}
+
 // shouldSkipOperation returns true if the result of the operation has
 // already happened and is safe to skip.
 //
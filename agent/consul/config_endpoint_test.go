@@ -197,6 +197,49 @@ func TestConfigEntry_ProxyDefaultsMeshGateway(t *testing.T) {
 	require.Equal(t, structs.MeshGatewayModeLocal, proxyConf.MeshGateway.Mode)
 }
 
+func TestConfigEntry_Apply_LeafCertTTL(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	apply := func(ttl time.Duration) error {
+		args := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry: &structs.ServiceConfigEntry{
+				Name:     "foo",
+				LeafCert: &structs.LeafCertConfig{TTL: ttl},
+			},
+		}
+		var out bool
+		return msgpackrpc.CallWithCodec(codec, "ConfigEntry.Apply", &args, &out)
+	}
+
+	require.NoError(t, apply(time.Hour))
+
+	// Only the CA configuration in the state store is looked at.
+	state := s1.fsm.State()
+	idx, _, err := state.CAConfig(nil)
+	require.NoError(t, err)
+	require.NoError(t, state.CASetConfig(idx+1, &structs.CAConfiguration{
+		ClusterID: "cluster-id",
+		Provider:  structs.AWSCAProvider,
+	}))
+
+	err = apply(time.Hour)
+	require.ErrorContains(t, err, "leaf cert TTL of 1h0m0s is not supported by the aws-pca CA provider")
+	require.NoError(t, apply(48*time.Hour))
+}
+
 func TestConfigEntry_Apply_ACLDeny(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -4,6 +4,7 @@
 package consul
 
 import (
+	"crypto/x509"
 	"fmt"
 	"time"
 
@@ -157,7 +158,12 @@ func (s *ConnectCA) Sign(
 		return err
 	}
 
-	cert, err := s.srv.caManager.AuthorizeAndSignCertificate(csr, authz)
+	node, err := s.signingNode(csr, authz.Identity(), args.Node)
+	if err != nil {
+		return err
+	}
+
+	cert, err := s.srv.caManager.authorizeAndSignCertificate(csr, authz, node)
 	if err != nil {
 		return err
 	}
@@ -165,6 +171,106 @@ func (s *ConnectCA) Sign(
 	return nil
 }
 
+// signingNode returns the name of the node to record with the certificate
+// signed for the CSR. The node sent by the client is only trusted when the
+// node identities of the token, the agent of the CSR or the catalog confirm
+// it, and the request is rejected when it contradicts the token or the CSR.
+// An empty name is returned when the node can't be confirmed.
+func (s *ConnectCA) signingNode(csr *x509.CertificateRequest, identity structs.ACLIdentity, node string) (string, error) {
+	if len(csr.URIs) != 1 {
+		// The CSR is rejected when it is signed.
+		return "", nil
+	}
+	spiffeID, err := connect.ParseCertURI(csr.URIs[0])
+	if err != nil {
+		return "", err
+	}
+
+	mismatch := func(source string) error {
+		return connect.InvalidCSRError("node %q does not match the %s", node, source)
+	}
+
+	if identity != nil {
+		var identityNodes []string
+		for _, id := range identity.NodeIdentityList() {
+			if id.Datacenter == s.srv.config.Datacenter {
+				identityNodes = append(identityNodes, id.NodeName)
+			}
+		}
+		switch {
+		case len(identityNodes) == 0:
+		case node == "" && len(identityNodes) == 1:
+			return identityNodes[0], nil
+		case node == "":
+			return "", nil
+		default:
+			for _, n := range identityNodes {
+				if n == node {
+					return node, nil
+				}
+			}
+			return "", mismatch("node identities of the token")
+		}
+	}
+
+	if v, ok := spiffeID.(*connect.SpiffeIDAgent); ok {
+		if node != "" && node != v.Agent {
+			return "", mismatch("agent in the CSR")
+		}
+		return v.Agent, nil
+	}
+
+	if node == "" {
+		return "", nil
+	}
+
+	// Look for the instances registered in the catalog that are allowed to
+	// request the certificate.
+	state := s.srv.fsm.State()
+	var instances structs.ServiceNodes
+	switch v := spiffeID.(type) {
+	case *connect.SpiffeIDService:
+		_, connectNodes, err := state.ConnectServiceNodes(nil, v.Service, v.GetEnterpriseMeta(), structs.DefaultPeerKeyword)
+		if err != nil {
+			return "", err
+		}
+		instances = append(instances, connectNodes...)
+
+		// Terminating gateways request the certificates of their linked services.
+		_, gateways, err := state.ServiceGateways(nil, v.Service, structs.ServiceKindTerminatingGateway, *v.GetEnterpriseMeta())
+		if err != nil {
+			return "", err
+		}
+		for _, gw := range gateways {
+			instances = append(instances, &structs.ServiceNode{Node: gw.Node.Node})
+		}
+	case *connect.SpiffeIDMeshGateway:
+		_, gateways, err := state.ServiceDump(nil, structs.ServiceKindMeshGateway, true, v.GetEnterpriseMeta(), structs.DefaultPeerKeyword)
+		if err != nil {
+			return "", err
+		}
+		for _, gw := range gateways {
+			instances = append(instances, &structs.ServiceNode{Node: gw.Node.Node})
+		}
+	}
+
+	for _, instance := range instances {
+		if instance.Node == node {
+			return node, nil
+		}
+	}
+
+	// The node can't be confirmed, for example when a server requests the
+	// certificate of an agentless proxy, when an agent requests it for a
+	// service registered on another node, or before the service is synced to
+	// the catalog, so the certificate is recorded without it.
+	s.logger.Debug("not recording the node of the certificate, it is not confirmed by the catalog",
+		"node", node,
+		"spiffeID", spiffeID.URI().String(),
+	)
+	return "", nil
+}
+
 // SignIntermediate signs an intermediate certificate for a remote datacenter.
 func (s *ConnectCA) SignIntermediate(
 	args *structs.CASignRequest,
@@ -211,3 +317,160 @@ func (s *ConnectCA) SignIntermediate(
 
 	return nil
 }
+
+// IntermediateCSR returns the CSR for the next intermediate of a CA provider
+// whose root is kept offline, such as the offline provider.
+func (s *ConnectCA) IntermediateCSR(
+	args *structs.CAIntermediateRequest,
+	reply *string) error {
+	// Exit early if Connect hasn't been enabled.
+	if !s.srv.config.ConnectEnabled {
+		return ErrConnectNotEnabled
+	}
+
+	if done, err := s.srv.ForwardRPC("ConnectCA.IntermediateCSR", args, reply); done {
+		return err
+	}
+
+	// Verify we are allowed to serve this request
+	if s.srv.config.PrimaryDatacenter != s.srv.config.Datacenter {
+		return ErrNotPrimaryDatacenter
+	}
+
+	// This action requires operator write access.
+	authz, err := s.srv.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+	if err := authz.ToAllowAuthorizer().OperatorWriteAllowed(nil); err != nil {
+		return err
+	}
+
+	csr, err := s.srv.caManager.PrimaryIntermediateCSR()
+	if err != nil {
+		return err
+	}
+	*reply = csr
+	return nil
+}
+
+// SetIntermediate sets the intermediate signed by the offline root of a CA
+// provider whose root is kept offline, such as the offline provider.
+func (s *ConnectCA) SetIntermediate(
+	args *structs.CAIntermediateRequest,
+	reply *interface{}) error {
+	// Exit early if Connect hasn't been enabled.
+	if !s.srv.config.ConnectEnabled {
+		return ErrConnectNotEnabled
+	}
+
+	if done, err := s.srv.ForwardRPC("ConnectCA.SetIntermediate", args, reply); done {
+		return err
+	}
+
+	// Verify we are allowed to serve this request
+	if s.srv.config.PrimaryDatacenter != s.srv.config.Datacenter {
+		return ErrNotPrimaryDatacenter
+	}
+
+	// This action requires operator write access.
+	authz, err := s.srv.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+	if err := authz.ToAllowAuthorizer().OperatorWriteAllowed(nil); err != nil {
+		return err
+	}
+
+	if args.Certificate == "" {
+		return fmt.Errorf("Missing intermediate certificate")
+	}
+
+	return s.srv.caManager.PrimarySetIntermediate(args.Certificate)
+}
+
+// Revoke revokes the leaf certificates issued in this datacenter that match
+// the request.
+func (s *ConnectCA) Revoke(
+	args *structs.CARevokeRequest,
+	reply *structs.CARevokeResponse) error {
+	// Exit early if Connect hasn't been enabled.
+	if !s.srv.config.ConnectEnabled {
+		return ErrConnectNotEnabled
+	}
+
+	if done, err := s.srv.ForwardRPC("ConnectCA.Revoke", args, reply); done {
+		return err
+	}
+
+	// This action requires operator write access.
+	authz, err := s.srv.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+	if err := authz.ToAllowAuthorizer().OperatorWriteAllowed(nil); err != nil {
+		return err
+	}
+
+	certs, err := s.srv.caManager.RevokeLeafCerts(args)
+	if err != nil {
+		return err
+	}
+	reply.Certs = certs
+	return nil
+}
+
+// Status reports the health of the CA and the number of outstanding leaf
+// certificates signed by each root. It is always served by the leader since
+// only the leader knows the state of the CA.
+func (s *ConnectCA) Status(
+	args *structs.DCSpecificRequest,
+	reply *structs.CAStatus) error {
+	// Exit early if Connect hasn't been enabled.
+	if !s.srv.config.ConnectEnabled {
+		return ErrConnectNotEnabled
+	}
+
+	args.AllowStale = false
+	if done, err := s.srv.ForwardRPC("ConnectCA.Status", args, reply); done {
+		return err
+	}
+
+	// This action requires operator read access.
+	authz, err := s.srv.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+	if err := authz.ToAllowAuthorizer().OperatorReadAllowed(nil); err != nil {
+		return err
+	}
+
+	status, err := s.srv.caManager.Status()
+	if err != nil {
+		return err
+	}
+	*reply = *status
+	return nil
+}
+
+// OCSP returns the OCSP response for a leaf certificate issued in this
+// datacenter. Like the roots, the response is public and no ACL is required.
+func (s *ConnectCA) OCSP(
+	args *structs.CAOCSPRequest,
+	reply *[]byte) error {
+	// Exit early if Connect hasn't been enabled.
+	if !s.srv.config.ConnectEnabled {
+		return ErrConnectNotEnabled
+	}
+
+	if done, err := s.srv.ForwardRPC("ConnectCA.OCSP", args, reply); done {
+		return err
+	}
+
+	resp, err := s.srv.caManager.OCSPResponse(args.Request)
+	if err != nil {
+		return err
+	}
+	*reply = resp
+	return nil
+}
@@ -13,8 +13,10 @@ import (
 	"time"
 
 	msgpackrpc "github.com/hashicorp/consul-net-rpc/net-rpc-msgpackrpc"
+	"github.com/hashicorp/consul-net-rpc/net/rpc"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
 
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/connect"
@@ -896,6 +898,59 @@ func TestConnectCASign(t *testing.T) {
 	}
 }
 
+func TestConnectCASign_leafCertOverrides(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, s1 := testServerWithConfig(t, func(cfg *Config) {
+		cfg.PrimaryDatacenter = "dc1"
+		cfg.CAConfig.Config["LeafCertTTL"] = "72h"
+	})
+	codec := rpcClient(t, s1)
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	require.NoError(t, s1.fsm.State().EnsureConfigEntry(1, &structs.ServiceConfigEntry{
+		Kind: structs.ServiceDefaults,
+		Name: "web",
+		LeafCert: &structs.LeafCertConfig{
+			TTL:            2 * time.Hour,
+			PrivateKeyType: "rsa",
+			PrivateKeyBits: 2048,
+		},
+	}))
+	spiffeID := connect.TestSpiffeIDService(t, "web")
+
+	t.Run("key type mismatch", func(t *testing.T) {
+		// TestCSR always generates a CSR with an EC key.
+		csr, _ := connect.TestCSR(t, spiffeID)
+		args := &structs.CASignRequest{
+			Datacenter: "dc1",
+			CSR:        csr,
+		}
+		var reply structs.IssuedCert
+		err := msgpackrpc.CallWithCodec(codec, "ConnectCA.Sign", args, &reply)
+		testutil.RequireErrorContains(t, err, "CSR key must be rsa 2048 bits for this service")
+	})
+
+	t.Run("overridden TTL", func(t *testing.T) {
+		pk, _, err := connect.GeneratePrivateKeyWithConfig("rsa", 2048)
+		require.NoError(t, err)
+		csr, err := connect.CreateCSR(spiffeID, pk, nil, nil)
+		require.NoError(t, err)
+
+		args := &structs.CASignRequest{
+			Datacenter: "dc1",
+			CSR:        csr,
+		}
+		var reply structs.IssuedCert
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.Sign", args, &reply))
+		require.Equal(t, 2*time.Hour, reply.ValidBefore.Sub(reply.ValidAfter))
+	})
+}
+
 // Bench how long Signing RPC takes. This was used to ballpark reasonable
 // default rate limit to protect servers from thundering herds of signing
 // requests on root rotation.
@@ -1185,3 +1240,377 @@ func TestConnectCASignValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestConnectCARevoke(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(cfg *Config) {
+		cfg.PrimaryDatacenter = "dc1"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	sign := func(t *testing.T, service, node string) *structs.IssuedCert {
+		testRegisterConnectProxy(t, codec, node, service)
+
+		csr, _ := connect.TestCSR(t, connect.TestSpiffeIDService(t, service))
+		args := &structs.CASignRequest{
+			Datacenter: "dc1",
+			CSR:        csr,
+			Node:       node,
+		}
+		var reply structs.IssuedCert
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.Sign", args, &reply))
+		return &reply
+	}
+	web := sign(t, "web", "node1")
+	db := sign(t, "db", "node2")
+
+	// The issued certificates are recorded without their certificate.
+	_, recorded, err := s1.fsm.State().CALeafCert(nil, web.SerialNumber)
+	require.NoError(t, err)
+	require.Equal(t, "node1", recorded.Node)
+	require.Empty(t, recorded.CertPEM)
+	require.False(t, recorded.IsRevoked())
+
+	// At least one criteria is required and unknown serial numbers are rejected.
+	var reply structs.CARevokeResponse
+	err = msgpackrpc.CallWithCodec(codec, "ConnectCA.Revoke", &structs.CARevokeRequest{Datacenter: "dc1"}, &reply)
+	testutil.RequireErrorContains(t, err, "a serial number, service or node is required")
+	err = msgpackrpc.CallWithCodec(codec, "ConnectCA.Revoke", &structs.CARevokeRequest{
+		Datacenter:   "dc1",
+		SerialNumber: "ff:ff",
+	}, &reply)
+	testutil.RequireErrorContains(t, err, "no certificate with serial number ff:ff")
+
+	// No certificate matches both criteria.
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.Revoke", &structs.CARevokeRequest{
+		Datacenter: "dc1",
+		Service:    "web",
+		Node:       "node2",
+	}, &reply))
+	require.Empty(t, reply.Certs)
+
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.Revoke", &structs.CARevokeRequest{
+		Datacenter: "dc1",
+		Service:    "web",
+		Reason:     "compromised",
+	}, &reply))
+	require.Len(t, reply.Certs, 1)
+	require.Equal(t, web.SerialNumber, reply.Certs[0].SerialNumber)
+	require.Equal(t, "compromised", reply.Certs[0].RevocationReason)
+	require.True(t, reply.Certs[0].IsRevoked())
+
+	// The revocation list is returned with the roots.
+	var roots structs.IndexedCARoots
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.Roots", &structs.DCSpecificRequest{Datacenter: "dc1"}, &roots))
+	require.NotEmpty(t, roots.RevocationList)
+	active := testParseCert(t, roots.Active().RootCert)
+
+	block, _ := pem.Decode([]byte(roots.RevocationList))
+	require.NotNil(t, block)
+	crl, err := x509.ParseRevocationList(block.Bytes)
+	require.NoError(t, err)
+	require.NoError(t, crl.CheckSignatureFrom(active))
+	require.Len(t, crl.RevokedCertificates, 1)
+	require.Equal(t, web.SerialNumber, connect.EncodeSerialNumber(crl.RevokedCertificates[0].SerialNumber))
+	require.False(t, roots.WANFederated)
+
+	// The roots report when the datacenter is federated with others, whose
+	// certificates are not covered by the revocation list.
+	require.NoError(t, s1.fsm.State().FederationStateSet(100, &structs.FederationState{Datacenter: "dc2"}))
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.Roots", &structs.DCSpecificRequest{Datacenter: "dc1"}, &roots))
+	require.True(t, roots.WANFederated)
+
+	// OCSP reports the status of the certificates.
+	checkOCSP := func(t *testing.T, cert *structs.IssuedCert, status int) {
+		req, err := ocsp.CreateRequest(testParseCert(t, cert.CertPEM), active, nil)
+		require.NoError(t, err)
+
+		var der []byte
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.OCSP", &structs.CAOCSPRequest{
+			Datacenter: "dc1",
+			Request:    req,
+		}, &der))
+		resp, err := ocsp.ParseResponse(der, active)
+		require.NoError(t, err)
+		require.Equal(t, status, resp.Status)
+	}
+	checkOCSP(t, web, ocsp.Revoked)
+	checkOCSP(t, db, ocsp.Good)
+
+	// Revoking the same certificates again is a no-op.
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.Revoke", &structs.CARevokeRequest{
+		Datacenter:   "dc1",
+		SerialNumber: web.SerialNumber,
+	}, &reply))
+	require.Empty(t, reply.Certs)
+}
+
+func TestConnectCARevoke_MinimumVersion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(cfg *Config) {
+		cfg.PrimaryDatacenter = "dc1"
+		cfg.Build = "1.16.0"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	testRegisterConnectProxy(t, codec, "node1", "web")
+
+	csr, _ := connect.TestCSR(t, connect.TestSpiffeIDService(t, "web"))
+	var cert structs.IssuedCert
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.Sign", &structs.CASignRequest{
+		Datacenter: "dc1",
+		CSR:        csr,
+		Node:       "node1",
+	}, &cert))
+
+	// The certificate is signed but not recorded, and can't be revoked.
+	_, recorded, err := s1.fsm.State().CALeafCert(nil, cert.SerialNumber)
+	require.NoError(t, err)
+	require.Nil(t, recorded)
+
+	var reply structs.CARevokeResponse
+	err = msgpackrpc.CallWithCodec(codec, "ConnectCA.Revoke", &structs.CARevokeRequest{
+		Datacenter: "dc1",
+		Service:    "web",
+	}, &reply)
+	testutil.RequireErrorContains(t, err, "all servers must be running at least Consul 1.17.0 before recording and revoking leaf certificates")
+
+	_, crls, err := s1.fsm.State().CARevocationLists(nil)
+	require.NoError(t, err)
+	require.Empty(t, crls)
+}
+
+func TestConnectCASign_Node(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(cfg *Config) {
+		cfg.PrimaryDatacenter = "dc1"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	testRegisterConnectProxy(t, codec, "node1", "web")
+
+	sign := func(t *testing.T, id connect.CertURI, node string) (string, error) {
+		csr, _ := connect.TestCSR(t, id)
+		args := &structs.CASignRequest{
+			Datacenter: "dc1",
+			CSR:        csr,
+			Node:       node,
+		}
+		var reply structs.IssuedCert
+		if err := msgpackrpc.CallWithCodec(codec, "ConnectCA.Sign", args, &reply); err != nil {
+			return "", err
+		}
+		_, recorded, err := s1.fsm.State().CALeafCert(nil, reply.SerialNumber)
+		require.NoError(t, err)
+		return recorded.Node, nil
+	}
+
+	t.Run("service registered on the node", func(t *testing.T) {
+		node, err := sign(t, connect.TestSpiffeIDService(t, "web"), "node1")
+		require.NoError(t, err)
+		require.Equal(t, "node1", node)
+	})
+
+	t.Run("service registered on another node", func(t *testing.T) {
+		// Agentless proxies and agents requesting certificates for services
+		// registered on other nodes are still signed, without the node.
+		node, err := sign(t, connect.TestSpiffeIDService(t, "web"), "node2")
+		require.NoError(t, err)
+		require.Empty(t, node)
+	})
+
+	t.Run("service not registered", func(t *testing.T) {
+		node, err := sign(t, connect.TestSpiffeIDService(t, "db"), "node2")
+		require.NoError(t, err)
+		require.Empty(t, node)
+	})
+
+	t.Run("agent", func(t *testing.T) {
+		agentID := &connect.SpiffeIDAgent{Host: connect.TestClusterID + ".consul", Datacenter: "dc1", Agent: "node1"}
+		node, err := sign(t, agentID, "")
+		require.NoError(t, err)
+		require.Equal(t, "node1", node)
+
+		_, err = sign(t, agentID, "node2")
+		testutil.RequireErrorContains(t, err, `node "node2" does not match the agent in the CSR`)
+	})
+}
+
+func TestConnectCASign_NodeIdentity(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, s1, codec := testACLServerWithConfig(t, nil, false)
+	waitForLeaderEstablishment(t, s1)
+
+	policy, err := upsertTestPolicyWithRules(codec, TestDefaultInitialManagementToken, "dc1", `service "web" { policy = "write" }`)
+	require.NoError(t, err)
+	token, err := upsertTestToken(codec, TestDefaultInitialManagementToken, "dc1", func(token *structs.ACLToken) {
+		token.Policies = []structs.ACLTokenPolicyLink{{ID: policy.ID}}
+		token.NodeIdentities = []*structs.ACLNodeIdentity{{NodeName: "node1", Datacenter: "dc1"}}
+	})
+	require.NoError(t, err)
+
+	sign := func(t *testing.T, node string) (string, error) {
+		csr, _ := connect.TestCSR(t, connect.TestSpiffeIDService(t, "web"))
+		args := &structs.CASignRequest{
+			Datacenter:   "dc1",
+			CSR:          csr,
+			Node:         node,
+			WriteRequest: structs.WriteRequest{Token: token.SecretID},
+		}
+		var reply structs.IssuedCert
+		if err := msgpackrpc.CallWithCodec(codec, "ConnectCA.Sign", args, &reply); err != nil {
+			return "", err
+		}
+		_, recorded, err := s1.fsm.State().CALeafCert(nil, reply.SerialNumber)
+		require.NoError(t, err)
+		return recorded.Node, nil
+	}
+
+	// The node is taken from the token when the client doesn't send it.
+	node, err := sign(t, "")
+	require.NoError(t, err)
+	require.Equal(t, "node1", node)
+
+	node, err = sign(t, "node1")
+	require.NoError(t, err)
+	require.Equal(t, "node1", node)
+
+	_, err = sign(t, "node2")
+	testutil.RequireErrorContains(t, err, `node "node2" does not match the node identities of the token`)
+}
+
+// testRegisterConnectProxy registers a sidecar proxy for the service on the
+// node.
+func testRegisterConnectProxy(t *testing.T, codec rpc.ClientCodec, node, service string) {
+	t.Helper()
+
+	args := &structs.RegisterRequest{
+		Datacenter: "dc1",
+		Node:       node,
+		Address:    "127.0.0.1",
+		Service: &structs.NodeService{
+			Kind:    structs.ServiceKindConnectProxy,
+			Service: service + "-sidecar-proxy",
+			Port:    20000,
+			Proxy:   structs.ConnectProxyConfig{DestinationServiceName: service},
+		},
+	}
+	var out struct{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.Register", args, &out))
+}
+
+func TestConnectCAStatus(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(cfg *Config) {
+		cfg.PrimaryDatacenter = "dc1"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	sign := func(t *testing.T, service string) *structs.IssuedCert {
+		csr, _ := connect.TestCSR(t, connect.TestSpiffeIDService(t, service))
+		args := &structs.CASignRequest{
+			Datacenter: "dc1",
+			CSR:        csr,
+		}
+		var reply structs.IssuedCert
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.Sign", args, &reply))
+		return &reply
+	}
+	web := sign(t, "web")
+	sign(t, "db")
+
+	var revoked structs.CARevokeResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.Revoke", &structs.CARevokeRequest{
+		Datacenter:   "dc1",
+		SerialNumber: web.SerialNumber,
+	}, &revoked))
+
+	var status structs.CAStatus
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.Status", &structs.DCSpecificRequest{Datacenter: "dc1"}, &status))
+	require.Equal(t, "consul", status.Provider)
+	require.Equal(t, "INITIALIZED", status.State)
+	require.False(t, status.RotationInProgress)
+	require.False(t, status.IntermediatePending)
+	require.Len(t, status.Roots, 1)
+
+	root := status.Roots[0]
+	require.Equal(t, status.ActiveRootID, root.ID)
+	require.True(t, root.Active)
+	require.Nil(t, root.RotatedOutAt)
+	// Revoked certificates are not counted.
+	require.Equal(t, 1, root.LeafCerts)
+	require.Equal(t, root.ID, web.RootID)
+
+	// Rotate the root, the leaves signed by the old root are still counted
+	// against it.
+	_, newKey, err := connect.GeneratePrivateKey()
+	require.NoError(t, err)
+	newConfig := &structs.CAConfiguration{
+		Provider: "consul",
+		Config: map[string]interface{}{
+			"PrivateKey": newKey,
+		},
+	}
+	var reply interface{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.ConfigurationSet", &structs.CARequest{
+		Datacenter: "dc1",
+		Config:     newConfig,
+	}, &reply))
+	sign(t, "api")
+
+	status = structs.CAStatus{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.Status", &structs.DCSpecificRequest{Datacenter: "dc1"}, &status))
+	require.True(t, status.RotationInProgress)
+	require.Len(t, status.Roots, 2)
+	require.True(t, status.Roots[0].Active)
+	require.Equal(t, 1, status.Roots[0].LeafCerts)
+	require.False(t, status.Roots[1].Active)
+	require.Equal(t, root.ID, status.Roots[1].ID)
+	require.NotNil(t, status.Roots[1].RotatedOutAt)
+	require.Equal(t, 1, status.Roots[1].LeafCerts)
+}
@@ -7,6 +7,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/structs"
 )
@@ -53,6 +55,43 @@ func TestFilter_DirEnt(t *testing.T) {
 	}
 }
 
+func TestFilter_DirEnt_KeyGlob(t *testing.T) {
+	t.Parallel()
+	policy, err := acl.NewPolicyFromSource(`
+		key_prefix "app/" {
+			policy = "read"
+		}
+		key_glob "app/*/secrets/**" {
+			policy = "deny"
+		}
+		key "app/web/secrets/public" {
+			policy = "read"
+		}
+	`, nil, nil)
+	require.NoError(t, err)
+	aclR, err := acl.NewPolicyAuthorizerWithDefaults(acl.DenyAll(), []*acl.Policy{policy}, nil)
+	require.NoError(t, err)
+
+	ents := structs.DirEntries{}
+	for _, key := range []string{
+		"app/web/config",
+		"app/web/secrets/db",
+		"app/web/secrets/public",
+		"app/api/secrets/tls/key",
+		"app/api/config",
+		"other",
+	} {
+		ents = append(ents, &structs.DirEntry{Key: key})
+	}
+
+	ents = FilterDirEnt(aclR, ents)
+	var keys []string
+	for _, e := range ents {
+		keys = append(keys, e.Key)
+	}
+	require.Equal(t, []string{"app/web/config", "app/web/secrets/public", "app/api/config"}, keys)
+}
+
 func TestFilter_TxnResults(t *testing.T) {
 	t.Parallel()
 	policy, _ := acl.NewPolicyFromSource(testFilterRules, nil, nil)
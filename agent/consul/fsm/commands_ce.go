@@ -147,6 +147,9 @@ func init() {
 	registerCommand(structs.PeeringSecretsWriteType, (*FSM).applyPeeringSecretsWrite)
 	registerCommand(structs.ResourceOperationType, (*FSM).applyResourceOperation)
 	registerCommand(structs.UpdateVirtualIPRequestType, (*FSM).applyManualVirtualIPs)
+	registerCommand(structs.ACLTemplatedPolicySetRequestType, (*FSM).applyACLTemplatedPolicySetOperation)
+	registerCommand(structs.ACLTemplatedPolicyDeleteRequestType, (*FSM).applyACLTemplatedPolicyDeleteOperation)
+	registerCommand(structs.ACLTokenUsageRequestType, (*FSM).applyACLTokenUsageOperation)
 }
 
 func (c *FSM) applyRegister(buf []byte, index uint64) interface{} {
@@ -456,6 +459,24 @@ func ApplyConnectCAOperationFromRequest(state *state.Store, req *structs.CAReque
 		}
 
 		return sn
+	case structs.CAOpRevokeLeafCerts:
+		if err := state.CARevokeLeafCerts(index, req.LeafCerts, req.RevocationLists); err != nil {
+			return err
+		}
+
+		return true
+	case structs.CAOpDeleteLeafCerts:
+		if err := state.CADeleteLeafCerts(index, req.LeafCerts); err != nil {
+			return err
+		}
+
+		return true
+	case structs.CAOpSetRevocationList:
+		if err := state.CASetRevocationLists(index, req.RevocationLists); err != nil {
+			return err
+		}
+
+		return true
 	default:
 		return fmt.Errorf("Invalid CA operation '%s'", req.Op)
 	}
@@ -472,7 +493,15 @@ func (c *FSM) applyConnectCALeafOperation(buf []byte, index uint64) interface{}
 		[]metrics.Label{{Name: "op", Value: string(req.Op)}})
 	switch req.Op {
 	case structs.CALeafOpIncrementIndex:
-		// Use current index as the new value as well as the value to write at.
+		// Record the issued certificate if there is one, which also sets the
+		// index. Otherwise use current index as the new value as well as the
+		// value to write at.
+		if req.Cert != nil {
+			if err := c.state.CALeafCertSet(index, req.Cert); err != nil {
+				return err
+			}
+			return index
+		}
 		if err := c.state.CALeafSetIndex(index, index); err != nil {
 			return err
 		}
@@ -496,6 +525,7 @@ func (c *FSM) applyACLTokenSetOperation(buf []byte, index uint64) interface{} {
 		AllowMissingPolicyAndRoleIDs: req.AllowMissingLinks,
 		ProhibitUnprivileged:         req.ProhibitUnprivileged,
 		FromReplication:              req.FromReplication,
+		AllowSecretRotation:          req.AllowSecretRotation,
 	}
 	return c.state.ACLTokenBatchSet(index, req.Tokens, opts)
 }
@@ -543,6 +573,39 @@ func (c *FSM) applyACLPolicyDeleteOperation(buf []byte, index uint64) interface{
 	return c.state.ACLPolicyBatchDelete(index, req.PolicyIDs)
 }
 
+func (c *FSM) applyACLTemplatedPolicySetOperation(buf []byte, index uint64) interface{} {
+	var req structs.ACLTemplatedPolicyBatchSetRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSinceWithLabels([]string{"fsm", "acl", "templated_policy"}, time.Now(),
+		[]metrics.Label{{Name: "op", Value: "upsert"}})
+
+	return c.state.ACLTemplatedPolicyBatchSet(index, req.TemplatedPolicies)
+}
+
+func (c *FSM) applyACLTemplatedPolicyDeleteOperation(buf []byte, index uint64) interface{} {
+	var req structs.ACLTemplatedPolicyBatchDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSinceWithLabels([]string{"fsm", "acl", "templated_policy"}, time.Now(),
+		[]metrics.Label{{Name: "op", Value: "delete"}})
+
+	return c.state.ACLTemplatedPolicyBatchDelete(index, req.TemplatedPolicyIDs)
+}
+
+func (c *FSM) applyACLTokenUsageOperation(buf []byte, index uint64) interface{} {
+	var req structs.ACLTokenUsageRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSinceWithLabels([]string{"fsm", "acl", "token"}, time.Now(),
+		[]metrics.Label{{Name: "op", Value: "usage"}})
+
+	return c.state.ACLTokenUsageSet(index, req.Usage)
+}
+
 func (c *FSM) applyConfigEntryOperation(buf []byte, index uint64) interface{} {
 	req := structs.ConfigEntryRequest{
 		Entry: &structs.ProxyConfigEntry{},
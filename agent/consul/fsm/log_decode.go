@@ -0,0 +1,276 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package fsm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/raft"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/proto/private/pbpeering"
+	"github.com/hashicorp/consul/proto/private/pbstorage"
+)
+
+// redacted replaces the value of sensitive fields in decoded log entries.
+const redacted = "<redacted>"
+
+// logDecoders maps message types to a constructor for the value the FSM
+// decodes that type of log into. Message types that are proto encoded return
+// a proto.Message.
+var logDecoders = map[structs.MessageType]func() interface{}{
+	structs.RegisterRequestType:                 func() interface{} { return &structs.RegisterRequest{} },
+	structs.DeregisterRequestType:               func() interface{} { return &structs.DeregisterRequest{} },
+	structs.KVSRequestType:                      func() interface{} { return &structs.KVSRequest{} },
+	structs.SessionRequestType:                  func() interface{} { return &structs.SessionRequest{} },
+	structs.TombstoneRequestType:                func() interface{} { return &structs.TombstoneRequest{} },
+	structs.CoordinateBatchUpdateType:           func() interface{} { return &structs.Coordinates{} },
+	structs.PreparedQueryRequestType:            func() interface{} { return &structs.PreparedQueryRequest{} },
+	structs.TxnRequestType:                      func() interface{} { return &structs.TxnRequest{} },
+	structs.AutopilotRequestType:                func() interface{} { return &structs.AutopilotSetConfigRequest{} },
+	structs.IntentionRequestType:                func() interface{} { return &structs.IntentionRequest{} },
+	structs.ConnectCARequestType:                func() interface{} { return &structs.CARequest{} },
+	structs.ConnectCALeafRequestType:            func() interface{} { return &structs.CALeafRequest{} },
+	structs.ACLTokenSetRequestType:              func() interface{} { return &structs.ACLTokenBatchSetRequest{} },
+	structs.ACLTokenDeleteRequestType:           func() interface{} { return &structs.ACLTokenBatchDeleteRequest{} },
+	structs.ACLBootstrapRequestType:             func() interface{} { return &structs.ACLTokenBootstrapRequest{} },
+	structs.ACLPolicySetRequestType:             func() interface{} { return &structs.ACLPolicyBatchSetRequest{} },
+	structs.ACLPolicyDeleteRequestType:          func() interface{} { return &structs.ACLPolicyBatchDeleteRequest{} },
+	structs.ACLRoleSetRequestType:               func() interface{} { return &structs.ACLRoleBatchSetRequest{} },
+	structs.ACLRoleDeleteRequestType:            func() interface{} { return &structs.ACLRoleBatchDeleteRequest{} },
+	structs.ACLBindingRuleSetRequestType:        func() interface{} { return &structs.ACLBindingRuleBatchSetRequest{} },
+	structs.ACLBindingRuleDeleteRequestType:     func() interface{} { return &structs.ACLBindingRuleBatchDeleteRequest{} },
+	structs.ACLAuthMethodSetRequestType:         func() interface{} { return &structs.ACLAuthMethodBatchSetRequest{} },
+	structs.ACLAuthMethodDeleteRequestType:      func() interface{} { return &structs.ACLAuthMethodBatchDeleteRequest{} },
+	structs.ACLTemplatedPolicySetRequestType:    func() interface{} { return &structs.ACLTemplatedPolicyBatchSetRequest{} },
+	structs.ACLTemplatedPolicyDeleteRequestType: func() interface{} { return &structs.ACLTemplatedPolicyBatchDeleteRequest{} },
+	structs.ACLTokenUsageRequestType:            func() interface{} { return &structs.ACLTokenUsageRequest{} },
+	structs.FederationStateRequestType:          func() interface{} { return &structs.FederationStateRequest{} },
+	structs.SystemMetadataRequestType:           func() interface{} { return &structs.SystemMetadataRequest{} },
+	structs.UpdateVirtualIPRequestType:          func() interface{} { return &state.ServiceVirtualIP{} },
+	structs.ConfigEntryRequestType: func() interface{} {
+		return &structs.ConfigEntryRequest{Entry: &structs.ProxyConfigEntry{}}
+	},
+	structs.PeeringWriteType:             func() interface{} { return &pbpeering.PeeringWriteRequest{} },
+	structs.PeeringDeleteType:            func() interface{} { return &pbpeering.PeeringDeleteRequest{} },
+	structs.PeeringTerminateByIDType:     func() interface{} { return &pbpeering.PeeringTerminateByIDRequest{} },
+	structs.PeeringTrustBundleWriteType:  func() interface{} { return &pbpeering.PeeringTrustBundleWriteRequest{} },
+	structs.PeeringTrustBundleDeleteType: func() interface{} { return &pbpeering.PeeringTrustBundleDeleteRequest{} },
+	structs.PeeringSecretsWriteType:      func() interface{} { return &pbpeering.SecretsWriteRequest{} },
+	structs.ResourceOperationType:        func() interface{} { return &pbstorage.Log{} },
+}
+
+// sensitiveLogFields are the (normalized) field names whose values are
+// never included in decoded log entries.
+var sensitiveLogFields = map[string]struct{}{
+	"secretid":            {},
+	"token":               {},
+	"privatekey":          {},
+	"signingkey":          {},
+	"value":               {},
+	"establishmentsecret": {},
+	"password":            {},
+}
+
+var logFieldSeparators = strings.NewReplacer("_", "", "-", "")
+
+// isSensitiveLogField reports whether a field with the given name should be
+// redacted from a decoded log entry. Names are compared case-insensitively and
+// without separators, since bodies encoded with struct tags or as maps may use
+// snake_case or kebab-case keys such as "private_key".
+func isSensitiveLogField(name string) bool {
+	name = logFieldSeparators.Replace(strings.ToLower(name))
+	if _, ok := sensitiveLogFields[name]; ok {
+		return true
+	}
+	return strings.Contains(name, "secret") || strings.HasSuffix(name, "token")
+}
+
+// DecodeLog decodes a Raft log entry using the same message types the FSM
+// uses to apply it. The returned body is a generic summary of the request
+// with sensitive fields redacted, suitable for showing to operators. Failure
+// to decode the body is reported in the entry rather than returned.
+func DecodeLog(l *raft.Log) *structs.RaftLogEntry {
+	entry := &structs.RaftLogEntry{
+		Index:      l.Index,
+		Term:       l.Term,
+		LogType:    l.Type.String(),
+		AppendedAt: l.AppendedAt,
+		Size:       len(l.Data),
+	}
+	if l.Type != raft.LogCommand || len(l.Data) == 0 {
+		return entry
+	}
+
+	msgType := structs.MessageType(l.Data[0]) &^ structs.IgnoreUnknownTypeFlag
+
+	// Log verification checkpoints carry their metadata in Extensions and
+	// are not chunked even though Extensions is set.
+	if len(l.Extensions) > 0 &&
+		!(msgType == structs.RaftLogVerifierCheckpoint && bytes.HasPrefix(l.Extensions, logVerifierMagicBytes[:])) {
+		entry.Chunked = true
+		return entry
+	}
+	entry.MessageType = msgType.String()
+
+	newReq := logDecoders[msgType]
+	if newReq == nil {
+		return entry
+	}
+
+	body, err := decodeLogBody(l.Data[1:], newReq())
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	entry.Body = body
+	return entry
+}
+
+func decodeLogBody(buf []byte, req interface{}) (map[string]interface{}, error) {
+	var (
+		raw []byte
+		err error
+	)
+	if pb, ok := req.(proto.Message); ok {
+		if err := structs.DecodeProto(buf, pb); err != nil {
+			return nil, fmt.Errorf("failed to decode request: %w", err)
+		}
+		raw, err = protojson.Marshal(pb)
+	} else {
+		if err := structs.Decode(buf, req); err != nil {
+			return nil, fmt.Errorf("failed to decode request: %w", err)
+		}
+		raw, err = json.Marshal(req)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize request: %w", err)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		// Some requests, such as coordinate updates, are not objects.
+		var list []interface{}
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, fmt.Errorf("failed to summarize request: %w", err)
+		}
+		body = map[string]interface{}{"Items": list}
+	}
+	redactLogBody(body)
+	return body, nil
+}
+
+// redactLogBody walks a decoded request in place, replacing the values of
+// sensitive fields and dropping empty ones to keep the summary short.
+func redactLogBody(v interface{}) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for k, child := range x {
+			switch {
+			case isEmptyLogValue(child):
+				delete(x, k)
+			case isSensitiveLogField(k):
+				x[k] = redacted
+			default:
+				redactLogBody(child)
+			}
+		}
+	case []interface{}:
+		for _, child := range x {
+			redactLogBody(child)
+		}
+	}
+}
+
+func isEmptyLogValue(v interface{}) bool {
+	switch x := v.(type) {
+	case nil:
+		return true
+	case string:
+		return x == ""
+	case bool:
+		return !x
+	case float64:
+		return x == 0
+	case map[string]interface{}:
+		return len(x) == 0
+	case []interface{}:
+		return len(x) == 0
+	}
+	return false
+}
+
+// MatchesLogFilter reports whether a decoded entry satisfies the type filter.
+// Index bounds and limits are applied by ReadLogs.
+func MatchesLogFilter(entry *structs.RaftLogEntry, filter structs.RaftLogFilter) bool {
+	if len(filter.Types) == 0 {
+		return true
+	}
+	for _, t := range filter.Types {
+		if strings.EqualFold(t, entry.MessageType) || strings.EqualFold(t, entry.LogType) {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultRaftLogLimit is the number of entries returned by ReadLogs when the
+// filter does not specify a limit.
+const defaultRaftLogLimit = 100
+
+// ReadLogs reads and decodes entries from a Raft log store, newest first,
+// until the filter's limit is reached. The entries in the response are in
+// ascending index order.
+func ReadLogs(store raft.LogStore, filter structs.RaftLogFilter) (*structs.RaftLogResponse, error) {
+	first, err := store.FirstIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read first log index: %w", err)
+	}
+	last, err := store.LastIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read last log index: %w", err)
+	}
+	resp := &structs.RaftLogResponse{FirstIndex: first, LastIndex: last}
+	if last == 0 {
+		return resp, nil
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultRaftLogLimit
+	}
+	min, max := first, last
+	if filter.MinIndex > min {
+		min = filter.MinIndex
+	}
+	if filter.MaxIndex != 0 && filter.MaxIndex < max {
+		max = filter.MaxIndex
+	}
+
+	for idx := max; idx >= min && idx > 0 && len(resp.Entries) < limit; idx-- {
+		var l raft.Log
+		if err := store.GetLog(idx, &l); err != nil {
+			if err == raft.ErrLogNotFound {
+				// Compaction may have removed the entry since we read the bounds.
+				continue
+			}
+			return nil, fmt.Errorf("failed to read log at index %d: %w", idx, err)
+		}
+		entry := DecodeLog(&l)
+		if MatchesLogFilter(entry, filter) {
+			resp.Entries = append(resp.Entries, entry)
+		}
+	}
+
+	for i, j := 0, len(resp.Entries)-1; i < j; i, j = i+1, j-1 {
+		resp.Entries[i], resp.Entries[j] = resp.Entries[j], resp.Entries[i]
+	}
+	return resp, nil
+}
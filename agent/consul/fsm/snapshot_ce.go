@@ -30,6 +30,8 @@ func init() {
 	registerRestorer(structs.ConnectCARequestType, restoreConnectCA)
 	registerRestorer(structs.ConnectCAProviderStateType, restoreConnectCAProviderState)
 	registerRestorer(structs.ConnectCAConfigType, restoreConnectCAConfig)
+	registerRestorer(structs.ConnectCALeafCertType, restoreConnectCALeafCert)
+	registerRestorer(structs.ConnectCARevocationListType, restoreConnectCARevocationList)
 	registerRestorer(structs.IndexRequestType, restoreIndex)
 	registerRestorer(structs.ACLTokenSetRequestType, restoreToken)
 	registerRestorer(structs.ACLPolicySetRequestType, restorePolicy)
@@ -44,6 +46,7 @@ func init() {
 	registerRestorer(structs.PeeringWriteType, restorePeering)
 	registerRestorer(structs.PeeringTrustBundleWriteType, restorePeeringTrustBundle)
 	registerRestorer(structs.PeeringSecretsWriteType, restorePeeringSecrets)
+	registerRestorer(structs.ACLTemplatedPolicySetRequestType, restoreTemplatedPolicy)
 }
 
 func persistCE(s *snapshot, sink raft.SnapshotSink, encoder *codec.Encoder) error {
@@ -83,6 +86,12 @@ func persistCE(s *snapshot, sink raft.SnapshotSink, encoder *codec.Encoder) erro
 	if err := s.persistConnectCAConfig(sink, encoder); err != nil {
 		return err
 	}
+	if err := s.persistConnectCALeafCerts(sink, encoder); err != nil {
+		return err
+	}
+	if err := s.persistConnectCARevocationLists(sink, encoder); err != nil {
+		return err
+	}
 	if err := s.persistConfigEntries(sink, encoder); err != nil {
 		return err
 	}
@@ -235,6 +244,20 @@ func (s *snapshot) persistACLs(sink raft.SnapshotSink,
 		}
 	}
 
+	templatedPolicies, err := s.state.ACLTemplatedPolicies()
+	if err != nil {
+		return err
+	}
+
+	for templatedPolicy := templatedPolicies.Next(); templatedPolicy != nil; templatedPolicy = templatedPolicies.Next() {
+		if _, err := sink.Write([]byte{byte(structs.ACLTemplatedPolicySetRequestType)}); err != nil {
+			return err
+		}
+		if err := encoder.Encode(templatedPolicy.(*structs.ACLTemplatedPolicyDefinition)); err != nil {
+			return err
+		}
+	}
+
 	roles, err := s.state.ACLRoles()
 	if err != nil {
 		return err
@@ -422,6 +445,42 @@ func (s *snapshot) persistConnectCAProviderState(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *snapshot) persistConnectCALeafCerts(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	certs, err := s.state.CALeafCerts()
+	if err != nil {
+		return err
+	}
+
+	for _, cert := range certs {
+		if _, err := sink.Write([]byte{byte(structs.ConnectCALeafCertType)}); err != nil {
+			return err
+		}
+		if err := encoder.Encode(cert); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *snapshot) persistConnectCARevocationLists(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	crls, err := s.state.CARevocationLists()
+	if err != nil {
+		return err
+	}
+
+	for _, crl := range crls {
+		if _, err := sink.Write([]byte{byte(structs.ConnectCARevocationListType)}); err != nil {
+			return err
+		}
+		if err := encoder.Encode(crl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *snapshot) persistLegacyIntentions(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 	//nolint:staticcheck
@@ -761,6 +820,28 @@ func restoreConnectCAConfig(header *SnapshotHeader, restore *state.Restore, deco
 	return nil
 }
 
+func restoreConnectCALeafCert(header *SnapshotHeader, restore *state.Restore, decoder *codec.Decoder) error {
+	var req structs.IssuedCert
+	if err := decoder.Decode(&req); err != nil {
+		return err
+	}
+	if err := restore.CALeafCert(&req); err != nil {
+		return err
+	}
+	return nil
+}
+
+func restoreConnectCARevocationList(header *SnapshotHeader, restore *state.Restore, decoder *codec.Decoder) error {
+	var req structs.CARevocationList
+	if err := decoder.Decode(&req); err != nil {
+		return err
+	}
+	if err := restore.CARevocationList(&req); err != nil {
+		return err
+	}
+	return nil
+}
+
 func restoreIndex(header *SnapshotHeader, restore *state.Restore, decoder *codec.Decoder) error {
 	var req state.IndexEntry
 	if err := decoder.Decode(&req); err != nil {
@@ -789,6 +870,14 @@ func restorePolicy(header *SnapshotHeader, restore *state.Restore, decoder *code
 	return restore.ACLPolicy(&req)
 }
 
+func restoreTemplatedPolicy(header *SnapshotHeader, restore *state.Restore, decoder *codec.Decoder) error {
+	var req structs.ACLTemplatedPolicyDefinition
+	if err := decoder.Decode(&req); err != nil {
+		return err
+	}
+	return restore.ACLTemplatedPolicy(&req)
+}
+
 func restoreConfigEntry(header *SnapshotHeader, restore *state.Restore, decoder *codec.Decoder) error {
 	var req structs.ConfigEntryRequest
 	if err := decoder.Decode(&req); err != nil {
@@ -113,6 +113,14 @@ func TestFSM_SnapshotRestore_CE(t *testing.T) {
 	policy.SetHash(true)
 	require.NoError(t, fsm.state.ACLPolicySet(1, policy))
 
+	templatedPolicy := &structs.ACLTemplatedPolicyDefinition{
+		ID:       "4f1a9b2e-7a3c-4f0e-9a0e-3f0d2b6c8e11",
+		Name:     "team-kv-namespace",
+		Template: `key_prefix "teams/{{.Name}}/" { policy = "write" }`,
+	}
+	templatedPolicy.SetHash(true)
+	require.NoError(t, fsm.state.ACLTemplatedPolicySet(1, templatedPolicy))
+
 	role := &structs.ACLRole{
 		ID:          "86dedd19-8fae-4594-8294-4e6948a81f9a",
 		Name:        "some-role",
@@ -237,6 +245,22 @@ func TestFSM_SnapshotRestore_CE(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, ok)
 
+	// Issued leaf certificates and revocation list
+	revokedAt := time.Now().UTC().Truncate(time.Second)
+	leafCert := &structs.IssuedCert{
+		SerialNumber: "01",
+		Service:      "web",
+		Node:         "foo",
+		ValidBefore:  revokedAt.Add(time.Hour),
+		RevokedAt:    &revokedAt,
+	}
+	require.NoError(t, fsm.state.CALeafCertSet(16, leafCert))
+	crls := []*structs.CARevocationList{
+		{PEM: "crl", SigningKeyID: "key", Revoked: 1},
+		{PEM: "old-crl", SigningKeyID: "old-key"},
+	}
+	require.NoError(t, fsm.state.CASetRevocationLists(16, crls))
+
 	// CA Config
 	caConfig := &structs.CAConfiguration{
 		ClusterID: "foo",
@@ -762,6 +786,11 @@ func TestFSM_SnapshotRestore_CE(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, policy, policy2)
 
+	// Verify ACL Templated Policy is restored
+	_, templatedPolicy2, err := fsm2.state.ACLTemplatedPolicyGetByID(nil, templatedPolicy.ID)
+	require.NoError(t, err)
+	require.Equal(t, templatedPolicy, templatedPolicy2)
+
 	// Verify tombstones are restored
 	func() {
 		snap := fsm2.state.Snapshot()
@@ -807,6 +836,14 @@ func TestFSM_SnapshotRestore_CE(t *testing.T) {
 	require.Equal(t, "foo", provider.PrivateKey)
 	require.Equal(t, "bar", provider.RootCert)
 
+	// Verify the issued leaf certificates and revocation list are restored.
+	_, leafCerts, err := fsm2.state.CALeafCerts(nil)
+	require.NoError(t, err)
+	require.Equal(t, []*structs.IssuedCert{leafCert}, leafCerts)
+	_, crls2, err := fsm2.state.CARevocationLists(nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, crls, crls2)
+
 	// Verify CA configuration is restored.
 	_, caConf, err := fsm2.state.CAConfig(nil)
 	require.NoError(t, err)
@@ -6,6 +6,8 @@ package consul
 import (
 	"fmt"
 	"net"
+	"net/url"
+	"strings"
 
 	"github.com/hashicorp/go-bexpr"
 	"github.com/hashicorp/go-hclog"
@@ -280,6 +282,66 @@ func (m *Internal) CatalogOverview(args *structs.DCSpecificRequest, reply *struc
 	return nil
 }
 
+// OIDCAuthMethods lists the auth methods of type "oidc". It requires no ACL
+// token since it is used to offer login options to users that do not have
+// one yet.
+func (m *Internal) OIDCAuthMethods(args *structs.DCSpecificRequest, reply *structs.ACLOIDCAuthMethodListResponse) error {
+	if err := m.srv.validateEnterpriseRequest(&args.EnterpriseMeta, false); err != nil {
+		return err
+	}
+
+	if done, err := m.srv.ForwardRPC("Internal.OIDCAuthMethods", args, reply); done {
+		return err
+	}
+
+	return m.srv.blockingQuery(&args.QueryOptions, &reply.QueryMeta,
+		func(ws memdb.WatchSet, state *state.Store) error {
+			index, methods, err := state.ACLAuthMethodList(ws, &args.EnterpriseMeta)
+			if err != nil {
+				return err
+			}
+
+			var stubs []*structs.ACLOIDCAuthMethodStub
+			for _, method := range methods {
+				if method.Type != "oidc" {
+					continue
+				}
+				discoveryURL, _ := method.Config["OIDCDiscoveryURL"].(string)
+				stubs = append(stubs, &structs.ACLOIDCAuthMethodStub{
+					Name:           method.Name,
+					DisplayName:    method.DisplayName,
+					Kind:           oidcProviderKind(discoveryURL),
+					EnterpriseMeta: method.EnterpriseMeta,
+				})
+			}
+
+			reply.Index, reply.AuthMethods = index, stubs
+			return nil
+		})
+}
+
+// oidcProviderKind guesses the well-known provider behind an OIDC discovery
+// URL.
+func oidcProviderKind(discoveryURL string) string {
+	u, err := url.Parse(discoveryURL)
+	if err != nil {
+		return "no-icon"
+	}
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case host == "accounts.google.com":
+		return "google"
+	case strings.HasSuffix(host, ".okta.com"), strings.HasSuffix(host, ".oktapreview.com"):
+		return "okta"
+	case strings.HasSuffix(host, ".auth0.com"):
+		return "auth0"
+	case host == "login.microsoftonline.com":
+		return "microsoft"
+	default:
+		return "no-icon"
+	}
+}
+
 func (m *Internal) ServiceTopology(args *structs.ServiceSpecificRequest, reply *structs.IndexedServiceTopology) error {
 	if done, err := m.srv.ForwardRPC("Internal.ServiceTopology", args, reply); done {
 		return err
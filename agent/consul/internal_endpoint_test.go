@@ -3727,3 +3727,18 @@ func TestInternal_AssignManualServiceVIPs(t *testing.T) {
 		})
 	}
 }
+
+func TestInternal_oidcProviderKind(t *testing.T) {
+	cases := map[string]string{
+		"https://accounts.google.com":                           "google",
+		"https://dev-123456.okta.com/oauth2/default":            "okta",
+		"https://myco.auth0.com/":                               "auth0",
+		"https://login.microsoftonline.com/tenant-id/v2.0":      "microsoft",
+		"https://keycloak.example.com/auth/realms/consul":       "no-icon",
+		"https://evil.example.com/accounts.google.com/.well-kn": "no-icon",
+		"": "no-icon",
+	}
+	for discoveryURL, expect := range cases {
+		require.Equal(t, expect, oidcProviderKind(discoveryURL), discoveryURL)
+	}
+}
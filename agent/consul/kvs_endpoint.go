@@ -18,6 +18,7 @@ import (
 	"github.com/hashicorp/consul/agent/consul/state"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sentinel"
 )
 
 var KVSummaries = []prometheus.SummaryDefinition{
@@ -36,7 +37,7 @@ type KVS struct {
 // preApply does all the verification of a KVS update that is performed BEFORE
 // we submit as a Raft log entry. This includes enforcing the lock delay which
 // must only be done on the leader.
-func kvsPreApply(logger hclog.Logger, srv *Server, authz resolver.Result, op api.KVOp, dirEnt *structs.DirEntry) (bool, error) {
+func kvsPreApply(logger hclog.Logger, srv *Server, authz resolver.Result, op api.KVOp, dirEnt *structs.DirEntry, sourceIP string) (bool, error) {
 	// Verify the entry.
 	if dirEnt.Key == "" && op != api.KVDeleteTree {
 		return false, fmt.Errorf("Must provide key")
@@ -47,6 +48,7 @@ func kvsPreApply(logger hclog.Logger, srv *Server, authz resolver.Result, op api
 	case api.KVDeleteTree:
 		var authzContext acl.AuthorizerContext
 		dirEnt.FillAuthzContext(&authzContext)
+		authzContext.SentinelScope = kvsSentinelScope(authz, dirEnt, sourceIP)
 
 		if err := authz.ToAllowAuthorizer().KeyWritePrefixAllowed(dirEnt.Key, &authzContext); err != nil {
 			return false, err
@@ -69,6 +71,7 @@ func kvsPreApply(logger hclog.Logger, srv *Server, authz resolver.Result, op api
 	default:
 		var authzContext acl.AuthorizerContext
 		dirEnt.FillAuthzContext(&authzContext)
+		authzContext.SentinelScope = kvsSentinelScope(authz, dirEnt, sourceIP)
 
 		if err := authz.ToAllowAuthorizer().KeyWriteAllowed(dirEnt.Key, &authzContext); err != nil {
 			return false, err
@@ -96,6 +99,15 @@ func kvsPreApply(logger hclog.Logger, srv *Server, authz resolver.Result, op api
 	return true, nil
 }
 
+// kvsSentinelScope returns the scope that the conditions of key rules are
+// evaluated against when writing the entry.
+func kvsSentinelScope(authz resolver.Result, dirEnt *structs.DirEntry, sourceIP string) sentinel.ScopeFn {
+	return func() map[string]interface{} {
+		scope := sentinel.ScopeKVUpsert(dirEnt.Key, dirEnt.Value, dirEnt.Flags)
+		return sentinel.AddRequest(scope, sentinelRequest(authz.ACLIdentity, sourceIP))
+	}
+}
+
 // Apply is used to apply a KVS update request to the data store.
 func (k *KVS) Apply(args *structs.KVSRequest, reply *bool) error {
 	if done, err := k.srv.ForwardRPC("KVS.Apply", args, reply); done {
@@ -113,7 +125,7 @@ func (k *KVS) Apply(args *structs.KVSRequest, reply *bool) error {
 		return err
 	}
 
-	ok, err := kvsPreApply(k.logger, k.srv, authz, args.Op, &args.DirEnt)
+	ok, err := kvsPreApply(k.logger, k.srv, authz, args.Op, &args.DirEnt, args.SourceIP)
 	if err != nil {
 		return err
 	}
@@ -132,6 +132,60 @@ func TestKVS_Apply_ACLDeny(t *testing.T) {
 	}
 }
 
+func TestKVS_Apply_ACLCondition(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.PrimaryDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLInitialManagementToken = "root"
+		c.ACLResolverSettings.ACLDefaultPolicy = "deny"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForTestAgent(t, s1.RPC, "dc1", testrpc.WithToken("root"))
+
+	id := createToken(t, codec, `
+key_prefix "app/" {
+	policy = "write"
+	condition = "value_size < 8 && cidrmatch(source_ip, \"10.0.0.0/8\")"
+}
+`)
+
+	apply := func(op api.KVOp, key, value, sourceIP string) error {
+		args := structs.KVSRequest{
+			Datacenter: "dc1",
+			Op:         op,
+			DirEnt: structs.DirEntry{
+				Key:   key,
+				Value: []byte(value),
+			},
+			SourceIP:     sourceIP,
+			WriteRequest: structs.WriteRequest{Token: id},
+		}
+		var out bool
+		return msgpackrpc.CallWithCodec(codec, "KVS.Apply", &args, &out)
+	}
+
+	require.NoError(t, apply(api.KVSet, "app/config", "small", "10.1.2.3"))
+
+	err := apply(api.KVSet, "app/config", "much too large", "10.1.2.3")
+	require.True(t, acl.IsErrPermissionDenied(err), "err: %v", err)
+
+	err = apply(api.KVSet, "app/config", "small", "192.168.1.1")
+	require.True(t, acl.IsErrPermissionDenied(err), "err: %v", err)
+
+	err = apply(api.KVDeleteTree, "app/", "", "192.168.1.1")
+	require.True(t, acl.IsErrPermissionDenied(err), "err: %v", err)
+	require.NoError(t, apply(api.KVDeleteTree, "app/", "", "10.1.2.3"))
+}
+
 func TestKVS_Get(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -611,6 +611,7 @@ func (s *Server) startACLReplication(ctx context.Context) {
 	s.initReplicationStatus()
 	s.leaderRoutineManager.Start(ctx, aclPolicyReplicationRoutineName, s.runACLPolicyReplicator)
 	s.leaderRoutineManager.Start(ctx, aclRoleReplicationRoutineName, s.runACLRoleReplicator)
+	s.leaderRoutineManager.Start(ctx, aclTemplatedPolicyReplicationRoutineName, s.runACLTemplatedPolicyReplicator)
 
 	if s.config.ACLTokenReplication {
 		s.leaderRoutineManager.Start(ctx, aclTokenReplicationRoutineName, s.runACLTokenReplicator)
@@ -638,6 +639,14 @@ func (s *Server) runACLRoleReplicator(ctx context.Context) error {
 	return s.runACLReplicator(ctx, roleLogger, structs.ACLReplicateRoles, s.replicateACLRoles, "acl-roles")
 }
 
+// This function is only intended to be run as a managed go routine, it will block until
+// the context passed in indicates that it should exit.
+func (s *Server) runACLTemplatedPolicyReplicator(ctx context.Context) error {
+	templatedPolicyLogger := s.aclReplicationLogger(structs.ACLReplicateTemplatedPolicies.SingularNoun())
+	templatedPolicyLogger.Info("started ACL Templated Policy replication")
+	return s.runACLReplicator(ctx, templatedPolicyLogger, structs.ACLReplicateTemplatedPolicies, s.replicateACLTemplatedPolicies, "acl-templated-policies")
+}
+
 // This function is only intended to be run as a managed go routine, it will block until
 // the context passed in indicates that it should exit.
 func (s *Server) runACLTokenReplicator(ctx context.Context) error {
@@ -720,6 +729,7 @@ func (s *Server) stopACLReplication() {
 	// these will be no-ops when not started
 	s.leaderRoutineManager.Stop(aclPolicyReplicationRoutineName)
 	s.leaderRoutineManager.Stop(aclRoleReplicationRoutineName)
+	s.leaderRoutineManager.Stop(aclTemplatedPolicyReplicationRoutineName)
 	s.leaderRoutineManager.Stop(aclTokenReplicationRoutineName)
 }
 
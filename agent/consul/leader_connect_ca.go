@@ -45,12 +45,13 @@ type caServerDelegate interface {
 
 	State() *state.Store
 	IsLeader() bool
-	ApplyCALeafRequest() (uint64, error)
+	ApplyCALeafRequest(cert *structs.IssuedCert) (uint64, error)
 
 	forwardDC(method, dc string, args interface{}, reply interface{}) error
 	generateCASignRequest(csr string) *structs.CASignRequest
 
 	ServersSupportMultiDCConnectCA() error
+	ServersSupportLeafCertRevocation() error
 }
 
 // CAManager is a wrapper around CA operations such as updating roots, an intermediate
@@ -73,6 +74,10 @@ type CAManager struct {
 	// instance.
 	providerRoot *structs.CARoot
 
+	// revocationLock serializes the updates to the revoked leaf certificates
+	// and the revocation list.
+	revocationLock sync.Mutex
+
 	// stateLock protects the internal state used for administrative CA tasks.
 	stateLock    sync.Mutex
 	state        caState
@@ -98,20 +103,16 @@ func (c *caDelegateWithState) ApplyCARequest(req *structs.CARequest) (interface{
 	return c.Server.raftApplyMsgpack(structs.ConnectCARequestType, req)
 }
 
-func (c *caDelegateWithState) ApplyCALeafRequest() (uint64, error) {
-	// TODO(banks): when we implement IssuedCerts table we can use the insert to
-	// that as the raft index to return in response.
-	//
-	// UPDATE(mkeeler): The original implementation relied on updating the CAConfig
-	// and using its index as the ModifyIndex for certs. This was buggy. The long
-	// term goal is still to insert some metadata into raft about the certificates
-	// and use that raft index for the ModifyIndex. This is a partial step in that
-	// direction except that we only are setting an index and not storing the
-	// metadata.
+func (c *caDelegateWithState) ApplyCALeafRequest(cert *structs.IssuedCert) (uint64, error) {
 	req := structs.CALeafRequest{
 		Op:         structs.CALeafOpIncrementIndex,
 		Datacenter: c.Server.config.Datacenter,
 	}
+	// Older servers drop the certificate, so it is only recorded once all the
+	// servers are able to keep it.
+	if err := c.ServersSupportLeafCertRevocation(); err == nil {
+		req.Cert = cert
+	}
 	resp, err := c.Server.raftApplyMsgpack(structs.ConnectCALeafRequestType|structs.IgnoreUnknownTypeFlag, &req)
 	if err != nil {
 		return 0, err
@@ -143,6 +144,10 @@ func (c *caDelegateWithState) ServersSupportMultiDCConnectCA() error {
 	return nil
 }
 
+func (c *caDelegateWithState) ServersSupportLeafCertRevocation() error {
+	return c.Server.requireServersMinimumVersion(minCALeafCertRevocationVersion, "recording and revoking leaf certificates")
+}
+
 func (c *caDelegateWithState) ProviderState(id string) (*structs.CAConsulProviderState, error) {
 	_, s, err := c.fsm.State().CAProviderState(id)
 	return s, err
@@ -337,6 +342,7 @@ func (c *CAManager) Start(ctx context.Context) {
 func (c *CAManager) Stop() {
 	c.leaderRoutineManager.Stop(secondaryCARootWatchRoutineName)
 	c.leaderRoutineManager.Stop(intermediateCertRenewWatchRoutineName)
+	c.leaderRoutineManager.Stop(caRevocationListRoutineName)
 	c.leaderRoutineManager.Stop(backgroundCAInitializationRoutineName)
 
 	if provider, _ := c.getCAProvider(); provider != nil {
@@ -357,6 +363,7 @@ func (c *CAManager) startPostInitializeRoutines(ctx context.Context) {
 	}
 
 	c.leaderRoutineManager.Start(ctx, intermediateCertRenewWatchRoutineName, c.runRenewIntermediate)
+	c.leaderRoutineManager.Start(ctx, caRevocationListRoutineName, c.runRevocationList)
 }
 
 func (c *CAManager) backgroundCAInitialization(ctx context.Context) error {
@@ -463,6 +470,8 @@ func (c *CAManager) newProvider(conf *structs.CAConfiguration) (ca.Provider, err
 		return ca.NewVaultProvider(logger), nil
 	case structs.AWSCAProvider:
 		return ca.NewAWSProvider(logger), nil
+	case structs.OfflineCAProvider:
+		return ca.NewOfflineProvider(c.delegate, logger), nil
 	default:
 		if c.providerShim != nil {
 			return c.providerShim, nil
@@ -541,6 +550,29 @@ func (c *CAManager) primaryInitialize(provider ca.Provider, conf *structs.CAConf
 	if err != nil {
 		return err
 	}
+
+	// provider may keep its root offline in which case the leaf signing
+	// cert is the intermediate that was last uploaded, if any. The stored
+	// root already tracks it unless the root has changed.
+	if usesOfflineRoot(provider) {
+		leafPem, err := provider.ActiveLeafSigningCert()
+		if err != nil {
+			return fmt.Errorf("error fetching leaf signing cert: %w", err)
+		}
+		switch {
+		case leafPem == "":
+			c.logger.Warn("the offline CA provider has no intermediate certificate yet, " +
+				"leaf certificates cannot be signed until one is set with 'consul connect ca set-intermediate'")
+		case activeRoot != nil && activeRoot.ID == rootCA.ID:
+			rootCA.SigningKeyID = activeRoot.SigningKeyID
+		default:
+			if err := setLeafSigningCert(rootCA, leafPem); err != nil {
+				return fmt.Errorf("error setting leaf signing cert: %w", err)
+			}
+			rootUpdateRequired = true
+		}
+	}
+
 	if activeRoot != nil && !rootUpdateRequired {
 		// This state shouldn't be possible to get into because we update the root and
 		// CA config in the same FSM operation.
@@ -922,6 +954,27 @@ func (c *CAManager) primaryUpdateRootCA(newProvider ca.Provider, args *structs.C
 		}
 	}
 
+	// provider may keep its root offline in which case we keep using the
+	// intermediates of the existing root, or wait for one to be uploaded for a
+	// new root.
+	if usesOfflineRoot(newProvider) {
+		if root != nil && root.ID == newActiveRoot.ID {
+			newActiveRoot.IntermediateCerts = root.IntermediateCerts
+			newActiveRoot.SigningKeyID = root.SigningKeyID
+		} else {
+			leafPem, err := newProvider.ActiveLeafSigningCert()
+			if err != nil {
+				return fmt.Errorf("error fetching leaf signing cert: %w", err)
+			}
+			if leafPem == "" {
+				c.logger.Warn("the offline CA provider has no intermediate certificate yet, " +
+					"leaf certificates cannot be signed until one is set with 'consul connect ca set-intermediate'")
+			} else if err := setLeafSigningCert(newActiveRoot, leafPem); err != nil {
+				return fmt.Errorf("error setting leaf signing cert: %w", err)
+			}
+		}
+	}
+
 	// If the root didn't change, just update the config and return.
 	if root != nil && root.ID == newActiveRoot.ID {
 		args.Op = structs.CAOpSetConfig
@@ -1059,6 +1112,160 @@ func (c *CAManager) primaryRenewIntermediate(provider ca.Provider, newActiveRoot
 	return nil
 }
 
+// primaryRemindOfflineIntermediate warns operators when the intermediate of a
+// provider with an offline root needs to be replaced, and prepares the CSR for
+// its replacement. It should only be called while the state lock is held by
+// setting the state to non-ready.
+func (c *CAManager) primaryRemindOfflineIntermediate(provider ca.Provider, forceNow bool) error {
+	activeIntermediate, err := provider.ActiveLeafSigningCert()
+	if err != nil {
+		return err
+	}
+	if activeIntermediate == "" {
+		c.logger.Warn("the offline CA provider has no intermediate certificate yet, " +
+			"leaf certificates cannot be signed until one is set with 'consul connect ca set-intermediate'")
+		return nil
+	}
+
+	intermediateCert, err := connect.ParseCert(activeIntermediate)
+	if err != nil {
+		return fmt.Errorf("error parsing active intermediate cert: %v", err)
+	}
+	if !forceNow && lessThanHalfTimePassed(c.timeNow(), intermediateCert.NotBefore, intermediateCert.NotAfter) {
+		return nil
+	}
+
+	if _, _, err := provider.GenerateIntermediateCSR(); err != nil {
+		return fmt.Errorf("error generating intermediate CSR: %w", err)
+	}
+
+	c.logger.Warn("the intermediate certificate of the offline CA provider must be renewed, "+
+		"sign the CSR from 'consul connect ca csr' with the offline root and upload it with 'consul connect ca set-intermediate'",
+		"expiration", intermediateCert.NotAfter,
+	)
+	return nil
+}
+
+// PrimaryIntermediateCSR returns the CSR for the next intermediate of a
+// provider whose root is kept offline. The same CSR is returned until an
+// intermediate signed for it is set with PrimarySetIntermediate.
+func (c *CAManager) PrimaryIntermediateCSR() (string, error) {
+	if _, err := c.setState(caStateRenewIntermediate, true); err != nil {
+		return "", err
+	}
+	defer c.setState(caStateInitialized, false)
+
+	provider, err := c.offlineRootProvider()
+	if err != nil {
+		return "", err
+	}
+
+	csr, _, err := provider.GenerateIntermediateCSR()
+	if err != nil {
+		return "", err
+	}
+	return csr, nil
+}
+
+// PrimarySetIntermediate validates an intermediate signed by the offline
+// root of the provider and rotates the leaf signing cert to it. The previous
+// intermediates are kept until they expire so that the leaf certificates they
+// signed remain valid.
+func (c *CAManager) PrimarySetIntermediate(intermediatePEM string) error {
+	if _, err := c.setState(caStateRenewIntermediate, true); err != nil {
+		return err
+	}
+	defer c.setState(caStateInitialized, false)
+
+	provider, err := c.offlineRootProvider()
+	if err != nil {
+		return err
+	}
+
+	state := c.delegate.State()
+	_, root, err := state.CARootActive(nil)
+	if err != nil {
+		return err
+	}
+	if root == nil {
+		return fmt.Errorf("CA is uninitialized: no active root")
+	}
+	activeRoot := root.Clone()
+
+	intermediatePEM = lib.EnsureTrailingNewline(intermediatePEM)
+	if err := c.validateOfflineIntermediate(activeRoot, intermediatePEM); err != nil {
+		return err
+	}
+
+	if err := provider.SetIntermediate(intermediatePEM, activeRoot.RootCert, ""); err != nil {
+		return fmt.Errorf("failed to set the intermediate certificate with the CA provider: %w", err)
+	}
+
+	if err := setLeafSigningCert(activeRoot, intermediatePEM); err != nil {
+		return fmt.Errorf("failed to set the leaf signing cert to the intermediate: %w", err)
+	}
+
+	if err := c.persistNewRootAndConfig(provider, activeRoot, nil); err != nil {
+		return err
+	}
+
+	c.setCAProvider(provider, activeRoot)
+
+	c.logger.Info("set new intermediate certificate signed by the offline root", "signing_key_id", activeRoot.SigningKeyID)
+	return nil
+}
+
+// offlineRootProvider returns the active provider if this is the primary
+// datacenter and the provider keeps its root offline.
+func (c *CAManager) offlineRootProvider() (ca.Provider, error) {
+	if !c.serverConf.InPrimaryDatacenter() {
+		return nil, ErrNotPrimaryDatacenter
+	}
+	provider, _ := c.getCAProvider()
+	if provider == nil {
+		return nil, fmt.Errorf("CA is uninitialized: provider is nil")
+	}
+	if !usesOfflineRoot(provider) {
+		return nil, fmt.Errorf("the CA provider does not use an offline root")
+	}
+	return provider, nil
+}
+
+// validateOfflineIntermediate checks that an uploaded intermediate chains back
+// to the active root and that it remains valid long enough to sign leaf
+// certificates.
+func (c *CAManager) validateOfflineIntermediate(activeRoot *structs.CARoot, intermediatePEM string) error {
+	intermediate, chain, err := connect.ParseIntermediateCerts(intermediatePEM)
+	if err != nil {
+		return fmt.Errorf("error parsing intermediate cert: %w", err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM([]byte(activeRoot.RootCert))
+	if _, err := intermediate.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: chain,
+		CurrentTime:   c.timeNow(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return fmt.Errorf("intermediate cert does not chain to the active root %q: %w", activeRoot.ID, err)
+	}
+
+	_, config, err := c.delegate.State().CAConfig(nil)
+	if err != nil {
+		return err
+	}
+	commonCfg, err := config.GetCommonConfig()
+	if err != nil {
+		return err
+	}
+	if intermediate.NotAfter.Before(c.timeNow().Add(commonCfg.LeafCertTTL)) {
+		return fmt.Errorf("intermediate cert expires at %s, before leaf certificates signed now would (LeafCertTTL %s)",
+			intermediate.NotAfter, commonCfg.LeafCertTTL)
+	}
+	return nil
+}
+
 // secondaryRequestNewSigningCert creates a Certificate Signing Request, sends
 // the request to the primary, and stores the received certificate in the
 // provider.
@@ -1184,6 +1391,12 @@ func (c *CAManager) renewIntermediate(ctx context.Context, forceNow bool) error
 	}
 	activeRoot := root.Clone()
 
+	// The intermediate of a provider with an offline root can only be renewed
+	// by an operator, so we remind them instead.
+	if isPrimary && usesOfflineRoot(provider) {
+		return c.primaryRemindOfflineIntermediate(provider, forceNow)
+	}
+
 	// If this is the primary, check if this is a provider that uses an intermediate cert. If
 	// it isn't, we don't need to check for a renewal.
 	if isPrimary && !primaryUsesIntermediate(provider) {
@@ -1423,6 +1636,12 @@ func (l *connectSignRateLimiter) getCSRRateLimiterWithLimit(limit rate.Limit) *r
 // identified by the SPIFFE ID in the given CSR's SAN. It performs authorization
 // using the given acl.Authorizer.
 func (c *CAManager) AuthorizeAndSignCertificate(csr *x509.CertificateRequest, authz acl.Authorizer) (*structs.IssuedCert, error) {
+	return c.authorizeAndSignCertificate(csr, authz, "")
+}
+
+// authorizeAndSignCertificate is like AuthorizeAndSignCertificate but records
+// the name of the node that requested the certificate.
+func (c *CAManager) authorizeAndSignCertificate(csr *x509.CertificateRequest, authz acl.Authorizer, node string) (*structs.IssuedCert, error) {
 	// Note that only one spiffe id is allowed currently. If more than one is desired
 	// in future implmentations, then each ID should have authorization checks.
 	if len(csr.URIs) != 1 {
@@ -1497,10 +1716,14 @@ func (c *CAManager) AuthorizeAndSignCertificate(csr *x509.CertificateRequest, au
 		return nil, connect.InvalidCSRError("SPIFFE ID in CSR must be a service, mesh-gateway, or agent ID")
 	}
 
-	return c.SignCertificate(csr, spiffeID)
+	return c.signCertificate(csr, spiffeID, node)
 }
 
 func (c *CAManager) SignCertificate(csr *x509.CertificateRequest, spiffeID connect.CertURI) (*structs.IssuedCert, error) {
+	return c.signCertificate(csr, spiffeID, "")
+}
+
+func (c *CAManager) signCertificate(csr *x509.CertificateRequest, spiffeID connect.CertURI, node string) (*structs.IssuedCert, error) {
 	provider, caRoot := c.getCAProvider()
 	if provider == nil {
 		return nil, fmt.Errorf("CA is uninitialized and unable to sign certificates yet: provider is nil")
@@ -1602,6 +1825,21 @@ func (c *CAManager) SignCertificate(csr *x509.CertificateRequest, spiffeID conne
 
 	connect.HackSANExtensionForCSR(csr)
 
+	// Apply the leaf certificate overrides of the service.
+	leafCertTTL := commonCfg.LeafCertTTL
+	if isService {
+		leafCfg, err := serviceLeafCertConfig(state, serviceID)
+		if err != nil {
+			return nil, err
+		}
+		if leafCfg != nil {
+			if err := checkLeafCertKey(csr, leafCfg); err != nil {
+				return nil, err
+			}
+			leafCertTTL = commonCfg.LeafCertTTLFor(leafCfg.TTL)
+		}
+	}
+
 	// Check if the root expired before using it to sign.
 	// TODO: we store NotBefore and NotAfter on this struct, so we could avoid
 	// parsing the cert here.
@@ -1618,7 +1856,12 @@ func (c *CAManager) SignCertificate(csr *x509.CertificateRequest, spiffeID conne
 	}
 
 	// All seems to be in order, actually sign it.
-	pem, err := provider.Sign(csr)
+	var pem string
+	if ttlSigner, ok := provider.(ca.LeafTTLSigner); ok && leafCertTTL != commonCfg.LeafCertTTL {
+		pem, err = ttlSigner.SignWithTTL(csr, leafCertTTL)
+	} else {
+		pem, err = provider.Sign(csr)
+	}
 	if err == ca.ErrRateLimited {
 		return nil, ErrRateLimited
 	}
@@ -1631,11 +1874,6 @@ func (c *CAManager) SignCertificate(csr *x509.CertificateRequest, spiffeID conne
 		pem = pem + lib.EnsureTrailingNewline(p)
 	}
 
-	modIdx, err := c.delegate.ApplyCALeafRequest()
-	if err != nil {
-		return nil, err
-	}
-
 	cert, err := connect.ParseCert(pem)
 	if err != nil {
 		return nil, err
@@ -1647,11 +1885,10 @@ func (c *CAManager) SignCertificate(csr *x509.CertificateRequest, spiffeID conne
 		CertPEM:        pem,
 		ValidAfter:     cert.NotBefore,
 		ValidBefore:    cert.NotAfter,
+		Node:           node,
+		SigningKeyID:   connect.EncodeSigningKeyID(cert.AuthorityKeyId),
+		RootID:         caRoot.ID,
 		EnterpriseMeta: entMeta,
-		RaftIndex: structs.RaftIndex{
-			ModifyIndex: modIdx,
-			CreateIndex: modIdx,
-		},
 	}
 
 	switch {
@@ -1667,15 +1904,60 @@ func (c *CAManager) SignCertificate(csr *x509.CertificateRequest, spiffeID conne
 	case isAgent:
 		reply.Agent = agentID.Agent
 		reply.AgentURI = cert.URIs[0].String()
+		reply.Node = agentID.Agent
 	case isServer:
 		reply.ServerURI = cert.URIs[0].String()
 	default:
 		return nil, errors.New("not possible")
 	}
 
+	// Record the certificate without its PEM so that it can be revoked. The
+	// raft index of the record is used as the index of the certificate.
+	record := reply
+	record.CertPEM = ""
+	modIdx, err := c.delegate.ApplyCALeafRequest(&record)
+	if err != nil {
+		return nil, err
+	}
+	reply.RaftIndex = structs.RaftIndex{
+		ModifyIndex: modIdx,
+		CreateIndex: modIdx,
+	}
+
 	return &reply, nil
 }
 
+// serviceLeafCertConfig returns the leaf certificate overrides set in the
+// service-defaults config entry of the service, if any.
+func serviceLeafCertConfig(store *state.Store, id *connect.SpiffeIDService) (*structs.LeafCertConfig, error) {
+	_, entry, err := store.ConfigEntry(nil, structs.ServiceDefaults, id.Service, id.GetEnterpriseMeta())
+	if err != nil {
+		return nil, fmt.Errorf("error reading the service-defaults of %q: %w", id.Service, err)
+	}
+	svc, ok := entry.(*structs.ServiceConfigEntry)
+	if !ok || svc == nil {
+		return nil, nil
+	}
+	return svc.LeafCert, nil
+}
+
+// checkLeafCertKey verifies that the key of the CSR is of the type and length
+// required for the leaf certificates of the service.
+func checkLeafCertKey(csr *x509.CertificateRequest, leafCfg *structs.LeafCertConfig) error {
+	if leafCfg.PrivateKeyType == "" {
+		return nil
+	}
+	keyType, keyBits, err := connect.KeyInfoFromPublicKey(csr.PublicKey)
+	if err != nil {
+		return connect.InvalidCSRError("%s", err)
+	}
+	if keyType != leafCfg.PrivateKeyType || keyBits != leafCfg.PrivateKeyBits {
+		return connect.InvalidCSRError("CSR key must be %s %d bits for this service, got %s %d bits",
+			leafCfg.PrivateKeyType, leafCfg.PrivateKeyBits, keyType, keyBits)
+	}
+	return nil
+}
+
 func (c *CAManager) checkExpired(pem string) error {
 	cert, err := connect.ParseCert(pem)
 	if err != nil {
@@ -1692,12 +1974,17 @@ func primaryUsesIntermediate(provider ca.Provider) bool {
 	return ok
 }
 
+func usesOfflineRoot(provider ca.Provider) bool {
+	_, ok := provider.(ca.PrimaryUsesOfflineRoot)
+	return ok
+}
+
 func (c *CAManager) isIntermediateUsedToSignLeaf() bool {
 	if c.serverConf.Datacenter != c.serverConf.PrimaryDatacenter {
 		return true
 	}
 	provider, _ := c.getCAProvider()
-	return primaryUsesIntermediate(provider)
+	return primaryUsesIntermediate(provider) || usesOfflineRoot(provider)
 }
 
 func providerPrettyName(provider string) string {
@@ -1708,6 +1995,8 @@ func providerPrettyName(provider string) string {
 		return "Vault"
 	case "aws-pca":
 		return "Aws-Pca"
+	case "offline":
+		return "Offline"
 	case "provider-name":
 		return "Provider-Name"
 	default:
@@ -0,0 +1,413 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package consul
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/go-version"
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/agent/connect/ca"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+var (
+	// minCALeafCertRevocationVersion is the minimum version all the servers in
+	// a datacenter must run before the signed leaf certificates are recorded
+	// and can be revoked.
+	minCALeafCertRevocationVersion = version.Must(version.NewVersion("1.17.0"))
+
+	// caRevocationListValidity is how long a revocation list is valid for at
+	// least. It is re-issued after half of that time.
+	caRevocationListValidity = 24 * time.Hour
+
+	// caRevocationListCheckInterval is how often the leader checks if the
+	// revocation list must be re-issued and prunes the expired leaf
+	// certificates, in addition to when the active root changes.
+	caRevocationListCheckInterval = time.Hour
+
+	// caOCSPResponseValidity is how long an OCSP response is valid for.
+	caOCSPResponseValidity = time.Hour
+)
+
+// RevokeLeafCerts revokes the unexpired leaf certificates issued in this
+// datacenter that match the request, and updates the revocation list. It
+// returns the certificates that were revoked.
+func (c *CAManager) RevokeLeafCerts(args *structs.CARevokeRequest) ([]*structs.IssuedCert, error) {
+	if args.SerialNumber == "" && args.Service == "" && args.Node == "" {
+		return nil, fmt.Errorf("a serial number, service or node is required")
+	}
+	if err := c.delegate.ServersSupportLeafCertRevocation(); err != nil {
+		return nil, err
+	}
+
+	var serial string
+	if args.SerialNumber != "" {
+		sn, err := connect.ParseSerialNumber(args.SerialNumber)
+		if err != nil {
+			return nil, err
+		}
+		serial = connect.EncodeSerialNumber(sn)
+	}
+
+	c.revocationLock.Lock()
+	defer c.revocationLock.Unlock()
+
+	provider, root := c.getCAProvider()
+	if provider == nil || root == nil {
+		return nil, fmt.Errorf("CA is uninitialized: provider is nil")
+	}
+
+	_, certs, err := c.delegate.State().CALeafCerts(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.timeNow()
+	var (
+		found   bool
+		revoked []*structs.IssuedCert
+	)
+	for _, cert := range certs {
+		switch {
+		case serial != "" && cert.SerialNumber != serial:
+			continue
+		case args.Service != "" && (cert.Service != args.Service ||
+			cert.PartitionOrDefault() != args.PartitionOrDefault() ||
+			cert.NamespaceOrDefault() != args.NamespaceOrDefault()):
+			continue
+		case args.Node != "" && cert.Node != args.Node:
+			continue
+		}
+		found = true
+
+		if cert.IsRevoked() || !cert.ValidBefore.After(now) {
+			continue
+		}
+		r := *cert
+		r.RevokedAt = &now
+		r.RevocationReason = args.Reason
+		revoked = append(revoked, &r)
+	}
+	if serial != "" && !found {
+		return nil, fmt.Errorf("no certificate with serial number %s was issued in this datacenter", serial)
+	}
+	if len(revoked) == 0 {
+		return nil, nil
+	}
+
+	// Generate the revocation list with the certificates that are revoked now.
+	revokedBySerial := make(map[string]*structs.IssuedCert, len(revoked))
+	for _, r := range revoked {
+		revokedBySerial[r.SerialNumber] = r
+	}
+	updated := make([]*structs.IssuedCert, 0, len(certs))
+	for _, cert := range certs {
+		if r, ok := revokedBySerial[cert.SerialNumber]; ok {
+			cert = r
+		}
+		updated = append(updated, cert)
+	}
+	_, current, err := c.delegate.State().CARevocationLists(nil)
+	if err != nil {
+		return nil, err
+	}
+	crls, _, err := c.revocationLists(provider, root, updated, current, true)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &structs.CARequest{
+		Op:              structs.CAOpRevokeLeafCerts,
+		LeafCerts:       revoked,
+		RevocationLists: crls,
+	}
+	if _, err := c.delegate.ApplyCARequest(req); err != nil {
+		return nil, err
+	}
+
+	for _, r := range revoked {
+		c.logger.Info("revoked leaf certificate",
+			"serial_number", r.SerialNumber,
+			"service", r.Service,
+			"agent", r.Agent,
+			"node", r.Node,
+			"reason", r.RevocationReason,
+		)
+	}
+	return revoked, nil
+}
+
+// OCSPResponse returns the signed OCSP response for the given DER-encoded
+// OCSP request. Only the certificates signed by the active leaf signing
+// certificate can be checked.
+func (c *CAManager) OCSPResponse(der []byte) ([]byte, error) {
+	req, err := ocsp.ParseRequest(der)
+	if err != nil {
+		return ocsp.MalformedRequestErrorResponse, nil
+	}
+
+	provider, root := c.getCAProvider()
+	if provider == nil || root == nil {
+		return ocsp.TryLaterErrorResponse, nil
+	}
+	signer, ok := provider.(ca.RevocationSigner)
+	if !ok {
+		return ocsp.UnauthorizedErrorResponse, nil
+	}
+
+	_, cert, err := c.delegate.State().CALeafCert(nil, connect.EncodeSerialNumber(req.SerialNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.timeNow()
+	template := ocsp.Response{
+		SerialNumber: req.SerialNumber,
+		ThisUpdate:   now.Add(-ca.CertificateTimeDriftBuffer),
+		NextUpdate:   now.Add(caOCSPResponseValidity),
+	}
+	switch {
+	case cert == nil:
+		template.Status = ocsp.Unknown
+	case cert.SigningKeyID != root.SigningKeyID:
+		return ocsp.UnauthorizedErrorResponse, nil
+	case cert.IsRevoked():
+		template.Status = ocsp.Revoked
+		template.RevokedAt = *cert.RevokedAt
+		template.RevocationReason = ocsp.Unspecified
+	default:
+		template.Status = ocsp.Good
+	}
+
+	return signer.SignOCSPResponse(template)
+}
+
+// runRevocationList keeps the revocation lists up to date, issuing one for the
+// active signing certificate when it changes and re-issuing it before it
+// expires, and prunes the records of the expired leaf certificates.
+func (c *CAManager) runRevocationList(ctx context.Context) error {
+	retryLoopBackoff(ctx, func() error {
+		ws := memdb.NewWatchSet()
+		if _, _, err := c.delegate.State().CARoots(ws); err != nil {
+			return err
+		}
+
+		if err := c.updateRevocationList(); err != nil {
+			return err
+		}
+
+		watchCtx, cancel := context.WithTimeout(ctx, caRevocationListCheckInterval)
+		defer cancel()
+		ws.WatchCtx(watchCtx)
+		return nil
+	}, func(err error) {
+		c.logger.Error("error updating the CA revocation list",
+			"routine", caRevocationListRoutineName,
+			"error", err,
+		)
+	})
+	return nil
+}
+
+// updateRevocationList prunes the expired leaf certificates and updates the
+// revocation lists if needed. Nothing is done until all the servers support
+// the revocation of leaf certificates.
+func (c *CAManager) updateRevocationList() error {
+	if err := c.delegate.ServersSupportLeafCertRevocation(); err != nil {
+		c.logger.Debug("not updating the revocation lists", "error", err)
+		return nil
+	}
+
+	c.revocationLock.Lock()
+	defer c.revocationLock.Unlock()
+
+	provider, root := c.getCAProvider()
+	if provider == nil || root == nil {
+		return nil
+	}
+
+	state := c.delegate.State()
+	_, activeRoot, err := state.CARootActive(nil)
+	if err != nil {
+		return err
+	}
+	if activeRoot == nil {
+		return nil
+	}
+	if activeRoot.SigningKeyID != root.SigningKeyID {
+		return fmt.Errorf("the CA provider has not been updated with the active signing certificate yet")
+	}
+
+	_, certs, err := state.CALeafCerts(nil)
+	if err != nil {
+		return err
+	}
+
+	now := c.timeNow()
+	var expired, valid []*structs.IssuedCert
+	for _, cert := range certs {
+		if cert.ValidBefore.After(now) {
+			valid = append(valid, cert)
+		} else {
+			expired = append(expired, &structs.IssuedCert{SerialNumber: cert.SerialNumber})
+		}
+	}
+	if len(expired) > 0 {
+		req := &structs.CARequest{
+			Op:        structs.CAOpDeleteLeafCerts,
+			LeafCerts: expired,
+		}
+		if _, err := c.delegate.ApplyCARequest(req); err != nil {
+			return fmt.Errorf("error pruning expired leaf certificates: %w", err)
+		}
+		c.logger.Debug("pruned expired leaf certificates", "count", len(expired))
+	}
+
+	_, current, err := state.CARevocationLists(nil)
+	if err != nil {
+		return err
+	}
+	crls, changed, err := c.revocationLists(provider, root, valid, current, false)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+
+	req := &structs.CARequest{
+		Op:              structs.CAOpSetRevocationList,
+		RevocationLists: crls,
+	}
+	if _, err := c.delegate.ApplyCARequest(req); err != nil {
+		return fmt.Errorf("error setting the revocation lists: %w", err)
+	}
+	return nil
+}
+
+// revocationLists returns the revocation lists of the given leaf certificates
+// issued in this datacenter: one for the active signing certificate, and one
+// for each previous signing certificate of unexpired leaf certificates. Only
+// the active signing certificate can issue a new revocation list, so the
+// current ones of the previous signing certificates are kept until they are
+// about to expire, and are then replaced by a revocation list without PEM.
+//
+// The revocation list of the active signing certificate is re-issued if
+// reissue is true, if it is about to be stale, or if it expires before some
+// of the certificates it covers. It also reports whether the revocation lists
+// differ from the current ones.
+func (c *CAManager) revocationLists(provider ca.Provider, root *structs.CARoot, certs []*structs.IssuedCert, current []*structs.CARevocationList, reissue bool) ([]*structs.CARevocationList, bool, error) {
+	now := c.timeNow()
+
+	currentByKeyID := make(map[string]*structs.CARevocationList, len(current))
+	for _, crl := range current {
+		currentByKeyID[crl.SigningKeyID] = crl
+	}
+
+	// Find the latest expiration time of the unexpired certificates of each
+	// signing certificate.
+	validBefore := make(map[string]time.Time)
+	for _, cert := range certs {
+		if cert.ValidBefore.After(now) && cert.ValidBefore.After(validBefore[cert.SigningKeyID]) {
+			validBefore[cert.SigningKeyID] = cert.ValidBefore
+		}
+	}
+
+	var crls []*structs.CARevocationList
+	signer, canSign := provider.(ca.RevocationSigner)
+	if canSign {
+		crl := currentByKeyID[root.SigningKeyID]
+		if reissue || crl == nil || crl.PEM == "" ||
+			!now.Before(crl.ThisUpdate.Add(caRevocationListValidity/2)) ||
+			crl.NextUpdate.Before(validBefore[root.SigningKeyID]) {
+			var err error
+			crl, err = c.generateRevocationList(signer, root, certs)
+			if err != nil {
+				return nil, false, err
+			}
+		}
+		crls = append(crls, crl)
+		delete(validBefore, root.SigningKeyID)
+	}
+
+	keyIDs := make([]string, 0, len(validBefore))
+	for keyID := range validBefore {
+		keyIDs = append(keyIDs, keyID)
+	}
+	sort.Strings(keyIDs)
+	for _, keyID := range keyIDs {
+		crl := currentByKeyID[keyID]
+		if crl == nil || crl.PEM == "" || !now.Add(caRevocationListCheckInterval).Before(crl.NextUpdate) {
+			crl = &structs.CARevocationList{SigningKeyID: keyID}
+		}
+		crls = append(crls, crl)
+	}
+
+	changed := len(crls) != len(current)
+	for _, crl := range crls {
+		if prev, ok := currentByKeyID[crl.SigningKeyID]; !ok || prev.PEM != crl.PEM {
+			changed = true
+		}
+	}
+	return crls, changed, nil
+}
+
+// generateRevocationList returns a new revocation list, signed by the leaf
+// signing certificate of the given root, with the revoked certificates among
+// certs that were signed by it and did not expire. The revocation list is
+// valid at least until the expiration of the certificates it covers.
+func (c *CAManager) generateRevocationList(signer ca.RevocationSigner, root *structs.CARoot, certs []*structs.IssuedCert) (*structs.CARevocationList, error) {
+	now := c.timeNow()
+	nextUpdate := now.Add(caRevocationListValidity)
+	var entries []pkix.RevokedCertificate
+	for _, cert := range certs {
+		if cert.SigningKeyID != root.SigningKeyID || !cert.ValidBefore.After(now) {
+			continue
+		}
+		if cert.ValidBefore.After(nextUpdate) {
+			nextUpdate = cert.ValidBefore
+		}
+		if !cert.IsRevoked() {
+			continue
+		}
+		sn, err := connect.ParseSerialNumber(cert.SerialNumber)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, pkix.RevokedCertificate{
+			SerialNumber:   sn,
+			RevocationTime: *cert.RevokedAt,
+		})
+	}
+
+	thisUpdate := now.Add(-ca.CertificateTimeDriftBuffer)
+	template := &x509.RevocationList{
+		Number:              big.NewInt(now.UnixNano()),
+		ThisUpdate:          thisUpdate,
+		NextUpdate:          nextUpdate,
+		RevokedCertificates: entries,
+	}
+	pem, err := signer.SignRevocationList(template)
+	if err != nil {
+		return nil, err
+	}
+
+	return &structs.CARevocationList{
+		PEM:          pem,
+		SigningKeyID: root.SigningKeyID,
+		Revoked:      len(entries),
+		ThisUpdate:   thisUpdate,
+		NextUpdate:   template.NextUpdate,
+	}, nil
+}
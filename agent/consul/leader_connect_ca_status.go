@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package consul
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/agent/connect/ca"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// Status reports the expiry of the trusted roots and of their intermediate,
+// the state of a pending rotation and the number of outstanding leaf
+// certificates signed by each root.
+func (c *CAManager) Status() (*structs.CAStatus, error) {
+	store := c.delegate.State()
+	_, roots, config, err := store.CARootsAndConfig(nil)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil || config.ClusterID == "" {
+		return nil, fmt.Errorf("CA has not finished initializing")
+	}
+
+	c.stateLock.Lock()
+	state := c.state
+	c.stateLock.Unlock()
+
+	status := &structs.CAStatus{
+		Provider:    config.Provider,
+		State:       string(state),
+		TrustDomain: connect.SpiffeIDSigningForCluster(config.ClusterID).Host(),
+		Roots:       make([]*structs.CARootStatus, 0, len(roots)),
+	}
+
+	if provider, _ := c.getCAProvider(); provider != nil {
+		if offline, ok := provider.(ca.PrimaryUsesOfflineRoot); ok {
+			status.IntermediatePending, err = offline.IntermediatePending()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rootStatuses := make(map[string]*structs.CARootStatus, len(roots))
+	rootsBySigningKeyID := make(map[string]*structs.CARootStatus, len(roots))
+	for _, root := range roots {
+		rootStatus := &structs.CARootStatus{
+			ID:       root.ID,
+			Name:     root.Name,
+			Active:   root.Active,
+			NotAfter: root.NotAfter,
+		}
+		if n := len(root.IntermediateCerts); n > 0 {
+			cert, err := connect.ParseCert(root.IntermediateCerts[n-1])
+			if err != nil {
+				return nil, fmt.Errorf("error parsing the intermediate of root %s: %w", root.ID, err)
+			}
+			rootStatus.IntermediateNotAfter = &cert.NotAfter
+		}
+		if !root.RotatedOutAt.IsZero() {
+			rotatedOutAt := root.RotatedOutAt
+			rootStatus.RotatedOutAt = &rotatedOutAt
+		}
+
+		if root.Active {
+			status.ActiveRootID = root.ID
+		} else {
+			status.RotationInProgress = true
+		}
+		status.Roots = append(status.Roots, rootStatus)
+		rootStatuses[root.ID] = rootStatus
+		rootsBySigningKeyID[root.SigningKeyID] = rootStatus
+	}
+
+	// The leaves signed before their root was recorded with them are matched
+	// on the key that signed them instead.
+	_, certs, err := store.CALeafCerts(nil)
+	if err != nil {
+		return nil, err
+	}
+	now := c.timeNow()
+	for _, cert := range certs {
+		if cert.IsRevoked() || !cert.ValidBefore.After(now) {
+			continue
+		}
+		rootStatus, ok := rootStatuses[cert.RootID]
+		if !ok {
+			rootStatus, ok = rootsBySigningKeyID[cert.SigningKeyID]
+		}
+		if ok {
+			rootStatus.LeafCerts++
+		}
+	}
+
+	sort.SliceStable(status.Roots, func(i, j int) bool {
+		return status.Roots[i].Active && !status.Roots[j].Active
+	})
+	return status, nil
+}
@@ -24,6 +24,7 @@ import (
 	vaultapi "github.com/hashicorp/vault/api"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
 	"google.golang.org/grpc"
 
 	"github.com/hashicorp/consul/acl"
@@ -202,7 +203,11 @@ func (m *mockCAServerDelegate) ServersSupportMultiDCConnectCA() error {
 	return nil
 }
 
-func (m *mockCAServerDelegate) ApplyCALeafRequest() (uint64, error) {
+func (m *mockCAServerDelegate) ServersSupportLeafCertRevocation() error {
+	return nil
+}
+
+func (m *mockCAServerDelegate) ApplyCALeafRequest(_ *structs.IssuedCert) (uint64, error) {
 	return 3, nil
 }
 
@@ -1447,3 +1452,217 @@ func TestCAManager_AuthorizeAndSignCertificate(t *testing.T) {
 		})
 	}
 }
+
+// signOfflineIntermediate signs an intermediate CSR with the key of the given
+// root like an operator would with the offline root.
+func signOfflineIntermediate(t *testing.T, root *structs.CARoot, csrPEM string, ttl time.Duration) string {
+	t.Helper()
+
+	csr, err := connect.ParseCSR(csrPEM)
+	require.NoError(t, err)
+	rootCert, err := connect.ParseCert(root.RootCert)
+	require.NoError(t, err)
+	signer, err := connect.ParseSigner(root.SigningKey)
+	require.NoError(t, err)
+	keyID, err := connect.KeyId(csr.PublicKey)
+	require.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               csr.Subject,
+		URIs:                  csr.URIs,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(ttl),
+		AuthorityKeyId:        rootCert.SubjectKeyId,
+		SubjectKeyId:          keyID,
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, &template, rootCert, csr.PublicKey, signer)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: raw}))
+	return buf.String()
+}
+
+func TestCAManager_OfflineProvider(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	offlineRoot := connect.TestCA(t, nil)
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.CAConfig = &structs.CAConfiguration{
+			ClusterID: connect.TestClusterID,
+			Provider:  structs.OfflineCAProvider,
+			Config: map[string]interface{}{
+				"RootCert":            offlineRoot.RootCert,
+				"LeafCertTTL":         "72h",
+				"IntermediateCertTTL": "288h",
+			},
+		}
+	})
+	testrpc.WaitForTestAgent(t, s1.RPC, "dc1")
+	codec := rpcClient(t, s1)
+
+	getRoots := func(t *testing.T) structs.IndexedCARoots {
+		t.Helper()
+		var roots structs.IndexedCARoots
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.Roots", &structs.DCSpecificRequest{}, &roots))
+		require.Len(t, roots.Roots, 1)
+		return roots
+	}
+	roots := getRoots(t)
+	require.Equal(t, offlineRoot.ID, roots.Roots[0].ID)
+	require.Empty(t, roots.Roots[0].IntermediateCerts)
+
+	spiffeService := &connect.SpiffeIDService{
+		Host:       roots.TrustDomain,
+		Namespace:  "default",
+		Datacenter: "dc1",
+		Service:    "web",
+	}
+	leafCSR, _ := connect.TestCSR(t, spiffeService)
+	sign := func() (structs.IssuedCert, error) {
+		var cert structs.IssuedCert
+		err := msgpackrpc.CallWithCodec(codec, "ConnectCA.Sign", &structs.CASignRequest{CSR: leafCSR}, &cert)
+		return cert, err
+	}
+
+	// Leaf certificates can't be signed until an intermediate is set.
+	_, err := sign()
+	require.ErrorContains(t, err, "no intermediate certificate has been set")
+
+	csrReq := structs.CAIntermediateRequest{Datacenter: "dc1"}
+	var csrPEM string
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.IntermediateCSR", &csrReq, &csrPEM))
+	require.NotEmpty(t, csrPEM)
+
+	setIntermediate := func(certPEM string) error {
+		args := structs.CAIntermediateRequest{Datacenter: "dc1", Certificate: certPEM}
+		var reply interface{}
+		return msgpackrpc.CallWithCodec(codec, "ConnectCA.SetIntermediate", &args, &reply)
+	}
+
+	t.Run("missing certificate", func(t *testing.T) {
+		require.ErrorContains(t, setIntermediate(""), "Missing intermediate certificate")
+	})
+
+	t.Run("signed by another root", func(t *testing.T) {
+		other := signOfflineIntermediate(t, connect.TestCA(t, nil), csrPEM, 365*24*time.Hour)
+		require.ErrorContains(t, setIntermediate(other), "does not chain to the active root")
+	})
+
+	t.Run("expires before leaf certs", func(t *testing.T) {
+		short := signOfflineIntermediate(t, offlineRoot, csrPEM, time.Hour)
+		require.ErrorContains(t, setIntermediate(short), "before leaf certificates signed now would")
+	})
+
+	intermediatePEM := signOfflineIntermediate(t, offlineRoot, csrPEM, 365*24*time.Hour)
+	require.NoError(t, setIntermediate(intermediatePEM))
+
+	roots = getRoots(t)
+	require.Equal(t, []string{intermediatePEM}, roots.Roots[0].IntermediateCerts)
+	intermediate, err := connect.ParseCert(intermediatePEM)
+	require.NoError(t, err)
+	require.Equal(t, connect.EncodeSigningKeyID(intermediate.SubjectKeyId), roots.Roots[0].SigningKeyID)
+
+	cert, err := sign()
+	require.NoError(t, err)
+	verifyLeafCert(t, roots.Roots[0], cert.CertPEM)
+
+	// Renewal prepares a CSR for a new key while the current intermediate
+	// keeps signing, and the previous intermediate is kept once the next one
+	// is set.
+	require.NoError(t, s1.caManager.renewIntermediateNow(context.Background()))
+	var nextCSR string
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.IntermediateCSR", &csrReq, &nextCSR))
+	require.NotEqual(t, csrPEM, nextCSR)
+	_, err = sign()
+	require.NoError(t, err)
+
+	nextPEM := signOfflineIntermediate(t, offlineRoot, nextCSR, 365*24*time.Hour)
+	require.NoError(t, setIntermediate(nextPEM))
+
+	roots = getRoots(t)
+	require.Equal(t, []string{intermediatePEM, nextPEM}, roots.Roots[0].IntermediateCerts)
+	cert, err = sign()
+	require.NoError(t, err)
+	verifyLeafCert(t, roots.Roots[0], cert.CertPEM)
+}
+
+// mockRevocationSigner is a provider that signs revocation lists with a
+// placeholder PEM that identifies the signing certificate.
+type mockRevocationSigner struct {
+	mockCAProvider
+	keyID string
+}
+
+func (m *mockRevocationSigner) SignRevocationList(template *x509.RevocationList) (string, error) {
+	return fmt.Sprintf("%s-%d-%d", m.keyID, template.Number, len(template.RevokedCertificates)), nil
+}
+
+func (m *mockRevocationSigner) SignOCSPResponse(ocsp.Response) ([]byte, error) { return nil, nil }
+
+func TestCAManager_RevocationLists(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	manager := &CAManager{timeNow: func() time.Time { return now }}
+
+	revokedAt := now.Add(-time.Minute)
+	certs := []*structs.IssuedCert{
+		{SerialNumber: "01", SigningKeyID: "old", ValidBefore: now.Add(72 * time.Hour)},
+		{SerialNumber: "02", SigningKeyID: "old", ValidBefore: now.Add(time.Hour), RevokedAt: &revokedAt},
+		{SerialNumber: "03", SigningKeyID: "new", ValidBefore: now.Add(time.Hour)},
+		{SerialNumber: "04", SigningKeyID: "expired", ValidBefore: now.Add(-time.Hour)},
+	}
+
+	// The active signing certificate gets a revocation list, even without
+	// revoked certificates, which covers its unexpired certificates.
+	oldProvider := &mockRevocationSigner{keyID: "old"}
+	crls, changed, err := manager.revocationLists(oldProvider, &structs.CARoot{SigningKeyID: "old"}, certs, nil, false)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Len(t, crls, 2)
+	require.Equal(t, "old", crls[0].SigningKeyID)
+	require.Equal(t, 1, crls[0].Revoked)
+	require.Equal(t, now.Add(72*time.Hour), crls[0].NextUpdate)
+	require.Equal(t, &structs.CARevocationList{SigningKeyID: "new"}, crls[1])
+	oldCRL := crls[0]
+
+	// The current revocation list is kept until it is about to be stale.
+	crls, changed, err = manager.revocationLists(oldProvider, &structs.CARoot{SigningKeyID: "old"}, certs, crls, false)
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.Equal(t, oldCRL, crls[0])
+
+	// After the rotation, the new signing certificate gets a revocation list
+	// and the one of the previous signing certificate is kept, since it can't
+	// be re-issued.
+	newProvider := &mockRevocationSigner{keyID: "new"}
+	crls, changed, err = manager.revocationLists(newProvider, &structs.CARoot{SigningKeyID: "new"}, certs, crls, false)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Len(t, crls, 2)
+	require.Equal(t, "new", crls[0].SigningKeyID)
+	require.Equal(t, 0, crls[0].Revoked)
+	require.Equal(t, oldCRL, crls[1])
+
+	// The revocation list of the previous signing certificate is dropped when
+	// it is about to expire.
+	now = oldCRL.NextUpdate.Add(-time.Minute)
+	crls, changed, err = manager.revocationLists(newProvider, &structs.CARoot{SigningKeyID: "new"}, certs[:1], crls, false)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Len(t, crls, 2)
+	require.Equal(t, &structs.CARevocationList{SigningKeyID: "old"}, crls[1])
+
+	// The certificates of providers that can't sign revocation lists can't be
+	// checked.
+	unchecked := &structs.IssuedCert{SerialNumber: "05", SigningKeyID: "vault", ValidBefore: now.Add(time.Hour)}
+	crls, _, err = manager.revocationLists(&mockCAProvider{}, &structs.CARoot{SigningKeyID: "vault"}, []*structs.IssuedCert{unchecked}, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, []*structs.CARevocationList{{SigningKeyID: "vault"}}, crls)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/raft"
 	"github.com/hashicorp/serf/serf"
 
+	"github.com/hashicorp/consul/agent/consul/fsm"
 	"github.com/hashicorp/consul/agent/metadata"
 	"github.com/hashicorp/consul/agent/structs"
 )
@@ -79,6 +80,36 @@ func (op *Operator) RaftGetConfiguration(args *structs.DCSpecificRequest, reply
 	return nil
 }
 
+// RaftGetLog is used to list and decode recent entries from this server's Raft
+// log. The request is forwarded to the leader unless it allows stale reads, in
+// which case the receiving server's log is read.
+func (op *Operator) RaftGetLog(args *structs.RaftLogRequest, reply *structs.RaftLogResponse) error {
+	if done, err := op.srv.ForwardRPC("Operator.RaftGetLog", args, reply); done {
+		return err
+	}
+
+	// The log holds data from every part of the catalog, KV store and ACL
+	// system, so even redacted it requires operator write access.
+	authz, err := op.srv.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+	if err := authz.ToAllowAuthorizer().OperatorWriteAllowed(nil); err != nil {
+		return err
+	}
+
+	store := op.srv.raftLogStore()
+	if store == nil {
+		return fmt.Errorf("raft log store is not available")
+	}
+	resp, err := fsm.ReadLogs(store, args.RaftLogFilter)
+	if err != nil {
+		return err
+	}
+	*reply = *resp
+	return nil
+}
+
 // RaftRemovePeerByAddress is used to kick a stale peer (one that it in the Raft
 // quorum but no longer known to Serf or the catalog) by address in the form of
 // "IP:port". The reply argument is not used, but it required to fulfill the RPC
@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/consul-net-rpc/net/rpc"
 	"github.com/hashicorp/go-hclog"
 
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/consul/stream"
 	"github.com/hashicorp/consul/agent/grpc-external/limiter"
 	"github.com/hashicorp/consul/agent/hcp"
@@ -41,6 +42,10 @@ type Deps struct {
 	// NewRequestRecorderFunc provides a middleware.RequestRecorder for the server to use; it cannot be nil
 	NewRequestRecorderFunc func(logger hclog.Logger, isLeader func() bool, localDC string) *middleware.RequestRecorder
 
+	// Auditor, if not nil, records the RPCs handled by the server when RPC
+	// auditing is enabled.
+	Auditor *audit.Auditor
+
 	// HCP contains the dependencies required when integrating with the HashiCorp Cloud Platform
 	HCP hcp.Deps
 
@@ -39,6 +39,7 @@ import (
 	"github.com/hashicorp/consul-net-rpc/net/rpc"
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/acl/resolver"
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/blockingquery"
 	"github.com/hashicorp/consul/agent/consul/authmethod"
 	"github.com/hashicorp/consul/agent/consul/authmethod/ssoauth"
@@ -141,27 +142,29 @@ const (
 )
 
 const (
-	aclPolicyReplicationRoutineName       = "ACL policy replication"
-	aclRoleReplicationRoutineName         = "ACL role replication"
-	aclTokenReplicationRoutineName        = "ACL token replication"
-	aclTokenReapingRoutineName            = "acl token reaping"
-	caRootPruningRoutineName              = "CA root pruning"
-	caRootMetricRoutineName               = "CA root expiration metric"
-	caSigningMetricRoutineName            = "CA signing expiration metric"
-	configEntryControllersRoutineName     = "config entry controllers"
-	configReplicationRoutineName          = "config entry replication"
-	federationStateReplicationRoutineName = "federation state replication"
-	federationStateAntiEntropyRoutineName = "federation state anti-entropy"
-	federationStatePruningRoutineName     = "federation state pruning"
-	intentionMigrationRoutineName         = "intention config entry migration"
-	secondaryCARootWatchRoutineName       = "secondary CA roots watch"
-	intermediateCertRenewWatchRoutineName = "intermediate cert renew watch"
-	backgroundCAInitializationRoutineName = "CA initialization"
-	virtualIPCheckRoutineName             = "virtual IP version check"
-	peeringStreamsRoutineName             = "streaming peering resources"
-	peeringDeletionRoutineName            = "peering deferred deletion"
-	peeringStreamsMetricsRoutineName      = "metrics for streaming peering resources"
-	raftLogVerifierRoutineName            = "raft log verifier"
+	aclPolicyReplicationRoutineName          = "ACL policy replication"
+	aclRoleReplicationRoutineName            = "ACL role replication"
+	aclTemplatedPolicyReplicationRoutineName = "ACL templated policy replication"
+	aclTokenReplicationRoutineName           = "ACL token replication"
+	aclTokenReapingRoutineName               = "acl token reaping"
+	caRootPruningRoutineName                 = "CA root pruning"
+	caRevocationListRoutineName              = "CA revocation list"
+	caRootMetricRoutineName                  = "CA root expiration metric"
+	caSigningMetricRoutineName               = "CA signing expiration metric"
+	configEntryControllersRoutineName        = "config entry controllers"
+	configReplicationRoutineName             = "config entry replication"
+	federationStateReplicationRoutineName    = "federation state replication"
+	federationStateAntiEntropyRoutineName    = "federation state anti-entropy"
+	federationStatePruningRoutineName        = "federation state pruning"
+	intentionMigrationRoutineName            = "intention config entry migration"
+	secondaryCARootWatchRoutineName          = "secondary CA roots watch"
+	intermediateCertRenewWatchRoutineName    = "intermediate cert renew watch"
+	backgroundCAInitializationRoutineName    = "CA initialization"
+	virtualIPCheckRoutineName                = "virtual IP version check"
+	peeringStreamsRoutineName                = "streaming peering resources"
+	peeringDeletionRoutineName               = "peering deferred deletion"
+	peeringStreamsMetricsRoutineName         = "metrics for streaming peering resources"
+	raftLogVerifierRoutineName               = "raft log verifier"
 )
 
 var (
@@ -201,6 +204,10 @@ type Server struct {
 
 	aclAuthMethodValidators authmethod.Cache
 
+	// auditor, if not nil, records audit events such as the issuance of
+	// break-glass tokens.
+	auditor *audit.Auditor
+
 	// autopilot is the Autopilot instance for this server.
 	autopilot *autopilot.Autopilot
 
@@ -604,8 +611,17 @@ func NewServer(config *Config, flat Deps, externalGRPCServer *grpc.Server,
 		),
 	}
 
+	s.auditor = flat.Auditor
+
+	var rpcInterceptor rpc.ServerServiceCallInterceptor
 	if flat.GetNetRPCInterceptorFunc != nil {
-		rpcServerOpts = append(rpcServerOpts, rpc.WithServerServiceCallInterceptor(flat.GetNetRPCInterceptorFunc(recorder)))
+		rpcInterceptor = flat.GetNetRPCInterceptorFunc(recorder)
+	}
+	if flat.Auditor != nil && flat.Auditor.RPCEnabled() {
+		rpcInterceptor = s.auditRPCInterceptor(flat.Auditor, rpcInterceptor)
+	}
+	if rpcInterceptor != nil {
+		rpcServerOpts = append(rpcServerOpts, rpc.WithServerServiceCallInterceptor(rpcInterceptor))
 	}
 
 	s.rpcServer = rpc.NewServerWithOpts(rpcServerOpts...)
@@ -682,6 +698,8 @@ func NewServer(config *Config, flat Deps, externalGRPCServer *grpc.Server,
 		Logger:      logger,
 		ACLConfig:   s.aclConfig,
 		Tokens:      flat.Tokens,
+
+		TrackTokenUsage: true,
 	}
 	// Initialize the ACL resolver.
 	if s.ACLResolver, err = NewACLResolver(&aclConfig); err != nil {
@@ -879,6 +897,11 @@ func NewServer(config *Config, flat Deps, externalGRPCServer *grpc.Server,
 	// since it can fire events when leadership is obtained.
 	go s.monitorLeadership()
 
+	// Start recording the usage of ACL tokens.
+	if s.config.ACLsEnabled {
+		go s.runACLTokenUsageFlusher(&lib.StopChannelContext{StopCh: s.shutdownCh})
+	}
+
 	// Start listening for RPC requests.
 	go func() {
 		if err := s.grpcHandler.Run(); err != nil {
@@ -1844,6 +1867,18 @@ func (s *Server) RemoveFailedNodeWAN(wanNode string, prune bool, entMeta *acl.En
 	return s.removeFailedNode(removeFn, "", wanNode, entMeta)
 }
 
+// raftLogStore returns the store holding this server's Raft log, or nil if
+// Raft has not been set up yet.
+func (s *Server) raftLogStore() raft.LogStore {
+	if s.raftStore != nil {
+		return s.raftStore
+	}
+	if s.raftInmem != nil {
+		return s.raftInmem
+	}
+	return nil
+}
+
 // IsLeader checks if this server is the cluster leader
 func (s *Server) IsLeader() bool {
 	return s.raft.State() == raft.Leader
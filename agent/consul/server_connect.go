@@ -69,9 +69,65 @@ func (s *Server) getCARoots(ws memdb.WatchSet, state *state.Store) (*structs.Ind
 		}
 	}
 
+	// Include the revocation lists so that they reach the proxies along with
+	// the roots they use to verify the leaf certificates.
+	crlIndex, crls, err := state.CARevocationLists(ws)
+	if err != nil {
+		return nil, err
+	}
+	if crlIndex > indexedRoots.Index {
+		indexedRoots.Index = crlIndex
+	}
+	indexedRoots.RevocationList = concatRevocationLists(crls)
+	if indexedRoots.RevocationList == "" {
+		return indexedRoots, nil
+	}
+
+	// The proxies must not check the certificates signed by the other
+	// datacenters against the revocation lists of this one.
+	if s.config.Datacenter != s.config.PrimaryDatacenter {
+		indexedRoots.WANFederated = true
+		return indexedRoots, nil
+	}
+	fedIndex, fedStates, err := state.FederationStateList(ws)
+	if err != nil {
+		return nil, err
+	}
+	if fedIndex > indexedRoots.Index {
+		indexedRoots.Index = fedIndex
+	}
+	for _, fedState := range fedStates {
+		if fedState.Datacenter != s.config.Datacenter {
+			indexedRoots.WANFederated = true
+		}
+	}
+
 	return indexedRoots, nil
 }
 
+// concatRevocationLists returns the PEM-encoded revocation lists, or an empty
+// string if none of them lists revoked certificates or if one of them has no
+// PEM. Since the proxies only accept the leaf certificates of a signing
+// certificate that has a revocation list once some revocation list is set,
+// they are only distributed together.
+func concatRevocationLists(crls []*structs.CARevocationList) string {
+	var (
+		pems    string
+		revoked bool
+	)
+	for _, crl := range crls {
+		if crl.PEM == "" {
+			return ""
+		}
+		pems += lib.EnsureTrailingNewline(crl.PEM)
+		revoked = revoked || crl.Revoked > 0
+	}
+	if !revoked {
+		return ""
+	}
+	return pems
+}
+
 func (s *Server) getTrustDomain(config *structs.CAConfiguration) (string, error) {
 	if config == nil || config.ClusterID == "" {
 		return "", fmt.Errorf("CA has not finished initializing")
@@ -426,6 +426,7 @@ type ACLTokenSetOptions struct {
 	AllowMissingPolicyAndRoleIDs bool
 	ProhibitUnprivileged         bool
 	FromReplication              bool
+	AllowSecretRotation          bool
 }
 
 func (s *Store) ACLTokenBatchSet(idx uint64, tokens structs.ACLTokens, opts ACLTokenSetOptions) error {
@@ -538,8 +539,23 @@ func aclTokenSetTxn(tx WriteTxn, idx uint64, token *structs.ACLToken, opts ACLTo
 			return fmt.Errorf("The ACL Token AccessorID field is immutable")
 		}
 
+		// The SecretID can only be changed by rotating the token, which may
+		// also arrive here through replication.
 		if token.SecretID != original.SecretID {
-			return fmt.Errorf("The ACL Token SecretID field is immutable")
+			if !opts.AllowSecretRotation && !opts.FromReplication {
+				return fmt.Errorf("The ACL Token SecretID field is immutable")
+			}
+			// The SecretID is the table's primary key, so the existing entry
+			// has to be removed rather than replaced.
+			if err := tx.Delete(tableACLTokens, original); err != nil {
+				return fmt.Errorf("failed deleting rotated acl token: %v", err)
+			}
+		}
+
+		// Usage is tracked separately from the rest of the token, don't let
+		// an update or a replicated copy of the token roll it back.
+		if original.LastUsedAt != nil && (token.LastUsedAt == nil || token.LastUsedAt.Before(*original.LastUsedAt)) {
+			token.LastUsedAt = original.LastUsedAt
 		}
 
 		token.CreateIndex = original.CreateIndex
@@ -555,9 +571,70 @@ func aclTokenSetTxn(tx WriteTxn, idx uint64, token *structs.ACLToken, opts ACLTo
 	return aclTokenInsert(tx, token)
 }
 
+// ACLTokenUsageSet records when tokens were last used. Unknown tokens are
+// ignored and LastUsedAt never moves backwards. The ModifyIndex of the tokens
+// is left alone so that usage updates don't interfere with CAS updates or
+// wake up watchers that only care about the token's contents.
+func (s *Store) ACLTokenUsageSet(idx uint64, usage []structs.ACLTokenUsage) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	for _, u := range usage {
+		if err := aclTokenUsageSetTxn(tx, u); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func aclTokenUsageSetTxn(tx WriteTxn, usage structs.ACLTokenUsage) error {
+	_, existing, err := aclTokenGetFromIndex(tx, usage.AccessorID, indexAccessor, nil)
+	if err != nil {
+		return fmt.Errorf("failed token lookup: %s", err)
+	}
+	if existing == nil {
+		return nil
+	}
+
+	original := existing.(*structs.ACLToken)
+	if original.LastUsedAt != nil && !usage.LastUsedAt.After(*original.LastUsedAt) {
+		return nil
+	}
+
+	lastUsedAt := usage.LastUsedAt
+	token := original.Clone()
+	token.LastUsedAt = &lastUsedAt
+	if err := tx.Insert(tableACLTokens, token); err != nil {
+		return fmt.Errorf("failed inserting acl token: %v", err)
+	}
+	return nil
+}
+
 // ACLTokenGetBySecret is used to look up an existing ACL token by its SecretID.
+// The previous SecretID of a rotated token also matches until its grace period
+// ends, in which case the returned token holds the new SecretID.
 func (s *Store) ACLTokenGetBySecret(ws memdb.WatchSet, secret string, entMeta *acl.EnterpriseMeta) (uint64, *structs.ACLToken, error) {
-	return s.aclTokenGet(ws, secret, indexID, entMeta)
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	token, err := aclTokenGetTxn(tx, ws, secret, indexID, entMeta)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if token == nil {
+		token, err = aclTokenGetTxn(tx, ws, secret, indexPreviousSecret, entMeta)
+		if err != nil {
+			return 0, nil, err
+		}
+		if token != nil && !token.HasPreviousSecret(time.Now()) {
+			token = nil
+		}
+	}
+
+	idx := aclTokenMaxIndex(tx, token, entMeta)
+	return idx, token, nil
 }
 
 // ACLTokenGetByAccessor is used to look up an existing ACL token by its AccessorID.
@@ -787,6 +864,37 @@ func (s *Store) ACLTokenListExpired(local bool, asOf time.Time, max int) (struct
 	return tokens, iter.WatchCh(), nil
 }
 
+// ACLTokenListPreviousSecretExpired lists rotated tokens whose previous
+// SecretID expired as of the provided time. The returned set will be no larger
+// than the max value provided.
+func (s *Store) ACLTokenListPreviousSecretExpired(local bool, asOf time.Time, max int) (structs.ACLTokens, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	iter, err := tx.Get(tableACLTokens, indexPreviousSecretExpires)
+	if err != nil {
+		return nil, fmt.Errorf("failed acl token listing: %v", err)
+	}
+
+	var tokens structs.ACLTokens
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		token := raw.(*structs.ACLToken)
+		if !token.PreviousSecretExpirationTime.Before(asOf) {
+			break
+		}
+		if token.Local != local {
+			continue
+		}
+
+		tokens = append(tokens, token)
+		if len(tokens) >= max {
+			break
+		}
+	}
+
+	return tokens, nil
+}
+
 func (s *Store) expiresIndexName(local bool) string {
 	if local {
 		return indexExpiresLocal
@@ -1183,8 +1291,15 @@ func aclRoleSetTxn(tx WriteTxn, idx uint64, role *structs.ACLRole, allowMissing
 			return fmt.Errorf("encountered a Role %s (%s) with an empty templated policy name in the state store", role.Name, role.ID)
 		}
 
-		baseTemplate, ok := structs.GetACLTemplatedPolicyBase(templatedPolicy.TemplateName)
-		if !ok {
+		baseTemplate, err := aclTemplatedPolicyBaseGetByNameTxn(tx, nil, templatedPolicy.TemplateName)
+		if err != nil {
+			return err
+		}
+		if baseTemplate == nil {
+			// user-defined templated policies may not have been replicated yet
+			if allowMissing {
+				continue
+			}
 			return fmt.Errorf("encountered a Role %s (%s) with an invalid templated policy name %q", role.Name, role.ID, templatedPolicy.TemplateName)
 		}
 
@@ -1192,8 +1307,7 @@ func aclRoleSetTxn(tx WriteTxn, idx uint64, role *structs.ACLRole, allowMissing
 			templatedPolicy.TemplateID = baseTemplate.TemplateID
 		}
 
-		err := templatedPolicy.ValidateTemplatedPolicy(baseTemplate.Schema)
-		if err != nil {
+		if err := templatedPolicy.ValidateTemplatedPolicy(baseTemplate.Schema); err != nil {
 			return fmt.Errorf("encountered a Role %s (%s) with an invalid templated policy: %w", role.Name, role.ID, err)
 		}
 	}
@@ -55,7 +55,8 @@ func testIndexerTableACLTokens() map[string]indexerTestCase {
 		Roles: []structs.ACLTokenRoleLink{
 			{ID: roleID1}, {ID: roleID2},
 		},
-		AuthMethod: "test-Auth-Method",
+		AuthMethod:       "test-Auth-Method",
+		PreviousSecretID: "123e4567-e89a-12d7-a456-426614174AbE",
 	}
 	encodedPID1 := []byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9a, 0x12, 0xd7, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x01}
 	encodedPID2 := []byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9a, 0x12, 0xd7, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x02}
@@ -98,6 +99,16 @@ func testIndexerTableACLTokens() map[string]indexerTestCase {
 				expected: []byte("test-auth-method\x00"),
 			},
 		},
+		indexPreviousSecret: {
+			read: indexValue{
+				source:   "123e4567-e89a-12d7-a456-426614174AbE",
+				expected: []byte("123e4567-e89a-12d7-a456-426614174AbE\x00"),
+			},
+			write: indexValue{
+				source:   obj,
+				expected: []byte("123e4567-e89a-12d7-a456-426614174AbE\x00"),
+			},
+		},
 	}
 }
 
@@ -21,7 +21,24 @@ func aclChangeUnsubscribeEvent(tx ReadTxn, changes Changes) ([]stream.Event, err
 		switch change.Table {
 		case tableACLTokens:
 			token := changeObject(change).(*structs.ACLToken)
-			secretIDs = append(secretIDs, token.SecretID)
+			// Recording the usage of a token leaves its ModifyIndex alone and
+			// doesn't affect what it is allowed to do.
+			if change.Updated() && change.Before.(*structs.ACLToken).ModifyIndex == token.ModifyIndex {
+				continue
+			}
+			secretIDs = appendTokenSecretIDs(secretIDs, token)
+
+			// Also unsubscribe the secrets the token no longer has, after it
+			// was rotated or its previous secret was reaped.
+			if change.Updated() {
+				before := change.Before.(*structs.ACLToken)
+				if before.SecretID != token.SecretID {
+					secretIDs = append(secretIDs, before.SecretID)
+				}
+				if before.PreviousSecretID != "" && before.PreviousSecretID != token.PreviousSecretID {
+					secretIDs = append(secretIDs, before.PreviousSecretID)
+				}
+			}
 
 		case tableACLRoles:
 			role := changeObject(change).(*structs.ACLRole)
@@ -71,8 +88,17 @@ func changeObject(change memdb.Change) interface{} {
 
 func appendSecretIDsFromTokenIterator(seq []string, tokens memdb.ResultIterator) []string {
 	for token := tokens.Next(); token != nil; token = tokens.Next() {
-		token := token.(*structs.ACLToken)
-		seq = append(seq, token.SecretID)
+		seq = appendTokenSecretIDs(seq, token.(*structs.ACLToken))
+	}
+	return seq
+}
+
+// appendTokenSecretIDs appends the SecretID of the token, and its previous
+// SecretID if it was rotated and is still in its grace period.
+func appendTokenSecretIDs(seq []string, token *structs.ACLToken) []string {
+	seq = append(seq, token.SecretID)
+	if token.PreviousSecretID != "" {
+		seq = append(seq, token.PreviousSecretID)
 	}
 	return seq
 }
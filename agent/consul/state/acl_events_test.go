@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/consul/agent/consul/stream"
 	"github.com/hashicorp/consul/agent/structs"
@@ -41,6 +42,43 @@ func TestACLChangeUnsubscribeEvent(t *testing.T) {
 			},
 			expected: stream.NewCloseSubscriptionEvent(newSecretIDs(1)),
 		},
+		{
+			Name: "token rotate",
+			Setup: func(tx *txn) error {
+				return aclTokenSetTxn(tx, tx.Index, newACLToken(1), ACLTokenSetOptions{})
+			},
+			Mutate: func(tx *txn) error {
+				return aclTokenSetTxn(tx, tx.Index, newRotatedACLToken(1, 2), ACLTokenSetOptions{AllowSecretRotation: true})
+			},
+			// The old secret is unsubscribed both as the deleted entry and as
+			// the previous secret of the new one.
+			expected: stream.NewCloseSubscriptionEvent(newSecretIDs(1, 2, 1)),
+		},
+		{
+			Name: "token previous secret reaped",
+			Setup: func(tx *txn) error {
+				return aclTokenSetTxn(tx, tx.Index, newRotatedACLToken(1, 2), ACLTokenSetOptions{AllowSecretRotation: true})
+			},
+			Mutate: func(tx *txn) error {
+				token := newRotatedACLToken(1, 2)
+				token.PreviousSecretID = ""
+				token.PreviousSecretExpirationTime = nil
+				return aclTokenSetTxn(tx, tx.Index, token, ACLTokenSetOptions{})
+			},
+			expected: stream.NewCloseSubscriptionEvent(newSecretIDs(2, 1)),
+		},
+		{
+			Name: "token usage",
+			Setup: func(tx *txn) error {
+				return aclTokenSetTxn(tx, tx.Index, newACLToken(1), ACLTokenSetOptions{})
+			},
+			Mutate: func(tx *txn) error {
+				token := newACLToken(1)
+				return aclTokenUsageSetTxn(tx, structs.ACLTokenUsage{AccessorID: token.AccessorID, LastUsedAt: time.Now()})
+			},
+			// Only the usage changed, subscriptions are left alone.
+			expected: stream.NewCloseSubscriptionEvent(nil),
+		},
 		{
 			Name: "token delete",
 			Setup: func(tx *txn) error {
@@ -177,6 +215,17 @@ func newACLToken(n int) *structs.ACLToken {
 	}
 }
 
+// newRotatedACLToken returns the token n after its secret was rotated to the
+// one of token secret, during its grace period.
+func newRotatedACLToken(n, secret int) *structs.ACLToken {
+	token := newACLToken(n)
+	token.PreviousSecretID = token.SecretID
+	token.SecretID = newACLToken(secret).SecretID
+	expiration := time.Now().Add(time.Hour)
+	token.PreviousSecretExpirationTime = &expiration
+	return token
+}
+
 func newACLPolicy(n int) *structs.ACLPolicy {
 	numStr := strconv.Itoa(n)
 	uuid := strings.ReplaceAll("22222222-????-????-????-????????????", "?", numStr)
@@ -19,6 +19,8 @@ const (
 	tableACLBindingRules = "acl-binding-rules"
 	tableACLAuthMethods  = "acl-auth-methods"
 
+	tableACLTemplatedPolicies = "acl-templated-policies"
+
 	indexAccessor      = "accessor"
 	indexPolicies      = "policies"
 	indexRoles         = "roles"
@@ -28,6 +30,9 @@ const (
 	indexName          = "name"
 	indexExpiresGlobal = "expires-global"
 	indexExpiresLocal  = "expires-local"
+
+	indexPreviousSecret        = "previous-secret"
+	indexPreviousSecretExpires = "previous-secret-expires"
 )
 
 func tokensTableSchema() *memdb.TableSchema {
@@ -116,6 +121,24 @@ func tokensTableSchema() *memdb.TableSchema {
 					writeIndexMulti: indexServiceNameFromACLToken,
 				},
 			},
+			indexPreviousSecret: {
+				Name:         indexPreviousSecret,
+				AllowMissing: true,
+				Unique:       true,
+				Indexer: indexerSingle[string, *structs.ACLToken]{
+					readIndex:  indexFromStringCaseSensitive,
+					writeIndex: indexPreviousSecretIDFromACLToken,
+				},
+			},
+			indexPreviousSecretExpires: {
+				Name:         indexPreviousSecretExpires,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: indexerSingle[*TimeQuery, *structs.ACLToken]{
+					readIndex:  indexFromTimeQuery,
+					writeIndex: indexPreviousSecretExpiresFromACLToken,
+				},
+			},
 		},
 	}
 }
@@ -156,6 +179,41 @@ func indexNameFromACLPolicy(p *structs.ACLPolicy) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+func templatedPoliciesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: tableACLTemplatedPolicies,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.UUIDFieldIndex{
+					Field: "ID",
+				},
+			},
+			indexName: {
+				Name:         indexName,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: indexerSingle[string, *structs.ACLTemplatedPolicyDefinition]{
+					readIndex:  indexFromString,
+					writeIndex: indexNameFromACLTemplatedPolicy,
+				},
+			},
+		},
+	}
+}
+
+func indexNameFromACLTemplatedPolicy(p *structs.ACLTemplatedPolicyDefinition) ([]byte, error) {
+	if p.Name == "" {
+		return nil, errMissingValueForIndex
+	}
+
+	var b indexBuilder
+	b.String(strings.ToLower(p.Name))
+	return b.Bytes(), nil
+}
+
 func rolesTableSchema() *memdb.TableSchema {
 	return &memdb.TableSchema{
 		Name: tableACLRoles,
@@ -318,6 +376,29 @@ func indexSecretIDFromACLToken(t *structs.ACLToken) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+func indexPreviousSecretIDFromACLToken(t *structs.ACLToken) ([]byte, error) {
+	if t.PreviousSecretID == "" {
+		return nil, errMissingValueForIndex
+	}
+
+	var b indexBuilder
+	b.String(t.PreviousSecretID)
+	return b.Bytes(), nil
+}
+
+func indexPreviousSecretExpiresFromACLToken(t *structs.ACLToken) ([]byte, error) {
+	if t.PreviousSecretID == "" || t.PreviousSecretExpirationTime == nil {
+		return nil, errMissingValueForIndex
+	}
+	if t.PreviousSecretExpirationTime.Unix() < 0 {
+		return nil, fmt.Errorf("token previous secret expiration time cannot be before the unix epoch: %s", t.PreviousSecretExpirationTime)
+	}
+
+	var b indexBuilder
+	b.Time(*t.PreviousSecretExpirationTime)
+	return b.Bytes(), nil
+}
+
 func indexFromStringCaseSensitive(s string) ([]byte, error) {
 	var b indexBuilder
 	b.String(s)
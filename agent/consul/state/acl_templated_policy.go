@@ -0,0 +1,259 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+var (
+	// ErrMissingACLTemplatedPolicyID is returned when a templated policy set
+	// is called on a templated policy with an empty ID.
+	ErrMissingACLTemplatedPolicyID = errors.New("Missing ACL Templated Policy ID")
+
+	// ErrMissingACLTemplatedPolicyName is returned when a templated policy set
+	// is called on a templated policy with an empty Name.
+	ErrMissingACLTemplatedPolicyName = errors.New("Missing ACL Templated Policy Name")
+)
+
+// ACLTemplatedPolicies is used when saving a snapshot
+func (s *Snapshot) ACLTemplatedPolicies() (memdb.ResultIterator, error) {
+	return s.tx.Get(tableACLTemplatedPolicies, indexID)
+}
+
+func (s *Restore) ACLTemplatedPolicy(templatedPolicy *structs.ACLTemplatedPolicyDefinition) error {
+	return aclTemplatedPolicyInsert(s.tx, templatedPolicy)
+}
+
+func (s *Store) ACLTemplatedPolicyBatchSet(idx uint64, templatedPolicies structs.ACLTemplatedPolicyDefinitions) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	for _, templatedPolicy := range templatedPolicies {
+		if err := aclTemplatedPolicySetTxn(tx, idx, templatedPolicy); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *Store) ACLTemplatedPolicySet(idx uint64, templatedPolicy *structs.ACLTemplatedPolicyDefinition) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	if err := aclTemplatedPolicySetTxn(tx, idx, templatedPolicy); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func aclTemplatedPolicySetTxn(tx WriteTxn, idx uint64, templatedPolicy *structs.ACLTemplatedPolicyDefinition) error {
+	if templatedPolicy.ID == "" {
+		return ErrMissingACLTemplatedPolicyID
+	}
+
+	if templatedPolicy.Name == "" {
+		return ErrMissingACLTemplatedPolicyName
+	}
+
+	if _, ok := structs.GetACLTemplatedPolicyBase(templatedPolicy.Name); ok {
+		return fmt.Errorf("A builtin templated policy with name %q already exists", templatedPolicy.Name)
+	}
+
+	existingRaw, err := tx.First(tableACLTemplatedPolicies, indexID, templatedPolicy.ID)
+	if err != nil {
+		return fmt.Errorf("failed acl templated policy lookup: %v", err)
+	}
+
+	// ensure the name is unique (cannot conflict with another templated policy with a different ID)
+	nameMatch, err := tx.First(tableACLTemplatedPolicies, indexName, templatedPolicy.Name)
+	if err != nil {
+		return fmt.Errorf("failed acl templated policy lookup: %v", err)
+	}
+	if nameMatch != nil && templatedPolicy.ID != nameMatch.(*structs.ACLTemplatedPolicyDefinition).ID {
+		return fmt.Errorf("A templated policy with name %q already exists", templatedPolicy.Name)
+	}
+
+	// Set the indexes
+	if existingRaw != nil {
+		templatedPolicy.CreateIndex = existingRaw.(*structs.ACLTemplatedPolicyDefinition).CreateIndex
+		templatedPolicy.ModifyIndex = idx
+	} else {
+		templatedPolicy.CreateIndex = idx
+		templatedPolicy.ModifyIndex = idx
+	}
+
+	return aclTemplatedPolicyInsert(tx, templatedPolicy)
+}
+
+func (s *Store) ACLTemplatedPolicyGetByID(ws memdb.WatchSet, id string) (uint64, *structs.ACLTemplatedPolicyDefinition, error) {
+	return s.aclTemplatedPolicyGet(ws, indexID, id)
+}
+
+func (s *Store) ACLTemplatedPolicyGetByName(ws memdb.WatchSet, name string) (uint64, *structs.ACLTemplatedPolicyDefinition, error) {
+	return s.aclTemplatedPolicyGet(ws, indexName, name)
+}
+
+func (s *Store) aclTemplatedPolicyGet(ws memdb.WatchSet, index, value string) (uint64, *structs.ACLTemplatedPolicyDefinition, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	templatedPolicy, err := aclTemplatedPolicyGetTxn(tx, ws, index, value)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return maxIndexTxn(tx, tableACLTemplatedPolicies), templatedPolicy, nil
+}
+
+func aclTemplatedPolicyGetTxn(tx ReadTxn, ws memdb.WatchSet, index, value string) (*structs.ACLTemplatedPolicyDefinition, error) {
+	watchCh, raw, err := tx.FirstWatch(tableACLTemplatedPolicies, index, value)
+	if err != nil {
+		return nil, fmt.Errorf("failed acl templated policy lookup: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if raw == nil {
+		return nil, nil
+	}
+	return raw.(*structs.ACLTemplatedPolicyDefinition), nil
+}
+
+// ACLTemplatedPolicyBaseGetByName returns the builtin or user-defined templated
+// policy with the given name, or nil if there is no such templated policy.
+func (s *Store) ACLTemplatedPolicyBaseGetByName(ws memdb.WatchSet, name string) (uint64, *structs.ACLTemplatedPolicyBase, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	base, err := aclTemplatedPolicyBaseGetByNameTxn(tx, ws, name)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return maxIndexTxn(tx, tableACLTemplatedPolicies), base, nil
+}
+
+func aclTemplatedPolicyBaseGetByNameTxn(tx ReadTxn, ws memdb.WatchSet, name string) (*structs.ACLTemplatedPolicyBase, error) {
+	if base, ok := structs.GetACLTemplatedPolicyBase(name); ok {
+		return base, nil
+	}
+
+	templatedPolicy, err := aclTemplatedPolicyGetTxn(tx, ws, indexName, name)
+	if err != nil || templatedPolicy == nil {
+		return nil, err
+	}
+	return templatedPolicy.Base(), nil
+}
+
+func (s *Store) ACLTemplatedPolicyBatchGet(ws memdb.WatchSet, ids []string) (uint64, structs.ACLTemplatedPolicyDefinitions, error) {
+	return s.aclTemplatedPolicyBatchGet(ws, indexID, ids)
+}
+
+func (s *Store) ACLTemplatedPolicyBatchGetByName(ws memdb.WatchSet, names []string) (uint64, structs.ACLTemplatedPolicyDefinitions, error) {
+	return s.aclTemplatedPolicyBatchGet(ws, indexName, names)
+}
+
+func (s *Store) aclTemplatedPolicyBatchGet(ws memdb.WatchSet, index string, values []string) (uint64, structs.ACLTemplatedPolicyDefinitions, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	templatedPolicies := make(structs.ACLTemplatedPolicyDefinitions, 0)
+	for _, value := range values {
+		templatedPolicy, err := aclTemplatedPolicyGetTxn(tx, ws, index, value)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if templatedPolicy != nil {
+			templatedPolicies = append(templatedPolicies, templatedPolicy)
+		}
+	}
+
+	return maxIndexTxn(tx, tableACLTemplatedPolicies), templatedPolicies, nil
+}
+
+func (s *Store) ACLTemplatedPolicyList(ws memdb.WatchSet) (uint64, structs.ACLTemplatedPolicyDefinitions, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	iter, err := tx.Get(tableACLTemplatedPolicies, indexName)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed acl templated policy lookup: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	var result structs.ACLTemplatedPolicyDefinitions
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		result = append(result, raw.(*structs.ACLTemplatedPolicyDefinition))
+	}
+
+	return maxIndexTxn(tx, tableACLTemplatedPolicies), result, nil
+}
+
+func (s *Store) ACLTemplatedPolicyDeleteByID(idx uint64, id string) error {
+	return s.aclTemplatedPolicyDelete(idx, indexID, id)
+}
+
+func (s *Store) ACLTemplatedPolicyDeleteByName(idx uint64, name string) error {
+	return s.aclTemplatedPolicyDelete(idx, indexName, name)
+}
+
+func (s *Store) ACLTemplatedPolicyBatchDelete(idx uint64, ids []string) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	for _, id := range ids {
+		if err := aclTemplatedPolicyDeleteTxn(tx, idx, indexID, id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *Store) aclTemplatedPolicyDelete(idx uint64, index, value string) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	if err := aclTemplatedPolicyDeleteTxn(tx, idx, index, value); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func aclTemplatedPolicyDeleteTxn(tx WriteTxn, idx uint64, index, value string) error {
+	raw, err := tx.First(tableACLTemplatedPolicies, index, value)
+	if err != nil {
+		return fmt.Errorf("failed acl templated policy lookup: %v", err)
+	}
+
+	if raw == nil {
+		return nil
+	}
+
+	if err := tx.Delete(tableACLTemplatedPolicies, raw); err != nil {
+		return fmt.Errorf("failed deleting acl templated policy: %v", err)
+	}
+	if err := indexUpdateMaxTxn(tx, idx, tableACLTemplatedPolicies); err != nil {
+		return fmt.Errorf("failed updating acl templated policies index: %v", err)
+	}
+	return nil
+}
+
+func aclTemplatedPolicyInsert(tx WriteTxn, templatedPolicy *structs.ACLTemplatedPolicyDefinition) error {
+	if err := tx.Insert(tableACLTemplatedPolicies, templatedPolicy); err != nil {
+		return fmt.Errorf("failed inserting acl templated policy: %v", err)
+	}
+	if err := indexUpdateMaxTxn(tx, templatedPolicy.ModifyIndex, tableACLTemplatedPolicies); err != nil {
+		return fmt.Errorf("failed updating acl templated policies index: %v", err)
+	}
+	return nil
+}
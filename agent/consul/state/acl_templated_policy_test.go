@@ -0,0 +1,186 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+)
+
+func TestStateStore_ACLTemplatedPolicy_SetGet(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Missing ID", func(t *testing.T) {
+		t.Parallel()
+		s := testACLStateStore(t)
+
+		tp := structs.ACLTemplatedPolicyDefinition{
+			Name:     "team-kv-namespace",
+			Template: `key_prefix "teams/" { policy = "read" }`,
+		}
+
+		require.ErrorIs(t, s.ACLTemplatedPolicySet(3, &tp), ErrMissingACLTemplatedPolicyID)
+	})
+
+	t.Run("Missing Name", func(t *testing.T) {
+		t.Parallel()
+		s := testACLStateStore(t)
+
+		tp := structs.ACLTemplatedPolicyDefinition{
+			ID:       testRoleID_A,
+			Template: `key_prefix "teams/" { policy = "read" }`,
+		}
+
+		require.ErrorIs(t, s.ACLTemplatedPolicySet(3, &tp), ErrMissingACLTemplatedPolicyName)
+	})
+
+	t.Run("Builtin Name", func(t *testing.T) {
+		t.Parallel()
+		s := testACLStateStore(t)
+
+		tp := structs.ACLTemplatedPolicyDefinition{
+			ID:       testRoleID_A,
+			Name:     api.ACLTemplatedPolicyServiceName,
+			Template: `key_prefix "teams/" { policy = "read" }`,
+		}
+
+		require.Error(t, s.ACLTemplatedPolicySet(3, &tp))
+	})
+
+	t.Run("Duplicate Name", func(t *testing.T) {
+		t.Parallel()
+		s := testACLStateStore(t)
+
+		require.NoError(t, s.ACLTemplatedPolicySet(3, &structs.ACLTemplatedPolicyDefinition{
+			ID:       testRoleID_A,
+			Name:     "team-kv-namespace",
+			Template: `key_prefix "teams/" { policy = "read" }`,
+		}))
+
+		require.Error(t, s.ACLTemplatedPolicySet(4, &structs.ACLTemplatedPolicyDefinition{
+			ID:       testRoleID_B,
+			Name:     "team-kv-namespace",
+			Template: `key_prefix "teams/" { policy = "read" }`,
+		}))
+	})
+
+	t.Run("Insert and Update", func(t *testing.T) {
+		t.Parallel()
+		s := testACLStateStore(t)
+
+		require.NoError(t, s.ACLTemplatedPolicySet(3, &structs.ACLTemplatedPolicyDefinition{
+			ID:       testRoleID_A,
+			Name:     "team-kv-namespace",
+			Template: `key_prefix "teams/" { policy = "read" }`,
+		}))
+
+		idx, rtp, err := s.ACLTemplatedPolicyGetByID(nil, testRoleID_A)
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), idx)
+		require.NotNil(t, rtp)
+		require.Equal(t, uint64(3), rtp.CreateIndex)
+		require.Equal(t, uint64(3), rtp.ModifyIndex)
+
+		require.NoError(t, s.ACLTemplatedPolicySet(4, &structs.ACLTemplatedPolicyDefinition{
+			ID:          testRoleID_A,
+			Name:        "team-kv-namespace",
+			Description: "updated",
+			Template:    `key_prefix "teams/" { policy = "write" }`,
+		}))
+
+		idx, rtp, err = s.ACLTemplatedPolicyGetByName(nil, "team-kv-namespace")
+		require.NoError(t, err)
+		require.Equal(t, uint64(4), idx)
+		require.NotNil(t, rtp)
+		require.Equal(t, "updated", rtp.Description)
+		require.Equal(t, uint64(3), rtp.CreateIndex)
+		require.Equal(t, uint64(4), rtp.ModifyIndex)
+
+		_, base, err := s.ACLTemplatedPolicyBaseGetByName(nil, "team-kv-namespace")
+		require.NoError(t, err)
+		require.NotNil(t, base)
+		require.Equal(t, testRoleID_A, base.TemplateID)
+
+		_, base, err = s.ACLTemplatedPolicyBaseGetByName(nil, api.ACLTemplatedPolicyNodeName)
+		require.NoError(t, err)
+		require.NotNil(t, base)
+		require.Equal(t, structs.ACLTemplatedPolicyNodeID, base.TemplateID)
+
+		_, base, err = s.ACLTemplatedPolicyBaseGetByName(nil, "does-not-exist")
+		require.NoError(t, err)
+		require.Nil(t, base)
+	})
+}
+
+func TestStateStore_ACLTemplatedPolicy_ListDelete(t *testing.T) {
+	t.Parallel()
+	s := testACLStateStore(t)
+
+	require.NoError(t, s.ACLTemplatedPolicyBatchSet(2, structs.ACLTemplatedPolicyDefinitions{
+		{
+			ID:       testRoleID_A,
+			Name:     "team-a",
+			Template: `key_prefix "teams/a/" { policy = "read" }`,
+		},
+		{
+			ID:       testRoleID_B,
+			Name:     "team-b",
+			Template: `key_prefix "teams/b/" { policy = "read" }`,
+		},
+	}))
+
+	idx, tps, err := s.ACLTemplatedPolicyList(nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), idx)
+	require.Len(t, tps, 2)
+
+	_, tps, err = s.ACLTemplatedPolicyBatchGetByName(nil, []string{"team-b", "missing"})
+	require.NoError(t, err)
+	require.Len(t, tps, 1)
+	require.Equal(t, testRoleID_B, tps[0].ID)
+
+	require.NoError(t, s.ACLTemplatedPolicyDeleteByName(3, "team-a"))
+	require.NoError(t, s.ACLTemplatedPolicyBatchDelete(4, []string{testRoleID_B}))
+	// deleting a missing templated policy is not an error
+	require.NoError(t, s.ACLTemplatedPolicyDeleteByID(5, testRoleID_A))
+
+	idx, tps, err = s.ACLTemplatedPolicyList(nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), idx)
+	require.Empty(t, tps)
+}
+
+func testIndexerTableACLTemplatedPolicies() map[string]indexerTestCase {
+	obj := &structs.ACLTemplatedPolicyDefinition{
+		ID:   "123e4567-e89a-12d7-a456-426614174abc",
+		Name: "Team-KV",
+	}
+	encodedID := []byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9a, 0x12, 0xd7, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x4a, 0xbc}
+	return map[string]indexerTestCase{
+		indexID: {
+			read: indexValue{
+				source:   obj.ID,
+				expected: encodedID,
+			},
+			write: indexValue{
+				source:   obj,
+				expected: encodedID,
+			},
+		},
+		indexName: {
+			read: indexValue{
+				source:   "Team-KV",
+				expected: []byte("team-kv\x00"),
+			},
+			write: indexValue{
+				source:   obj,
+				expected: []byte("team-kv\x00"),
+			},
+		},
+	}
+}
@@ -504,6 +504,50 @@ func TestStateStore_ACLToken_SetGet(t *testing.T) {
 	})
 }
 
+func TestStateStore_ACLToken_UsageSet(t *testing.T) {
+	t.Parallel()
+	s := testACLTokensStateStore(t)
+
+	token := &structs.ACLToken{
+		AccessorID: "daf37c07-d04d-4fd5-9678-a8206a57d61a",
+		SecretID:   "39171632-6f34-4411-827f-9416403687f4",
+	}
+	require.NoError(t, s.ACLTokenSet(2, token))
+
+	now := time.Now().UTC()
+	require.NoError(t, s.ACLTokenUsageSet(3, []structs.ACLTokenUsage{
+		{AccessorID: token.AccessorID, LastUsedAt: now},
+		// Unknown tokens are ignored.
+		{AccessorID: "b9a4a3a1-0f49-4e8d-8d3e-2b9c6c0f6f9e", LastUsedAt: now},
+	}))
+
+	_, rtoken, err := s.ACLTokenGetByAccessor(nil, token.AccessorID, nil)
+	require.NoError(t, err)
+	require.NotNil(t, rtoken.LastUsedAt)
+	require.True(t, now.Equal(*rtoken.LastUsedAt))
+	require.Equal(t, uint64(2), rtoken.ModifyIndex)
+
+	// Usage never moves backwards.
+	require.NoError(t, s.ACLTokenUsageSet(4, []structs.ACLTokenUsage{
+		{AccessorID: token.AccessorID, LastUsedAt: now.Add(-time.Hour)},
+	}))
+	_, rtoken, err = s.ACLTokenGetByAccessor(nil, token.AccessorID, nil)
+	require.NoError(t, err)
+	require.True(t, now.Equal(*rtoken.LastUsedAt))
+
+	// Updates of the token that don't carry usage keep it.
+	require.NoError(t, s.ACLTokenSet(5, &structs.ACLToken{
+		AccessorID:  token.AccessorID,
+		SecretID:    token.SecretID,
+		Description: "updated",
+	}))
+	_, rtoken, err = s.ACLTokenGetByAccessor(nil, token.AccessorID, nil)
+	require.NoError(t, err)
+	require.Equal(t, "updated", rtoken.Description)
+	require.NotNil(t, rtoken.LastUsedAt)
+	require.True(t, now.Equal(*rtoken.LastUsedAt))
+}
+
 func TestStateStore_ACLTokens_UpsertBatchRead(t *testing.T) {
 	t.Parallel()
 
@@ -1466,6 +1510,104 @@ func TestStateStore_ACLToken_Delete(t *testing.T) {
 	})
 }
 
+func TestStateStore_ACLToken_Rotate(t *testing.T) {
+	t.Parallel()
+
+	const (
+		accessorID = "daf37c07-d04d-4fd5-9678-a8206a57d61a"
+		oldSecret  = "39171632-6f34-4411-827f-9416403687f4"
+		newSecret  = "e4b5e0d3-5a8f-4d4b-8f6e-3a0bd6f2f5a1"
+	)
+
+	setup := func(t *testing.T, previousExpiration time.Time) *Store {
+		s := testACLTokensStateStore(t)
+		token := &structs.ACLToken{
+			AccessorID: accessorID,
+			SecretID:   oldSecret,
+			Policies: []structs.ACLTokenPolicyLink{
+				{ID: testPolicyID_A},
+			},
+		}
+		require.NoError(t, s.ACLTokenSet(2, token))
+
+		_, token, err := s.ACLTokenGetByAccessor(nil, accessorID, nil)
+		require.NoError(t, err)
+
+		rotated := token.Clone()
+		rotated.SecretID = newSecret
+		rotationTime := time.Now()
+		rotated.RotationTime = &rotationTime
+		rotated.PreviousSecretID = oldSecret
+		rotated.PreviousSecretExpirationTime = &previousExpiration
+		rotated.SetHash(true)
+		require.NoError(t, s.ACLTokenBatchSet(3, structs.ACLTokens{rotated}, ACLTokenSetOptions{AllowSecretRotation: true}))
+		return s
+	}
+
+	t.Run("Immutable Without Rotation", func(t *testing.T) {
+		t.Parallel()
+		s := testACLTokensStateStore(t)
+		require.NoError(t, s.ACLTokenSet(2, &structs.ACLToken{AccessorID: accessorID, SecretID: oldSecret}))
+
+		err := s.ACLTokenSet(3, &structs.ACLToken{AccessorID: accessorID, SecretID: newSecret})
+		require.EqualError(t, err, "The ACL Token SecretID field is immutable")
+	})
+
+	t.Run("Grace Period", func(t *testing.T) {
+		t.Parallel()
+		s := setup(t, time.Now().Add(time.Hour))
+
+		for _, secret := range []string{oldSecret, newSecret} {
+			_, token, err := s.ACLTokenGetBySecret(nil, secret, nil)
+			require.NoError(t, err)
+			require.NotNil(t, token)
+			require.Equal(t, accessorID, token.AccessorID)
+			require.Equal(t, newSecret, token.SecretID)
+		}
+
+		// The rotated token replaces the original rather than sitting next to it.
+		_, tokens, err := s.ACLTokenList(nil, true, true, "", "", "", nil, nil)
+		require.NoError(t, err)
+		var matches int
+		for _, token := range tokens {
+			if token.AccessorID == accessorID {
+				matches++
+			}
+		}
+		require.Equal(t, 1, matches)
+
+		expired, err := s.ACLTokenListPreviousSecretExpired(false, time.Now(), 10)
+		require.NoError(t, err)
+		require.Empty(t, expired)
+
+		expired, err = s.ACLTokenListPreviousSecretExpired(false, time.Now().Add(2*time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, expired, 1)
+		require.Equal(t, accessorID, expired[0].AccessorID)
+	})
+
+	t.Run("Grace Period Ended", func(t *testing.T) {
+		t.Parallel()
+		s := setup(t, time.Now().Add(-time.Minute))
+
+		_, token, err := s.ACLTokenGetBySecret(nil, oldSecret, nil)
+		require.NoError(t, err)
+		require.Nil(t, token)
+
+		_, token, err = s.ACLTokenGetBySecret(nil, newSecret, nil)
+		require.NoError(t, err)
+		require.NotNil(t, token)
+
+		expired, err := s.ACLTokenListPreviousSecretExpired(true, time.Now(), 10)
+		require.NoError(t, err)
+		require.Empty(t, expired)
+
+		expired, err = s.ACLTokenListPreviousSecretExpired(false, time.Now(), 10)
+		require.NoError(t, err)
+		require.Len(t, expired, 1)
+	})
+}
+
 func TestStateStore_ACLPolicy_SetGet(t *testing.T) {
 	t.Parallel()
 
@@ -633,6 +633,7 @@ func validateProposedConfigEntryInGraph(
 	case structs.HTTPRoute:
 	case structs.TCPRoute:
 	case structs.RateLimitIPConfig:
+	case structs.FederatedTrustBundle:
 	case structs.JWTProvider:
 		if newEntry == nil && existingEntry != nil {
 			err := validateJWTProviderIsReferenced(tx, kindName, existingEntry)
@@ -102,6 +102,7 @@ var _ configEntryIndexable = (*structs.ServiceRouterConfigEntry)(nil)
 var _ configEntryIndexable = (*structs.ServiceSplitterConfigEntry)(nil)
 var _ configEntryIndexable = (*structs.TerminatingGatewayConfigEntry)(nil)
 var _ configEntryIndexable = (*structs.JWTProviderConfigEntry)(nil)
+var _ configEntryIndexable = (*structs.FederatedTrustBundleConfigEntry)(nil)
 
 func indexFromConfigEntry(c structs.ConfigEntry) ([]byte, error) {
 	if c.GetName() == "" || c.GetKind() == "" {
@@ -13,11 +13,12 @@ import (
 )
 
 const (
-	tableConnectCABuiltin       = "connect-ca-builtin"
-	tableConnectCABuiltinSerial = "connect-ca-builtin-serial"
-	tableConnectCAConfig        = "connect-ca-config"
-	tableConnectCARoots         = "connect-ca-roots"
-	tableConnectCALeafCerts     = "connect-ca-leaf-certs"
+	tableConnectCABuiltin        = "connect-ca-builtin"
+	tableConnectCABuiltinSerial  = "connect-ca-builtin-serial"
+	tableConnectCAConfig         = "connect-ca-config"
+	tableConnectCARoots          = "connect-ca-roots"
+	tableConnectCALeafCerts      = "connect-ca-leaf-certs"
+	tableConnectCARevocationList = "connect-ca-revocation-list"
 )
 
 // caBuiltinProviderTableSchema returns a new table schema used for storing
@@ -77,6 +78,43 @@ func caRootTableSchema() *memdb.TableSchema {
 	}
 }
 
+// caLeafCertTableSchema returns a new table schema used for storing the
+// leaf certificates issued for Connect.
+func caLeafCertTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: tableConnectCALeafCerts,
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "SerialNumber",
+				},
+			},
+		},
+	}
+}
+
+// caRevocationListTableSchema returns a new table schema used for storing
+// the revocation lists of the leaf certificates issued for Connect, one for
+// each signing certificate.
+func caRevocationListTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: tableConnectCARevocationList,
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "SigningKeyID",
+				},
+			},
+		},
+	}
+}
+
 // CAConfig is used to pull the CA config from the snapshot.
 func (s *Snapshot) CAConfig() (*structs.CAConfiguration, error) {
 	c, err := s.tx.First(tableConnectCAConfig, "id")
@@ -450,6 +488,240 @@ func (s *Store) CALeafSetIndex(idx uint64, index uint64) error {
 	return indexUpdateMaxTxn(tx, index, tableConnectCALeafCerts)
 }
 
+// CALeafCerts is used to pull the issued leaf certificates from the snapshot.
+func (s *Snapshot) CALeafCerts() ([]*structs.IssuedCert, error) {
+	iter, err := s.tx.Get(tableConnectCALeafCerts, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*structs.IssuedCert
+	for wrapped := iter.Next(); wrapped != nil; wrapped = iter.Next() {
+		ret = append(ret, wrapped.(*structs.IssuedCert))
+	}
+
+	return ret, nil
+}
+
+// CALeafCert is used when restoring from a snapshot.
+func (s *Restore) CALeafCert(cert *structs.IssuedCert) error {
+	if err := s.tx.Insert(tableConnectCALeafCerts, cert); err != nil {
+		return fmt.Errorf("failed restoring CA leaf cert: %s", err)
+	}
+	if err := indexUpdateMaxTxn(s.tx, cert.ModifyIndex, tableConnectCALeafCerts); err != nil {
+		return fmt.Errorf("failed updating index: %s", err)
+	}
+
+	return nil
+}
+
+// CALeafCertSet records a leaf certificate that was issued and updates the
+// leaf certificates index.
+func (s *Store) CALeafCertSet(idx uint64, cert *structs.IssuedCert) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	cert.CreateIndex = idx
+	cert.ModifyIndex = idx
+	if err := tx.Insert(tableConnectCALeafCerts, cert); err != nil {
+		return fmt.Errorf("failed inserting CA leaf cert: %s", err)
+	}
+	if err := indexUpdateMaxTxn(tx, idx, tableConnectCALeafCerts); err != nil {
+		return fmt.Errorf("failed updating index: %s", err)
+	}
+
+	return tx.Commit()
+}
+
+// CALeafCerts returns the recorded leaf certificates that were issued.
+func (s *Store) CALeafCerts(ws memdb.WatchSet) (uint64, []*structs.IssuedCert, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	idx := maxIndexTxn(tx, tableConnectCALeafCerts)
+
+	iter, err := tx.Get(tableConnectCALeafCerts, "id")
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed CA leaf certs lookup: %s", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	var result []*structs.IssuedCert
+	for v := iter.Next(); v != nil; v = iter.Next() {
+		result = append(result, v.(*structs.IssuedCert))
+	}
+	return idx, result, nil
+}
+
+// CALeafCert returns the recorded leaf certificate with the given serial
+// number, or nil if it is not known.
+func (s *Store) CALeafCert(ws memdb.WatchSet, serialNumber string) (uint64, *structs.IssuedCert, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	idx := maxIndexTxn(tx, tableConnectCALeafCerts)
+
+	watchCh, existing, err := tx.FirstWatch(tableConnectCALeafCerts, "id", serialNumber)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed CA leaf cert lookup: %s", err)
+	}
+	ws.Add(watchCh)
+
+	if existing == nil {
+		return idx, nil, nil
+	}
+	return idx, existing.(*structs.IssuedCert), nil
+}
+
+// CARevokeLeafCerts marks the given leaf certificates as revoked and replaces
+// the revocation lists in a single transaction. Certificates that are not known
+// are ignored.
+func (s *Store) CARevokeLeafCerts(idx uint64, certs []*structs.IssuedCert, crls []*structs.CARevocationList) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	for _, cert := range certs {
+		existing, err := tx.First(tableConnectCALeafCerts, "id", cert.SerialNumber)
+		if err != nil {
+			return fmt.Errorf("failed CA leaf cert lookup: %s", err)
+		}
+		if existing == nil {
+			continue
+		}
+
+		updated := *existing.(*structs.IssuedCert)
+		updated.RevokedAt = cert.RevokedAt
+		updated.RevocationReason = cert.RevocationReason
+		updated.ModifyIndex = idx
+		if err := tx.Insert(tableConnectCALeafCerts, &updated); err != nil {
+			return fmt.Errorf("failed updating CA leaf cert: %s", err)
+		}
+	}
+	if err := indexUpdateMaxTxn(tx, idx, tableConnectCALeafCerts); err != nil {
+		return fmt.Errorf("failed updating index: %s", err)
+	}
+
+	if err := caSetRevocationListsTxn(tx, idx, crls); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CADeleteLeafCerts deletes the records of the given leaf certificates.
+func (s *Store) CADeleteLeafCerts(idx uint64, certs []*structs.IssuedCert) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	for _, cert := range certs {
+		if _, err := tx.DeleteAll(tableConnectCALeafCerts, "id", cert.SerialNumber); err != nil {
+			return fmt.Errorf("failed deleting CA leaf cert: %s", err)
+		}
+	}
+	if err := indexUpdateMaxTxn(tx, idx, tableConnectCALeafCerts); err != nil {
+		return fmt.Errorf("failed updating index: %s", err)
+	}
+
+	return tx.Commit()
+}
+
+// CARevocationLists is used to pull the revocation lists from the snapshot.
+func (s *Snapshot) CARevocationLists() ([]*structs.CARevocationList, error) {
+	iter, err := s.tx.Get(tableConnectCARevocationList, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	var crls []*structs.CARevocationList
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		crls = append(crls, raw.(*structs.CARevocationList))
+	}
+	return crls, nil
+}
+
+// CARevocationList is used when restoring from a snapshot.
+func (s *Restore) CARevocationList(crl *structs.CARevocationList) error {
+	if err := s.tx.Insert(tableConnectCARevocationList, crl); err != nil {
+		return fmt.Errorf("failed restoring CA revocation list: %s", err)
+	}
+	if err := indexUpdateMaxTxn(s.tx, crl.ModifyIndex, tableConnectCARevocationList); err != nil {
+		return fmt.Errorf("failed updating index: %s", err)
+	}
+
+	return nil
+}
+
+// CARevocationLists returns the current revocation lists, one for each
+// signing certificate.
+func (s *Store) CARevocationLists(ws memdb.WatchSet) (uint64, []*structs.CARevocationList, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	return caRevocationListsTxn(tx, ws)
+}
+
+func caRevocationListsTxn(tx ReadTxn, ws memdb.WatchSet) (uint64, []*structs.CARevocationList, error) {
+	idx := maxIndexTxn(tx, tableConnectCARevocationList)
+
+	iter, err := tx.Get(tableConnectCARevocationList, "id")
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed CA revocation list lookup: %s", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	var crls []*structs.CARevocationList
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		crls = append(crls, raw.(*structs.CARevocationList))
+	}
+	return idx, crls, nil
+}
+
+// CASetRevocationLists replaces the revocation lists with the given ones.
+func (s *Store) CASetRevocationLists(idx uint64, crls []*structs.CARevocationList) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	if err := caSetRevocationListsTxn(tx, idx, crls); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func caSetRevocationListsTxn(tx WriteTxn, idx uint64, crls []*structs.CARevocationList) error {
+	_, prev, err := caRevocationListsTxn(tx, nil)
+	if err != nil {
+		return err
+	}
+
+	createIndexes := make(map[string]uint64, len(prev))
+	for _, crl := range prev {
+		createIndexes[crl.SigningKeyID] = crl.CreateIndex
+		if err := tx.Delete(tableConnectCARevocationList, crl); err != nil {
+			return fmt.Errorf("failed deleting CA revocation list: %s", err)
+		}
+	}
+	for _, crl := range crls {
+		if createIndex, ok := createIndexes[crl.SigningKeyID]; ok {
+			crl.CreateIndex = createIndex
+		} else {
+			crl.CreateIndex = idx
+		}
+		crl.ModifyIndex = idx
+		if err := tx.Insert(tableConnectCARevocationList, crl); err != nil {
+			return fmt.Errorf("failed updating CA revocation list: %s", err)
+		}
+	}
+	if len(prev) == 0 && len(crls) == 0 {
+		return nil
+	}
+
+	if err := tx.Insert(tableIndex, &IndexEntry{tableConnectCARevocationList, idx}); err != nil {
+		return fmt.Errorf("failed updating index: %s", err)
+	}
+	return nil
+}
+
 func (s *Store) CARootsAndConfig(ws memdb.WatchSet) (uint64, structs.CARoots, *structs.CAConfiguration, error) {
 	tx := s.db.Txn(false)
 	defer tx.Abort()
@@ -4,8 +4,10 @@
 package state
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/consul/proto/private/prototest"
 	"github.com/hashicorp/consul/sdk/testutil"
@@ -495,3 +497,141 @@ func TestStore_CABuiltinProvider_Snapshot_Restore(t *testing.T) {
 		assert.Equal(t, state, res)
 	}
 }
+
+func TestStore_CALeafCerts_Revoke(t *testing.T) {
+	s := testStateStore(t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for i, name := range []string{"web", "db"} {
+		cert := &structs.IssuedCert{
+			SerialNumber: fmt.Sprintf("0%d", i+1),
+			Service:      name,
+			Node:         "node1",
+			ValidAfter:   now,
+			ValidBefore:  now.Add(time.Hour),
+		}
+		require.NoError(t, s.CALeafCertSet(uint64(i+1), cert))
+	}
+
+	ws := memdb.NewWatchSet()
+	idx, certs, err := s.CALeafCerts(ws)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), idx)
+	require.Len(t, certs, 2)
+
+	_, crls, err := s.CARevocationLists(ws)
+	require.NoError(t, err)
+	require.Empty(t, crls)
+
+	// Revoking a certificate sets the revocation lists in the same transaction.
+	revoked := &structs.IssuedCert{
+		SerialNumber:     "01",
+		RevokedAt:        &now,
+		RevocationReason: "compromised",
+	}
+	unknown := &structs.IssuedCert{SerialNumber: "ff", RevokedAt: &now}
+	expected := []*structs.CARevocationList{{PEM: "crl", SigningKeyID: "key", Revoked: 1}}
+	require.NoError(t, s.CARevokeLeafCerts(3, []*structs.IssuedCert{revoked, unknown}, expected))
+	require.True(t, watchFired(ws))
+
+	idx, cert, err := s.CALeafCert(nil, "01")
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), idx)
+	require.True(t, cert.IsRevoked())
+	require.Equal(t, "compromised", cert.RevocationReason)
+	require.Equal(t, "node1", cert.Node)
+	require.Equal(t, uint64(1), cert.CreateIndex)
+	require.Equal(t, uint64(3), cert.ModifyIndex)
+
+	_, cert, err = s.CALeafCert(nil, "ff")
+	require.NoError(t, err)
+	require.Nil(t, cert)
+
+	idx, crls, err = s.CARevocationLists(nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), idx)
+	require.Len(t, crls, 1)
+	require.Equal(t, "crl", crls[0].PEM)
+	require.Equal(t, uint64(3), crls[0].CreateIndex)
+
+	// Replacing the revocation lists keeps the create index of the ones of
+	// the same signing certificate.
+	require.NoError(t, s.CASetRevocationLists(4, []*structs.CARevocationList{
+		{PEM: "crl2", SigningKeyID: "key"},
+		{SigningKeyID: "old-key"},
+	}))
+	idx, crls, err = s.CARevocationLists(nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), idx)
+	require.Len(t, crls, 2)
+	require.Equal(t, "crl2", crls[0].PEM)
+	require.Equal(t, uint64(3), crls[0].CreateIndex)
+	require.Equal(t, uint64(4), crls[0].ModifyIndex)
+	require.Equal(t, "old-key", crls[1].SigningKeyID)
+	require.Equal(t, uint64(4), crls[1].CreateIndex)
+
+	// Deleting the records of the certificates and clearing the lists.
+	require.NoError(t, s.CADeleteLeafCerts(5, []*structs.IssuedCert{{SerialNumber: "01"}}))
+	require.NoError(t, s.CASetRevocationLists(6, nil))
+
+	_, certs, err = s.CALeafCerts(nil)
+	require.NoError(t, err)
+	require.Len(t, certs, 1)
+	require.Equal(t, "02", certs[0].SerialNumber)
+
+	idx, crls, err = s.CARevocationLists(nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), idx)
+	require.Empty(t, crls)
+}
+
+func TestStore_CALeafCerts_Snapshot_Restore(t *testing.T) {
+	s := testStateStore(t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	cert := &structs.IssuedCert{
+		SerialNumber: "01",
+		Service:      "web",
+		ValidBefore:  now.Add(time.Hour),
+		RevokedAt:    &now,
+	}
+	require.NoError(t, s.CALeafCertSet(1, cert))
+	crls := []*structs.CARevocationList{
+		{PEM: "crl", SigningKeyID: "key", Revoked: 1},
+		{PEM: "old-crl", SigningKeyID: "old-key"},
+	}
+	require.NoError(t, s.CASetRevocationLists(2, crls))
+
+	snap := s.Snapshot()
+	defer snap.Close()
+
+	// Alter the real state store.
+	require.NoError(t, s.CADeleteLeafCerts(3, []*structs.IssuedCert{cert}))
+	require.NoError(t, s.CASetRevocationLists(4, nil))
+
+	snapped, err := snap.CALeafCerts()
+	require.NoError(t, err)
+	require.Equal(t, []*structs.IssuedCert{cert}, snapped)
+	snappedCRLs, err := snap.CARevocationLists()
+	require.NoError(t, err)
+	require.Equal(t, crls, snappedCRLs)
+
+	// Restore the values into a new state store.
+	s2 := testStateStore(t)
+	restore := s2.Restore()
+	require.NoError(t, restore.CALeafCert(snapped[0]))
+	for _, crl := range snappedCRLs {
+		require.NoError(t, restore.CARevocationList(crl))
+	}
+	require.NoError(t, restore.Commit())
+
+	idx, certs, err := s2.CALeafCerts(nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), idx)
+	require.Equal(t, []*structs.IssuedCert{cert}, certs)
+
+	idx, restoredCRLs, err := s2.CARevocationLists(nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), idx)
+	require.Equal(t, crls, restoredCRLs)
+}
@@ -20,6 +20,8 @@ func newDBSchema() *memdb.DBSchema {
 		bindingRulesTableSchema,
 		caBuiltinProviderTableSchema,
 		caConfigTableSchema,
+		caLeafCertTableSchema,
+		caRevocationListTableSchema,
 		caRootTableSchema,
 		checksTableSchema,
 		configTableSchema,
@@ -45,6 +47,7 @@ func newDBSchema() *memdb.DBSchema {
 		sessionChecksTableSchema,
 		sessionsTableSchema,
 		systemMetadataTableSchema,
+		templatedPoliciesTableSchema,
 		tokensTableSchema,
 		tombstonesTableSchema,
 		usageTableSchema,
@@ -43,10 +43,11 @@ func TestNewDBSchema_Indexers(t *testing.T) {
 
 	var testcases = map[string]func() map[string]indexerTestCase{
 		// acl
-		tableACLBindingRules: testIndexerTableACLBindingRules,
-		tableACLPolicies:     testIndexerTableACLPolicies,
-		tableACLRoles:        testIndexerTableACLRoles,
-		tableACLTokens:       testIndexerTableACLTokens,
+		tableACLBindingRules:      testIndexerTableACLBindingRules,
+		tableACLPolicies:          testIndexerTableACLPolicies,
+		tableACLRoles:             testIndexerTableACLRoles,
+		tableACLTemplatedPolicies: testIndexerTableACLTemplatedPolicies,
+		tableACLTokens:            testIndexerTableACLTokens,
 		// catalog
 		tableChecks:            testIndexerTableChecks,
 		tableServices:          testIndexerTableServices,
@@ -104,6 +104,62 @@ func TestStore_IntegrationWithEventPublisher_ACLTokenUpdate(t *testing.T) {
 	require.Equal(t, stream.ErrSubForceClosed, err)
 }
 
+func TestStore_IntegrationWithEventPublisher_ACLTokenSecretReaped(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	s := testACLTokensStateStore(t)
+
+	// Setup token and wait for good state
+	token := createTokenAndWaitForACLEventPublish(t, s)
+
+	// Rotate the token.
+	rotated := token.Clone()
+	rotated.PreviousSecretID = token.SecretID
+	rotated.SecretID = "e2ad3ba6-1c2d-4a2a-9c5c-0b1a38b0d2f4"
+	expiration := time.Now().Add(time.Hour)
+	rotated.PreviousSecretExpirationTime = &expiration
+	rotated.SetHash(true)
+	require.NoError(t, s.ACLTokenBatchSet(3, structs.ACLTokens{rotated.Clone()}, ACLTokenSetOptions{AllowSecretRotation: true}))
+
+	// Register a subscription with the previous secret, which is still valid
+	// during the grace period.
+	subscription := &stream.SubscribeRequest{
+		Topic:   topicService,
+		Subject: stream.StringSubject("nope"),
+		Token:   token.SecretID,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	publisher := stream.NewEventPublisher(0)
+	registerTestSnapshotHandlers(t, s, publisher)
+	go publisher.Run(ctx)
+	s.db.publisher = publisher
+	sub, err := publisher.Subscribe(subscription)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	eventCh := testRunSub(sub)
+
+	// Stream should get EndOfSnapshot
+	e := assertEvent(t, eventCh)
+	require.True(t, e.IsEndOfSnapshot())
+
+	// Reap the previous secret.
+	reaped := rotated.Clone()
+	reaped.PreviousSecretID = ""
+	reaped.PreviousSecretExpirationTime = nil
+	reaped.SetHash(true)
+	require.NoError(t, s.ACLTokenBatchSet(4, structs.ACLTokens{reaped}, ACLTokenSetOptions{}))
+
+	// Ensure the reset event was sent.
+	err = assertErr(t, eventCh)
+	require.Equal(t, stream.ErrSubForceClosed, err)
+}
+
 func TestStore_IntegrationWithEventPublisher_ACLPolicyUpdate(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
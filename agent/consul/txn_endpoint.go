@@ -36,14 +36,14 @@ type Txn struct {
 
 // preCheck is used to verify the incoming operations before any further
 // processing takes place. This checks things like ACLs.
-func (t *Txn) preCheck(authorizer resolver.Result, ops structs.TxnOps) structs.TxnErrors {
+func (t *Txn) preCheck(authorizer resolver.Result, ops structs.TxnOps, sourceIP string) structs.TxnErrors {
 	var errors structs.TxnErrors
 
 	// Perform the pre-apply checks for any KV operations.
 	for i, op := range ops {
 		switch {
 		case op.KV != nil:
-			ok, err := kvsPreApply(t.logger, t.srv, authorizer, op.KV.Verb, &op.KV.DirEnt)
+			ok, err := kvsPreApply(t.logger, t.srv, authorizer, op.KV.Verb, &op.KV.DirEnt, sourceIP)
 			if err != nil {
 				errors = append(errors, &structs.TxnError{
 					OpIndex: i,
@@ -154,7 +154,7 @@ func (t *Txn) Apply(args *structs.TxnRequest, reply *structs.TxnResponse) error
 	if err != nil {
 		return err
 	}
-	reply.Errors = t.preCheck(authz, args.Ops)
+	reply.Errors = t.preCheck(authz, args.Ops, args.SourceIP)
 	if len(reply.Errors) > 0 {
 		return nil
 	}
@@ -206,7 +206,7 @@ func (t *Txn) Read(args *structs.TxnReadRequest, reply *structs.TxnReadResponse)
 	// KVCheckIndex, the txn fails and permission denied errors are returned.
 	//
 	// TODO: Maybe we should unify these, or at least cover it in the docs?
-	reply.Errors = t.preCheck(authz, args.Ops)
+	reply.Errors = t.preCheck(authz, args.Ops, "")
 	if len(reply.Errors) > 0 {
 		return nil
 	}
@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package httpcompressioncache
+
+import (
+	"fmt"
+
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/hashicorp/consul/api"
+	ext_cmn "github.com/hashicorp/consul/envoyextensions/extensioncommon"
+	"github.com/hashicorp/go-multierror"
+)
+
+type httpCompressionCache struct {
+	ext_cmn.BasicExtensionAdapter
+
+	// ProxyType identifies the type of Envoy proxy that this extension applies to.
+	// The extension will only be configured for proxies that match this type and
+	// will be ignored for all other proxy types.
+	ProxyType api.ServiceKind
+	// ListenerType controls which listener the extension applies to. It supports "inbound" or "outbound" listeners.
+	ListenerType string
+	// Config holds the extension configuration.
+	Config compressionCacheConfig
+}
+
+var _ ext_cmn.BasicExtension = (*httpCompressionCache)(nil)
+
+func Constructor(ext api.EnvoyExtension) (ext_cmn.EnvoyExtender, error) {
+	h, err := newHTTPCompressionCache(ext)
+	if err != nil {
+		return nil, err
+	}
+	return &ext_cmn.BasicEnvoyExtender{
+		Extension: h,
+	}, nil
+}
+
+// CanApply indicates if the http-compression-cache extension can be applied to the given extension runtime configuration.
+func (h *httpCompressionCache) CanApply(config *ext_cmn.RuntimeConfig) bool {
+	return config.Kind == api.ServiceKindConnectProxy
+}
+
+// Validate checks that the local service uses an HTTP protocol when the extension applies to its
+// inbound listener. The compressor and cache filters are HTTP filters, so they cannot be inserted
+// into the listener of a TCP service.
+func (h *httpCompressionCache) Validate(config *ext_cmn.RuntimeConfig) error {
+	if h.ListenerType != "inbound" || isHTTP(config.Protocol) {
+		return nil
+	}
+	protocol := config.Protocol
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	return fmt.Errorf("the %q extension only supports services with an HTTP protocol but service %q uses the %q protocol",
+		api.BuiltinHTTPCompressionCacheExtension, config.ServiceName.Name, protocol)
+}
+
+func (h *httpCompressionCache) matchesListenerDirection(isInboundListener bool) bool {
+	return (!isInboundListener && h.ListenerType == "outbound") || (isInboundListener && h.ListenerType == "inbound")
+}
+
+// PatchFilters inserts the compressor and cache filters into the filter chain of the HTTP connection
+// manager, immediately before the router filter. Outbound listeners of TCP upstreams don't have an
+// HTTP connection manager and are left unchanged.
+func (h *httpCompressionCache) PatchFilters(cfg *ext_cmn.RuntimeConfig, filters []*envoy_listener_v3.Filter, isInboundListener bool) ([]*envoy_listener_v3.Filter, error) {
+	if !h.matchesListenerDirection(isInboundListener) {
+		return filters, nil
+	}
+
+	if _, _, err := ext_cmn.GetHTTPConnectionManager(filters...); err != nil {
+		if isInboundListener {
+			return filters, fmt.Errorf("the %q extension can only be applied to HTTP listeners: %w",
+				api.BuiltinHTTPCompressionCacheExtension, err)
+		}
+		return filters, nil
+	}
+
+	httpFilters, err := h.Config.toEnvoyHttpFilters()
+	if err != nil {
+		return filters, err
+	}
+
+	// Inserting each filter before the router keeps them in order. Responses go through the filters
+	// in reverse order, so the cache stores uncompressed responses and the compressor encodes them
+	// based on the Accept-Encoding header of each request.
+	insertOptions := ext_cmn.InsertOptions{
+		Location:   ext_cmn.InsertBeforeFirstMatch,
+		FilterName: "envoy.filters.http.router",
+	}
+	for _, f := range httpFilters {
+		filters, err = ext_cmn.InsertHTTPFilter(filters, f, insertOptions)
+		if err != nil {
+			return filters, err
+		}
+	}
+	return filters, nil
+}
+
+func newHTTPCompressionCache(ext api.EnvoyExtension) (*httpCompressionCache, error) {
+	h := &httpCompressionCache{}
+	if ext.Name != api.BuiltinHTTPCompressionCacheExtension {
+		return h, fmt.Errorf("expected extension name %q but got %q", api.BuiltinHTTPCompressionCacheExtension, ext.Name)
+	}
+	if err := h.fromArguments(ext.Arguments); err != nil {
+		return h, err
+	}
+	return h, nil
+}
+
+func (h *httpCompressionCache) fromArguments(args map[string]any) error {
+	if err := mapstructure.Decode(args, h); err != nil {
+		return err
+	}
+	h.normalize()
+	return h.validate()
+}
+
+func (h *httpCompressionCache) normalize() {
+	if h.ProxyType == "" {
+		h.ProxyType = api.ServiceKindConnectProxy
+	}
+
+	if h.ListenerType == "" {
+		h.ListenerType = "inbound"
+	}
+
+	h.Config.normalize()
+}
+
+func (h *httpCompressionCache) validate() error {
+	var resultErr error
+	if h.ProxyType != api.ServiceKindConnectProxy {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("unsupported ProxyType %q, only %q is supported",
+			h.ProxyType,
+			api.ServiceKindConnectProxy))
+	}
+
+	if h.ListenerType != "inbound" && h.ListenerType != "outbound" {
+		resultErr = multierror.Append(resultErr, fmt.Errorf(`unexpected ListenerType %q, supported values are "inbound" or "outbound"`, h.ListenerType))
+	}
+
+	if err := h.Config.validate(); err != nil {
+		resultErr = multierror.Append(resultErr, err)
+	}
+
+	return resultErr
+}
+
+func isHTTP(protocol string) bool {
+	switch protocol {
+	case "grpc", "http2", "http":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,228 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package httpcompressioncache
+
+import (
+	"testing"
+
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_http_compressor_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/compressor/v3"
+	envoy_http_router_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_tcp_proxy_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+func TestConstructor(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		extName string
+		args    map[string]any
+		errMsg  string
+	}{
+		"invalid name": {
+			extName: "invalid",
+			errMsg:  `expected extension name "builtin/http-compression-cache"`,
+		},
+		"invalid proxy type": {
+			args:   map[string]any{"ProxyType": "invalid"},
+			errMsg: `unsupported ProxyType`,
+		},
+		"invalid listener type": {
+			args:   map[string]any{"ListenerType": "invalid"},
+			errMsg: `unexpected ListenerType "invalid"`,
+		},
+		"empty config": {
+			args:   map[string]any{"ProxyType": "connect-proxy"},
+			errMsg: `at least one of Compression or Cache must be set`,
+		},
+		"unsupported algorithm": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Compression": map[string]any{"Algorithms": []string{"zstd"}},
+				},
+			},
+			errMsg: `unsupported algorithm "zstd"`,
+		},
+		"duplicate algorithm": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Compression": map[string]any{"Algorithms": []string{"gzip", "GZIP"}},
+				},
+			},
+			errMsg: `duplicate algorithm "gzip"`,
+		},
+		"invalid content type": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Compression": map[string]any{"ContentTypes": []string{"json"}},
+				},
+			},
+			errMsg: `invalid content type "json"`,
+		},
+		"empty vary header": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Cache": map[string]any{"AllowedVaryHeaders": []string{""}},
+				},
+			},
+			errMsg: `failed to validate Config.Cache`,
+		},
+		"valid": {
+			args: map[string]any{
+				"ListenerType": "outbound",
+				"Config": map[string]any{
+					"Compression": map[string]any{
+						"Algorithms":       []string{"brotli", "gzip"},
+						"ContentTypes":     []string{"application/json"},
+						"MinContentLength": 1024,
+					},
+					"Cache": map[string]any{
+						"MaxBodyBytes":       1048576,
+						"AllowedVaryHeaders": []string{"accept-language"},
+					},
+				},
+			},
+		},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			extName := api.BuiltinHTTPCompressionCacheExtension
+			if c.extName != "" {
+				extName = c.extName
+			}
+			_, err := newHTTPCompressionCache(api.EnvoyExtension{Name: extName, Arguments: c.args})
+			if c.errMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.errMsg)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		listenerType string
+		protocol     string
+		errMsg       string
+	}{
+		"inbound http": {
+			listenerType: "inbound",
+			protocol:     "http",
+		},
+		"inbound grpc": {
+			listenerType: "inbound",
+			protocol:     "grpc",
+		},
+		"inbound tcp": {
+			listenerType: "inbound",
+			protocol:     "tcp",
+			errMsg:       `the "builtin/http-compression-cache" extension only supports services with an HTTP protocol but service "web" uses the "tcp" protocol`,
+		},
+		"inbound default protocol": {
+			listenerType: "inbound",
+			errMsg:       `service "web" uses the "tcp" protocol`,
+		},
+		"outbound tcp": {
+			listenerType: "outbound",
+			protocol:     "tcp",
+		},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			ext, err := newHTTPCompressionCache(api.EnvoyExtension{
+				Name: api.BuiltinHTTPCompressionCacheExtension,
+				Arguments: map[string]any{
+					"ListenerType": c.listenerType,
+					"Config": map[string]any{
+						"Cache": map[string]any{},
+					},
+				},
+			})
+			require.NoError(t, err)
+
+			err = ext.Validate(&extensioncommon.RuntimeConfig{
+				ServiceName: api.CompoundServiceName{Name: "web"},
+				Protocol:    c.protocol,
+			})
+			if c.errMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, c.errMsg)
+			}
+		})
+	}
+}
+
+func TestPatchFilters(t *testing.T) {
+	t.Parallel()
+	ext, err := newHTTPCompressionCache(api.EnvoyExtension{
+		Name: api.BuiltinHTTPCompressionCacheExtension,
+		Arguments: map[string]any{
+			"Config": map[string]any{
+				"Compression": map[string]any{
+					"Algorithms":       []string{"brotli", "gzip"},
+					"ContentTypes":     []string{"application/json"},
+					"MinContentLength": 1024,
+				},
+				"Cache": map[string]any{},
+			},
+		},
+	})
+	require.NoError(t, err)
+	cfg := &extensioncommon.RuntimeConfig{Protocol: "http"}
+
+	t.Run("http", func(t *testing.T) {
+		router, err := extensioncommon.MakeEnvoyHTTPFilter("envoy.filters.http.router", &envoy_http_router_v3.Router{})
+		require.NoError(t, err)
+		hcm, err := extensioncommon.MakeFilter("envoy.filters.network.http_connection_manager", &envoy_http_v3.HttpConnectionManager{
+			HttpFilters: []*envoy_http_v3.HttpFilter{router},
+		})
+		require.NoError(t, err)
+
+		filters, err := ext.PatchFilters(cfg, []*envoy_listener_v3.Filter{hcm}, true)
+		require.NoError(t, err)
+
+		httpConnMgr, _, err := extensioncommon.GetHTTPConnectionManager(filters...)
+		require.NoError(t, err)
+		var names []string
+		for _, f := range httpConnMgr.HttpFilters {
+			names = append(names, f.Name)
+		}
+		require.Equal(t, []string{
+			"envoy.filters.http.compressor.brotli",
+			"envoy.filters.http.compressor.gzip",
+			"envoy.filters.http.cache",
+			"envoy.filters.http.router",
+		}, names)
+
+		var brotli envoy_http_compressor_v3.Compressor
+		require.NoError(t, httpConnMgr.HttpFilters[0].GetTypedConfig().UnmarshalTo(&brotli))
+		require.True(t, brotli.ChooseFirst)
+		require.Equal(t, "envoy.compression.brotli.compressor", brotli.CompressorLibrary.Name)
+		require.Equal(t, []string{"application/json"}, brotli.ResponseDirectionConfig.CommonConfig.ContentType)
+		require.Equal(t, uint32(1024), brotli.ResponseDirectionConfig.CommonConfig.MinContentLength.GetValue())
+
+		// Outbound listeners are left unchanged when the extension applies to the inbound listener.
+		unchanged, err := ext.PatchFilters(cfg, []*envoy_listener_v3.Filter{hcm}, false)
+		require.NoError(t, err)
+		require.Equal(t, []*envoy_listener_v3.Filter{hcm}, unchanged)
+	})
+
+	t.Run("tcp", func(t *testing.T) {
+		tcpProxy, err := extensioncommon.MakeFilter("envoy.filters.network.tcp_proxy", &envoy_tcp_proxy_v3.TcpProxy{})
+		require.NoError(t, err)
+
+		_, err = ext.PatchFilters(cfg, []*envoy_listener_v3.Filter{tcpProxy}, true)
+		require.ErrorContains(t, err, `the "builtin/http-compression-cache" extension can only be applied to HTTP listeners`)
+	})
+}
@@ -0,0 +1,201 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package httpcompressioncache
+
+import (
+	"fmt"
+	"strings"
+
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_brotli_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/compression/brotli/compressor/v3"
+	envoy_gzip_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/compression/gzip/compressor/v3"
+	envoy_http_cache_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/cache/v3"
+	envoy_http_compressor_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/compressor/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_simple_http_cache_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/http/cache/simple_http_cache/v3"
+	envoy_type_matcher_v3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/hashicorp/go-multierror"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	cmn "github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+const (
+	compressorFilterName = "envoy.filters.http.compressor"
+	cacheFilterName      = "envoy.filters.http.cache"
+
+	algorithmGzip   = "gzip"
+	algorithmBrotli = "brotli"
+)
+
+type compressionCacheConfig struct {
+	Compression *Compression
+	Cache       *Cache
+}
+
+func (c *compressionCacheConfig) normalize() {
+	if c.Compression != nil {
+		c.Compression.normalize()
+	}
+}
+
+func (c *compressionCacheConfig) validate() error {
+	var resultErr error
+	if c.Compression == nil && c.Cache == nil {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("at least one of Compression or Cache must be set"))
+	}
+	if c.Compression != nil {
+		if err := c.Compression.validate(); err != nil {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("failed to validate Config.Compression: %w", err))
+		}
+	}
+	if c.Cache != nil {
+		if err := c.Cache.validate(); err != nil {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("failed to validate Config.Cache: %w", err))
+		}
+	}
+	return resultErr
+}
+
+// toEnvoyHttpFilters returns the HTTP filters to insert, in order: one compressor filter per
+// algorithm followed by the cache filter.
+func (c compressionCacheConfig) toEnvoyHttpFilters() ([]*envoy_http_v3.HttpFilter, error) {
+	var filters []*envoy_http_v3.HttpFilter
+	if c.Compression != nil {
+		for i, algorithm := range c.Compression.Algorithms {
+			f, err := c.Compression.toEnvoyHttpFilter(algorithm, i == 0)
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, f)
+		}
+	}
+	if c.Cache != nil {
+		f, err := c.Cache.toEnvoyHttpFilter()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+// Compression configures the compression of the responses.
+type Compression struct {
+	// Algorithms lists the compression algorithms in order of preference. Envoy picks the first one
+	// when the client accepts several with the same quality value.
+	Algorithms []string
+	// ContentTypes lists the content types of the responses to compress. When empty, Envoy compresses
+	// a default set of text, JSON and XML content types.
+	ContentTypes []string
+	// MinContentLength is the minimum size in bytes of the responses to compress. When zero, Envoy
+	// only compresses responses of at least 30 bytes.
+	MinContentLength uint32
+}
+
+func (c *Compression) normalize() {
+	if len(c.Algorithms) == 0 {
+		c.Algorithms = []string{algorithmGzip}
+	}
+	for i, a := range c.Algorithms {
+		c.Algorithms[i] = strings.ToLower(a)
+	}
+}
+
+func (c *Compression) validate() error {
+	var resultErr error
+	seen := make(map[string]struct{}, len(c.Algorithms))
+	for _, a := range c.Algorithms {
+		if a != algorithmGzip && a != algorithmBrotli {
+			resultErr = multierror.Append(resultErr, fmt.Errorf(`unsupported algorithm %q, supported values are "gzip" or "brotli"`, a))
+			continue
+		}
+		if _, ok := seen[a]; ok {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("duplicate algorithm %q", a))
+		}
+		seen[a] = struct{}{}
+	}
+	for _, ct := range c.ContentTypes {
+		if !strings.Contains(ct, "/") {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("invalid content type %q, expected a media type such as %q", ct, "application/json"))
+		}
+	}
+	return resultErr
+}
+
+func (c *Compression) toEnvoyHttpFilter(algorithm string, chooseFirst bool) (*envoy_http_v3.HttpFilter, error) {
+	var library proto.Message
+	switch algorithm {
+	case algorithmBrotli:
+		library = &envoy_brotli_v3.Brotli{}
+	default:
+		library = &envoy_gzip_v3.Gzip{}
+	}
+	libraryAny, err := anypb.New(library)
+	if err != nil {
+		return nil, err
+	}
+
+	common := &envoy_http_compressor_v3.Compressor_CommonDirectionConfig{
+		ContentType: c.ContentTypes,
+	}
+	if c.MinContentLength > 0 {
+		common.MinContentLength = wrapperspb.UInt32(c.MinContentLength)
+	}
+	// Envoy looks up filters by the type of their configuration, so suffix the name with the
+	// algorithm to tell the compressor filters apart.
+	return cmn.MakeEnvoyHTTPFilter(compressorFilterName+"."+algorithm, &envoy_http_compressor_v3.Compressor{
+		CompressorLibrary: &envoy_core_v3.TypedExtensionConfig{
+			Name:        fmt.Sprintf("envoy.compression.%s.compressor", algorithm),
+			TypedConfig: libraryAny,
+		},
+		ResponseDirectionConfig: &envoy_http_compressor_v3.Compressor_ResponseDirectionConfig{
+			CommonConfig: common,
+		},
+		ChooseFirst: chooseFirst,
+	})
+}
+
+// Cache configures the caching of the responses in memory. Envoy follows the caching headers of
+// the responses, as specified by RFC 7234, to decide which responses to cache and for how long.
+type Cache struct {
+	// MaxBodyBytes is the maximum size in bytes of the body of the responses to cache. When zero,
+	// the size of the cached responses is not limited.
+	MaxBodyBytes uint32
+	// AllowedVaryHeaders lists the headers that the responses may vary on. Envoy doesn't cache the
+	// responses that have a Vary header with other headers.
+	AllowedVaryHeaders []string
+}
+
+func (c *Cache) validate() error {
+	var resultErr error
+	for _, h := range c.AllowedVaryHeaders {
+		if h == "" {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("AllowedVaryHeaders must not contain empty header names"))
+		}
+	}
+	return resultErr
+}
+
+func (c *Cache) toEnvoyHttpFilter() (*envoy_http_v3.HttpFilter, error) {
+	storage, err := anypb.New(&envoy_simple_http_cache_v3.SimpleHttpCacheConfig{})
+	if err != nil {
+		return nil, err
+	}
+
+	var varyHeaders []*envoy_type_matcher_v3.StringMatcher
+	for _, h := range c.AllowedVaryHeaders {
+		varyHeaders = append(varyHeaders, &envoy_type_matcher_v3.StringMatcher{
+			MatchPattern: &envoy_type_matcher_v3.StringMatcher_Exact{Exact: h},
+			IgnoreCase:   true,
+		})
+	}
+	return cmn.MakeEnvoyHTTPFilter(cacheFilterName, &envoy_http_cache_v3.CacheConfig{
+		TypedConfig:        storage,
+		AllowedVaryHeaders: varyHeaders,
+		MaxBodyBytes:       c.MaxBodyBytes,
+	})
+}
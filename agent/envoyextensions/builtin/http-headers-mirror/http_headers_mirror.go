@@ -0,0 +1,172 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package httpheadersmirror
+
+import (
+	"fmt"
+
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/hashicorp/consul/api"
+	ext_cmn "github.com/hashicorp/consul/envoyextensions/extensioncommon"
+	"github.com/hashicorp/go-multierror"
+)
+
+type httpHeadersMirror struct {
+	ext_cmn.BasicExtensionAdapter
+
+	// ProxyType identifies the type of Envoy proxy that this extension applies to.
+	// The extension will only be configured for proxies that match this type and
+	// will be ignored for all other proxy types.
+	ProxyType api.ServiceKind
+	// ListenerType controls which listener the extension applies to. It supports "inbound" or "outbound" listeners.
+	ListenerType string
+	// Config holds the extension configuration.
+	Config headersMirrorConfig
+}
+
+var _ ext_cmn.BasicExtension = (*httpHeadersMirror)(nil)
+
+func Constructor(ext api.EnvoyExtension) (ext_cmn.EnvoyExtender, error) {
+	h, err := newHTTPHeadersMirror(ext)
+	if err != nil {
+		return nil, err
+	}
+	return &ext_cmn.BasicEnvoyExtender{
+		Extension: h,
+	}, nil
+}
+
+// CanApply indicates if the http-headers-mirror extension can be applied to the given extension runtime configuration.
+func (h *httpHeadersMirror) CanApply(config *ext_cmn.RuntimeConfig) bool {
+	return config.Kind == api.ServiceKindConnectProxy
+}
+
+// Validate checks that the extension can be applied to the local proxy. The routes of the inbound
+// listener only exist for HTTP services, and the mirror service must be an upstream of the local
+// service since Envoy mirrors the requests to its cluster.
+func (h *httpHeadersMirror) Validate(config *ext_cmn.RuntimeConfig) error {
+	var resultErr error
+	if h.ListenerType == "inbound" && !isHTTP(config.Protocol) {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("inbound listener of service %q must use an HTTP protocol, got %q",
+			config.ServiceName.Name, config.Protocol))
+	}
+	if h.Config.Mirror != nil {
+		if _, err := h.Config.Mirror.clusterName(config); err != nil {
+			resultErr = multierror.Append(resultErr, err)
+		}
+	}
+	return resultErr
+}
+
+// PatchRoute adds the header modifiers and the mirror policy to the routes discovered through RDS for
+// the listeners the extension applies to. Routes that are defined inline in the HTTP connection
+// manager are patched by PatchFilters.
+func (h *httpHeadersMirror) PatchRoute(p ext_cmn.RoutePayload) (*envoy_route_v3.RouteConfiguration, bool, error) {
+	if !h.matchesListenerDirection(p.IsInbound()) {
+		return p.Message, false, nil
+	}
+
+	patched, err := h.Config.patchRouteConfiguration(p.RuntimeConfig, p.Message)
+	if err != nil {
+		return p.Message, false, err
+	}
+	return p.Message, patched, nil
+}
+
+func (h *httpHeadersMirror) matchesListenerDirection(isInboundListener bool) bool {
+	return (!isInboundListener && h.ListenerType == "outbound") || (isInboundListener && h.ListenerType == "inbound")
+}
+
+// PatchFilters adds the header modifiers and the mirror policy to the route configuration defined
+// inline in the HTTP connection manager, if any. It does nothing for TCP listeners.
+func (h *httpHeadersMirror) PatchFilters(cfg *ext_cmn.RuntimeConfig, filters []*envoy_listener_v3.Filter, isInboundListener bool) ([]*envoy_listener_v3.Filter, error) {
+	if !h.matchesListenerDirection(isInboundListener) {
+		return filters, nil
+	}
+
+	httpConnMgr, idx, err := ext_cmn.GetHTTPConnectionManager(filters...)
+	if err != nil {
+		// Only HTTP listeners have routes.
+		return filters, nil
+	}
+	routeConfig := httpConnMgr.GetRouteConfig()
+	if routeConfig == nil {
+		return filters, nil
+	}
+
+	patched, err := h.Config.patchRouteConfiguration(cfg, routeConfig)
+	if err != nil || !patched {
+		return filters, err
+	}
+
+	newHttpConMan, err := ext_cmn.MakeFilter("envoy.filters.network.http_connection_manager", httpConnMgr)
+	if err != nil {
+		return filters, fmt.Errorf("failed to patch the routes of the HTTP connection manager filter: %w", err)
+	}
+	filtersCopy := make([]*envoy_listener_v3.Filter, len(filters))
+	copy(filtersCopy, filters)
+	filtersCopy[idx] = newHttpConMan
+	return filtersCopy, nil
+}
+
+func newHTTPHeadersMirror(ext api.EnvoyExtension) (*httpHeadersMirror, error) {
+	h := &httpHeadersMirror{}
+	if ext.Name != api.BuiltinHTTPHeadersMirrorExtension {
+		return h, fmt.Errorf("expected extension name %q but got %q", api.BuiltinHTTPHeadersMirrorExtension, ext.Name)
+	}
+	if err := h.fromArguments(ext.Arguments); err != nil {
+		return h, err
+	}
+	return h, nil
+}
+
+func (h *httpHeadersMirror) fromArguments(args map[string]any) error {
+	if err := mapstructure.Decode(args, h); err != nil {
+		return err
+	}
+	h.normalize()
+	return h.validate()
+}
+
+func (h *httpHeadersMirror) normalize() {
+	if h.ProxyType == "" {
+		h.ProxyType = api.ServiceKindConnectProxy
+	}
+
+	if h.ListenerType == "" {
+		h.ListenerType = "inbound"
+	}
+
+	h.Config.normalize()
+}
+
+func (h *httpHeadersMirror) validate() error {
+	var resultErr error
+	if h.ProxyType != api.ServiceKindConnectProxy {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("unsupported ProxyType %q, only %q is supported",
+			h.ProxyType,
+			api.ServiceKindConnectProxy))
+	}
+
+	if h.ListenerType != "inbound" && h.ListenerType != "outbound" {
+		resultErr = multierror.Append(resultErr, fmt.Errorf(`unexpected ListenerType %q, supported values are "inbound" or "outbound"`, h.ListenerType))
+	}
+
+	if err := h.Config.validate(); err != nil {
+		resultErr = multierror.Append(resultErr, err)
+	}
+
+	return resultErr
+}
+
+func isHTTP(protocol string) bool {
+	switch protocol {
+	case "grpc", "http2", "http":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,241 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package httpheadersmirror
+
+import (
+	"testing"
+
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+func TestConstructor(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		extName string
+		args    map[string]any
+		errMsg  string
+	}{
+		"invalid name": {
+			extName: "invalid",
+			errMsg:  `expected extension name "builtin/http-headers-mirror"`,
+		},
+		"invalid proxy type": {
+			args:   map[string]any{"ProxyType": "invalid"},
+			errMsg: `unsupported ProxyType`,
+		},
+		"invalid listener type": {
+			args:   map[string]any{"ListenerType": "invalid"},
+			errMsg: `unexpected ListenerType "invalid"`,
+		},
+		"empty config": {
+			args:   map[string]any{"ProxyType": "connect-proxy"},
+			errMsg: `at least one of RequestHeaders, ResponseHeaders or Mirror must be set`,
+		},
+		"pseudo-header": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"RequestHeaders": map[string]any{
+						"Set": map[string]any{":path": "/"},
+					},
+				},
+			},
+			errMsg: `invalid header in Set: pseudo-header ":path" cannot be modified`,
+		},
+		"remove host header": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"RequestHeaders": map[string]any{
+						"Remove": []string{"Host"},
+					},
+				},
+			},
+			errMsg: `the "Host" header cannot be removed`,
+		},
+		"empty header name": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"ResponseHeaders": map[string]any{
+						"Remove": []string{""},
+					},
+				},
+			},
+			errMsg: `failed to validate Config.ResponseHeaders`,
+		},
+		"mirror without service": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Mirror": map[string]any{"Percent": 10},
+				},
+			},
+			errMsg: `Service.Name must be set`,
+		},
+		"mirror percent out of range": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Mirror": map[string]any{
+						"Service": map[string]any{"Name": "shadow"},
+						"Percent": 150,
+					},
+				},
+			},
+			errMsg: `Percent must be greater than 0 and at most 100, got 150`,
+		},
+		"valid": {
+			args: map[string]any{
+				"ListenerType": "outbound",
+				"Config": map[string]any{
+					"RequestHeaders": map[string]any{
+						"Add":    map[string]any{"x-request-source": "mesh"},
+						"Remove": []string{"x-debug"},
+					},
+					"Mirror": map[string]any{
+						"Service": map[string]any{"Name": "shadow"},
+						"Percent": 12.5,
+					},
+				},
+			},
+		},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			extName := api.BuiltinHTTPHeadersMirrorExtension
+			if c.extName != "" {
+				extName = c.extName
+			}
+			_, err := newHTTPHeadersMirror(api.EnvoyExtension{Name: extName, Arguments: c.args})
+			if c.errMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.errMsg)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	shadow := api.CompoundServiceName{Name: "shadow", Namespace: "default", Partition: "default"}
+	upstreams := map[api.CompoundServiceName]*extensioncommon.UpstreamData{
+		shadow: {PrimarySNI: "shadow.default.dc1.internal.consul"},
+	}
+	cases := map[string]struct {
+		listenerType string
+		protocol     string
+		mirror       string
+		errMsg       string
+	}{
+		"inbound http": {
+			listenerType: "inbound",
+			protocol:     "http",
+			mirror:       "shadow",
+		},
+		"inbound tcp": {
+			listenerType: "inbound",
+			protocol:     "tcp",
+			mirror:       "shadow",
+			errMsg:       `inbound listener of service "web" must use an HTTP protocol, got "tcp"`,
+		},
+		"outbound tcp": {
+			listenerType: "outbound",
+			protocol:     "tcp",
+			mirror:       "shadow",
+		},
+		"mirror service is not an upstream": {
+			listenerType: "inbound",
+			protocol:     "http",
+			mirror:       "other",
+			errMsg:       `no upstream definition found for mirror service "other"`,
+		},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			ext, err := newHTTPHeadersMirror(api.EnvoyExtension{
+				Name: api.BuiltinHTTPHeadersMirrorExtension,
+				Arguments: map[string]any{
+					"ListenerType": c.listenerType,
+					"Config": map[string]any{
+						"Mirror": map[string]any{
+							"Service": map[string]any{"Name": c.mirror},
+						},
+					},
+				},
+			})
+			require.NoError(t, err)
+
+			err = ext.Validate(&extensioncommon.RuntimeConfig{
+				ServiceName: api.CompoundServiceName{Name: "web"},
+				Protocol:    c.protocol,
+				Upstreams:   upstreams,
+			})
+			if c.errMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, c.errMsg)
+			}
+		})
+	}
+}
+
+func TestPatchRouteConfiguration(t *testing.T) {
+	t.Parallel()
+	ext, err := newHTTPHeadersMirror(api.EnvoyExtension{
+		Name: api.BuiltinHTTPHeadersMirrorExtension,
+		Arguments: map[string]any{
+			"Config": map[string]any{
+				"RequestHeaders": map[string]any{
+					"Add": map[string]any{"x-b": "2", "x-a": "1"},
+					"Set": map[string]any{"x-c": "3"},
+				},
+				"ResponseHeaders": map[string]any{
+					"Remove": []string{"server"},
+				},
+				"Mirror": map[string]any{
+					"Service": map[string]any{"Name": "shadow"},
+					"Percent": 12.5,
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	cfg := &extensioncommon.RuntimeConfig{
+		Upstreams: map[api.CompoundServiceName]*extensioncommon.UpstreamData{
+			{Name: "shadow", Namespace: "default", Partition: "default"}: {PrimarySNI: "shadow.default.dc1.internal.consul"},
+		},
+	}
+	rc := &envoy_route_v3.RouteConfiguration{
+		VirtualHosts: []*envoy_route_v3.VirtualHost{{
+			Routes: []*envoy_route_v3.Route{
+				{Action: &envoy_route_v3.Route_Route{Route: &envoy_route_v3.RouteAction{}}},
+				{Action: &envoy_route_v3.Route_Redirect{Redirect: &envoy_route_v3.RedirectAction{}}},
+			},
+		}},
+	}
+
+	patched, err := ext.Config.patchRouteConfiguration(cfg, rc)
+	require.NoError(t, err)
+	require.True(t, patched)
+
+	var added []string
+	for _, h := range rc.RequestHeadersToAdd {
+		added = append(added, h.Header.Key)
+		require.Equal(t, h.Header.Key != "x-c", h.Append.GetValue())
+	}
+	require.Equal(t, []string{"x-a", "x-b", "x-c"}, added)
+	require.Equal(t, []string{"server"}, rc.ResponseHeadersToRemove)
+
+	policies := rc.VirtualHosts[0].Routes[0].GetRoute().RequestMirrorPolicies
+	require.Len(t, policies, 1)
+	require.Equal(t, "shadow.default.dc1.internal.consul", policies[0].Cluster)
+	require.Equal(t, uint32(125000), policies[0].RuntimeFraction.DefaultValue.Numerator)
+	require.Equal(t, envoy_type_v3.FractionalPercent_MILLION, policies[0].RuntimeFraction.DefaultValue.Denominator)
+}
@@ -0,0 +1,218 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package httpheadersmirror
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/hashicorp/go-multierror"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/api"
+	cmn "github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+type headersMirrorConfig struct {
+	RequestHeaders  *HeaderModifiers
+	ResponseHeaders *HeaderModifiers
+	Mirror          *Mirror
+}
+
+func (c *headersMirrorConfig) normalize() {
+	if c.Mirror != nil {
+		c.Mirror.normalize()
+	}
+}
+
+func (c *headersMirrorConfig) validate() error {
+	c.normalize()
+
+	var resultErr error
+	if c.RequestHeaders == nil && c.ResponseHeaders == nil && c.Mirror == nil {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("at least one of RequestHeaders, ResponseHeaders or Mirror must be set"))
+	}
+	if c.RequestHeaders != nil {
+		if err := c.RequestHeaders.validate(); err != nil {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("failed to validate Config.RequestHeaders: %w", err))
+		}
+	}
+	if c.ResponseHeaders != nil {
+		if err := c.ResponseHeaders.validate(); err != nil {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("failed to validate Config.ResponseHeaders: %w", err))
+		}
+	}
+	if c.Mirror != nil {
+		if err := c.Mirror.validate(); err != nil {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("failed to validate Config.Mirror: %w", err))
+		}
+	}
+	return resultErr
+}
+
+// patchRouteConfiguration adds the header modifiers to the route configuration, so they apply to
+// all the requests of the listener, and adds the mirror policy to each of its routes.
+func (c headersMirrorConfig) patchRouteConfiguration(cfg *cmn.RuntimeConfig, rc *envoy_route_v3.RouteConfiguration) (bool, error) {
+	patched := false
+	if c.RequestHeaders != nil {
+		rc.RequestHeadersToAdd = append(rc.RequestHeadersToAdd, c.RequestHeaders.toEnvoy()...)
+		rc.RequestHeadersToRemove = append(rc.RequestHeadersToRemove, c.RequestHeaders.Remove...)
+		patched = true
+	}
+	if c.ResponseHeaders != nil {
+		rc.ResponseHeadersToAdd = append(rc.ResponseHeadersToAdd, c.ResponseHeaders.toEnvoy()...)
+		rc.ResponseHeadersToRemove = append(rc.ResponseHeadersToRemove, c.ResponseHeaders.Remove...)
+		patched = true
+	}
+
+	if c.Mirror == nil {
+		return patched, nil
+	}
+	policy, err := c.Mirror.toEnvoy(cfg)
+	if err != nil {
+		return false, err
+	}
+	for _, vh := range rc.VirtualHosts {
+		for _, route := range vh.Routes {
+			// Redirects and direct responses are not sent upstream so there is nothing to mirror.
+			if action := route.GetRoute(); action != nil {
+				action.RequestMirrorPolicies = append(action.RequestMirrorPolicies, policy)
+				patched = true
+			}
+		}
+	}
+	return patched, nil
+}
+
+// HeaderModifiers is the same as the request and response header modifiers of the route
+// destinations of a service-router configuration entry.
+type HeaderModifiers struct {
+	Add    map[string]string
+	Set    map[string]string
+	Remove []string
+}
+
+// toEnvoy returns the headers to add, in a deterministic order. The headers of Add are appended to
+// the existing values while the headers of Set replace them.
+func (h *HeaderModifiers) toEnvoy() []*envoy_core_v3.HeaderValueOption {
+	var opts []*envoy_core_v3.HeaderValueOption
+	opts = append(opts, makeHeaderValueOptions(h.Add, true)...)
+	opts = append(opts, makeHeaderValueOptions(h.Set, false)...)
+	return opts
+}
+
+func (h *HeaderModifiers) validate() error {
+	var resultErr error
+	for _, field := range []struct {
+		name string
+		vals map[string]string
+	}{{"Add", h.Add}, {"Set", h.Set}} {
+		for k := range field.vals {
+			if err := validateHeaderName(k); err != nil {
+				resultErr = multierror.Append(resultErr, fmt.Errorf("invalid header in %s: %w", field.name, err))
+			}
+		}
+	}
+	for _, k := range h.Remove {
+		if err := validateHeaderName(k); err != nil {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("invalid header in Remove: %w", err))
+		} else if strings.EqualFold(k, "host") {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("invalid header in Remove: the %q header cannot be removed", k))
+		}
+	}
+	return resultErr
+}
+
+func validateHeaderName(name string) error {
+	if name == "" {
+		return fmt.Errorf("header name must not be empty")
+	}
+	if strings.HasPrefix(name, ":") {
+		return fmt.Errorf("pseudo-header %q cannot be modified", name)
+	}
+	return nil
+}
+
+func makeHeaderValueOptions(vals map[string]string, add bool) []*envoy_core_v3.HeaderValueOption {
+	keys := make([]string, 0, len(vals))
+	for k := range vals {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	opts := make([]*envoy_core_v3.HeaderValueOption, 0, len(vals))
+	for _, k := range keys {
+		opts = append(opts, &envoy_core_v3.HeaderValueOption{
+			Header: &envoy_core_v3.HeaderValue{
+				Key:   k,
+				Value: vals[k],
+			},
+			Append: wrapperspb.Bool(add),
+		})
+	}
+	return opts
+}
+
+// Mirror configures Envoy to send a copy of a percentage of the requests to an upstream service.
+// Envoy doesn't wait for the responses of the mirrored requests and discards them.
+type Mirror struct {
+	Service      api.CompoundServiceName
+	Percent      *float64
+	TraceSampled *bool
+}
+
+func (m *Mirror) normalize() {
+	m.Service.Namespace = acl.NamespaceOrDefault(m.Service.Namespace)
+	m.Service.Partition = acl.PartitionOrDefault(m.Service.Partition)
+}
+
+func (m *Mirror) validate() error {
+	var resultErr error
+	if m.Service.Name == "" {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("Service.Name must be set"))
+	}
+	if m.Percent != nil && (*m.Percent <= 0 || *m.Percent > 100) {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("Percent must be greater than 0 and at most 100, got %v", *m.Percent))
+	}
+	return resultErr
+}
+
+// clusterName returns the name of the cluster of the primary target of the mirror upstream service.
+// It returns a non-nil error if the service is not an upstream of the local service.
+func (m *Mirror) clusterName(cfg *cmn.RuntimeConfig) (string, error) {
+	upstream, ok := cfg.Upstreams[m.Service]
+	if !ok || upstream.PrimarySNI == "" {
+		return "", fmt.Errorf("no upstream definition found for mirror service %q", m.Service.Name)
+	}
+	return upstream.PrimarySNI, nil
+}
+
+func (m *Mirror) toEnvoy(cfg *cmn.RuntimeConfig) (*envoy_route_v3.RouteAction_RequestMirrorPolicy, error) {
+	clusterName, err := m.clusterName(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &envoy_route_v3.RouteAction_RequestMirrorPolicy{
+		Cluster: clusterName,
+	}
+	if m.Percent != nil {
+		// Envoy only supports integer numerators so express the percentage in millionths.
+		policy.RuntimeFraction = &envoy_core_v3.RuntimeFractionalPercent{
+			DefaultValue: &envoy_type_v3.FractionalPercent{
+				Numerator:   uint32(*m.Percent * 10000),
+				Denominator: envoy_type_v3.FractionalPercent_MILLION,
+			},
+		}
+	}
+	if m.TraceSampled != nil {
+		policy.TraceSampled = wrapperspb.Bool(*m.TraceSampled)
+	}
+	return policy, nil
+}
@@ -0,0 +1,207 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package ratelimit
+
+import (
+	"fmt"
+
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/hashicorp/consul/api"
+	ext_cmn "github.com/hashicorp/consul/envoyextensions/extensioncommon"
+	"github.com/hashicorp/go-multierror"
+)
+
+type rateLimit struct {
+	ext_cmn.BasicExtensionAdapter
+
+	// ProxyType identifies the type of Envoy proxy that this extension applies to.
+	// The extension will only be configured for proxies that match this type and
+	// will be ignored for all other proxy types.
+	ProxyType api.ServiceKind
+	// InsertOptions controls how the extension inserts the filter.
+	InsertOptions ext_cmn.InsertOptions
+	// ListenerType controls which listener the extension applies to. It supports "inbound" or "outbound" listeners.
+	ListenerType string
+	// Config holds the extension configuration.
+	Config rateLimitConfig
+}
+
+var _ ext_cmn.BasicExtension = (*rateLimit)(nil)
+
+func Constructor(ext api.EnvoyExtension) (ext_cmn.EnvoyExtender, error) {
+	r, err := newRateLimit(ext)
+	if err != nil {
+		return nil, err
+	}
+	return &ext_cmn.BasicEnvoyExtender{
+		Extension: r,
+	}, nil
+}
+
+// CanApply indicates if the rate-limit extension can be applied to the given extension runtime configuration.
+func (r *rateLimit) CanApply(config *ext_cmn.RuntimeConfig) bool {
+	return config.Kind == api.ServiceKindConnectProxy
+}
+
+// PatchClusters modifies the cluster resources for the rate-limit extension.
+//
+// If the extension is configured to target a rate limit service running on the local host network
+// this func will insert a cluster for calling that service. It does nothing if the extension is
+// configured for local rate limiting or to target an upstream service because the existing cluster
+// for the upstream will be used directly by the filter.
+func (r *rateLimit) PatchClusters(cfg *ext_cmn.RuntimeConfig, c ext_cmn.ClusterMap) (ext_cmn.ClusterMap, error) {
+	cluster, err := r.Config.toEnvoyCluster(cfg)
+	if err != nil {
+		return c, err
+	}
+	if cluster != nil {
+		c[cluster.Name] = cluster
+	}
+	return c, nil
+}
+
+// PatchRoute adds the route level rate limit configuration to the routes discovered through RDS for
+// the listeners the extension applies to. Routes that are defined inline in the HTTP connection manager
+// are patched by PatchFilters.
+func (r *rateLimit) PatchRoute(p ext_cmn.RoutePayload) (*envoy_route_v3.RouteConfiguration, bool, error) {
+	if !r.matchesListenerDirection(p.IsInbound()) {
+		return p.Message, false, nil
+	}
+
+	patched, err := r.Config.patchRouteConfiguration(p.Message)
+	if err != nil {
+		return p.Message, false, err
+	}
+	return p.Message, patched, nil
+}
+
+func (r *rateLimit) matchesListenerDirection(isInboundListener bool) bool {
+	return (!isInboundListener && r.ListenerType == "outbound") || (isInboundListener && r.ListenerType == "inbound")
+}
+
+// PatchFilters inserts a rate limit filter into the list of network filters or the filter chain of the HTTP connection manager.
+// The filter type is chosen for each filter chain since the protocol of the local service doesn't apply to the
+// listeners of its upstreams.
+func (r *rateLimit) PatchFilters(cfg *ext_cmn.RuntimeConfig, filters []*envoy_listener_v3.Filter, isInboundListener bool) ([]*envoy_listener_v3.Filter, error) {
+	if !r.matchesListenerDirection(isInboundListener) {
+		return filters, nil
+	}
+
+	if _, _, err := ext_cmn.GetHTTPConnectionManager(filters...); err == nil {
+		filters, err := r.patchInlineRoutes(filters)
+		if err != nil {
+			return filters, err
+		}
+		rateLimitFilter, err := r.Config.toEnvoyHttpFilter(cfg)
+		if err != nil {
+			return filters, err
+		}
+		return ext_cmn.InsertHTTPFilter(filters, rateLimitFilter, r.insertOptions(true))
+	}
+
+	rateLimitFilter, err := r.Config.toEnvoyNetworkFilter(cfg)
+	if err != nil {
+		return filters, err
+	}
+	return ext_cmn.InsertNetworkFilter(filters, rateLimitFilter, r.insertOptions(false))
+}
+
+// patchInlineRoutes adds the route level rate limit configuration to the route configuration defined
+// inline in the HTTP connection manager, if any.
+func (r *rateLimit) patchInlineRoutes(filters []*envoy_listener_v3.Filter) ([]*envoy_listener_v3.Filter, error) {
+	httpConnMgr, idx, err := ext_cmn.GetHTTPConnectionManager(filters...)
+	if err != nil {
+		return filters, err
+	}
+	routeConfig := httpConnMgr.GetRouteConfig()
+	if routeConfig == nil {
+		return filters, nil
+	}
+
+	patched, err := r.Config.patchRouteConfiguration(routeConfig)
+	if err != nil || !patched {
+		return filters, err
+	}
+
+	newHttpConMan, err := ext_cmn.MakeFilter("envoy.filters.network.http_connection_manager", httpConnMgr)
+	if err != nil {
+		return filters, fmt.Errorf("failed to patch the routes of the HTTP connection manager filter: %w", err)
+	}
+	filtersCopy := make([]*envoy_listener_v3.Filter, len(filters))
+	copy(filtersCopy, filters)
+	filtersCopy[idx] = newHttpConMan
+	return filtersCopy, nil
+}
+
+func newRateLimit(ext api.EnvoyExtension) (*rateLimit, error) {
+	r := &rateLimit{}
+	if ext.Name != api.BuiltinRateLimitExtension {
+		return r, fmt.Errorf("expected extension name %q but got %q", api.BuiltinRateLimitExtension, ext.Name)
+	}
+	if err := r.fromArguments(ext.Arguments); err != nil {
+		return r, err
+	}
+	// Requests are denied when the rate limit service is unavailable only if the extension is required.
+	r.Config.failureModeDeny = ext.Required
+	return r, nil
+}
+
+func (r *rateLimit) fromArguments(args map[string]any) error {
+	if err := mapstructure.Decode(args, r); err != nil {
+		return err
+	}
+	r.normalize()
+	return r.validate()
+}
+
+// insertOptions returns the options used to insert the filter into an HTTP or a TCP filter chain.
+func (r *rateLimit) insertOptions(isHTTP bool) ext_cmn.InsertOptions {
+	// If the insert options have been expressly configured, then use them.
+	if r.InsertOptions.Location != "" {
+		return r.InsertOptions
+	}
+
+	// Configure the default, insert the filter immediately before the terminal filter.
+	opts := ext_cmn.InsertOptions{Location: ext_cmn.InsertBeforeFirstMatch}
+	if isHTTP {
+		opts.FilterName = "envoy.filters.http.router"
+	} else {
+		opts.FilterName = "envoy.filters.network.tcp_proxy"
+	}
+	return opts
+}
+
+func (r *rateLimit) normalize() {
+	if r.ProxyType == "" {
+		r.ProxyType = api.ServiceKindConnectProxy
+	}
+
+	if r.ListenerType == "" {
+		r.ListenerType = "inbound"
+	}
+
+	r.Config.normalize()
+}
+
+func (r *rateLimit) validate() error {
+	var resultErr error
+	if r.ProxyType != api.ServiceKindConnectProxy {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("unsupported ProxyType %q, only %q is supported",
+			r.ProxyType,
+			api.ServiceKindConnectProxy))
+	}
+
+	if r.ListenerType != "inbound" && r.ListenerType != "outbound" {
+		resultErr = multierror.Append(resultErr, fmt.Errorf(`unexpected ListenerType %q, supported values are "inbound" or "outbound"`, r.ListenerType))
+	}
+
+	if err := r.Config.validate(); err != nil {
+		resultErr = multierror.Append(resultErr, err)
+	}
+
+	return resultErr
+}
@@ -0,0 +1,375 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package ratelimit
+
+import (
+	"testing"
+
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_http_router_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_tcp_proxy_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+func TestConstructor(t *testing.T) {
+	t.Parallel()
+	tokenBucket := map[string]any{
+		"MaxTokens":     100,
+		"TokensPerFill": 10,
+		"FillInterval":  "1s",
+	}
+	grpcService := map[string]any{
+		"Target": map[string]any{
+			"URI": "localhost:8081",
+		},
+	}
+	descriptors := []map[string]any{
+		{"Entries": []map[string]any{{"Key": "service", "Value": "web"}}},
+	}
+	cases := map[string]struct {
+		extName string
+		args    map[string]any
+		errMsg  string
+	}{
+		"invalid name": {
+			extName: "invalid",
+			errMsg:  `expected extension name "builtin/rate-limit"`,
+		},
+		"invalid proxy type": {
+			args:   map[string]any{"ProxyType": "invalid"},
+			errMsg: `unsupported ProxyType`,
+		},
+		"invalid listener type": {
+			args:   map[string]any{"ListenerType": "invalid"},
+			errMsg: `unexpected ListenerType "invalid"`,
+		},
+		"no mode": {
+			args:   map[string]any{"ProxyType": "connect-proxy"},
+			errMsg: `exactly one of Local or Global must be set`,
+		},
+		"both modes": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Local":  map[string]any{"TokenBucket": tokenBucket},
+					"Global": map[string]any{"Domain": "consul", "GrpcService": grpcService, "Descriptors": descriptors},
+				},
+			},
+			errMsg: `exactly one of Local or Global must be set`,
+		},
+		"empty local": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Local": map[string]any{},
+				},
+			},
+			errMsg: `at least one of TokenBucket or Routes must be set`,
+		},
+		"invalid max tokens": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Local": map[string]any{
+						"TokenBucket": map[string]any{"FillInterval": "1s"},
+					},
+				},
+			},
+			errMsg: `MaxTokens must be greater than 0`,
+		},
+		"invalid fill interval": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Local": map[string]any{
+						"TokenBucket": map[string]any{"MaxTokens": 1, "FillInterval": "one"},
+					},
+				},
+			},
+			errMsg: `failed to parse FillInterval "one" as a duration`,
+		},
+		"fill interval too short": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Local": map[string]any{
+						"TokenBucket": map[string]any{"MaxTokens": 1, "FillInterval": "10ms"},
+					},
+				},
+			},
+			errMsg: `FillInterval "10ms" must be at least 50ms`,
+		},
+		"route without path": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Local": map[string]any{
+						"Routes": []map[string]any{{"TokenBucket": tokenBucket}},
+					},
+				},
+			},
+			errMsg: `exactly one of PathExact or PathPrefix must be set`,
+		},
+		"route with relative path": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Local": map[string]any{
+						"Routes": []map[string]any{{"PathPrefix": "api", "TokenBucket": tokenBucket}},
+					},
+				},
+			},
+			errMsg: `PathPrefix "api" must begin with a '/'`,
+		},
+		"route without token bucket": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Local": map[string]any{
+						"Routes": []map[string]any{{"PathExact": "/api"}},
+					},
+				},
+			},
+			errMsg: `TokenBucket must be set`,
+		},
+		"global without domain": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Global": map[string]any{"GrpcService": grpcService, "Descriptors": descriptors},
+				},
+			},
+			errMsg: `Domain must be set`,
+		},
+		"global without service": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Global": map[string]any{"Domain": "consul", "Descriptors": descriptors},
+				},
+			},
+			errMsg: `GrpcService must be set`,
+		},
+		"global with non-loopback address": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Global": map[string]any{
+						"Domain": "consul",
+						"GrpcService": map[string]any{
+							"Target": map[string]any{"URI": "10.0.0.1:8081"},
+						},
+						"Descriptors": descriptors,
+					},
+				},
+			},
+			errMsg: `invalid host for Target.URI "10.0.0.1:8081": expected "localhost", "127.0.0.1", or "::1"`,
+		},
+		"global without descriptors": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Global": map[string]any{"Domain": "consul", "GrpcService": grpcService},
+				},
+			},
+			errMsg: `at least one Descriptor must be set`,
+		},
+		"descriptor entry with multiple sources": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Global": map[string]any{
+						"Domain":      "consul",
+						"GrpcService": grpcService,
+						"Descriptors": []map[string]any{
+							{"Entries": []map[string]any{{"Key": "k", "Value": "v", "RequestHeader": "x-tenant"}}},
+						},
+					},
+				},
+			},
+			errMsg: `exactly one of Value, RequestHeader or RemoteAddress must be set`,
+		},
+		"descriptor entry with remote address key": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Global": map[string]any{
+						"Domain":      "consul",
+						"GrpcService": grpcService,
+						"Descriptors": []map[string]any{
+							{"Entries": []map[string]any{{"Key": "k", "RemoteAddress": true}}},
+						},
+					},
+				},
+			},
+			errMsg: `Key must not be set with RemoteAddress`,
+		},
+		"valid local": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Local": map[string]any{
+						"TokenBucket": tokenBucket,
+						"Routes":      []map[string]any{{"PathPrefix": "/api", "TokenBucket": tokenBucket}},
+					},
+				},
+			},
+		},
+		"valid global": {
+			args: map[string]any{
+				"Config": map[string]any{
+					"Global": map[string]any{
+						"Domain":      "consul",
+						"GrpcService": grpcService,
+						"Descriptors": descriptors,
+					},
+				},
+			},
+		},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			extName := api.BuiltinRateLimitExtension
+			if c.extName != "" {
+				extName = c.extName
+			}
+			ext, err := newRateLimit(api.EnvoyExtension{Name: extName, Arguments: c.args})
+			if c.errMsg == "" {
+				require.NoError(t, err)
+
+				httpFilter, err := ext.Config.toEnvoyHttpFilter(&extensioncommon.RuntimeConfig{})
+				require.NoError(t, err)
+				require.NotNil(t, httpFilter)
+
+				netFilter, err := ext.Config.toEnvoyNetworkFilter(&extensioncommon.RuntimeConfig{})
+				require.NoError(t, err)
+				require.NotNil(t, netFilter)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.errMsg)
+			}
+		})
+	}
+}
+
+func TestToEnvoyNetworkFilter_Unsupported(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		config map[string]any
+		errMsg string
+	}{
+		"local without token bucket": {
+			config: map[string]any{
+				"Local": map[string]any{
+					"Routes": []map[string]any{{
+						"PathPrefix":  "/api",
+						"TokenBucket": map[string]any{"MaxTokens": 1, "FillInterval": "1s"},
+					}},
+				},
+			},
+			errMsg: "Config.Local.TokenBucket must be set to rate limit TCP listeners",
+		},
+		"global with request header": {
+			config: map[string]any{
+				"Global": map[string]any{
+					"Domain": "consul",
+					"GrpcService": map[string]any{
+						"Target": map[string]any{"URI": "localhost:8081"},
+					},
+					"Descriptors": []map[string]any{
+						{"Entries": []map[string]any{{"Key": "tenant", "RequestHeader": "x-tenant"}}},
+					},
+				},
+			},
+			errMsg: "Config.Global.Descriptors[0].Entries[0] must set a Value to rate limit TCP listeners",
+		},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			ext, err := newRateLimit(api.EnvoyExtension{
+				Name:      api.BuiltinRateLimitExtension,
+				Arguments: map[string]any{"Config": c.config},
+			})
+			require.NoError(t, err)
+
+			_, err = ext.Config.toEnvoyNetworkFilter(&extensioncommon.RuntimeConfig{})
+			require.EqualError(t, err, c.errMsg)
+		})
+	}
+}
+
+func TestPatchRouteConfiguration_Local(t *testing.T) {
+	t.Parallel()
+	ext, err := newRateLimit(api.EnvoyExtension{
+		Name: api.BuiltinRateLimitExtension,
+		Arguments: map[string]any{
+			"Config": map[string]any{
+				"Local": map[string]any{
+					"Routes": []map[string]any{
+						{"PathExact": "/health", "TokenBucket": map[string]any{"MaxTokens": 1, "FillInterval": "1s"}},
+						{"PathPrefix": "/api", "TokenBucket": map[string]any{"MaxTokens": 5, "FillInterval": "1s"}},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	route := func(match *envoy_route_v3.RouteMatch) *envoy_route_v3.Route {
+		return &envoy_route_v3.Route{Match: match}
+	}
+	rc := &envoy_route_v3.RouteConfiguration{
+		VirtualHosts: []*envoy_route_v3.VirtualHost{{
+			Routes: []*envoy_route_v3.Route{
+				route(&envoy_route_v3.RouteMatch{PathSpecifier: &envoy_route_v3.RouteMatch_Path{Path: "/health"}}),
+				route(&envoy_route_v3.RouteMatch{PathSpecifier: &envoy_route_v3.RouteMatch_Prefix{Prefix: "/api"}}),
+				route(&envoy_route_v3.RouteMatch{PathSpecifier: &envoy_route_v3.RouteMatch_Prefix{Prefix: "/"}}),
+			},
+		}},
+	}
+
+	patched, err := ext.Config.patchRouteConfiguration(rc)
+	require.NoError(t, err)
+	require.True(t, patched)
+
+	routes := rc.VirtualHosts[0].Routes
+	require.Contains(t, routes[0].TypedPerFilterConfig, localRateLimitFilterName)
+	require.Contains(t, routes[1].TypedPerFilterConfig, localRateLimitFilterName)
+	require.Empty(t, routes[2].TypedPerFilterConfig)
+}
+
+func TestPatchFilters_PerFilterChain(t *testing.T) {
+	t.Parallel()
+	ext, err := newRateLimit(api.EnvoyExtension{
+		Name: api.BuiltinRateLimitExtension,
+		Arguments: map[string]any{
+			"ListenerType": "outbound",
+			"Config": map[string]any{
+				"Local": map[string]any{
+					"TokenBucket": map[string]any{"MaxTokens": 100, "FillInterval": "1s"},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	router, err := extensioncommon.MakeEnvoyHTTPFilter("envoy.filters.http.router", &envoy_http_router_v3.Router{})
+	require.NoError(t, err)
+	hcm, err := extensioncommon.MakeFilter("envoy.filters.network.http_connection_manager", &envoy_http_v3.HttpConnectionManager{
+		HttpFilters: []*envoy_http_v3.HttpFilter{router},
+	})
+	require.NoError(t, err)
+	tcpProxy, err := extensioncommon.MakeFilter("envoy.filters.network.tcp_proxy", &envoy_tcp_proxy_v3.TcpProxy{})
+	require.NoError(t, err)
+
+	// The local service is TCP but the filter type follows each upstream filter chain, and the
+	// options used for one chain don't leak into the next one.
+	cfg := &extensioncommon.RuntimeConfig{Protocol: "tcp"}
+	for i := 0; i < 2; i++ {
+		filters, err := ext.PatchFilters(cfg, []*envoy_listener_v3.Filter{hcm}, false)
+		require.NoError(t, err)
+		httpConnMgr, _, err := extensioncommon.GetHTTPConnectionManager(filters...)
+		require.NoError(t, err)
+		require.Len(t, httpConnMgr.HttpFilters, 2)
+		require.Equal(t, "envoy.filters.http.local_ratelimit", httpConnMgr.HttpFilters[0].Name)
+
+		filters, err = ext.PatchFilters(cfg, []*envoy_listener_v3.Filter{tcpProxy}, false)
+		require.NoError(t, err)
+		require.Len(t, filters, 2)
+		require.Equal(t, "envoy.filters.network.local_ratelimit", filters[0].Name)
+	}
+	require.Empty(t, ext.InsertOptions)
+}
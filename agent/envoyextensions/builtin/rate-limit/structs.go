@@ -0,0 +1,700 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_endpoint_v3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_ratelimit_config_v3 "github.com/envoyproxy/go-control-plane/envoy/config/ratelimit/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_common_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/common/ratelimit/v3"
+	envoy_http_local_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/local_ratelimit/v3"
+	envoy_http_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
+	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_network_local_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/local_ratelimit/v3"
+	envoy_network_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/ratelimit/v3"
+	envoy_upstreams_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
+	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/hashicorp/go-multierror"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/api"
+	cmn "github.com/hashicorp/consul/envoyextensions/extensioncommon"
+)
+
+const (
+	LocalRateLimitServiceClusterName = "local_ratelimit_service"
+
+	localRateLimitFilterName  = "envoy.filters.http.local_ratelimit"
+	globalRateLimitFilterName = "envoy.filters.http.ratelimit"
+
+	defaultStatPrefix = "rate_limit"
+	// Envoy rejects token buckets that are refilled more often than every 50ms.
+	minFillInterval = 50 * time.Millisecond
+
+	localhost     = "localhost"
+	localhostIPv4 = "127.0.0.1"
+	localhostIPv6 = "::1"
+)
+
+type rateLimitConfig struct {
+	Global     *GlobalRateLimit
+	Local      *LocalRateLimit
+	StatPrefix string
+
+	failureModeDeny bool
+}
+
+func (c *rateLimitConfig) normalize() {
+	if c.StatPrefix == "" {
+		c.StatPrefix = defaultStatPrefix
+	}
+	if c.isGlobal() {
+		c.Global.normalize()
+	}
+}
+
+func (c *rateLimitConfig) validate() error {
+	c.normalize()
+
+	var resultErr error
+	if c.isGlobal() == c.isLocal() {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("exactly one of Local or Global must be set"))
+	}
+
+	if c.isLocal() {
+		if err := c.Local.validate(); err != nil {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("failed to validate Config.Local: %w", err))
+		}
+	}
+	if c.isGlobal() {
+		if err := c.Global.validate(); err != nil {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("failed to validate Config.Global: %w", err))
+		}
+	}
+
+	return resultErr
+}
+
+func (c rateLimitConfig) isGlobal() bool {
+	return c.Global != nil
+}
+
+func (c rateLimitConfig) isLocal() bool {
+	return c.Local != nil
+}
+
+// toEnvoyCluster returns an Envoy cluster for connecting to the rate limit service.
+// If the extension is configured with the rate limit service locally via the URI set to localhost,
+// this func will return a new cluster definition that will allow the proxy to connect to the rate
+// limit service running on localhost on the configured port.
+//
+// If the extension is configured for local rate limiting or with the rate limit service as an
+// upstream there is no need to insert a new cluster so this method returns nil.
+func (c *rateLimitConfig) toEnvoyCluster(_ *cmn.RuntimeConfig) (*envoy_cluster_v3.Cluster, error) {
+	if !c.isGlobal() {
+		return nil, nil
+	}
+
+	target := c.Global.GrpcService.Target
+
+	// If the target is an upstream we do not need to create a cluster. We will use the cluster of the upstream.
+	if target.isService() {
+		return nil, nil
+	}
+
+	host, port, err := target.addr()
+	if err != nil {
+		return nil, err
+	}
+
+	clusterType := &envoy_cluster_v3.Cluster_Type{Type: envoy_cluster_v3.Cluster_STATIC}
+	if host == localhost {
+		// If the host is "localhost" use a STRICT_DNS cluster type to perform DNS lookup.
+		clusterType = &envoy_cluster_v3.Cluster_Type{Type: envoy_cluster_v3.Cluster_STRICT_DNS}
+	}
+
+	// By default HTTP/1.1 is used for the transport protocol. gRPC requires that we explicitly configure HTTP/2
+	httpProtoOpts := &envoy_upstreams_http_v3.HttpProtocolOptions{
+		UpstreamProtocolOptions: &envoy_upstreams_http_v3.HttpProtocolOptions_ExplicitHttpConfig_{
+			ExplicitHttpConfig: &envoy_upstreams_http_v3.HttpProtocolOptions_ExplicitHttpConfig{
+				ProtocolConfig: &envoy_upstreams_http_v3.HttpProtocolOptions_ExplicitHttpConfig_Http2ProtocolOptions{},
+			},
+		},
+	}
+	httpProtoOptsAny, err := anypb.New(httpProtoOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &envoy_cluster_v3.Cluster{
+		Name:                 LocalRateLimitServiceClusterName,
+		ClusterDiscoveryType: clusterType,
+		ConnectTimeout:       target.timeoutDurationPB(),
+		LoadAssignment: &envoy_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: LocalRateLimitServiceClusterName,
+			Endpoints: []*envoy_endpoint_v3.LocalityLbEndpoints{
+				{
+					LbEndpoints: []*envoy_endpoint_v3.LbEndpoint{{
+						HostIdentifier: &envoy_endpoint_v3.LbEndpoint_Endpoint{
+							Endpoint: &envoy_endpoint_v3.Endpoint{
+								Address: &envoy_core_v3.Address{
+									Address: &envoy_core_v3.Address_SocketAddress{
+										SocketAddress: &envoy_core_v3.SocketAddress{
+											Address: host,
+											PortSpecifier: &envoy_core_v3.SocketAddress_PortValue{
+												PortValue: uint32(port),
+											},
+										},
+									},
+								},
+							},
+						},
+					}},
+				},
+			},
+		},
+		TypedExtensionProtocolOptions: map[string]*anypb.Any{
+			"envoy.extensions.upstreams.http.v3.HttpProtocolOptions": httpProtoOptsAny,
+		},
+	}, nil
+}
+
+// toEnvoyHttpFilter returns the HTTP filter that enforces the rate limits. The local rate limit filter
+// enforces the token bucket of the listener, if any, while the routes that have their own token bucket
+// override it through their per-filter configuration. The global rate limit filter calls the rate limit
+// service with the descriptors generated by the actions of the virtual hosts.
+func (c rateLimitConfig) toEnvoyHttpFilter(cfg *cmn.RuntimeConfig) (*envoy_http_v3.HttpFilter, error) {
+	if c.isLocal() {
+		return cmn.MakeEnvoyHTTPFilter(localRateLimitFilterName, c.Local.toEnvoyHttp(c.StatPrefix, c.Local.TokenBucket))
+	}
+
+	rls, err := c.Global.envoyRateLimitService(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return cmn.MakeEnvoyHTTPFilter(globalRateLimitFilterName, &envoy_http_ratelimit_v3.RateLimit{
+		Domain:           c.Global.Domain,
+		Timeout:          c.Global.GrpcService.Target.timeoutDurationPB(),
+		FailureModeDeny:  c.failureModeDeny,
+		RateLimitService: rls,
+	})
+}
+
+// toEnvoyNetworkFilter returns the network filter that enforces the rate limits on new connections.
+// Route overrides and descriptors that are computed from requests are not supported for TCP listeners.
+func (c rateLimitConfig) toEnvoyNetworkFilter(cfg *cmn.RuntimeConfig) (*envoy_listener_v3.Filter, error) {
+	if c.isLocal() {
+		if c.Local.TokenBucket == nil {
+			return nil, fmt.Errorf("Config.Local.TokenBucket must be set to rate limit TCP listeners")
+		}
+		return cmn.MakeFilter("envoy.filters.network.local_ratelimit", &envoy_network_local_ratelimit_v3.LocalRateLimit{
+			StatPrefix:  c.StatPrefix,
+			TokenBucket: c.Local.TokenBucket.toEnvoy(),
+		})
+	}
+
+	descriptors, err := c.Global.envoyNetworkDescriptors()
+	if err != nil {
+		return nil, err
+	}
+	rls, err := c.Global.envoyRateLimitService(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return cmn.MakeFilter("envoy.filters.network.ratelimit", &envoy_network_ratelimit_v3.RateLimit{
+		StatPrefix:       c.StatPrefix,
+		Domain:           c.Global.Domain,
+		Descriptors:      descriptors,
+		Timeout:          c.Global.GrpcService.Target.timeoutDurationPB(),
+		FailureModeDeny:  c.failureModeDeny,
+		RateLimitService: rls,
+	})
+}
+
+// patchRouteConfiguration adds the route level configuration of the rate limits to the routes of the
+// given route configuration. It returns false if the route configuration was not modified.
+func (c rateLimitConfig) patchRouteConfiguration(rc *envoy_route_v3.RouteConfiguration) (bool, error) {
+	if c.isGlobal() {
+		rateLimit := c.Global.toEnvoyRateLimit()
+		for _, vh := range rc.VirtualHosts {
+			vh.RateLimits = append(vh.RateLimits, rateLimit)
+		}
+		return len(rc.VirtualHosts) > 0, nil
+	}
+
+	patched := false
+	for _, vh := range rc.VirtualHosts {
+		for _, route := range vh.Routes {
+			override := c.Local.routeOverride(route.Match)
+			if override == nil {
+				continue
+			}
+			perFilterCfg, err := anypb.New(c.Local.toEnvoyHttp(c.StatPrefix, override.TokenBucket))
+			if err != nil {
+				return false, err
+			}
+			if route.TypedPerFilterConfig == nil {
+				route.TypedPerFilterConfig = make(map[string]*anypb.Any)
+			}
+			route.TypedPerFilterConfig[localRateLimitFilterName] = perFilterCfg
+			patched = true
+		}
+	}
+	return patched, nil
+}
+
+type LocalRateLimit struct {
+	TokenBucket *TokenBucket
+	Routes      []*RouteRateLimit
+}
+
+func (l *LocalRateLimit) validate() error {
+	var resultErr error
+	if l.TokenBucket == nil && len(l.Routes) == 0 {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("at least one of TokenBucket or Routes must be set"))
+	}
+	if l.TokenBucket != nil {
+		if err := l.TokenBucket.validate(); err != nil {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("invalid TokenBucket: %w", err))
+		}
+	}
+	for i, r := range l.Routes {
+		if err := r.validate(); err != nil {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("invalid Routes[%d]: %w", i, err))
+		}
+	}
+	return resultErr
+}
+
+// routeOverride returns the first route rate limit that applies to an Envoy route with the given match.
+// It returns nil if the route uses the token bucket of the listener.
+func (l *LocalRateLimit) routeOverride(match *envoy_route_v3.RouteMatch) *RouteRateLimit {
+	for _, r := range l.Routes {
+		if r.matches(match) {
+			return r
+		}
+	}
+	return nil
+}
+
+func (l *LocalRateLimit) toEnvoyHttp(statPrefix string, bucket *TokenBucket) *envoy_http_local_ratelimit_v3.LocalRateLimit {
+	if bucket == nil {
+		// Without a token bucket the filter only enforces the overrides of the routes.
+		return &envoy_http_local_ratelimit_v3.LocalRateLimit{StatPrefix: statPrefix}
+	}
+	return &envoy_http_local_ratelimit_v3.LocalRateLimit{
+		StatPrefix:     statPrefix,
+		TokenBucket:    bucket.toEnvoy(),
+		FilterEnabled:  runtimePercent("local_rate_limit_enabled"),
+		FilterEnforced: runtimePercent("local_rate_limit_enforced"),
+	}
+}
+
+// runtimePercent returns a runtime fractional percent that defaults to 100%. The local rate limit
+// filter is disabled unless both its enabled and enforced percentages are set.
+func runtimePercent(key string) *envoy_core_v3.RuntimeFractionalPercent {
+	return &envoy_core_v3.RuntimeFractionalPercent{
+		DefaultValue: &envoy_type_v3.FractionalPercent{
+			Numerator:   100,
+			Denominator: envoy_type_v3.FractionalPercent_HUNDRED,
+		},
+		RuntimeKey: key,
+	}
+}
+
+type RouteRateLimit struct {
+	PathExact   string
+	PathPrefix  string
+	TokenBucket *TokenBucket
+}
+
+// matches returns true if the Envoy route match has the same path specifier as the route rate limit.
+func (r *RouteRateLimit) matches(match *envoy_route_v3.RouteMatch) bool {
+	if match == nil {
+		return false
+	}
+	if r.PathExact != "" {
+		return match.GetPath() == r.PathExact
+	}
+	return match.GetPrefix() == r.PathPrefix
+}
+
+func (r *RouteRateLimit) validate() error {
+	var resultErr error
+	if (r.PathExact == "") == (r.PathPrefix == "") {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("exactly one of PathExact or PathPrefix must be set"))
+	}
+	if r.PathExact != "" && !strings.HasPrefix(r.PathExact, "/") {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("PathExact %q must begin with a '/'", r.PathExact))
+	}
+	if r.PathPrefix != "" && !strings.HasPrefix(r.PathPrefix, "/") {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("PathPrefix %q must begin with a '/'", r.PathPrefix))
+	}
+	if r.TokenBucket == nil {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("TokenBucket must be set"))
+	} else if err := r.TokenBucket.validate(); err != nil {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("invalid TokenBucket: %w", err))
+	}
+	return resultErr
+}
+
+type TokenBucket struct {
+	MaxTokens     int
+	TokensPerFill int
+	FillInterval  string
+
+	fillInterval time.Duration
+}
+
+func (b *TokenBucket) toEnvoy() *envoy_type_v3.TokenBucket {
+	bucket := &envoy_type_v3.TokenBucket{
+		MaxTokens:    uint32(b.MaxTokens),
+		FillInterval: durationpb.New(b.fillInterval),
+	}
+	if b.TokensPerFill > 0 {
+		bucket.TokensPerFill = wrapperspb.UInt32(uint32(b.TokensPerFill))
+	}
+	return bucket
+}
+
+func (b *TokenBucket) validate() error {
+	var resultErr error
+	if b.MaxTokens <= 0 {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("MaxTokens must be greater than 0"))
+	}
+	if b.TokensPerFill < 0 {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("TokensPerFill must not be negative"))
+	}
+	if b.FillInterval == "" {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("FillInterval must be set"))
+	} else if d, err := time.ParseDuration(b.FillInterval); err != nil {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("failed to parse FillInterval %q as a duration: %w", b.FillInterval, err))
+	} else if d < minFillInterval {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("FillInterval %q must be at least %s", b.FillInterval, minFillInterval))
+	} else {
+		b.fillInterval = d
+	}
+	return resultErr
+}
+
+type GlobalRateLimit struct {
+	Domain      string
+	GrpcService *GrpcService
+	Descriptors []*Descriptor
+}
+
+func (g *GlobalRateLimit) normalize() {
+	g.GrpcService.normalize()
+}
+
+func (g *GlobalRateLimit) validate() error {
+	var resultErr error
+	if g.Domain == "" {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("Domain must be set"))
+	}
+	if g.GrpcService == nil {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("GrpcService must be set"))
+	} else if err := g.GrpcService.validate(); err != nil {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("failed to validate GrpcService: %w", err))
+	}
+	if len(g.Descriptors) == 0 {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("at least one Descriptor must be set"))
+	}
+	for i, d := range g.Descriptors {
+		if err := d.validate(); err != nil {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("invalid Descriptors[%d]: %w", i, err))
+		}
+	}
+	return resultErr
+}
+
+// envoyRateLimitService returns the configuration of the rate limit service.
+// If the extension is configured with an upstream rate limit service then the cluster of that upstream
+// is used. If the extension is configured with a URI, the extension inserts a new cluster with the name
+// "local_ratelimit_service", so we use that name.
+func (g *GlobalRateLimit) envoyRateLimitService(cfg *cmn.RuntimeConfig) (*envoy_ratelimit_config_v3.RateLimitServiceConfig, error) {
+	target := g.GrpcService.Target
+	clusterName := LocalRateLimitServiceClusterName
+	if target.isService() {
+		var err error
+		if clusterName, err = target.clusterName(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	return &envoy_ratelimit_config_v3.RateLimitServiceConfig{
+		GrpcService: &envoy_core_v3.GrpcService{
+			TargetSpecifier: &envoy_core_v3.GrpcService_EnvoyGrpc_{
+				EnvoyGrpc: &envoy_core_v3.GrpcService_EnvoyGrpc{
+					ClusterName: clusterName,
+					Authority:   g.GrpcService.Authority,
+				},
+			},
+			Timeout: target.timeoutDurationPB(),
+		},
+		TransportApiVersion: envoy_core_v3.ApiVersion_V3,
+	}, nil
+}
+
+// toEnvoyRateLimit returns the actions that generate the descriptors sent to the rate limit service.
+func (g *GlobalRateLimit) toEnvoyRateLimit() *envoy_route_v3.RateLimit {
+	rateLimit := &envoy_route_v3.RateLimit{}
+	for _, d := range g.Descriptors {
+		for _, e := range d.Entries {
+			rateLimit.Actions = append(rateLimit.Actions, e.toEnvoyAction())
+		}
+	}
+	return rateLimit
+}
+
+// envoyNetworkDescriptors returns the static descriptors sent to the rate limit service for each new
+// connection. It returns a non-nil error if an entry is computed from a request.
+func (g *GlobalRateLimit) envoyNetworkDescriptors() ([]*envoy_common_ratelimit_v3.RateLimitDescriptor, error) {
+	var descriptors []*envoy_common_ratelimit_v3.RateLimitDescriptor
+	for i, d := range g.Descriptors {
+		descriptor := &envoy_common_ratelimit_v3.RateLimitDescriptor{}
+		for j, e := range d.Entries {
+			if e.Value == "" {
+				return nil, fmt.Errorf("Config.Global.Descriptors[%d].Entries[%d] must set a Value to rate limit TCP listeners", i, j)
+			}
+			descriptor.Entries = append(descriptor.Entries, &envoy_common_ratelimit_v3.RateLimitDescriptor_Entry{
+				Key:   e.Key,
+				Value: e.Value,
+			})
+		}
+		descriptors = append(descriptors, descriptor)
+	}
+	return descriptors, nil
+}
+
+type Descriptor struct {
+	Entries []*DescriptorEntry
+}
+
+func (d *Descriptor) validate() error {
+	var resultErr error
+	if len(d.Entries) == 0 {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("at least one Entry must be set"))
+	}
+	for i, e := range d.Entries {
+		if err := e.validate(); err != nil {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("invalid Entries[%d]: %w", i, err))
+		}
+	}
+	return resultErr
+}
+
+// DescriptorEntry is an entry of a descriptor sent to the rate limit service. The value of the entry
+// is either static, the value of a request header or the address of the downstream client.
+type DescriptorEntry struct {
+	Key           string
+	Value         string
+	RequestHeader string
+	RemoteAddress bool
+}
+
+func (e *DescriptorEntry) toEnvoyAction() *envoy_route_v3.RateLimit_Action {
+	switch {
+	case e.RemoteAddress:
+		return &envoy_route_v3.RateLimit_Action{
+			ActionSpecifier: &envoy_route_v3.RateLimit_Action_RemoteAddress_{
+				RemoteAddress: &envoy_route_v3.RateLimit_Action_RemoteAddress{},
+			},
+		}
+	case e.RequestHeader != "":
+		return &envoy_route_v3.RateLimit_Action{
+			ActionSpecifier: &envoy_route_v3.RateLimit_Action_RequestHeaders_{
+				RequestHeaders: &envoy_route_v3.RateLimit_Action_RequestHeaders{
+					HeaderName:    e.RequestHeader,
+					DescriptorKey: e.Key,
+				},
+			},
+		}
+	default:
+		return &envoy_route_v3.RateLimit_Action{
+			ActionSpecifier: &envoy_route_v3.RateLimit_Action_GenericKey_{
+				GenericKey: &envoy_route_v3.RateLimit_Action_GenericKey{
+					DescriptorKey:   e.Key,
+					DescriptorValue: e.Value,
+				},
+			},
+		}
+	}
+}
+
+func (e *DescriptorEntry) validate() error {
+	var resultErr error
+	sources := 0
+	for _, set := range []bool{e.Value != "", e.RequestHeader != "", e.RemoteAddress} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("exactly one of Value, RequestHeader or RemoteAddress must be set"))
+	}
+	if e.RemoteAddress {
+		if e.Key != "" {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("Key must not be set with RemoteAddress, Envoy always uses %q", "remote_address"))
+		}
+	} else if e.Key == "" {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("Key must be set"))
+	}
+	return resultErr
+}
+
+type GrpcService struct {
+	Target    *Target
+	Authority string
+}
+
+func (v *GrpcService) normalize() {
+	if v == nil {
+		return
+	}
+	v.Target.normalize()
+}
+
+func (v *GrpcService) validate() error {
+	var resultErr error
+	if v == nil {
+		return resultErr
+	}
+
+	if v.Target == nil {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("GrpcService.Target must be set"))
+	}
+	if err := v.Target.validate(); err != nil {
+		resultErr = multierror.Append(resultErr, err)
+	}
+	return resultErr
+}
+
+type Target struct {
+	Service api.CompoundServiceName
+	URI     string
+	Timeout string
+
+	timeout *time.Duration
+	host    string
+	port    int
+}
+
+// addr returns the host and port for the target when the target is a URI.
+// It returns a non-nil error if the target is not a URI.
+func (t Target) addr() (string, int, error) {
+	if !t.isURI() {
+		return "", 0, fmt.Errorf("target is not configured with a URI, set Target.URI")
+	}
+	return t.host, t.port, nil
+}
+
+// clusterName returns the cluster name for the target when the target is an upstream service.
+// It searches through the upstreams in the provided runtime configuration and returns the name
+// of the cluster for the first upstream service that matches the target service.
+// It returns a non-nil error if a matching cluster is not found or if the target is not an
+// upstream service.
+func (t Target) clusterName(cfg *cmn.RuntimeConfig) (string, error) {
+	if !t.isService() {
+		return "", fmt.Errorf("target is not configured with an upstream service, set Target.Service")
+	}
+
+	for service, upstream := range cfg.Upstreams {
+		if service == t.Service {
+			for sni := range upstream.SNIs {
+				return sni, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no upstream definition found for service %q", t.Service.Name)
+}
+
+func (t Target) isService() bool {
+	return t.Service.Name != ""
+}
+
+func (t Target) isURI() bool {
+	return t.URI != ""
+}
+
+func (t *Target) normalize() {
+	if t == nil {
+		return
+	}
+	t.Service.Namespace = acl.NamespaceOrDefault(t.Service.Namespace)
+	t.Service.Partition = acl.PartitionOrDefault(t.Service.Partition)
+}
+
+// timeoutDurationPB returns the target's timeout as a *durationpb.Duration.
+// It returns nil if the timeout has not been explicitly set.
+func (t *Target) timeoutDurationPB() *durationpb.Duration {
+	if t == nil || t.timeout == nil {
+		return nil
+	}
+	return durationpb.New(*t.timeout)
+}
+
+func (t *Target) validate() error {
+	var err, resultErr error
+	if t == nil {
+		return resultErr
+	}
+
+	if t.isURI() == t.isService() {
+		resultErr = multierror.Append(resultErr, fmt.Errorf("exactly one of Target.Service or Target.URI must be set"))
+	}
+
+	if t.isURI() {
+		t.host, t.port, err = parseAddr(t.URI)
+		if err == nil {
+			switch t.host {
+			case localhost, localhostIPv4, localhostIPv6:
+			default:
+				resultErr = multierror.Append(resultErr,
+					fmt.Errorf("invalid host for Target.URI %q: expected %q, %q, or %q", t.URI, localhost, localhostIPv4, localhostIPv6))
+			}
+		} else {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("invalid format for Target.URI %q: expected host:port", t.URI))
+		}
+	}
+
+	if t.Timeout != "" {
+		if d, err := time.ParseDuration(t.Timeout); err == nil {
+			t.timeout = &d
+		} else {
+			resultErr = multierror.Append(resultErr, fmt.Errorf("failed to parse Target.Timeout %q as a duration: %w", t.Timeout, err))
+		}
+	}
+	return resultErr
+}
+
+func parseAddr(s string) (host string, port int, err error) {
+	// Strip the protocol if one was provided
+	if _, addr, hasProto := strings.Cut(s, "://"); hasProto {
+		s = addr
+	}
+	idx := strings.LastIndex(s, ":")
+	switch idx {
+	case -1, len(s) - 1:
+		err = fmt.Errorf("invalid input format %q: expected host:port", s)
+	case 0:
+		host = localhost
+		port, err = strconv.Atoi(s[idx+1:])
+	default:
+		host = s[:idx]
+		port, err = strconv.Atoi(s[idx+1:])
+	}
+	return
+}
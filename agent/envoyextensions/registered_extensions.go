@@ -11,9 +11,12 @@ import (
 
 	awslambda "github.com/hashicorp/consul/agent/envoyextensions/builtin/aws-lambda"
 	extauthz "github.com/hashicorp/consul/agent/envoyextensions/builtin/ext-authz"
+	httpcompressioncache "github.com/hashicorp/consul/agent/envoyextensions/builtin/http-compression-cache"
+	httpheadersmirror "github.com/hashicorp/consul/agent/envoyextensions/builtin/http-headers-mirror"
 	"github.com/hashicorp/consul/agent/envoyextensions/builtin/lua"
 	otelaccesslogging "github.com/hashicorp/consul/agent/envoyextensions/builtin/otel-access-logging"
 	propertyoverride "github.com/hashicorp/consul/agent/envoyextensions/builtin/property-override"
+	ratelimit "github.com/hashicorp/consul/agent/envoyextensions/builtin/rate-limit"
 	"github.com/hashicorp/consul/agent/envoyextensions/builtin/wasm"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/envoyextensions/extensioncommon"
@@ -22,12 +25,15 @@ import (
 type extensionConstructor func(api.EnvoyExtension) (extensioncommon.EnvoyExtender, error)
 
 var extensionConstructors = map[string]extensionConstructor{
-	api.BuiltinOTELAccessLoggingExtension: otelaccesslogging.Constructor,
-	api.BuiltinLuaExtension:               lua.Constructor,
-	api.BuiltinAWSLambdaExtension:         awslambda.Constructor,
-	api.BuiltinPropertyOverrideExtension:  propertyoverride.Constructor,
-	api.BuiltinWasmExtension:              wasm.Constructor,
-	api.BuiltinExtAuthzExtension:          extauthz.Constructor,
+	api.BuiltinOTELAccessLoggingExtension:    otelaccesslogging.Constructor,
+	api.BuiltinLuaExtension:                  lua.Constructor,
+	api.BuiltinAWSLambdaExtension:            awslambda.Constructor,
+	api.BuiltinPropertyOverrideExtension:     propertyoverride.Constructor,
+	api.BuiltinWasmExtension:                 wasm.Constructor,
+	api.BuiltinExtAuthzExtension:             extauthz.Constructor,
+	api.BuiltinRateLimitExtension:            ratelimit.Constructor,
+	api.BuiltinHTTPHeadersMirrorExtension:    httpheadersmirror.Constructor,
+	api.BuiltinHTTPCompressionCacheExtension: httpcompressioncache.Constructor,
 }
 
 // ConstructExtension attempts to lookup and build an extension from the registry with the
@@ -4,6 +4,7 @@
 package agent
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -29,6 +30,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/cache"
 	"github.com/hashicorp/consul/agent/config"
 	"github.com/hashicorp/consul/agent/consul"
@@ -548,6 +550,14 @@ func (s *HTTPHandlers) wrap(handler endpoint, methods []string) http.HandlerFunc
 			)
 		}()
 
+		if auditor := s.agent.baseDeps.Auditor; auditor != nil {
+			auditResp := &auditResponseWriter{ResponseWriter: resp, status: http.StatusOK}
+			resp = auditResp
+			defer func() {
+				s.writeAuditHTTPEvent(auditor, req, auditResp.status, start, err)
+			}()
+		}
+
 		var obj interface{}
 
 		// if this endpoint has declared methods, respond appropriately to OPTIONS requests. Otherwise let the endpoint handle that.
@@ -630,6 +640,72 @@ func (s *HTTPHandlers) wrap(handler endpoint, methods []string) http.HandlerFunc
 	}
 }
 
+// auditResponseWriter records the status code written by a handler so that
+// it can be included in the audit log.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *auditResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *auditResponseWriter) Flush() {
+	w.wroteHeader = true
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack lets handlers take over the connection, for example to upgrade it
+// to another protocol, which is recorded as switching protocols.
+func (w *auditResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil && !w.wroteHeader {
+		w.status = http.StatusSwitchingProtocols
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+func (w *auditResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// writeAuditHTTPEvent records a completed HTTP API request in the audit log.
+func (s *HTTPHandlers) writeAuditHTTPEvent(auditor *audit.Auditor, req *http.Request, status int, start time.Time, err error) {
+	endpoint := aclEndpointRE.ReplaceAllString(req.URL.Path, "$1<hidden>$4")
+	if !auditor.Matches(endpoint) {
+		return
+	}
+
+	ev := audit.NewHTTPEvent(req, endpoint, status, start)
+	if err != nil {
+		ev.Response.Error = err.Error()
+	}
+
+	var token string
+	s.parseToken(req, &token)
+	ev.Auth.SecretID = token
+	ev.Auth.AccessorID = s.agent.aclAccessorID(token)
+	auditor.Write(ev)
+}
+
 func isV1CatalogRequest(logURL string) bool {
 	switch {
 	case strings.HasPrefix(logURL, "/v1/catalog/"),
@@ -1170,6 +1246,14 @@ func sourceAddrFromRequest(req *http.Request) string {
 		}
 	}
 
+	return remoteIPFromRequest(req)
+}
+
+// remoteIPFromRequest returns the IP address of the connection the request was
+// made over. Unlike sourceAddrFromRequest it ignores the X-Forwarded-For
+// header, which clients can set to any value, so it is used for the source IP
+// of ACL rule conditions.
+func remoteIPFromRequest(req *http.Request) string {
 	host, _, err := net.SplitHostPort(req.RemoteAddr)
 	if err != nil {
 		return ""
@@ -7,6 +7,8 @@ func init() {
 	registerEndpoint("/v1/acl/bootstrap", []string{"PUT"}, (*HTTPHandlers).ACLBootstrap)
 	registerEndpoint("/v1/acl/login", []string{"POST"}, (*HTTPHandlers).ACLLogin)
 	registerEndpoint("/v1/acl/logout", []string{"POST"}, (*HTTPHandlers).ACLLogout)
+	registerEndpoint("/v1/acl/oidc/auth-url", []string{"POST"}, (*HTTPHandlers).ACLOIDCAuthURL)
+	registerEndpoint("/v1/acl/oidc/callback", []string{"POST"}, (*HTTPHandlers).ACLOIDCCallback)
 	registerEndpoint("/v1/acl/replication", []string{"GET"}, (*HTTPHandlers).ACLReplicationStatus)
 	registerEndpoint("/v1/acl/policies", []string{"GET"}, (*HTTPHandlers).ACLPolicyList)
 	registerEndpoint("/v1/acl/policy", []string{"PUT"}, (*HTTPHandlers).ACLPolicyCreate)
@@ -25,10 +27,13 @@ func init() {
 	registerEndpoint("/v1/acl/tokens", []string{"GET"}, (*HTTPHandlers).ACLTokenList)
 	registerEndpoint("/v1/acl/token", []string{"PUT"}, (*HTTPHandlers).ACLTokenCreate)
 	registerEndpoint("/v1/acl/token/self", []string{"GET"}, (*HTTPHandlers).ACLTokenSelf)
+	registerEndpoint("/v1/acl/token/break-glass", []string{"PUT"}, (*HTTPHandlers).ACLTokenBreakGlass)
 	registerEndpoint("/v1/acl/token/", []string{"GET", "PUT", "DELETE"}, (*HTTPHandlers).ACLTokenCRUD)
 	registerEndpoint("/v1/acl/templated-policies", []string{"GET"}, (*HTTPHandlers).ACLTemplatedPoliciesList)
-	registerEndpoint("/v1/acl/templated-policy/name/", []string{"GET"}, (*HTTPHandlers).ACLTemplatedPolicyRead)
+	registerEndpoint("/v1/acl/templated-policy", []string{"PUT"}, (*HTTPHandlers).ACLTemplatedPolicyCreate)
+	registerEndpoint("/v1/acl/templated-policy/name/", []string{"GET", "PUT", "DELETE"}, (*HTTPHandlers).ACLTemplatedPolicyCRUD)
 	registerEndpoint("/v1/acl/templated-policy/preview/", []string{"POST"}, (*HTTPHandlers).ACLTemplatedPolicyPreview)
+	registerEndpoint("/v1/acl/authorize/explain", []string{"POST"}, (*HTTPHandlers).ACLAuthorizeExplain)
 	registerEndpoint("/v1/agent/token/", []string{"PUT"}, (*HTTPHandlers).AgentToken)
 	registerEndpoint("/v1/agent/self", []string{"GET"}, (*HTTPHandlers).AgentSelf)
 	registerEndpoint("/v1/agent/host", []string{"GET"}, (*HTTPHandlers).AgentHost)
@@ -73,6 +78,14 @@ func init() {
 	registerEndpoint("/v1/config", []string{"PUT"}, (*HTTPHandlers).ConfigApply)
 	registerEndpoint("/v1/connect/ca/configuration", []string{"GET", "PUT"}, (*HTTPHandlers).ConnectCAConfiguration)
 	registerEndpoint("/v1/connect/ca/roots", []string{"GET"}, (*HTTPHandlers).ConnectCARoots)
+	registerEndpoint("/v1/connect/ca/spiffe-bundle", []string{"GET"}, (*HTTPHandlers).ConnectCASPIFFEBundle)
+	registerEndpoint("/v1/connect/ca/status", []string{"GET"}, (*HTTPHandlers).ConnectCAStatus)
+	registerEndpoint("/v1/connect/ca/intermediate-csr", []string{"PUT"}, (*HTTPHandlers).ConnectCAIntermediateCSR)
+	registerEndpoint("/v1/connect/ca/intermediate", []string{"PUT"}, (*HTTPHandlers).ConnectCASetIntermediate)
+	registerEndpoint("/v1/connect/ca/revoke", []string{"PUT"}, (*HTTPHandlers).ConnectCARevoke)
+	registerEndpoint("/v1/connect/ca/crl", []string{"GET"}, (*HTTPHandlers).ConnectCARevocationList)
+	registerEndpoint("/v1/connect/ca/ocsp", []string{"POST"}, (*HTTPHandlers).ConnectCAOCSP)
+	registerEndpoint("/v1/connect/ca/ocsp/", []string{"GET"}, (*HTTPHandlers).ConnectCAOCSP)
 	registerEndpoint("/v1/connect/intentions", []string{"GET", "POST"}, (*HTTPHandlers).IntentionEndpoint) // POST is deprecated
 	registerEndpoint("/v1/connect/intentions/match", []string{"GET"}, (*HTTPHandlers).IntentionMatch)
 	registerEndpoint("/v1/connect/intentions/check", []string{"GET"}, (*HTTPHandlers).IntentionCheck)
@@ -100,6 +113,7 @@ func init() {
 	registerEndpoint("/v1/internal/ui/services", []string{"GET"}, (*HTTPHandlers).UIServices)
 	registerEndpoint("/v1/internal/ui/exported-services", []string{"GET"}, (*HTTPHandlers).UIExportedServices)
 	registerEndpoint("/v1/internal/ui/catalog-overview", []string{"GET"}, (*HTTPHandlers).UICatalogOverview)
+	registerEndpoint("/v1/internal/ui/oidc-auth-methods", []string{"GET"}, (*HTTPHandlers).UIOIDCAuthMethods)
 	registerEndpoint("/v1/internal/ui/gateway-services-nodes/", []string{"GET"}, (*HTTPHandlers).UIGatewayServicesNodes)
 	registerEndpoint("/v1/internal/ui/gateway-intentions/", []string{"GET"}, (*HTTPHandlers).UIGatewayIntentions)
 	registerEndpoint("/v1/internal/ui/service-topology/", []string{"GET"}, (*HTTPHandlers).UIServiceTopology)
@@ -107,10 +121,12 @@ func init() {
 	registerEndpoint("/v1/internal/service-virtual-ip", []string{"PUT"}, (*HTTPHandlers).AssignManualServiceVIPs)
 	registerEndpoint("/v1/kv/", []string{"GET", "PUT", "DELETE"}, (*HTTPHandlers).KVSEndpoint)
 	registerEndpoint("/v1/operator/raft/configuration", []string{"GET"}, (*HTTPHandlers).OperatorRaftConfiguration)
+	registerEndpoint("/v1/operator/raft/log", []string{"GET"}, (*HTTPHandlers).OperatorRaftLog)
 	registerEndpoint("/v1/operator/raft/transfer-leader", []string{"POST"}, (*HTTPHandlers).OperatorRaftTransferLeader)
 	registerEndpoint("/v1/operator/raft/peer", []string{"DELETE"}, (*HTTPHandlers).OperatorRaftPeer)
 	registerEndpoint("/v1/operator/keyring", []string{"GET", "POST", "PUT", "DELETE"}, (*HTTPHandlers).OperatorKeyringEndpoint)
 	registerEndpoint("/v1/operator/usage", []string{"GET"}, (*HTTPHandlers).OperatorUsage)
+	registerEndpoint("/v1/operator/audit-hash", []string{"POST"}, (*HTTPHandlers).OperatorAuditHash)
 	registerEndpoint("/v1/operator/autopilot/configuration", []string{"GET", "PUT"}, (*HTTPHandlers).OperatorAutopilotConfiguration)
 	registerEndpoint("/v1/operator/autopilot/health", []string{"GET"}, (*HTTPHandlers).OperatorServerHealth)
 	registerEndpoint("/v1/operator/autopilot/state", []string{"GET"}, (*HTTPHandlers).OperatorAutopilotState)
@@ -30,6 +30,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/net/http2"
 
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/config"
 	"github.com/hashicorp/consul/agent/consul"
 	"github.com/hashicorp/consul/agent/structs"
@@ -493,6 +495,141 @@ func TestHTTPAPI_Ban_Nonprintable_Characters(t *testing.T) {
 	}
 }
 
+func TestHTTPAPI_Audit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	auditPath := filepath.Join(testutil.TempDir(t, "audit"), "audit.json")
+	a := NewTestAgent(t, TestACLConfig()+fmt.Sprintf(`
+		audit {
+			enabled = true
+			rpc_enabled = true
+			exclude = ["/v1/agent/*", "Status.*"]
+			sink "file" {
+				path = %q
+				rotate_duration = "24h"
+			}
+		}
+	`, auditPath))
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1", testrpc.WithToken("root"))
+
+	serve := func(method, url, token string, body io.Reader) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, url, body)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("X-Consul-Token", token)
+		}
+		resp := httptest.NewRecorder()
+		a.srv.handler().ServeHTTP(resp, req)
+		return resp
+	}
+
+	require.Equal(t, http.StatusOK, serve("PUT", "/v1/kv/foo", "root", strings.NewReader("bar")).Code)
+	require.Equal(t, http.StatusOK, serve("GET", "/v1/kv/foo?token=root", "", nil).Code)
+	require.Equal(t, http.StatusForbidden, serve("GET", "/v1/kv/foo", "", nil).Code)
+	require.Equal(t, http.StatusOK, serve("GET", "/v1/agent/self", "root", nil).Code)
+
+	resp := serve("POST", "/v1/operator/audit-hash", "root", strings.NewReader(`{"Input": "root"}`))
+	require.Equal(t, http.StatusOK, resp.Code)
+	var hash api.AuditHashResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&hash))
+	require.NotEmpty(t, hash.Hash)
+	require.NotContains(t, resp.Body.String(), "root")
+
+	raw, err := os.ReadFile(auditPath)
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), `"root"`, "tokens should never be written in the clear")
+
+	var httpEvents, rpcEvents []*audit.Event
+	for _, line := range strings.Split(strings.TrimSpace(string(raw)), "\n") {
+		var entry audit.Entry
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		require.NotEqual(t, "/v1/agent/self", entry.Payload.Request.Endpoint)
+		require.False(t, strings.HasPrefix(entry.Payload.Request.Endpoint, "Status."))
+		switch entry.Payload.Type {
+		case audit.EventTypeHTTP:
+			httpEvents = append(httpEvents, entry.Payload)
+		case audit.EventTypeRPC:
+			rpcEvents = append(rpcEvents, entry.Payload)
+		}
+	}
+
+	require.Len(t, httpEvents, 4)
+	put := httpEvents[0]
+	require.Equal(t, "PUT", put.Request.Operation)
+	require.Equal(t, "/v1/kv/foo", put.Request.Endpoint)
+	require.Equal(t, "200", put.Response.Status)
+	require.Equal(t, audit.OutcomeSuccess, put.Response.Outcome)
+	require.Equal(t, hash.Hash, put.Auth.SecretID)
+	require.NotEmpty(t, put.Auth.AccessorID)
+	require.NotEqual(t, acl.AnonymousTokenID, put.Auth.AccessorID)
+
+	get := httpEvents[1]
+	require.Equal(t, hash.Hash, get.Request.QueryParams["token"])
+	require.Equal(t, put.Auth.AccessorID, get.Auth.AccessorID)
+
+	denied := httpEvents[2]
+	require.Equal(t, "403", denied.Response.Status)
+	require.Equal(t, audit.OutcomeError, denied.Response.Outcome)
+	require.Equal(t, acl.AnonymousTokenID, denied.Auth.AccessorID)
+
+	require.Equal(t, "/v1/operator/audit-hash", httpEvents[3].Request.Endpoint)
+
+	var applied bool
+	for _, ev := range rpcEvents {
+		if ev.Request.Endpoint == "KVS.Apply" {
+			applied = true
+			require.Equal(t, "write", ev.Request.Operation)
+			require.Equal(t, put.Auth.AccessorID, ev.Auth.AccessorID)
+			require.Equal(t, hash.Hash, ev.Auth.SecretID)
+		}
+	}
+	require.True(t, applied, "expected the KVS.Apply RPC to be audited")
+}
+
+func TestAuditResponseWriter_Hijack(t *testing.T) {
+	t.Parallel()
+
+	statusCh := make(chan int, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		w := &auditResponseWriter{ResponseWriter: resp, status: http.StatusOK}
+		conn, _, err := w.Hijack()
+		require.NoError(t, err)
+		conn.Close()
+		statusCh <- w.status
+	}))
+	defer srv.Close()
+
+	// The connection is closed without a response.
+	_, err := http.Get(srv.URL)
+	require.Error(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, <-statusCh)
+
+	w := &auditResponseWriter{ResponseWriter: httptest.NewRecorder(), status: http.StatusOK}
+	_, _, err = w.Hijack()
+	require.ErrorContains(t, err, "does not support hijacking")
+}
+
+func TestOperator_AuditHash_Disabled(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+
+	req, err := http.NewRequest("POST", "/v1/operator/audit-hash", strings.NewReader(`{"Input": "foo"}`))
+	require.NoError(t, err)
+	resp := httptest.NewRecorder()
+	a.srv.handler().ServeHTTP(resp, req)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Contains(t, resp.Body.String(), "Audit logging is not enabled")
+}
+
 func TestHTTPAPI_Allow_Nonprintable_Characters_With_Flag(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -858,6 +995,19 @@ func testPrettyPrint(pretty string, t *testing.T) {
 	}
 }
 
+func TestRemoteIPFromRequest(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest("PUT", "/v1/kv/foo", nil)
+	req.RemoteAddr = "198.18.0.1:51234"
+	req.Header.Set("X-Forwarded-For", "10.0.0.1")
+	require.Equal(t, "198.18.0.1", remoteIPFromRequest(req))
+	require.Equal(t, "10.0.0.1", sourceAddrFromRequest(req))
+
+	req.RemoteAddr = "invalid"
+	require.Empty(t, remoteIPFromRequest(req))
+}
+
 func TestParseSource(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
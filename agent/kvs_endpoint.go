@@ -173,6 +173,7 @@ func (s *HTTPHandlers) KVSPut(resp http.ResponseWriter, req *http.Request, args
 		},
 	}
 	applyReq.Token = args.Token
+	applyReq.SourceIP = remoteIPFromRequest(req)
 
 	// Check for flags
 	params := req.URL.Query()
@@ -252,6 +253,7 @@ func (s *HTTPHandlers) KVSDelete(resp http.ResponseWriter, req *http.Request, ar
 		},
 	}
 	applyReq.Token = args.Token
+	applyReq.SourceIP = remoteIPFromRequest(req)
 
 	// Check for recurse
 	params := req.URL.Query()
@@ -0,0 +1,47 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package leafcert
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/consul/agent/cacheshim"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// NewCachedLeafCertConfigReader returns a LeafCertConfigReader that reads the
+// service-defaults config entries from the agent cache, which keeps them up to
+// date with a blocking query instead of reading them again for every leaf.
+func NewCachedLeafCertConfigReader(cache cacheshim.Cache) LeafCertConfigReader {
+	return &agentCacheLeafCertConfigReader{cache: cache}
+}
+
+type agentCacheLeafCertConfigReader struct {
+	cache cacheshim.Cache
+}
+
+var _ LeafCertConfigReader = (*agentCacheLeafCertConfigReader)(nil)
+
+func (r *agentCacheLeafCertConfigReader) LeafCertConfig(ctx context.Context, req *ConnectCALeafRequest) (*structs.LeafCertConfig, error) {
+	raw, _, err := r.cache.Get(ctx, cacheshim.ConfigEntryName, &structs.ConfigEntryQuery{
+		Kind:           structs.ServiceDefaults,
+		Name:           req.Service,
+		Datacenter:     req.Datacenter,
+		EnterpriseMeta: req.EnterpriseMeta,
+		QueryOptions:   structs.QueryOptions{Token: req.Token},
+	})
+	if err != nil {
+		return nil, err
+	}
+	reply, ok := raw.(*structs.ConfigEntryResponse)
+	if !ok {
+		return nil, errors.New("invalid ConfigEntry response type")
+	}
+	entry, ok := reply.Entry.(*structs.ServiceConfigEntry)
+	if !ok || entry == nil {
+		return nil, nil
+	}
+	return entry.LeafCert, nil
+}
@@ -5,6 +5,7 @@ package leafcert
 
 import (
 	"context"
+	"crypto"
 	"errors"
 	"fmt"
 	"net"
@@ -296,7 +297,25 @@ func (m *Manager) generateNewLeaf(
 	// instead intelligently pick the key type we generate here based on the key
 	// type of the active signing CA. We already have that loaded since we need
 	// the trust domain.
-	pk, pkPEM, err := connect.GeneratePrivateKey()
+	//
+	// Services may override the key type in their service-defaults, in which
+	// case the servers only sign CSRs with that key type.
+	var leafCfg *structs.LeafCertConfig
+	if req.Service != "" && m.configReader != nil {
+		leafCfg, err = m.configReader.LeafCertConfig(context.Background(), req)
+		if err != nil {
+			return nil, newState, fmt.Errorf("error reading the leaf certificate config of %q: %w", req.Service, err)
+		}
+	}
+	var (
+		pk    crypto.Signer
+		pkPEM string
+	)
+	if leafCfg != nil && leafCfg.PrivateKeyType != "" {
+		pk, pkPEM, err = connect.GeneratePrivateKeyWithConfig(leafCfg.PrivateKeyType, leafCfg.PrivateKeyBits)
+	} else {
+		pk, pkPEM, err = connect.GeneratePrivateKey()
+	}
 	if err != nil {
 		return nil, newState, err
 	}
@@ -312,6 +331,7 @@ func (m *Manager) generateNewLeaf(
 		WriteRequest: structs.WriteRequest{Token: req.Token},
 		Datacenter:   req.Datacenter,
 		CSR:          csr,
+		Node:         m.config.NodeName,
 	}
 
 	reply, err := m.certSigner.SignCert(context.Background(), &args)
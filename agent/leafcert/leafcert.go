@@ -38,6 +38,11 @@ const (
 )
 
 type Config struct {
+	// NodeName is the name of the local node. It is sent along with the
+	// signing requests so that the certificates issued for the node can be
+	// revoked together.
+	NodeName string
+
 	// LastGetTTL is the time that the certs returned by this type remain in
 	// the cache after the last get operation. If a cert isn't accessed within
 	// this duration, the certs is purged and background refreshing will cease.
@@ -100,6 +105,11 @@ type Deps struct {
 
 	// CertSigner is an interface to remotely sign certificates.
 	CertSigner CertSigner
+
+	// LeafCertConfigReader is an optional interface to read the leaf
+	// certificate overrides of services. When it is nil the default key type
+	// is used for all the certificates.
+	LeafCertConfigReader LeafCertConfigReader
 }
 
 type RootsReader interface {
@@ -111,6 +121,12 @@ type CertSigner interface {
 	SignCert(ctx context.Context, args *structs.CASignRequest) (*structs.IssuedCert, error)
 }
 
+type LeafCertConfigReader interface {
+	// LeafCertConfig returns the leaf certificate overrides of the service of
+	// the request, or nil if there are none.
+	LeafCertConfig(ctx context.Context, req *ConnectCALeafRequest) (*structs.LeafCertConfig, error)
+}
+
 func NewManager(deps Deps) *Manager {
 	deps.Config = deps.Config.withDefaults()
 
@@ -125,10 +141,11 @@ func NewManager(deps Deps) *Manager {
 	}
 
 	m := &Manager{
-		config:      deps.Config,
-		logger:      deps.Logger,
-		certSigner:  deps.CertSigner,
-		rootsReader: deps.RootsReader,
+		config:       deps.Config,
+		logger:       deps.Logger,
+		certSigner:   deps.CertSigner,
+		rootsReader:  deps.RootsReader,
+		configReader: deps.LeafCertConfigReader,
 		//
 		certs:           make(map[string]*certData),
 		certsExpiryHeap: ttlcache.NewExpiryHeap(),
@@ -159,6 +176,10 @@ type Manager struct {
 	// certSigner is an interface to remotely sign certificates.
 	certSigner CertSigner
 
+	// configReader is an optional interface to read the leaf certificate
+	// overrides of services.
+	configReader LeafCertConfigReader
+
 	// rootWatcher helps let multiple requests for leaf certs to coordinate
 	// sharing a single long-lived watch for the root certs. This allows the
 	// leaf cert requests to notice when the roots rotate and trigger their
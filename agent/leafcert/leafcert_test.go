@@ -657,6 +657,46 @@ func TestManager_DNSSANForService(t *testing.T) {
 	require.Equal(t, csr.DNSNames, []string{"test.example.com"})
 }
 
+func TestManager_LeafCertKeyTypeForService(t *testing.T) {
+	t.Parallel()
+
+	m, signer := NewTestManager(t, nil)
+	m.configReader = testLeafCertConfigReader{
+		"web": {PrivateKeyType: "rsa", PrivateKeyBits: 2048},
+	}
+
+	_ = signer.UpdateCA(t, nil)
+
+	for i, service := range []string{"web", "api"} {
+		req := &ConnectCALeafRequest{
+			Datacenter: "dc1",
+			Service:    service,
+		}
+		_, _, err := m.Get(context.Background(), req)
+		require.NoError(t, err)
+
+		caReq := signer.GetCapture(i)
+		require.NotNil(t, caReq)
+		csr, err := connect.ParseCSR(caReq.CSR)
+		require.NoError(t, err)
+		keyType, keyBits, err := connect.KeyInfoFromPublicKey(csr.PublicKey)
+		require.NoError(t, err)
+		if service == "web" {
+			require.Equal(t, "rsa", keyType)
+			require.Equal(t, 2048, keyBits)
+		} else {
+			require.Equal(t, connect.DefaultPrivateKeyType, keyType)
+			require.Equal(t, connect.DefaultPrivateKeyBits, keyBits)
+		}
+	}
+}
+
+type testLeafCertConfigReader map[string]*structs.LeafCertConfig
+
+func (r testLeafCertConfigReader) LeafCertConfig(_ context.Context, req *ConnectCALeafRequest) (*structs.LeafCertConfig, error) {
+	return r[req.Service], nil
+}
+
 func TestManager_workflow_good(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -38,6 +38,46 @@ func (s *HTTPHandlers) OperatorRaftConfiguration(resp http.ResponseWriter, req *
 	return reply, nil
 }
 
+// OperatorRaftLog is used to list and decode recent entries from the Raft log.
+// With the stale query mode the log of the server handling the request is read
+// instead of the leader's.
+func (s *HTTPHandlers) OperatorRaftLog(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.RaftLogRequest
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	params := req.URL.Query()
+	for _, name := range []string{"min_index", "max_index"} {
+		if _, ok := params[name]; !ok {
+			continue
+		}
+		val, err := strconv.ParseUint(params.Get(name), 10, 64)
+		if err != nil {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Error parsing %s value: %v", name, err)}
+		}
+		if name == "min_index" {
+			args.MinIndex = val
+		} else {
+			args.MaxIndex = val
+		}
+	}
+	if _, ok := params["limit"]; ok {
+		limit, err := strconv.Atoi(params.Get("limit"))
+		if err != nil || limit < 0 {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Error parsing limit value: %q", params.Get("limit"))}
+		}
+		args.Limit = limit
+	}
+	args.Types = params["type"]
+
+	var reply structs.RaftLogResponse
+	if err := s.agent.RPC(req.Context(), "Operator.RaftGetLog", &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
 // OperatorRaftTransferLeader is used to transfer raft cluster leadership to another node
 func (s *HTTPHandlers) OperatorRaftTransferLeader(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 
@@ -407,6 +447,35 @@ RETRY_ONCE:
 	return out, nil
 }
 
+// OperatorAuditHash returns the hash of the given input as it would appear in
+// this agent's audit log, so that hashed values can be searched for.
+func (s *HTTPHandlers) OperatorAuditHash(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var token string
+	s.parseToken(req, &token)
+	authz, err := s.agent.delegate.ResolveTokenAndDefaultMeta(token, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := authz.ToAllowAuthorizer().OperatorReadAllowed(nil); err != nil {
+		return nil, err
+	}
+
+	auditor := s.agent.baseDeps.Auditor
+	if auditor == nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Audit logging is not enabled on this agent"}
+	}
+
+	var args api.AuditHashRequest
+	if err := decodeBody(req.Body, &args); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request decode failed: %v", err)}
+	}
+	if args.Input == "" {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Missing input to hash"}
+	}
+
+	return api.AuditHashResponse{Hash: auditor.Hash(args.Input)}, nil
+}
+
 func stringIDs(ids []raft.ServerID) []string {
 	out := make([]string, len(ids))
 	for i, id := range ids {
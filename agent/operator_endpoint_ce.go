@@ -6,16 +6,90 @@
 package agent
 
 import (
-	"github.com/hashicorp/consul/api"
 	autopilot "github.com/hashicorp/raft-autopilot"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
 )
 
-func autopilotToAPIServerEnterprise(_ *autopilot.ServerState, _ *api.AutopilotServer) {
-	// noop in ce
+func autopilotToAPIServerEnterprise(srv *autopilot.ServerState, apiSrv *api.AutopilotServer) {
+	var ext structs.AutopilotServerExt
+	if !decodeAutopilotExt(srv.Server.Ext, &ext) {
+		return
+	}
+	apiSrv.RedundancyZone = ext.RedundancyZone
+	apiSrv.UpgradeVersion = ext.UpgradeVersion
+	apiSrv.ReadReplica = ext.ReadReplica
 }
 
 func autopilotToAPIStateEnterprise(state *autopilot.State, apiState *api.AutopilotState) {
-	// without the enterprise features there is no different between these two and we don't want to
-	// alarm anyone by leaving this as the zero value.
+	// without the promoter's state there is no different between these two and we don't
+	// want to alarm anyone by leaving this as the zero value.
 	apiState.OptimisticFailureTolerance = state.FailureTolerance
+
+	var ext structs.AutopilotStateExt
+	if !decodeAutopilotExt(state.Ext, &ext) {
+		return
+	}
+	if ext.OptimisticFailureTolerance > state.FailureTolerance {
+		apiState.OptimisticFailureTolerance = ext.OptimisticFailureTolerance
+	}
+
+	if len(ext.ReadReplicas) > 0 {
+		apiState.ReadReplicas = stringIDs(ext.ReadReplicas)
+	}
+
+	if len(ext.RedundancyZones) > 0 {
+		apiState.RedundancyZones = make(map[string]api.AutopilotZone, len(ext.RedundancyZones))
+		for name, zone := range ext.RedundancyZones {
+			apiState.RedundancyZones[name] = api.AutopilotZone{
+				Servers:          stringIDs(zone.Servers),
+				Voters:           stringIDs(zone.Voters),
+				FailureTolerance: zone.FailureTolerance,
+			}
+		}
+	}
+
+	if u := ext.Upgrade; u != nil {
+		apiState.Upgrade = &api.AutopilotUpgrade{
+			Status:                 api.AutopilotUpgradeStatus(u.Status),
+			TargetVersion:          u.TargetVersion,
+			TargetVersionVoters:    stringIDs(u.TargetVersionVoters),
+			TargetVersionNonVoters: stringIDs(u.TargetVersionNonVoters),
+			OtherVersionVoters:     stringIDs(u.OtherVersionVoters),
+			OtherVersionNonVoters:  stringIDs(u.OtherVersionNonVoters),
+
+			TargetVersionReadReplicas: stringIDs(u.TargetVersionReadReplicas),
+			OtherVersionReadReplicas:  stringIDs(u.OtherVersionReadReplicas),
+		}
+		if len(u.RedundancyZones) > 0 {
+			apiState.Upgrade.RedundancyZones = make(map[string]api.AutopilotZoneUpgradeVersions, len(u.RedundancyZones))
+			for name, zone := range u.RedundancyZones {
+				apiState.Upgrade.RedundancyZones[name] = api.AutopilotZoneUpgradeVersions{
+					TargetVersionVoters:    stringIDs(zone.TargetVersionVoters),
+					TargetVersionNonVoters: stringIDs(zone.TargetVersionNonVoters),
+					OtherVersionVoters:     stringIDs(zone.OtherVersionVoters),
+					OtherVersionNonVoters:  stringIDs(zone.OtherVersionNonVoters),
+				}
+			}
+		}
+	}
+}
+
+// decodeAutopilotExt decodes the promoter's extended state into out. The
+// state is the promoter's own type when it was read from the local server but
+// a generic map once it has been through an RPC.
+func decodeAutopilotExt(ext interface{}, out interface{}) bool {
+	if ext == nil {
+		return false
+	}
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		WeaklyTypedInput: true,
+		Result:           out,
+	})
+	if err != nil {
+		return false
+	}
+	return decoder.Decode(ext) == nil
 }
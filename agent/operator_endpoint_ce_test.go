@@ -6,13 +6,19 @@
 package agent
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
-	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul-net-rpc/go-msgpack/codec"
+	"github.com/hashicorp/raft"
+	autopilot "github.com/hashicorp/raft-autopilot"
 	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
 )
 
 func TestOperator_Usage(t *testing.T) {
@@ -70,6 +76,85 @@ func TestOperator_Usage(t *testing.T) {
 	require.Equal(t, expected, raw.(structs.Usage).Usage)
 }
 
+func TestAutopilotStateToAPIConversion_Ext(t *testing.T) {
+	input := &autopilot.State{
+		Healthy:          true,
+		FailureTolerance: 0,
+		Leader:           "a1",
+		Voters:           []raft.ServerID{"a1", "b1"},
+		Servers: map[raft.ServerID]*autopilot.ServerState{
+			"a1": {
+				Server: autopilot.Server{
+					ID:       "a1",
+					NodeType: structs.AutopilotNodeZoneVoter,
+					Ext:      &structs.AutopilotServerExt{RedundancyZone: "a", UpgradeVersion: "1.17.0"},
+				},
+				State: autopilot.RaftLeader,
+			},
+			"a2": {
+				Server: autopilot.Server{
+					ID:       "a2",
+					NodeType: structs.AutopilotNodeZoneStandby,
+					Ext:      &structs.AutopilotServerExt{RedundancyZone: "a", UpgradeVersion: "1.17.0"},
+				},
+				State: autopilot.RaftNonVoter,
+			},
+			"b1": {
+				Server: autopilot.Server{
+					ID:       "b1",
+					NodeType: structs.AutopilotNodeZoneVoter,
+					Ext:      &structs.AutopilotServerExt{RedundancyZone: "b", UpgradeVersion: "1.17.0"},
+				},
+				State: autopilot.RaftVoter,
+			},
+		},
+		Ext: &structs.AutopilotStateExt{
+			OptimisticFailureTolerance: 1,
+			RedundancyZones: map[string]structs.AutopilotZone{
+				"a": {Servers: []raft.ServerID{"a1", "a2"}, Voters: []raft.ServerID{"a1"}, FailureTolerance: 1},
+				"b": {Servers: []raft.ServerID{"b1"}, Voters: []raft.ServerID{"b1"}},
+			},
+			Upgrade: &structs.AutopilotUpgrade{
+				Status:                 structs.AutopilotUpgradeIdle,
+				TargetVersion:          "1.17.0",
+				TargetVersionVoters:    []raft.ServerID{"a1", "b1"},
+				TargetVersionNonVoters: []raft.ServerID{"a2"},
+			},
+		},
+	}
+
+	check := func(t *testing.T, state *autopilot.State) {
+		out := autopilotToAPIState(state)
+		require.Equal(t, 1, out.OptimisticFailureTolerance)
+		require.Equal(t, map[string]api.AutopilotZone{
+			"a": {Servers: []string{"a1", "a2"}, Voters: []string{"a1"}, FailureTolerance: 1},
+			"b": {Servers: []string{"b1"}, Voters: []string{"b1"}},
+		}, out.RedundancyZones)
+		require.Equal(t, api.AutopilotUpgradeIdle, out.Upgrade.Status)
+		require.Equal(t, "1.17.0", out.Upgrade.TargetVersion)
+		require.Equal(t, []string{"a1", "b1"}, out.Upgrade.TargetVersionVoters)
+		require.Equal(t, []string{"a2"}, out.Upgrade.TargetVersionNonVoters)
+		require.Equal(t, "a", out.Servers["a2"].RedundancyZone)
+		require.Equal(t, "1.17.0", out.Servers["a2"].UpgradeVersion)
+		require.Equal(t, api.AutopilotTypeZoneStandby, out.Servers["a2"].NodeType)
+	}
+
+	t.Run("local", func(t *testing.T) {
+		check(t, input)
+	})
+
+	t.Run("rpc", func(t *testing.T) {
+		// The state read from a remote server has the ext fields decoded
+		// as generic maps.
+		var buf bytes.Buffer
+		require.NoError(t, codec.NewEncoder(&buf, structs.MsgpackHandle).Encode(input))
+		var decoded autopilot.State
+		require.NoError(t, structs.Decode(buf.Bytes(), &decoded))
+		require.IsType(t, map[string]interface{}{}, decoded.Ext)
+		check(t, &decoded)
+	})
+}
+
 func upsertTestService(rpc rpcFn, secret, datacenter, name, node, partition string, modifyFuncs ...func(*structs.NodeService)) error {
 	req := structs.RegisterRequest{
 		Datacenter:     datacenter,
@@ -7,8 +7,11 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/go-memdb"
+
 	"github.com/hashicorp/consul/agent/cache"
 	cachetype "github.com/hashicorp/consul/agent/cache-types"
+	"github.com/hashicorp/consul/agent/consul/watch"
 	"github.com/hashicorp/consul/agent/proxycfg"
 	"github.com/hashicorp/consul/agent/rpcclient/configentry"
 	"github.com/hashicorp/consul/agent/structs"
@@ -45,6 +48,12 @@ type serverConfigEntry struct {
 }
 
 func (e serverConfigEntry) Notify(ctx context.Context, req *structs.ConfigEntryQuery, correlationID string, ch chan<- proxycfg.UpdateEvent) error {
+	if req.Kind == structs.FederatedTrustBundle && req.Name == "" {
+		// There is no event stream topic for this kind so fall back to a
+		// blocking query against the state store.
+		return e.notifyConfigEntryList(ctx, req, correlationID, ch)
+	}
+
 	cfgReq, err := newConfigEntryRequest(req, e.deps)
 	if err != nil {
 		return err
@@ -52,6 +61,38 @@ func (e serverConfigEntry) Notify(ctx context.Context, req *structs.ConfigEntryQ
 	return e.deps.ViewStore.NotifyCallback(ctx, cfgReq, correlationID, dispatchCacheUpdate(ch))
 }
 
+func (e serverConfigEntry) notifyConfigEntryList(ctx context.Context, req *structs.ConfigEntryQuery, correlationID string, ch chan<- proxycfg.UpdateEvent) error {
+	return watch.ServerLocalNotify(ctx, correlationID, e.deps.GetStore,
+		func(ws memdb.WatchSet, store Store) (uint64, *structs.IndexedConfigEntries, error) {
+			authz, err := e.deps.ACLResolver.ResolveTokenAndDefaultMeta(req.Token, &req.EnterpriseMeta, nil)
+			if err != nil {
+				return 0, nil, err
+			}
+
+			index, entries, err := store.ConfigEntriesByKind(ws, req.Kind, &req.EnterpriseMeta)
+			if err != nil {
+				return 0, nil, err
+			}
+
+			result := &structs.IndexedConfigEntries{
+				Kind: req.Kind,
+				QueryMeta: structs.QueryMeta{
+					Backend: structs.QueryBackendBlocking,
+					Index:   index,
+				},
+			}
+			for _, entry := range entries {
+				if err := entry.CanRead(authz); err != nil {
+					continue
+				}
+				result.Entries = append(result.Entries, entry)
+			}
+			return index, result, nil
+		},
+		dispatchBlockingQueryUpdate[*structs.IndexedConfigEntries](ch),
+	)
+}
+
 func newConfigEntryRequest(req *structs.ConfigEntryQuery, deps ServerDataSourceDeps) (*configEntryRequest, error) {
 	var topic pbsubscribe.Topic
 	switch req.Kind {
@@ -106,6 +106,18 @@ func (s *handlerConnectProxy) initialize(ctx context.Context) (ConfigSnapshot, e
 		return snap, err
 	}
 
+	// Watch for the trust bundles imported from foreign trust domains, which
+	// are accepted by the public listener like the ones of the peers.
+	err = s.dataSources.ConfigEntryList.Notify(ctx, &structs.ConfigEntryQuery{
+		Kind:           structs.FederatedTrustBundle,
+		Datacenter:     s.source.Datacenter,
+		QueryOptions:   structs.QueryOptions{Token: s.token},
+		EnterpriseMeta: *structs.DefaultEnterpriseMetaInPartition(s.proxyID.PartitionOrDefault()),
+	}, federatedTrustBundlesWatchID, s.ch)
+	if err != nil {
+		return snap, err
+	}
+
 	// Get information about the entire service mesh.
 	err = s.dataSources.ConfigEntry.Notify(ctx, &structs.ConfigEntryQuery{
 		Kind:           structs.MeshConfig,
@@ -355,6 +367,25 @@ func (s *handlerConnectProxy) handleUpdate(ctx context.Context, u UpdateEvent, s
 		}
 
 		snap.JWTProviders = providers
+	case u.CorrelationID == federatedTrustBundlesWatchID:
+		resp, ok := u.Result.(*structs.IndexedConfigEntries)
+		if !ok {
+			return fmt.Errorf("invalid type for response: %T", u.Result)
+		}
+
+		bundles := make([]*pbpeering.PeeringTrustBundle, 0, len(resp.Entries))
+		for _, entry := range resp.Entries {
+			bundleEntry, ok := entry.(*structs.FederatedTrustBundleConfigEntry)
+			if !ok {
+				return fmt.Errorf("invalid type for response: %T", entry)
+			}
+			bundles = append(bundles, &pbpeering.PeeringTrustBundle{
+				TrustDomain: bundleEntry.TrustDomain,
+				RootPEMs:    bundleEntry.RootPEMs,
+				Partition:   bundleEntry.PartitionOrEmpty(),
+			})
+		}
+		snap.ConnectProxy.FederatedTrustBundles = bundles
 	case u.CorrelationID == peeredUpstreamsID:
 		resp, ok := u.Result.(*structs.IndexedPeeredServiceList)
 		if !ok {
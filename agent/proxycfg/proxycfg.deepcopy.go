@@ -156,6 +156,10 @@ func (o *ConfigSnapshotUpstreams) DeepCopy() *ConfigSnapshotUpstreams {
 	if o.Leaf != nil {
 		cp.Leaf = new(structs.IssuedCert)
 		*cp.Leaf = *o.Leaf
+		if o.Leaf.RevokedAt != nil {
+			cp.Leaf.RevokedAt = new(time.Time)
+			*cp.Leaf.RevokedAt = *o.Leaf.RevokedAt
+		}
 	}
 	if o.MeshConfig != nil {
 		cp.MeshConfig = o.MeshConfig.DeepCopy()
@@ -432,6 +436,15 @@ func (o *configSnapshotConnectProxy) DeepCopy() *configSnapshotConnectProxy {
 			}
 		}
 	}
+	if o.FederatedTrustBundles != nil {
+		cp.FederatedTrustBundles = make([]*pbpeering.PeeringTrustBundle, len(o.FederatedTrustBundles))
+		copy(cp.FederatedTrustBundles, o.FederatedTrustBundles)
+		for i2 := range o.FederatedTrustBundles {
+			if o.FederatedTrustBundles[i2] != nil {
+				cp.FederatedTrustBundles[i2] = o.FederatedTrustBundles[i2].DeepCopy()
+			}
+		}
+	}
 	if o.WatchedServiceChecks != nil {
 		cp.WatchedServiceChecks = make(map[structs.ServiceID][]structs.CheckType, len(o.WatchedServiceChecks))
 		for k2, v2 := range o.WatchedServiceChecks {
@@ -675,6 +688,10 @@ func (o *configSnapshotMeshGateway) DeepCopy() *configSnapshotMeshGateway {
 	if o.Leaf != nil {
 		cp.Leaf = new(structs.IssuedCert)
 		*cp.Leaf = *o.Leaf
+		if o.Leaf.RevokedAt != nil {
+			cp.Leaf.RevokedAt = new(time.Time)
+			*cp.Leaf.RevokedAt = *o.Leaf.RevokedAt
+		}
 	}
 	if o.PeerServers != nil {
 		cp.PeerServers = make(map[string]PeerServersValue, len(o.PeerServers))
@@ -746,6 +763,10 @@ func (o *configSnapshotTerminatingGateway) DeepCopy() *configSnapshotTerminating
 			if v2 != nil {
 				cp_ServiceLeaves_v2 = new(structs.IssuedCert)
 				*cp_ServiceLeaves_v2 = *v2
+				if v2.RevokedAt != nil {
+					cp_ServiceLeaves_v2.RevokedAt = new(time.Time)
+					*cp_ServiceLeaves_v2.RevokedAt = *v2.RevokedAt
+				}
 			}
 			cp.ServiceLeaves[k2] = cp_ServiceLeaves_v2
 		}
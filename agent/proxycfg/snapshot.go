@@ -147,6 +147,10 @@ type configSnapshotConnectProxy struct {
 	InboundPeerTrustBundlesSet bool
 	InboundPeerTrustBundles    []*pbpeering.PeeringTrustBundle
 
+	// FederatedTrustBundles are the trust bundles imported from foreign trust
+	// domains with federated-trust-bundle config entries.
+	FederatedTrustBundles []*pbpeering.PeeringTrustBundle
+
 	WatchedServiceChecks   map[structs.ServiceID][]structs.CheckType // TODO: missing garbage collection
 	PreparedQueryEndpoints map[UpstreamID]structs.CheckServiceNodes  // DEPRECATED:see:WatchedUpstreamEndpoints
 
@@ -1080,6 +1084,20 @@ func (s *ConfigSnapshot) PeeringTrustBundles() []*pbpeering.PeeringTrustBundle {
 	}
 }
 
+// InboundTrustBundles returns the trust bundles, besides the local roots,
+// whose certificates are accepted by the public listener: the ones of the
+// peers and, for connect proxies, the ones imported from foreign trust
+// domains.
+func (s *ConfigSnapshot) InboundTrustBundles() []*pbpeering.PeeringTrustBundle {
+	bundles := s.PeeringTrustBundles()
+	if s.Kind != structs.ServiceKindConnectProxy || len(s.ConnectProxy.FederatedTrustBundles) == 0 {
+		return bundles
+	}
+	out := make([]*pbpeering.PeeringTrustBundle, 0, len(bundles)+len(s.ConnectProxy.FederatedTrustBundles))
+	out = append(out, bundles...)
+	return append(out, s.ConnectProxy.FederatedTrustBundles...)
+}
+
 // RootPEMs returns all PEM-encoded public certificates for the root CA.
 func (s *ConfigSnapshot) RootPEMs() string {
 	var rootPEMs string
@@ -1089,6 +1107,28 @@ func (s *ConfigSnapshot) RootPEMs() string {
 	return rootPEMs
 }
 
+// RevocationList returns the PEM-encoded revocation lists of the leaf
+// certificates revoked in the local datacenter, or an empty string if none is.
+// They only cover the certificates signed in the local datacenter, so they
+// must only be used to verify the certificates of its services.
+func (s *ConfigSnapshot) RevocationList() string {
+	if s.Roots == nil {
+		return ""
+	}
+	return s.Roots.RevocationList
+}
+
+// DownstreamRevocationList returns the revocation lists to verify the
+// certificates of the downstreams with, or an empty string if some of them may
+// be signed outside of the local datacenter: by the other datacenters it is
+// federated with, or by the trust domains of the given bundles.
+func (s *ConfigSnapshot) DownstreamRevocationList(bundles []*pbpeering.PeeringTrustBundle) string {
+	if s.Roots == nil || s.Roots.WANFederated || len(bundles) > 0 {
+		return ""
+	}
+	return s.Roots.RevocationList
+}
+
 func (s *ConfigSnapshot) MeshConfig() *structs.MeshConfigEntry {
 	switch s.Kind {
 	case structs.ServiceKindConnectProxy:
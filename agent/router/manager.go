@@ -9,6 +9,7 @@
 package router
 
 import (
+	"math"
 	"math/rand"
 	"net"
 	"sync"
@@ -16,8 +17,10 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/serf/coordinate"
 
 	"github.com/hashicorp/consul/agent/metadata"
+	"github.com/hashicorp/consul/lib"
 	"github.com/hashicorp/consul/logging"
 )
 
@@ -80,6 +83,10 @@ type Manager struct {
 	// offline is used to indicate that there are no servers, or that all
 	// known servers have failed the ping test.
 	offline int32
+
+	// failedReadReplicas holds the names of the read replicas that failed an
+	// RPC since the last rebalance. FindStaleReadServer skips them.
+	failedReadReplicas sync.Map
 }
 
 // AddServer takes out an internal write lock and adds a new server.  If the
@@ -205,6 +212,59 @@ func (m *Manager) FindServer() *metadata.Server {
 	return l.servers[0]
 }
 
+// managerCoordinates is implemented by Serf clusters that track network
+// coordinates. It is used to estimate the RTT to each server.
+type managerCoordinates interface {
+	GetCoordinate() (*coordinate.Coordinate, error)
+	GetCachedCoordinate(name string) (*coordinate.Coordinate, bool)
+}
+
+// FindStaleReadServer returns the server to send a read that allows stale
+// results to. Read replicas are preferred since they don't take part in the
+// Raft quorum, and the nearest one is picked when network coordinates are
+// available. Read replicas that failed since the last rebalance are skipped.
+// If there are no other read replicas this is the same as FindServer.
+func (m *Manager) FindStaleReadServer() *metadata.Server {
+	l := m.getServerList()
+
+	var replicas []*metadata.Server
+	for _, srv := range l.servers {
+		if !srv.ReadReplica {
+			continue
+		}
+		if _, failed := m.failedReadReplicas.Load(srv.Name); failed {
+			continue
+		}
+		replicas = append(replicas, srv)
+	}
+	if len(replicas) == 0 {
+		return m.FindServer()
+	}
+
+	// Without coordinates fall back to the order of the server list, which
+	// is shuffled when rebalancing.
+	cluster, ok := m.clusterInfo.(managerCoordinates)
+	if !ok {
+		return replicas[0]
+	}
+	coord, err := cluster.GetCoordinate()
+	if err != nil || coord == nil {
+		return replicas[0]
+	}
+
+	best, bestRTT := replicas[0], math.Inf(1)
+	for _, srv := range replicas {
+		other, ok := cluster.GetCachedCoordinate(srv.Name)
+		if !ok {
+			continue
+		}
+		if rtt := lib.ComputeDistance(coord, other); rtt < bestRTT {
+			best, bestRTT = srv, rtt
+		}
+	}
+	return best
+}
+
 func (m *Manager) checkServers(fn func(srv *metadata.Server) bool) bool {
 	if m == nil {
 		return true
@@ -262,6 +322,12 @@ func New(logger hclog.Logger, shutdownCh chan struct{}, clusterInfo ManagerSerfC
 // NotifyFailedServer marks the passed in server as "failed" by rotating it
 // to the end of the server list.
 func (m *Manager) NotifyFailedServer(s *metadata.Server) {
+	// Stop sending stale reads to a failed read replica until the next
+	// rebalance, otherwise it would be picked again as the nearest one.
+	if s.ReadReplica {
+		m.failedReadReplicas.Store(s.Name, struct{}{})
+	}
+
 	l := m.getServerList()
 
 	// If the server being failed is not the first server on the list,
@@ -328,6 +394,12 @@ func (m *Manager) healthyServer(server *metadata.Server) bool {
 // deregistered.  Before the newly shuffled server list is saved, the new
 // remote endpoint is tested to ensure its responsive.
 func (m *Manager) RebalanceServers() {
+	// Give the read replicas that failed another chance.
+	m.failedReadReplicas.Range(func(name, _ any) bool {
+		m.failedReadReplicas.Delete(name)
+		return true
+	})
+
 	// Obtain a copy of the current serverList
 	l := m.getServerList()
 
@@ -451,6 +523,7 @@ func (m *Manager) RemoveServer(s *metadata.Server) {
 	m.listLock.Lock()
 	defer m.listLock.Unlock()
 	l := m.getServerList()
+	m.failedReadReplicas.Delete(s.Name)
 
 	// Remove the server if known
 	for i := range l.servers {
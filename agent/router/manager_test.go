@@ -9,9 +9,13 @@ import (
 	"net"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/hashicorp/serf/coordinate"
 
 	"github.com/hashicorp/consul/agent/metadata"
 	"github.com/hashicorp/consul/agent/router"
+	"github.com/hashicorp/consul/lib"
 	"github.com/hashicorp/consul/sdk/testutil"
 )
 
@@ -57,6 +61,20 @@ func (s *fauxSerf) NumNodes() int {
 	return 16384
 }
 
+type fauxCoordinateSerf struct {
+	fauxSerf
+	coords map[string]*coordinate.Coordinate
+}
+
+func (s *fauxCoordinateSerf) GetCoordinate() (*coordinate.Coordinate, error) {
+	return s.coords["client"], nil
+}
+
+func (s *fauxCoordinateSerf) GetCachedCoordinate(name string) (*coordinate.Coordinate, bool) {
+	coord, ok := s.coords[name]
+	return coord, ok
+}
+
 func testManager(t testing.TB) (m *router.Manager) {
 	logger := testutil.Logger(t)
 	shutdownCh := make(chan struct{})
@@ -197,6 +215,61 @@ func TestServers_FindServer(t *testing.T) {
 	}
 }
 
+func TestServers_FindStaleReadServer(t *testing.T) {
+	cluster := &fauxCoordinateSerf{
+		coords: map[string]*coordinate.Coordinate{
+			"client": lib.GenerateCoordinate(0),
+			"s1":     lib.GenerateCoordinate(time.Millisecond),
+			"r1":     lib.GenerateCoordinate(20 * time.Millisecond),
+			"r2":     lib.GenerateCoordinate(5 * time.Millisecond),
+		},
+	}
+
+	m := router.New(testutil.Logger(t), make(chan struct{}), cluster, &fauxConnPool{}, "", noopRebalancer)
+	if m.FindStaleReadServer() != nil {
+		t.Fatalf("Expected nil return")
+	}
+
+	// Without read replicas this is the same as FindServer.
+	m.AddServer(&metadata.Server{Name: "s1"})
+	if srv := m.FindStaleReadServer(); srv == nil || srv.Name != "s1" {
+		t.Fatalf("Expected s1 server, got %v", srv)
+	}
+
+	// The nearest read replica is preferred.
+	m.AddServer(&metadata.Server{Name: "r1", ReadReplica: true})
+	m.AddServer(&metadata.Server{Name: "r2", ReadReplica: true})
+	if srv := m.FindStaleReadServer(); srv == nil || srv.Name != "r2" {
+		t.Fatalf("Expected r2 server, got %v", srv)
+	}
+	if srv := m.FindServer(); srv == nil || srv.Name != "s1" {
+		t.Fatalf("Expected s1 server, got %v", srv)
+	}
+
+	// A read replica that failed is skipped until the next rebalance.
+	m.NotifyFailedServer(&metadata.Server{Name: "r2", ReadReplica: true})
+	if srv := m.FindStaleReadServer(); srv == nil || srv.Name != "r1" {
+		t.Fatalf("Expected r1 server, got %v", srv)
+	}
+	m.NotifyFailedServer(&metadata.Server{Name: "r1", ReadReplica: true})
+	if srv := m.FindStaleReadServer(); srv == nil || srv.Name != "s1" {
+		t.Fatalf("Expected s1 server, got %v", srv)
+	}
+	m.RebalanceServers()
+	if srv := m.FindStaleReadServer(); srv == nil || srv.Name != "r2" {
+		t.Fatalf("Expected r2 server, got %v", srv)
+	}
+
+	// Without coordinates the first read replica in the list is used.
+	m = testManager(t)
+	m.AddServer(&metadata.Server{Name: "s1"})
+	m.AddServer(&metadata.Server{Name: "r1", ReadReplica: true})
+	m.AddServer(&metadata.Server{Name: "r2", ReadReplica: true})
+	if srv := m.FindStaleReadServer(); srv == nil || srv.Name != "r1" {
+		t.Fatalf("Expected r1 server, got %v", srv)
+	}
+}
+
 func TestServers_New(t *testing.T) {
 	logger := testutil.Logger(t)
 	shutdownCh := make(chan struct{})
@@ -390,6 +390,18 @@ func (r *Router) FindLANRoute() (*Manager, *metadata.Server) {
 	return mgr, mgr.FindServer()
 }
 
+// FindLANStaleReadRoute is like FindLANRoute but returns the server to use
+// for a read that allows stale results, preferring the nearest read replica.
+func (r *Router) FindLANStaleReadRoute() (*Manager, *metadata.Server) {
+	mgr := r.GetLANManager()
+
+	if mgr == nil {
+		return nil, nil
+	}
+
+	return mgr, mgr.FindStaleReadServer()
+}
+
 // FindLANServer will look for a server in the local datacenter.
 // This function may return a nil value if no server is available.
 func (r *Router) FindLANServer() *metadata.Server {
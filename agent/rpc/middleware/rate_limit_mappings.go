@@ -9,37 +9,51 @@ import "github.com/hashicorp/consul/agent/consul/rate"
 // for rate limiting purposes. Please be sure to update this list
 // if a net/rpc endpoint is removed.
 var rpcRateLimitSpecs = map[string]rate.OperationSpec{
-	"ACL.AuthMethodDelete":  {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.AuthMethodList":    {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.AuthMethodRead":    {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.AuthMethodSet":     {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.Authorize":         {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.BindingRuleDelete": {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.BindingRuleList":   {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.BindingRuleRead":   {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.BindingRuleSet":    {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.BootstrapTokens":   {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.Login":             {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.Logout":            {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.PolicyBatchRead":   {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.PolicyDelete":      {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.PolicyList":        {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.PolicyRead":        {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.PolicyResolve":     {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.PolicySet":         {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.ReplicationStatus": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.RoleBatchRead":     {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.RoleDelete":        {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.RoleList":          {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.RoleRead":          {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.RoleResolve":       {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.RoleSet":           {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.TokenBatchRead":    {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.TokenClone":        {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.TokenDelete":       {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.TokenList":         {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.TokenRead":         {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.TokenSet":          {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.AuthMethodDelete":         {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.AuthMethodList":           {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.AuthMethodRead":           {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.AuthMethodSet":            {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.Authorize":                {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.AuthorizeExplain":         {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.BindingRuleDelete":        {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.BindingRuleList":          {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.BindingRuleRead":          {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.BindingRuleSet":           {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.BootstrapTokens":          {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.Login":                    {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.Logout":                   {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.OIDCAuthURL":              {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.OIDCCallback":             {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.PolicyBatchRead":          {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.PolicyDelete":             {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.PolicyList":               {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.PolicyRead":               {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.PolicyResolve":            {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.PolicySet":                {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.ReplicationStatus":        {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.RoleBatchRead":            {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.RoleDelete":               {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.RoleList":                 {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.RoleRead":                 {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.RoleResolve":              {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.RoleSet":                  {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.TemplatedPolicyBatchRead": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.TemplatedPolicyDelete":    {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.TemplatedPolicyList":      {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.TemplatedPolicyRead":      {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.TemplatedPolicyResolve":   {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.TemplatedPolicySet":       {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.TokenBatchRead":           {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.TokenClone":               {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.TokenDelete":              {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.TokenList":                {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.TokenRead":                {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.TokenLineage":             {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.TokenRenew":               {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.TokenRotate":              {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.TokenBreakGlass":          {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.TokenUsageUpdate":         {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.TokenSet":                 {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
 
 	"AutoConfig.InitialConfiguration": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryAutoConfig},
 
@@ -66,9 +80,14 @@ var rpcRateLimitSpecs = map[string]rate.OperationSpec{
 
 	"ConnectCA.ConfigurationGet": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryConnectCA},
 	"ConnectCA.ConfigurationSet": {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryConnectCA},
+	"ConnectCA.IntermediateCSR":  {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryConnectCA},
+	"ConnectCA.OCSP":             {Type: rate.OperationTypeRead, Category: rate.OperationCategoryConnectCA},
+	"ConnectCA.Revoke":           {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryConnectCA},
 	"ConnectCA.Roots":            {Type: rate.OperationTypeRead, Category: rate.OperationCategoryConnectCA},
+	"ConnectCA.SetIntermediate":  {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryConnectCA},
 	"ConnectCA.Sign":             {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryConnectCA},
 	"ConnectCA.SignIntermediate": {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryConnectCA},
+	"ConnectCA.Status":           {Type: rate.OperationTypeRead, Category: rate.OperationCategoryConnectCA},
 
 	"Coordinate.ListDatacenters": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryCoordinate},
 	"Coordinate.ListNodes":       {Type: rate.OperationTypeRead, Category: rate.OperationCategoryCoordinate},
@@ -105,6 +124,7 @@ var rpcRateLimitSpecs = map[string]rate.OperationSpec{
 	"Internal.KeyringOperation":              {Type: rate.OperationTypeRead, Category: rate.OperationCategoryInternal},
 	"Internal.NodeDump":                      {Type: rate.OperationTypeRead, Category: rate.OperationCategoryInternal},
 	"Internal.NodeInfo":                      {Type: rate.OperationTypeRead, Category: rate.OperationCategoryInternal},
+	"Internal.OIDCAuthMethods":               {Type: rate.OperationTypeRead, Category: rate.OperationCategoryInternal},
 	"Internal.PeeredUpstreams":               {Type: rate.OperationTypeRead, Category: rate.OperationCategoryInternal},
 	"Internal.ServiceDump":                   {Type: rate.OperationTypeRead, Category: rate.OperationCategoryInternal},
 	"Internal.ServiceGateways":               {Type: rate.OperationTypeRead, Category: rate.OperationCategoryInternal},
@@ -119,6 +139,7 @@ var rpcRateLimitSpecs = map[string]rate.OperationSpec{
 	"Operator.AutopilotSetConfiguration": {Type: rate.OperationTypeExempt, Category: rate.OperationCategoryOperator},
 	"Operator.AutopilotState":            {Type: rate.OperationTypeExempt, Category: rate.OperationCategoryOperator},
 	"Operator.RaftGetConfiguration":      {Type: rate.OperationTypeExempt, Category: rate.OperationCategoryOperator},
+	"Operator.RaftGetLog":                {Type: rate.OperationTypeExempt, Category: rate.OperationCategoryOperator},
 	"Operator.RaftRemovePeerByAddress":   {Type: rate.OperationTypeExempt, Category: rate.OperationCategoryOperator},
 	"Operator.RaftRemovePeerByID":        {Type: rate.OperationTypeExempt, Category: rate.OperationCategoryOperator},
 	"Operator.ServerHealth":              {Type: rate.OperationTypeExempt, Category: rate.OperationCategoryOperator},
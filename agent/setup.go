@@ -19,6 +19,7 @@ import (
 	"github.com/hashicorp/raft-wal/verifier"
 	"google.golang.org/grpc/grpclog"
 
+	"github.com/hashicorp/consul/agent/audit"
 	autoconf "github.com/hashicorp/consul/agent/auto-config"
 	"github.com/hashicorp/consul/agent/cache"
 	"github.com/hashicorp/consul/agent/config"
@@ -165,6 +166,11 @@ func NewBaseDeps(configLoader ConfigLoader, logOut io.Writer, providedLogger hcl
 		return d, err
 	}
 
+	d.Auditor, err = audit.New(cfg.Audit, cfg.DataDir, d.Logger.Named(logging.Audit))
+	if err != nil {
+		return d, fmt.Errorf("failed to initialize audit log: %w", err)
+	}
+
 	d.RuntimeConfig = cfg
 	d.Tokens = new(token.Store)
 
@@ -178,10 +184,12 @@ func NewBaseDeps(configLoader ConfigLoader, logOut io.Writer, providedLogger hcl
 
 	// TODO: create leafCertManager in BaseDeps once NetRPC is available without Agent
 	d.LeafCertManager = leafcert.NewManager(leafcert.Deps{
-		Logger:      d.Logger.Named("leaf-certs"),
-		CertSigner:  leafcert.NewNetRPCCertSigner(d.NetRPC),
-		RootsReader: leafcert.NewCachedRootsReader(d.Cache, cfg.Datacenter),
+		Logger:               d.Logger.Named("leaf-certs"),
+		CertSigner:           leafcert.NewNetRPCCertSigner(d.NetRPC),
+		LeafCertConfigReader: leafcert.NewCachedLeafCertConfigReader(d.Cache),
+		RootsReader:          leafcert.NewCachedRootsReader(d.Cache, cfg.Datacenter),
 		Config: leafcert.Config{
+			NodeName:                         cfg.NodeName,
 			TestOverrideCAChangeInitialDelay: cfg.ConnectTestCALeafRootChangeSpread,
 		},
 	})
@@ -273,6 +281,9 @@ func (bd BaseDeps) Close() {
 	bd.AutoConfig.Stop()
 	bd.LeafCertManager.Stop()
 	bd.MetricsConfig.Cancel()
+	if bd.Auditor != nil {
+		bd.Auditor.Close()
+	}
 
 	for _, fn := range []func(){bd.deregisterBalancer, bd.deregisterResolver, bd.stopHostCollector} {
 		if fn != nil {
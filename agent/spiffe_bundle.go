@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package agent
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	cachetype "github.com/hashicorp/consul/agent/cache-types"
+	"github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/agent/leafcert"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+const (
+	// spiffeBundleRefreshHint is how often the consumers of the SPIFFE bundle
+	// are told to check for updates. Roots are trusted for a long time after
+	// a rotation so this does not need to be short.
+	spiffeBundleRefreshHint = 5 * time.Minute
+
+	spiffeBundleProfileSPIFFE = "https_spiffe"
+	spiffeBundleProfileWeb    = "https_web"
+)
+
+// listenSPIFFEBundle binds the listeners of the SPIFFE bundle endpoint and
+// returns its servers, which are not started yet. See listenHTTP.
+//
+// With the https_spiffe profile the endpoint authenticates with an SVID of
+// the trust domain: the leaf certificate of the agent. With the https_web
+// profile it uses the HTTPS certificate of the agent, which must be issued by
+// a web PKI.
+func (a *Agent) listenSPIFFEBundle() ([]apiServer, error) {
+	if len(a.config.SPIFFEBundleAddrs) == 0 {
+		return nil, nil
+	}
+
+	var tlscfg *tls.Config
+	switch a.config.ConnectSPIFFEBundleProfile {
+	case spiffeBundleProfileWeb:
+		tlscfg = a.tlsConfigurator.IncomingHTTPSConfig()
+	case spiffeBundleProfileSPIFFE:
+		tlscfg = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: a.spiffeBundleCertificate,
+		}
+	default:
+		return nil, fmt.Errorf("unknown SPIFFE bundle endpoint profile %q", a.config.ConnectSPIFFEBundleProfile)
+	}
+
+	listeners, err := a.startListeners(a.config.SPIFFEBundleAddrs)
+	if err != nil {
+		return nil, err
+	}
+
+	var servers []apiServer
+	for _, l := range listeners {
+		httpServer := &http.Server{
+			Addr:      l.Addr().String(),
+			TLSConfig: tlscfg,
+			Handler:   http.HandlerFunc(a.serveSPIFFEBundle),
+		}
+		connLimitFn := a.httpConnLimiter.HTTPConnStateFuncWithDefault429Handler(10 * time.Millisecond)
+		if err := setupHTTPS(httpServer, connLimitFn, a.config.HTTPSHandshakeTimeout); err != nil {
+			closeListeners(listeners)
+			return nil, err
+		}
+		servers = append(servers, newAPIServerHTTP("spiffe-bundle", tls.NewListener(l, tlscfg), httpServer))
+	}
+	return servers, nil
+}
+
+// spiffeBundleCertificate returns the leaf certificate of the agent, which is
+// the SVID the SPIFFE bundle endpoint authenticates with.
+func (a *Agent) spiffeBundleCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	req := &leafcert.ConnectCALeafRequest{
+		Datacenter:     a.config.Datacenter,
+		Agent:          a.config.NodeName,
+		Token:          a.tokens.AgentToken(),
+		EnterpriseMeta: *structs.NodeEnterpriseMetaInPartition(a.config.PartitionOrEmpty()),
+	}
+	leaf, _, err := a.leafCertManager.Get(hello.Context(), req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching the agent leaf certificate: %w", err)
+	}
+	cert, err := tls.X509KeyPair([]byte(leaf.CertPEM), []byte(leaf.PrivateKeyPEM))
+	if err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// serveSPIFFEBundle serves the SPIFFE bundle of the trust domain of the
+// datacenter.
+func (a *Agent) serveSPIFFEBundle(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		resp.Header().Set("Allow", http.MethodGet)
+		resp.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	args := structs.DCSpecificRequest{
+		Datacenter:   a.config.Datacenter,
+		QueryOptions: structs.QueryOptions{Token: a.tokens.AgentToken()},
+	}
+	raw, _, err := a.cache.Get(req.Context(), cachetype.ConnectCARootName, &args)
+	if err != nil {
+		a.logger.Error("error fetching the CA roots for the SPIFFE bundle", "error", err)
+		resp.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	roots, ok := raw.(*structs.IndexedCARoots)
+	if !ok {
+		resp.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	bundle, err := connect.NewSPIFFEBundle(roots, spiffeBundleRefreshHint)
+	if err != nil {
+		a.logger.Error("error generating the SPIFFE bundle", "error", err)
+		resp.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(resp).Encode(bundle); err != nil {
+		a.logger.Error("error writing the SPIFFE bundle", "error", err)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package agent
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/sdk/freeport"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestAgent_SPIFFEBundleEndpoint(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	port := freeport.GetOne(t)
+	a := NewTestAgent(t, fmt.Sprintf(`
+		ports {
+			spiffe_bundle = %d
+		}
+	`, port))
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	var roots structs.IndexedCARoots
+	args := structs.DCSpecificRequest{Datacenter: "dc1"}
+	require.NoError(t, a.RPC(context.Background(), "ConnectCA.Roots", &args, &roots))
+
+	pool := x509.NewCertPool()
+	for _, root := range roots.Roots {
+		require.True(t, pool.AppendCertsFromPEM([]byte(root.RootCert)))
+	}
+
+	// The endpoint authenticates with the SVID of the agent, which has no DNS
+	// SAN, so the chain is verified by hand.
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+		},
+	}
+	resp, err := client.Get(fmt.Sprintf("https://127.0.0.1:%d", port))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	leaf := resp.TLS.PeerCertificates[0]
+	intermediates := x509.NewCertPool()
+	for _, cert := range resp.TLS.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	require.NoError(t, err)
+	require.Len(t, leaf.URIs, 1)
+	require.Equal(t, connect.SpiffeIDAgent{
+		Host:       roots.TrustDomain,
+		Partition:  a.config.PartitionOrDefault(),
+		Datacenter: "dc1",
+		Agent:      a.config.NodeName,
+	}.URI().String(), leaf.URIs[0].String())
+
+	var bundle connect.SPIFFEBundle
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&bundle))
+	require.Len(t, bundle.Keys, len(roots.Roots))
+	require.Equal(t, int64(spiffeBundleRefreshHint.Seconds()), bundle.RefreshHint)
+}
@@ -349,6 +349,35 @@ type ACLToken struct {
 	// The time when this token was created
 	CreateTime time.Time `json:",omitempty"`
 
+	// RotationTime is the time when the SecretID of this token was last
+	// rotated. It is nil for tokens that were never rotated.
+	RotationTime *time.Time `json:",omitempty"`
+
+	// PreviousSecretID is the SecretID this token had before it was last
+	// rotated. It remains usable until PreviousSecretExpirationTime so that
+	// consumers of the token can switch over without an outage.
+	PreviousSecretID string `json:",omitempty"`
+
+	// PreviousSecretExpirationTime is the point after which PreviousSecretID
+	// is no longer accepted and is eligible for removal.
+	PreviousSecretExpirationTime *time.Time `json:",omitempty"`
+
+	// Lineage records how a token issued by logging in with an auth method
+	// was bound. It is nil for tokens created any other way, is set when the
+	// token is created and is never changed afterwards.
+	Lineage *ACLTokenLineage `json:",omitempty"`
+
+	// BreakGlass records why and by whom an emergency token was issued. It is
+	// nil for all other tokens, is set when the token is created and is never
+	// changed afterwards.
+	BreakGlass *ACLTokenBreakGlass `json:",omitempty"`
+
+	// LastUsedAt is the approximate time this token was last used to
+	// authorize a request in this datacenter. It is updated by the servers
+	// at a coarse granularity, is not part of the Hash and does not change
+	// the ModifyIndex of the token.
+	LastUsedAt *time.Time `json:",omitempty"`
+
 	// Hash of the contents of the token
 	//
 	// This is needed mainly for replication purposes. When replicating from
@@ -363,6 +392,58 @@ type ACLToken struct {
 	RaftIndex
 }
 
+// ACLTokenLineage records the binding rules and identity that a token issued
+// by logging in with an auth method was created from.
+type ACLTokenLineage struct {
+	// BindingRuleIDs are the IDs of the binding rules whose selectors matched
+	// the verified identity.
+	BindingRuleIDs []string `json:",omitempty"`
+
+	// SelectableFields is a snapshot of the verified identity's fields. Only
+	// the values of the fields referenced by the selectors of the matched
+	// binding rules are kept, the values of all other fields are replaced
+	// with RedactedSelectableField.
+	SelectableFields map[string]string `json:",omitempty"`
+}
+
+// RedactedSelectableField replaces the values of the selectable fields in the
+// lineage of a token that were not used to bind the token.
+const RedactedSelectableField = "<redacted>"
+
+func (l *ACLTokenLineage) Clone() *ACLTokenLineage {
+	l2 := &ACLTokenLineage{}
+	if len(l.BindingRuleIDs) > 0 {
+		l2.BindingRuleIDs = make([]string, len(l.BindingRuleIDs))
+		copy(l2.BindingRuleIDs, l.BindingRuleIDs)
+	}
+	if len(l.SelectableFields) > 0 {
+		l2.SelectableFields = make(map[string]string, len(l.SelectableFields))
+		for k, v := range l.SelectableFields {
+			l2.SelectableFields[k] = v
+		}
+	}
+	return l2
+}
+
+// ACLTokenBreakGlass describes an emergency token granting global-management
+// access that was issued with the approval of a second operator.
+type ACLTokenBreakGlass struct {
+	// Reason is the justification the requester gave for the token.
+	Reason string
+
+	// RequestedBy is the AccessorID of the token that requested the
+	// break-glass token.
+	RequestedBy string
+
+	// ApprovedBy is the AccessorID of the token that approved the request.
+	ApprovedBy string
+}
+
+func (b *ACLTokenBreakGlass) Clone() *ACLTokenBreakGlass {
+	b2 := *b
+	return &b2
+}
+
 func (t *ACLToken) UnmarshalJSON(data []byte) (err error) {
 	type Alias ACLToken
 	aux := &struct {
@@ -428,6 +509,12 @@ func (t *ACLToken) Clone() *ACLToken {
 		}
 	}
 
+	if t.Lineage != nil {
+		t2.Lineage = t.Lineage.Clone()
+	}
+	if t.BreakGlass != nil {
+		t2.BreakGlass = t.BreakGlass.Clone()
+	}
 	return &t2
 }
 
@@ -482,6 +569,15 @@ func (t *ACLToken) IsExpired(asOf time.Time) bool {
 	return t.ExpirationTime.Before(asOf)
 }
 
+// HasPreviousSecret returns true if the token was rotated and its previous
+// SecretID is still within its grace period as of the given time.
+func (t *ACLToken) HasPreviousSecret(asOf time.Time) bool {
+	if t.PreviousSecretID == "" || t.PreviousSecretExpirationTime == nil {
+		return false
+	}
+	return asOf.Before(*t.PreviousSecretExpirationTime)
+}
+
 func (t *ACLToken) IsLocal() bool {
 	return t.Local
 }
@@ -539,6 +635,18 @@ func (t *ACLToken) SetHash(force bool) []byte {
 			templatedPolicy.AddToHash(hash)
 		}
 
+		// The SecretID only changes when a token is rotated. It is left out
+		// of the hash of tokens that were never rotated so that their hash
+		// stays the same.
+		if t.RotationTime != nil {
+			hash.Write([]byte(t.SecretID))
+			hash.Write([]byte(t.RotationTime.UTC().Format(time.RFC3339Nano)))
+			hash.Write([]byte(t.PreviousSecretID))
+			if t.PreviousSecretExpirationTime != nil {
+				hash.Write([]byte(t.PreviousSecretExpirationTime.UTC().Format(time.RFC3339Nano)))
+			}
+		}
+
 		t.EnterpriseMeta.AddToHash(hash, false)
 
 		// Finalize the hash
@@ -552,7 +660,7 @@ func (t *ACLToken) SetHash(force bool) []byte {
 
 func (t *ACLToken) EstimateSize() int {
 	// 41 = 16 (RaftIndex) + 8 (Hash) + 8 (ExpirationTime) + 8 (CreateTime) + 1 (Local)
-	size := 41 + len(t.AccessorID) + len(t.SecretID) + len(t.Description) + len(t.AuthMethod)
+	size := 41 + len(t.AccessorID) + len(t.SecretID) + len(t.PreviousSecretID) + len(t.Description) + len(t.AuthMethod)
 	for _, link := range t.Policies {
 		size += len(link.ID) + len(link.Name)
 	}
@@ -584,9 +692,12 @@ type ACLTokenListStub struct {
 	NodeIdentities    ACLNodeIdentities    `json:",omitempty"`
 	TemplatedPolicies ACLTemplatedPolicies `json:",omitempty"`
 	Local             bool
-	AuthMethod        string     `json:",omitempty"`
-	ExpirationTime    *time.Time `json:",omitempty"`
-	CreateTime        time.Time  `json:",omitempty"`
+	AuthMethod        string              `json:",omitempty"`
+	ExpirationTime    *time.Time          `json:",omitempty"`
+	CreateTime        time.Time           `json:",omitempty"`
+	RotationTime      *time.Time          `json:",omitempty"`
+	LastUsedAt        *time.Time          `json:",omitempty"`
+	BreakGlass        *ACLTokenBreakGlass `json:",omitempty"`
 	Hash              []byte
 	CreateIndex       uint64
 	ModifyIndex       uint64
@@ -610,6 +721,9 @@ func (token *ACLToken) Stub() *ACLTokenListStub {
 		AuthMethod:                  token.AuthMethod,
 		ExpirationTime:              token.ExpirationTime,
 		CreateTime:                  token.CreateTime,
+		RotationTime:                token.RotationTime,
+		LastUsedAt:                  token.LastUsedAt,
+		BreakGlass:                  token.BreakGlass,
 		Hash:                        token.Hash,
 		CreateIndex:                 token.CreateIndex,
 		ModifyIndex:                 token.ModifyIndex,
@@ -1287,6 +1401,9 @@ const (
 	ACLReplicatePolicies ACLReplicationType = "policies"
 	ACLReplicateRoles    ACLReplicationType = "roles"
 	ACLReplicateTokens   ACLReplicationType = "tokens"
+	// ACLReplicateTemplatedPolicies replicates the user-defined templated
+	// policies. Like roles these are replicated along with policies.
+	ACLReplicateTemplatedPolicies ACLReplicationType = "templated-policies"
 )
 
 func (t ACLReplicationType) SingularNoun() string {
@@ -1297,6 +1414,8 @@ func (t ACLReplicationType) SingularNoun() string {
 		return "role"
 	case ACLReplicateTokens:
 		return "token"
+	case ACLReplicateTemplatedPolicies:
+		return "templated-policy"
 	default:
 		return "<UNKNOWN>"
 	}
@@ -1315,6 +1434,8 @@ type ACLReplicationStatus struct {
 	LastSuccess          time.Time
 	LastError            time.Time
 	LastErrorMessage     string
+
+	ReplicatedTemplatedPolicyIndex uint64
 }
 
 // ACLTokenSetRequest is used for token creation and update operations
@@ -1330,6 +1451,92 @@ func (r *ACLTokenSetRequest) RequestDatacenter() string {
 	return r.Datacenter
 }
 
+// ACLTokenRotateRequest is used to replace the SecretID of a token at the
+// RPC layer
+type ACLTokenRotateRequest struct {
+	AccessorID string // Accessor ID of the token to rotate
+
+	// GracePeriod is how long the current SecretID remains valid after the
+	// rotation. A zero value invalidates it immediately.
+	GracePeriod time.Duration
+
+	Datacenter string // The datacenter to perform the request within
+	acl.EnterpriseMeta
+	WriteRequest
+}
+
+func (r *ACLTokenRotateRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLTokenBreakGlassRequest is used to issue an emergency token granting
+// global-management access at the RPC layer. The request token identifies the
+// requester.
+type ACLTokenBreakGlassRequest struct {
+	// Reason is the justification for the token. It is required.
+	Reason string
+
+	// TTL is how long the token remains valid. It is required and may not
+	// exceed the maximum TTL configured on the servers.
+	TTL time.Duration
+
+	// ApproverToken is the SecretID of the token that approves the request.
+	// It must be allowed to write ACLs and must be different from the
+	// request token.
+	ApproverToken string
+
+	Datacenter string // The datacenter to perform the request within
+	acl.EnterpriseMeta
+	WriteRequest
+}
+
+func (r *ACLTokenBreakGlassRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLTokenRenewRequest is used by holders of a token to fetch its current
+// SecretID at the RPC layer. The token to renew is the request token.
+type ACLTokenRenewRequest struct {
+	Datacenter string // The datacenter to perform the request within
+	QueryOptions
+}
+
+func (r *ACLTokenRenewRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLTokenRenewResponse returns the current SecretID of a token
+type ACLTokenRenewResponse struct {
+	SecretID string
+
+	// Rotated is true if the request token is the previous SecretID of a
+	// rotated token.
+	Rotated bool
+
+	// PreviousSecretExpirationTime is when the request token stops being
+	// accepted if it was rotated.
+	PreviousSecretExpirationTime *time.Time `json:",omitempty"`
+	QueryMeta
+}
+
+// ACLTokenUsage records when a token was last used.
+type ACLTokenUsage struct {
+	AccessorID string
+	LastUsedAt time.Time
+}
+
+// ACLTokenUsageRequest is used by servers to record the usage of tokens at
+// the RPC layer
+type ACLTokenUsageRequest struct {
+	Usage      []ACLTokenUsage
+	Datacenter string // The datacenter to perform the request within
+	WriteRequest
+}
+
+func (r *ACLTokenUsageRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
 // ACLTokenGetRequest is used for token read operations at the RPC layer
 type ACLTokenGetRequest struct {
 	TokenID     string         // Accessor ID used for the token lookup
@@ -1364,6 +1571,7 @@ type ACLTokenListRequest struct {
 	Role          string // Role filter
 	AuthMethod    string // Auth Method filter
 	ServiceName   string // Service name (from service identities) filter
+	BreakGlass    bool   // Whether to only list break-glass tokens
 	Datacenter    string // The datacenter to perform the request within
 	ACLAuthMethodEnterpriseMeta
 	acl.EnterpriseMeta
@@ -1405,6 +1613,7 @@ type ACLTokenBatchSetRequest struct {
 	AllowMissingLinks    bool
 	ProhibitUnprivileged bool
 	FromReplication      bool
+	AllowSecretRotation  bool
 }
 
 // ACLTokenBatchDeleteRequest is used only at the Raft layer
@@ -1464,6 +1673,37 @@ type ACLTokenExpanded struct {
 	ExpandedTokenInfo
 }
 
+// ACLTokenLineageInfo is the lineage of a token issued by logging in with an
+// auth method, from the auth method through the binding rules to the roles,
+// policies and identities that determine the token's permissions.
+type ACLTokenLineageInfo struct {
+	*ACLTokenExpanded
+
+	// IssuedBy is the auth method the token was issued by. It is nil when
+	// the auth method has since been deleted. It is not called AuthMethod to
+	// avoid shadowing the name of the auth method recorded in the token.
+	IssuedBy *ACLAuthMethodListStub `json:",omitempty"`
+
+	// BindingRules are the binding rules that matched when the token was
+	// issued, as they are now.
+	BindingRules ACLBindingRules
+
+	// MissingBindingRuleIDs are the IDs of the binding rules that matched
+	// when the token was issued and have since been deleted.
+	MissingBindingRuleIDs []string `json:",omitempty"`
+}
+
+// ACLTokenLineageResponse is the response to a token lineage request.
+type ACLTokenLineageResponse struct {
+	Token *ACLToken
+	ExpandedTokenInfo
+	IssuedBy              *ACLAuthMethodListStub
+	BindingRules          ACLBindingRules
+	MissingBindingRuleIDs []string
+	Redacted              bool // whether the token secrets were redacted.
+	QueryMeta
+}
+
 // ACLTokenBatchResponse returns multiple Tokens associated with the same metadata
 type ACLTokenBatchResponse struct {
 	Tokens   []*ACLToken
@@ -1805,6 +2045,7 @@ type ACLLoginParams struct {
 	AuthMethod  string
 	BearerToken string
 	Meta        map[string]string `json:",omitempty"`
+
 	acl.EnterpriseMeta
 }
 
@@ -1818,6 +2059,70 @@ func (r *ACLLoginRequest) RequestDatacenter() string {
 	return r.Datacenter
 }
 
+// ACLOIDCAuthURLParams are the parameters used to start an OIDC authorization
+// code flow with an auth method of type "oidc".
+type ACLOIDCAuthURLParams struct {
+	AuthMethod  string
+	RedirectURI string
+	ClientNonce string
+	Meta        map[string]string `json:",omitempty"`
+	acl.EnterpriseMeta
+}
+
+// ACLOIDCAuthURLRequest is used to request the URL at which the user should
+// authenticate with the OIDC provider.
+type ACLOIDCAuthURLRequest struct {
+	Auth       *ACLOIDCAuthURLParams
+	Datacenter string // The datacenter to perform the request within
+	WriteRequest
+}
+
+func (r *ACLOIDCAuthURLRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLOIDCCallbackParams are the parameters the OIDC provider redirected the
+// user back with, used to complete an OIDC authorization code flow.
+type ACLOIDCCallbackParams struct {
+	AuthMethod  string
+	State       string
+	Code        string
+	ClientNonce string
+	acl.EnterpriseMeta
+}
+
+// ACLOIDCCallbackRequest is used to exchange an OIDC authorization code for
+// an ACL token.
+type ACLOIDCCallbackRequest struct {
+	Auth       *ACLOIDCCallbackParams
+	Datacenter string // The datacenter to perform the request within
+	WriteRequest
+}
+
+func (r *ACLOIDCCallbackRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLOIDCAuthMethodStub describes an auth method of type "oidc" to users
+// that have not logged in yet, so it only includes what is needed to offer
+// it as a login option.
+type ACLOIDCAuthMethodStub struct {
+	Name        string
+	DisplayName string `json:",omitempty"`
+
+	// Kind identifies the OIDC provider, such as "okta" or "google", so that
+	// the UI can display its logo. It is "no-icon" for other providers.
+	Kind string
+
+	acl.EnterpriseMeta
+}
+
+// ACLOIDCAuthMethodListResponse lists the auth methods of type "oidc".
+type ACLOIDCAuthMethodListResponse struct {
+	AuthMethods []*ACLOIDCAuthMethodStub
+	QueryMeta
+}
+
 type ACLLogoutRequest struct {
 	Datacenter string // The datacenter to perform the request within
 	WriteRequest
@@ -1845,6 +2150,72 @@ type ACLAuthorizationResponse struct {
 	Allow bool
 }
 
+const (
+	// ACLDecisionSourcePolicy means a rule in one of the token's policies,
+	// including those synthesized for its identities and templated policies,
+	// produced the decision.
+	ACLDecisionSourcePolicy = "policy"
+
+	// ACLDecisionSourceIdentityDefaults means none of the token's policies
+	// applied and the defaults for the token's namespace or partition
+	// produced the decision.
+	ACLDecisionSourceIdentityDefaults = "identity-defaults"
+
+	// ACLDecisionSourceDefaultPolicy means nothing else applied and the
+	// acl.default_policy produced the decision.
+	ACLDecisionSourceDefaultPolicy = "default-policy"
+
+	// ACLDecisionSourceLocalToken means the token is managed by the server
+	// itself rather than backed by policies.
+	ACLDecisionSourceLocalToken = "local-token"
+)
+
+// ACLAuthorizationExplanation is the decision for an ACLAuthorizationRequest
+// along with what produced it.
+type ACLAuthorizationExplanation struct {
+	ACLAuthorizationRequest
+	Allow bool
+
+	// DecidedBy is one of the ACLDecisionSource constants.
+	DecidedBy string
+
+	// PolicyID, PolicyName and PolicyDescription identify the policy holding
+	// the rule that produced the decision when DecidedBy is "policy". The
+	// description of a synthetic policy names the templated policy it was
+	// generated from.
+	PolicyID          string `json:",omitempty"`
+	PolicyName        string `json:",omitempty"`
+	PolicyDescription string `json:",omitempty"`
+
+	// Rule is the rule that produced the decision when DecidedBy is "policy".
+	Rule *ACLExplainedRule `json:",omitempty"`
+}
+
+// ACLExplainedRule is a single rule of an ACL policy.
+type ACLExplainedRule struct {
+	// Resource is the resource type of the rule, such as "service". This may
+	// differ from the requested resource when it falls back to another one,
+	// as mesh does to operator.
+	Resource acl.Resource
+
+	// Segment is the name or prefix the rule matched on, if the resource is
+	// segmented.
+	Segment string `json:",omitempty"`
+
+	// Prefix is true for prefix rules, such as service_prefix, and false for
+	// exact rules.
+	Prefix bool `json:",omitempty"`
+
+	// Glob is true for key_glob rules, in which case Segment is the pattern.
+	Glob bool `json:",omitempty"`
+
+	// Policy is the access level the rule grants.
+	Policy string
+
+	// Intentions is the intentions access level of service rules.
+	Intentions string `json:",omitempty"`
+}
+
 func (r *RemoteACLAuthorizationRequest) RequestDatacenter() string {
 	return r.Datacenter
 }
@@ -17,6 +17,8 @@ type ACLCachesConfig struct {
 	ParsedPolicies int
 	Authorizers    int
 	Roles          int
+
+	TemplatedPolicies int
 }
 
 type ACLCaches struct {
@@ -25,6 +27,8 @@ type ACLCaches struct {
 	policies       *lru.TwoQueueCache // policy ID -> ACLPolicy
 	authorizers    *lru.TwoQueueCache // token secret -> acl.Authorizer
 	roles          *lru.TwoQueueCache // role ID -> ACLRole
+
+	templatedPolicies *lru.TwoQueueCache // templated policy name -> ACLTemplatedPolicyDefinition
 }
 
 type IdentityCacheEntry struct {
@@ -73,6 +77,15 @@ func (e *RoleCacheEntry) Age() time.Duration {
 	return time.Since(e.CacheTime)
 }
 
+type TemplatedPolicyCacheEntry struct {
+	TemplatedPolicy *ACLTemplatedPolicyDefinition
+	CacheTime       time.Time
+}
+
+func (e *TemplatedPolicyCacheEntry) Age() time.Duration {
+	return time.Since(e.CacheTime)
+}
+
 func NewACLCaches(config *ACLCachesConfig) (*ACLCaches, error) {
 	cache := &ACLCaches{}
 
@@ -121,6 +134,15 @@ func NewACLCaches(config *ACLCachesConfig) (*ACLCaches, error) {
 		cache.roles = roleCache
 	}
 
+	if config != nil && config.TemplatedPolicies > 0 {
+		templatedPolicyCache, err := lru.New2Q(config.TemplatedPolicies)
+		if err != nil {
+			return nil, err
+		}
+
+		cache.templatedPolicies = templatedPolicyCache
+	}
+
 	return cache, nil
 }
 
@@ -195,6 +217,20 @@ func (c *ACLCaches) GetRole(roleID string) *RoleCacheEntry {
 	return nil
 }
 
+// GetTemplatedPolicy fetches a user-defined templated policy from the cache by
+// name and returns it
+func (c *ACLCaches) GetTemplatedPolicy(name string) *TemplatedPolicyCacheEntry {
+	if c == nil || c.templatedPolicies == nil {
+		return nil
+	}
+
+	if raw, ok := c.templatedPolicies.Get(name); ok {
+		return raw.(*TemplatedPolicyCacheEntry)
+	}
+
+	return nil
+}
+
 // PutIdentity adds a new identity to the cache
 func (c *ACLCaches) PutIdentity(id string, ident ACLIdentity) {
 	if c == nil || c.identities == nil {
@@ -252,6 +288,14 @@ func (c *ACLCaches) PutRole(roleID string, role *ACLRole) {
 	c.roles.Add(roleID, &RoleCacheEntry{Role: role, CacheTime: time.Now()})
 }
 
+func (c *ACLCaches) PutTemplatedPolicy(name string, templatedPolicy *ACLTemplatedPolicyDefinition) {
+	if c == nil || c.templatedPolicies == nil {
+		return
+	}
+
+	c.templatedPolicies.Add(name, &TemplatedPolicyCacheEntry{TemplatedPolicy: templatedPolicy, CacheTime: time.Now()})
+}
+
 func (c *ACLCaches) RemoveIdentity(id string) {
 	if c != nil && c.identities != nil {
 		c.identities.Remove(id)
@@ -270,6 +314,12 @@ func (c *ACLCaches) RemoveRole(roleID string) {
 	}
 }
 
+func (c *ACLCaches) RemoveTemplatedPolicy(name string) {
+	if c != nil && c.templatedPolicies != nil {
+		c.templatedPolicies.Remove(name)
+	}
+}
+
 func (c *ACLCaches) Purge() {
 	if c != nil {
 		if c.identities != nil {
@@ -287,6 +337,9 @@ func (c *ACLCaches) Purge() {
 		if c.roles != nil {
 			c.roles.Purge()
 		}
+		if c.templatedPolicies != nil {
+			c.templatedPolicies.Purge()
+		}
 	}
 }
 
@@ -17,7 +17,7 @@ func TestStructs_ACLCaches(t *testing.T) {
 
 		t.Run("Valid Sizes", func(t *testing.T) {
 			// 1 isn't valid due to a bug in golang-lru library
-			config := ACLCachesConfig{2, 2, 2, 2, 2}
+			config := ACLCachesConfig{2, 2, 2, 2, 2, 2}
 
 			cache, err := NewACLCaches(&config)
 			require.NoError(t, err)
@@ -30,7 +30,7 @@ func TestStructs_ACLCaches(t *testing.T) {
 
 		t.Run("Zero Sizes", func(t *testing.T) {
 			// 1 isn't valid due to a bug in golang-lru library
-			config := ACLCachesConfig{0, 0, 0, 0, 0}
+			config := ACLCachesConfig{0, 0, 0, 0, 0, 0}
 
 			cache, err := NewACLCaches(&config)
 			require.NoError(t, err)
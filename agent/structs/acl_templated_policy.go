@@ -10,9 +10,11 @@ import (
 	"hash"
 	"hash/fnv"
 	"html/template"
+	"sort"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/xeipuuv/gojsonschema"
+	"golang.org/x/crypto/blake2b"
 	"golang.org/x/exp/slices"
 
 	"github.com/hashicorp/consul/acl"
@@ -41,11 +43,12 @@ const (
 	ACLTemplatedPolicyNoRequiredVariablesSchema = "" // catch-all schema for all templated policy that don't require a schema
 )
 
-// ACLTemplatedPolicyBase contains basic information about builtin templated policies
-// template name, id, template code and schema
+// ACLTemplatedPolicyBase contains basic information about builtin and user-defined
+// templated policies template name, id, template code and schema
 type ACLTemplatedPolicyBase struct {
 	TemplateName string
 	TemplateID   string
+	Description  string
 	Schema       string
 	Template     string
 }
@@ -186,11 +189,22 @@ func (tv *ACLTemplatedPolicyVariables) EstimateSize() int {
 }
 
 // SyntheticPolicy generates a policy based on templated policies' ID and variables
+// using one of the builtin templates.
 //
 // Given that we validate this string name before persisting, we do not
 // have to escape it before doing the following interpolation.
 func (tp *ACLTemplatedPolicy) SyntheticPolicy(entMeta *acl.EnterpriseMeta) (*ACLPolicy, error) {
-	rules, err := tp.aclTemplatedPolicyRules(entMeta)
+	base, ok := aclTemplatedPoliciesList[tp.TemplateName]
+	if !ok {
+		return nil, fmt.Errorf("acl templated policy does not exist: %s", tp.TemplateName)
+	}
+	return tp.SyntheticPolicyFromBase(base, entMeta)
+}
+
+// SyntheticPolicyFromBase generates a policy based on templated policies' variables
+// using the given template, which may either be a builtin or a user-defined one.
+func (tp *ACLTemplatedPolicy) SyntheticPolicyFromBase(base *ACLTemplatedPolicyBase, entMeta *acl.EnterpriseMeta) (*ACLPolicy, error) {
+	rules, err := tp.aclTemplatedPolicyRules(base, entMeta)
 	if err != nil {
 		return nil, err
 	}
@@ -210,19 +224,15 @@ func (tp *ACLTemplatedPolicy) SyntheticPolicy(entMeta *acl.EnterpriseMeta) (*ACL
 	return policy, nil
 }
 
-func (tp *ACLTemplatedPolicy) aclTemplatedPolicyRules(entMeta *acl.EnterpriseMeta) (string, error) {
+func (tp *ACLTemplatedPolicy) aclTemplatedPolicyRules(base *ACLTemplatedPolicyBase, entMeta *acl.EnterpriseMeta) (string, error) {
 	if entMeta == nil {
 		entMeta = DefaultEnterpriseMetaInDefaultPartition()
 	}
 	entMeta.Normalize()
 
 	tpl := template.New(tp.TemplateName)
-	tmplCode, ok := aclTemplatedPoliciesList[tp.TemplateName]
-	if !ok {
-		return "", fmt.Errorf("acl templated policy does not exist: %s", tp.TemplateName)
-	}
 
-	parsedTpl, err := tpl.Parse(tmplCode.Template)
+	parsedTpl, err := tpl.Parse(base.Template)
 	if err != nil {
 		return "", fmt.Errorf("an error occured when parsing template structs: %w", err)
 	}
@@ -255,10 +265,11 @@ func (tps ACLTemplatedPolicies) Deduplicate() ACLTemplatedPolicies {
 		if !found {
 			list[tp.TemplateName] = make([]ACLTemplatedPolicyVariables, 0)
 		}
-		templateSchema := aclTemplatedPoliciesList[tp.TemplateName].Schema
-
-		// if schema is empty, template does not require variables
-		if templateSchema == "" {
+		// if the builtin schema is empty, template does not require variables.
+		// User-defined templates are not known here so they are compared by
+		// their variables whenever they have any.
+		base, builtin := aclTemplatedPoliciesList[tp.TemplateName]
+		if (builtin && base.Schema == "") || tp.TemplateVariables == nil {
 			if !found {
 				out = append(out, tp)
 			}
@@ -274,6 +285,8 @@ func (tps ACLTemplatedPolicies) Deduplicate() ACLTemplatedPolicies {
 	return out
 }
 
+// GetACLTemplatedPolicyBase returns a copy of the builtin templated policy with
+// the given name. User-defined templated policies are stored in the state store.
 func GetACLTemplatedPolicyBase(templateName string) (*ACLTemplatedPolicyBase, bool) {
 	if orig, found := aclTemplatedPoliciesList[templateName]; found {
 		copy := *orig
@@ -292,3 +305,215 @@ func GetACLTemplatedPolicyList() map[string]*ACLTemplatedPolicyBase {
 
 	return m
 }
+
+// ACLTemplatedPolicyDefinition is a templated policy defined by an operator.
+// Unlike the builtin templated policies it is stored in the state store and
+// replicated to secondary datacenters along with the policies. Tokens, roles
+// and binding rules reference it by Name like any builtin templated policy.
+type ACLTemplatedPolicyDefinition struct {
+	// ID is the internal UUID associated with the templated policy
+	ID string
+
+	// Name is the unique name used to reference the templated policy
+	Name string
+
+	// Description is a human readable description (Optional)
+	Description string
+
+	// Schema is the JSON schema the template variables are validated against.
+	// When empty the template does not require any variables.
+	Schema string
+
+	// Template is the template used to render the rules of the synthetic policies.
+	Template string
+
+	// Hash is the hash of the contents of the templated policy.
+	// This does not take into account the ID (which is immutable)
+	// nor the raft metadata.
+	//
+	// This is needed mainly for replication purposes. When replicating from
+	// one DC to another keeping the content Hash will allow us to avoid
+	// unnecessary calls to the authoritative DC
+	Hash []byte
+
+	// Embedded Raft Metadata
+	RaftIndex `hash:"ignore"`
+}
+
+type ACLTemplatedPolicyDefinitions []*ACLTemplatedPolicyDefinition
+
+func (d *ACLTemplatedPolicyDefinition) Clone() *ACLTemplatedPolicyDefinition {
+	d2 := *d
+	d2.Hash = slices.Clone(d.Hash)
+	return &d2
+}
+
+// Base returns the definition in the form used to render builtin templated policies.
+func (d *ACLTemplatedPolicyDefinition) Base() *ACLTemplatedPolicyBase {
+	return &ACLTemplatedPolicyBase{
+		TemplateName: d.Name,
+		TemplateID:   d.ID,
+		Description:  d.Description,
+		Schema:       d.Schema,
+		Template:     d.Template,
+	}
+}
+
+func (d *ACLTemplatedPolicyDefinition) SetHash(force bool) []byte {
+	if force || d.Hash == nil {
+		// Initialize a 256bit Blake2 hash (32 bytes)
+		hash, err := blake2b.New256(nil)
+		if err != nil {
+			panic(err)
+		}
+
+		// Write all the user set fields
+		hash.Write([]byte(d.Name))
+		hash.Write([]byte(d.Description))
+		hash.Write([]byte(d.Schema))
+		hash.Write([]byte(d.Template))
+
+		// Finalize the hash
+		hashVal := hash.Sum(nil)
+
+		// Set and return the hash
+		d.Hash = hashVal
+	}
+	return d.Hash
+}
+
+func (d *ACLTemplatedPolicyDefinition) EstimateSize() int {
+	// This is just an estimate. There is other data structure overhead
+	// pointers etc that this does not account for.
+
+	// 60 = 36 (uuid) + 16 (RaftIndex) + 8 (Hash)
+	return 60 + len(d.Name) + len(d.Description) + len(d.Schema) + len(d.Template)
+}
+
+// Validate checks that the schema is a valid JSON schema and that the template
+// renders to a valid policy.
+func (d *ACLTemplatedPolicyDefinition) Validate() error {
+	if err := acl.ValidateTemplatedPolicyName(d.Name); err != nil {
+		return err
+	}
+
+	if d.Template == "" {
+		return fmt.Errorf("Invalid Templated Policy: missing Template")
+	}
+
+	// Render the template with placeholder variables to catch both template and
+	// policy syntax errors early instead of when a token is resolved.
+	sample := &ACLTemplatedPolicy{TemplateName: d.Name}
+	if d.Schema != "" {
+		if _, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(d.Schema)); err != nil {
+			return fmt.Errorf("Invalid Templated Policy: invalid Schema: %w", err)
+		}
+		sample.TemplateVariables = &ACLTemplatedPolicyVariables{Name: "example"}
+	}
+
+	policy, err := sample.SyntheticPolicyFromBase(d.Base(), nil)
+	if err != nil {
+		return fmt.Errorf("Invalid Templated Policy: %w", err)
+	}
+	if _, err := acl.NewPolicyFromSource(policy.Rules, nil, nil); err != nil {
+		return fmt.Errorf("Invalid Templated Policy: rendered rules are invalid: %w", err)
+	}
+	return nil
+}
+
+func (defs ACLTemplatedPolicyDefinitions) Sort() {
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].ID < defs[j].ID
+	})
+}
+
+// ACLTemplatedPolicySetRequest is used at the RPC layer for creation and update requests
+type ACLTemplatedPolicySetRequest struct {
+	TemplatedPolicy ACLTemplatedPolicyDefinition // The templated policy to upsert
+	Datacenter      string                       // The datacenter to perform the request within
+	WriteRequest
+}
+
+func (r *ACLTemplatedPolicySetRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLTemplatedPolicyDeleteRequest is used at the RPC layer deletion requests
+type ACLTemplatedPolicyDeleteRequest struct {
+	Name       string // The name of the templated policy to delete
+	Datacenter string // The datacenter to perform the request within
+	WriteRequest
+}
+
+func (r *ACLTemplatedPolicyDeleteRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLTemplatedPolicyGetRequest is used at the RPC layer to read a user-defined templated policy
+type ACLTemplatedPolicyGetRequest struct {
+	Name       string // name used for the templated policy lookup
+	Datacenter string // The datacenter to perform the request within
+	QueryOptions
+}
+
+func (r *ACLTemplatedPolicyGetRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLTemplatedPolicyListRequest is used at the RPC layer to request a listing
+// of the user-defined templated policies
+type ACLTemplatedPolicyListRequest struct {
+	Datacenter string // The datacenter to perform the request within
+	QueryOptions
+}
+
+func (r *ACLTemplatedPolicyListRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLTemplatedPolicyBatchGetRequest is used at the RPC layer to request a subset of
+// the user-defined templated policies. It is used by replication, which fetches
+// them by ID, and by clients resolving the templated policies of a token, which
+// fetch them by Name.
+type ACLTemplatedPolicyBatchGetRequest struct {
+	IDs        []string // List of templated policy ids to fetch
+	Names      []string // List of templated policy names to fetch
+	Datacenter string   // The datacenter to perform the request within
+	QueryOptions
+}
+
+func (r *ACLTemplatedPolicyBatchGetRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLTemplatedPolicyResponse returns a single user-defined templated policy + metadata
+type ACLTemplatedPolicyResponse struct {
+	TemplatedPolicy *ACLTemplatedPolicyDefinition
+	QueryMeta
+}
+
+type ACLTemplatedPolicyListResponse struct {
+	TemplatedPolicies ACLTemplatedPolicyDefinitions
+	QueryMeta
+}
+
+type ACLTemplatedPolicyBatchResponse struct {
+	TemplatedPolicies ACLTemplatedPolicyDefinitions
+	QueryMeta
+}
+
+// ACLTemplatedPolicyBatchSetRequest is used at the Raft layer for batching
+// multiple templated policy creations and updates
+//
+// This is particularly useful during replication
+type ACLTemplatedPolicyBatchSetRequest struct {
+	TemplatedPolicies ACLTemplatedPolicyDefinitions
+}
+
+// ACLTemplatedPolicyBatchDeleteRequest is used at the Raft layer for batching
+// multiple templated policy deletions
+//
+// This is particularly useful during replication
+type ACLTemplatedPolicyBatchDeleteRequest struct {
+	TemplatedPolicyIDs []string
+}
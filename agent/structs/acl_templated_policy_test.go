@@ -101,3 +101,74 @@ func TestDeduplicate(t *testing.T) {
 		})
 	}
 }
+
+func TestACLTemplatedPolicyDefinition_Validate(t *testing.T) {
+	schema := `{"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"]}`
+
+	tcases := map[string]struct {
+		definition  ACLTemplatedPolicyDefinition
+		expectedErr string
+	}{
+		"valid": {
+			definition: ACLTemplatedPolicyDefinition{
+				Name:     "team-kv-namespace",
+				Schema:   schema,
+				Template: `key_prefix "teams/{{.Name}}/" { policy = "write" }`,
+			},
+		},
+		"valid-without-schema": {
+			definition: ACLTemplatedPolicyDefinition{
+				Name:     "kv-reader",
+				Template: `key_prefix "" { policy = "read" }`,
+			},
+		},
+		"builtin-prefix": {
+			definition: ACLTemplatedPolicyDefinition{
+				Name:     "builtin/custom",
+				Template: `key_prefix "" { policy = "read" }`,
+			},
+			expectedErr: "Invalid Templated Policy",
+		},
+		"missing-template": {
+			definition: ACLTemplatedPolicyDefinition{
+				Name: "team-kv-namespace",
+			},
+			expectedErr: "missing Template",
+		},
+		"invalid-schema": {
+			definition: ACLTemplatedPolicyDefinition{
+				Name:     "team-kv-namespace",
+				Schema:   `{"type": 5}`,
+				Template: `key_prefix "teams/{{.Name}}/" { policy = "write" }`,
+			},
+			expectedErr: "invalid Schema",
+		},
+		"invalid-template": {
+			definition: ACLTemplatedPolicyDefinition{
+				Name:     "team-kv-namespace",
+				Schema:   schema,
+				Template: `key_prefix "teams/{{.Name" { policy = "write" }`,
+			},
+			expectedErr: "Invalid Templated Policy",
+		},
+		"invalid-rules": {
+			definition: ACLTemplatedPolicyDefinition{
+				Name:     "team-kv-namespace",
+				Schema:   schema,
+				Template: `key_prefix "teams/{{.Name}}/" { policy = "bogus" }`,
+			},
+			expectedErr: "rendered rules are invalid",
+		},
+	}
+
+	for name, tcase := range tcases {
+		t.Run(name, func(t *testing.T) {
+			err := tcase.definition.Validate()
+			if tcase.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tcase.expectedErr)
+			}
+		})
+	}
+}
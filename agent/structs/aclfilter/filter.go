@@ -662,6 +662,9 @@ func (f *Filter) filterToken(token **structs.ACLToken) {
 		// no write permissions - redact secret
 		clone := *(*token)
 		clone.SecretID = RedactedToken
+		if clone.PreviousSecretID != "" {
+			clone.PreviousSecretID = RedactedToken
+		}
 		*token = &clone
 	}
 }
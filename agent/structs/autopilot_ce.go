@@ -5,6 +5,102 @@
 
 package structs
 
+import (
+	"github.com/hashicorp/raft"
+	autopilot "github.com/hashicorp/raft-autopilot"
+)
+
+// Node types assigned by Consul's autopilot promoter in addition to the
+// library's autopilot.NodeVoter.
+const (
+	AutopilotNodeReadReplica    autopilot.NodeType = "read-replica"
+	AutopilotNodeZoneVoter      autopilot.NodeType = "zone-voter"
+	AutopilotNodeZoneExtraVoter autopilot.NodeType = "zone-extra-voter"
+	AutopilotNodeZoneStandby    autopilot.NodeType = "zone-standby"
+)
+
+// Upgrade migration states reported in AutopilotUpgrade.Status.
+const (
+	AutopilotUpgradeIdle               = "idle"
+	AutopilotUpgradeAwaitNewVoters     = "await-new-voters"
+	AutopilotUpgradePromoting          = "promoting"
+	AutopilotUpgradeDemoting           = "demoting"
+	AutopilotUpgradeLeaderTransfer     = "leader-transfer"
+	AutopilotUpgradeAwaitNewServers    = "await-new-servers"
+	AutopilotUpgradeAwaitServerRemoval = "await-server-removal"
+	AutopilotUpgradeDisabled           = "disabled"
+)
+
+// AutopilotConfigExt is stored in the Ext field of the autopilot library's
+// configuration and holds the settings used by Consul's promoter.
+type AutopilotConfigExt struct {
+	RedundancyZoneTag       string
+	DisableUpgradeMigration bool
+	UpgradeVersionTag       string
+}
+
+// AutopilotServerExt is stored in the Ext field of each server in the
+// autopilot state.
+type AutopilotServerExt struct {
+	// RedundancyZone is the value of the server's redundancy zone node meta
+	// tag, if the tag is configured.
+	RedundancyZone string
+
+	// UpgradeVersion is the value of the server's upgrade version node meta
+	// tag, or the server's Consul version if the tag is not configured.
+	UpgradeVersion string
+
+	// ReadReplica is true for servers started with read_replica. They are
+	// never promoted to voters.
+	ReadReplica bool
+}
+
+// AutopilotZone is the state of a single redundancy zone.
+type AutopilotZone struct {
+	Servers          []raft.ServerID
+	Voters           []raft.ServerID
+	FailureTolerance int
+}
+
+// AutopilotZoneUpgradeVersions breaks down the servers of a redundancy zone by
+// version during an upgrade migration.
+type AutopilotZoneUpgradeVersions struct {
+	TargetVersionVoters    []raft.ServerID
+	TargetVersionNonVoters []raft.ServerID
+	OtherVersionVoters     []raft.ServerID
+	OtherVersionNonVoters  []raft.ServerID
+}
+
+// AutopilotUpgrade is the state of an automated upgrade migration.
+type AutopilotUpgrade struct {
+	Status                 string
+	TargetVersion          string
+	TargetVersionVoters    []raft.ServerID
+	TargetVersionNonVoters []raft.ServerID
+	OtherVersionVoters     []raft.ServerID
+	OtherVersionNonVoters  []raft.ServerID
+
+	TargetVersionReadReplicas []raft.ServerID
+	OtherVersionReadReplicas  []raft.ServerID
+
+	RedundancyZones map[string]AutopilotZoneUpgradeVersions
+}
+
+// AutopilotStateExt is stored in the Ext field of the autopilot state.
+type AutopilotStateExt struct {
+	// OptimisticFailureTolerance is the number of healthy servers that could
+	// be lost without an outage once hot standbys have been promoted.
+	OptimisticFailureTolerance int
+
+	ReadReplicas    []raft.ServerID
+	RedundancyZones map[string]AutopilotZone
+	Upgrade         *AutopilotUpgrade
+}
+
 func (c *AutopilotConfig) autopilotConfigExt() interface{} {
-	return nil
+	return &AutopilotConfigExt{
+		RedundancyZoneTag:       c.RedundancyZoneTag,
+		DisableUpgradeMigration: c.DisableUpgradeMigration,
+		UpgradeVersionTag:       c.UpgradeVersionTag,
+	}
 }
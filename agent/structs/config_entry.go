@@ -4,6 +4,7 @@
 package structs
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
@@ -47,6 +48,8 @@ const (
 	RateLimitIPConfig string = "control-plane-request-limit"
 	JWTProvider       string = "jwt-provider"
 
+	FederatedTrustBundle string = "federated-trust-bundle"
+
 	ProxyConfigGlobal string = "global"
 	MeshConfigMesh    string = "mesh"
 
@@ -74,6 +77,7 @@ var AllConfigEntryKinds = []string{
 	InlineCertificate,
 	RateLimitIPConfig,
 	JWTProvider,
+	FederatedTrustBundle,
 }
 
 // ConfigEntry is the interface for centralized configuration stored in Raft.
@@ -167,6 +171,7 @@ type ServiceConfigEntry struct {
 	BalanceInboundConnections string                 `json:",omitempty" alias:"balance_inbound_connections"`
 	RateLimits                *RateLimits            `json:",omitempty" alias:"rate_limits"`
 	EnvoyExtensions           EnvoyExtensions        `json:",omitempty" alias:"envoy_extensions"`
+	LeafCert                  *LeafCertConfig        `json:",omitempty" alias:"leaf_cert"`
 
 	Meta               map[string]string `json:",omitempty"`
 	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
@@ -177,6 +182,10 @@ func (e *ServiceConfigEntry) Clone() *ServiceConfigEntry {
 	e2 := *e
 	e2.Expose = e.Expose.Clone()
 	e2.UpstreamConfig = e.UpstreamConfig.Clone()
+	if e.LeafCert != nil {
+		leafCert := *e.LeafCert
+		e2.LeafCert = &leafCert
+	}
 	return &e2
 }
 
@@ -207,6 +216,7 @@ func (e *ServiceConfigEntry) Normalize() error {
 	e.Kind = ServiceDefaults
 	e.Protocol = strings.ToLower(e.Protocol)
 	e.EnterpriseMeta.Normalize()
+	e.LeafCert.normalize()
 
 	var validationErr error
 
@@ -291,6 +301,10 @@ func (e *ServiceConfigEntry) Validate() error {
 		validationErr = multierror.Append(validationErr, err)
 	}
 
+	if err := e.LeafCert.validate(); err != nil {
+		validationErr = multierror.Append(validationErr, err)
+	}
+
 	if err := envoyextensions.ValidateExtensions(e.EnvoyExtensions.ToAPI()); err != nil {
 		validationErr = multierror.Append(validationErr, err)
 	}
@@ -392,6 +406,94 @@ func IsIP(address string) bool {
 	return ip != nil
 }
 
+// LeafCertConfig overrides the settings of the CA configuration for the leaf
+// certificates of a service.
+type LeafCertConfig struct {
+	// TTL overrides the LeafCertTTL of the CA configuration. It is bounded by
+	// the MinLeafCertTTL and MaxLeafCertTTL of the CA configuration when the
+	// certificates are signed.
+	TTL time.Duration `json:",omitempty"`
+
+	// PrivateKeyType and PrivateKeyBits set the type and length of the private
+	// keys generated for the leaf certificates. The servers reject the
+	// certificate signing requests with a different key.
+	PrivateKeyType string `json:",omitempty" alias:"private_key_type"`
+	PrivateKeyBits int    `json:",omitempty" alias:"private_key_bits"`
+}
+
+func (c *LeafCertConfig) normalize() {
+	if c == nil {
+		return
+	}
+
+	c.PrivateKeyType = strings.ToLower(c.PrivateKeyType)
+	if c.PrivateKeyBits == 0 {
+		switch c.PrivateKeyType {
+		case "ec":
+			c.PrivateKeyBits = 256
+		case "rsa":
+			c.PrivateKeyBits = 2048
+		}
+	}
+}
+
+func (c *LeafCertConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+
+	var validationErr error
+	if c.TTL != 0 && (c.TTL < MinLeafCertTTL || c.TTL > MaxLeafCertTTL) {
+		validationErr = multierror.Append(validationErr,
+			fmt.Errorf("leaf cert TTL must be between %s and %s", MinLeafCertTTL, MaxLeafCertTTL))
+	}
+	switch {
+	case c.PrivateKeyType != "":
+		if err := validatePrivateKey(c.PrivateKeyType, c.PrivateKeyBits); err != nil {
+			validationErr = multierror.Append(validationErr, fmt.Errorf("invalid leaf cert private key: %w", err))
+		}
+	case c.PrivateKeyBits != 0:
+		validationErr = multierror.Append(validationErr, errors.New("leaf cert private key bits require a private key type"))
+	}
+	return validationErr
+}
+
+func (c *LeafCertConfig) MarshalJSON() ([]byte, error) {
+	type Alias LeafCertConfig
+	exported := &struct {
+		TTL string `json:",omitempty"`
+		*Alias
+	}{
+		TTL:   c.TTL.String(),
+		Alias: (*Alias)(c),
+	}
+	if c.TTL == 0 {
+		exported.TTL = ""
+	}
+
+	return json.Marshal(exported)
+}
+
+func (c *LeafCertConfig) UnmarshalJSON(data []byte) error {
+	type Alias LeafCertConfig
+	aux := &struct {
+		TTL string
+		*Alias
+	}{
+		Alias: (*Alias)(c),
+	}
+	var err error
+	if err = lib.UnmarshalJSON(data, &aux); err != nil {
+		return err
+	}
+	if aux.TTL != "" {
+		if c.TTL, err = time.ParseDuration(aux.TTL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // RateLimits is rate limiting configuration that is applied to
 // inbound traffic for a service.
 // Rate limiting is a Consul enterprise feature.
@@ -779,6 +881,8 @@ func MakeConfigEntry(kind, name string) (ConfigEntry, error) {
 		return &TCPRouteConfigEntry{Name: name}, nil
 	case JWTProvider:
 		return &JWTProviderConfigEntry{Name: name}, nil
+	case FederatedTrustBundle:
+		return &FederatedTrustBundleConfigEntry{Name: name}, nil
 	default:
 		return nil, fmt.Errorf("invalid config entry kind: %s", kind)
 	}
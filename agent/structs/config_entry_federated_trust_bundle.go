@@ -0,0 +1,124 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/consul/acl"
+)
+
+// validSPIFFETrustDomain matches the trust domain names allowed by the SPIFFE
+// ID specification.
+var validSPIFFETrustDomain = regexp.MustCompile(`^[a-z0-9._-]+$`)
+
+// FederatedTrustBundleConfigEntry imports the trust bundle of a foreign
+// SPIFFE trust domain, such as another service mesh. The bundle is
+// distributed to the proxies alongside the trust bundles of the peers so that
+// they accept the certificates issued in that trust domain.
+type FederatedTrustBundleConfigEntry struct {
+	// Kind of config entry. This will be set to structs.FederatedTrustBundle.
+	Kind string
+
+	// Name identifies the imported bundle.
+	Name string
+
+	// TrustDomain is the SPIFFE trust domain the bundle is for.
+	TrustDomain string `alias:"trust_domain"`
+
+	// RootPEMs are the PEM-encoded X.509 authorities of the trust domain.
+	RootPEMs []string `alias:"root_pems"`
+
+	Meta               map[string]string `json:",omitempty"`
+	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
+	RaftIndex
+}
+
+func (e *FederatedTrustBundleConfigEntry) GetKind() string            { return FederatedTrustBundle }
+func (e *FederatedTrustBundleConfigEntry) GetName() string            { return e.Name }
+func (e *FederatedTrustBundleConfigEntry) GetMeta() map[string]string { return e.Meta }
+func (e *FederatedTrustBundleConfigEntry) GetEnterpriseMeta() *acl.EnterpriseMeta {
+	return &e.EnterpriseMeta
+}
+func (e *FederatedTrustBundleConfigEntry) GetRaftIndex() *RaftIndex { return &e.RaftIndex }
+
+func (e *FederatedTrustBundleConfigEntry) Normalize() error {
+	if e == nil {
+		return fmt.Errorf("config entry is nil")
+	}
+
+	e.Kind = FederatedTrustBundle
+	e.EnterpriseMeta.Normalize()
+	return nil
+}
+
+func (e *FederatedTrustBundleConfigEntry) Validate() error {
+	if e.Name == "" {
+		return errors.New("Name is required")
+	}
+	if err := validateConfigEntryMeta(e.Meta); err != nil {
+		return err
+	}
+
+	if e.TrustDomain == "" {
+		return errors.New("TrustDomain is required")
+	}
+	if !validSPIFFETrustDomain.MatchString(e.TrustDomain) {
+		return fmt.Errorf("TrustDomain %q is invalid: only lowercase letters, digits, '.', '-' and '_' are allowed", e.TrustDomain)
+	}
+
+	if len(e.RootPEMs) == 0 {
+		return errors.New("at least one root PEM is required")
+	}
+	for i, rootPEM := range e.RootPEMs {
+		if err := validateRootPEM(rootPEM); err != nil {
+			return fmt.Errorf("RootPEMs[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateRootPEM verifies that rootPEM contains only CA certificates.
+func validateRootPEM(rootPEM string) error {
+	rest := []byte(rootPEM)
+	var found bool
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return fmt.Errorf("unexpected PEM block of type %q", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		if !cert.IsCA {
+			return errors.New("certificate is not a CA certificate")
+		}
+		found = true
+	}
+	if !found {
+		return errors.New("failed to parse certificate PEM")
+	}
+	return nil
+}
+
+// CanRead always allows reading the entry since, like the CA roots, the
+// certificates of a trust bundle are public.
+func (e *FederatedTrustBundleConfigEntry) CanRead(authz acl.Authorizer) error {
+	return nil
+}
+
+func (e *FederatedTrustBundleConfigEntry) CanWrite(authz acl.Authorizer) error {
+	var authzContext acl.AuthorizerContext
+	e.FillAuthzContext(&authzContext)
+	return authz.ToAllowAuthorizer().MeshWriteAllowed(&authzContext)
+}
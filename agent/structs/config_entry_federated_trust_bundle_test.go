@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testFederatedRootPEM returns a self-signed certificate, which is a CA
+// certificate when isCA is true.
+func testFederatedRootPEM(t *testing.T, isCA bool) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "other-mesh.example.com"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestFederatedTrustBundleConfigEntry_ValidateAndNormalize(t *testing.T) {
+	defaultMeta := DefaultEnterpriseMetaInDefaultPartition()
+	rootPEM := testFederatedRootPEM(t, true)
+
+	cases := map[string]configEntryTestcase{
+		"valid": {
+			entry: &FederatedTrustBundleConfigEntry{
+				Name:        "other-mesh",
+				TrustDomain: "other-mesh.example.com",
+				RootPEMs:    []string{rootPEM},
+			},
+			expected: &FederatedTrustBundleConfigEntry{
+				Kind:           FederatedTrustBundle,
+				Name:           "other-mesh",
+				TrustDomain:    "other-mesh.example.com",
+				RootPEMs:       []string{rootPEM},
+				EnterpriseMeta: *defaultMeta,
+			},
+		},
+		"missing name": {
+			entry: &FederatedTrustBundleConfigEntry{
+				TrustDomain: "other-mesh.example.com",
+				RootPEMs:    []string{rootPEM},
+			},
+			validateErr: "Name is required",
+		},
+		"missing trust domain": {
+			entry: &FederatedTrustBundleConfigEntry{
+				Name:     "other-mesh",
+				RootPEMs: []string{rootPEM},
+			},
+			validateErr: "TrustDomain is required",
+		},
+		"invalid trust domain": {
+			entry: &FederatedTrustBundleConfigEntry{
+				Name:        "other-mesh",
+				TrustDomain: "spiffe://Other-Mesh",
+				RootPEMs:    []string{rootPEM},
+			},
+			validateErr: `TrustDomain "spiffe://Other-Mesh" is invalid`,
+		},
+		"missing root PEMs": {
+			entry: &FederatedTrustBundleConfigEntry{
+				Name:        "other-mesh",
+				TrustDomain: "other-mesh.example.com",
+			},
+			validateErr: "at least one root PEM is required",
+		},
+		"invalid root PEM": {
+			entry: &FederatedTrustBundleConfigEntry{
+				Name:        "other-mesh",
+				TrustDomain: "other-mesh.example.com",
+				RootPEMs:    []string{rootPEM, "not a certificate"},
+			},
+			validateErr: "RootPEMs[1]: failed to parse certificate PEM",
+		},
+		"root PEM is not a CA": {
+			entry: &FederatedTrustBundleConfigEntry{
+				Name:        "other-mesh",
+				TrustDomain: "other-mesh.example.com",
+				RootPEMs:    []string{testFederatedRootPEM(t, false)},
+			},
+			validateErr: "RootPEMs[0]: certificate is not a CA certificate",
+		},
+	}
+
+	testConfigEntryNormalizeAndValidate(t, cases)
+}
+
+func TestFederatedTrustBundleConfigEntry_ACLs(t *testing.T) {
+	entry := &FederatedTrustBundleConfigEntry{Name: "other-mesh"}
+
+	authz := newTestAuthz(t, `mesh = "read"`)
+	require.NoError(t, entry.CanRead(authz))
+	require.Error(t, entry.CanWrite(authz))
+
+	authz = newTestAuthz(t, `mesh = "write"`)
+	require.NoError(t, entry.CanWrite(authz))
+}
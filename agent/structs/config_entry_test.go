@@ -272,6 +272,36 @@ func TestDecodeConfigEntry_ServiceDefaults(t *testing.T) {
 				MaxInboundConnections: 14,
 			},
 		},
+		{
+			name: "service-defaults-with-LeafCert",
+			snake: `
+				kind = "service-defaults"
+				name = "web"
+				leaf_cert {
+					ttl = "12h"
+					private_key_type = "rsa"
+					private_key_bits = 4096
+				}
+			`,
+			camel: `
+				Kind = "service-defaults"
+				Name = "web"
+				LeafCert {
+					TTL = "12h"
+					PrivateKeyType = "rsa"
+					PrivateKeyBits = 4096
+				}
+			`,
+			expect: &ServiceConfigEntry{
+				Kind: "service-defaults",
+				Name: "web",
+				LeafCert: &LeafCertConfig{
+					TTL:            12 * time.Hour,
+					PrivateKeyType: "rsa",
+					PrivateKeyBits: 4096,
+				},
+			},
+		},
 	} {
 		tc := tc
 
@@ -1976,6 +2006,36 @@ func TestDecodeConfigEntry(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "federated-trust-bundle",
+			snake: `
+				kind = "federated-trust-bundle"
+				name = "other-mesh"
+				trust_domain = "other-mesh.example.com"
+				root_pems = ["ROOT"]
+				meta {
+					"foo" = "bar"
+				}
+			`,
+			camel: `
+				Kind = "federated-trust-bundle"
+				Name = "other-mesh"
+				TrustDomain = "other-mesh.example.com"
+				RootPEMs = ["ROOT"]
+				Meta {
+					"foo" = "bar"
+				}
+			`,
+			expect: &FederatedTrustBundleConfigEntry{
+				Kind:        "federated-trust-bundle",
+				Name:        "other-mesh",
+				TrustDomain: "other-mesh.example.com",
+				RootPEMs:    []string{"ROOT"},
+				Meta: map[string]string{
+					"foo": "bar",
+				},
+			},
+		},
 		{
 			name: "http-route",
 			snake: `
@@ -2681,6 +2741,46 @@ func TestServiceConfigEntry(t *testing.T) {
 				EnterpriseMeta: *DefaultEnterpriseMetaInDefaultPartition(),
 			},
 		},
+		"normalize: leaf cert key bits": {
+			entry: &ServiceConfigEntry{
+				Name: "web",
+				LeafCert: &LeafCertConfig{
+					TTL:            time.Hour,
+					PrivateKeyType: "RSA",
+				},
+			},
+			expected: &ServiceConfigEntry{
+				Kind:           ServiceDefaults,
+				Name:           "web",
+				EnterpriseMeta: *DefaultEnterpriseMetaInDefaultPartition(),
+				LeafCert: &LeafCertConfig{
+					TTL:            time.Hour,
+					PrivateKeyType: "rsa",
+					PrivateKeyBits: 2048,
+				},
+			},
+		},
+		"validate: leaf cert TTL too short": {
+			entry: &ServiceConfigEntry{
+				Name:     "web",
+				LeafCert: &LeafCertConfig{TTL: time.Minute},
+			},
+			validateErr: "leaf cert TTL must be between 1h0m0s and 8760h0m0s",
+		},
+		"validate: leaf cert invalid key type": {
+			entry: &ServiceConfigEntry{
+				Name:     "web",
+				LeafCert: &LeafCertConfig{PrivateKeyType: "dsa"},
+			},
+			validateErr: "invalid leaf cert private key: private key type must be either 'ec' or 'rsa'",
+		},
+		"validate: leaf cert key bits without type": {
+			entry: &ServiceConfigEntry{
+				Name:     "web",
+				LeafCert: &LeafCertConfig{PrivateKeyBits: 384},
+			},
+			validateErr: "leaf cert private key bits require a private key type",
+		},
 		"validate: nil destination address": {
 			entry: &ServiceConfigEntry{
 				Kind:     ServiceDefaults,
@@ -22,6 +22,25 @@ type ConnectAuthorizeRequest struct {
 	// lists.
 	ClientCertURI    string
 	ClientCertSerial string
+
+	// HTTP is the HTTP request to authorize, if any. When it is set, the L7
+	// permissions of the matching intention are evaluated against it instead
+	// of denying the connection.
+	HTTP *ConnectAuthorizeHTTPRequest `json:",omitempty"`
+}
+
+// ConnectAuthorizeHTTPRequest describes the HTTP request evaluated against the
+// L7 permissions of an intention.
+type ConnectAuthorizeHTTPRequest struct {
+	// Method is the HTTP method of the request, such as GET.
+	Method string
+
+	// Path is the path of the request, without the query string.
+	Path string
+
+	// Header are the headers of the request. Like in Go's http.Header, the
+	// keys are canonicalized and the Host header is included.
+	Header map[string][]string `json:",omitempty"`
 }
 
 func (req *ConnectAuthorizeRequest) TargetPartition() string {
@@ -56,6 +56,18 @@ type IndexedCARoots struct {
 	// Roots is a list of root CA certs to trust.
 	Roots []*CARoot
 
+	// RevocationList holds the PEM-encoded certificate revocation lists for
+	// the leaf certificates revoked in this datacenter, one for each signing
+	// certificate of the unexpired leaf certificates. It is only set while
+	// some revoked certificates have not expired yet, and there is a
+	// revocation list for every such signing certificate.
+	RevocationList string `json:",omitempty"`
+
+	// WANFederated is true when this datacenter is federated with other
+	// datacenters, whose leaf certificates are trusted but not covered by
+	// RevocationList.
+	WANFederated bool `json:"-"`
+
 	// QueryMeta contains the meta sent via a header. We ignore for JSON
 	// so this whole structure can be returned.
 	QueryMeta `json:"-"`
@@ -183,6 +195,28 @@ func (c CARoots) Active() *CARoot {
 	return nil
 }
 
+// CAIntermediateRequest is used to retrieve the CSR for, or to set, the
+// intermediate certificate of a CA provider whose root private key is kept
+// outside of Consul.
+type CAIntermediateRequest struct {
+	// Datacenter is the target for this request.
+	Datacenter string
+
+	// Certificate is the PEM-encoded intermediate certificate signed by the
+	// offline root, optionally followed by the certificates needed to chain it
+	// back to the root. It is only used when setting the intermediate.
+	Certificate string
+
+	// WriteRequest is a common struct containing ACL tokens and other
+	// write-related common elements for requests.
+	WriteRequest
+}
+
+// RequestDatacenter returns the datacenter for a given request.
+func (q *CAIntermediateRequest) RequestDatacenter() string {
+	return q.Datacenter
+}
+
 // CASignRequest is the request for signing a service certificate.
 type CASignRequest struct {
 	// Datacenter is the target for this request.
@@ -191,6 +225,12 @@ type CASignRequest struct {
 	// CSR is the PEM-encoded CSR.
 	CSR string
 
+	// Node is the name of the node requesting the certificate. It is recorded
+	// with the issued certificate so that the certificates issued to a node
+	// can be revoked. The servers only record it when the node identities of
+	// the token or the catalog confirm it.
+	Node string
+
 	// WriteRequest is a common struct containing ACL tokens and other
 	// write-related common elements for requests.
 	WriteRequest
@@ -201,6 +241,63 @@ func (q *CASignRequest) RequestDatacenter() string {
 	return q.Datacenter
 }
 
+// CARevokeRequest is the request for revoking the leaf certificates issued in
+// a datacenter. Only the certificates matching all of the given criteria are
+// revoked, and at least one of them must be set.
+type CARevokeRequest struct {
+	// Datacenter is the target for this request.
+	Datacenter string
+
+	// SerialNumber is the serial number of the certificate to revoke, encoded
+	// in standard hex separated by :.
+	SerialNumber string
+
+	// Service is the name of the service whose certificates are revoked.
+	Service string
+
+	// Node is the name of the node whose certificates are revoked. This
+	// includes the certificates requested by its agent for local services.
+	Node string
+
+	// Reason is a free-form description of why the certificates are revoked.
+	Reason string
+
+	// EnterpriseMeta is the namespace and partition of the Service.
+	acl.EnterpriseMeta
+
+	// WriteRequest is a common struct containing ACL tokens and other
+	// write-related common elements for requests.
+	WriteRequest
+}
+
+// RequestDatacenter returns the datacenter for a given request.
+func (q *CARevokeRequest) RequestDatacenter() string {
+	return q.Datacenter
+}
+
+// CARevokeResponse is the response to a CARevokeRequest.
+type CARevokeResponse struct {
+	// Certs are the certificates that were revoked by the request.
+	Certs []*IssuedCert
+}
+
+// CAOCSPRequest is the request for the status of a leaf certificate using
+// the Online Certificate Status Protocol.
+type CAOCSPRequest struct {
+	// Datacenter is the target for this request.
+	Datacenter string
+
+	// Request is the DER-encoded OCSP request.
+	Request []byte
+
+	QueryOptions
+}
+
+// RequestDatacenter returns the datacenter for a given request.
+func (q *CAOCSPRequest) RequestDatacenter() string {
+	return q.Datacenter
+}
+
 // IssuedCert is a certificate that has been issued by a Connect CA.
 type IssuedCert struct {
 	// SerialNumber is the unique serial number for this certificate.
@@ -246,6 +343,22 @@ type IssuedCert struct {
 	ValidAfter  time.Time
 	ValidBefore time.Time
 
+	// Node is the name of the node that requested the certificate, if known.
+	Node string `json:",omitempty"`
+
+	// SigningKeyID is the key ID of the CA certificate that signed this
+	// certificate, encoded like CARoot.SigningKeyID.
+	SigningKeyID string `json:",omitempty"`
+
+	// RootID is the ID of the CA root that was active when the certificate
+	// was signed, which the certificate chains to.
+	RootID string `json:",omitempty"`
+
+	// RevokedAt is the time the certificate was revoked at, or nil if it is
+	// not revoked. RevocationReason is the reason given when revoking it.
+	RevokedAt        *time.Time `json:",omitempty"`
+	RevocationReason string     `json:",omitempty"`
+
 	// EnterpriseMeta is the Consul Enterprise specific metadata
 	acl.EnterpriseMeta
 
@@ -258,6 +371,110 @@ func (i *IssuedCert) Key() string {
 	)
 }
 
+// IsRevoked returns true if the certificate has been revoked.
+func (i *IssuedCert) IsRevoked() bool {
+	return i.RevokedAt != nil
+}
+
+// CARevocationList is the certificate revocation list of the leaf
+// certificates revoked in a datacenter that were signed by one signing
+// certificate.
+type CARevocationList struct {
+	// PEM is the PEM-encoded CRL. It is empty when the signing certificate
+	// is no longer active and its last CRL expired, so the revocation of its
+	// unexpired leaf certificates cannot be checked anymore.
+	PEM string
+
+	// SigningKeyID is the key ID of the CA certificate that signed the CRL,
+	// encoded like CARoot.SigningKeyID. Only the certificates signed by it
+	// are listed.
+	SigningKeyID string
+
+	// Revoked is the number of certificates listed in the CRL.
+	Revoked int
+
+	// ThisUpdate and NextUpdate are the times the CRL was issued at and the
+	// time by which the next CRL will be issued.
+	ThisUpdate time.Time
+	NextUpdate time.Time
+
+	RaftIndex
+}
+
+// CAStatus reports the health of the CA of a datacenter and the inventory
+// of the leaf certificates it issued.
+type CAStatus struct {
+	// Provider is the CA provider in use.
+	Provider string
+
+	// State is the state of the CA on the leader, such as INITIALIZED or
+	// RENEWING while the intermediate is being renewed.
+	State string
+
+	// TrustDomain is the SPIFFE trust domain of the datacenter.
+	TrustDomain string
+
+	// ActiveRootID is the ID of the root that signs the new leaf
+	// certificates.
+	ActiveRootID string
+
+	// RotationInProgress is true while the roots rotated out by a root
+	// rotation are still trusted, until they are pruned.
+	RotationInProgress bool
+
+	// IntermediatePending is true when a CSR was generated for the
+	// intermediate of an offline root and no intermediate was set for it
+	// yet.
+	IntermediatePending bool
+
+	// Roots are the trusted roots, the active one first.
+	Roots []*CARootStatus
+
+	// LocalProxiesOnInactiveRoots are the proxies connected over xDS to the
+	// agent that served the request whose leaf certificate is signed by a
+	// root that is no longer active. The proxies connected to other agents
+	// are not included. It is only set by the HTTP API.
+	LocalProxiesOnInactiveRoots []*CAStatusProxy `json:",omitempty"`
+
+	QueryMeta
+}
+
+// CARootStatus reports the expiry of a trusted root and the number of leaf
+// certificates it signed.
+type CARootStatus struct {
+	ID     string
+	Name   string
+	Active bool
+
+	// NotAfter is the expiry of the root certificate.
+	NotAfter time.Time
+
+	// IntermediateNotAfter is the expiry of the intermediate that signs the
+	// leaf certificates, if the root uses one.
+	IntermediateNotAfter *time.Time `json:",omitempty"`
+
+	// RotatedOutAt is the time the root stopped being the active root.
+	RotatedOutAt *time.Time `json:",omitempty"`
+
+	// LeafCerts is the number of unexpired leaf certificates signed by the
+	// root that were not revoked.
+	LeafCerts int
+}
+
+// CAStatusProxy is a proxy connected over xDS whose leaf certificate is
+// signed by a root that is no longer active.
+type CAStatusProxy struct {
+	ProxyID ServiceID
+	Kind    ServiceKind `json:",omitempty"`
+	Node    string      `json:",omitempty"`
+
+	// LeafSerialNumber and LeafRootID identify the leaf certificate of the
+	// proxy and the root that signed it.
+	LeafSerialNumber string
+	LeafRootID       string
+	LeafValidBefore  time.Time
+}
+
 // CAOp is the operation for a request related to intentions.
 type CAOp string
 
@@ -268,6 +485,9 @@ const (
 	CAOpDeleteProviderState           CAOp = "delete-provider-state"
 	CAOpSetRootsAndConfig             CAOp = "set-roots-config"
 	CAOpIncrementProviderSerialNumber CAOp = "increment-provider-serial"
+	CAOpRevokeLeafCerts               CAOp = "revoke-leaf-certs"
+	CAOpDeleteLeafCerts               CAOp = "delete-leaf-certs"
+	CAOpSetRevocationList             CAOp = "set-revocation-list"
 )
 
 // CARequest is used to modify connect CA data. This is used by the
@@ -293,6 +513,15 @@ type CARequest struct {
 	// ProviderState is the state for the builtin CA provider.
 	ProviderState *CAConsulProviderState
 
+	// LeafCerts is a list of issued leaf certificates. This is used by
+	// CAOpRevokeLeafCerts to update the revoked certificates and by
+	// CAOpDeleteLeafCerts to delete the expired ones.
+	LeafCerts []*IssuedCert
+
+	// RevocationLists are the revocation lists that replace the current ones
+	// along with CAOpRevokeLeafCerts and CAOpSetRevocationList.
+	RevocationLists []*CARevocationList
+
 	// WriteRequest is a common struct containing ACL tokens and other
 	// write-related common elements for requests.
 	WriteRequest
@@ -304,9 +533,10 @@ func (q *CARequest) RequestDatacenter() string {
 }
 
 const (
-	ConsulCAProvider = "consul"
-	VaultCAProvider  = "vault"
-	AWSCAProvider    = "aws-pca"
+	ConsulCAProvider  = "consul"
+	VaultCAProvider   = "vault"
+	AWSCAProvider     = "aws-pca"
+	OfflineCAProvider = "offline"
 )
 
 // CAConfiguration is the configuration for the current CA plugin.
@@ -442,6 +672,12 @@ type CommonCAProviderConfig struct {
 	// name. As with PrivateKeyType this is only relevant whan the provier is
 	// generating new CA keys (root or intermediate).
 	PrivateKeyBits int
+
+	// MinLeafCertTTL and MaxLeafCertTTL bound the TTL that services can
+	// request for their leaf certificates in their service-defaults config
+	// entry. They default to the minimum leaf cert TTL and to the LeafCertTTL.
+	MinLeafCertTTL time.Duration
+	MaxLeafCertTTL time.Duration
 }
 
 var MinLeafCertTTL = time.Hour
@@ -497,13 +733,65 @@ func (c CommonCAProviderConfig) Validate() error {
 		return fmt.Errorf("Intermediate Cert TTL must be greater or equal than 3 * LeafCertTTL (>=%s).", 3*c.LeafCertTTL)
 	}
 
-	switch c.PrivateKeyType {
+	if c.MinLeafCertTTL != 0 {
+		if c.MinLeafCertTTL < MinLeafCertTTL {
+			return fmt.Errorf("min leaf cert TTL must be greater or equal than %s", MinLeafCertTTL)
+		}
+		if c.MinLeafCertTTL > c.LeafCertTTL {
+			return fmt.Errorf("min leaf cert TTL must be less or equal than the leaf cert TTL (%s)", c.LeafCertTTL)
+		}
+	}
+	if c.MaxLeafCertTTL != 0 {
+		if c.MaxLeafCertTTL > MaxLeafCertTTL {
+			return fmt.Errorf("max leaf cert TTL must be less than %s", MaxLeafCertTTL)
+		}
+		if c.MaxLeafCertTTL < c.LeafCertTTL {
+			return fmt.Errorf("max leaf cert TTL must be greater or equal than the leaf cert TTL (%s)", c.LeafCertTTL)
+		}
+		// The same reasoning as for the LeafCertTTL applies to the longest
+		// leaf certificates a service can be issued.
+		if c.IntermediateCertTTL < (3 * c.MaxLeafCertTTL) {
+			return fmt.Errorf("Intermediate Cert TTL must be greater or equal than 3 * MaxLeafCertTTL (>=%s).", 3*c.MaxLeafCertTTL)
+		}
+	}
+
+	return validatePrivateKey(c.PrivateKeyType, c.PrivateKeyBits)
+}
+
+// LeafCertTTLFor returns the TTL of the leaf certificates of a service that
+// overrides the LeafCertTTL with ttl, bounded by MinLeafCertTTL and
+// MaxLeafCertTTL. The LeafCertTTL is returned if ttl is zero. When they are
+// not set, the bounds default to the minimum leaf cert TTL and the
+// LeafCertTTL so that services can only request shorter certificates.
+func (c CommonCAProviderConfig) LeafCertTTLFor(ttl time.Duration) time.Duration {
+	if ttl == 0 {
+		return c.LeafCertTTL
+	}
+
+	min, max := c.MinLeafCertTTL, c.MaxLeafCertTTL
+	if min == 0 {
+		min = MinLeafCertTTL
+	}
+	if max == 0 {
+		max = c.LeafCertTTL
+	}
+	switch {
+	case ttl < min:
+		return min
+	case max > 0 && ttl > max:
+		return max
+	}
+	return ttl
+}
+
+func validatePrivateKey(keyType string, keyBits int) error {
+	switch keyType {
 	case "ec":
-		if c.PrivateKeyBits != 224 && c.PrivateKeyBits != 256 && c.PrivateKeyBits != 384 && c.PrivateKeyBits != 521 {
+		if keyBits != 224 && keyBits != 256 && keyBits != 384 && keyBits != 521 {
 			return fmt.Errorf("EC key length must be one of (224, 256, 384, 521) bits")
 		}
 	case "rsa":
-		if c.PrivateKeyBits != 2048 && c.PrivateKeyBits != 4096 {
+		if keyBits != 2048 && keyBits != 4096 {
 			return fmt.Errorf("RSA key length must be 2048 or 4096 bits")
 		}
 	default:
@@ -530,13 +818,30 @@ func (c *ConsulCAProviderConfig) Validate() error {
 	return nil
 }
 
+// OfflineCAProviderConfig is the configuration for the offline CA provider,
+// which keeps the root private key outside of Consul and signs leaf
+// certificates with an intermediate that an operator signs offline.
+type OfflineCAProviderConfig struct {
+	CommonCAProviderConfig `mapstructure:",squash"`
+
+	// RootCert is the PEM-encoded certificate of the offline root CA.
+	RootCert string
+}
+
 // CAConsulProviderState is used to track the built-in Consul CA provider's state.
+// The offline CA provider uses it as well.
 type CAConsulProviderState struct {
 	ID               string
 	PrivateKey       string
 	RootCert         string
 	IntermediateCert string
 
+	// PendingPrivateKey and PendingCSR are only used by the offline CA
+	// provider. They hold the key and the CSR of the next intermediate until
+	// the operator uploads the certificate signed by the offline root.
+	PendingPrivateKey string
+	PendingCSR        string
+
 	RaftIndex
 }
 
@@ -591,6 +896,10 @@ type CALeafRequest struct {
 	// Datacenter is the target for this request.
 	Datacenter string
 
+	// Cert is the certificate that was issued. It is recorded so that it can
+	// be revoked later.
+	Cert *IssuedCert
+
 	// WriteRequest is a common struct containing ACL tokens and other
 	// write-related common elements for requests.
 	WriteRequest
@@ -154,6 +154,58 @@ func TestCAProviderConfig_Validate(t *testing.T) {
 			wantErr: true,
 			wantMsg: "root cert TTL is set and is not greater than intermediate cert ttl. root cert ttl: 3h0m0s, intermediate cert ttl: 4h0m0s",
 		},
+		{
+			name: "min leaf cert TTL greater than leaf cert TTL",
+			cfg: &CommonCAProviderConfig{
+				LeafCertTTL:         1 * time.Hour,
+				MinLeafCertTTL:      2 * time.Hour,
+				IntermediateCertTTL: 4 * time.Hour,
+				RootCertTTL:         5 * time.Hour,
+				PrivateKeyType:      "ec",
+				PrivateKeyBits:      256,
+			},
+			wantErr: true,
+			wantMsg: "min leaf cert TTL must be less or equal than the leaf cert TTL (1h0m0s)",
+		},
+		{
+			name: "max leaf cert TTL less than leaf cert TTL",
+			cfg: &CommonCAProviderConfig{
+				LeafCertTTL:         2 * time.Hour,
+				MaxLeafCertTTL:      1 * time.Hour,
+				IntermediateCertTTL: 6 * time.Hour,
+				RootCertTTL:         7 * time.Hour,
+				PrivateKeyType:      "ec",
+				PrivateKeyBits:      256,
+			},
+			wantErr: true,
+			wantMsg: "max leaf cert TTL must be greater or equal than the leaf cert TTL (2h0m0s)",
+		},
+		{
+			name: "intermediate cert ttl too short for max leaf cert TTL",
+			cfg: &CommonCAProviderConfig{
+				LeafCertTTL:         1 * time.Hour,
+				MaxLeafCertTTL:      2 * time.Hour,
+				IntermediateCertTTL: 5 * time.Hour,
+				RootCertTTL:         7 * time.Hour,
+				PrivateKeyType:      "ec",
+				PrivateKeyBits:      256,
+			},
+			wantErr: true,
+			wantMsg: "Intermediate Cert TTL must be greater or equal than 3 * MaxLeafCertTTL (>=6h0m0s).",
+		},
+		{
+			name: "good min and max leaf cert TTLs",
+			cfg: &CommonCAProviderConfig{
+				LeafCertTTL:         2 * time.Hour,
+				MinLeafCertTTL:      1 * time.Hour,
+				MaxLeafCertTTL:      3 * time.Hour,
+				IntermediateCertTTL: 9 * time.Hour,
+				RootCertTTL:         10 * time.Hour,
+				PrivateKeyType:      "ec",
+				PrivateKeyBits:      256,
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -166,3 +218,21 @@ func TestCAProviderConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestCommonCAProviderConfig_LeafCertTTLFor(t *testing.T) {
+	cfg := CommonCAProviderConfig{
+		LeafCertTTL:    24 * time.Hour,
+		MinLeafCertTTL: 2 * time.Hour,
+		MaxLeafCertTTL: 48 * time.Hour,
+	}
+	require.Equal(t, 24*time.Hour, cfg.LeafCertTTLFor(0))
+	require.Equal(t, 12*time.Hour, cfg.LeafCertTTLFor(12*time.Hour))
+	require.Equal(t, 2*time.Hour, cfg.LeafCertTTLFor(time.Hour))
+	require.Equal(t, 48*time.Hour, cfg.LeafCertTTLFor(72*time.Hour))
+
+	// Without bounds, services can only request shorter certificates.
+	cfg = CommonCAProviderConfig{LeafCertTTL: 24 * time.Hour}
+	require.Equal(t, MinLeafCertTTL, cfg.LeafCertTTLFor(time.Minute))
+	require.Equal(t, 12*time.Hour, cfg.LeafCertTTLFor(12*time.Hour))
+	require.Equal(t, 24*time.Hour, cfg.LeafCertTTLFor(72*time.Hour))
+}
@@ -5,6 +5,7 @@ package structs
 
 import (
 	"net"
+	"time"
 
 	"github.com/hashicorp/raft"
 )
@@ -70,6 +71,83 @@ func (op *RaftRemovePeerRequest) RequestDatacenter() string {
 	return op.Datacenter
 }
 
+// RaftLogFilter selects which entries are returned when reading the Raft log.
+type RaftLogFilter struct {
+	// MinIndex and MaxIndex bound the range of log indexes to consider. A
+	// zero value means the range is open on that side.
+	MinIndex uint64
+	MaxIndex uint64
+
+	// Types restricts the output to entries whose message type (such as
+	// "KVS" or "Register") or Raft log type (such as "LogConfiguration")
+	// matches one of the given names, case-insensitively.
+	Types []string
+
+	// Limit is the maximum number of entries to return. The most recent
+	// matching entries are returned when there are more than Limit.
+	Limit int
+}
+
+// RaftLogRequest is used by the Operator endpoint to list and decode
+// entries from a server's Raft log.
+type RaftLogRequest struct {
+	// Datacenter is the target this request is intended for.
+	Datacenter string
+
+	RaftLogFilter
+
+	// QueryOptions holds the ACL token to go along with this request. Use
+	// AllowStale to read the log of the server that receives the request
+	// rather than the leader's.
+	QueryOptions
+}
+
+// RequestDatacenter returns the datacenter for a given request.
+func (op *RaftLogRequest) RequestDatacenter() string {
+	return op.Datacenter
+}
+
+// RaftLogEntry is a single decoded entry from the Raft log.
+type RaftLogEntry struct {
+	Index uint64
+	Term  uint64
+
+	// LogType is the Raft-level type of the entry, such as "LogCommand".
+	LogType string
+
+	// MessageType is the name of the FSM message type for command entries.
+	MessageType string `json:",omitempty"`
+
+	// AppendedAt is the time the leader first appended the entry, when the
+	// log store recorded it.
+	AppendedAt time.Time `json:",omitempty"`
+
+	// Size is the size in bytes of the entry's data.
+	Size int
+
+	// Chunked is true if the entry is one part of a command that was split
+	// across multiple log entries, in which case Body is not decoded.
+	Chunked bool `json:",omitempty"`
+
+	// Body is a summary of the decoded request with sensitive fields such as
+	// secrets, private keys and KV values redacted.
+	Body map[string]interface{} `json:",omitempty"`
+
+	// Error is set if the entry's data could not be decoded.
+	Error string `json:",omitempty"`
+}
+
+// RaftLogResponse is returned when reading the Raft log.
+type RaftLogResponse struct {
+	// FirstIndex and LastIndex are the bounds of the log currently held by
+	// the server that answered the request.
+	FirstIndex uint64
+	LastIndex  uint64
+
+	// Entries are the matching entries in ascending index order.
+	Entries []*RaftLogEntry
+}
+
 // AutopilotSetConfigRequest is used by the Operator endpoint to update the
 // current Autopilot configuration of the cluster.
 type AutopilotSetConfigRequest struct {
@@ -961,6 +961,10 @@ func (o *ServiceConfigEntry) DeepCopy() *ServiceConfigEntry {
 			}
 		}
 	}
+	if o.LeafCert != nil {
+		cp.LeafCert = new(LeafCertConfig)
+		*cp.LeafCert = *o.LeafCert
+	}
 	if o.Meta != nil {
 		cp.Meta = make(map[string]string, len(o.Meta))
 		for k2, v2 := range o.Meta {
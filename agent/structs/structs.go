@@ -45,50 +45,55 @@ type RaftIndex struct {
 // These are serialized between Consul servers and stored in Consul snapshots,
 // so entries must only ever be added.
 const (
-	RegisterRequestType             MessageType = 0
-	DeregisterRequestType                       = 1
-	KVSRequestType                              = 2
-	SessionRequestType                          = 3
-	DeprecatedACLRequestType                    = 4 // Removed with the legacy ACL system
-	TombstoneRequestType                        = 5
-	CoordinateBatchUpdateType                   = 6
-	PreparedQueryRequestType                    = 7
-	TxnRequestType                              = 8
-	AutopilotRequestType                        = 9
-	AreaRequestType                             = 10
-	ACLBootstrapRequestType                     = 11
-	IntentionRequestType                        = 12
-	ConnectCARequestType                        = 13
-	ConnectCAProviderStateType                  = 14
-	ConnectCAConfigType                         = 15 // FSM snapshots only.
-	IndexRequestType                            = 16 // FSM snapshots only.
-	ACLTokenSetRequestType                      = 17
-	ACLTokenDeleteRequestType                   = 18
-	ACLPolicySetRequestType                     = 19
-	ACLPolicyDeleteRequestType                  = 20
-	ConnectCALeafRequestType                    = 21
-	ConfigEntryRequestType                      = 22
-	ACLRoleSetRequestType                       = 23
-	ACLRoleDeleteRequestType                    = 24
-	ACLBindingRuleSetRequestType                = 25
-	ACLBindingRuleDeleteRequestType             = 26
-	ACLAuthMethodSetRequestType                 = 27
-	ACLAuthMethodDeleteRequestType              = 28
-	ChunkingStateType                           = 29
-	FederationStateRequestType                  = 30
-	SystemMetadataRequestType                   = 31
-	ServiceVirtualIPRequestType                 = 32
-	FreeVirtualIPRequestType                    = 33
-	KindServiceNamesType                        = 34
-	PeeringWriteType                            = 35
-	PeeringDeleteType                           = 36
-	PeeringTerminateByIDType                    = 37
-	PeeringTrustBundleWriteType                 = 38
-	PeeringTrustBundleDeleteType                = 39
-	PeeringSecretsWriteType                     = 40
-	RaftLogVerifierCheckpoint                   = 41 // Only used for log verifier, no-op on FSM.
-	ResourceOperationType                       = 42
-	UpdateVirtualIPRequestType                  = 43
+	RegisterRequestType                 MessageType = 0
+	DeregisterRequestType                           = 1
+	KVSRequestType                                  = 2
+	SessionRequestType                              = 3
+	DeprecatedACLRequestType                        = 4 // Removed with the legacy ACL system
+	TombstoneRequestType                            = 5
+	CoordinateBatchUpdateType                       = 6
+	PreparedQueryRequestType                        = 7
+	TxnRequestType                                  = 8
+	AutopilotRequestType                            = 9
+	AreaRequestType                                 = 10
+	ACLBootstrapRequestType                         = 11
+	IntentionRequestType                            = 12
+	ConnectCARequestType                            = 13
+	ConnectCAProviderStateType                      = 14
+	ConnectCAConfigType                             = 15 // FSM snapshots only.
+	IndexRequestType                                = 16 // FSM snapshots only.
+	ACLTokenSetRequestType                          = 17
+	ACLTokenDeleteRequestType                       = 18
+	ACLPolicySetRequestType                         = 19
+	ACLPolicyDeleteRequestType                      = 20
+	ConnectCALeafRequestType                        = 21
+	ConfigEntryRequestType                          = 22
+	ACLRoleSetRequestType                           = 23
+	ACLRoleDeleteRequestType                        = 24
+	ACLBindingRuleSetRequestType                    = 25
+	ACLBindingRuleDeleteRequestType                 = 26
+	ACLAuthMethodSetRequestType                     = 27
+	ACLAuthMethodDeleteRequestType                  = 28
+	ChunkingStateType                               = 29
+	FederationStateRequestType                      = 30
+	SystemMetadataRequestType                       = 31
+	ServiceVirtualIPRequestType                     = 32
+	FreeVirtualIPRequestType                        = 33
+	KindServiceNamesType                            = 34
+	PeeringWriteType                                = 35
+	PeeringDeleteType                               = 36
+	PeeringTerminateByIDType                        = 37
+	PeeringTrustBundleWriteType                     = 38
+	PeeringTrustBundleDeleteType                    = 39
+	PeeringSecretsWriteType                         = 40
+	RaftLogVerifierCheckpoint                       = 41 // Only used for log verifier, no-op on FSM.
+	ResourceOperationType                           = 42
+	UpdateVirtualIPRequestType                      = 43
+	ACLTemplatedPolicySetRequestType                = 44
+	ACLTemplatedPolicyDeleteRequestType             = 45
+	ACLTokenUsageRequestType                        = 46
+	ConnectCALeafCertType                           = 47 // FSM snapshots only.
+	ConnectCARevocationListType                     = 48 // FSM snapshots only.
 )
 
 const (
@@ -115,49 +120,54 @@ const (
 // requestTypeStrings is used for snapshot enhance
 // any new request types added must be placed here
 var requestTypeStrings = map[MessageType]string{
-	RegisterRequestType:             "Register",
-	DeregisterRequestType:           "Deregister",
-	KVSRequestType:                  "KVS",
-	SessionRequestType:              "Session",
-	DeprecatedACLRequestType:        "ACL", // DEPRECATED (ACL-Legacy-Compat)
-	TombstoneRequestType:            "Tombstone",
-	CoordinateBatchUpdateType:       "CoordinateBatchUpdate",
-	PreparedQueryRequestType:        "PreparedQuery",
-	TxnRequestType:                  "Txn",
-	AutopilotRequestType:            "Autopilot",
-	AreaRequestType:                 "Area",
-	ACLBootstrapRequestType:         "ACLBootstrap",
-	IntentionRequestType:            "Intention",
-	ConnectCARequestType:            "ConnectCA",
-	ConnectCAProviderStateType:      "ConnectCAProviderState",
-	ConnectCAConfigType:             "ConnectCAConfig", // FSM snapshots only.
-	IndexRequestType:                "Index",           // FSM snapshots only.
-	ACLTokenSetRequestType:          "ACLToken",
-	ACLTokenDeleteRequestType:       "ACLTokenDelete",
-	ACLPolicySetRequestType:         "ACLPolicy",
-	ACLPolicyDeleteRequestType:      "ACLPolicyDelete",
-	ConnectCALeafRequestType:        "ConnectCALeaf",
-	ConfigEntryRequestType:          "ConfigEntry",
-	ACLRoleSetRequestType:           "ACLRole",
-	ACLRoleDeleteRequestType:        "ACLRoleDelete",
-	ACLBindingRuleSetRequestType:    "ACLBindingRule",
-	ACLBindingRuleDeleteRequestType: "ACLBindingRuleDelete",
-	ACLAuthMethodSetRequestType:     "ACLAuthMethod",
-	ACLAuthMethodDeleteRequestType:  "ACLAuthMethodDelete",
-	ChunkingStateType:               "ChunkingState",
-	FederationStateRequestType:      "FederationState",
-	SystemMetadataRequestType:       "SystemMetadata",
-	ServiceVirtualIPRequestType:     "ServiceVirtualIP",
-	FreeVirtualIPRequestType:        "FreeVirtualIP",
-	KindServiceNamesType:            "KindServiceName",
-	PeeringWriteType:                "Peering",
-	PeeringDeleteType:               "PeeringDelete",
-	PeeringTrustBundleWriteType:     "PeeringTrustBundle",
-	PeeringTrustBundleDeleteType:    "PeeringTrustBundleDelete",
-	PeeringSecretsWriteType:         "PeeringSecret",
-	RaftLogVerifierCheckpoint:       "RaftLogVerifierCheckpoint",
-	ResourceOperationType:           "Resource",
-	UpdateVirtualIPRequestType:      "UpdateManualVirtualIPRequestType",
+	RegisterRequestType:                 "Register",
+	DeregisterRequestType:               "Deregister",
+	KVSRequestType:                      "KVS",
+	SessionRequestType:                  "Session",
+	DeprecatedACLRequestType:            "ACL", // DEPRECATED (ACL-Legacy-Compat)
+	TombstoneRequestType:                "Tombstone",
+	CoordinateBatchUpdateType:           "CoordinateBatchUpdate",
+	PreparedQueryRequestType:            "PreparedQuery",
+	TxnRequestType:                      "Txn",
+	AutopilotRequestType:                "Autopilot",
+	AreaRequestType:                     "Area",
+	ACLBootstrapRequestType:             "ACLBootstrap",
+	IntentionRequestType:                "Intention",
+	ConnectCARequestType:                "ConnectCA",
+	ConnectCAProviderStateType:          "ConnectCAProviderState",
+	ConnectCAConfigType:                 "ConnectCAConfig", // FSM snapshots only.
+	IndexRequestType:                    "Index",           // FSM snapshots only.
+	ACLTokenSetRequestType:              "ACLToken",
+	ACLTokenDeleteRequestType:           "ACLTokenDelete",
+	ACLPolicySetRequestType:             "ACLPolicy",
+	ACLPolicyDeleteRequestType:          "ACLPolicyDelete",
+	ConnectCALeafRequestType:            "ConnectCALeaf",
+	ConfigEntryRequestType:              "ConfigEntry",
+	ACLRoleSetRequestType:               "ACLRole",
+	ACLRoleDeleteRequestType:            "ACLRoleDelete",
+	ACLBindingRuleSetRequestType:        "ACLBindingRule",
+	ACLBindingRuleDeleteRequestType:     "ACLBindingRuleDelete",
+	ACLAuthMethodSetRequestType:         "ACLAuthMethod",
+	ACLAuthMethodDeleteRequestType:      "ACLAuthMethodDelete",
+	ChunkingStateType:                   "ChunkingState",
+	FederationStateRequestType:          "FederationState",
+	SystemMetadataRequestType:           "SystemMetadata",
+	ServiceVirtualIPRequestType:         "ServiceVirtualIP",
+	FreeVirtualIPRequestType:            "FreeVirtualIP",
+	KindServiceNamesType:                "KindServiceName",
+	PeeringWriteType:                    "Peering",
+	PeeringDeleteType:                   "PeeringDelete",
+	PeeringTrustBundleWriteType:         "PeeringTrustBundle",
+	PeeringTrustBundleDeleteType:        "PeeringTrustBundleDelete",
+	PeeringSecretsWriteType:             "PeeringSecret",
+	RaftLogVerifierCheckpoint:           "RaftLogVerifierCheckpoint",
+	ResourceOperationType:               "Resource",
+	UpdateVirtualIPRequestType:          "UpdateManualVirtualIPRequestType",
+	ACLTemplatedPolicySetRequestType:    "ACLTemplatedPolicy",
+	ACLTemplatedPolicyDeleteRequestType: "ACLTemplatedPolicyDelete",
+	ACLTokenUsageRequestType:            "ACLTokenUsage",
+	ConnectCALeafCertType:               "ConnectCALeafCert",       // FSM snapshots only.
+	ConnectCARevocationListType:         "ConnectCARevocationList", // FSM snapshots only.
 }
 
 const (
@@ -2673,6 +2683,12 @@ type KVSRequest struct {
 	Datacenter string
 	Op         api.KVOp // Which operation are we performing
 	DirEnt     DirEntry // Which directory entry
+
+	// SourceIP is the IP address of the client that made the request. It is
+	// used to evaluate the conditions of ACL rules, and is set by the agent
+	// from the HTTP connection rather than decoded from the request.
+	SourceIP string `json:"-"`
+
 	WriteRequest
 }
 
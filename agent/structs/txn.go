@@ -101,6 +101,12 @@ type TxnOps []*TxnOp
 type TxnRequest struct {
 	Datacenter string
 	Ops        TxnOps
+
+	// SourceIP is the IP address of the client that made the request. It is
+	// used to evaluate the conditions of ACL rules, and is set by the agent
+	// from the HTTP connection rather than decoded from the request.
+	SourceIP string `json:"-"`
+
 	WriteRequest
 }
 
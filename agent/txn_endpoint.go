@@ -371,7 +371,7 @@ func (s *HTTPHandlers) Txn(resp http.ResponseWriter, req *http.Request) (interfa
 
 		ret, conflict = reply, len(reply.Errors) > 0
 	} else {
-		args := structs.TxnRequest{Ops: ops}
+		args := structs.TxnRequest{Ops: ops, SourceIP: remoteIPFromRequest(req)}
 		s.parseDC(req, &args.Datacenter)
 		s.parseToken(req, &args.Token)
 
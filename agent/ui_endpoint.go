@@ -942,3 +942,29 @@ RPC:
 	}
 	return result, nil
 }
+
+// UIOIDCAuthMethods lists the auth methods of type "oidc" so that the UI can
+// offer them as login options.
+func (s *HTTPHandlers) UIOIDCAuthMethods(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Parse arguments
+	args := structs.DCSpecificRequest{}
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+	if err := s.parseEntMeta(req, &args.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	// Make the RPC request
+	var out structs.ACLOIDCAuthMethodListResponse
+	defer setMeta(resp, &out.QueryMeta)
+	if err := s.agent.RPC(req.Context(), "Internal.OIDCAuthMethods", &args, &out); err != nil {
+		return nil, err
+	}
+
+	// Ensure at least a zero length slice
+	if out.AuthMethods == nil {
+		out.AuthMethods = make([]*structs.ACLOIDCAuthMethodStub, 0)
+	}
+	return out.AuthMethods, nil
+}
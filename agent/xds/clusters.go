@@ -484,6 +484,12 @@ func makeMTLSTransportSocket(cfgSnap *proxycfg.ConfigSnapshot, uid proxycfg.Upst
 	if err != nil {
 		return nil, fmt.Errorf("failed to inject SAN matcher rules for cluster %q: %v", sni, err)
 	}
+	if uid.Peer == "" && (uid.Datacenter == "" || uid.Datacenter == cfgSnap.Datacenter) {
+		// The revocation list only covers the local datacenter.
+		if err := injectRevocationList(commonTLSContext, cfgSnap.RevocationList()); err != nil {
+			return nil, fmt.Errorf("failed to inject revocation list for cluster %q: %v", sni, err)
+		}
+	}
 	tlsContext := envoy_tls_v3.UpstreamTlsContext{
 		CommonTlsContext: commonTLSContext,
 		Sni:              sni,
@@ -1633,6 +1639,33 @@ func injectSANMatcher(tlsContext *envoy_tls_v3.CommonTlsContext, matchStrings ..
 	return nil
 }
 
+// injectRevocationList configures the validation context to reject the leaf
+// certificates in the given revocation lists. Only the leaf certificates are
+// checked since the revocation lists only cover the certificates signed in the
+// local datacenter. Envoy then rejects the certificates whose signing
+// certificate has no revocation list, so it must only be used when the peer
+// certificate is signed in the local datacenter.
+func injectRevocationList(tlsContext *envoy_tls_v3.CommonTlsContext, crlPEM string) error {
+	if tlsContext == nil || crlPEM == "" {
+		return nil
+	}
+
+	validationCtx, ok := tlsContext.ValidationContextType.(*envoy_tls_v3.CommonTlsContext_ValidationContext)
+	if !ok {
+		return fmt.Errorf("invalid type: expected CommonTlsContext_ValidationContext, got %T",
+			tlsContext.ValidationContextType)
+	}
+
+	validationCtx.ValidationContext.Crl = &envoy_core_v3.DataSource{
+		Specifier: &envoy_core_v3.DataSource_InlineString{
+			InlineString: crlPEM,
+		},
+	}
+	validationCtx.ValidationContext.OnlyVerifyLeafCertCrl = true
+
+	return nil
+}
+
 // makeClusterFromUserConfig returns the listener config decoded from an
 // arbitrary proto3 json format string or an error if it's invalid.
 //
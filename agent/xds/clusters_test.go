@@ -1707,3 +1707,20 @@ func TestMakeJWTCertValidationContext(t *testing.T) {
 		})
 	}
 }
+
+func TestInjectRevocationList(t *testing.T) {
+	tlsContext := makeCommonTLSContext(&structs.IssuedCert{}, "root", nil)
+
+	// Nothing is injected without a revocation list.
+	require.NoError(t, injectRevocationList(tlsContext, ""))
+	validationCtx := tlsContext.GetValidationContext()
+	require.Nil(t, validationCtx.Crl)
+	require.False(t, validationCtx.OnlyVerifyLeafCertCrl)
+
+	require.NoError(t, injectRevocationList(tlsContext, "crl"))
+	require.Equal(t, "crl", validationCtx.Crl.GetInlineString())
+	require.True(t, validationCtx.OnlyVerifyLeafCertCrl)
+
+	require.NoError(t, injectRevocationList(nil, "crl"))
+	require.Error(t, injectRevocationList(&envoy_tls_v3.CommonTlsContext{}, "crl"))
+}
@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package xds
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/consul/agent/proxycfg"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// connectedProxies tracks the latest config snapshot of the proxies with an
+// open xDS stream. Its zero value is ready to use.
+type connectedProxies struct {
+	lock      sync.Mutex
+	nextID    uint64
+	snapshots map[uint64]*proxycfg.ConfigSnapshot
+}
+
+// register returns the ID of a new stream and a function to call when the
+// stream is closed.
+func (c *connectedProxies) register() (uint64, func()) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.nextID++
+	id := c.nextID
+	return id, func() {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		delete(c.snapshots, id)
+	}
+}
+
+// update records the snapshot sent on the stream.
+func (c *connectedProxies) update(id uint64, snap *proxycfg.ConfigSnapshot) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.snapshots == nil {
+		c.snapshots = make(map[uint64]*proxycfg.ConfigSnapshot)
+	}
+	c.snapshots[id] = snap
+}
+
+// ProxiesOnInactiveRoots returns the proxies with an open xDS stream whose
+// leaf certificate is signed by a root that is no longer active, which is
+// the case until the leaves are renewed after a root rotation.
+func (s *Server) ProxiesOnInactiveRoots() []*structs.CAStatusProxy {
+	s.connectedProxies.lock.Lock()
+	defer s.connectedProxies.lock.Unlock()
+
+	var proxies []*structs.CAStatusProxy
+	for _, snap := range s.connectedProxies.snapshots {
+		leaf := snap.Leaf()
+		if leaf == nil || snap.Roots == nil {
+			continue
+		}
+		rootID := leafRootID(leaf, snap.Roots)
+		if rootID == "" || rootID == snap.Roots.ActiveRootID {
+			continue
+		}
+		proxies = append(proxies, &structs.CAStatusProxy{
+			ProxyID:          snap.ProxyID.ServiceID,
+			Kind:             snap.Kind,
+			Node:             snap.ProxyID.NodeName,
+			LeafSerialNumber: leaf.SerialNumber,
+			LeafRootID:       rootID,
+			LeafValidBefore:  leaf.ValidBefore,
+		})
+	}
+
+	sort.Slice(proxies, func(i, j int) bool {
+		if proxies[i].Node != proxies[j].Node {
+			return proxies[i].Node < proxies[j].Node
+		}
+		return proxies[i].ProxyID.String() < proxies[j].ProxyID.String()
+	})
+	return proxies
+}
+
+// leafRootID returns the ID of the root that signed the leaf, or an empty
+// string if it is not one of the given roots. The leaves signed by older
+// servers do not record their root so they are matched on the key that
+// signed them.
+func leafRootID(leaf *structs.IssuedCert, roots *structs.IndexedCARoots) string {
+	if leaf.RootID != "" {
+		return leaf.RootID
+	}
+	for _, root := range roots.Roots {
+		if leaf.SigningKeyID != "" && root.SigningKeyID == leaf.SigningKeyID {
+			return root.ID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package xds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/agent/proxycfg"
+)
+
+func TestServer_ProxiesOnInactiveRoots(t *testing.T) {
+	s := &Server{}
+
+	snap := proxycfg.TestConfigSnapshot(t, nil, nil)
+	oldRoot := snap.Roots.Roots[0]
+	snap.ConnectProxy.Leaf.SigningKeyID = oldRoot.SigningKeyID
+
+	id, unregister := s.connectedProxies.register()
+	s.connectedProxies.update(id, snap)
+
+	// The leaf is signed by the active root.
+	require.Empty(t, s.ProxiesOnInactiveRoots())
+
+	// Rotate the root, the leaf is now signed by an inactive root.
+	newRoot := connect.TestCA(t, oldRoot)
+	oldRoot.Active = false
+	snap.Roots.Roots = append(snap.Roots.Roots, newRoot)
+	snap.Roots.ActiveRootID = newRoot.ID
+
+	proxies := s.ProxiesOnInactiveRoots()
+	require.Len(t, proxies, 1)
+	require.Equal(t, snap.ProxyID.ServiceID, proxies[0].ProxyID)
+	require.Equal(t, oldRoot.ID, proxies[0].LeafRootID)
+	require.Equal(t, snap.ConnectProxy.Leaf.SerialNumber, proxies[0].LeafSerialNumber)
+
+	// The root recorded with the leaf takes precedence over its signing key.
+	snap.ConnectProxy.Leaf.RootID = newRoot.ID
+	require.Empty(t, s.ProxiesOnInactiveRoots())
+
+	// The proxies are forgotten once their stream is closed.
+	snap.ConnectProxy.Leaf.RootID = oldRoot.ID
+	require.Len(t, s.ProxiesOnInactiveRoots(), 1)
+	unregister()
+	require.Empty(t, s.ProxiesOnInactiveRoots())
+}
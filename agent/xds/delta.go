@@ -161,6 +161,9 @@ func (s *Server) processDelta(stream ADSDeltaStream, reqCh <-chan *envoy_discove
 
 	logger := s.Logger.Named(logging.XDS).With("xdsVersion", "v3")
 
+	streamID, unregister := s.connectedProxies.register()
+	defer unregister()
+
 	// need to run a small state machine to get through initial authentication.
 	var state = stateDeltaInit
 
@@ -270,6 +273,9 @@ func (s *Server) processDelta(stream ADSDeltaStream, reqCh <-chan *envoy_discove
 				return status.Error(codes.Aborted, "xDS stream terminated due to an irrecoverable error, please try again")
 			}
 			proxySnapshot = cs
+			if snap, ok := cs.(*proxycfg.ConfigSnapshot); ok {
+				s.connectedProxies.update(streamID, snap)
+			}
 
 			newRes, err := getEnvoyConfiguration(proxySnapshot, logger, s.CfgFetcher)
 			if err != nil {
@@ -720,6 +720,118 @@ end`,
 				}, nil)
 			},
 		},
+		{
+			// Insert the compressor and cache filters into the inbound HTTP listener.
+			name: "http-compression-cache-inbound",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshot(t, func(ns *structs.NodeService) {
+					ns.Proxy.Config = map[string]any{"protocol": "http"}
+					ns.Proxy.EnvoyExtensions = makeHTTPCompressionCacheEnvoyExtension("inbound")
+				}, nil)
+			},
+		},
+		{
+			// Insert the compressor and cache filters into the outbound HTTP listener of db, the TCP listeners
+			// of the other upstreams are left unchanged.
+			name: "http-compression-cache-outbound",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshotDiscoveryChain(t, "chain-and-router", false, func(ns *structs.NodeService) {
+					ns.Proxy.EnvoyExtensions = makeHTTPCompressionCacheEnvoyExtension("outbound")
+				}, nil)
+			},
+		},
+		{
+			// Modify the headers of the inbound HTTP listener and mirror its requests to the db upstream.
+			name: "http-headers-mirror-inbound",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshot(t, func(ns *structs.NodeService) {
+					ns.Proxy.Config = map[string]any{"protocol": "http"}
+					ns.Proxy.EnvoyExtensions = makeHTTPHeadersMirrorEnvoyExtension("inbound", "db")
+				}, nil)
+			},
+		},
+		{
+			// Modify the headers of the outbound HTTP routes and mirror their requests to the db upstream.
+			name: "http-headers-mirror-outbound",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				// The router of the chain makes Consul discover the routes of db through RDS.
+				return proxycfg.TestConfigSnapshotDiscoveryChain(t, "chain-and-router", false, func(ns *structs.NodeService) {
+					ns.Proxy.Config["protocol"] = "http"
+					ns.Proxy.EnvoyExtensions = makeHTTPHeadersMirrorEnvoyExtension("outbound", "db")
+				}, nil)
+			},
+		},
+		{
+			// Insert an HTTP local rate limit filter with a token bucket for the listener and an override for the default route.
+			name: "rate-limit-http-local",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshot(t, func(ns *structs.NodeService) {
+					ns.Proxy.Config = map[string]any{"protocol": "http"}
+					ns.Proxy.EnvoyExtensions = makeRateLimitEnvoyExtension("local")
+				}, nil)
+			},
+		},
+		{
+			// Insert an HTTP global rate limit filter that calls a rate limit service on the local host.
+			name: "rate-limit-http-global-local-service",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshot(t, func(ns *structs.NodeService) {
+					ns.Proxy.Config = map[string]any{"protocol": "http"}
+					ns.Proxy.EnvoyExtensions = makeRateLimitEnvoyExtension(
+						"global",
+						"descriptors=full",
+					)
+				}, nil)
+			},
+		},
+		{
+			// Insert an HTTP global rate limit filter that calls an upstream rate limit service and denies requests on failure.
+			name: "rate-limit-http-global-upstream-service",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshot(t, func(ns *structs.NodeService) {
+					ns.Proxy.Config = map[string]any{"protocol": "http"}
+					ns.Proxy.EnvoyExtensions = makeRateLimitEnvoyExtension(
+						"global",
+						"required=true",
+						"dest=upstream",
+					)
+				}, nil)
+			},
+		},
+		{
+			// Insert a TCP local rate limit filter before the TCP proxy.
+			name: "rate-limit-tcp-local",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshot(t, func(ns *structs.NodeService) {
+					ns.Proxy.Config = map[string]any{"protocol": "tcp"}
+					ns.Proxy.EnvoyExtensions = makeRateLimitEnvoyExtension("local")
+				}, nil)
+			},
+		},
+		{
+			// Insert a TCP global rate limit filter that calls an upstream rate limit service.
+			name: "rate-limit-tcp-global-upstream-service",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshot(t, func(ns *structs.NodeService) {
+					ns.Proxy.Config = map[string]any{"protocol": "tcp"}
+					ns.Proxy.EnvoyExtensions = makeRateLimitEnvoyExtension(
+						"global",
+						"dest=upstream",
+					)
+				}, nil)
+			},
+		},
+		{
+			// Insert local rate limit filters into the outbound listeners of a TCP service. The HTTP filter and
+			// route overrides are used for the db upstream, which has an HTTP router, and the network filter for
+			// the TCP geo-cache upstream.
+			name: "rate-limit-outbound-mixed-upstreams",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshotDiscoveryChain(t, "chain-and-router", false, func(ns *structs.NodeService) {
+					ns.Proxy.EnvoyExtensions = makeRateLimitEnvoyExtension("local", "listener=outbound")
+				}, nil)
+			},
+		},
 		{
 			// Insert an OpenTelemetry access logging extension
 			name: "otel-access-logging-http",
@@ -162,3 +162,133 @@ func makeWasmEnvoyExtension(proto, listener, locale string) []structs.EnvoyExten
 		},
 	}
 }
+
+func makeRateLimitEnvoyExtension(mode string, opts ...string) []structs.EnvoyExtension {
+	required := false
+	listenerType := "inbound"
+	target := map[string]any{"URI": "127.0.0.1:8081"}
+	entries := []map[string]any{
+		{"Key": "service", "Value": "web"},
+	}
+
+	for _, opt := range opts {
+		if k, v, valid := strings.Cut(opt, "="); valid {
+			switch k {
+			case "required":
+				required = true
+			case "listener":
+				listenerType = v
+			case "dest":
+				if v == "upstream" {
+					target = map[string]any{
+						"Service": map[string]any{"Name": "db"},
+						"Timeout": "100ms",
+					}
+				}
+			case "descriptors":
+				if v == "full" {
+					entries = append(entries,
+						map[string]any{"Key": "tenant", "RequestHeader": "x-tenant"},
+						map[string]any{"RemoteAddress": true},
+					)
+				}
+			}
+		}
+	}
+
+	configMap := map[string]any{
+		"Local": map[string]any{
+			"TokenBucket": map[string]any{
+				"MaxTokens":     100,
+				"TokensPerFill": 10,
+				"FillInterval":  "1s",
+			},
+			"Routes": []map[string]any{
+				{
+					"PathPrefix": "/",
+					"TokenBucket": map[string]any{
+						"MaxTokens":    5,
+						"FillInterval": "10s",
+					},
+				},
+			},
+		},
+	}
+	if mode == "global" {
+		configMap = map[string]any{
+			"StatPrefix": "web_rate_limit",
+			"Global": map[string]any{
+				"Domain": "consul",
+				"GrpcService": map[string]any{
+					"Target": target,
+				},
+				"Descriptors": []map[string]any{
+					{"Entries": entries},
+				},
+			},
+		}
+	}
+
+	return []structs.EnvoyExtension{
+		{
+			Name:     api.BuiltinRateLimitExtension,
+			Required: required,
+			Arguments: map[string]any{
+				"ListenerType": listenerType,
+				"Config":       configMap,
+			},
+		},
+	}
+}
+
+func makeHTTPHeadersMirrorEnvoyExtension(listener, mirrorService string) []structs.EnvoyExtension {
+	// Note: only use one entry in the Add and Set maps to keep the examples short, the
+	// extension sorts the headers so the output is deterministic.
+	return []structs.EnvoyExtension{
+		{
+			Name: api.BuiltinHTTPHeadersMirrorExtension,
+			Arguments: map[string]any{
+				"ProxyType":    "connect-proxy",
+				"ListenerType": listener,
+				"Config": map[string]any{
+					"RequestHeaders": map[string]any{
+						"Add":    map[string]any{"x-mesh-listener": listener},
+						"Set":    map[string]any{"x-forwarded-proto": "https"},
+						"Remove": []string{"x-debug"},
+					},
+					"ResponseHeaders": map[string]any{
+						"Remove": []string{"server"},
+					},
+					"Mirror": map[string]any{
+						"Service":      map[string]any{"Name": mirrorService},
+						"Percent":      12.5,
+						"TraceSampled": false,
+					},
+				},
+			},
+		},
+	}
+}
+
+func makeHTTPCompressionCacheEnvoyExtension(listener string) []structs.EnvoyExtension {
+	return []structs.EnvoyExtension{
+		{
+			Name: api.BuiltinHTTPCompressionCacheExtension,
+			Arguments: map[string]any{
+				"ProxyType":    "connect-proxy",
+				"ListenerType": listener,
+				"Config": map[string]any{
+					"Compression": map[string]any{
+						"Algorithms":       []string{"brotli", "gzip"},
+						"ContentTypes":     []string{"application/json", "text/html"},
+						"MinContentLength": 1024,
+					},
+					"Cache": map[string]any{
+						"MaxBodyBytes":       1048576,
+						"AllowedVaryHeaders": []string{"accept-language"},
+					},
+				},
+			},
+		},
+	}
+}
@@ -86,7 +86,7 @@ func (s *ResourceGenerator) mapDiscoChainTargets(cfgSnap *proxycfg.ConfigSnapsho
 
 	for _, tid := range tids {
 		target := chain.Targets[tid]
-		var sni, rootPEMs string
+		var sni, rootPEMs, crlPEM string
 		var spiffeIDs []string
 		targetUID := proxycfg.NewUpstreamIDFromTargetID(tid)
 		ti := targetInfo{TargetID: tid, PrioritizeByLocality: target.PrioritizeByLocality}
@@ -118,6 +118,10 @@ func (s *ResourceGenerator) mapDiscoChainTargets(cfgSnap *proxycfg.ConfigSnapsho
 		} else {
 			sni = target.SNI
 			rootPEMs = cfgSnap.RootPEMs()
+			if target.Datacenter == cfgSnap.Datacenter {
+				// The revocation list only covers the local datacenter.
+				crlPEM = cfgSnap.RevocationList()
+			}
 			spiffeIDs = []string{connect.SpiffeIDService{
 				Host:       cfgSnap.Roots.TrustDomain,
 				Namespace:  target.Namespace,
@@ -136,6 +140,9 @@ func (s *ResourceGenerator) mapDiscoChainTargets(cfgSnap *proxycfg.ConfigSnapsho
 		if err != nil {
 			return failoverTargets, fmt.Errorf("failed to inject SAN matcher rules for cluster %q: %v", sni, err)
 		}
+		if err := injectRevocationList(commonTLSContext, crlPEM); err != nil {
+			return failoverTargets, fmt.Errorf("failed to inject revocation list for cluster %q: %v", sni, err)
+		}
 
 		tlsContext := &envoy_tls_v3.UpstreamTlsContext{
 			CommonTlsContext: commonTLSContext,
@@ -1139,7 +1139,7 @@ func injectHTTPFilterOnFilterChains(
 // since TLS validation will be done against root certs for all peers
 // that might dial this proxy.
 func (s *ResourceGenerator) injectConnectTLSForPublicListener(cfgSnap *proxycfg.ConfigSnapshot, listener *envoy_listener_v3.Listener) error {
-	transportSocket, err := createDownstreamTransportSocketForConnectTLS(cfgSnap, cfgSnap.PeeringTrustBundles())
+	transportSocket, err := createDownstreamTransportSocketForConnectTLS(cfgSnap, cfgSnap.InboundTrustBundles())
 	if err != nil {
 		return err
 	}
@@ -1186,6 +1186,9 @@ func createDownstreamTransportSocketForConnectTLS(cfgSnap *proxycfg.ConfigSnapsh
 		// Configure alpn protocols on CommonTLSContext
 		tlsContext.AlpnProtocols = getAlpnProtocols(cfg.Protocol)
 	}
+	if err := injectRevocationList(tlsContext, cfgSnap.DownstreamRevocationList(peerBundles)); err != nil {
+		return nil, err
+	}
 
 	// Inject peering trust bundles if this service is exported to peered clusters.
 	if len(peerBundles) > 0 {
@@ -1782,6 +1785,9 @@ func (s *ResourceGenerator) makeFilterChainTerminatingGateway(cfgSnap *proxycfg.
 		),
 		RequireClientCertificate: &wrapperspb.BoolValue{Value: true},
 	}
+	if err := injectRevocationList(tlsContext.CommonTlsContext, cfgSnap.DownstreamRevocationList(tgtwyOpts.peerTrustBundles)); err != nil {
+		return nil, err
+	}
 	transportSocket, err := makeDownstreamTLSTransportSocket(tlsContext)
 	if err != nil {
 		return nil, err
@@ -145,19 +145,12 @@ func TestAllResourcesFromSnapshot(t *testing.T) {
 			},
 		},
 		{
-			name: "connect-proxy-exported-to-peers",
-			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
-				return proxycfg.TestConfigSnapshot(t, func(ns *structs.NodeService) {
-					// This test is only concerned about the SPIFFE cert validator config in the public listener
-					// so we empty out the upstreams to avoid generating unnecessary upstream listeners.
-					ns.Proxy.Upstreams = structs.Upstreams{}
-				}, []proxycfg.UpdateEvent{
-					{
-						CorrelationID: "peering-trust-bundles",
-						Result:        proxycfg.TestPeerTrustBundles(t),
-					},
-				})
-			},
+			name:   "connect-proxy-exported-to-peers",
+			create: testConfigSnapshotExportedToPeers,
+		},
+		{
+			name:   "connect-proxy-with-federated-trust-bundles",
+			create: testConfigSnapshotWithFederatedTrustBundles,
 		},
 		{
 			name: "transparent-proxy",
@@ -191,6 +184,7 @@ func TestAllResourcesFromSnapshot(t *testing.T) {
 		},
 	}
 	tests = append(tests, getConnectProxyTransparentProxyGoldenTestCases()...)
+	tests = append(tests, getRevocationListGoldenTestCases()...)
 	tests = append(tests, getMeshGatewayPeeringGoldenTestCases()...)
 	tests = append(tests, getTrafficControlPeeringGoldenTestCases(false)...)
 	tests = append(tests, getEnterpriseGoldenTestCases(t)...)
@@ -210,6 +204,99 @@ func TestAllResourcesFromSnapshot(t *testing.T) {
 	}
 }
 
+func testConfigSnapshotExportedToPeers(t testinf.T) *proxycfg.ConfigSnapshot {
+	return proxycfg.TestConfigSnapshot(t, func(ns *structs.NodeService) {
+		// This test is only concerned about the SPIFFE cert validator config in the public listener
+		// so we empty out the upstreams to avoid generating unnecessary upstream listeners.
+		ns.Proxy.Upstreams = structs.Upstreams{}
+	}, []proxycfg.UpdateEvent{
+		{
+			CorrelationID: "peering-trust-bundles",
+			Result:        proxycfg.TestPeerTrustBundles(t),
+		},
+	})
+}
+
+func testConfigSnapshotWithFederatedTrustBundles(t testinf.T) *proxycfg.ConfigSnapshot {
+	return proxycfg.TestConfigSnapshot(t, func(ns *structs.NodeService) {
+		// This test is only concerned about the SPIFFE cert validator config in the public listener
+		// so we empty out the upstreams to avoid generating unnecessary upstream listeners.
+		ns.Proxy.Upstreams = structs.Upstreams{}
+	}, []proxycfg.UpdateEvent{
+		{
+			CorrelationID: "federated-trust-bundles",
+			Result: &structs.IndexedConfigEntries{
+				Kind: structs.FederatedTrustBundle,
+				Entries: []structs.ConfigEntry{
+					&structs.FederatedTrustBundleConfigEntry{
+						Kind:        structs.FederatedTrustBundle,
+						Name:        "other-mesh",
+						TrustDomain: "other-mesh.example.com",
+						RootPEMs:    proxycfg.TestPeerTrustBundles(t).Bundles[0].RootPEMs,
+					},
+				},
+			},
+		},
+	})
+}
+
+// testRevocationList is a CRL signed by a throwaway CA that revokes the
+// certificate with the serial number 2.
+const testRevocationList = `-----BEGIN X509 CRL-----
+MIHkMIGKAgEBMAoGCCqGSM49BAMCMBQxEjAQBgNVBAMTCVRlc3QgQ0EgMhcNMTkw
+MzIyMDAwMDAwWhcNMjkwMzIyMDAwMDAwWjAUMBICAQIXDTE5MDMyMjAwMDAwMFqg
+LzAtMB8GA1UdIwQYMBaAFCY4uPiuata/9Ic0VDqx05dFCGvYMAoGA1UdFAQDAgEB
+MAoGCCqGSM49BAMCA0kAMEYCIQDYxQiJc6b8beUrZpe5ByzqigIf8p77xudUWi8B
+yM+G/gIhAPs6hyMvHnSyyLBJbI2b/5c3kMwQPDmaiAfOIudy3k/Y
+-----END X509 CRL-----
+`
+
+// getRevocationListGoldenTestCases covers where the revocation list of the
+// local datacenter is used: it is only injected to verify the certificates of
+// the peers that are signed in the local datacenter.
+func getRevocationListGoldenTestCases() []goldenTestCase {
+	setRevocationList := func(snap *proxycfg.ConfigSnapshot) {
+		snap.Roots.RevocationList = testRevocationList
+	}
+	return []goldenTestCase{
+		{
+			// Only the target in the local datacenter is verified with the
+			// revocation list, not the failover target in dc2.
+			name: "connect-proxy-with-failover-and-revocation-list",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshotDiscoveryChain(t, "failover-through-remote-gateway", false, nil, nil)
+			},
+			setup: setRevocationList,
+		},
+		{
+			// The downstreams may be signed by the other datacenters.
+			name: "connect-proxy-wan-federated-with-revocation-list",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshot(t, nil, nil)
+			},
+			setup: func(snap *proxycfg.ConfigSnapshot) {
+				setRevocationList(snap)
+				snap.Roots.WANFederated = true
+			},
+		},
+		{
+			name:   "connect-proxy-with-peered-upstreams-and-revocation-list",
+			create: proxycfg.TestConfigSnapshotPeering,
+			setup:  setRevocationList,
+		},
+		{
+			name:   "connect-proxy-exported-to-peers-with-revocation-list",
+			create: testConfigSnapshotExportedToPeers,
+			setup:  setRevocationList,
+		},
+		{
+			name:   "connect-proxy-with-federated-trust-bundles-and-revocation-list",
+			create: testConfigSnapshotWithFederatedTrustBundles,
+			setup:  setRevocationList,
+		},
+	}
+}
+
 func getConnectProxyTransparentProxyGoldenTestCases() []goldenTestCase {
 	return []goldenTestCase{
 		{
@@ -108,6 +108,10 @@ type Server struct {
 	ResourceMapMutateFn func(resourceMap *xdscommon.IndexedResources)
 
 	activeStreams *activeStreamCounters
+
+	// connectedProxies tracks the proxies with an open stream, see
+	// ProxiesOnInactiveRoots.
+	connectedProxies connectedProxies
 }
 
 // activeStreamCounters tracks various stream-related metrics.
@@ -55,6 +55,26 @@ type ACLToken struct {
 	CreateTime        time.Time     `json:",omitempty"`
 	Hash              []byte        `json:",omitempty"`
 
+	// RotationTime is the time the token's SecretID was last rotated.
+	RotationTime *time.Time `json:",omitempty"`
+
+	// PreviousSecretID is the SecretID the token had before it was last
+	// rotated. It remains valid until PreviousSecretExpirationTime.
+	PreviousSecretID             string     `json:",omitempty"`
+	PreviousSecretExpirationTime *time.Time `json:",omitempty"`
+
+	// LastUsedAt is the approximate time the token was last used in the
+	// datacenter it was read from.
+	LastUsedAt *time.Time `json:",omitempty"`
+
+	// Lineage records the binding rules and identity fields a token issued
+	// by logging in with an auth method was created from.
+	Lineage *ACLTokenLineage `json:",omitempty"`
+
+	// BreakGlass records why and by whom an emergency token was issued. It
+	// is nil for all other tokens.
+	BreakGlass *ACLTokenBreakGlass `json:",omitempty"`
+
 	// DEPRECATED (ACL-Legacy-Compat)
 	// Rules are an artifact of legacy tokens deprecated in Consul 1.4
 	Rules string `json:"-"`
@@ -72,6 +92,63 @@ type ACLToken struct {
 	AuthMethodNamespace string `json:",omitempty"`
 }
 
+// ACLTokenBreakGlass describes an emergency token granting global-management
+// access.
+type ACLTokenBreakGlass struct {
+	// Reason is the justification the requester gave for the token.
+	Reason string
+
+	// RequestedBy is the AccessorID of the token that requested it.
+	RequestedBy string
+
+	// ApprovedBy is the AccessorID of the token that approved the request.
+	ApprovedBy string
+}
+
+// ACLTokenBreakGlassRequest is used to request a break-glass token.
+type ACLTokenBreakGlassRequest struct {
+	// Reason is the justification for the token. It is required.
+	Reason string
+
+	// TTL is how long the token remains valid. It is required and may not
+	// exceed the maximum TTL configured on the servers.
+	TTL time.Duration
+
+	// ApproverToken is the SecretID of a token that is allowed to write
+	// ACLs and approves the request. It must be different from the token
+	// making the request.
+	ApproverToken string
+}
+
+// ACLTokenLineage records how a token issued by logging in with an auth
+// method was bound.
+type ACLTokenLineage struct {
+	// BindingRuleIDs are the IDs of the binding rules that matched.
+	BindingRuleIDs []string `json:",omitempty"`
+
+	// SelectableFields is a snapshot of the identity's selectable fields.
+	// Fields that the binding rules' selectors did not refer to have their
+	// values redacted.
+	SelectableFields map[string]string `json:",omitempty"`
+}
+
+// ACLTokenLineageInfo shows how a token issued by logging in with an auth
+// method got its permissions, from the auth method through the binding rules
+// to the roles, policies and identities of the token.
+type ACLTokenLineageInfo struct {
+	ACLTokenExpanded
+
+	// IssuedBy is the auth method that issued the token. It is nil if the
+	// auth method has since been deleted.
+	IssuedBy *ACLAuthMethodListEntry `json:",omitempty"`
+
+	BindingRules []*ACLBindingRule
+
+	// MissingBindingRuleIDs are the binding rules that matched when the
+	// token was issued and have since been deleted.
+	MissingBindingRuleIDs []string `json:",omitempty"`
+}
+
 type ACLTokenExpanded struct {
 	ExpandedPolicies []ACLPolicy
 	ExpandedRoles    []ACLRole
@@ -101,6 +178,9 @@ type ACLTokenListEntry struct {
 	AuthMethod        string     `json:",omitempty"`
 	ExpirationTime    *time.Time `json:",omitempty"`
 	CreateTime        time.Time
+	RotationTime      *time.Time          `json:",omitempty"`
+	LastUsedAt        *time.Time          `json:",omitempty"`
+	BreakGlass        *ACLTokenBreakGlass `json:",omitempty"`
 	Hash              []byte
 	Legacy            bool `json:"-"` // DEPRECATED
 
@@ -140,6 +220,8 @@ type ACLReplicationStatus struct {
 	LastSuccess          time.Time
 	LastError            time.Time
 	LastErrorMessage     string
+
+	ReplicatedTemplatedPolicyIndex uint64
 }
 
 // ACLServiceIdentity represents a high-level grant of all necessary privileges
@@ -170,10 +252,33 @@ type ACLTemplatedPolicy struct {
 
 type ACLTemplatedPolicyResponse struct {
 	TemplateName string
+	Description  string `json:",omitempty"`
 	Schema       string
 	Template     string
 }
 
+// ACLTemplatedPolicyDefinition represents a user-defined templated policy.
+// Once created it can be referenced by name from tokens, roles and binding
+// rules just like the builtin templated policies.
+type ACLTemplatedPolicyDefinition struct {
+	ID          string
+	Name        string
+	Description string
+
+	// Schema is the JSON schema used to validate the template variables.
+	// When empty the template does not take any variables.
+	Schema string
+
+	// Template is used to render the rules of the synthetic policy. The
+	// template variables are available as {{.Name}}, along with
+	// {{.Namespace}} and {{.Partition}}.
+	Template string
+
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
 type ACLTemplatedPolicyVariables struct {
 	Name string
 }
@@ -315,6 +420,9 @@ type ACLTokenFilterOptions struct {
 	Policy      string `json:",omitempty"`
 	Role        string `json:",omitempty"`
 	ServiceName string `json:",omitempty"`
+
+	// BreakGlass only lists break-glass tokens.
+	BreakGlass bool `json:",omitempty"`
 }
 
 func (m *ACLAuthMethod) MarshalJSON() ([]byte, error) {
@@ -821,6 +929,62 @@ func (a *ACL) TokenClone(accessorID string, description string, q *WriteOptions)
 	return &out, wm, nil
 }
 
+// TokenRotate issues a new SecretID for the token with the given accessorID.
+// The previous SecretID remains valid for gracePeriod, a zero grace period
+// invalidates it immediately.
+func (a *ACL) TokenRotate(accessorID string, gracePeriod time.Duration, q *WriteOptions) (*ACLToken, *WriteMeta, error) {
+	if accessorID == "" {
+		return nil, nil, fmt.Errorf("Must specify a token AccessorID for Token Rotation")
+	}
+
+	r := a.c.newRequest("PUT", "/v1/acl/token/"+accessorID+"/rotate")
+	r.setWriteOptions(q)
+	r.obj = struct{ GracePeriod string }{gracePeriod.String()}
+	rtt, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+	wm := &WriteMeta{RequestTime: rtt}
+	var out ACLToken
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+
+	return &out, wm, nil
+}
+
+// TokenBreakGlass issues a local token granting global-management access that
+// expires after the requested TTL. The token in q identifies the requester, the
+// request must be approved by a different token that is allowed to write ACLs.
+func (a *ACL) TokenBreakGlass(req *ACLTokenBreakGlassRequest, q *WriteOptions) (*ACLToken, *WriteMeta, error) {
+	r := a.c.newRequest("PUT", "/v1/acl/token/break-glass")
+	r.setWriteOptions(q)
+	r.obj = struct {
+		Reason        string
+		TTL           string
+		ApproverToken string
+	}{req.Reason, req.TTL.String(), req.ApproverToken}
+	rtt, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+	wm := &WriteMeta{RequestTime: rtt}
+	var out ACLToken
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+
+	return &out, wm, nil
+}
+
 // TokenDelete removes a single ACL token. The accessorID parameter must be a valid
 // Accessor ID of an existing token.
 func (a *ACL) TokenDelete(accessorID string, q *WriteOptions) (*WriteMeta, error) {
@@ -890,6 +1054,32 @@ func (a *ACL) TokenReadExpanded(accessorID string, q *QueryOptions) (*ACLTokenEx
 	return &out, qm, nil
 }
 
+// TokenLineage retrieves the lineage of a token issued by logging in with an
+// auth method. The accessorID parameter must be a valid Accessor ID of an
+// existing token.
+func (a *ACL) TokenLineage(accessorID string, q *QueryOptions) (*ACLTokenLineageInfo, *QueryMeta, error) {
+	r := a.c.newRequest("GET", "/v1/acl/token/"+accessorID+"/lineage")
+	r.setQueryOptions(q)
+	rtt, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out ACLTokenLineageInfo
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+
+	return &out, qm, nil
+}
+
 // TokenReadSelf retrieves the full token details of the token currently
 // assigned to the API Client. In this manner its possible to read a token
 // by its Secret ID.
@@ -958,6 +1148,9 @@ func (a *ACL) TokenListFiltered(t ACLTokenFilterOptions, q *QueryOptions) ([]*AC
 	if t.ServiceName != "" {
 		r.params.Set("servicename", t.ServiceName)
 	}
+	if t.BreakGlass {
+		r.params.Set("breakglass", "")
+	}
 
 	rtt, resp, err := a.c.doRequest(r)
 	if err != nil {
@@ -1715,6 +1908,79 @@ func (a *ACL) TemplatedPolicyList(q *QueryOptions) (map[string]ACLTemplatedPolic
 	return entries, qm, nil
 }
 
+// TemplatedPolicyCreate will create a new user-defined templated policy. It is
+// not allowed for the templated policy parameter's ID field to be set as this
+// will be generated by Consul while processing the request.
+func (a *ACL) TemplatedPolicyCreate(tp *ACLTemplatedPolicyDefinition, q *WriteOptions) (*ACLTemplatedPolicyDefinition, *WriteMeta, error) {
+	if tp.ID != "" {
+		return nil, nil, fmt.Errorf("Cannot specify an ID in Templated Policy Creation")
+	}
+
+	r := a.c.newRequest("PUT", "/v1/acl/templated-policy")
+	r.setWriteOptions(q)
+	r.obj = tp
+	rtt, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+	wm := &WriteMeta{RequestTime: rtt}
+	var out ACLTemplatedPolicyDefinition
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+
+	return &out, wm, nil
+}
+
+// TemplatedPolicyUpdate updates the user-defined templated policy with the
+// given name. The name of a templated policy cannot be changed.
+func (a *ACL) TemplatedPolicyUpdate(tp *ACLTemplatedPolicyDefinition, q *WriteOptions) (*ACLTemplatedPolicyDefinition, *WriteMeta, error) {
+	if tp.Name == "" {
+		return nil, nil, fmt.Errorf("Must specify a Name in Templated Policy Update")
+	}
+
+	r := a.c.newRequest("PUT", "/v1/acl/templated-policy/name/"+tp.Name)
+	r.setWriteOptions(q)
+	r.obj = tp
+	rtt, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+	wm := &WriteMeta{RequestTime: rtt}
+	var out ACLTemplatedPolicyDefinition
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+
+	return &out, wm, nil
+}
+
+// TemplatedPolicyDelete deletes the user-defined templated policy with the
+// given name. Builtin templated policies cannot be deleted.
+func (a *ACL) TemplatedPolicyDelete(templateName string, q *WriteOptions) (*WriteMeta, error) {
+	r := a.c.newRequest("DELETE", "/v1/acl/templated-policy/name/"+templateName)
+	r.setWriteOptions(q)
+	rtt, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, err
+	}
+
+	wm := &WriteMeta{RequestTime: rtt}
+	return wm, nil
+}
+
 // TemplatedPolicyPreview is used to preview the policy rendered by the templated policy.
 func (a *ACL) TemplatedPolicyPreview(tp *ACLTemplatedPolicy, q *WriteOptions) (*ACLPolicy, *WriteMeta, error) {
 	r := a.c.newRequest("POST", "/v1/acl/templated-policy/preview/"+tp.TemplateName)
@@ -1736,3 +2002,88 @@ func (a *ACL) TemplatedPolicyPreview(tp *ACLTemplatedPolicy, q *WriteOptions) (*
 	}
 	return &out, wm, nil
 }
+
+const (
+	// ACLDecisionSourcePolicy means a rule in one of the token's policies,
+	// including those synthesized for its identities and templated policies,
+	// produced the decision.
+	ACLDecisionSourcePolicy = "policy"
+
+	// ACLDecisionSourceIdentityDefaults means none of the token's policies
+	// applied and the defaults for the token's namespace or partition
+	// produced the decision.
+	ACLDecisionSourceIdentityDefaults = "identity-defaults"
+
+	// ACLDecisionSourceDefaultPolicy means nothing else applied and the
+	// acl.default_policy produced the decision.
+	ACLDecisionSourceDefaultPolicy = "default-policy"
+
+	// ACLDecisionSourceLocalToken means the token is managed by the server
+	// itself rather than backed by policies.
+	ACLDecisionSourceLocalToken = "local-token"
+)
+
+// ACLAuthorizationRequest asks whether a token has the given access to a
+// resource, such as write access to the service "web".
+type ACLAuthorizationRequest struct {
+	Resource  string
+	Segment   string `json:",omitempty"`
+	Access    string
+	Namespace string `json:",omitempty"`
+	Partition string `json:",omitempty"`
+}
+
+// ACLAuthorizationExplanation is the decision for an ACLAuthorizationRequest
+// along with the policy rule or default that produced it.
+type ACLAuthorizationExplanation struct {
+	ACLAuthorizationRequest
+	Allow bool
+
+	// DecidedBy is one of the ACLDecisionSource constants.
+	DecidedBy string
+
+	// PolicyID, PolicyName and PolicyDescription identify the policy holding
+	// the rule that produced the decision when DecidedBy is "policy".
+	PolicyID          string `json:",omitempty"`
+	PolicyName        string `json:",omitempty"`
+	PolicyDescription string `json:",omitempty"`
+
+	// Rule is the rule that produced the decision when DecidedBy is "policy".
+	Rule *ACLExplainedRule `json:",omitempty"`
+}
+
+// ACLExplainedRule is a single rule of an ACL policy.
+type ACLExplainedRule struct {
+	Resource   string
+	Segment    string `json:",omitempty"`
+	Prefix     bool   `json:",omitempty"`
+	Glob       bool   `json:",omitempty"`
+	Policy     string
+	Intentions string `json:",omitempty"`
+}
+
+// AuthorizeExplain checks the requests against the token used for the query
+// and explains which policy rule, or default, produced each decision.
+func (a *ACL) AuthorizeExplain(requests []*ACLAuthorizationRequest, q *QueryOptions) ([]*ACLAuthorizationExplanation, *QueryMeta, error) {
+	r := a.c.newRequest("POST", "/v1/acl/authorize/explain")
+	r.setQueryOptions(q)
+	r.obj = requests
+
+	rtt, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out []*ACLAuthorizationExplanation
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return out, qm, nil
+}
@@ -380,6 +380,12 @@ type AgentToken struct {
 	Token string
 }
 
+// AgentTokenRenewResult lists the agent's tokens that were replaced with a
+// rotated SecretID by RenewACLTokens.
+type AgentTokenRenewResult struct {
+	Renewed []string
+}
+
 // Metrics info is used to store different types of metric values from the agent.
 type MetricsInfo struct {
 	Timestamp string
@@ -421,6 +427,18 @@ type AgentAuthorizeParams struct {
 	Target           string
 	ClientCertURI    string
 	ClientCertSerial string
+
+	// HTTP is the HTTP request to authorize, if any. When it is set, the L7
+	// permissions of the matching intention are evaluated against it.
+	HTTP *AgentAuthorizeHTTPParams `json:",omitempty"`
+}
+
+// AgentAuthorizeHTTPParams describes the HTTP request evaluated against the
+// L7 permissions of an intention.
+type AgentAuthorizeHTTPParams struct {
+	Method string
+	Path   string
+	Header map[string][]string `json:",omitempty"`
 }
 
 // AgentAuthorize is the response structure for Connect authorization.
@@ -1397,6 +1415,27 @@ func (a *Agent) UpdateDNSToken(token string, q *WriteOptions) (*WriteMeta, error
 	return a.updateToken("dns", token, q)
 }
 
+// RenewACLTokens asks the agent to check whether any of its ACL tokens have been
+// rotated and, if so, to start using their new SecretIDs.
+func (a *Agent) RenewACLTokens(q *WriteOptions) (*AgentTokenRenewResult, *WriteMeta, error) {
+	r := a.c.newRequest("PUT", "/v1/agent/token/renew")
+	r.setWriteOptions(q)
+	rtt, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+	wm := &WriteMeta{RequestTime: rtt}
+	var out AgentTokenRenewResult
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return &out, wm, nil
+}
+
 // updateToken can be used to update one of an agent's ACL tokens after the agent has
 // started. The tokens are may not be persisted, so will need to be updated again if
 // the agent is restarted unless the agent is configured to persist them.
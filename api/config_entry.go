@@ -36,15 +36,20 @@ const (
 	InlineCertificate string = "inline-certificate"
 	HTTPRoute         string = "http-route"
 	JWTProvider       string = "jwt-provider"
+
+	FederatedTrustBundle string = "federated-trust-bundle"
 )
 
 const (
-	BuiltinAWSLambdaExtension         string = "builtin/aws/lambda"
-	BuiltinExtAuthzExtension          string = "builtin/ext-authz"
-	BuiltinLuaExtension               string = "builtin/lua"
-	BuiltinOTELAccessLoggingExtension string = "builtin/otel-access-logging"
-	BuiltinPropertyOverrideExtension  string = "builtin/property-override"
-	BuiltinWasmExtension              string = "builtin/wasm"
+	BuiltinAWSLambdaExtension            string = "builtin/aws/lambda"
+	BuiltinExtAuthzExtension             string = "builtin/ext-authz"
+	BuiltinHTTPCompressionCacheExtension string = "builtin/http-compression-cache"
+	BuiltinHTTPHeadersMirrorExtension    string = "builtin/http-headers-mirror"
+	BuiltinLuaExtension                  string = "builtin/lua"
+	BuiltinOTELAccessLoggingExtension    string = "builtin/otel-access-logging"
+	BuiltinPropertyOverrideExtension     string = "builtin/property-override"
+	BuiltinRateLimitExtension            string = "builtin/rate-limit"
+	BuiltinWasmExtension                 string = "builtin/wasm"
 	// BuiltinValidateExtension should not be exposed directly or accepted as a valid configured
 	// extension type, as it is only used indirectly via troubleshooting tools. It is included here
 	// for common reference alongside other builtin extensions.
@@ -376,11 +381,61 @@ type ServiceConfigEntry struct {
 	BalanceInboundConnections string                  `json:",omitempty" alias:"balance_inbound_connections"`
 	RateLimits                *RateLimits             `json:",omitempty" alias:"rate_limits"`
 	EnvoyExtensions           []EnvoyExtension        `json:",omitempty" alias:"envoy_extensions"`
+	LeafCert                  *LeafCertConfig         `json:",omitempty" alias:"leaf_cert"`
 	Meta                      map[string]string       `json:",omitempty"`
 	CreateIndex               uint64
 	ModifyIndex               uint64
 }
 
+// LeafCertConfig overrides the settings of the CA configuration for the leaf
+// certificates of a service.
+type LeafCertConfig struct {
+	// TTL overrides the LeafCertTTL of the CA configuration, within its
+	// MinLeafCertTTL and MaxLeafCertTTL.
+	TTL time.Duration `json:",omitempty"`
+
+	// PrivateKeyType and PrivateKeyBits set the type and length of the private
+	// keys generated for the leaf certificates.
+	PrivateKeyType string `json:",omitempty" alias:"private_key_type"`
+	PrivateKeyBits int    `json:",omitempty" alias:"private_key_bits"`
+}
+
+func (c *LeafCertConfig) MarshalJSON() ([]byte, error) {
+	type Alias LeafCertConfig
+	exported := &struct {
+		TTL string `json:",omitempty"`
+		*Alias
+	}{
+		TTL:   c.TTL.String(),
+		Alias: (*Alias)(c),
+	}
+	if c.TTL == 0 {
+		exported.TTL = ""
+	}
+
+	return json.Marshal(exported)
+}
+
+func (c *LeafCertConfig) UnmarshalJSON(data []byte) error {
+	type Alias LeafCertConfig
+	aux := &struct {
+		TTL string
+		*Alias
+	}{
+		Alias: (*Alias)(c),
+	}
+	var err error
+	if err = json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.TTL != "" {
+		if c.TTL, err = time.ParseDuration(aux.TTL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *ServiceConfigEntry) GetKind() string            { return s.Kind }
 func (s *ServiceConfigEntry) GetName() string            { return s.Name }
 func (s *ServiceConfigEntry) GetPartition() string       { return s.Partition }
@@ -454,6 +509,8 @@ func makeConfigEntry(kind, name string) (ConfigEntry, error) {
 		return &RateLimitIPConfigEntry{Kind: kind, Name: name}, nil
 	case JWTProvider:
 		return &JWTProviderConfigEntry{Kind: kind, Name: name}, nil
+	case FederatedTrustBundle:
+		return &FederatedTrustBundleConfigEntry{Kind: kind, Name: name}, nil
 	default:
 		return nil, fmt.Errorf("invalid config entry kind: %s", kind)
 	}
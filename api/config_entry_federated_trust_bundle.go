@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+// FederatedTrustBundleConfigEntry imports the trust bundle of a foreign
+// SPIFFE trust domain, such as another service mesh, so that the proxies
+// accept the certificates issued in that trust domain.
+type FederatedTrustBundleConfigEntry struct {
+	// Kind of the config entry. This should be set to api.FederatedTrustBundle.
+	Kind string
+
+	// Name identifies the imported bundle.
+	Name string
+
+	// TrustDomain is the SPIFFE trust domain the bundle is for.
+	TrustDomain string `alias:"trust_domain"`
+
+	// RootPEMs are the PEM-encoded X.509 authorities of the trust domain.
+	RootPEMs []string `alias:"root_pems"`
+
+	Meta map[string]string `json:",omitempty"`
+
+	// CreateIndex is the Raft index this entry was created at. This is a
+	// read-only field.
+	CreateIndex uint64
+
+	// ModifyIndex is used for the Check-And-Set operations and can also be fed
+	// back into the WaitIndex of the QueryOptions in order to perform blocking
+	// queries.
+	ModifyIndex uint64
+
+	// Partition is the partition the config entry is associated with.
+	// Partitioning is a Consul Enterprise feature.
+	Partition string `json:",omitempty"`
+
+	// Namespace is the namespace the config entry is associated with.
+	// Namespacing is a Consul Enterprise feature.
+	Namespace string `json:",omitempty"`
+}
+
+func (e *FederatedTrustBundleConfigEntry) GetKind() string            { return FederatedTrustBundle }
+func (e *FederatedTrustBundleConfigEntry) GetName() string            { return e.Name }
+func (e *FederatedTrustBundleConfigEntry) GetPartition() string       { return e.Partition }
+func (e *FederatedTrustBundleConfigEntry) GetNamespace() string       { return e.Namespace }
+func (e *FederatedTrustBundleConfigEntry) GetMeta() map[string]string { return e.Meta }
+func (e *FederatedTrustBundleConfigEntry) GetCreateIndex() uint64     { return e.CreateIndex }
+func (e *FederatedTrustBundleConfigEntry) GetModifyIndex() uint64     { return e.ModifyIndex }
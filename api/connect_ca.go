@@ -4,7 +4,9 @@
 package api
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/mitchellh/mapstructure"
@@ -41,6 +43,8 @@ type CAConfig struct {
 // CommonCAProviderConfig is the common options available to all CA providers.
 type CommonCAProviderConfig struct {
 	LeafCertTTL      time.Duration
+	MinLeafCertTTL   time.Duration
+	MaxLeafCertTTL   time.Duration
 	RootCertTTL      time.Duration
 	SkipValidate     bool
 	CSRMaxPerSecond  float32
@@ -129,10 +133,118 @@ type LeafCert struct {
 	ValidAfter  time.Time
 	ValidBefore time.Time
 
+	// Node is the name of the node that requested the certificate, if known.
+	Node string `json:",omitempty"`
+
+	// RevokedAt is the time the certificate was revoked, and RevocationReason
+	// the reason given when it was. They are only set for revoked
+	// certificates.
+	RevokedAt        *time.Time `json:",omitempty"`
+	RevocationReason string     `json:",omitempty"`
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
+// CAStatus reports the health of the CA of a datacenter and the inventory of
+// the leaf certificates it issued.
+type CAStatus struct {
+	// Provider is the CA provider in use.
+	Provider string
+
+	// State is the state of the CA on the leader, such as INITIALIZED or
+	// RENEWING while the intermediate is being renewed.
+	State string
+
+	// TrustDomain is the SPIFFE trust domain of the datacenter.
+	TrustDomain string
+
+	// ActiveRootID is the ID of the root that signs the new leaf
+	// certificates.
+	ActiveRootID string
+
+	// RotationInProgress is true while the roots rotated out by a root
+	// rotation are still trusted, until they are pruned.
+	RotationInProgress bool
+
+	// IntermediatePending is true when a CSR was generated for the
+	// intermediate of an offline root and no intermediate was set for it
+	// yet.
+	IntermediatePending bool
+
+	// Roots are the trusted roots, the active one first.
+	Roots []*CARootStatus
+
+	// LocalProxiesOnInactiveRoots are the proxies connected over xDS to the
+	// agent that served the request whose leaf certificate is signed by a
+	// root that is no longer active. The proxies connected to other agents
+	// are not included.
+	LocalProxiesOnInactiveRoots []*CAStatusProxy `json:",omitempty"`
+}
+
+// CARootStatus reports the expiry of a trusted root and the number of leaf
+// certificates it signed.
+type CARootStatus struct {
+	ID     string
+	Name   string
+	Active bool
+
+	// NotAfter is the expiry of the root certificate.
+	NotAfter time.Time
+
+	// IntermediateNotAfter is the expiry of the intermediate that signs the
+	// leaf certificates, if the root uses one.
+	IntermediateNotAfter *time.Time `json:",omitempty"`
+
+	// RotatedOutAt is the time the root stopped being the active root.
+	RotatedOutAt *time.Time `json:",omitempty"`
+
+	// LeafCerts is the number of unexpired leaf certificates signed by the
+	// root that were not revoked.
+	LeafCerts int
+}
+
+// CAStatusProxy is a proxy connected over xDS whose leaf certificate is
+// signed by a root that is no longer active.
+type CAStatusProxy struct {
+	ProxyID CAStatusProxyID
+	Kind    ServiceKind `json:",omitempty"`
+	Node    string      `json:",omitempty"`
+
+	// LeafSerialNumber and LeafRootID identify the leaf certificate of the
+	// proxy and the root that signed it.
+	LeafSerialNumber string
+	LeafRootID       string
+	LeafValidBefore  time.Time
+}
+
+// CAStatusProxyID identifies a proxy service instance.
+type CAStatusProxyID struct {
+	ID        string
+	Namespace string `json:",omitempty"`
+	Partition string `json:",omitempty"`
+}
+
+// CARevokeRequest selects the leaf certificates to revoke. The certificates
+// must match all of the given criteria, and at least one is required.
+type CARevokeRequest struct {
+	// SerialNumber is the serial number of the certificate to revoke, encoded
+	// in hex. The colons are optional.
+	SerialNumber string `json:",omitempty"`
+
+	// Service revokes the certificates of the service in Namespace and
+	// Partition.
+	Service   string `json:",omitempty"`
+	Namespace string `json:",omitempty"`
+	Partition string `json:",omitempty"`
+
+	// Node revokes the certificates requested by the node.
+	Node string `json:",omitempty"`
+
+	// Reason is a free-form description of why the certificates are revoked.
+	Reason string `json:",omitempty"`
+}
+
 // CARoots queries the list of available roots.
 func (h *Connect) CARoots(q *QueryOptions) (*CARootList, *QueryMeta, error) {
 	r := h.c.newRequest("GET", "/v1/connect/ca/roots")
@@ -199,3 +311,130 @@ func (h *Connect) CASetConfig(conf *CAConfig, q *WriteOptions) (*WriteMeta, erro
 	wm.RequestTime = rtt
 	return wm, nil
 }
+
+// CAIntermediateCSR returns the CSR for the next intermediate of a CA provider
+// whose root is kept offline. The same CSR is returned until an intermediate
+// signed for it is set with CASetIntermediate.
+func (h *Connect) CAIntermediateCSR(q *WriteOptions) (string, *WriteMeta, error) {
+	r := h.c.newRequest("PUT", "/v1/connect/ca/intermediate-csr")
+	r.setWriteOptions(q)
+	rtt, resp, err := h.c.doRequest(r)
+	if err != nil {
+		return "", nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return "", nil, err
+	}
+
+	wm := &WriteMeta{}
+	wm.RequestTime = rtt
+
+	var out struct{ CSR string }
+	if err := decodeBody(resp, &out); err != nil {
+		return "", nil, err
+	}
+	return out.CSR, wm, nil
+}
+
+// CASetIntermediate sets the intermediate signed by the offline root of a CA
+// provider whose root is kept offline. The PEM may contain the certificates
+// needed to chain the intermediate back to the root after the intermediate.
+func (h *Connect) CASetIntermediate(certPEM string, q *WriteOptions) (*WriteMeta, error) {
+	r := h.c.newRequest("PUT", "/v1/connect/ca/intermediate")
+	r.setWriteOptions(q)
+	r.obj = struct{ Certificate string }{Certificate: certPEM}
+	rtt, resp, err := h.c.doRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, err
+	}
+
+	wm := &WriteMeta{}
+	wm.RequestTime = rtt
+	return wm, nil
+}
+
+// CARevoke revokes the unexpired leaf certificates issued in the datacenter
+// that match the request, and returns the certificates that were revoked.
+func (h *Connect) CARevoke(req *CARevokeRequest, q *WriteOptions) ([]*LeafCert, *WriteMeta, error) {
+	r := h.c.newRequest("PUT", "/v1/connect/ca/revoke")
+	r.setWriteOptions(q)
+	r.obj = req
+	rtt, resp, err := h.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+
+	wm := &WriteMeta{}
+	wm.RequestTime = rtt
+
+	var out []*LeafCert
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return out, wm, nil
+}
+
+// CAStatus returns the health of the CA and the number of outstanding leaf
+// certificates signed by each root, along with the proxies connected to the
+// queried agent that are still using a leaf signed by an inactive root.
+func (h *Connect) CAStatus(q *QueryOptions) (*CAStatus, *QueryMeta, error) {
+	r := h.c.newRequest("GET", "/v1/connect/ca/status")
+	r.setQueryOptions(q)
+	rtt, resp, err := h.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out CAStatus
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return &out, qm, nil
+}
+
+// CARevocationList returns the PEM-encoded revocation lists of the leaf
+// certificates revoked in the datacenter, or an empty string if none is.
+func (h *Connect) CARevocationList(q *QueryOptions) (string, *QueryMeta, error) {
+	r := h.c.newRequest("GET", "/v1/connect/ca/crl")
+	r.setQueryOptions(q)
+	rtt, resp, err := h.c.doRequest(r)
+	if err != nil {
+		return "", nil, err
+	}
+	defer closeResponseBody(resp)
+	found, resp, err := requireNotFoundOrOK(resp)
+	if err != nil {
+		return "", nil, err
+	}
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	if !found {
+		return "", qm, nil
+	}
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, resp.Body); err != nil {
+		return "", nil, fmt.Errorf("Failed to read response: %v", err)
+	}
+	return buf.String(), qm, nil
+}
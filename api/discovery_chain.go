@@ -181,6 +181,7 @@ type DiscoverySplit struct {
 type DiscoveryResolver struct {
 	Default        bool
 	ConnectTimeout time.Duration
+	RequestTimeout time.Duration
 	Target         string
 	Failover       *DiscoveryFailover
 }
@@ -236,6 +237,7 @@ type DiscoveryTarget struct {
 	Service       string
 	ServiceSubset string
 	Namespace     string
+	Partition     string
 	Datacenter    string
 
 	MeshGateway    MeshGatewayConfig
@@ -53,6 +53,7 @@ func TestAPI_DiscoveryChain_Get(t *testing.T) {
 						ID:             "web.default.default.dc1",
 						Service:        "web",
 						Namespace:      "default",
+						Partition:      "default",
 						Datacenter:     "dc1",
 						ConnectTimeout: 5 * time.Second,
 						SNI:            "web.default.dc1.internal." + testClusterID + ".consul",
@@ -95,6 +96,7 @@ func TestAPI_DiscoveryChain_Get(t *testing.T) {
 						ID:             "web.default.default.dc2",
 						Service:        "web",
 						Namespace:      "default",
+						Partition:      "default",
 						Datacenter:     "dc2",
 						ConnectTimeout: 5 * time.Second,
 						SNI:            "web.default.dc2.internal." + testClusterID + ".consul",
@@ -142,6 +144,7 @@ func TestAPI_DiscoveryChain_Get(t *testing.T) {
 						ID:             "web.default.default.dc1",
 						Service:        "web",
 						Namespace:      "default",
+						Partition:      "default",
 						Datacenter:     "dc1",
 						ConnectTimeout: 33 * time.Second,
 						SNI:            "web.default.dc1.internal." + testClusterID + ".consul",
@@ -188,6 +191,7 @@ func TestAPI_DiscoveryChain_Get(t *testing.T) {
 						ID:         "web.default.default.dc2",
 						Service:    "web",
 						Namespace:  "default",
+						Partition:  "default",
 						Datacenter: "dc2",
 						MeshGateway: MeshGatewayConfig{
 							Mode: MeshGatewayModeLocal,
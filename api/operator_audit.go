@@ -1,8 +1,8 @@
 // Copyright (c) HashiCorp, Inc.
 // SPDX-License-Identifier: BUSL-1.1
 
-// The /v1/operator/audit-hash endpoint is available only in Consul Enterprise and
-// interact with its audit logging subsystem.
+// The /v1/operator/audit-hash endpoint interacts with the audit logging
+// subsystem of the agent the request is sent to.
 
 package api
 
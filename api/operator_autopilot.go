@@ -37,17 +37,18 @@ type AutopilotConfiguration struct {
 	// applicable with Raft protocol version 3 or higher.
 	ServerStabilizationTime *ReadableDuration
 
-	// (Enterprise-only) RedundancyZoneTag is the node tag to use for separating
+	// RedundancyZoneTag is the node tag to use for separating
 	// servers into zones for redundancy. If left blank, this feature will be disabled.
 	RedundancyZoneTag string
 
-	// (Enterprise-only) DisableUpgradeMigration will disable Autopilot's upgrade migration
+	// DisableUpgradeMigration will disable Autopilot's upgrade migration
 	// strategy of waiting until enough newer-versioned servers have been added to the
 	// cluster before promoting them to voters.
 	DisableUpgradeMigration bool
 
-	// (Enterprise-only) UpgradeVersionTag is the node tag to use for version info when
-	// performing upgrade migrations. If left blank, the Consul version will be used.
+	// UpgradeVersionTag is the node tag to use for version info when
+	// performing upgrade migrations. Upgrade migrations are only performed
+	// when it is set, leaving it blank disables them.
 	UpgradeVersionTag string
 
 	// CreateIndex holds the index corresponding the creation of this configuration.
@@ -3,6 +3,11 @@
 
 package api
 
+import (
+	"strconv"
+	"time"
+)
+
 // RaftServer has information about a server in the Raft configuration.
 type RaftServer struct {
 	// ID is the unique ID for the server. These are currently the same
@@ -42,6 +47,61 @@ type RaftConfiguration struct {
 	Index uint64
 }
 
+// RaftLogEntry is a single decoded entry from a server's Raft log.
+type RaftLogEntry struct {
+	Index uint64
+	Term  uint64
+
+	// LogType is the Raft-level type of the entry, such as "LogCommand".
+	LogType string
+
+	// MessageType is the name of the Consul message type for command entries,
+	// such as "KVS" or "Register".
+	MessageType string `json:",omitempty"`
+
+	// AppendedAt is the time the leader first appended the entry, if known.
+	AppendedAt time.Time `json:",omitempty"`
+
+	// Size is the size in bytes of the entry's data.
+	Size int
+
+	// Chunked is true if the entry is one part of a command that was split
+	// across multiple log entries, in which case Body is not decoded.
+	Chunked bool `json:",omitempty"`
+
+	// Body is a summary of the decoded request with sensitive fields redacted.
+	Body map[string]interface{} `json:",omitempty"`
+
+	// Error is set if the entry's data could not be decoded.
+	Error string `json:",omitempty"`
+}
+
+// RaftLog is returned when reading the Raft log.
+type RaftLog struct {
+	// FirstIndex and LastIndex are the bounds of the log held by the server
+	// that answered the request.
+	FirstIndex uint64
+	LastIndex  uint64
+
+	// Entries are the matching entries in ascending index order.
+	Entries []*RaftLogEntry
+}
+
+// RaftLogOptions selects which entries are returned by RaftGetLog.
+type RaftLogOptions struct {
+	// MinIndex and MaxIndex bound the range of indexes to return. Zero means
+	// the range is open on that side.
+	MinIndex uint64
+	MaxIndex uint64
+
+	// Types restricts the output to the given message types or Raft log types.
+	Types []string
+
+	// Limit is the maximum number of entries to return, newest first. The
+	// server applies a default limit if this is zero.
+	Limit int
+}
+
 // TransferLeaderResponse is returned when querying for the current Raft configuration.
 type TransferLeaderResponse struct {
 	Success bool
@@ -67,6 +127,42 @@ func (op *Operator) RaftGetConfiguration(q *QueryOptions) (*RaftConfiguration, e
 	return &out, nil
 }
 
+// RaftGetLog is used to list and decode recent entries from the Raft log. The
+// leader's log is read unless q allows stale reads, in which case the log of
+// the server handling the request is read.
+func (op *Operator) RaftGetLog(opts *RaftLogOptions, q *QueryOptions) (*RaftLog, error) {
+	r := op.c.newRequest("GET", "/v1/operator/raft/log")
+	r.setQueryOptions(q)
+	if opts != nil {
+		if opts.MinIndex != 0 {
+			r.params.Set("min_index", strconv.FormatUint(opts.MinIndex, 10))
+		}
+		if opts.MaxIndex != 0 {
+			r.params.Set("max_index", strconv.FormatUint(opts.MaxIndex, 10))
+		}
+		if opts.Limit != 0 {
+			r.params.Set("limit", strconv.Itoa(opts.Limit))
+		}
+		for _, t := range opts.Types {
+			r.params.Add("type", t)
+		}
+	}
+	_, resp, err := op.c.doRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, err
+	}
+
+	var out RaftLog
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
 // RaftLeaderTransfer is used to transfer the current raft leader to another node
 // Optionally accepts a non-empty id of another node to transfer leadership to.
 func (op *Operator) RaftLeaderTransfer(id string, q *QueryOptions) (*TransferLeaderResponse, error) {
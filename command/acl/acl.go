@@ -50,6 +50,10 @@ Usage: consul acl <subcommand> [options] [args]
                                  -datacenter "dc2" \
                                  -rules @rules.hcl
 
+  Explain why a token may not register a service:
+
+      $ consul acl explain -resource service:web -access write
+
   Set the default agent token:
 
       $ consul acl set-agent-token default 0bc6bc46-f25e-4262-b2d9-ffbe1d96be6f
@@ -0,0 +1,203 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package explain
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+)
+
+const (
+	PrettyFormat string = "pretty"
+	JSONFormat   string = "json"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	resources []string
+	access    string
+	format    string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.Var((*flags.AppendSliceValue)(&c.resources), "resource", "The resource to check access to, "+
+		"formatted as <type>:<name>, such as service:web or key:app/config. Resources that are not "+
+		"named, such as operator, are given by type alone. May be specified multiple times.")
+	c.flags.StringVar(&c.access, "access", "read", "The access level to check, such as read, "+
+		"write or list.")
+	c.flags.StringVar(&c.format, "format", PrettyFormat,
+		fmt.Sprintf("Output format {%s}", strings.Join([]string{PrettyFormat, JSONFormat}, "|")))
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(c.resources) == 0 {
+		c.UI.Error("Missing required '-resource' flag")
+		return 1
+	}
+	if c.format != PrettyFormat && c.format != JSONFormat {
+		c.UI.Error(fmt.Sprintf("Invalid format: %s", c.format))
+		return 1
+	}
+
+	requests := make([]*api.ACLAuthorizationRequest, 0, len(c.resources))
+	for _, resource := range c.resources {
+		rsc, segment, _ := strings.Cut(resource, ":")
+		requests = append(requests, &api.ACLAuthorizationRequest{
+			Resource:  rsc,
+			Segment:   segment,
+			Access:    c.access,
+			Namespace: c.http.Namespace(),
+			Partition: c.http.Partition(),
+		})
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	explanations, _, err := client.ACL().AuthorizeExplain(requests, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error explaining authorizations: %v", err))
+		return 1
+	}
+
+	if c.format == JSONFormat {
+		b, err := json.MarshalIndent(explanations, "", "    ")
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Failed to marshal explanations: %v", err))
+			return 1
+		}
+		c.UI.Output(string(b))
+		return 0
+	}
+
+	for i, explanation := range explanations {
+		if i > 0 {
+			c.UI.Output("")
+		}
+		c.UI.Output(formatExplanation(explanation))
+	}
+	return 0
+}
+
+func formatExplanation(e *api.ACLAuthorizationExplanation) string {
+	var buf bytes.Buffer
+
+	resource := e.Resource
+	if e.Segment != "" {
+		resource += ":" + e.Segment
+	}
+	decision := "denied"
+	if e.Allow {
+		decision = "allowed"
+	}
+	buf.WriteString(fmt.Sprintf("Resource:   %s\n", resource))
+	buf.WriteString(fmt.Sprintf("Access:     %s\n", e.Access))
+	buf.WriteString(fmt.Sprintf("Decision:   %s\n", decision))
+
+	switch e.DecidedBy {
+	case api.ACLDecisionSourcePolicy:
+		buf.WriteString(fmt.Sprintf("Decided By: policy %q", e.PolicyName))
+		if e.PolicyID != "" {
+			buf.WriteString(fmt.Sprintf(" (%s)", e.PolicyID))
+		}
+		buf.WriteString("\n")
+		if e.PolicyDescription != "" {
+			buf.WriteString(fmt.Sprintf("            %s\n", e.PolicyDescription))
+		}
+		if e.Rule != nil {
+			buf.WriteString(fmt.Sprintf("Rule:       %s\n", formatRule(e.Rule)))
+		}
+	case api.ACLDecisionSourceDefaultPolicy:
+		buf.WriteString("Decided By: the default policy, as no rule in the token's policies applies\n")
+	case api.ACLDecisionSourceIdentityDefaults:
+		buf.WriteString("Decided By: the defaults of the token's namespace or partition, as no rule in the token's policies applies\n")
+	case api.ACLDecisionSourceLocalToken:
+		buf.WriteString("Decided By: the token, which is managed by Consul itself rather than by policies\n")
+	default:
+		buf.WriteString(fmt.Sprintf("Decided By: %s\n", e.DecidedBy))
+	}
+
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+// formatRule renders the rule the way it would be written in HCL.
+func formatRule(rule *api.ACLExplainedRule) string {
+	block := rule.Resource
+	switch {
+	case rule.Prefix:
+		block += "_prefix"
+	case rule.Glob:
+		block += "_glob"
+	}
+
+	switch rule.Resource {
+	case "acl", "keyring", "operator", "mesh", "peering":
+		return fmt.Sprintf("%s = %q", block, rule.Policy)
+	}
+
+	body := fmt.Sprintf("policy = %q", rule.Policy)
+	if rule.Intentions != "" {
+		body += fmt.Sprintf(" intentions = %q", rule.Intentions)
+	}
+	return fmt.Sprintf("%s %q { %s }", block, rule.Segment, body)
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const (
+	synopsis = "Explain which ACL rules allow or deny a token access to resources"
+	help     = `
+Usage: consul acl explain [options] -resource <type>:<name> [-resource ...]
+
+  Checks whether the token used for the request has the given access to each
+  resource, and explains which policy and rule, or which default, produced the
+  decision. The decisions are made by the servers exactly as they are enforced.
+
+  Explain why the token cannot register the "web" service:
+
+      $ consul acl explain -token <token> -resource service:web -access write
+
+  Check read access to a KV key and to the operator APIs:
+
+      $ consul acl explain -resource key:app/config -resource operator
+`
+)
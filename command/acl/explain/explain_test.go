@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package explain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestExplainCommand_noTabs(t *testing.T) {
+	t.Parallel()
+
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestExplainCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		default_policy = "deny"
+		tokens {
+			initial_management = "root"
+		}
+	}`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+	policy, _, err := client.ACL().PolicyCreate(&api.ACLPolicy{
+		Name:  "web-read",
+		Rules: `service "web" { policy = "read" }`,
+	}, &api.WriteOptions{Token: "root"})
+	require.NoError(t, err)
+
+	token, _, err := client.ACL().TokenCreate(&api.ACLToken{
+		Policies: []*api.ACLTokenPolicyLink{{ID: policy.ID}},
+	}, &api.WriteOptions{Token: "root"})
+	require.NoError(t, err)
+
+	t.Run("missing resource", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{"-http-addr=" + a.HTTPAddr(), "-token=" + token.SecretID})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Missing required '-resource' flag")
+	})
+
+	t.Run("invalid access", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=" + token.SecretID,
+			"-resource=service:web",
+			"-access=fly",
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Invalid access level")
+	})
+
+	t.Run("pretty", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=" + token.SecretID,
+			"-resource=service:web",
+			"-resource=operator",
+			"-access=write",
+		})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		output := ui.OutputWriter.String()
+		require.Contains(t, output, "Resource:   service:web")
+		require.Contains(t, output, "Decision:   denied")
+		require.Contains(t, output, `Decided By: policy "web-read"`)
+		require.Contains(t, output, `Rule:       service "web" { policy = "read" }`)
+		require.Contains(t, output, "Resource:   operator")
+		require.Contains(t, output, "Decided By: the default policy")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=" + token.SecretID,
+			"-resource=service:web",
+			"-format=json",
+		})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		var explanations []*api.ACLAuthorizationExplanation
+		require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &explanations))
+		require.Len(t, explanations, 1)
+		require.True(t, explanations[0].Allow)
+		require.Equal(t, api.ACLDecisionSourcePolicy, explanations[0].DecidedBy)
+		require.Equal(t, policy.ID, explanations[0].PolicyID)
+		require.Equal(t, &api.ACLExplainedRule{Resource: "service", Segment: "web", Policy: "read"}, explanations[0].Rule)
+	})
+}
+
+func TestFormatRule(t *testing.T) {
+	require.Equal(t, `operator = "write"`, formatRule(&api.ACLExplainedRule{Resource: "operator", Policy: "write"}))
+	require.Equal(t, `key_prefix "app/" { policy = "list" }`,
+		formatRule(&api.ACLExplainedRule{Resource: "key", Segment: "app/", Prefix: true, Policy: "list"}))
+	require.Equal(t, `key_glob "app/*/secrets/**" { policy = "deny" }`,
+		formatRule(&api.ACLExplainedRule{Resource: "key", Segment: "app/*/secrets/**", Glob: true, Policy: "deny"}))
+	require.Equal(t, `service "web" { policy = "read" intentions = "write" }`,
+		formatRule(&api.ACLExplainedRule{Resource: "service", Segment: "web", Policy: "read", Intentions: "write"}))
+}
@@ -0,0 +1,166 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package templatedpolicycreate
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/acl/templatedpolicy"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/helpers"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	name        string
+	description string
+	template    string
+	schema      string
+
+	showMeta bool
+	format   string
+
+	testStdin io.Reader
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.BoolVar(&c.showMeta, "meta", false, "Indicates that templated policy metadata such "+
+		"as the schema and template code should be shown.")
+	c.flags.StringVar(&c.name, "name", "", "The new templated policy's name. This flag is required.")
+	c.flags.StringVar(&c.description, "description", "", "A description of the templated policy")
+	c.flags.StringVar(&c.template, "template", "", "The templated policy rules. May be prefixed with '@' "+
+		"to indicate that the value is a file path to load the template from. '-' may also be "+
+		"given to indicate that the template is available on stdin. This flag is required.")
+	c.flags.StringVar(&c.schema, "schema", "", "The JSON schema used to validate the template "+
+		"variables. May be prefixed with '@' to indicate that the value is a file path to load "+
+		"the schema from. '-' may also be given to indicate that the schema is available on stdin")
+	c.flags.StringVar(
+		&c.format,
+		"format",
+		templatedpolicy.PrettyFormat,
+		fmt.Sprintf("Output format {%s}", strings.Join(templatedpolicy.GetSupportedFormats(), "|")),
+	)
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if c.name == "" {
+		c.UI.Error("Missing required '-name' flag")
+		c.UI.Error(c.Help())
+		return 1
+	}
+
+	if c.template == "" {
+		c.UI.Error("Missing required '-template' flag")
+		c.UI.Error(c.Help())
+		return 1
+	}
+
+	if c.template == "-" && c.schema == "-" {
+		c.UI.Error("Only one of '-template' and '-schema' can be read from stdin")
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	template, err := helpers.LoadDataSource(c.template, c.testStdin)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error loading template: %v", err))
+		return 1
+	}
+
+	schema, err := helpers.LoadDataSource(c.schema, c.testStdin)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error loading schema: %v", err))
+		return 1
+	}
+
+	newTemplatedPolicy := &api.ACLTemplatedPolicyDefinition{
+		Name:        c.name,
+		Description: c.description,
+		Schema:      schema,
+		Template:    template,
+	}
+
+	tp, _, err := client.ACL().TemplatedPolicyCreate(newTemplatedPolicy, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to create new templated policy: %v", err))
+		return 1
+	}
+
+	formatter, err := templatedpolicy.NewFormatter(c.format, c.showMeta)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	out, err := formatter.FormatTemplatedPolicy(templatedpolicy.ResponseFromDefinition(tp))
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if out != "" {
+		c.UI.Info(out)
+	}
+
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const (
+	synopsis = "Create an ACL templated policy"
+	help     = `
+Usage: consul acl templated-policy create -name NAME -template TEMPLATE [options]
+
+    The -template and -schema option values allow loading the value from stdin,
+    a file or the raw value. To use stdin pass '-' as the value. To load the
+    value from a file prefix the value with an '@'. Any other values will be
+    used directly.
+
+    The template is rendered with the variables given when the templated policy
+    is referenced, for example {{.Name}}, and must result in valid policy rules.
+
+    Create a new templated policy:
+
+        $ consul acl templated-policy create -name "team-kv-namespace" \
+                                             -description "KV access for a team" \
+                                             -schema @schema.json \
+                                             -template @template.hcl
+`
+)
@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package templatedpolicycreate
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestTemplatedPolicyCreateCommand_noTabs(t *testing.T) {
+	t.Parallel()
+
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestTemplatedPolicyCreateCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			initial_management = "root"
+		}
+	}`)
+
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1", testrpc.WithToken("root"))
+
+	schema := `{"type": "object", "properties": {"name": {"type": "string", "$ref": "#/definitions/min-length-one"}}, "required": ["name"], "definitions": {"min-length-one": {"type": "string", "minLength": 1}}}`
+
+	t.Run("missing template flag", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-name=team-kv-namespace",
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Missing required '-template' flag")
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-name=broken",
+			"-template=key_prefix {",
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Failed to create new templated policy")
+	})
+
+	t.Run("create", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-name=team-kv-namespace",
+			"-description=KV access for a team",
+			"-schema=" + schema,
+			`-template=key_prefix "teams/{{.Name}}/" { policy = "write" }`,
+			"-format=json",
+		})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+		require.Empty(t, ui.ErrorWriter.String())
+
+		var tp api.ACLTemplatedPolicyResponse
+		require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &tp))
+		require.Equal(t, "team-kv-namespace", tp.TemplateName)
+		require.Equal(t, "KV access for a team", tp.Description)
+	})
+
+	t.Run("builtin name", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-name=" + api.ACLTemplatedPolicyServiceName,
+			`-template=key_prefix "" { policy = "write" }`,
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Failed to create new templated policy")
+	})
+}
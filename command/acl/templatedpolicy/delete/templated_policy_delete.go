@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package templatedpolicydelete
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/command/flags"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	name string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.name, "name", "", "The name of the templated policy to delete.")
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if c.name == "" {
+		c.UI.Error("Must specify the -name parameter")
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	if _, err := client.ACL().TemplatedPolicyDelete(c.name, nil); err != nil {
+		c.UI.Error(fmt.Sprintf("Error deleting templated policy %q: %v", c.name, err))
+		return 1
+	}
+
+	c.UI.Info(fmt.Sprintf("Templated policy %q deleted successfully", c.name))
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const (
+	synopsis = "Delete an ACL templated policy"
+	help     = `
+Usage: consul acl templated-policy delete [options] -name NAME
+
+    Deletes a user-defined templated policy. Builtin templated policies cannot
+    be deleted. Tokens, roles and binding rules that still reference the
+    templated policy no longer receive its rules.
+
+        $ consul acl templated-policy delete -name "team-kv-namespace"
+`
+)
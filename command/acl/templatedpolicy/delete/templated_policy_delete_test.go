@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package templatedpolicydelete
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestTemplatedPolicyDeleteCommand_noTabs(t *testing.T) {
+	t.Parallel()
+
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestTemplatedPolicyDeleteCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			initial_management = "root"
+		}
+	}`)
+
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1", testrpc.WithToken("root"))
+
+	client := a.Client()
+	_, _, err := client.ACL().TemplatedPolicyCreate(&api.ACLTemplatedPolicyDefinition{
+		Name:     "team-kv-namespace",
+		Template: `key_prefix "teams/" { policy = "read" }`,
+	}, &api.WriteOptions{Token: "root"})
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := New(ui)
+
+	code := cmd.Run([]string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-token=root",
+		"-name=team-kv-namespace",
+	})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), `Templated policy "team-kv-namespace" deleted successfully`)
+
+	ui = cli.NewMockUi()
+	cmd = New(ui)
+
+	code = cmd.Run([]string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-token=root",
+		"-name=" + api.ACLTemplatedPolicyServiceName,
+	})
+	require.Equal(t, 1, code)
+}
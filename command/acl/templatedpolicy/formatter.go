@@ -57,14 +57,15 @@ type prettyFormatter struct {
 
 // FormatTemplatedPolicy displays template name, input variables and example usages. When
 // showMeta is true, we display raw template code and schema.
-// This implementation is a conscious choice as  we know builtin variables we know every required/optional input variables
-// so we can just hardcode this.
-// In the future, when we implement user defined templated policies, we will move this to some sort of schema parsing.
-// This implementation allows us to move forward without limiting ourselves when implementing user defined templated policies.
+// The input variables of the builtin templated policies are hardcoded, while those of
+// user defined templated policies are derived from their schema.
 func (f *prettyFormatter) FormatTemplatedPolicy(templatedPolicy api.ACLTemplatedPolicyResponse) (string, error) {
 	var buffer bytes.Buffer
 
 	buffer.WriteString(fmt.Sprintf("Name:            %s\n", templatedPolicy.TemplateName))
+	if templatedPolicy.Description != "" {
+		buffer.WriteString(fmt.Sprintf("Description:     %s\n", templatedPolicy.Description))
+	}
 
 	buffer.WriteString("Input variables:")
 	switch templatedPolicy.TemplateName {
@@ -79,7 +80,7 @@ func (f *prettyFormatter) FormatTemplatedPolicy(templatedPolicy api.ACLTemplated
 	case api.ACLTemplatedPolicyDNSName, api.ACLTemplatedPolicyNomadServerName:
 		noRequiredVariablesOutput(&buffer, templatedPolicy.TemplateName)
 	default:
-		buffer.WriteString("   None\n")
+		schemaVariablesOutput(&buffer, templatedPolicy)
 	}
 
 	if f.showMeta {
@@ -98,6 +99,48 @@ func noRequiredVariablesOutput(buffer *bytes.Buffer, templateName string) {
 	buffer.WriteString(fmt.Sprintf("%sconsul acl token create -templated-policy %s\n", WhitespaceIndent, templateName))
 }
 
+// templatedPolicySchema holds the parts of a templated policy's JSON schema
+// needed to describe its input variables.
+type templatedPolicySchema struct {
+	Properties map[string]struct {
+		Description string `json:"description"`
+	} `json:"properties"`
+	Required []string `json:"required"`
+}
+
+func schemaVariablesOutput(buffer *bytes.Buffer, templatedPolicy api.ACLTemplatedPolicyResponse) {
+	var schema templatedPolicySchema
+	if templatedPolicy.Schema != "" {
+		// An invalid schema is rejected when the templated policy is written, so
+		// this only guards against unexpected input.
+		if err := json.Unmarshal([]byte(templatedPolicy.Schema), &schema); err != nil {
+			buffer.WriteString("   None\n")
+			return
+		}
+	}
+
+	property, ok := schema.Properties["name"]
+	if !ok {
+		noRequiredVariablesOutput(buffer, templatedPolicy.TemplateName)
+		return
+	}
+
+	requirement := "Optional"
+	for _, required := range schema.Required {
+		if required == "name" {
+			requirement = "Required"
+		}
+	}
+
+	buffer.WriteString(fmt.Sprintf("\n%sName: String - %s", WhitespaceIndent, requirement))
+	if property.Description != "" {
+		buffer.WriteString(fmt.Sprintf(" - %s", property.Description))
+	}
+	buffer.WriteString("\n")
+	buffer.WriteString("Example usage:\n")
+	buffer.WriteString(fmt.Sprintf("%sconsul acl token create -templated-policy %s -var name:example\n", WhitespaceIndent, templatedPolicy.TemplateName))
+}
+
 func (f *prettyFormatter) FormatTemplatedPolicyList(policies map[string]api.ACLTemplatedPolicyResponse) (string, error) {
 	var buffer bytes.Buffer
 
@@ -134,3 +177,14 @@ func (f *jsonFormatter) FormatTemplatedPolicyList(templatedPolicies map[string]a
 	}
 	return string(b), nil
 }
+
+// ResponseFromDefinition converts a user-defined templated policy into the
+// representation used by the formatters.
+func ResponseFromDefinition(tp *api.ACLTemplatedPolicyDefinition) api.ACLTemplatedPolicyResponse {
+	return api.ACLTemplatedPolicyResponse{
+		TemplateName: tp.Name,
+		Description:  tp.Description,
+		Schema:       tp.Schema,
+		Template:     tp.Template,
+	}
+}
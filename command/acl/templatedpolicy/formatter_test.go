@@ -60,6 +60,14 @@ func testFormatTemplatedPolicy(t *testing.T, dirPath string) {
 				Template:     structs.ACLTemplatedPolicyNomadServer,
 			},
 		},
+		"user-defined-templated-policy": {
+			templatedPolicy: api.ACLTemplatedPolicyResponse{
+				TemplateName: "team-kv-namespace",
+				Description:  "Read and write access to a team's KV namespace",
+				Schema:       `{"type": "object", "properties": {"name": {"type": "string", "description": "The name of the team."}}, "required": ["name"]}`,
+				Template:     `key_prefix "teams/{{.Name}}/" { policy = "write" }`,
+			},
+		},
 	}
 
 	formatters := map[string]Formatter{
@@ -46,5 +46,15 @@ Usage: consul acl templated-policy <subcommand> [options] [args]
 
       $ consul acl templated-policy read -name "builtin/service"
 
+  Create a user-defined templated policy:
+
+      $ consul acl templated-policy create -name "team-kv-namespace" \
+                                           -schema @schema.json \
+                                           -template @template.hcl
+
+  Delete a user-defined templated policy:
+
+      $ consul acl templated-policy delete -name "team-kv-namespace"
+
   For more examples, ask for subcommand help or view the documentation.
 `
@@ -0,0 +1,171 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package templatedpolicyupdate
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/acl/templatedpolicy"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/helpers"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	name        string
+	description string
+	template    string
+	schema      string
+
+	showMeta bool
+	format   string
+
+	testStdin io.Reader
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.BoolVar(&c.showMeta, "meta", false, "Indicates that templated policy metadata such "+
+		"as the schema and template code should be shown.")
+	c.flags.StringVar(&c.name, "name", "", "The name of the templated policy to update. "+
+		"This flag is required.")
+	c.flags.StringVar(&c.description, "description", "", "A description of the templated policy")
+	c.flags.StringVar(&c.template, "template", "", "The templated policy rules. May be prefixed with '@' "+
+		"to indicate that the value is a file path to load the template from. '-' may also be "+
+		"given to indicate that the template is available on stdin")
+	c.flags.StringVar(&c.schema, "schema", "", "The JSON schema used to validate the template "+
+		"variables. May be prefixed with '@' to indicate that the value is a file path to load "+
+		"the schema from. '-' may also be given to indicate that the schema is available on stdin")
+	c.flags.StringVar(
+		&c.format,
+		"format",
+		templatedpolicy.PrettyFormat,
+		fmt.Sprintf("Output format {%s}", strings.Join(templatedpolicy.GetSupportedFormats(), "|")),
+	)
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if c.name == "" {
+		c.UI.Error("Cannot update a templated policy without specifying the -name parameter")
+		return 1
+	}
+
+	if c.template == "-" && c.schema == "-" {
+		c.UI.Error("Only one of '-template' and '-schema' can be read from stdin")
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	template, err := helpers.LoadDataSource(c.template, c.testStdin)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error loading template: %v", err))
+		return 1
+	}
+
+	schema, err := helpers.LoadDataSource(c.schema, c.testStdin)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error loading schema: %v", err))
+		return 1
+	}
+
+	existing, _, err := client.ACL().TemplatedPolicyReadByName(c.name, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading templated policy %q: %v", c.name, err))
+		return 1
+	} else if existing == nil {
+		c.UI.Error(fmt.Sprintf("Templated policy not found with name %q", c.name))
+		return 1
+	}
+
+	tp := &api.ACLTemplatedPolicyDefinition{
+		Name:        c.name,
+		Description: existing.Description,
+		Schema:      existing.Schema,
+		Template:    existing.Template,
+	}
+	if c.description != "" {
+		tp.Description = c.description
+	}
+	if template != "" {
+		tp.Template = template
+	}
+	if schema != "" {
+		tp.Schema = schema
+	}
+
+	updated, _, err := client.ACL().TemplatedPolicyUpdate(tp, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error updating templated policy %q: %v", c.name, err))
+		return 1
+	}
+
+	formatter, err := templatedpolicy.NewFormatter(c.format, c.showMeta)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	out, err := formatter.FormatTemplatedPolicy(templatedpolicy.ResponseFromDefinition(updated))
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if out != "" {
+		c.UI.Info(out)
+	}
+
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const (
+	synopsis = "Update an ACL templated policy"
+	help     = `
+Usage: consul acl templated-policy update -name NAME [options]
+
+    Updates a user-defined templated policy. Builtin templated policies cannot
+    be updated. Fields that are not specified keep their current values.
+
+    Update the template:
+
+        $ consul acl templated-policy update -name "team-kv-namespace" \
+                                             -template @template.hcl
+`
+)
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package templatedpolicyupdate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestTemplatedPolicyUpdateCommand_noTabs(t *testing.T) {
+	t.Parallel()
+
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestTemplatedPolicyUpdateCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			initial_management = "root"
+		}
+	}`)
+
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1", testrpc.WithToken("root"))
+
+	client := a.Client()
+	_, _, err := client.ACL().TemplatedPolicyCreate(&api.ACLTemplatedPolicyDefinition{
+		Name:        "team-kv-namespace",
+		Description: "KV access for a team",
+		Template:    `key_prefix "teams/" { policy = "read" }`,
+	}, &api.WriteOptions{Token: "root"})
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := New(ui)
+
+	code := cmd.Run([]string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-token=root",
+		"-name=team-kv-namespace",
+		`-template=key_prefix "teams/" { policy = "write" }`,
+		"-meta",
+	})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	output := ui.OutputWriter.String()
+	require.Contains(t, output, "KV access for a team")
+	require.Contains(t, output, `policy = "write"`)
+
+	ui = cli.NewMockUi()
+	cmd = New(ui)
+
+	code = cmd.Run([]string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-token=root",
+		"-name=does-not-exist",
+		"-description=nope",
+	})
+	require.Equal(t, 1, code)
+}
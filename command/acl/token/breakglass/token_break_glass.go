@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokenbreakglass
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/acl/token"
+	"github.com/hashicorp/consul/command/flags"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	reason            string
+	ttl               time.Duration
+	approverToken     string
+	approverTokenFile string
+	format            string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.reason, "reason", "", "The justification for the break-glass "+
+		"token. This is required and is recorded with the token and in the audit log.")
+	c.flags.DurationVar(&c.ttl, "ttl", time.Hour, "How long the token remains valid. "+
+		"It may not exceed the maximum configured on the servers.")
+	c.flags.StringVar(&c.approverToken, "approver-token", "", "The SecretID of the token "+
+		"approving the request. It must be allowed to write ACLs and must be different "+
+		"from the token making the request.")
+	c.flags.StringVar(&c.approverTokenFile, "approver-token-file", "", "Path to a file "+
+		"containing the SecretID of the token approving the request.")
+	c.flags.StringVar(
+		&c.format,
+		"format",
+		token.PrettyFormat,
+		fmt.Sprintf("Output format {%s}", strings.Join(token.GetSupportedFormats(), "|")),
+	)
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(c.flags.Args()) > 0 {
+		c.UI.Error("Too many arguments (expected 0)")
+		return 1
+	}
+
+	if c.reason == "" {
+		c.UI.Error("Missing required '-reason' flag")
+		return 1
+	}
+
+	if c.ttl <= 0 {
+		c.UI.Error("The -ttl must be positive")
+		return 1
+	}
+
+	approverToken := c.approverToken
+	switch {
+	case approverToken != "" && c.approverTokenFile != "":
+		c.UI.Error("Cannot specify both -approver-token and -approver-token-file")
+		return 1
+	case c.approverTokenFile != "":
+		data, err := os.ReadFile(c.approverTokenFile)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error reading approver token file: %s", err))
+			return 1
+		}
+		approverToken = strings.TrimSpace(string(data))
+		if approverToken == "" {
+			c.UI.Error(fmt.Sprintf("No approver token found in %s", c.approverTokenFile))
+			return 1
+		}
+	case approverToken == "":
+		c.UI.Error("Missing required '-approver-token' or '-approver-token-file' flag")
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	t, _, err := client.ACL().TokenBreakGlass(&api.ACLTokenBreakGlassRequest{
+		Reason:        c.reason,
+		TTL:           c.ttl,
+		ApproverToken: approverToken,
+	}, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error issuing break-glass token: %v", err))
+		return 1
+	}
+
+	formatter, err := token.NewFormatter(c.format, false)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	out, err := formatter.FormatToken(t)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if out != "" {
+		c.UI.Info(out)
+	}
+
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const (
+	synopsis = "Issue a short-lived emergency token with global-management access"
+	help     = `
+Usage: consul acl token break-glass [options] -reason REASON -approver-token-file FILE
+
+    This command issues a local token linked to the global-management policy
+    that expires after the requested TTL. The token used to run the command
+    identifies the requester. The request must be approved by a second token
+    that is allowed to write ACLs.
+
+    Every break-glass token records its reason, requester and approver, is
+    written to the audit log and is deleted automatically once it expires.
+
+    Issue a break-glass token for one hour:
+
+        $ consul acl token break-glass -reason "Incident 4711" \
+                                       -approver-token-file approver.token
+
+    List the break-glass tokens that have not expired yet:
+
+        $ consul acl token list -break-glass
+`
+)
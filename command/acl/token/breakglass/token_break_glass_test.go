@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokenbreakglass
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestTokenBreakGlassCommand_noTabs(t *testing.T) {
+	t.Parallel()
+
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestTokenBreakGlassCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, `
+   primary_datacenter = "dc1"
+   acl {
+      enabled = true
+      tokens {
+         initial_management = "root"
+      }
+   }`)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+
+	newToken := func(t *testing.T, name, rules string) *api.ACLToken {
+		_, _, err := client.ACL().PolicyCreate(
+			&api.ACLPolicy{Name: name, Rules: rules},
+			&api.WriteOptions{Token: "root"},
+		)
+		require.NoError(t, err)
+
+		token, _, err := client.ACL().TokenCreate(
+			&api.ACLToken{Policies: []*api.ACLTokenPolicyLink{{Name: name}}},
+			&api.WriteOptions{Token: "root"},
+		)
+		require.NoError(t, err)
+		return token
+	}
+
+	requester := newToken(t, "operator", `operator = "read"`)
+	approver := newToken(t, "approver", `acl = "write"`)
+
+	approverFile := filepath.Join(t.TempDir(), "approver")
+	require.NoError(t, os.WriteFile(approverFile, []byte(approver.SecretID+"\n"), 0600))
+
+	t.Run("Missing reason", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=" + requester.SecretID,
+			"-approver-token=" + approver.SecretID,
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Missing required '-reason' flag")
+	})
+
+	t.Run("Missing approver", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=" + requester.SecretID,
+			"-reason=outage",
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Missing required '-approver-token' or '-approver-token-file' flag")
+	})
+
+	t.Run("Self approval", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=" + approver.SecretID,
+			"-approver-token=" + approver.SecretID,
+			"-reason=outage",
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Error issuing break-glass token")
+	})
+
+	var issued api.ACLToken
+	t.Run("Issue", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=" + requester.SecretID,
+			"-approver-token-file=" + approverFile,
+			"-reason=outage",
+			"-ttl=30m",
+			"-format=json",
+		})
+		require.Empty(t, ui.ErrorWriter.String())
+		require.Equal(t, 0, code)
+
+		require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &issued))
+		require.True(t, issued.Local)
+		require.NotNil(t, issued.ExpirationTime)
+		require.Equal(t, &api.ACLTokenBreakGlass{
+			Reason:      "outage",
+			RequestedBy: requester.AccessorID,
+			ApprovedBy:  approver.AccessorID,
+		}, issued.BreakGlass)
+
+		// The issued token has global-management access.
+		_, _, err := client.ACL().PolicyList(&api.QueryOptions{Token: issued.SecretID})
+		require.NoError(t, err)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		tokens, _, err := client.ACL().TokenListFiltered(
+			api.ACLTokenFilterOptions{BreakGlass: true},
+			&api.QueryOptions{Token: "root"},
+		)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		require.Equal(t, issued.AccessorID, tokens[0].AccessorID)
+		require.Equal(t, "outage", tokens[0].BreakGlass.Reason)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/consul/acl"
@@ -26,6 +27,7 @@ const (
 type Formatter interface {
 	FormatToken(token *api.ACLToken) (string, error)
 	FormatTokenExpanded(token *api.ACLTokenExpanded) (string, error)
+	FormatTokenLineage(lineage *api.ACLTokenLineageInfo) (string, error)
 	FormatTokenList(tokens []*api.ACLTokenListEntry) (string, error)
 }
 
@@ -76,6 +78,18 @@ func (f *prettyFormatter) FormatToken(token *api.ACLToken) (string, error) {
 	if token.ExpirationTime != nil && !token.ExpirationTime.IsZero() {
 		buffer.WriteString(fmt.Sprintf("Expiration Time:  %v\n", *token.ExpirationTime))
 	}
+	if token.RotationTime != nil && !token.RotationTime.IsZero() {
+		buffer.WriteString(fmt.Sprintf("Rotation Time:    %v\n", *token.RotationTime))
+	}
+	if token.LastUsedAt != nil && !token.LastUsedAt.IsZero() {
+		buffer.WriteString(fmt.Sprintf("Last Used:        %v\n", *token.LastUsedAt))
+	}
+	if token.PreviousSecretExpirationTime != nil && !token.PreviousSecretExpirationTime.IsZero() {
+		buffer.WriteString(fmt.Sprintf("Previous SecretID Expiration Time: %v\n", *token.PreviousSecretExpirationTime))
+	}
+	if token.BreakGlass != nil {
+		formatBreakGlass(&buffer, token.BreakGlass)
+	}
 	if f.showMeta {
 		buffer.WriteString(fmt.Sprintf("Hash:             %x\n", token.Hash))
 		buffer.WriteString(fmt.Sprintf("Create Index:     %d\n", token.CreateIndex))
@@ -127,6 +141,13 @@ func (f *prettyFormatter) FormatToken(token *api.ACLToken) (string, error) {
 	return buffer.String(), nil
 }
 
+func formatBreakGlass(buffer *bytes.Buffer, bg *api.ACLTokenBreakGlass) {
+	buffer.WriteString(fmt.Sprintln("Break Glass:"))
+	buffer.WriteString(fmt.Sprintf("   Reason:       %s\n", bg.Reason))
+	buffer.WriteString(fmt.Sprintf("   Requested By: %s\n", bg.RequestedBy))
+	buffer.WriteString(fmt.Sprintf("   Approved By:  %s\n", bg.ApprovedBy))
+}
+
 func (f *prettyFormatter) FormatTokenExpanded(token *api.ACLTokenExpanded) (string, error) {
 	var buffer bytes.Buffer
 
@@ -147,6 +168,18 @@ func (f *prettyFormatter) FormatTokenExpanded(token *api.ACLTokenExpanded) (stri
 	if token.ExpirationTime != nil && !token.ExpirationTime.IsZero() {
 		buffer.WriteString(fmt.Sprintf("Expiration Time:  %v\n", *token.ExpirationTime))
 	}
+	if token.RotationTime != nil && !token.RotationTime.IsZero() {
+		buffer.WriteString(fmt.Sprintf("Rotation Time:    %v\n", *token.RotationTime))
+	}
+	if token.LastUsedAt != nil && !token.LastUsedAt.IsZero() {
+		buffer.WriteString(fmt.Sprintf("Last Used:        %v\n", *token.LastUsedAt))
+	}
+	if token.PreviousSecretExpirationTime != nil && !token.PreviousSecretExpirationTime.IsZero() {
+		buffer.WriteString(fmt.Sprintf("Previous SecretID Expiration Time: %v\n", *token.PreviousSecretExpirationTime))
+	}
+	if token.BreakGlass != nil {
+		formatBreakGlass(&buffer, token.BreakGlass)
+	}
 	if f.showMeta {
 		buffer.WriteString(fmt.Sprintf("Hash:             %x\n", token.Hash))
 		buffer.WriteString(fmt.Sprintf("Create Index:     %d\n", token.CreateIndex))
@@ -302,6 +335,57 @@ func (f *prettyFormatter) FormatTokenExpanded(token *api.ACLTokenExpanded) (stri
 	return buffer.String(), nil
 }
 
+func (f *prettyFormatter) FormatTokenLineage(lineage *api.ACLTokenLineageInfo) (string, error) {
+	var buffer bytes.Buffer
+
+	if lineage.IssuedBy != nil {
+		buffer.WriteString(fmt.Sprintf("Auth Method:      %s (Type: %s)\n", lineage.IssuedBy.Name, lineage.IssuedBy.Type))
+	} else {
+		buffer.WriteString(fmt.Sprintf("Auth Method:      %s (deleted)\n", lineage.ACLToken.AuthMethod))
+	}
+
+	var fields map[string]string
+	if lineage.Lineage != nil {
+		fields = lineage.Lineage.SelectableFields
+	}
+	if len(fields) > 0 {
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		buffer.WriteString("Selectable Fields:\n")
+		for _, name := range names {
+			buffer.WriteString(fmt.Sprintf(WHITESPACE_2+"%s: %s\n", name, fields[name]))
+		}
+	}
+
+	if len(lineage.BindingRules) > 0 || len(lineage.MissingBindingRuleIDs) > 0 {
+		buffer.WriteString("Binding Rules:\n")
+		for _, rule := range lineage.BindingRules {
+			buffer.WriteString(fmt.Sprintf(WHITESPACE_2+"ID: %s\n", rule.ID))
+			buffer.WriteString(fmt.Sprintf(WHITESPACE_4+"Description: %s\n", rule.Description))
+			buffer.WriteString(fmt.Sprintf(WHITESPACE_4+"Selector:    %s\n", rule.Selector))
+			buffer.WriteString(fmt.Sprintf(WHITESPACE_4+"Bind:        %s %q\n", rule.BindType, rule.BindName))
+		}
+		for _, id := range lineage.MissingBindingRuleIDs {
+			buffer.WriteString(fmt.Sprintf(WHITESPACE_2+"ID: %s (deleted)\n", id))
+		}
+	} else {
+		buffer.WriteString("Binding Rules:    none recorded\n")
+	}
+
+	buffer.WriteString("=== Issued Token ===\n")
+	expanded, err := f.FormatTokenExpanded(&lineage.ACLTokenExpanded)
+	if err != nil {
+		return "", err
+	}
+	buffer.WriteString(expanded)
+
+	return buffer.String(), nil
+}
+
 func displaySyntheticPolicy(policy *structs.ACLPolicy, buffer *bytes.Buffer, indent string) {
 	buffer.WriteString(fmt.Sprintf(indent+WHITESPACE_2+"Description: %s\n", policy.Description))
 	buffer.WriteString(indent + WHITESPACE_2 + "Rules:")
@@ -345,6 +429,15 @@ func (f *prettyFormatter) formatTokenListEntry(token *api.ACLTokenListEntry) str
 	if token.ExpirationTime != nil && !token.ExpirationTime.IsZero() {
 		buffer.WriteString(fmt.Sprintf("Expiration Time:  %v\n", *token.ExpirationTime))
 	}
+	if token.RotationTime != nil && !token.RotationTime.IsZero() {
+		buffer.WriteString(fmt.Sprintf("Rotation Time:    %v\n", *token.RotationTime))
+	}
+	if token.LastUsedAt != nil && !token.LastUsedAt.IsZero() {
+		buffer.WriteString(fmt.Sprintf("Last Used:        %v\n", *token.LastUsedAt))
+	}
+	if token.BreakGlass != nil {
+		formatBreakGlass(&buffer, token.BreakGlass)
+	}
 	if f.showMeta {
 		buffer.WriteString(fmt.Sprintf("Hash:             %x\n", token.Hash))
 		buffer.WriteString(fmt.Sprintf("Create Index:     %d\n", token.CreateIndex))
@@ -427,3 +520,11 @@ func (f *jsonFormatter) FormatTokenExpanded(token *api.ACLTokenExpanded) (string
 	}
 	return string(b), nil
 }
+
+func (f *jsonFormatter) FormatTokenLineage(lineage *api.ACLTokenLineageInfo) (string, error) {
+	b, err := json.MarshalIndent(lineage, "", "    ")
+	if err != nil {
+		return "", fmt.Errorf("Failed to marshal token lineage: %v", err)
+	}
+	return string(b), nil
+}
@@ -60,6 +60,30 @@ func TestFormatToken(t *testing.T) {
 				ModifyIndex: 100,
 			},
 		},
+		"break-glass": {
+			token: api.ACLToken{
+				AccessorID:     "fbd2447f-7479-4329-ad13-b021d74f86ba",
+				SecretID:       "869c6e91-4de9-4dab-b56e-87548435f9c6",
+				Description:    "Break-glass token: incident 42",
+				Local:          true,
+				CreateTime:     time.Date(2020, 5, 22, 18, 52, 31, 0, time.UTC),
+				ExpirationTime: timeRef(time.Date(2020, 5, 22, 19, 52, 31, 0, time.UTC)),
+				Hash:           []byte{'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h'},
+				CreateIndex:    42,
+				ModifyIndex:    42,
+				Policies: []*api.ACLLink{
+					{
+						ID:   "00000000-0000-0000-0000-000000000001",
+						Name: "global-management",
+					},
+				},
+				BreakGlass: &api.ACLTokenBreakGlass{
+					Reason:      "incident 42",
+					RequestedBy: "5e52a099-4c90-c067-5478-980f06be9af5",
+					ApprovedBy:  "0b2a8f4e-1b55-4c39-9cc2-7bd8e3a7b6a1",
+				},
+			},
+		},
 		"complex": {
 			token: api.ACLToken{
 				AccessorID:          "fbd2447f-7479-4329-ad13-b021d74f86ba",
@@ -521,3 +545,82 @@ func testFormatTokenExpanded(t *testing.T, dirPath string) {
 		})
 	}
 }
+
+func TestFormatTokenLineage(t *testing.T) {
+	type testCase struct {
+		lineage api.ACLTokenLineageInfo
+	}
+
+	cases := map[string]testCase{
+		"basic": {
+			lineage: api.ACLTokenLineageInfo{
+				ACLTokenExpanded: api.ACLTokenExpanded{
+					ACLToken: api.ACLToken{
+						AccessorID:  "fbd2447f-7479-4329-ad13-b021d74f86ba",
+						SecretID:    "869c6e91-4de9-4dab-b56e-87548435f9c6",
+						Description: "token created via login",
+						Local:       true,
+						AuthMethod:  "minikube",
+						CreateTime:  time.Date(2020, 5, 22, 18, 52, 31, 0, time.UTC),
+						Hash:        []byte{'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h'},
+						CreateIndex: 42,
+						ModifyIndex: 100,
+						ServiceIdentities: []*api.ACLServiceIdentity{
+							{ServiceName: "web"},
+						},
+						Lineage: &api.ACLTokenLineage{
+							BindingRuleIDs: []string{
+								"b4cbbe40-3c32-4d6a-9e13-3dc8bb2c4b9a",
+								"0a0e9e7d-2b49-4f55-85a5-6cbbbb0e1b84",
+							},
+							SelectableFields: map[string]string{
+								"serviceaccount.name":      "web",
+								"serviceaccount.namespace": "default",
+								"serviceaccount.uid":       "<redacted>",
+							},
+						},
+					},
+					AgentACLDefaultPolicy: "deny",
+					AgentACLDownPolicy:    "extend-cache",
+					ResolvedByAgent:       "server-1",
+				},
+				IssuedBy: &api.ACLAuthMethodListEntry{
+					Name: "minikube",
+					Type: "kubernetes",
+				},
+				BindingRules: []*api.ACLBindingRule{
+					{
+						ID:          "b4cbbe40-3c32-4d6a-9e13-3dc8bb2c4b9a",
+						Description: "services in the default namespace",
+						AuthMethod:  "minikube",
+						Selector:    "serviceaccount.namespace==default",
+						BindType:    api.BindingRuleBindTypeService,
+						BindName:    "${serviceaccount.name}",
+					},
+				},
+				MissingBindingRuleIDs: []string{"0a0e9e7d-2b49-4f55-85a5-6cbbbb0e1b84"},
+			},
+		},
+	}
+
+	formatters := map[string]Formatter{
+		"pretty": newPrettyFormatter(false),
+		"json":   newJSONFormatter(false),
+	}
+
+	for name, tcase := range cases {
+		t.Run(name, func(t *testing.T) {
+			for fmtName, formatter := range formatters {
+				t.Run(fmtName, func(t *testing.T) {
+					actual, err := formatter.FormatTokenLineage(&tcase.lineage)
+					require.NoError(t, err)
+
+					gName := fmt.Sprintf("%s.%s", name, fmtName)
+
+					expected := golden(t, path.Join("FormatTokenLineage", gName), actual)
+					require.Equal(t, expected, actual)
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokenlineage
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/command/acl"
+	"github.com/hashicorp/consul/command/acl/token"
+	"github.com/hashicorp/consul/command/flags"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	tokenAccessorID string
+	format          string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.tokenAccessorID, "accessor-id", "", "The Accessor ID of the token to "+
+		"inspect. It may be specified as a unique ID prefix but will error if the prefix "+
+		"matches multiple token Accessor IDs. The Accessor ID may also be given "+
+		"as the only argument to the command.")
+	c.flags.StringVar(
+		&c.format,
+		"format",
+		token.PrettyFormat,
+		fmt.Sprintf("Output format {%s}", strings.Join(token.GetSupportedFormats(), "|")),
+	)
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	tokenAccessor := c.tokenAccessorID
+	switch rest := c.flags.Args(); {
+	case len(rest) > 1:
+		c.UI.Error("Too many arguments (expected at most 1)")
+		return 1
+	case len(rest) == 1 && tokenAccessor != "":
+		c.UI.Error("Cannot specify both the -accessor-id flag and an argument")
+		return 1
+	case len(rest) == 1:
+		tokenAccessor = rest[0]
+	}
+
+	if tokenAccessor == "" {
+		c.UI.Error("Cannot show the lineage of a token without specifying its Accessor ID")
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	tok, err := acl.GetTokenAccessorIDFromPartial(client, tokenAccessor)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error determining token Accessor ID: %v", err))
+		return 1
+	}
+
+	lineage, _, err := client.ACL().TokenLineage(tok, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading token lineage %q: %v", tok, err))
+		return 1
+	}
+
+	formatter, err := token.NewFormatter(c.format, false)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	out, err := formatter.FormatTokenLineage(lineage)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if out != "" {
+		c.UI.Info(out)
+	}
+
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const (
+	synopsis = "Show how an ACL token issued by an auth method got its permissions"
+	help     = `
+Usage: consul acl token lineage [options] [ACCESSOR_ID]
+
+    This command shows the lineage of a token created by "consul login": the
+    auth method that issued it, the binding rules that matched the login
+    along with the identity fields they selected on, and the roles, policies
+    and identities the token was granted with its effective permissions.
+
+    Values of identity fields that no binding rule selected on are redacted.
+    Binding rules deleted since the login are listed as such.
+
+    Show the lineage of a token:
+
+        $ consul acl token lineage 986193
+
+    Show the lineage of a token as JSON:
+
+        $ consul acl token lineage -format=json -accessor-id 986193
+`
+)
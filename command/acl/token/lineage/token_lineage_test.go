@@ -0,0 +1,157 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokenlineage
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/agent/consul/authmethod/testauth"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestTokenLineageCommand_noTabs(t *testing.T) {
+	t.Parallel()
+
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestTokenLineageCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, `
+   primary_datacenter = "dc1"
+   acl {
+      enabled = true
+      tokens {
+         initial_management = "root"
+      }
+   }`)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+
+	testSessionID := testauth.StartSession()
+	defer testauth.ResetSession(testSessionID)
+
+	testauth.InstallSessionToken(
+		testSessionID,
+		"demo-token",
+		"default", "demo", "76091af4-4b56-11e9-ac4b-708b11801cbe",
+	)
+
+	_, _, err := client.ACL().AuthMethodCreate(
+		&api.ACLAuthMethod{
+			Name: "test",
+			Type: "testing",
+			Config: map[string]interface{}{
+				"SessionID": testSessionID,
+			},
+		},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	rule, _, err := client.ACL().BindingRuleCreate(
+		&api.ACLBindingRule{
+			AuthMethod: "test",
+			Selector:   "serviceaccount.namespace==default",
+			BindType:   api.BindingRuleBindTypeService,
+			BindName:   "${serviceaccount.name}",
+		},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	loginToken, _, err := client.ACL().Login(&api.ACLLoginParams{
+		AuthMethod:  "test",
+		BearerToken: "demo-token",
+	}, nil)
+	require.NoError(t, err)
+
+	plainToken, _, err := client.ACL().TokenCreate(
+		&api.ACLToken{Description: "test"},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	t.Run("Missing accessor", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{"-http-addr=" + a.HTTPAddr(), "-token=root"})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Cannot show the lineage of a token without specifying its Accessor ID")
+	})
+
+	t.Run("Not issued by an auth method", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{"-http-addr=" + a.HTTPAddr(), "-token=root", plainToken.AccessorID})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "was not issued by an auth method")
+	})
+
+	t.Run("Pretty", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{"-http-addr=" + a.HTTPAddr(), "-token=root", "-accessor-id=" + loginToken.AccessorID})
+		require.Empty(t, ui.ErrorWriter.String())
+		require.Equal(t, 0, code)
+
+		output := ui.OutputWriter.String()
+		require.Contains(t, output, "Auth Method:      test (Type: testing)")
+		require.Contains(t, output, "serviceaccount.namespace: default")
+		require.Contains(t, output, "serviceaccount.uid: <redacted>")
+		require.Contains(t, output, "ID: "+rule.ID)
+		require.Contains(t, output, loginToken.AccessorID)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{"-http-addr=" + a.HTTPAddr(), "-token=root", "-format=json", loginToken.AccessorID})
+		require.Empty(t, ui.ErrorWriter.String())
+		require.Equal(t, 0, code)
+
+		var lineage api.ACLTokenLineageInfo
+		require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &lineage))
+		require.Equal(t, loginToken.AccessorID, lineage.AccessorID)
+		require.Equal(t, "test", lineage.IssuedBy.Name)
+		require.Len(t, lineage.BindingRules, 1)
+		require.Equal(t, rule.ID, lineage.BindingRules[0].ID)
+		require.Equal(t, []string{rule.ID}, lineage.Lineage.BindingRuleIDs)
+		require.Len(t, lineage.ExpandedPolicies, 1)
+	})
+
+	t.Run("Deleted binding rule", func(t *testing.T) {
+		_, err := client.ACL().BindingRuleDelete(rule.ID, &api.WriteOptions{Token: "root"})
+		require.NoError(t, err)
+
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{"-http-addr=" + a.HTTPAddr(), "-token=root", loginToken.AccessorID})
+		require.Empty(t, ui.ErrorWriter.String())
+		require.Equal(t, 0, code)
+		require.Contains(t, ui.OutputWriter.String(), "ID: "+rule.ID+" (deleted)")
+	})
+}
@@ -7,7 +7,10 @@ import (
 	"flag"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/acl/token"
 	"github.com/hashicorp/consul/command/flags"
 	"github.com/mitchellh/cli"
@@ -25,14 +28,22 @@ type cmd struct {
 	http  *flags.HTTPFlags
 	help  string
 
-	showMeta bool
-	format   string
+	showMeta    bool
+	format      string
+	unusedSince time.Duration
+	breakGlass  bool
 }
 
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
 	c.flags.BoolVar(&c.showMeta, "meta", false, "Indicates that token metadata such "+
 		"as the content hash and Raft indices should be shown for each entry")
+	c.flags.DurationVar(&c.unusedSince, "unused-since", 0, "Only list tokens that have not "+
+		"been used for at least this long, for example 720h. Tokens that were never used "+
+		"are listed once they are older than this. Usage is recorded with a granularity "+
+		"of about an hour.")
+	c.flags.BoolVar(&c.breakGlass, "break-glass", false, "Only list break-glass tokens "+
+		"that have not expired yet.")
 	c.flags.StringVar(
 		&c.format,
 		"format",
@@ -57,12 +68,19 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	tokens, _, err := client.ACL().TokenList(nil)
+	tokens, _, err := client.ACL().TokenListFiltered(api.ACLTokenFilterOptions{BreakGlass: c.breakGlass}, nil)
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Failed to retrieve the token list: %v", err))
 		return 1
 	}
 
+	if c.unusedSince < 0 {
+		c.UI.Error("The -unused-since flag must not be negative")
+		return 1
+	} else if c.unusedSince > 0 {
+		tokens = unusedTokens(tokens, time.Now().Add(-c.unusedSince))
+	}
+
 	formatter, err := token.NewFormatter(c.format, c.showMeta)
 	if err != nil {
 		c.UI.Error(err.Error())
@@ -80,6 +98,26 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+// unusedTokens returns the tokens that were last used, or created if they
+// were never used, before the cutoff. The anonymous token can't be deleted so
+// it is never reported.
+func unusedTokens(tokens []*api.ACLTokenListEntry, cutoff time.Time) []*api.ACLTokenListEntry {
+	unused := make([]*api.ACLTokenListEntry, 0, len(tokens))
+	for _, t := range tokens {
+		if t.AccessorID == acl.AnonymousTokenID {
+			continue
+		}
+		lastActivity := t.CreateTime
+		if t.LastUsedAt != nil && t.LastUsedAt.After(lastActivity) {
+			lastActivity = *t.LastUsedAt
+		}
+		if lastActivity.Before(cutoff) {
+			unused = append(unused, t)
+		}
+	}
+	return unused
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -96,5 +134,13 @@ Usage: consul acl token list [options]
   List all the ACL tokens
 
           $ consul acl token list
+
+  List the tokens that have not been used in the last 30 days
+
+          $ consul acl token list -unused-since=720h
+
+  List the break-glass tokens
+
+          $ consul acl token list -break-glass
 `
 )
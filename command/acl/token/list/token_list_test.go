@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/consul/agent"
 	"github.com/hashicorp/consul/api"
@@ -137,3 +138,80 @@ func TestTokenListCommand_JSON(t *testing.T) {
 	}
 	require.Subset(t, respIDs, tokenIds)
 }
+
+func TestTokenListCommand_UnusedSince(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			initial_management = "root"
+		}
+	}`)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+	_, _, err := client.ACL().TokenCreate(
+		&api.ACLToken{Description: "new token"},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	run := func(t *testing.T, unusedSince string) []api.ACLTokenListEntry {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-format=json",
+			"-unused-since=" + unusedSince,
+		})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		var out []api.ACLTokenListEntry
+		require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &out))
+		return out
+	}
+
+	// Everything was just created.
+	require.Empty(t, run(t, "1h"))
+
+	// Only the anonymous token is left out.
+	all, _, err := client.ACL().TokenList(&api.QueryOptions{Token: "root"})
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	require.Len(t, run(t, "1ms"), len(all)-1)
+}
+
+func TestUnusedTokens(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	ago := func(d time.Duration) *time.Time {
+		ts := now.Add(-d)
+		return &ts
+	}
+
+	tokens := []*api.ACLTokenListEntry{
+		{AccessorID: "00000000-0000-0000-0000-000000000002", CreateTime: *ago(1000 * time.Hour)},
+		{AccessorID: "never-used-old", CreateTime: *ago(1000 * time.Hour)},
+		{AccessorID: "never-used-new", CreateTime: *ago(time.Hour)},
+		{AccessorID: "used-long-ago", CreateTime: *ago(1000 * time.Hour), LastUsedAt: ago(800 * time.Hour)},
+		{AccessorID: "used-recently", CreateTime: *ago(1000 * time.Hour), LastUsedAt: ago(time.Hour)},
+	}
+
+	var ids []string
+	for _, t := range unusedTokens(tokens, now.Add(-720*time.Hour)) {
+		ids = append(ids, t.AccessorID)
+	}
+	require.Equal(t, []string{"never-used-old", "used-long-ago"}, ids)
+}
@@ -0,0 +1,149 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokenrotate
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/command/acl"
+	"github.com/hashicorp/consul/command/acl/token"
+	"github.com/hashicorp/consul/command/flags"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	tokenAccessorID string
+	gracePeriod     time.Duration
+	format          string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.tokenAccessorID, "accessor-id", "", "The Accessor ID of the token to rotate. "+
+		"It may be specified as a unique ID prefix but will error if the prefix "+
+		"matches multiple token Accessor IDs. The Accessor ID may also be given "+
+		"as the only argument to the command.")
+	c.flags.DurationVar(&c.gracePeriod, "grace-period", time.Hour, "How long the token's "+
+		"current SecretID remains valid after rotation. A value of 0 invalidates "+
+		"it immediately.")
+	c.flags.StringVar(
+		&c.format,
+		"format",
+		token.PrettyFormat,
+		fmt.Sprintf("Output format {%s}", strings.Join(token.GetSupportedFormats(), "|")),
+	)
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	tokenAccessor := c.tokenAccessorID
+	switch rest := c.flags.Args(); {
+	case len(rest) > 1:
+		c.UI.Error("Too many arguments (expected at most 1)")
+		return 1
+	case len(rest) == 1 && tokenAccessor != "":
+		c.UI.Error("Cannot specify both the -accessor-id flag and an argument")
+		return 1
+	case len(rest) == 1:
+		tokenAccessor = rest[0]
+	}
+
+	if tokenAccessor == "" {
+		c.UI.Error("Cannot rotate a token without specifying its Accessor ID")
+		return 1
+	}
+
+	if c.gracePeriod < 0 {
+		c.UI.Error("The -grace-period must not be negative")
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	tok, err := acl.GetTokenAccessorIDFromPartial(client, tokenAccessor)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error determining token Accessor ID: %v", err))
+		return 1
+	}
+
+	t, _, err := client.ACL().TokenRotate(tok, c.gracePeriod, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error rotating token: %v", err))
+		return 1
+	}
+
+	formatter, err := token.NewFormatter(c.format, false)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	out, err := formatter.FormatToken(t)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if out != "" {
+		c.UI.Info(out)
+	}
+
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const (
+	synopsis = "Rotate the SecretID of an ACL token"
+	help     = `
+Usage: consul acl token rotate [options] [ACCESSOR_ID]
+
+    This command issues a new SecretID for an existing token. The token keeps its
+    Accessor ID, policies, roles and identities. The previous SecretID remains
+    valid for the grace period so that its users can switch to the new one.
+
+    Rotate a token, keeping the old SecretID valid for one hour:
+
+        $ consul acl token rotate 986193
+
+    Rotate a token and invalidate the old SecretID immediately:
+
+        $ consul acl token rotate -grace-period=0 -accessor-id 986193
+
+    Agents can pick up a rotated SecretID for their own tokens with:
+
+        $ curl -X PUT -H "X-Consul-Token: ..." http://127.0.0.1:8500/v1/agent/token/renew
+`
+)
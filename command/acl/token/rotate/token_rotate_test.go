@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokenrotate
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestTokenRotateCommand_noTabs(t *testing.T) {
+	t.Parallel()
+
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestTokenRotateCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, `
+   primary_datacenter = "dc1"
+   acl {
+      enabled = true
+      tokens {
+         initial_management = "root"
+      }
+   }`)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+
+	_, _, err := client.ACL().PolicyCreate(
+		&api.ACLPolicy{Name: "test-policy"},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	token, _, err := client.ACL().TokenCreate(
+		&api.ACLToken{Description: "test", Policies: []*api.ACLTokenPolicyLink{{Name: "test-policy"}}},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	t.Run("Missing accessor", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{"-http-addr=" + a.HTTPAddr(), "-token=root"})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Cannot rotate a token without specifying its Accessor ID")
+	})
+
+	t.Run("Grace period", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-format=json",
+			token.AccessorID,
+		}
+
+		code := cmd.Run(args)
+		require.Empty(t, ui.ErrorWriter.String())
+		require.Equal(t, 0, code)
+
+		var rotated api.ACLToken
+		require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &rotated))
+		require.Equal(t, token.AccessorID, rotated.AccessorID)
+		require.NotEqual(t, token.SecretID, rotated.SecretID)
+		require.NotNil(t, rotated.RotationTime)
+		require.NotNil(t, rotated.PreviousSecretExpirationTime)
+		require.Len(t, rotated.Policies, 1)
+
+		// Both the old and the new secret can be used to read the token.
+		for _, secret := range []string{token.SecretID, rotated.SecretID} {
+			self, _, err := client.ACL().TokenReadSelf(&api.QueryOptions{Token: secret})
+			require.NoError(t, err)
+			require.Equal(t, token.AccessorID, self.AccessorID)
+		}
+
+		token = &rotated
+	})
+
+	t.Run("Immediate", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-grace-period=0",
+			"-accessor-id=" + token.AccessorID,
+		}
+
+		code := cmd.Run(args)
+		require.Empty(t, ui.ErrorWriter.String())
+		require.Equal(t, 0, code)
+		require.Contains(t, ui.OutputWriter.String(), "Rotation Time:")
+		require.NotContains(t, ui.OutputWriter.String(), "Previous SecretID Expiration Time:")
+
+		_, _, err := client.ACL().TokenReadSelf(&api.QueryOptions{Token: token.SecretID})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ACL not found")
+	})
+}
@@ -56,5 +56,17 @@ Usage: consul acl token <subcommand> [options] [args]
 
     $ consul acl token delete -accessor-id 986193
 
+  Rotate the SecretID of a token
+
+    $ consul acl token rotate 986193
+
+  Issue a short-lived emergency token with global-management access
+
+    $ consul acl token break-glass -reason "Incident 4711" -approver-token-file approver.token
+
+  Show how a token issued by an auth method got its permissions
+
+    $ consul acl token lineage 986193
+
   For more examples, ask for subcommand help or view the documentation.
 `
@@ -44,5 +44,21 @@ Usage: consul connect ca <subcommand> [options] [args]
 
       $ consul connect ca set-config -config-file ca.json
 
+  Get the CSR for the next intermediate of an offline root CA:
+
+      $ consul connect ca csr -out intermediate.csr
+
+  Set the intermediate signed by the offline root CA:
+
+      $ consul connect ca set-intermediate -cert intermediate.pem
+
+  Revoke the leaf certificates of a service:
+
+      $ consul connect ca revoke -service web
+
+  Display the health of the CA and its certificate inventory:
+
+      $ consul connect ca status
+
   For more examples, ask for subcommand help or view the documentation.
 `
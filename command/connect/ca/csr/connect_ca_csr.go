@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package csr
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// flags
+	outFile string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.outFile, "out", "",
+		"Write the CSR to this file instead of printing it.")
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		c.UI.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	// Set up a client.
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	csr, _, err := client.Connect().CAIntermediateCSR(nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error generating intermediate CSR: %s", err))
+		return 1
+	}
+
+	if c.outFile == "" {
+		c.UI.Output(strings.TrimSpace(csr))
+		return 0
+	}
+
+	if err := os.WriteFile(c.outFile, []byte(csr), 0644); err != nil {
+		c.UI.Error(fmt.Sprintf("Error writing CSR: %s", err))
+		return 1
+	}
+	c.UI.Output(fmt.Sprintf("Intermediate CSR written to %s", c.outFile))
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Print the CSR for the next intermediate of an offline root CA"
+const help = `
+Usage: consul connect ca csr [options]
+
+  Prints the certificate signing request (CSR) for the next intermediate
+  certificate of the offline CA provider. Sign the CSR with the offline root
+  and upload the resulting certificate with "consul connect ca set-intermediate".
+
+  The same CSR is returned until an intermediate signed for it is uploaded.
+
+      $ consul connect ca csr -out intermediate.csr
+`
@@ -0,0 +1,124 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package revoke
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// flags
+	serial  string
+	service string
+	node    string
+	reason  string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.serial, "serial", "",
+		"The serial number of the leaf certificate to revoke, in hex. The colons "+
+			"are optional.")
+	c.flags.StringVar(&c.service, "service", "",
+		"Revoke the leaf certificates issued for this service.")
+	c.flags.StringVar(&c.node, "node", "",
+		"Revoke the leaf certificates requested by this node.")
+	c.flags.StringVar(&c.reason, "reason", "",
+		"A description of why the certificates are revoked, recorded with them.")
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		c.UI.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	if c.serial == "" && c.service == "" && c.node == "" {
+		c.UI.Error("At least one of the -serial, -service or -node flags is required")
+		return 1
+	}
+
+	// Set up a client.
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	req := &api.CARevokeRequest{
+		SerialNumber: c.serial,
+		Service:      c.service,
+		Node:         c.node,
+		Reason:       c.reason,
+	}
+	certs, _, err := client.Connect().CARevoke(req, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error revoking certificates: %s", err))
+		return 1
+	}
+
+	if len(certs) == 0 {
+		c.UI.Output("No certificate to revoke")
+		return 0
+	}
+	for _, cert := range certs {
+		if cert.Node != "" {
+			c.UI.Info(fmt.Sprintf("Revoked %s (service %q on node %q)", cert.SerialNumber, cert.Service, cert.Node))
+		} else {
+			c.UI.Info(fmt.Sprintf("Revoked %s (service %q)", cert.SerialNumber, cert.Service))
+		}
+	}
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Revoke leaf certificates issued by the Connect CA"
+const help = `
+Usage: consul connect ca revoke [options]
+
+  Revokes the unexpired leaf certificates issued in the datacenter that match
+  all of the given -serial, -service and -node flags. Revoked certificates are
+  added to the revocation list distributed to the proxies and are reported as
+  revoked over OCSP.
+
+  Revoke a single certificate:
+
+      $ consul connect ca revoke -serial 2a:5f:0c:01 -reason "key compromise"
+
+  Revoke the certificates of a service on a node:
+
+      $ consul connect ca revoke -service web -node node-1
+`
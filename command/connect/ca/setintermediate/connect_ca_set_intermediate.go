@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package setintermediate
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// flags
+	certFile string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.certFile, "cert", "",
+		"The path to the PEM-encoded intermediate certificate signed by the "+
+			"offline root. It may be followed by the certificates needed to chain "+
+			"it back to the root.")
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		c.UI.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	if c.certFile == "" {
+		c.UI.Error("The -cert flag is required")
+		return 1
+	}
+
+	certPEM, err := os.ReadFile(c.certFile)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading certificate file: %s", err))
+		return 1
+	}
+
+	// Set up a client.
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if _, err := client.Connect().CASetIntermediate(string(certPEM), nil); err != nil {
+		c.UI.Error(fmt.Sprintf("Error setting intermediate certificate: %s", err))
+		return 1
+	}
+	c.UI.Output("Intermediate certificate updated!")
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Upload an intermediate signed by an offline root CA"
+const help = `
+Usage: consul connect ca set-intermediate [options]
+
+  Uploads the intermediate certificate signed by the offline root for the CSR
+  from "consul connect ca csr". Consul validates that it chains to the active
+  root and was issued for the pending CSR, then signs new leaf certificates
+  with it.
+
+      $ consul connect ca set-intermediate -cert intermediate.pem
+`
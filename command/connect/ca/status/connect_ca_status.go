@@ -0,0 +1,157 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package status
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+const (
+	PrettyFormat string = "pretty"
+	JSONFormat   string = "json"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// flags
+	format string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.format, "format", PrettyFormat,
+		fmt.Sprintf("Output format {%s}", strings.Join([]string{PrettyFormat, JSONFormat}, "|")))
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		c.UI.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	if c.format != PrettyFormat && c.format != JSONFormat {
+		c.UI.Error(fmt.Sprintf("Invalid format, valid formats are {%s}", strings.Join([]string{PrettyFormat, JSONFormat}, "|")))
+		return 1
+	}
+
+	// Set up a client.
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	status, _, err := client.Connect().CAStatus(nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error querying CA status: %s", err))
+		return 1
+	}
+
+	if c.format == JSONFormat {
+		output, err := json.MarshalIndent(status, "", "    ")
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error encoding output: %s", err))
+			return 1
+		}
+		c.UI.Output(string(output))
+		return 0
+	}
+
+	c.UI.Output(formatStatus(status))
+	return 0
+}
+
+func formatStatus(status *api.CAStatus) string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString(fmt.Sprintf("Provider:             %s\n", status.Provider))
+	buffer.WriteString(fmt.Sprintf("State:                %s\n", status.State))
+	buffer.WriteString(fmt.Sprintf("Trust Domain:         %s\n", status.TrustDomain))
+	buffer.WriteString(fmt.Sprintf("Active Root ID:       %s\n", status.ActiveRootID))
+	buffer.WriteString(fmt.Sprintf("Rotation In Progress: %t\n", status.RotationInProgress))
+	if status.IntermediatePending {
+		buffer.WriteString("Intermediate Pending: true\n")
+	}
+
+	buffer.WriteString("\nRoots:\n")
+	for _, root := range status.Roots {
+		buffer.WriteString(fmt.Sprintf("   %s\n", root.ID))
+		buffer.WriteString(fmt.Sprintf("      Name:                  %s\n", root.Name))
+		buffer.WriteString(fmt.Sprintf("      Active:                %t\n", root.Active))
+		buffer.WriteString(fmt.Sprintf("      Expires:               %s\n", root.NotAfter.Format(time.RFC3339)))
+		if root.IntermediateNotAfter != nil {
+			buffer.WriteString(fmt.Sprintf("      Intermediate Expires:  %s\n", root.IntermediateNotAfter.Format(time.RFC3339)))
+		}
+		if root.RotatedOutAt != nil {
+			buffer.WriteString(fmt.Sprintf("      Rotated Out At:        %s\n", root.RotatedOutAt.Format(time.RFC3339)))
+		}
+		buffer.WriteString(fmt.Sprintf("      Leaf Certificates:     %d\n", root.LeafCerts))
+	}
+
+	if len(status.LocalProxiesOnInactiveRoots) > 0 {
+		buffer.WriteString("\nLocal Proxies On Inactive Roots:\n")
+		for _, proxy := range status.LocalProxiesOnInactiveRoots {
+			buffer.WriteString(fmt.Sprintf("   %s\n", proxy.ProxyID.ID))
+			if proxy.Node != "" {
+				buffer.WriteString(fmt.Sprintf("      Node:           %s\n", proxy.Node))
+			}
+			buffer.WriteString(fmt.Sprintf("      Root ID:        %s\n", proxy.LeafRootID))
+			buffer.WriteString(fmt.Sprintf("      Leaf Serial:    %s\n", proxy.LeafSerialNumber))
+			buffer.WriteString(fmt.Sprintf("      Leaf Expires:   %s\n", proxy.LeafValidBefore.Format(time.RFC3339)))
+		}
+	}
+
+	return buffer.String()
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Display the health of the Connect CA and its certificate inventory"
+const help = `
+Usage: consul connect ca status [options]
+
+  Displays the expiry of the trusted roots and of their intermediate, whether
+  a root rotation is in progress and the number of unexpired leaf certificates
+  signed by each root.
+
+  It also lists the proxies connected to the queried agent whose leaf
+  certificate is still signed by a root that is no longer active. Proxies
+  connected to other agents are not included, run it against each agent and
+  server to list all of them.
+
+      $ consul connect ca status
+`
@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package status
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestConnectCAStatusCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestConnectCAStatusCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	code := c.Run([]string{"-http-addr=" + a.HTTPAddr()})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Provider:             consul")
+	require.Contains(t, ui.OutputWriter.String(), "Rotation In Progress: false")
+
+	ui = cli.NewMockUi()
+	c = New(ui)
+	code = c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-format=json"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	var status api.CAStatus
+	require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &status))
+	require.Len(t, status.Roots, 1)
+	require.Equal(t, status.ActiveRootID, status.Roots[0].ID)
+	require.True(t, status.Roots[0].Active)
+}
+
+func TestConnectCAStatusCommand_InvalidFormat(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	code := c.Run([]string{"-format=yaml"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Invalid format")
+}
@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package login
+
+import (
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/hashicorp/consul/agent/consul/authmethod/certauth"
+	"github.com/hashicorp/consul/api"
+)
+
+// createCertBearerToken generates a bearer token for the cert auth method,
+// signed with the client certificate and key also used for HTTPS, as set by
+// the -client-cert and -client-key flags or their environment variables.
+func (c *cmd) createCertBearerToken() (string, error) {
+	cfg := api.DefaultConfig()
+	c.http.MergeOntoConfig(cfg)
+	if cfg.TLSConfig.CertFile == "" || cfg.TLSConfig.KeyFile == "" {
+		return "", fmt.Errorf("Missing required '-client-cert' and '-client-key' flags")
+	}
+
+	pair, err := tls.LoadX509KeyPair(cfg.TLSConfig.CertFile, cfg.TLSConfig.KeyFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to load client certificate: %w", err)
+	}
+
+	chain := make([]*x509.Certificate, 0, len(pair.Certificate))
+	for _, raw := range pair.Certificate {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse client certificate: %w", err)
+		}
+		chain = append(chain, cert)
+	}
+
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return "", fmt.Errorf("unsupported private key type %T", pair.PrivateKey)
+	}
+	return certauth.NewLoginToken(c.authMethodName, chain, key)
+}
@@ -39,7 +39,8 @@ type cmd struct {
 	tokenSinkFile   string
 	meta            map[string]string
 
-	aws AWSLogin
+	aws  AWSLogin
+	oidc OIDCLogin
 
 	enterpriseCmd
 }
@@ -51,7 +52,8 @@ func (c *cmd) init() {
 		"Name of the auth method to login to.")
 
 	c.flags.StringVar(&c.authMethodType, "type", "",
-		"Type of the auth method to login to. This field is optional and defaults to no type.")
+		"Type of the auth method to login to. This field is optional and defaults to no type. "+
+			"Required for the oidc and cert auth method types.")
 
 	c.flags.StringVar(&c.bearerTokenFile, "bearer-token-file", "",
 		"Path to a file containing a secret bearer token to use with this auth method.")
@@ -66,6 +68,7 @@ func (c *cmd) init() {
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.aws.flags())
+	flags.Merge(c.flags, c.oidc.flags())
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
 	flags.Merge(c.flags, c.http.MultiTenancyFlags())
@@ -111,6 +114,18 @@ func (c *cmd) bearerTokenLogin() int {
 		} else {
 			c.bearerToken = token
 		}
+	} else if c.authMethodType == "cert" {
+		if c.bearerTokenFile != "" {
+			c.UI.Error("Cannot use '-bearer-token-file' flag with '-type=cert'")
+			return 1
+		}
+
+		if token, err := c.createCertBearerToken(); err != nil {
+			c.UI.Error(fmt.Sprintf("Error with cert auth method: %s", err))
+			return 1
+		} else {
+			c.bearerToken = token
+		}
 	} else if c.bearerTokenFile == "" {
 		c.UI.Error("Missing required '-bearer-token-file' flag")
 		return 1
@@ -5,6 +5,7 @@ package login
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,11 +18,13 @@ import (
 
 	"github.com/hashicorp/consul-awsauth/iamauthtest"
 	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/agent/consul/authmethod/certauth"
 	"github.com/hashicorp/consul/agent/consul/authmethod/kubeauth"
 	"github.com/hashicorp/consul/agent/consul/authmethod/testauth"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/acl"
 	"github.com/hashicorp/consul/internal/go-sso/oidcauth/oidcauthtest"
+	"github.com/hashicorp/consul/sdk/freeport"
 	"github.com/hashicorp/consul/sdk/testutil"
 	"github.com/hashicorp/consul/testrpc"
 )
@@ -517,6 +520,210 @@ func TestLoginCommand_jwt(t *testing.T) {
 	}
 }
 
+func TestLoginCommand_oidc(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	testDir := testutil.TempDir(t, "acl")
+
+	a := newTestAgent(t)
+	client := a.Client()
+
+	tokenSinkFile := filepath.Join(testDir, "test.token")
+
+	listenAddr := fmt.Sprintf("127.0.0.1:%d", freeport.GetOne(t))
+	redirectURI := "http://" + listenAddr + "/oidc/callback"
+
+	// spin up a fake oidc server
+	oidcServer := oidcauthtest.Start(t)
+	oidcServer.SetClientCreds("abc", "def")
+	oidcServer.SetAllowedRedirectURIs([]string{redirectURI})
+	oidcServer.SetExpectedAuthCode("authcode")
+	oidcServer.SetCustomClaims(map[string]interface{}{
+		"first_name": "jeff2",
+	})
+
+	_, _, err := client.ACL().AuthMethodCreate(&api.ACLAuthMethod{
+		Name: "oidc",
+		Type: "oidc",
+		Config: map[string]interface{}{
+			"JWTSupportedAlgs":    []string{"ES256"},
+			"OIDCDiscoveryURL":    oidcServer.Addr(),
+			"OIDCDiscoveryCACert": oidcServer.CACert(),
+			"OIDCClientID":        "abc",
+			"OIDCClientSecret":    "def",
+			"AllowedRedirectURIs": []string{redirectURI},
+			"ClaimMappings":       map[string]string{"first_name": "name"},
+		},
+	}, &api.WriteOptions{Token: "root"})
+	require.NoError(t, err)
+
+	_, _, err = client.ACL().BindingRuleCreate(&api.ACLBindingRule{
+		AuthMethod: "oidc",
+		BindType:   api.BindingRuleBindTypeService,
+		BindName:   "test--${value.name}",
+		Selector:   "value.name == jeff2",
+	}, &api.WriteOptions{Token: "root"})
+	require.NoError(t, err)
+
+	t.Run("bearer token file not allowed", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-type=oidc",
+			"-method=oidc",
+			"-token-sink-file", tokenSinkFile,
+			"-bearer-token-file", filepath.Join(testDir, "bearer.token"),
+		})
+		require.Equal(t, 1, code, "err: %s", ui.ErrorWriter.String())
+		require.Contains(t, ui.ErrorWriter.String(), "Cannot use '-bearer-token-file' flag")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		defer os.Remove(tokenSinkFile)
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		// Simulate the browser: authenticate with the provider and follow
+		// its redirect to the command's callback listener.
+		cmd.oidc.openURL = func(authURL string) error {
+			redirect, err := oidcServer.Authorize(authURL)
+			if err != nil {
+				return err
+			}
+			resp, err := http.Get(redirect.String())
+			if err != nil {
+				return err
+			}
+			return resp.Body.Close()
+		}
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-type=oidc",
+			"-method=oidc",
+			"-oidc-callback-listen-addr=" + listenAddr,
+			"-token-sink-file", tokenSinkFile,
+		})
+		require.Equal(t, 0, code, "err: %s", ui.ErrorWriter.String())
+		require.Empty(t, ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), oidcServer.Addr()+"/auth?")
+
+		raw, err := os.ReadFile(tokenSinkFile)
+		require.NoError(t, err)
+
+		token := strings.TrimSpace(string(raw))
+		require.Len(t, token, 36, "must be a valid uid: %s", token)
+
+		tok, _, err := client.ACL().TokenReadSelf(&api.QueryOptions{Token: token})
+		require.NoError(t, err)
+		require.Equal(t, "oidc", tok.AuthMethod)
+		require.Len(t, tok.ServiceIdentities, 1)
+		require.Equal(t, "test--jeff2", tok.ServiceIdentities[0].ServiceName)
+	})
+}
+
+func TestLoginCommand_cert(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	testDir := testutil.TempDir(t, "acl")
+
+	a := newTestAgent(t)
+	client := a.Client()
+
+	tokenSinkFile := filepath.Join(testDir, "test.token")
+	certFile := filepath.Join(testDir, "client.pem")
+	keyFile := filepath.Join(testDir, "client-key.pem")
+
+	ca := certauth.NewTestCA(t)
+	certPEM, keyPEM := ca.IssueClientCert(t, "web-client", nil)
+	require.NoError(t, os.WriteFile(certFile, []byte(certPEM), 0600))
+	require.NoError(t, os.WriteFile(keyFile, []byte(keyPEM), 0600))
+
+	_, _, err := client.ACL().AuthMethodCreate(&api.ACLAuthMethod{
+		Name: "cert",
+		Type: "cert",
+		Config: map[string]interface{}{
+			"CACerts": []string{ca.CertPEM},
+		},
+	}, &api.WriteOptions{Token: "root"})
+	require.NoError(t, err)
+
+	_, _, err = client.ACL().BindingRuleCreate(&api.ACLBindingRule{
+		AuthMethod: "cert",
+		BindType:   api.BindingRuleBindTypeService,
+		BindName:   "${common_name}",
+	}, &api.WriteOptions{Token: "root"})
+	require.NoError(t, err)
+
+	t.Run("missing client cert", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-type=cert",
+			"-method=cert",
+			"-token-sink-file", tokenSinkFile,
+		})
+		require.Equal(t, 1, code, "err: %s", ui.ErrorWriter.String())
+		require.Contains(t, ui.ErrorWriter.String(), "Missing required '-client-cert' and '-client-key' flags")
+	})
+
+	t.Run("bearer token file not allowed", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-type=cert",
+			"-method=cert",
+			"-token-sink-file", tokenSinkFile,
+			"-bearer-token-file", filepath.Join(testDir, "bearer.token"),
+		})
+		require.Equal(t, 1, code, "err: %s", ui.ErrorWriter.String())
+		require.Contains(t, ui.ErrorWriter.String(), "Cannot use '-bearer-token-file' flag")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		defer os.Remove(tokenSinkFile)
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-type=cert",
+			"-method=cert",
+			"-client-cert", certFile,
+			"-client-key", keyFile,
+			"-token-sink-file", tokenSinkFile,
+		})
+		require.Equal(t, 0, code, "err: %s", ui.ErrorWriter.String())
+		require.Empty(t, ui.ErrorWriter.String())
+
+		raw, err := os.ReadFile(tokenSinkFile)
+		require.NoError(t, err)
+
+		token := strings.TrimSpace(string(raw))
+		require.Len(t, token, 36, "must be a valid uid: %s", token)
+
+		tok, _, err := client.ACL().TokenReadSelf(&api.QueryOptions{Token: token})
+		require.NoError(t, err)
+		require.Equal(t, "cert", tok.AuthMethod)
+		require.Len(t, tok.ServiceIdentities, 1)
+		require.Equal(t, "web-client", tok.ServiceIdentities[0].ServiceName)
+	})
+}
+
 func TestLoginCommand_aws_iam(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -0,0 +1,212 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package login
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/skratchdot/open-golang/open"
+
+	"github.com/hashicorp/consul/api"
+)
+
+const (
+	defaultOIDCCallbackListenAddr = "localhost:8550"
+
+	oidcCallbackPath = "/oidc/callback"
+)
+
+type OIDCLogin struct {
+	callbackListenAddr string
+	noBrowser          bool
+
+	// openURL opens the provider's auth URL in the user's browser. It
+	// defaults to open.Run and is replaced in tests.
+	openURL func(string) error
+}
+
+func (o *OIDCLogin) flags() *flag.FlagSet {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.StringVar(&o.callbackListenAddr, "oidc-callback-listen-addr", defaultOIDCCallbackListenAddr,
+		"The address to listen on for the redirect from the OIDC provider. The redirect URI "+
+			"http://<addr>/oidc/callback must be listed in the auth method's AllowedRedirectURIs. [oidc only]")
+
+	fs.BoolVar(&o.noBrowser, "oidc-no-browser", false,
+		"Print the provider's login URL instead of opening it in a browser. [oidc only]")
+	return fs
+}
+
+// oidcCallback holds the parameters the provider redirected the browser back
+// with.
+type oidcCallback struct {
+	state string
+	code  string
+	err   error
+}
+
+func (c *cmd) oidcLogin() int {
+	if c.bearerTokenFile != "" {
+		c.UI.Error("Cannot use '-bearer-token-file' flag with the oidc auth method type")
+		return 1
+	}
+
+	// Ensure that we don't try to use a token when performing a login
+	// operation.
+	c.http.SetToken("")
+	c.http.SetTokenFile("")
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	ln, err := net.Listen("tcp", c.oidc.callbackListenAddr)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error starting OIDC callback listener: %s", err))
+		return 1
+	}
+	defer ln.Close()
+
+	// Keep the configured host, which must match the auth method's allowed
+	// redirect URIs, but use the bound port in case port 0 was requested.
+	host, _, err := net.SplitHostPort(c.oidc.callbackListenAddr)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Invalid '-oidc-callback-listen-addr': %s", err))
+		return 1
+	}
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	redirectURI := "http://" + net.JoinHostPort(host, port) + oidcCallbackPath
+
+	clientNonce, err := uuid.GenerateUUID()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error generating client nonce: %s", err))
+		return 1
+	}
+
+	authURL, _, err := client.ACL().OIDCAuthURL(&api.ACLOIDCAuthURLParams{
+		AuthMethod:  c.authMethodName,
+		RedirectURI: redirectURI,
+		ClientNonce: clientNonce,
+		Meta:        c.meta,
+	}, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error fetching OIDC auth URL: %s", err))
+		return 1
+	}
+
+	callbackCh := make(chan oidcCallback, 1)
+	srv := &http.Server{Handler: oidcCallbackHandler(callbackCh)}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	if c.oidc.noBrowser {
+		c.UI.Output(fmt.Sprintf("Complete the login via your OIDC provider by visiting:\n\n    %s\n", authURL))
+	} else {
+		c.UI.Output(fmt.Sprintf("Complete the login via your OIDC provider. Launching browser to:\n\n    %s\n", authURL))
+		openURL := c.oidc.openURL
+		if openURL == nil {
+			openURL = open.Run
+		}
+		if err := openURL(authURL); err != nil {
+			c.UI.Warn(fmt.Sprintf("Error opening browser, visit the URL above to continue: %s", err))
+		}
+	}
+	c.UI.Output(fmt.Sprintf("Waiting for OIDC authentication to complete on %s...", redirectURI))
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	var cb oidcCallback
+	select {
+	case cb = <-callbackCh:
+	case <-ctx.Done():
+		c.UI.Error("Interrupted while waiting for OIDC authentication")
+		return 1
+	}
+	if cb.err != nil {
+		c.UI.Error(fmt.Sprintf("Error logging in: %s", cb.err))
+		return 1
+	}
+
+	tok, _, err := client.ACL().OIDCCallback(&api.ACLOIDCCallbackParams{
+		AuthMethod:  c.authMethodName,
+		State:       cb.state,
+		Code:        cb.code,
+		ClientNonce: clientNonce,
+	}, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error logging in: %s", err))
+		return 1
+	}
+
+	if err := c.writeToSink(tok); err != nil {
+		c.UI.Error(fmt.Sprintf("Error writing token to file sink: %s", err))
+		return 1
+	}
+
+	return 0
+}
+
+// oidcCallbackHandler returns a handler for the provider's redirect that
+// delivers the first callback it receives on ch.
+func oidcCallbackHandler(ch chan<- oidcCallback) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcCallbackPath, func(w http.ResponseWriter, req *http.Request) {
+		q := req.URL.Query()
+
+		var cb oidcCallback
+		switch {
+		case q.Get("error") != "":
+			cb.err = fmt.Errorf("OIDC provider returned an error: %s", q.Get("error"))
+			if desc := q.Get("error_description"); desc != "" {
+				cb.err = fmt.Errorf("%w: %s", cb.err, desc)
+			}
+		case q.Get("state") == "" || q.Get("code") == "":
+			cb.err = errors.New("OIDC provider redirect is missing the state or code parameter")
+		default:
+			cb.state, cb.code = q.Get("state"), q.Get("code")
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if cb.err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, oidcFailureHTML)
+		} else {
+			fmt.Fprint(w, oidcSuccessHTML)
+		}
+
+		select {
+		case ch <- cb:
+		default:
+		}
+	})
+	return mux
+}
+
+const oidcSuccessHTML = `<!DOCTYPE html>
+<html>
+<head><title>Consul Login</title></head>
+<body>
+<p>Signed in to Consul. You can close this window and return to the terminal.</p>
+</body>
+</html>
+`
+
+const oidcFailureHTML = `<!DOCTYPE html>
+<html>
+<head><title>Consul Login</title></head>
+<body>
+<p>Login to Consul failed. Return to the terminal for details.</p>
+</body>
+</html>
+`
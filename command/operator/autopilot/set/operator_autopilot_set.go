@@ -64,7 +64,7 @@ func (c *cmd) init() {
 			"it can perform a migration. Must be one of `true|false`.")
 	c.flags.Var(&c.upgradeVersionTag, "upgrade-version-tag",
 		"(Enterprise-only) The node_meta tag to use for version info when performing upgrade "+
-			"migrations. If left blank, the Consul version will be used.")
+			"migrations. If left blank, upgrade migrations are disabled.")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package datadir provides offline access to the Raft state stored in a
+// Consul server's data directory. The server using the data directory must be
+// stopped before any of these functions are used.
+package datadir
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+	raftwal "github.com/hashicorp/raft-wal"
+	"github.com/hashicorp/raft-wal/metadb"
+	"go.etcd.io/bbolt"
+)
+
+// RaftDir returns the directory holding the Raft state within a data dir.
+func RaftDir(dataDir string) string {
+	return filepath.Join(dataDir, "raft")
+}
+
+// LogStore is a Raft log store opened from a data dir.
+type LogStore interface {
+	raft.LogStore
+	io.Closer
+}
+
+// OpenLogStore opens the Raft log held in dataDir without modifying it. The
+// BoltDB store is opened read-only; the write-ahead log is used if there is no
+// raft.db, matching the server's own selection.
+func OpenLogStore(dataDir string) (LogStore, error) {
+	dir := RaftDir(dataDir)
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("no Raft state found in data dir %q: %w", dataDir, err)
+	}
+
+	boltFile := filepath.Join(dir, "raft.db")
+	if _, err := os.Stat(boltFile); err == nil {
+		store, err := raftboltdb.New(raftboltdb.Options{
+			Path: boltFile,
+			BoltOptions: &bbolt.Options{
+				ReadOnly: true,
+				// A running server holds an exclusive lock on the file.
+				Timeout: time.Second,
+			},
+		})
+		if errors.Is(err, bbolt.ErrTimeout) {
+			return nil, fmt.Errorf("timed out opening %s, make sure the Consul server using it is stopped", boltFile)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", boltFile, err)
+		}
+		return store, nil
+	}
+
+	walDir := filepath.Join(dir, "wal")
+	if _, err := os.Stat(walDir); err != nil {
+		return nil, fmt.Errorf("no raft.db or wal directory found in %s", dir)
+	}
+	return openWALCopy(walDir)
+}
+
+// walCopy is a write-ahead log opened from a private copy of its directory.
+type walCopy struct {
+	*raftwal.WAL
+	dir string
+}
+
+// openWALCopy opens a copy of the write-ahead log in walDir. The WAL library
+// has no read-only mode: opening a WAL recovers its tail segment, may commit
+// new metadata and deletes unused segment files. Working on a copy guarantees
+// that inspecting the data dir of a live server can't modify it.
+func openWALCopy(walDir string) (*walCopy, error) {
+	dir, err := os.MkdirTemp("", "consul-raft-wal-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a temporary directory: %w", err)
+	}
+	if err := copyDir(walDir, dir); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to copy write-ahead log %s: %w", walDir, err)
+	}
+	wal, err := raftwal.Open(dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to open write-ahead log %s: %w", walDir, err)
+	}
+	return &walCopy{WAL: wal, dir: dir}, nil
+}
+
+// Close closes the write-ahead log and removes the copy.
+func (w *walCopy) Close() error {
+	err := w.WAL.Close()
+	if rmErr := os.RemoveAll(w.dir); err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+// copyDir copies the regular files of src into dst. The metadata file is copied
+// first so every segment it references is copied even if the server appends
+// to the log in the meantime.
+func copyDir(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Name() == metadb.FileName && entries[j].Name() != metadb.FileName
+	})
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		if err := copyFile(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,201 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package datadir
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+	raftwal "github.com/hashicorp/raft-wal"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/snapshot"
+)
+
+const (
+	testNodeID1 = "5e9a9ef4-6e79-4c8b-9d1c-4f0b8d3f8b01"
+	testNodeID2 = "5e9a9ef4-6e79-4c8b-9d1c-4f0b8d3f8b02"
+)
+
+// testDataDir creates a data dir holding a BoltDB Raft log with a
+// configuration entry followed by a few KV writes.
+func testDataDir(t *testing.T) string {
+	t.Helper()
+
+	dataDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(RaftDir(dataDir), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "node-id"), []byte(testNodeID1+"\n"), 0600))
+
+	store, err := raftboltdb.New(raftboltdb.Options{Path: filepath.Join(RaftDir(dataDir), "raft.db")})
+	require.NoError(t, err)
+	defer store.Close()
+
+	configuration := raft.Configuration{Servers: []raft.Server{
+		{Suffrage: raft.Voter, ID: testNodeID1, Address: "10.0.0.1:8300"},
+		{Suffrage: raft.Voter, ID: testNodeID2, Address: "10.0.0.2:8300"},
+	}}
+	logs := []*raft.Log{
+		{Index: 1, Term: 1, Type: raft.LogConfiguration, Data: raft.EncodeConfiguration(configuration)},
+	}
+	for i, key := range []string{"a", "b", "c"} {
+		data, err := structs.Encode(structs.KVSRequestType, &structs.KVSRequest{
+			Datacenter: "dc1",
+			Op:         "set",
+			DirEnt:     structs.DirEntry{Key: key, Value: []byte(key)},
+		})
+		require.NoError(t, err)
+		logs = append(logs, &raft.Log{Index: uint64(i + 2), Term: 2, Type: raft.LogCommand, Data: data})
+	}
+	require.NoError(t, store.StoreLogs(logs))
+	return dataDir
+}
+
+func TestReadState(t *testing.T) {
+	dataDir := testDataDir(t)
+
+	state, err := ReadState(dataDir)
+	require.NoError(t, err)
+	require.Equal(t, testNodeID1, state.NodeID)
+	require.Equal(t, uint64(1), state.FirstIndex)
+	require.Equal(t, uint64(4), state.LastIndex)
+	require.Equal(t, uint64(2), state.LastTerm)
+	require.Empty(t, state.SnapshotID)
+	require.Equal(t, uint64(1), state.ConfigurationIndex)
+	require.Equal(t, []Server{
+		{ID: testNodeID1, Address: "10.0.0.1:8300", Suffrage: "Voter"},
+		{ID: testNodeID2, Address: "10.0.0.2:8300", Suffrage: "Voter"},
+	}, state.Configuration)
+
+	_, err = ReadState(t.TempDir())
+	require.ErrorContains(t, err, "no Raft state found")
+}
+
+func TestOpenLogStore_WALIsNotModified(t *testing.T) {
+	dataDir := t.TempDir()
+	walDir := filepath.Join(RaftDir(dataDir), "wal")
+	require.NoError(t, os.MkdirAll(walDir, 0755))
+
+	wal, err := raftwal.Open(walDir)
+	require.NoError(t, err)
+	require.NoError(t, wal.StoreLogs([]*raft.Log{
+		{Index: 1, Term: 1, Type: raft.LogCommand, Data: []byte("a")},
+		{Index: 2, Term: 1, Type: raft.LogCommand, Data: []byte("b")},
+	}))
+	require.NoError(t, wal.Close())
+
+	readDir := func() map[string][]byte {
+		files := make(map[string][]byte)
+		entries, err := os.ReadDir(walDir)
+		require.NoError(t, err)
+		for _, e := range entries {
+			b, err := os.ReadFile(filepath.Join(walDir, e.Name()))
+			require.NoError(t, err)
+			files[e.Name()] = b
+		}
+		return files
+	}
+	before := readDir()
+
+	store, err := OpenLogStore(dataDir)
+	require.NoError(t, err)
+	last, err := store.LastIndex()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), last)
+	var log raft.Log
+	require.NoError(t, store.GetLog(2, &log))
+	require.Equal(t, []byte("b"), log.Data)
+	require.NoError(t, store.Close())
+
+	require.Equal(t, before, readDir())
+}
+
+func TestMostUpToDate(t *testing.T) {
+	a := &State{NodeID: "a", LastTerm: 3, LastIndex: 10}
+	b := &State{NodeID: "b", LastTerm: 4, LastIndex: 8}
+	c := &State{NodeID: "c", LastTerm: 4, LastIndex: 9}
+
+	require.Nil(t, MostUpToDate(nil))
+	require.Equal(t, c, MostUpToDate([]*State{a, b, c}))
+	require.Equal(t, a, MostUpToDate([]*State{a}))
+}
+
+func TestPeersJSON(t *testing.T) {
+	cases := map[string]struct {
+		servers []Server
+		err     string
+	}{
+		"ok": {
+			servers: []Server{{ID: testNodeID1, Address: "10.0.0.1:8300"}, {ID: testNodeID2, Address: "10.0.0.2:8300", Suffrage: "Nonvoter"}},
+		},
+		"empty": {
+			err: "at least one server",
+		},
+		"bad id": {
+			servers: []Server{{ID: "10.0.0.1:8300", Address: "10.0.0.1:8300"}},
+			err:     "not a valid node ID",
+		},
+		"bad address": {
+			servers: []Server{{ID: testNodeID1, Address: "10.0.0.1"}},
+			err:     "host:port",
+		},
+		"duplicate id": {
+			servers: []Server{{ID: testNodeID1, Address: "10.0.0.1:8300"}, {ID: testNodeID1, Address: "10.0.0.2:8300"}},
+			err:     "duplicate server ID",
+		},
+		"no voters": {
+			servers: []Server{{ID: testNodeID1, Address: "10.0.0.1:8300", Suffrage: "Nonvoter"}},
+			err:     "must be a voter",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := PeersJSON(tc.servers)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestWritePeersJSON(t *testing.T) {
+	dataDir := testDataDir(t)
+
+	path, err := WritePeersJSON(dataDir, []Server{
+		{ID: testNodeID1, Address: "10.0.0.1:8300"},
+		{ID: testNodeID2, Address: "10.0.0.2:8300", Suffrage: "Nonvoter"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(RaftDir(dataDir), "peers.json"), path)
+
+	configuration, err := raft.ReadConfigJSON(path)
+	require.NoError(t, err)
+	require.Equal(t, []raft.Server{
+		{Suffrage: raft.Voter, ID: testNodeID1, Address: "10.0.0.1:8300"},
+		{Suffrage: raft.Nonvoter, ID: testNodeID2, Address: "10.0.0.2:8300"},
+	}, configuration.Servers)
+}
+
+func TestExportSnapshot(t *testing.T) {
+	dataDir := testDataDir(t)
+
+	var buf bytes.Buffer
+	meta, err := ExportSnapshot(dataDir, &buf, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), meta.Index)
+	require.Equal(t, uint64(2), meta.Term)
+	require.Equal(t, uint64(1), meta.ConfigurationIndex)
+	require.Len(t, meta.Configuration.Servers, 2)
+
+	verified, err := snapshot.Verify(&buf)
+	require.NoError(t, err)
+	require.Equal(t, meta.Index, verified.Index)
+	require.Equal(t, meta.Size, verified.Size)
+}
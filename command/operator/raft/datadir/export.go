@@ -0,0 +1,183 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package datadir
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+
+	"github.com/hashicorp/consul/agent/consul/fsm"
+	"github.com/hashicorp/consul/agent/consul/state"
+	raftstorage "github.com/hashicorp/consul/internal/storage/raft"
+	"github.com/hashicorp/consul/snapshot"
+)
+
+// ExportSnapshot rebuilds the state held in dataDir by restoring its latest
+// snapshot and applying every log entry after it, then writes the result to
+// out as a snapshot archive that can be restored with "consul snapshot
+// restore". It returns the metadata of the exported snapshot.
+func ExportSnapshot(dataDir string, out io.Writer, logger hclog.Logger) (*raft.SnapshotMeta, error) {
+	if logger == nil {
+		logger = hclog.NewNullLogger()
+	}
+
+	store, err := OpenLogStore(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	// It's safe to pass nil as the handle argument here because we won't call
+	// the backend's data access methods (only Apply, Snapshot, and Restore).
+	backend, err := raftstorage.NewBackend(nil, logger)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go backend.Run(ctx)
+
+	f := fsm.NewFromDeps(fsm.Deps{
+		Logger: logger,
+		NewStateStore: func() *state.Store {
+			return state.NewStateStore(nil)
+		},
+		StorageBackend: backend,
+	})
+
+	meta, err := restoreLatestSnapshot(dataDir, f)
+	if err != nil {
+		return nil, err
+	}
+
+	first, err := store.FirstIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read first log index: %w", err)
+	}
+	last, err := store.LastIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read last log index: %w", err)
+	}
+
+	start := uint64(1)
+	term := uint64(0)
+	if meta != nil {
+		start, term = meta.Index+1, meta.Term
+	}
+	if last >= start && first > start {
+		return nil, fmt.Errorf("log starts at index %d but entries from index %d are needed to rebuild the state", first, start)
+	}
+
+	configuration, configurationIndex, err := lastConfiguration(store, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	index := start - 1
+	applier := f.ChunkingFSM()
+	for idx := start; idx <= last; idx++ {
+		var l raft.Log
+		if err := store.GetLog(idx, &l); err != nil {
+			return nil, fmt.Errorf("failed to read log at index %d: %w", idx, err)
+		}
+		if l.Type == raft.LogCommand {
+			if err := applyLog(applier, &l); err != nil {
+				return nil, err
+			}
+		}
+		index, term = l.Index, l.Term
+	}
+
+	// Persist the rebuilt state to a scratch file so that we know its size
+	// before writing the archive.
+	scratch, err := os.CreateTemp("", "consul-recovered-snapshot")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer os.Remove(scratch.Name())
+	defer scratch.Close()
+
+	fsmSnap, err := f.Snapshot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot recovered state: %w", err)
+	}
+	defer fsmSnap.Release()
+	if err := fsmSnap.Persist(&fileSink{File: scratch}); err != nil {
+		return nil, fmt.Errorf("failed to persist recovered state: %w", err)
+	}
+	size, err := scratch.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := scratch.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	exported := &raft.SnapshotMeta{
+		Version:            raft.SnapshotVersionMax,
+		ID:                 fmt.Sprintf("recovered-%d-%d", term, index),
+		Index:              index,
+		Term:               term,
+		Configuration:      configuration,
+		ConfigurationIndex: configurationIndex,
+		Size:               size,
+	}
+	if err := snapshot.Write(out, exported, scratch); err != nil {
+		return nil, err
+	}
+	return exported, nil
+}
+
+// restoreLatestSnapshot restores the newest snapshot in dataDir into f and
+// returns its metadata, or nil if there are no snapshots.
+func restoreLatestSnapshot(dataDir string, f *fsm.FSM) (*raft.SnapshotMeta, error) {
+	snaps, err := openSnapshotStore(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	list, err := snaps.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	meta, rc, err := snaps.Open(list[0].ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot %s: %w", list[0].ID, err)
+	}
+	// Restore closes the reader.
+	if err := f.ChunkingFSM().Restore(rc); err != nil {
+		return nil, fmt.Errorf("failed to restore snapshot %s: %w", meta.ID, err)
+	}
+	return meta, nil
+}
+
+// applyLog applies a single log entry, converting the panic the FSM raises on
+// entries it cannot decode into an error.
+func applyLog(f raft.FSM, l *raft.Log) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to apply log at index %d: %v", l.Index, r)
+		}
+	}()
+	f.Apply(l)
+	return nil
+}
+
+// fileSink is a raft.SnapshotSink that writes to a file which is left open
+// for reading back once the snapshot is persisted.
+type fileSink struct {
+	*os.File
+}
+
+func (s *fileSink) ID() string    { return "export" }
+func (s *fileSink) Cancel() error { return nil }
+func (s *fileSink) Close() error  { return s.File.Sync() }
@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package datadir
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/raft"
+)
+
+// peersEntry is the format of a single server in peers.json, as read by
+// raft.ReadConfigJSON.
+type peersEntry struct {
+	ID       string `json:"id"`
+	Address  string `json:"address"`
+	NonVoter bool   `json:"non_voter"`
+}
+
+// PeersJSON encodes servers in the peers.json format used to recover a Raft
+// configuration, after checking that the result would be accepted by the
+// server. Servers with any suffrage other than "Voter" are written as
+// non-voters.
+func PeersJSON(servers []Server) ([]byte, error) {
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("at least one server is required")
+	}
+
+	var (
+		peers         []peersEntry
+		configuration raft.Configuration
+	)
+	for _, s := range servers {
+		if _, err := uuid.ParseUUID(s.ID); err != nil {
+			return nil, fmt.Errorf("server ID %q is not a valid node ID: %w", s.ID, err)
+		}
+		if _, port, err := net.SplitHostPort(s.Address); err != nil || port == "" {
+			return nil, fmt.Errorf("server address %q must be in the form host:port", s.Address)
+		}
+
+		nonVoter := s.Suffrage != "" && s.Suffrage != raft.Voter.String()
+		suffrage := raft.Voter
+		if nonVoter {
+			suffrage = raft.Nonvoter
+		}
+		peers = append(peers, peersEntry{ID: s.ID, Address: s.Address, NonVoter: nonVoter})
+		configuration.Servers = append(configuration.Servers, raft.Server{
+			Suffrage: suffrage,
+			ID:       raft.ServerID(s.ID),
+			Address:  raft.ServerAddress(s.Address),
+		})
+	}
+
+	if err := checkConfiguration(configuration); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(peers, "", "  ")
+}
+
+// checkConfiguration applies the same rules Raft uses when reading
+// peers.json.
+func checkConfiguration(configuration raft.Configuration) error {
+	ids := make(map[raft.ServerID]struct{})
+	addresses := make(map[raft.ServerAddress]struct{})
+	voters := 0
+	for _, s := range configuration.Servers {
+		if _, ok := ids[s.ID]; ok {
+			return fmt.Errorf("found duplicate server ID %q", s.ID)
+		}
+		ids[s.ID] = struct{}{}
+		if _, ok := addresses[s.Address]; ok {
+			return fmt.Errorf("found duplicate server address %q", s.Address)
+		}
+		addresses[s.Address] = struct{}{}
+		if s.Suffrage == raft.Voter {
+			voters++
+		}
+	}
+	if voters == 0 {
+		return fmt.Errorf("at least one server must be a voter")
+	}
+	return nil
+}
+
+// WritePeersJSON writes a validated peers.json for servers into the Raft
+// directory of dataDir and returns its path. The server reads and removes the
+// file the next time it starts.
+func WritePeersJSON(dataDir string, servers []Server) (string, error) {
+	buf, err := PeersJSON(servers)
+	if err != nil {
+		return "", err
+	}
+
+	dir := RaftDir(dataDir)
+	if _, err := os.Stat(dir); err != nil {
+		return "", fmt.Errorf("no Raft state found in data dir %q: %w", dataDir, err)
+	}
+
+	// Write to a temporary file and rename so a partially written file is
+	// never picked up by a server.
+	path := filepath.Join(dir, "peers.json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf, 0600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if _, err := raft.ReadConfigJSON(tmp); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("generated peers.json is invalid: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
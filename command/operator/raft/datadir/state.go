@@ -0,0 +1,199 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package datadir
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+)
+
+// Server is a member of a Raft configuration.
+type Server struct {
+	ID       string
+	Address  string
+	Suffrage string
+}
+
+// State summarizes the Raft state held in a server's data dir. It is meant to
+// be encoded as JSON so the state of every surviving server can be gathered
+// and compared in one place when recovering from a loss of quorum.
+type State struct {
+	// NodeID is the contents of the data dir's node-id file, which is the
+	// server's Raft ID with Raft protocol 3.
+	NodeID string `json:",omitempty"`
+
+	// DataDir is the data dir the state was read from.
+	DataDir string
+
+	// FirstIndex, LastIndex and LastTerm describe the entries in the log.
+	FirstIndex uint64
+	LastIndex  uint64
+	LastTerm   uint64
+
+	// SnapshotID, SnapshotIndex and SnapshotTerm identify the latest
+	// snapshot, if there is one.
+	SnapshotID    string `json:",omitempty"`
+	SnapshotIndex uint64
+	SnapshotTerm  uint64
+
+	// Configuration is the last Raft configuration known to the server, from
+	// either its log or its latest snapshot, and ConfigurationIndex is the
+	// index at which it was written.
+	Configuration      []Server
+	ConfigurationIndex uint64
+}
+
+// ReadState reads a summary of the Raft state held in dataDir.
+func ReadState(dataDir string) (*State, error) {
+	store, err := OpenLogStore(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	state := &State{DataDir: dataDir}
+	if id, err := os.ReadFile(filepath.Join(dataDir, "node-id")); err == nil {
+		state.NodeID = strings.TrimSpace(string(id))
+	}
+
+	if state.FirstIndex, err = store.FirstIndex(); err != nil {
+		return nil, fmt.Errorf("failed to read first log index: %w", err)
+	}
+	if state.LastIndex, err = store.LastIndex(); err != nil {
+		return nil, fmt.Errorf("failed to read last log index: %w", err)
+	}
+	if state.LastIndex > 0 {
+		var l raft.Log
+		if err := store.GetLog(state.LastIndex, &l); err != nil {
+			return nil, fmt.Errorf("failed to read log at index %d: %w", state.LastIndex, err)
+		}
+		state.LastTerm = l.Term
+	}
+
+	meta, err := latestSnapshot(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	if meta != nil {
+		state.SnapshotID = meta.ID
+		state.SnapshotIndex = meta.Index
+		state.SnapshotTerm = meta.Term
+		if meta.Index > state.LastIndex {
+			state.LastIndex, state.LastTerm = meta.Index, meta.Term
+		}
+	}
+
+	configuration, index, err := lastConfiguration(store, meta)
+	if err != nil {
+		return nil, err
+	}
+	state.ConfigurationIndex = index
+	for _, s := range configuration.Servers {
+		state.Configuration = append(state.Configuration, Server{
+			ID:       string(s.ID),
+			Address:  string(s.Address),
+			Suffrage: s.Suffrage.String(),
+		})
+	}
+	return state, nil
+}
+
+// MostUpToDate returns the state with the most recent log, which is the
+// best server to keep when recovering from a loss of quorum. The log with the
+// highest last term wins, then the one with the highest last index.
+func MostUpToDate(states []*State) *State {
+	if len(states) == 0 {
+		return nil
+	}
+	sorted := make([]*State, len(states))
+	copy(sorted, states)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].LastTerm != sorted[j].LastTerm {
+			return sorted[i].LastTerm > sorted[j].LastTerm
+		}
+		return sorted[i].LastIndex > sorted[j].LastIndex
+	})
+	return sorted[0]
+}
+
+func openSnapshotStore(dataDir string) (*raft.FileSnapshotStore, error) {
+	snaps, err := raft.NewFileSnapshotStoreWithLogger(RaftDir(dataDir), 1, hclog.NewNullLogger())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot store: %w", err)
+	}
+	return snaps, nil
+}
+
+// latestSnapshot returns the metadata of the newest snapshot in dataDir, or
+// nil if there isn't one.
+func latestSnapshot(dataDir string) (*raft.SnapshotMeta, error) {
+	snaps, err := openSnapshotStore(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	list, err := snaps.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return list[0], nil
+}
+
+// lastConfiguration finds the newest Raft configuration in the log, falling
+// back to the one recorded in the snapshot.
+func lastConfiguration(store raft.LogStore, meta *raft.SnapshotMeta) (raft.Configuration, uint64, error) {
+	first, err := store.FirstIndex()
+	if err != nil {
+		return raft.Configuration{}, 0, err
+	}
+	last, err := store.LastIndex()
+	if err != nil {
+		return raft.Configuration{}, 0, err
+	}
+
+	var min uint64 = first
+	if meta != nil && meta.Index > min {
+		min = meta.Index
+	}
+	for idx := last; idx > 0 && idx >= min; idx-- {
+		var l raft.Log
+		if err := store.GetLog(idx, &l); err != nil {
+			if err == raft.ErrLogNotFound {
+				continue
+			}
+			return raft.Configuration{}, 0, fmt.Errorf("failed to read log at index %d: %w", idx, err)
+		}
+		if l.Type != raft.LogConfiguration {
+			continue
+		}
+		configuration, err := decodeConfiguration(l.Data)
+		if err != nil {
+			return raft.Configuration{}, 0, fmt.Errorf("failed to decode configuration at index %d: %w", idx, err)
+		}
+		return configuration, idx, nil
+	}
+
+	if meta != nil {
+		return meta.Configuration, meta.ConfigurationIndex, nil
+	}
+	return raft.Configuration{}, 0, nil
+}
+
+func decodeConfiguration(buf []byte) (configuration raft.Configuration, err error) {
+	// DecodeConfiguration panics on corrupt data.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return raft.DecodeConfiguration(buf), nil
+}
@@ -0,0 +1,237 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package raftlog
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
+
+	"github.com/hashicorp/consul/agent/consul/fsm"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/operator/raft/datadir"
+)
+
+const (
+	PrettyFormat string = "pretty"
+	JSONFormat   string = "json"
+
+	// maxSummaryLen is the length at which request bodies are truncated in
+	// the pretty output format.
+	maxSummaryLen = 120
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// flags
+	minIndex uint64
+	maxIndex uint64
+	types    []string
+	limit    int
+	dataDir  string
+	format   string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.Uint64Var(&c.minIndex, "min-index", 0,
+		"Only show entries with an index greater than or equal to this value.")
+	c.flags.Uint64Var(&c.maxIndex, "max-index", 0,
+		"Only show entries with an index less than or equal to this value.")
+	c.flags.Var((*flags.AppendSliceValue)(&c.types), "type",
+		"Only show entries of this message type (such as KVS or Register) or Raft "+
+			"log type (such as LogConfiguration). May be specified multiple times.")
+	c.flags.IntVar(&c.limit, "limit", 0,
+		"Maximum number of entries to show, newest first. Defaults to 100.")
+	c.flags.StringVar(&c.dataDir, "data-dir", "",
+		"Read the log directly from this server data directory instead of from a "+
+			"running server. The server using the directory must be stopped.")
+	c.flags.StringVar(&c.format, "format", PrettyFormat,
+		fmt.Sprintf("Output format {%s|%s}", PrettyFormat, JSONFormat))
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		c.UI.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	if c.format != PrettyFormat && c.format != JSONFormat {
+		c.UI.Error(fmt.Sprintf("Invalid format %q, must be one of %s or %s", c.format, PrettyFormat, JSONFormat))
+		return 1
+	}
+	if c.maxIndex != 0 && c.minIndex > c.maxIndex {
+		c.UI.Error("-min-index must not be greater than -max-index")
+		return 1
+	}
+
+	var (
+		result *api.RaftLog
+		err    error
+	)
+	if c.dataDir != "" {
+		result, err = c.readDataDir()
+	} else {
+		result, err = c.readServer()
+	}
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading Raft log: %v", err))
+		return 1
+	}
+
+	if c.format == JSONFormat {
+		b, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error encoding output: %s", err))
+			return 1
+		}
+		c.UI.Output(string(b))
+		return 0
+	}
+
+	c.UI.Output(formatLog(result))
+	return 0
+}
+
+func (c *cmd) readServer() (*api.RaftLog, error) {
+	client, err := c.http.APIClient()
+	if err != nil {
+		return nil, fmt.Errorf("error initializing client: %w", err)
+	}
+	opts := &api.RaftLogOptions{
+		MinIndex: c.minIndex,
+		MaxIndex: c.maxIndex,
+		Types:    c.types,
+		Limit:    c.limit,
+	}
+	return client.Operator().RaftGetLog(opts, &api.QueryOptions{AllowStale: c.http.Stale()})
+}
+
+func (c *cmd) readDataDir() (*api.RaftLog, error) {
+	store, err := datadir.OpenLogStore(c.dataDir)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	resp, err := fsm.ReadLogs(store, structs.RaftLogFilter{
+		MinIndex: c.minIndex,
+		MaxIndex: c.maxIndex,
+		Types:    c.types,
+		Limit:    c.limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := &api.RaftLog{
+		FirstIndex: resp.FirstIndex,
+		LastIndex:  resp.LastIndex,
+	}
+	for _, e := range resp.Entries {
+		out.Entries = append(out.Entries, &api.RaftLogEntry{
+			Index:       e.Index,
+			Term:        e.Term,
+			LogType:     e.LogType,
+			MessageType: e.MessageType,
+			AppendedAt:  e.AppendedAt,
+			Size:        e.Size,
+			Chunked:     e.Chunked,
+			Body:        e.Body,
+			Error:       e.Error,
+		})
+	}
+	return out, nil
+}
+
+func formatLog(log *api.RaftLog) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Log index range: %d - %d, showing %d entries\n\n", log.FirstIndex, log.LastIndex, len(log.Entries))
+
+	result := []string{"Index\x1fTerm\x1fType\x1fSize\x1fSummary"}
+	for _, e := range log.Entries {
+		typ := e.LogType
+		if e.MessageType != "" {
+			typ = e.MessageType
+		}
+		result = append(result, fmt.Sprintf("%d\x1f%d\x1f%s\x1f%d\x1f%s",
+			e.Index, e.Term, typ, e.Size, summarize(e)))
+	}
+	b.WriteString(columnize.Format(result, &columnize.Config{Delim: string([]byte{0x1f})}))
+	return b.String()
+}
+
+func summarize(e *api.RaftLogEntry) string {
+	switch {
+	case e.Error != "":
+		return "error: " + e.Error
+	case e.Chunked:
+		return "(chunk of a larger entry)"
+	case len(e.Body) == 0:
+		return "-"
+	}
+	b, err := json.Marshal(e.Body)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	s := string(b)
+	if len(s) > maxSummaryLen {
+		s = s[:maxSummaryLen-3] + "..."
+	}
+	return s
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Display and decode recent Raft log entries"
+const help = `
+Usage: consul operator raft log [options]
+
+  Lists recent entries in the Raft log, decoding each command into its
+  message type and a summary of the request. Secrets, private keys and KV
+  values are redacted.
+
+  By default the leader's log is read through the HTTP API, which requires
+  an ACL token with operator:write. Use -stale to read the log of the agent's
+  server instead, or -data-dir to read the log of a stopped server directly
+  from disk.
+
+  Show the 20 most recent KV writes:
+
+      $ consul operator raft log -type KVS -limit 20
+
+  Show an index range from an offline server:
+
+      $ consul operator raft log -data-dir /opt/consul -min-index 1000 -max-index 1100
+`
@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package raftlog
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+)
+
+func TestOperatorRaftLogCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestOperatorRaftLogCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+
+	client := a.Client()
+	_, err := client.KV().Put(&api.KVPair{Key: "app/config", Value: []byte("secret-value")}, nil)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	code := c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-type=KVS", "-format=json"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	var out api.RaftLog
+	require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &out))
+	require.Len(t, out.Entries, 1)
+	require.Equal(t, "KVS", out.Entries[0].MessageType)
+	require.NotContains(t, ui.OutputWriter.String(), "secret-value")
+
+	dirEnt := out.Entries[0].Body["DirEnt"].(map[string]interface{})
+	require.Equal(t, "app/config", dirEnt["Key"])
+
+	ui = cli.NewMockUi()
+	c = New(ui)
+	code = c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-type=KVS"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "app/config")
+}
+
+func TestOperatorRaftLogCommand_invalidArgs(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	require.Equal(t, 1, c.Run([]string{"-format=yaml"}))
+	require.Contains(t, ui.ErrorWriter.String(), "Invalid format")
+
+	ui = cli.NewMockUi()
+	c = New(ui)
+	require.Equal(t, 1, c.Run([]string{"-min-index=10", "-max-index=5"}))
+	require.Contains(t, ui.ErrorWriter.String(), "-min-index")
+
+	ui = cli.NewMockUi()
+	c = New(ui)
+	require.Equal(t, 1, c.Run([]string{"-data-dir=" + t.TempDir()}))
+	require.Contains(t, ui.ErrorWriter.String(), "no Raft state found")
+}
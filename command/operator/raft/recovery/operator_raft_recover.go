@@ -0,0 +1,269 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package recovery
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/operator/raft/datadir"
+)
+
+const (
+	PrettyFormat string = "pretty"
+	JSONFormat   string = "json"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	help  string
+
+	// flags
+	dataDir        string
+	states         []string
+	peers          []string
+	writePeers     bool
+	exportSnapshot string
+	format         string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.dataDir, "data-dir", "",
+		"Path to the data directory of the stopped server to inspect. Required.")
+	c.flags.Var((*flags.AppendSliceValue)(&c.states), "state",
+		"Path to a JSON file produced by running this command with -format=json "+
+			"on another surviving server. May be specified multiple times.")
+	c.flags.Var((*flags.AppendSliceValue)(&c.peers), "peer",
+		"A server to include in the recovered configuration, in the form "+
+			"ID=IP:port. May be specified multiple times. Defaults to the servers "+
+			"whose state was given, at their last known addresses.")
+	c.flags.BoolVar(&c.writePeers, "write-peers", false,
+		"Write a validated peers.json into the data directory. The server "+
+			"recovers its Raft configuration from it on the next start.")
+	c.flags.StringVar(&c.exportSnapshot, "export-snapshot", "",
+		"Rebuild the server's state from its latest snapshot and log and write "+
+			"it to this path as a snapshot archive.")
+	c.flags.StringVar(&c.format, "format", PrettyFormat,
+		fmt.Sprintf("Output format {%s|%s}", PrettyFormat, JSONFormat))
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		c.UI.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	if c.dataDir == "" {
+		c.UI.Error("Missing required -data-dir flag")
+		return 1
+	}
+	if c.format != PrettyFormat && c.format != JSONFormat {
+		c.UI.Error(fmt.Sprintf("Invalid format %q, must be one of %s or %s", c.format, PrettyFormat, JSONFormat))
+		return 1
+	}
+	if len(c.peers) > 0 && !c.writePeers {
+		c.UI.Error("-peer can only be used with -write-peers")
+		return 1
+	}
+
+	local, err := datadir.ReadState(c.dataDir)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading Raft state: %v", err))
+		return 1
+	}
+
+	if c.format == JSONFormat && len(c.states) == 0 && !c.writePeers && c.exportSnapshot == "" {
+		// This is the form used to gather the state of each survivor.
+		b, err := json.MarshalIndent(local, "", "  ")
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error encoding output: %s", err))
+			return 1
+		}
+		c.UI.Output(string(b))
+		return 0
+	}
+
+	states := []*datadir.State{local}
+	for _, path := range c.states {
+		s, err := readStateFile(path)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error reading state file: %v", err))
+			return 1
+		}
+		states = append(states, s)
+	}
+	best := datadir.MostUpToDate(states)
+
+	c.UI.Output(formatStates(states, best))
+
+	if c.writePeers {
+		servers, err := c.recoveredServers(states, best)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error building peers.json: %v", err))
+			return 1
+		}
+		path, err := datadir.WritePeersJSON(c.dataDir, servers)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error writing peers.json: %v", err))
+			return 1
+		}
+		c.UI.Output(fmt.Sprintf("\nWrote %s with %d servers. Write the same file on every "+
+			"server listed in it before starting them.", path, len(servers)))
+	}
+
+	if c.exportSnapshot != "" {
+		if err := c.export(); err != nil {
+			c.UI.Error(fmt.Sprintf("Error exporting snapshot: %v", err))
+			return 1
+		}
+	}
+	return 0
+}
+
+// recoveredServers returns the servers to write to peers.json: the ones given
+// with -peer, or otherwise every server whose state was given at the address
+// recorded in the most recent configuration.
+func (c *cmd) recoveredServers(states []*datadir.State, best *datadir.State) ([]datadir.Server, error) {
+	var servers []datadir.Server
+	if len(c.peers) > 0 {
+		for _, p := range c.peers {
+			id, addr, ok := strings.Cut(p, "=")
+			if !ok {
+				return nil, fmt.Errorf("-peer %q must be in the form ID=IP:port", p)
+			}
+			servers = append(servers, datadir.Server{ID: id, Address: addr})
+		}
+		return servers, nil
+	}
+
+	known := make(map[string]datadir.Server)
+	for _, s := range best.Configuration {
+		known[s.ID] = s
+	}
+	for _, s := range states {
+		if s.NodeID == "" {
+			return nil, fmt.Errorf("no node ID found in %s, use -peer to list the servers", s.DataDir)
+		}
+		server, ok := known[s.NodeID]
+		if !ok {
+			return nil, fmt.Errorf("server %s is not in the last known configuration, use -peer to list the servers", s.NodeID)
+		}
+		// Survivors are all made voters, whatever they were before.
+		server.Suffrage = ""
+		servers = append(servers, server)
+	}
+	return servers, nil
+}
+
+func (c *cmd) export() error {
+	f, err := os.Create(c.exportSnapshot)
+	if err != nil {
+		return err
+	}
+	meta, err := datadir.ExportSnapshot(c.dataDir, f, nil)
+	if err != nil {
+		f.Close()
+		os.Remove(c.exportSnapshot)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	c.UI.Output(fmt.Sprintf("\nExported state at index %d (term %d) to %s", meta.Index, meta.Term, c.exportSnapshot))
+	return nil
+}
+
+func readStateFile(path string) (*datadir.State, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s datadir.State
+	if err := json.Unmarshal(buf, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+func formatStates(states []*datadir.State, best *datadir.State) string {
+	var b strings.Builder
+
+	result := []string{"Node ID\x1fData Dir\x1fLast Index\x1fLast Term\x1fSnapshot Index\x1fConfiguration Index"}
+	for _, s := range states {
+		id := s.NodeID
+		if id == "" {
+			id = "(unknown)"
+		}
+		result = append(result, fmt.Sprintf("%s\x1f%s\x1f%d\x1f%d\x1f%d\x1f%d",
+			id, s.DataDir, s.LastIndex, s.LastTerm, s.SnapshotIndex, s.ConfigurationIndex))
+	}
+	b.WriteString(columnize.Format(result, &columnize.Config{Delim: string([]byte{0x1f})}))
+
+	fmt.Fprintf(&b, "\n\nLast known configuration (index %d):\n", best.ConfigurationIndex)
+	result = []string{"ID\x1fAddress\x1fSuffrage"}
+	for _, s := range best.Configuration {
+		result = append(result, fmt.Sprintf("%s\x1f%s\x1f%s", s.ID, s.Address, s.Suffrage))
+	}
+	b.WriteString(columnize.Format(result, &columnize.Config{Delim: string([]byte{0x1f})}))
+
+	if len(states) > 1 {
+		id := best.NodeID
+		if id == "" {
+			id = "(unknown)"
+		}
+		fmt.Fprintf(&b, "\n\nMost up-to-date server: %s (%s)", id, best.DataDir)
+	}
+	return b.String()
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Inspect a stopped server's Raft state and prepare an outage recovery"
+const help = `
+Usage: consul operator raft recover -data-dir=<path> [options]
+
+  Reads the Raft log and latest snapshot of a stopped server directly from its
+  data directory to help recover from a loss of quorum. It shows the server's
+  last log index and term and the last Raft configuration it knows about.
+
+  To find the best server to recover from, gather the state of every
+  surviving server and compare them on one of them:
+
+      $ consul operator raft recover -data-dir=/opt/consul -format=json > server1.json
+      $ consul operator raft recover -data-dir=/opt/consul -state=server1.json -state=server2.json
+
+  Then write a validated peers.json listing the surviving servers:
+
+      $ consul operator raft recover -data-dir=/opt/consul -state=server1.json -write-peers
+
+  The state of a server can also be exported as a snapshot archive that can
+  be restored into a new cluster with "consul snapshot restore":
+
+      $ consul operator raft recover -data-dir=/opt/consul -export-snapshot=recovered.snap
+`
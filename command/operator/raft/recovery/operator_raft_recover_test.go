@@ -0,0 +1,135 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package recovery
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/command/operator/raft/datadir"
+)
+
+const (
+	testNodeID1 = "5e9a9ef4-6e79-4c8b-9d1c-4f0b8d3f8b01"
+	testNodeID2 = "5e9a9ef4-6e79-4c8b-9d1c-4f0b8d3f8b02"
+	testNodeID3 = "5e9a9ef4-6e79-4c8b-9d1c-4f0b8d3f8b03"
+)
+
+func testDataDir(t *testing.T, nodeID string, lastIndex uint64) string {
+	t.Helper()
+
+	dataDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(datadir.RaftDir(dataDir), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "node-id"), []byte(nodeID), 0600))
+
+	store, err := raftboltdb.New(raftboltdb.Options{Path: filepath.Join(datadir.RaftDir(dataDir), "raft.db")})
+	require.NoError(t, err)
+	defer store.Close()
+
+	configuration := raft.Configuration{Servers: []raft.Server{
+		{Suffrage: raft.Voter, ID: testNodeID1, Address: "10.0.0.1:8300"},
+		{Suffrage: raft.Voter, ID: testNodeID2, Address: "10.0.0.2:8300"},
+		{Suffrage: raft.Voter, ID: testNodeID3, Address: "10.0.0.3:8300"},
+	}}
+	logs := []*raft.Log{
+		{Index: 1, Term: 1, Type: raft.LogConfiguration, Data: raft.EncodeConfiguration(configuration)},
+	}
+	for idx := uint64(2); idx <= lastIndex; idx++ {
+		logs = append(logs, &raft.Log{Index: idx, Term: 1, Type: raft.LogNoop})
+	}
+	require.NoError(t, store.StoreLogs(logs))
+	return dataDir
+}
+
+func TestOperatorRaftRecoverCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestOperatorRaftRecoverCommand(t *testing.T) {
+	t.Parallel()
+
+	dir1 := testDataDir(t, testNodeID1, 5)
+	dir2 := testDataDir(t, testNodeID2, 9)
+
+	// Gather the state of the second server.
+	ui := cli.NewMockUi()
+	require.Equal(t, 0, New(ui).Run([]string{"-data-dir=" + dir2, "-format=json"}), ui.ErrorWriter.String())
+	var state datadir.State
+	require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &state))
+	require.Equal(t, testNodeID2, state.NodeID)
+	require.Equal(t, uint64(9), state.LastIndex)
+
+	stateFile := filepath.Join(t.TempDir(), "server2.json")
+	require.NoError(t, os.WriteFile(stateFile, ui.OutputWriter.Bytes(), 0600))
+
+	// Compare it with the first and write peers.json.
+	ui = cli.NewMockUi()
+	require.Equal(t, 0, New(ui).Run([]string{"-data-dir=" + dir1, "-state=" + stateFile, "-write-peers"}), ui.ErrorWriter.String())
+	output := ui.OutputWriter.String()
+	require.Contains(t, output, "Most up-to-date server: "+testNodeID2)
+	require.Contains(t, output, "10.0.0.3:8300")
+
+	configuration, err := raft.ReadConfigJSON(filepath.Join(datadir.RaftDir(dir1), "peers.json"))
+	require.NoError(t, err)
+	require.Equal(t, []raft.Server{
+		{Suffrage: raft.Voter, ID: testNodeID1, Address: "10.0.0.1:8300"},
+		{Suffrage: raft.Voter, ID: testNodeID2, Address: "10.0.0.2:8300"},
+	}, configuration.Servers)
+}
+
+func TestOperatorRaftRecoverCommand_explicitPeers(t *testing.T) {
+	t.Parallel()
+
+	dir := testDataDir(t, testNodeID1, 3)
+
+	ui := cli.NewMockUi()
+	args := []string{"-data-dir=" + dir, "-write-peers", "-peer=" + testNodeID1 + "=10.1.0.1:8300"}
+	require.Equal(t, 0, New(ui).Run(args), ui.ErrorWriter.String())
+
+	configuration, err := raft.ReadConfigJSON(filepath.Join(datadir.RaftDir(dir), "peers.json"))
+	require.NoError(t, err)
+	require.Equal(t, []raft.Server{
+		{Suffrage: raft.Voter, ID: testNodeID1, Address: "10.1.0.1:8300"},
+	}, configuration.Servers)
+
+	ui = cli.NewMockUi()
+	args = []string{"-data-dir=" + dir, "-write-peers", "-peer=10.1.0.1:8300"}
+	require.Equal(t, 1, New(ui).Run(args))
+	require.Contains(t, ui.ErrorWriter.String(), "ID=IP:port")
+}
+
+func TestOperatorRaftRecoverCommand_exportSnapshot(t *testing.T) {
+	t.Parallel()
+
+	dir := testDataDir(t, testNodeID1, 3)
+	out := filepath.Join(t.TempDir(), "recovered.snap")
+
+	ui := cli.NewMockUi()
+	require.Equal(t, 0, New(ui).Run([]string{"-data-dir=" + dir, "-export-snapshot=" + out}), ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Exported state at index 3")
+	require.FileExists(t, out)
+}
+
+func TestOperatorRaftRecoverCommand_invalidArgs(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	require.Equal(t, 1, New(ui).Run(nil))
+	require.Contains(t, ui.ErrorWriter.String(), "-data-dir")
+
+	ui = cli.NewMockUi()
+	require.Equal(t, 1, New(ui).Run([]string{"-data-dir=" + t.TempDir(), "-peer=a=b"}))
+	require.Contains(t, ui.ErrorWriter.String(), "-write-peers")
+}
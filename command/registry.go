@@ -24,6 +24,7 @@ import (
 	aclbrread "github.com/hashicorp/consul/command/acl/bindingrule/read"
 	aclbrupdate "github.com/hashicorp/consul/command/acl/bindingrule/update"
 	aclbootstrap "github.com/hashicorp/consul/command/acl/bootstrap"
+	aclexplain "github.com/hashicorp/consul/command/acl/explain"
 	aclpolicy "github.com/hashicorp/consul/command/acl/policy"
 	aclpcreate "github.com/hashicorp/consul/command/acl/policy/create"
 	aclpdelete "github.com/hashicorp/consul/command/acl/policy/delete"
@@ -37,15 +38,21 @@ import (
 	aclrread "github.com/hashicorp/consul/command/acl/role/read"
 	aclrupdate "github.com/hashicorp/consul/command/acl/role/update"
 	acltp "github.com/hashicorp/consul/command/acl/templatedpolicy"
+	acltpcreate "github.com/hashicorp/consul/command/acl/templatedpolicy/create"
+	acltpdelete "github.com/hashicorp/consul/command/acl/templatedpolicy/delete"
 	acltplist "github.com/hashicorp/consul/command/acl/templatedpolicy/list"
 	acltppreview "github.com/hashicorp/consul/command/acl/templatedpolicy/preview"
 	acltpread "github.com/hashicorp/consul/command/acl/templatedpolicy/read"
+	acltpupdate "github.com/hashicorp/consul/command/acl/templatedpolicy/update"
 	acltoken "github.com/hashicorp/consul/command/acl/token"
+	acltbreakglass "github.com/hashicorp/consul/command/acl/token/breakglass"
 	acltclone "github.com/hashicorp/consul/command/acl/token/clone"
 	acltcreate "github.com/hashicorp/consul/command/acl/token/create"
 	acltdelete "github.com/hashicorp/consul/command/acl/token/delete"
+	acltlineage "github.com/hashicorp/consul/command/acl/token/lineage"
 	acltlist "github.com/hashicorp/consul/command/acl/token/list"
 	acltread "github.com/hashicorp/consul/command/acl/token/read"
+	acltrotate "github.com/hashicorp/consul/command/acl/token/rotate"
 	acltupdate "github.com/hashicorp/consul/command/acl/token/update"
 	"github.com/hashicorp/consul/command/agent"
 	"github.com/hashicorp/consul/command/catalog"
@@ -59,8 +66,12 @@ import (
 	configwrite "github.com/hashicorp/consul/command/config/write"
 	"github.com/hashicorp/consul/command/connect"
 	"github.com/hashicorp/consul/command/connect/ca"
+	cacsr "github.com/hashicorp/consul/command/connect/ca/csr"
 	caget "github.com/hashicorp/consul/command/connect/ca/get"
+	carevoke "github.com/hashicorp/consul/command/connect/ca/revoke"
 	caset "github.com/hashicorp/consul/command/connect/ca/set"
+	casetintermediate "github.com/hashicorp/consul/command/connect/ca/setintermediate"
+	castatus "github.com/hashicorp/consul/command/connect/ca/status"
 	"github.com/hashicorp/consul/command/connect/envoy"
 	pipebootstrap "github.com/hashicorp/consul/command/connect/envoy/pipe-bootstrap"
 	"github.com/hashicorp/consul/command/connect/expose"
@@ -101,6 +112,8 @@ import (
 	operautostate "github.com/hashicorp/consul/command/operator/autopilot/state"
 	operraft "github.com/hashicorp/consul/command/operator/raft"
 	operraftlist "github.com/hashicorp/consul/command/operator/raft/listpeers"
+	operraftlog "github.com/hashicorp/consul/command/operator/raft/raftlog"
+	operraftrecover "github.com/hashicorp/consul/command/operator/raft/recovery"
 	operraftremove "github.com/hashicorp/consul/command/operator/raft/removepeer"
 	"github.com/hashicorp/consul/command/operator/raft/transferleader"
 	"github.com/hashicorp/consul/command/operator/usage"
@@ -150,6 +163,7 @@ func RegisteredCommands(ui cli.Ui) map[string]mcli.CommandFactory {
 	registerCommands(ui, registry,
 		entry{"acl", func(cli.Ui) (cli.Command, error) { return acl.New(), nil }},
 		entry{"acl bootstrap", func(ui cli.Ui) (cli.Command, error) { return aclbootstrap.New(ui), nil }},
+		entry{"acl explain", func(ui cli.Ui) (cli.Command, error) { return aclexplain.New(ui), nil }},
 		entry{"acl policy", func(cli.Ui) (cli.Command, error) { return aclpolicy.New(), nil }},
 		entry{"acl policy create", func(ui cli.Ui) (cli.Command, error) { return aclpcreate.New(ui), nil }},
 		entry{"acl policy list", func(ui cli.Ui) (cli.Command, error) { return aclplist.New(ui), nil }},
@@ -164,6 +178,9 @@ func RegisteredCommands(ui cli.Ui) map[string]mcli.CommandFactory {
 		entry{"acl token read", func(ui cli.Ui) (cli.Command, error) { return acltread.New(ui), nil }},
 		entry{"acl token update", func(ui cli.Ui) (cli.Command, error) { return acltupdate.New(ui), nil }},
 		entry{"acl token delete", func(ui cli.Ui) (cli.Command, error) { return acltdelete.New(ui), nil }},
+		entry{"acl token lineage", func(ui cli.Ui) (cli.Command, error) { return acltlineage.New(ui), nil }},
+		entry{"acl token break-glass", func(ui cli.Ui) (cli.Command, error) { return acltbreakglass.New(ui), nil }},
+		entry{"acl token rotate", func(ui cli.Ui) (cli.Command, error) { return acltrotate.New(ui), nil }},
 		entry{"acl role", func(cli.Ui) (cli.Command, error) { return aclrole.New(), nil }},
 		entry{"acl role create", func(ui cli.Ui) (cli.Command, error) { return aclrcreate.New(ui), nil }},
 		entry{"acl role list", func(ui cli.Ui) (cli.Command, error) { return aclrlist.New(ui), nil }},
@@ -186,6 +203,9 @@ func RegisteredCommands(ui cli.Ui) map[string]mcli.CommandFactory {
 		entry{"acl templated-policy list", func(ui cli.Ui) (cli.Command, error) { return acltplist.New(ui), nil }},
 		entry{"acl templated-policy read", func(ui cli.Ui) (cli.Command, error) { return acltpread.New(ui), nil }},
 		entry{"acl templated-policy preview", func(ui cli.Ui) (cli.Command, error) { return acltppreview.New(ui), nil }},
+		entry{"acl templated-policy create", func(ui cli.Ui) (cli.Command, error) { return acltpcreate.New(ui), nil }},
+		entry{"acl templated-policy update", func(ui cli.Ui) (cli.Command, error) { return acltpupdate.New(ui), nil }},
+		entry{"acl templated-policy delete", func(ui cli.Ui) (cli.Command, error) { return acltpdelete.New(ui), nil }},
 		entry{"agent", func(ui cli.Ui) (cli.Command, error) { return agent.New(ui), nil }},
 		entry{"catalog", func(cli.Ui) (cli.Command, error) { return catalog.New(), nil }},
 		entry{"catalog datacenters", func(ui cli.Ui) (cli.Command, error) { return catlistdc.New(ui), nil }},
@@ -200,6 +220,10 @@ func RegisteredCommands(ui cli.Ui) map[string]mcli.CommandFactory {
 		entry{"connect ca", func(ui cli.Ui) (cli.Command, error) { return ca.New(), nil }},
 		entry{"connect ca get-config", func(ui cli.Ui) (cli.Command, error) { return caget.New(ui), nil }},
 		entry{"connect ca set-config", func(ui cli.Ui) (cli.Command, error) { return caset.New(ui), nil }},
+		entry{"connect ca csr", func(ui cli.Ui) (cli.Command, error) { return cacsr.New(ui), nil }},
+		entry{"connect ca set-intermediate", func(ui cli.Ui) (cli.Command, error) { return casetintermediate.New(ui), nil }},
+		entry{"connect ca revoke", func(ui cli.Ui) (cli.Command, error) { return carevoke.New(ui), nil }},
+		entry{"connect ca status", func(ui cli.Ui) (cli.Command, error) { return castatus.New(ui), nil }},
 		entry{"connect proxy", func(ui cli.Ui) (cli.Command, error) { return proxy.New(ui, MakeShutdownCh()), nil }},
 		entry{"connect envoy", func(ui cli.Ui) (cli.Command, error) { return envoy.New(ui), nil }},
 		entry{"connect envoy pipe-bootstrap", func(ui cli.Ui) (cli.Command, error) { return pipebootstrap.New(ui), nil }},
@@ -240,6 +264,8 @@ func RegisteredCommands(ui cli.Ui) map[string]mcli.CommandFactory {
 		entry{"operator autopilot state", func(ui cli.Ui) (cli.Command, error) { return operautostate.New(ui), nil }},
 		entry{"operator raft", func(cli.Ui) (cli.Command, error) { return operraft.New(), nil }},
 		entry{"operator raft list-peers", func(ui cli.Ui) (cli.Command, error) { return operraftlist.New(ui), nil }},
+		entry{"operator raft log", func(ui cli.Ui) (cli.Command, error) { return operraftlog.New(ui), nil }},
+		entry{"operator raft recover", func(ui cli.Ui) (cli.Command, error) { return operraftrecover.New(ui), nil }},
 		entry{"operator raft remove-peer", func(ui cli.Ui) (cli.Command, error) { return operraftremove.New(ui), nil }},
 		entry{"operator raft transfer-leader", func(ui cli.Ui) (cli.Command, error) { return transferleader.New(ui), nil }},
 		entry{"operator usage", func(ui cli.Ui) (cli.Command, error) { return usage.New(), nil }},
@@ -9,6 +9,8 @@ import (
 	"net"
 	"net/http"
 
+	"google.golang.org/grpc"
+
 	"github.com/hashicorp/consul/api"
 )
 
@@ -54,3 +56,25 @@ func ExampleService_HTTPClient() {
 	resp, _ := httpClient.Get("https://web.service.consul/foo/bar")
 	handleResponse(resp)
 }
+
+// Note: this assumes a suitable Consul ACL token with 'service:write' for
+// service 'web' is set in CONSUL_HTTP_TOKEN ENV var.
+func ExampleService_GRPCServerOptions() {
+	client, _ := api.NewClient(api.DefaultConfig())
+	svc, _ := NewService("web", client)
+	server := grpc.NewServer(svc.GRPCServerOptions()...)
+	l, _ := net.Listen("tcp", ":8080")
+	log.Fatal(server.Serve(l))
+}
+
+// Note: this assumes a suitable Consul ACL token with 'service:write' for
+// service 'web' is set in CONSUL_HTTP_TOKEN ENV var.
+func ExampleService_GRPCDialOptions() {
+	client, _ := api.NewClient(api.DefaultConfig())
+	svc, _ := NewService("web", client)
+	conn, _ := grpc.Dial("db", svc.GRPCDialOptions(&DiscoveryChainResolver{
+		Client: client,
+		Name:   "db",
+	})...)
+	defer conn.Close()
+}
@@ -0,0 +1,309 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package connect
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/api"
+)
+
+// GRPCServerOptions returns the options that configure a *grpc.Server to
+// accept Connect clients and authorize each RPC against the intentions of the
+// service. The L7 permissions of the intentions are matched against the full
+// method name as the path, and against the metadata as the headers. The
+// authorization of an RPC is reused for up to 10 seconds for the RPCs of the
+// same client with the same method and metadata.
+//
+// Like for ServerTLSConfig, the certificates are loaded dynamically so the
+// server doesn't need to be restarted when they are rotated. The identity of
+// the client of an RPC is returned by CertURIFromContext in the handlers.
+func (s *Service) GRPCServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.Creds(&grpcCredentials{service: s}),
+		grpc.ChainUnaryInterceptor(s.authorizeUnaryRPC),
+		grpc.ChainStreamInterceptor(s.authorizeStreamRPC),
+	}
+}
+
+// GRPCDialOptions returns the options that configure a *grpc.ClientConn to
+// connect to the instances returned by the resolver, such as a
+// DiscoveryChainResolver. A new instance is resolved each time the client
+// reconnects. The target passed to grpc.Dial is only used as the authority of
+// the RPCs, e.g.:
+//
+//	conn, err := grpc.Dial("db", svc.GRPCDialOptions(&connect.DiscoveryChainResolver{
+//		Client: client,
+//		Name:   "db",
+//	})...)
+func (s *Service) GRPCDialOptions(resolver Resolver) []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return s.Dial(ctx, resolver)
+		}),
+		grpc.WithTransportCredentials(&grpcCredentials{service: s}),
+	}
+}
+
+// certURIContextKey is the context key of the identity of the client of an
+// RPC.
+type certURIContextKey struct{}
+
+// CertURIFromContext returns the identity of the client of an RPC authorized
+// by a server configured with GRPCServerOptions.
+func CertURIFromContext(ctx context.Context) (connect.CertURI, bool) {
+	certURI, ok := ctx.Value(certURIContextKey{}).(connect.CertURI)
+	return certURI, ok
+}
+
+func (s *Service) authorizeUnaryRPC(ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := s.authorizeRPC(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Service) authorizeStreamRPC(srv interface{}, ss grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authorizeRPC(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authorizedServerStream{ServerStream: ss, ctx: ctx})
+}
+
+// authorizedServerStream overrides the context of a stream with the one that
+// contains the identity of the client.
+type authorizedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authorizedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// authorizeRPC authorizes the RPC of the given method against the intentions
+// of the service and returns the context of the RPC with the identity of its
+// client.
+func (s *Service) authorizeRPC(ctx context.Context, fullMethod string) (context.Context, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "connect: no peer")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.PeerCertificates) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "connect: no client certificate")
+	}
+	cert := tlsInfo.State.PeerCertificates[0]
+	if len(cert.URIs) < 1 {
+		return nil, status.Error(codes.Unauthenticated, "connect: invalid client certificate")
+	}
+	certURI, err := connect.ParseCertURI(cert.URIs[0])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "connect: invalid client certificate")
+	}
+
+	params := grpcAuthorizeParams(ctx, fullMethod)
+	key := grpcAuthzCacheKey(cert, params)
+	resp := s.grpcAuthz.get(key, time.Now())
+	if resp == nil {
+		resp, err = s.AuthorizeRequest(cert, params)
+		if err != nil {
+			s.logger.Error("authz call failed", "error", err)
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+		s.grpcAuthz.set(key, resp, time.Now())
+	}
+	if !resp.Authorized {
+		s.logger.Debug("RPC denied",
+			"client", certURI.URI().String(),
+			"method", fullMethod,
+			"reason", resp.Reason,
+		)
+		return nil, status.Error(codes.PermissionDenied, "connect: RPC denied by intentions")
+	}
+
+	return context.WithValue(ctx, certURIContextKey{}, certURI), nil
+}
+
+// grpcAuthzCacheTTL is how long the authorization of an RPC is reused for the
+// following RPCs of the same client. It bounds how long it takes for a change
+// of the intentions or a revoked certificate to apply to the RPCs.
+const grpcAuthzCacheTTL = 10 * time.Second
+
+// grpcAuthzCache caches the authorization of the RPCs so that they don't each
+// require a call to the agent. The zero value is ready to use.
+type grpcAuthzCache struct {
+	lock      sync.Mutex
+	entries   map[string]grpcAuthzCacheEntry
+	lastPrune time.Time
+}
+
+type grpcAuthzCacheEntry struct {
+	resp    *api.AgentAuthorize
+	expires time.Time
+}
+
+// get returns the cached authorization of the given key, or nil if it is not
+// cached or expired.
+func (c *grpcAuthzCache) get(key string, now time.Time) *api.AgentAuthorize {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !now.Before(entry.expires) {
+		return nil
+	}
+	return entry.resp
+}
+
+// set caches the authorization of the given key. The expired entries are
+// pruned at most once per TTL.
+func (c *grpcAuthzCache) set(key string, resp *api.AgentAuthorize, now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[string]grpcAuthzCacheEntry)
+	}
+	if now.Sub(c.lastPrune) >= grpcAuthzCacheTTL {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		c.lastPrune = now
+	}
+	c.entries[key] = grpcAuthzCacheEntry{resp: resp, expires: now.Add(grpcAuthzCacheTTL)}
+}
+
+// grpcAuthzCacheKey returns the key of the authorization of an RPC: the
+// identity and serial number of the client certificate, the method, and the
+// metadata since the L7 permissions of the intentions may match it.
+func grpcAuthzCacheKey(cert *x509.Certificate, params *api.AgentAuthorizeHTTPParams) string {
+	var b strings.Builder
+	b.WriteString(cert.URIs[0].String())
+	b.WriteByte(0)
+	b.WriteString(connect.EncodeSerialNumber(cert.SerialNumber))
+	b.WriteByte(0)
+	b.WriteString(params.Path)
+
+	names := make([]string, 0, len(params.Header))
+	for name := range params.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range params.Header[name] {
+			b.WriteByte(0)
+			b.WriteString(name)
+			b.WriteByte(':')
+			b.WriteString(value)
+		}
+	}
+	return b.String()
+}
+
+// grpcAuthorizeParams describes an RPC as the HTTP/2 request that carries it,
+// which is how Envoy evaluates the L7 intentions of gRPC services.
+func grpcAuthorizeParams(ctx context.Context, fullMethod string) *api.AgentAuthorizeHTTPParams {
+	header := make(http.Header)
+	md, _ := metadata.FromIncomingContext(ctx)
+	for k, values := range md {
+		if k == ":authority" {
+			header["Host"] = values
+			continue
+		}
+		if strings.HasPrefix(k, ":") {
+			continue
+		}
+		header[http.CanonicalHeaderKey(k)] = values
+	}
+	return &api.AgentAuthorizeHTTPParams{
+		Method: http.MethodPost,
+		Path:   fullMethod,
+		Header: header,
+	}
+}
+
+// grpcCredentials are the gRPC transport credentials of a Service. The
+// servers authenticate the clients during the handshake and authorize each
+// RPC. The clients are expected to dial with Service.Dial, which already
+// performed the handshake and verified the identity of the server.
+type grpcCredentials struct {
+	service *Service
+}
+
+// ClientHandshake implements credentials.TransportCredentials
+func (c *grpcCredentials) ClientHandshake(_ context.Context, _ string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return nil, nil, errors.New("connect: connection was not dialed by the Service")
+	}
+	return conn, grpcTLSInfo(tlsConn), nil
+}
+
+// ServerHandshake implements credentials.TransportCredentials
+func (c *grpcCredentials) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	conn := tls.Server(rawConn, c.service.HTTPServerTLSConfig())
+	if err := conn.Handshake(); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	return conn, grpcTLSInfo(conn), nil
+}
+
+// Info implements credentials.TransportCredentials
+func (c *grpcCredentials) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{
+		SecurityProtocol: "tls",
+		SecurityVersion:  "1.2",
+	}
+}
+
+// Clone implements credentials.TransportCredentials
+func (c *grpcCredentials) Clone() credentials.TransportCredentials {
+	return &grpcCredentials{service: c.service}
+}
+
+// OverrideServerName implements credentials.TransportCredentials. The server
+// name is not used since the identity of the server is verified against the
+// one returned by the resolver.
+func (c *grpcCredentials) OverrideServerName(string) error {
+	return nil
+}
+
+func grpcTLSInfo(conn *tls.Conn) credentials.TLSInfo {
+	state := conn.ConnectionState()
+	info := credentials.TLSInfo{
+		State: state,
+		CommonAuthInfo: credentials.CommonAuthInfo{
+			SecurityLevel: credentials.PrivacyAndIntegrity,
+		},
+	}
+	if len(state.PeerCertificates) > 0 && len(state.PeerCertificates[0].URIs) > 0 {
+		info.SPIFFEID = state.PeerCertificates[0].URIs[0]
+	}
+	return info
+}
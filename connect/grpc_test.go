@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package connect
+
+import (
+	"context"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+// testHealthServer is a gRPC health server that records the identity of the
+// client of the last check.
+type testHealthServer struct {
+	*health.Server
+	certURI connect.CertURI
+}
+
+func (s *testHealthServer) Check(ctx context.Context,
+	req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	s.certURI, _ = CertURIFromContext(ctx)
+	return s.Server.Check(ctx, req)
+}
+
+func TestService_GRPC(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	a := agent.StartTestAgent(t, agent.TestAgent{Name: "test-consul"})
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	client := a.Client()
+
+	// Only allow web to call db.
+	_, _, err := client.ConfigEntries().Set(&api.ServiceIntentionsConfigEntry{
+		Kind: api.ServiceIntentions,
+		Name: "db",
+		Sources: []*api.SourceIntention{
+			{Name: "web", Action: api.IntentionActionAllow},
+			{Name: "*", Action: api.IntentionActionDeny},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	ca := connect.TestCA(t, nil)
+	db := TestService(t, "db", ca)
+	db.client = client
+
+	healthSrv := &testHealthServer{Server: health.NewServer()}
+	srv := grpc.NewServer(db.GRPCServerOptions()...)
+	grpc_health_v1.RegisterHealthServer(srv, healthSrv)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go srv.Serve(l)
+	defer srv.Stop()
+
+	check := func(t *testing.T, service string) error {
+		svc := TestService(t, service, ca)
+		conn, err := grpc.Dial("db", svc.GRPCDialOptions(&StaticResolver{
+			Addr:    l.Addr().String(),
+			CertURI: connect.TestSpiffeIDService(t, "db"),
+		})...)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = grpc_health_v1.NewHealthClient(conn).Check(context.Background(),
+			&grpc_health_v1.HealthCheckRequest{})
+		return err
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		require.NoError(t, check(t, "web"))
+		require.Equal(t, connect.TestSpiffeIDService(t, "web").URI().String(),
+			healthSrv.certURI.URI().String())
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		err := check(t, "api")
+		require.Error(t, err)
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestGRPCAuthorizeParams(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{
+		":authority":   []string{"db"},
+		"content-type": []string{"application/grpc"},
+		"x-tenant":     []string{"a", "b"},
+	})
+
+	got := grpcAuthorizeParams(ctx, "/grpc.health.v1.Health/Check")
+	require.Equal(t, &api.AgentAuthorizeHTTPParams{
+		Method: http.MethodPost,
+		Path:   "/grpc.health.v1.Health/Check",
+		Header: map[string][]string{
+			"Host":         {"db"},
+			"Content-Type": {"application/grpc"},
+			"X-Tenant":     {"a", "b"},
+		},
+	}, got)
+}
+
+func TestGRPCAuthzCache(t *testing.T) {
+	ca := connect.TestCA(t, nil)
+	webCert := testLeafCert(t, ca, "web")
+	apiCert := testLeafCert(t, ca, "api")
+
+	params := func(method string, header map[string][]string) *api.AgentAuthorizeHTTPParams {
+		return &api.AgentAuthorizeHTTPParams{Method: http.MethodPost, Path: method, Header: header}
+	}
+	key := grpcAuthzCacheKey(webCert, params("/db.DB/Get", map[string][]string{"X-Tenant": {"a"}}))
+
+	// The key changes with the client, the method and the metadata.
+	require.Equal(t, key, grpcAuthzCacheKey(webCert, params("/db.DB/Get", map[string][]string{"X-Tenant": {"a"}})))
+	require.NotEqual(t, key, grpcAuthzCacheKey(apiCert, params("/db.DB/Get", map[string][]string{"X-Tenant": {"a"}})))
+	require.NotEqual(t, key, grpcAuthzCacheKey(webCert, params("/db.DB/Put", map[string][]string{"X-Tenant": {"a"}})))
+	require.NotEqual(t, key, grpcAuthzCacheKey(webCert, params("/db.DB/Get", map[string][]string{"X-Tenant": {"b"}})))
+
+	var cache grpcAuthzCache
+	now := time.Now()
+	require.Nil(t, cache.get(key, now))
+
+	denied := &api.AgentAuthorize{Authorized: false, Reason: "denied"}
+	cache.set(key, denied, now)
+	require.Equal(t, denied, cache.get(key, now.Add(grpcAuthzCacheTTL-time.Second)))
+	require.Nil(t, cache.get(key, now.Add(grpcAuthzCacheTTL)))
+
+	// The expired entries are pruned when a new one is cached.
+	other := grpcAuthzCacheKey(apiCert, params("/db.DB/Get", nil))
+	cache.set(other, &api.AgentAuthorize{Authorized: true}, now.Add(grpcAuthzCacheTTL))
+	require.Len(t, cache.entries, 1)
+}
+
+// testLeafCert returns the parsed leaf certificate of the given service.
+func testLeafCert(t *testing.T, ca *structs.CARoot, service string) *x509.Certificate {
+	certPEM, _ := connect.TestLeaf(t, service, ca)
+	cert, err := connect.ParseCert(certPEM)
+	require.NoError(t, err)
+	return cert
+}
@@ -49,7 +49,13 @@ type Config struct {
 
 // Service returns the *connect.Service structure represented by this config.
 func (c *Config) Service(client *api.Client, logger hclog.Logger) (*connect.Service, error) {
-	return connect.NewServiceWithConfig(c.ProxiedServiceName, connect.Config{Client: client, Logger: logger, ServerNextProtos: []string{}})
+	nextProtos := []string{}
+	if isHTTPProtocol(c.PublicListener.Protocol) {
+		// Let the clients negotiate HTTP/2 when the public listener parses
+		// HTTP.
+		nextProtos = []string{"h2", "http/1.1"}
+	}
+	return connect.NewServiceWithConfig(c.ProxiedServiceName, connect.Config{Client: client, Logger: logger, ServerNextProtos: nextProtos})
 }
 
 // PublicListenerConfig contains the parameters needed for the incoming mTLS
@@ -73,6 +79,11 @@ type PublicListenerConfig struct {
 	// handshake. Setting this low avoids DOS by malicious clients holding
 	// resources open. Defaults to 10000 (10s).
 	HandshakeTimeoutMs int `json:"handshake_timeout_ms" hcl:"handshake_timeout_ms" mapstructure:"handshake_timeout_ms"`
+
+	// Protocol is the protocol of the proxied application. When it is http,
+	// http2 or grpc, the requests are parsed to enforce the L7 permissions of
+	// the intentions. Otherwise the connections are proxied over TCP.
+	Protocol string `json:"protocol" hcl:"protocol" mapstructure:"protocol"`
 }
 
 // applyDefaults sets zero-valued params to a reasonable default.
@@ -101,6 +112,15 @@ func (uc *UpstreamConfig) ConnectTimeout() time.Duration {
 	return 10000 * time.Millisecond
 }
 
+// Protocol returns the protocol field of the nested config struct, which is
+// set from the service-defaults of the upstream, or tcp by default.
+func (uc *UpstreamConfig) Protocol() string {
+	if protocol, ok := uc.Config["protocol"].(string); ok && protocol != "" {
+		return protocol
+	}
+	return "tcp"
+}
+
 // applyDefaults sets zero-valued params to a reasonable default.
 func (uc *UpstreamConfig) applyDefaults() {
 	if uc.DestinationType == "" {
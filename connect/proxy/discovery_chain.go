@@ -0,0 +1,327 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+
+	agConnect "github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/connect"
+)
+
+// discoveryChainRouter routes the requests sent to an HTTP upstream with the
+// routers, splitters and resolvers of the compiled discovery chain of the
+// upstream service, like Envoy does with the routes generated for it.
+type discoveryChainRouter struct {
+	client *api.Client
+	cfg    UpstreamConfig
+	logger hclog.Logger
+}
+
+func newDiscoveryChainRouter(client *api.Client, cfg UpstreamConfig, logger hclog.Logger) *discoveryChainRouter {
+	return &discoveryChainRouter{
+		client: client,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Route implements upstreamRouter
+func (d *discoveryChainRouter) Route(r *http.Request) (*upstreamRoute, error) {
+	// The chain is kept up to date by the agent cache so fetching it for each
+	// request is cheap.
+	resp, _, err := d.client.DiscoveryChain().Get(d.cfg.DestinationName,
+		&api.DiscoveryChainOptions{EvaluateInDatacenter: d.cfg.Datacenter},
+		(&api.QueryOptions{
+			UseCache:  true,
+			Namespace: d.cfg.DestinationNamespace,
+			Partition: d.cfg.DestinationPartition,
+		}).WithContext(r.Context()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the discovery chain: %w", err)
+	}
+	return routeDiscoveryChain(d.client, d.cfg, resp.Chain, r)
+}
+
+// routeDiscoveryChain walks the compiled discovery chain from its start node
+// to the resolver of the request. It returns a nil route when no route of a
+// router matches the request.
+func routeDiscoveryChain(client *api.Client, cfg UpstreamConfig,
+	chain *api.CompiledDiscoveryChain, r *http.Request) (*upstreamRoute, error) {
+	if chain == nil {
+		return nil, fmt.Errorf("no discovery chain")
+	}
+
+	route := &upstreamRoute{}
+	nodeName := chain.StartNode
+	for {
+		node, ok := chain.Nodes[nodeName]
+		if !ok {
+			return nil, fmt.Errorf("discovery chain node %q not found", nodeName)
+		}
+
+		switch node.Type {
+		case api.DiscoveryGraphNodeTypeRouter:
+			var next *api.DiscoveryRoute
+			for _, dr := range node.Routes {
+				matched, prefix, err := routeMatches(dr.Definition.Match, r)
+				if err != nil {
+					return nil, err
+				}
+				if matched {
+					next = dr
+					route.matchedPrefix = prefix
+					break
+				}
+			}
+			if next == nil {
+				return nil, nil
+			}
+			if dest := next.Definition.Destination; dest != nil {
+				route.requestTimeout = dest.RequestTimeout
+				route.retry = retryPolicyForDestination(dest)
+				route.prefixRewrite = dest.PrefixRewrite
+				route.requestHeaders = dest.RequestHeaders
+				route.responseHeaders = dest.ResponseHeaders
+			}
+			nodeName = next.NextNode
+
+		case api.DiscoveryGraphNodeTypeSplitter:
+			if len(node.Splits) == 0 {
+				return nil, fmt.Errorf("discovery chain splitter %q has no splits", node.Name)
+			}
+			nodeName = pickSplit(node.Splits).NextNode
+
+		case api.DiscoveryGraphNodeTypeResolver:
+			return resolveDiscoveryTarget(client, cfg, chain, node.Resolver, route)
+
+		default:
+			return nil, fmt.Errorf("unknown discovery chain node type %q", node.Type)
+		}
+	}
+}
+
+// resolveDiscoveryTarget completes the route with the target of the resolver
+// and its failover targets.
+func resolveDiscoveryTarget(client *api.Client, cfg UpstreamConfig,
+	chain *api.CompiledDiscoveryChain, res *api.DiscoveryResolver,
+	route *upstreamRoute) (*upstreamRoute, error) {
+	if res == nil {
+		return nil, fmt.Errorf("discovery chain resolver node has no resolver")
+	}
+	target, ok := chain.Targets[res.Target]
+	if !ok {
+		return nil, fmt.Errorf("discovery chain target %q not found", res.Target)
+	}
+
+	route.targetID = target.ID
+	route.resolver = targetResolver(client, target)
+	switch {
+	case target.ConnectTimeout > 0:
+		route.connectTimeout = target.ConnectTimeout
+	case res.ConnectTimeout > 0:
+		route.connectTimeout = res.ConnectTimeout
+	default:
+		route.connectTimeout = cfg.ConnectTimeout()
+	}
+	// The timeout of the route takes precedence over the one of the resolver.
+	if route.requestTimeout == 0 {
+		route.requestTimeout = res.RequestTimeout
+	}
+
+	if res.Failover != nil && len(res.Failover.Targets) > 0 {
+		resolvers := []connect.Resolver{route.resolver}
+		for _, id := range res.Failover.Targets {
+			ft, ok := chain.Targets[id]
+			if !ok {
+				return nil, fmt.Errorf("discovery chain failover target %q not found", id)
+			}
+			resolvers = append(resolvers, targetResolver(client, ft))
+		}
+		route.resolver = &failoverResolver{resolvers: resolvers}
+	}
+	return route, nil
+}
+
+// targetResolver returns the resolver of the healthy instances of a discovery
+// chain target.
+func targetResolver(client *api.Client, target *api.DiscoveryTarget) connect.Resolver {
+	return &connect.ConsulResolver{
+		Client:     client,
+		Namespace:  target.Namespace,
+		Partition:  target.Partition,
+		Name:       target.Service,
+		Type:       connect.ConsulResolverTypeService,
+		Datacenter: target.Datacenter,
+		Filter:     target.Subset.Filter,
+	}
+}
+
+// failoverResolver resolves an instance with the first of its resolvers that
+// returns one.
+type failoverResolver struct {
+	resolvers []connect.Resolver
+}
+
+// Resolve implements connect.Resolver
+func (f *failoverResolver) Resolve(ctx context.Context) (string, agConnect.CertURI, error) {
+	var err error
+	for _, r := range f.resolvers {
+		addr, certURI, rErr := r.Resolve(ctx)
+		if rErr == nil {
+			return addr, certURI, nil
+		}
+		err = rErr
+	}
+	return "", nil, err
+}
+
+// pickSplit picks a split at random according to the weights of the splits.
+func pickSplit(splits []*api.DiscoverySplit) *api.DiscoverySplit {
+	var total float32
+	for _, s := range splits {
+		total += s.Weight
+	}
+	n := rand.Float32() * total
+	for _, s := range splits {
+		if n < s.Weight {
+			return s
+		}
+		n -= s.Weight
+	}
+	return splits[len(splits)-1]
+}
+
+// routeMatches returns whether the request matches the match of a route. It
+// also returns the matched path prefix, which is rewritten by the prefix
+// rewrite of the route.
+func routeMatches(match *api.ServiceRouteMatch, r *http.Request) (bool, string, error) {
+	if match == nil || match.HTTP == nil {
+		return true, "/", nil
+	}
+	m := match.HTTP
+
+	prefix := ""
+	switch {
+	case m.PathExact != "":
+		if r.URL.Path != m.PathExact {
+			return false, "", nil
+		}
+		prefix = m.PathExact
+	case m.PathPrefix != "":
+		if !strings.HasPrefix(r.URL.Path, m.PathPrefix) {
+			return false, "", nil
+		}
+		prefix = m.PathPrefix
+	case m.PathRegex != "":
+		ok, err := fullRegexMatch(m.PathRegex, r.URL.Path)
+		if err != nil || !ok {
+			return false, "", err
+		}
+	default:
+		prefix = "/"
+	}
+
+	for _, hdr := range m.Header {
+		ok, err := headerMatches(hdr, r)
+		if err != nil || !ok {
+			return false, "", err
+		}
+	}
+
+	query := r.URL.Query()
+	for _, qm := range m.QueryParam {
+		values, present := query[qm.Name]
+		switch {
+		case qm.Exact != "":
+			if !present || values[0] != qm.Exact {
+				return false, "", nil
+			}
+		case qm.Regex != "":
+			if !present {
+				return false, "", nil
+			}
+			ok, err := fullRegexMatch(qm.Regex, values[0])
+			if err != nil || !ok {
+				return false, "", err
+			}
+		case qm.Present:
+			if !present {
+				return false, "", nil
+			}
+		}
+	}
+
+	if len(m.Methods) > 0 {
+		found := false
+		for _, method := range m.Methods {
+			if strings.EqualFold(method, r.Method) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, "", nil
+		}
+	}
+
+	return true, prefix, nil
+}
+
+// headerMatches evaluates a header match of a route like Envoy does: the
+// values of a repeated header are joined with commas and a missing header
+// only matches an inverted presence match.
+func headerMatches(hdr api.ServiceRouteHTTPMatchHeader, r *http.Request) (bool, error) {
+	var value string
+	var present bool
+	if name := strings.ToLower(hdr.Name); name == "host" || name == ":authority" {
+		value, present = r.Host, true
+	} else {
+		values, ok := r.Header[http.CanonicalHeaderKey(hdr.Name)]
+		value, present = strings.Join(values, ","), ok
+	}
+	if !present {
+		return hdr.Present && hdr.Invert, nil
+	}
+
+	var matched bool
+	switch {
+	case hdr.Exact != "":
+		matched = value == hdr.Exact
+	case hdr.Regex != "":
+		ok, err := fullRegexMatch(hdr.Regex, value)
+		if err != nil {
+			return false, err
+		}
+		matched = ok
+	case hdr.Prefix != "":
+		matched = strings.HasPrefix(value, hdr.Prefix)
+	case hdr.Suffix != "":
+		matched = strings.HasSuffix(value, hdr.Suffix)
+	case hdr.Present:
+		matched = true
+	default:
+		// Envoy skips the impossible matches.
+		return true, nil
+	}
+	return matched != hdr.Invert, nil
+}
+
+// fullRegexMatch returns whether the whole value matches the regex, which is
+// how Envoy evaluates its safe regex matchers.
+func fullRegexMatch(expr, value string) (bool, error) {
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return false, fmt.Errorf("invalid regex %q: %w", expr, err)
+	}
+	return re.MatchString(value), nil
+}
@@ -0,0 +1,258 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// isHTTPProtocol returns whether the requests of a service using the given
+// protocol are parsed by the proxy.
+func isHTTPProtocol(protocol string) bool {
+	switch protocol {
+	case "http", "http2", "grpc":
+		return true
+	default:
+		return false
+	}
+}
+
+// isHTTP2Protocol returns whether a service using the given protocol only
+// accepts HTTP/2 requests.
+func isHTTP2Protocol(protocol string) bool {
+	return protocol == "http2" || protocol == "grpc"
+}
+
+// connContextKey is the context key of the connection a request was received
+// on.
+type connContextKey struct{}
+
+// serveHTTP serves the requests received by the listener with its httpHandler
+// until it is closed. HTTP/2 is used when it is negotiated with ALPN or when
+// the client uses prior knowledge, otherwise HTTP/1.1 is used.
+func (l *Listener) serveHTTP(listener net.Listener) error {
+	srv := &http.Server{
+		Handler:   h2c.NewHandler(l.httpHandler, &http2.Server{}),
+		ConnState: l.trackHTTPConn,
+		ConnContext: func(ctx context.Context, conn net.Conn) context.Context {
+			return context.WithValue(ctx, connContextKey{}, conn)
+		},
+		ErrorLog: l.logger.StandardLogger(&hclog.StandardLoggerOptions{InferLevels: true}),
+	}
+	l.setHTTPServer(srv)
+
+	err := srv.Serve(listener)
+	if errors.Is(err, http.ErrServerClosed) || atomic.LoadInt32(&l.stopFlag) == 1 {
+		return nil
+	}
+	return err
+}
+
+// trackHTTPConn maintains the count of active conns of an HTTP listener. The
+// connections upgraded to HTTP/2 with prior knowledge are hijacked from the
+// server, so they stop being counted once upgraded.
+func (l *Listener) trackHTTPConn(_ net.Conn, state http.ConnState) {
+	switch state {
+	case http.StateNew:
+		l.addActiveConns(1)
+	case http.StateHijacked, http.StateClosed:
+		l.addActiveConns(-1)
+	}
+}
+
+// reportRequest records the metrics of a request served by the listener.
+func (l *Listener) reportRequest(start time.Time, w *statusWriter) {
+	labels := append([]metrics.Label{{Name: "code", Value: strconv.Itoa(w.Status())}}, l.metricLabels...)
+	metrics.IncrCounterWithLabels([]string{l.metricPrefix, "requests"}, 1, labels)
+	metrics.MeasureSinceWithLabels([]string{l.metricPrefix, "request_time"}, start, l.metricLabels)
+}
+
+// statusWriter is an http.ResponseWriter that records the status code of the
+// response for the metrics.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader implements http.ResponseWriter
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher, which is required to proxy streaming
+// responses such as gRPC ones.
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the wrapped http.ResponseWriter for http.ResponseController.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Status returns the status code of the response.
+func (w *statusWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// writeProxyError writes the response of a request that could not be proxied.
+// The status codes and messages are the ones of Envoy so that the
+// applications behave the same with both proxies.
+func writeProxyError(w http.ResponseWriter, err error) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		http.Error(w, "upstream request timeout", http.StatusGatewayTimeout)
+		return
+	}
+	http.Error(w, "upstream connect error or disconnect/reset before headers", http.StatusServiceUnavailable)
+}
+
+// localTransport returns the transport used to send requests to the local
+// application, which speaks HTTP/2 without TLS when its protocol requires it.
+func localTransport(protocol string, connectTimeout time.Duration) http.RoundTripper {
+	dialer := &net.Dialer{Timeout: connectTimeout}
+	if isHTTP2Protocol(protocol) {
+		return &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, addr)
+			},
+		}
+	}
+	return &http.Transport{
+		DialContext:         dialer.DialContext,
+		MaxIdleConnsPerHost: 100,
+		IdleConnTimeout:     90 * time.Second,
+	}
+}
+
+// publicHTTPHandler authorizes the requests received by the public listener
+// against the L7 permissions of the intentions before proxying them to the
+// local application.
+type publicHTTPHandler struct {
+	listener *Listener
+	proxy    *httputil.ReverseProxy
+}
+
+func newPublicHTTPHandler(l *Listener, cfg PublicListenerConfig) *publicHTTPHandler {
+	proxy := httputil.NewSingleHostReverseProxy(&url.URL{
+		Scheme: "http",
+		Host:   cfg.LocalServiceAddress,
+	})
+	proxy.Transport = localTransport(cfg.Protocol,
+		time.Duration(cfg.LocalConnectTimeoutMs)*time.Millisecond)
+	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		l.logger.Error("failed to proxy request", "error", err)
+		writeProxyError(w, err)
+	}
+	// Stream the responses such as gRPC ones.
+	proxy.FlushInterval = -1
+
+	return &publicHTTPHandler{
+		listener: l,
+		proxy:    proxy,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *publicHTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sw := &statusWriter{ResponseWriter: w}
+	defer h.listener.reportRequest(time.Now(), sw)
+
+	cert := peerCertificate(r)
+	if cert == nil || len(cert.URIs) < 1 {
+		http.Error(sw, "RBAC: access denied", http.StatusForbidden)
+		return
+	}
+
+	resp, err := h.listener.Service.AuthorizeRequest(cert, httpAuthorizeParams(r))
+	if err != nil {
+		h.listener.logger.Error("authz call failed", "error", err)
+		http.Error(sw, "RBAC: authorization failed", http.StatusInternalServerError)
+		return
+	}
+	if !resp.Authorized {
+		h.listener.logger.Debug("request denied",
+			"client", cert.URIs[0].String(),
+			"method", r.Method,
+			"path", r.URL.Path,
+			"reason", resp.Reason,
+		)
+		http.Error(sw, "RBAC: access denied", http.StatusForbidden)
+		return
+	}
+
+	// Tell the application who the client is, like Envoy does.
+	r.Header.Set("X-Forwarded-Client-Cert", "URI="+cert.URIs[0].String())
+
+	h.proxy.ServeHTTP(sw, r)
+}
+
+// peerCertificate returns the leaf certificate presented by the client that
+// sent the request, if any.
+func peerCertificate(r *http.Request) *x509.Certificate {
+	state := r.TLS
+	if state == nil {
+		// The requests sent with HTTP/2 prior knowledge are served over the
+		// hijacked connection, which is not recognized as a TLS one.
+		if conn, ok := r.Context().Value(connContextKey{}).(*tls.Conn); ok {
+			cs := conn.ConnectionState()
+			state = &cs
+		}
+	}
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+	return state.PeerCertificates[0]
+}
+
+// httpAuthorizeParams returns the description of the request that is
+// evaluated against the L7 permissions of the intentions.
+func httpAuthorizeParams(r *http.Request) *api.AgentAuthorizeHTTPParams {
+	header := r.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	// Go removes the Host header from the request, but the permissions can
+	// match it.
+	header.Set("Host", r.Host)
+
+	return &api.AgentAuthorizeHTTPParams{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Header: header,
+	}
+}
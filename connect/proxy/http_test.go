@@ -0,0 +1,377 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+
+	agConnect "github.com/hashicorp/consul/agent/connect"
+	agMetrics "github.com/hashicorp/consul/agent/metrics"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/connect"
+	"github.com/hashicorp/consul/sdk/freeport"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestPublicListener_http(t *testing.T) {
+	// Can't enable t.Parallel since we rely on the global metrics instance.
+
+	ca := agConnect.TestCA(t, nil)
+	testApp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Header.Get("X-Forwarded-Client-Cert"))
+	}))
+	defer testApp.Close()
+
+	port := freeport.GetOne(t)
+	cfg := PublicListenerConfig{
+		BindAddress:           "127.0.0.1",
+		BindPort:              port,
+		LocalServiceAddress:   testApp.Listener.Addr().String(),
+		HandshakeTimeoutMs:    100,
+		LocalConnectTimeoutMs: 100,
+		Protocol:              "http",
+	}
+
+	sink := agMetrics.TestSetupMetrics(t, "consul.proxy.test")
+
+	svc := connect.TestService(t, "db", ca)
+	l := NewPublicListener(svc, cfg, testutil.Logger(t))
+
+	go func() {
+		if err := l.Serve(); err != nil {
+			t.Errorf("failed to listen: %v", err.Error())
+		}
+	}()
+	defer l.Close()
+	l.Wait()
+
+	client := connect.TestService(t, "web", ca)
+	dial := func(ctx context.Context) (net.Conn, error) {
+		return client.Dial(ctx, &connect.StaticResolver{
+			Addr:    TestLocalAddr(port),
+			CertURI: agConnect.TestSpiffeIDService(t, "db"),
+		})
+	}
+	clientURI := agConnect.TestSpiffeIDService(t, "web").URI().String()
+
+	t.Run("http/1.1", func(t *testing.T) {
+		// Don't offer h2 to force HTTP/1.1.
+		tlsCfg := connect.TestTLSConfig(t, "web", ca)
+		tlsCfg.NextProtos = nil
+		httpClient := &http.Client{Transport: &http.Transport{
+			DialTLSContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return tls.Dial("tcp", TestLocalAddr(port), tlsCfg)
+			},
+		}}
+		resp, err := httpClient.Get("https://db/")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, 1, resp.ProtoMajor)
+		require.Equal(t, "URI="+clientURI, string(body))
+	})
+
+	t.Run("http/2", func(t *testing.T) {
+		httpClient := &http.Client{Transport: &http2.Transport{
+			DialTLSContext: func(ctx context.Context, _, _ string, _ *tls.Config) (net.Conn, error) {
+				return dial(ctx)
+			},
+		}}
+		resp, err := httpClient.Get("https://db/")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, 2, resp.ProtoMajor)
+		require.Equal(t, "URI="+clientURI, string(body))
+	})
+
+	l.Close()
+
+	agMetrics.AssertCounter(t, sink, "consul.proxy.test.inbound.requests;code=200;dst=db", 2)
+}
+
+// testRouter is an upstreamRouter that routes all the requests to the same
+// route.
+type testRouter struct {
+	route *upstreamRoute
+}
+
+func (r *testRouter) Route(_ *http.Request) (*upstreamRoute, error) {
+	return r.route, nil
+}
+
+func TestUpstreamListener_http(t *testing.T) {
+	// Can't enable t.Parallel since we rely on the global metrics instance.
+
+	ca := agConnect.TestCA(t, nil)
+
+	// The application fails every other request.
+	var count int32
+	testApp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/flaky":
+			if atomic.AddInt32(&count, 1)%2 == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		case "/v2/slow":
+			time.Sleep(500 * time.Millisecond)
+		}
+		w.Header().Set("X-Remove", "true")
+		fmt.Fprintf(w, "%s %s", r.URL.Path, r.Header.Get("X-Added"))
+	}))
+	defer testApp.Close()
+
+	// Run the proxy of the upstream service in front of it.
+	publicPort := freeport.GetOne(t)
+	public := NewPublicListener(connect.TestService(t, "db", ca), PublicListenerConfig{
+		BindAddress:           "127.0.0.1",
+		BindPort:              publicPort,
+		LocalServiceAddress:   testApp.Listener.Addr().String(),
+		HandshakeTimeoutMs:    100,
+		LocalConnectTimeoutMs: 100,
+		Protocol:              "http",
+	}, testutil.Logger(t))
+	go func() {
+		if err := public.Serve(); err != nil {
+			t.Errorf("failed to listen: %v", err.Error())
+		}
+	}()
+	defer public.Close()
+	public.Wait()
+
+	cfg := UpstreamConfig{
+		DestinationType:      "service",
+		DestinationNamespace: "default",
+		DestinationName:      "db",
+		Config: map[string]interface{}{
+			"connect_timeout_ms": 100,
+			"protocol":           "http",
+		},
+		LocalBindAddress: "localhost",
+		LocalBindPort:    freeport.GetOne(t),
+	}
+
+	sink := agMetrics.TestSetupMetrics(t, "consul.proxy.test")
+
+	route := &upstreamRoute{
+		targetID: "db.default.default.dc1",
+		resolver: &connect.StaticResolver{
+			Addr:    TestLocalAddr(publicPort),
+			CertURI: agConnect.TestSpiffeIDService(t, "db"),
+		},
+		connectTimeout: 100 * time.Millisecond,
+		requestTimeout: 200 * time.Millisecond,
+		retry: retryPolicyForDestination(&api.ServiceRouteDestination{
+			RetryOnStatusCodes: []uint32{503},
+		}),
+		matchedPrefix: "/v1/",
+		prefixRewrite: "/v2/",
+		requestHeaders: &api.HTTPHeaderModifiers{
+			Set: map[string]string{"X-Added": "yes"},
+		},
+		responseHeaders: &api.HTTPHeaderModifiers{
+			Remove: []string{"X-Remove"},
+		},
+	}
+
+	svc := connect.TestService(t, "web", ca)
+	l := newUpstreamListener(svc, cfg, nil, &testRouter{route: route}, testutil.Logger(t))
+	go func() {
+		if err := l.Serve(); err != nil {
+			t.Errorf("failed to listen: %v", err.Error())
+		}
+	}()
+	defer l.Close()
+	l.Wait()
+
+	get := func(t *testing.T, path string) (*http.Response, string) {
+		resp, err := http.Get((&url.URL{Scheme: "http", Host: l.BindAddr(), Path: path}).String())
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(body)
+	}
+
+	t.Run("retry", func(t *testing.T) {
+		resp, body := get(t, "/v1/flaky")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "/v2/flaky yes", body)
+		require.Empty(t, resp.Header.Get("X-Remove"))
+		require.Equal(t, int32(2), atomic.LoadInt32(&count))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		resp, body := get(t, "/v1/slow")
+		require.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+		require.Contains(t, body, "upstream request timeout")
+	})
+
+	l.Close()
+
+	labels := "src=web;dst_type=service;dst=db"
+	agMetrics.AssertCounter(t, sink, "consul.proxy.test.upstream.requests;code=200;"+labels, 1)
+	agMetrics.AssertCounter(t, sink, "consul.proxy.test.upstream.requests;code=504;"+labels, 1)
+}
+
+func TestRouteDiscoveryChain(t *testing.T) {
+	chain := &api.CompiledDiscoveryChain{
+		ServiceName: "db",
+		StartNode:   "router:db.default.default",
+		Nodes: map[string]*api.DiscoveryGraphNode{
+			"router:db.default.default": {
+				Type: api.DiscoveryGraphNodeTypeRouter,
+				Name: "db.default.default",
+				Routes: []*api.DiscoveryRoute{
+					{
+						Definition: &api.ServiceRoute{
+							Match: &api.ServiceRouteMatch{HTTP: &api.ServiceRouteHTTPMatch{
+								PathPrefix: "/admin/",
+								Header: []api.ServiceRouteHTTPMatchHeader{
+									{Name: "x-debug", Present: true},
+								},
+								Methods: []string{"GET"},
+							}},
+							Destination: &api.ServiceRouteDestination{
+								Service:               "db-admin",
+								PrefixRewrite:         "/",
+								RequestTimeout:        5 * time.Second,
+								RetryOnConnectFailure: true,
+							},
+						},
+						NextNode: "resolver:db-admin.default.default.dc1",
+					},
+					{
+						Definition: &api.ServiceRoute{
+							Match: &api.ServiceRouteMatch{HTTP: &api.ServiceRouteHTTPMatch{
+								PathPrefix: "/",
+							}},
+						},
+						NextNode: "resolver:db.default.default.dc1",
+					},
+				},
+			},
+			"resolver:db-admin.default.default.dc1": {
+				Type: api.DiscoveryGraphNodeTypeResolver,
+				Resolver: &api.DiscoveryResolver{
+					ConnectTimeout: 5 * time.Second,
+					Target:         "db-admin.default.default.dc1",
+				},
+			},
+			"resolver:db.default.default.dc1": {
+				Type: api.DiscoveryGraphNodeTypeResolver,
+				Resolver: &api.DiscoveryResolver{
+					ConnectTimeout: 5 * time.Second,
+					RequestTimeout: 10 * time.Second,
+					Target:         "db.default.default.dc1",
+					Failover: &api.DiscoveryFailover{
+						Targets: []string{"db.default.default.dc2"},
+					},
+				},
+			},
+		},
+		Targets: map[string]*api.DiscoveryTarget{
+			"db-admin.default.default.dc1": {
+				ID:         "db-admin.default.default.dc1",
+				Service:    "db-admin",
+				Namespace:  "default",
+				Partition:  "default",
+				Datacenter: "dc1",
+			},
+			"db.default.default.dc1": {
+				ID:         "db.default.default.dc1",
+				Service:    "db",
+				Namespace:  "default",
+				Partition:  "default",
+				Datacenter: "dc1",
+			},
+			"db.default.default.dc2": {
+				ID:         "db.default.default.dc2",
+				Service:    "db",
+				Namespace:  "default",
+				Partition:  "default",
+				Datacenter: "dc2",
+			},
+		},
+	}
+	cfg := UpstreamConfig{DestinationName: "db"}
+
+	t.Run("matching route", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/admin/users", nil)
+		r.Header.Set("X-Debug", "1")
+
+		route, err := routeDiscoveryChain(nil, cfg, chain, r)
+		require.NoError(t, err)
+		require.Equal(t, "db-admin.default.default.dc1", route.targetID)
+		require.Equal(t, 5*time.Second, route.requestTimeout)
+		require.Equal(t, 5*time.Second, route.connectTimeout)
+		require.Equal(t, "/admin/", route.matchedPrefix)
+		require.Equal(t, "/", route.prefixRewrite)
+		require.Equal(t, &retryPolicy{numRetries: 1, retryOn: []string{"connect-failure"}}, route.retry)
+		require.IsType(t, &connect.ConsulResolver{}, route.resolver)
+	})
+
+	t.Run("default route", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/admin/users", nil)
+		r.Header.Set("X-Debug", "1")
+
+		route, err := routeDiscoveryChain(nil, cfg, chain, r)
+		require.NoError(t, err)
+		require.Equal(t, "db.default.default.dc1", route.targetID)
+		require.Equal(t, 10*time.Second, route.requestTimeout)
+		require.Nil(t, route.retry)
+
+		failover, ok := route.resolver.(*failoverResolver)
+		require.True(t, ok)
+		require.Len(t, failover.resolvers, 2)
+		require.Equal(t, "dc2", failover.resolvers[1].(*connect.ConsulResolver).Datacenter)
+	})
+}
+
+func TestRetryPolicy(t *testing.T) {
+	p := retryPolicyForDestination(&api.ServiceRouteDestination{
+		NumRetries:         3,
+		RetryOn:            []string{"gateway-error", "unavailable"},
+		RetryOnStatusCodes: []uint32{429},
+	})
+	require.Equal(t, uint32(3), p.numRetries)
+
+	resp := func(code int, header ...string) *http.Response {
+		r := &http.Response{StatusCode: code, Header: make(http.Header)}
+		for i := 0; i < len(header); i += 2 {
+			r.Header.Set(header[i], header[i+1])
+		}
+		return r
+	}
+
+	require.True(t, p.shouldRetry(nil, &dialError{err: fmt.Errorf("refused")}))
+	require.False(t, p.shouldRetry(nil, fmt.Errorf("reset")))
+	require.True(t, p.shouldRetry(resp(http.StatusBadGateway), nil))
+	require.True(t, p.shouldRetry(resp(http.StatusTooManyRequests), nil))
+	require.True(t, p.shouldRetry(resp(http.StatusOK, "Grpc-Status", "14"), nil))
+	require.False(t, p.shouldRetry(resp(http.StatusOK, "Grpc-Status", "13"), nil))
+	require.False(t, p.shouldRetry(resp(http.StatusInternalServerError), nil))
+
+	require.Nil(t, retryPolicyForDestination(&api.ServiceRouteDestination{}))
+}
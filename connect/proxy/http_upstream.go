@@ -0,0 +1,439 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http2"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/connect"
+)
+
+const (
+	// maxRetryBodySize is the size of the largest request body that is
+	// buffered so that the request can be retried. The requests with larger
+	// or streamed bodies are never retried.
+	maxRetryBodySize = 64 * 1024
+
+	// retryBackoff is the base interval between two attempts of a request,
+	// which is the one of Envoy.
+	retryBackoff = 25 * time.Millisecond
+)
+
+// upstreamRoute describes how a request sent to an HTTP upstream is proxied.
+type upstreamRoute struct {
+	// targetID identifies the instances the request is sent to. It is used as
+	// the host of the proxied requests so that the connections to different
+	// targets are pooled separately.
+	targetID string
+
+	// resolver returns the instance of the target to connect to.
+	resolver connect.Resolver
+
+	// connectTimeout bounds the time spent connecting to an instance.
+	connectTimeout time.Duration
+
+	// requestTimeout bounds the time spent proxying the request including all
+	// its retries. No timeout is applied when it is zero.
+	requestTimeout time.Duration
+
+	// retry is the retry policy of the request, or nil if it is never retried.
+	retry *retryPolicy
+
+	// matchedPrefix is replaced with prefixRewrite in the path of the request
+	// when prefixRewrite is set.
+	matchedPrefix string
+	prefixRewrite string
+
+	requestHeaders  *api.HTTPHeaderModifiers
+	responseHeaders *api.HTTPHeaderModifiers
+}
+
+// upstreamRouter picks the route of the requests sent to an HTTP upstream.
+type upstreamRouter interface {
+	Route(r *http.Request) (*upstreamRoute, error)
+}
+
+// staticRouter routes all the requests to the instances returned by the
+// resolver of the upstream, like the connections of a TCP upstream.
+type staticRouter struct {
+	cfg          UpstreamConfig
+	resolverFunc func(UpstreamConfig) (connect.Resolver, error)
+}
+
+// Route implements upstreamRouter
+func (s *staticRouter) Route(_ *http.Request) (*upstreamRoute, error) {
+	resolver, err := s.resolverFunc(s.cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &upstreamRoute{
+		targetID:       s.cfg.DestinationName,
+		resolver:       resolver,
+		connectTimeout: s.cfg.ConnectTimeout(),
+	}, nil
+}
+
+// routeContextKey is the context key of the route of a proxied request.
+type routeContextKey struct{}
+
+func routeFromContext(ctx context.Context) *upstreamRoute {
+	route, _ := ctx.Value(routeContextKey{}).(*upstreamRoute)
+	return route
+}
+
+// dialError is returned when no connection could be established to the
+// upstream, which is retried on connect-failure.
+type dialError struct {
+	err error
+}
+
+func (e *dialError) Error() string {
+	return e.err.Error()
+}
+
+func (e *dialError) Unwrap() error {
+	return e.err
+}
+
+// upstreamHTTPHandler proxies the requests received by an upstream listener
+// to the instances of the target picked by its router.
+type upstreamHTTPHandler struct {
+	listener *Listener
+	router   upstreamRouter
+	proxy    *httputil.ReverseProxy
+
+	// targets stores the last route of each target by target ID, which is
+	// used to dial the new connections of the target.
+	targets sync.Map
+}
+
+func newUpstreamHTTPHandler(l *Listener, router upstreamRouter, protocol string) *upstreamHTTPHandler {
+	h := &upstreamHTTPHandler{
+		listener: l,
+		router:   router,
+	}
+
+	var transport http.RoundTripper
+	if isHTTP2Protocol(protocol) {
+		transport = &http2.Transport{
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				return h.dial(ctx, addr)
+			},
+		}
+	} else {
+		transport = &http.Transport{
+			DialTLSContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return h.dial(ctx, addr)
+			},
+			// The instances may negotiate HTTP/2 since the Connect client
+			// always offers it.
+			ForceAttemptHTTP2:   true,
+			MaxIdleConnsPerHost: 100,
+			IdleConnTimeout:     90 * time.Second,
+		}
+	}
+
+	h.proxy = &httputil.ReverseProxy{
+		Director:       h.direct,
+		Transport:      &retryTransport{base: transport},
+		ModifyResponse: h.modifyResponse,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			l.logger.Error("failed to proxy request", "error", err)
+			writeProxyError(w, err)
+		},
+		// Stream the responses such as gRPC ones.
+		FlushInterval: -1,
+	}
+	return h
+}
+
+// ServeHTTP implements http.Handler
+func (h *upstreamHTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sw := &statusWriter{ResponseWriter: w}
+	defer h.listener.reportRequest(time.Now(), sw)
+
+	route, err := h.router.Route(r)
+	if err != nil {
+		h.listener.logger.Error("failed to route request", "error", err)
+		http.Error(sw, "no healthy upstream", http.StatusServiceUnavailable)
+		return
+	}
+	if route == nil {
+		http.Error(sw, "route not found", http.StatusNotFound)
+		return
+	}
+	h.targets.Store(route.targetID, route)
+
+	ctx := context.WithValue(r.Context(), routeContextKey{}, route)
+	if route.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, route.requestTimeout)
+		defer cancel()
+	}
+
+	h.proxy.ServeHTTP(sw, r.WithContext(ctx))
+}
+
+// direct rewrites the request for the target of its route.
+func (h *upstreamHTTPHandler) direct(r *http.Request) {
+	route := routeFromContext(r.Context())
+
+	r.URL.Scheme = "https"
+	r.URL.Host = route.targetID
+	if route.prefixRewrite != "" && strings.HasPrefix(r.URL.Path, route.matchedPrefix) {
+		r.URL.Path = route.prefixRewrite + strings.TrimPrefix(r.URL.Path, route.matchedPrefix)
+		r.URL.RawPath = ""
+	}
+	applyHeaderModifiers(r.Header, route.requestHeaders)
+
+	// Like in httputil.NewSingleHostReverseProxy, prevent the default
+	// User-Agent from being added.
+	if _, ok := r.Header["User-Agent"]; !ok {
+		r.Header.Set("User-Agent", "")
+	}
+}
+
+// modifyResponse applies the response header modifiers of the route.
+func (h *upstreamHTTPHandler) modifyResponse(resp *http.Response) error {
+	if route := routeFromContext(resp.Request.Context()); route != nil {
+		applyHeaderModifiers(resp.Header, route.responseHeaders)
+	}
+	return nil
+}
+
+// dial connects to an instance of the target identified by addr.
+func (h *upstreamHTTPHandler) dial(ctx context.Context, addr string) (net.Conn, error) {
+	targetID, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		targetID = addr
+	}
+	v, ok := h.targets.Load(targetID)
+	if !ok {
+		return nil, fmt.Errorf("unknown target %q", targetID)
+	}
+	route := v.(*upstreamRoute)
+
+	ctx, cancel := context.WithTimeout(ctx, route.connectTimeout)
+	defer cancel()
+	conn, err := h.listener.Service.Dial(ctx, route.resolver)
+	if err != nil {
+		return nil, &dialError{err: err}
+	}
+	return conn, nil
+}
+
+// applyHeaderModifiers modifies the given header like Envoy applies the
+// HTTPHeaderModifiers of the routes.
+func applyHeaderModifiers(header http.Header, mods *api.HTTPHeaderModifiers) {
+	if mods == nil {
+		return
+	}
+	for k, v := range mods.Add {
+		header.Add(k, v)
+	}
+	for k, v := range mods.Set {
+		header.Set(k, v)
+	}
+	for _, k := range mods.Remove {
+		header.Del(k)
+	}
+}
+
+// retryPolicy describes when a request is retried with the values of the
+// ServiceRouteDestination, which are the retry conditions of Envoy.
+type retryPolicy struct {
+	numRetries  uint32
+	retryOn     []string
+	statusCodes []int
+}
+
+// retryPolicyForDestination returns the retry policy of the requests routed
+// to the given destination, or nil if they are not retried.
+func retryPolicyForDestination(dest *api.ServiceRouteDestination) *retryPolicy {
+	if dest == nil {
+		return nil
+	}
+	if dest.NumRetries == 0 && !dest.RetryOnConnectFailure &&
+		len(dest.RetryOnStatusCodes) == 0 && len(dest.RetryOn) == 0 {
+		return nil
+	}
+
+	p := &retryPolicy{
+		numRetries: dest.NumRetries,
+	}
+	if p.numRetries == 0 {
+		// The default of Envoy.
+		p.numRetries = 1
+	}
+	p.retryOn = append(p.retryOn, dest.RetryOn...)
+	if dest.RetryOnConnectFailure {
+		p.retryOn = append(p.retryOn, "connect-failure")
+	}
+	if len(dest.RetryOnStatusCodes) > 0 {
+		p.retryOn = append(p.retryOn, "retriable-status-codes")
+		for _, code := range dest.RetryOnStatusCodes {
+			p.statusCodes = append(p.statusCodes, int(code))
+		}
+	}
+	return p
+}
+
+// shouldRetry returns whether an attempt that returned the given response or
+// error is retried.
+func (p *retryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	for _, cond := range p.retryOn {
+		if retryConditionMatches(cond, p.statusCodes, resp, err) {
+			return true
+		}
+	}
+	return false
+}
+
+func retryConditionMatches(cond string, statusCodes []int, resp *http.Response, err error) bool {
+	var dialErr *dialError
+	isDialErr := errors.As(err, &dialErr)
+
+	if err != nil {
+		switch cond {
+		case "connect-failure", "gateway-error":
+			return isDialErr
+		case "reset":
+			return !isDialErr
+		case "5xx":
+			return true
+		case "refused-stream":
+			var streamErr http2.StreamError
+			return errors.As(err, &streamErr) && streamErr.Code == http2.ErrCodeRefusedStream
+		}
+		return false
+	}
+
+	switch cond {
+	case "5xx":
+		return resp.StatusCode >= 500
+	case "gateway-error":
+		return resp.StatusCode == http.StatusBadGateway ||
+			resp.StatusCode == http.StatusServiceUnavailable ||
+			resp.StatusCode == http.StatusGatewayTimeout
+	case "retriable-4xx":
+		return resp.StatusCode == http.StatusConflict
+	case "retriable-status-codes":
+		for _, code := range statusCodes {
+			if resp.StatusCode == code {
+				return true
+			}
+		}
+	case "envoy-ratelimited":
+		return resp.Header.Get("X-Envoy-Ratelimited") != ""
+	case "cancelled", "deadline-exceeded", "internal", "resource-exhausted", "unavailable":
+		// gRPC failures are returned in the headers when there is no body.
+		return grpcStatusCondition(resp.Header.Get("Grpc-Status")) == cond
+	}
+	return false
+}
+
+// grpcStatusCondition returns the retry condition of the given gRPC status
+// code.
+func grpcStatusCondition(status string) string {
+	code, err := strconv.Atoi(status)
+	if err != nil {
+		return ""
+	}
+	switch code {
+	case 1:
+		return "cancelled"
+	case 4:
+		return "deadline-exceeded"
+	case 8:
+		return "resource-exhausted"
+	case 13:
+		return "internal"
+	case 14:
+		return "unavailable"
+	default:
+		return ""
+	}
+}
+
+// retryTransport retries the requests according to the retry policy of their
+// route.
+type retryTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *retryTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	route := routeFromContext(r.Context())
+	if route == nil || route.retry == nil {
+		return t.base.RoundTrip(r)
+	}
+
+	body, ok, err := bufferRequestBody(r)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return t.base.RoundTrip(r)
+	}
+
+	ctx := r.Context()
+	for attempt := uint32(0); ; attempt++ {
+		req := r
+		if attempt > 0 {
+			req = r.Clone(ctx)
+		}
+		if body != nil {
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= route.retry.numRetries || ctx.Err() != nil ||
+			!route.retry.shouldRetry(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, maxRetryBodySize))
+			resp.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(retryBackoff << attempt):
+		}
+	}
+}
+
+// bufferRequestBody reads the body of the request so that it can be sent
+// again. It returns false when the request can't be retried because its body
+// is too large or streamed.
+func bufferRequestBody(r *http.Request) ([]byte, bool, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, true, nil
+	}
+	if r.ContentLength < 0 || r.ContentLength > maxRetryBodySize {
+		return nil, false, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRetryBodySize))
+	r.Body.Close()
+	if err != nil {
+		return nil, false, err
+	}
+	return body, true, nil
+}
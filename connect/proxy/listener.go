@@ -8,6 +8,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"net"
+	"net/http"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -37,6 +38,11 @@ type Listener struct {
 	dialFunc   func() (net.Conn, error)
 	bindAddr   string
 
+	// httpHandler is set by the type-specific constructors when the requests
+	// are parsed. The connections are then served by httpServer instead of
+	// being proxied with dialFunc.
+	httpHandler http.Handler
+
 	stopFlag int32
 	stopChan chan struct{}
 
@@ -49,9 +55,10 @@ type Listener struct {
 	// this is cheap and correct.
 	listeningChan chan struct{}
 
-	// listenerLock guards access to the listener field
+	// listenerLock guards access to the listener and httpServer fields
 	listenerLock sync.Mutex
 	listener     net.Listener
+	httpServer   *http.Server
 
 	logger hclog.Logger
 
@@ -64,10 +71,12 @@ type Listener struct {
 
 // NewPublicListener returns a Listener setup to listen for public mTLS
 // connections and proxy them to the configured local application over TCP.
+// When the protocol of the service is HTTP based, the requests are authorized
+// individually against the L7 intentions instead.
 func NewPublicListener(svc *connect.Service, cfg PublicListenerConfig,
 	logger hclog.Logger) *Listener {
 	bindAddr := ipaddr.FormatAddressPort(cfg.BindAddress, cfg.BindPort)
-	return &Listener{
+	l := &Listener{
 		Service: svc,
 		listenFunc: func() (net.Listener, error) {
 			return tls.Listen("tcp", bindAddr, svc.ServerTLSConfig())
@@ -91,21 +100,45 @@ func NewPublicListener(svc *connect.Service, cfg PublicListenerConfig,
 		// seems for the extra complication of tracking many gauges here.
 		metricLabels: []metrics.Label{{Name: "dst", Value: svc.Name()}},
 	}
+	if isHTTPProtocol(cfg.Protocol) {
+		// The connection is authorized per request so the handshake only
+		// verifies the client certificate.
+		l.listenFunc = func() (net.Listener, error) {
+			return tls.Listen("tcp", bindAddr, svc.HTTPServerTLSConfig())
+		}
+		l.httpHandler = newPublicHTTPHandler(l, cfg)
+	}
+	return l
 }
 
 // NewUpstreamListener returns a Listener setup to listen locally for TCP
-// connections that are proxied to a discovered Connect service instance.
+// connections that are proxied to a discovered Connect service instance. When
+// the protocol of the upstream is HTTP based, the requests are routed with the
+// discovery chain of the upstream service instead.
 func NewUpstreamListener(svc *connect.Service, client *api.Client,
 	cfg UpstreamConfig, logger hclog.Logger) *Listener {
-	return newUpstreamListenerWithResolver(svc, cfg,
-		UpstreamResolverFuncFromClient(client), logger)
+	resolverFunc := UpstreamResolverFuncFromClient(client)
+	var router upstreamRouter
+	if isHTTPProtocol(cfg.Protocol()) && cfg.DestinationType != "prepared_query" {
+		router = newDiscoveryChainRouter(client, cfg, logger)
+	}
+	return newUpstreamListener(svc, cfg, resolverFunc, router, logger)
 }
 
 func newUpstreamListenerWithResolver(svc *connect.Service, cfg UpstreamConfig,
 	resolverFunc func(UpstreamConfig) (connect.Resolver, error),
 	logger hclog.Logger) *Listener {
+	return newUpstreamListener(svc, cfg, resolverFunc, nil, logger)
+}
+
+// newUpstreamListener returns an upstream Listener. The requests of an HTTP
+// upstream are routed with router, or to the instances returned by
+// resolverFunc when it is nil.
+func newUpstreamListener(svc *connect.Service, cfg UpstreamConfig,
+	resolverFunc func(UpstreamConfig) (connect.Resolver, error),
+	router upstreamRouter, logger hclog.Logger) *Listener {
 	bindAddr := ipaddr.FormatAddressPort(cfg.LocalBindAddress, cfg.LocalBindPort)
-	return &Listener{
+	l := &Listener{
 		Service: svc,
 		listenFunc: func() (net.Listener, error) {
 			return net.Listen("tcp", bindAddr)
@@ -132,6 +165,13 @@ func newUpstreamListenerWithResolver(svc *connect.Service, cfg UpstreamConfig,
 			{Name: "dst", Value: cfg.DestinationName},
 		},
 	}
+	if isHTTPProtocol(cfg.Protocol()) {
+		if router == nil {
+			router = &staticRouter{cfg: cfg, resolverFunc: resolverFunc}
+		}
+		l.httpHandler = newUpstreamHTTPHandler(l, router, cfg.Protocol())
+	}
+	return l
 }
 
 // Serve runs the listener until it is stopped. It is an error to call Serve
@@ -153,6 +193,10 @@ func (l *Listener) Serve() error {
 
 	close(l.listeningChan)
 
+	if l.httpHandler != nil {
+		return l.serveHTTP(listener)
+	}
+
 	for {
 		conn, err := listener.Accept()
 		if err != nil {
@@ -239,17 +283,19 @@ func (l *Listener) handleConn(src net.Conn) {
 // trackConn increments the count of active conns and returns a func() that can
 // be deferred on to decrement the counter again on connection close.
 func (l *Listener) trackConn() func() {
-	c := atomic.AddInt32(&l.activeConns, 1)
-	metrics.SetGaugeWithLabels([]string{l.metricPrefix, "conns"}, float32(c),
-		l.metricLabels)
-
+	l.addActiveConns(1)
 	return func() {
-		c := atomic.AddInt32(&l.activeConns, -1)
-		metrics.SetGaugeWithLabels([]string{l.metricPrefix, "conns"}, float32(c),
-			l.metricLabels)
+		l.addActiveConns(-1)
 	}
 }
 
+// addActiveConns adds delta to the count of active conns and reports it.
+func (l *Listener) addActiveConns(delta int32) {
+	c := atomic.AddInt32(&l.activeConns, delta)
+	metrics.SetGaugeWithLabels([]string{l.metricPrefix, "conns"}, float32(c),
+		l.metricLabels)
+}
+
 // Close terminates the listener and all active connections.
 func (l *Listener) Close() error {
 	// Prevent the listener from being started.
@@ -262,6 +308,9 @@ func (l *Listener) Close() error {
 	if listener := l.getListener(); listener != nil {
 		listener.Close()
 	}
+	if srv := l.getHTTPServer(); srv != nil {
+		srv.Close()
+	}
 
 	// Stop outstanding requests.
 	close(l.stopChan)
@@ -293,3 +342,15 @@ func (l *Listener) getListener() net.Listener {
 	defer l.listenerLock.Unlock()
 	return l.listener
 }
+
+func (l *Listener) setHTTPServer(srv *http.Server) {
+	l.listenerLock.Lock()
+	l.httpServer = srv
+	l.listenerLock.Unlock()
+}
+
+func (l *Listener) getHTTPServer() *http.Server {
+	l.listenerLock.Lock()
+	defer l.listenerLock.Unlock()
+	return l.httpServer
+}
@@ -184,6 +184,126 @@ func (cr *ConsulResolver) queryOptions(ctx context.Context) *api.QueryOptions {
 	return q.WithContext(ctx)
 }
 
+// DiscoveryChainResolver queries Consul for an instance of a service through
+// the compiled discovery chain of the service, so that the splitters and the
+// subsets, redirects and failovers of the resolvers apply like they do for the
+// proxies. Since the instance is picked for a connection and not for a
+// request, only the default route of a service router is followed.
+type DiscoveryChainResolver struct {
+	// Client is the Consul API client to use. Must be non-nil or Resolve will
+	// panic.
+	Client *api.Client
+
+	// Namespace of the service. Defaults to the namespace of the token.
+	Namespace string
+
+	// Partition of the service. Defaults to the partition of the token.
+	Partition string
+
+	// Name of the service to resolve.
+	Name string
+
+	// Datacenter to compile the discovery chain in. Defaults to the datacenter
+	// of the agent.
+	Datacenter string
+}
+
+// Resolve performs service discovery against the local Consul agent and
+// returns the address and expected identity of a suitable service instance.
+func (dr *DiscoveryChainResolver) Resolve(ctx context.Context) (string, connect.CertURI, error) {
+	q := &api.QueryOptions{
+		UseCache:  true,
+		Namespace: dr.Namespace,
+		Partition: dr.Partition,
+	}
+	resp, _, err := dr.Client.DiscoveryChain().Get(dr.Name,
+		&api.DiscoveryChainOptions{EvaluateInDatacenter: dr.Datacenter}, q.WithContext(ctx))
+	if err != nil {
+		return "", nil, err
+	}
+	chain := resp.Chain
+	if chain == nil {
+		return "", nil, fmt.Errorf("no discovery chain for service %q", dr.Name)
+	}
+
+	name := chain.StartNode
+	for {
+		node, ok := chain.Nodes[name]
+		if !ok {
+			return "", nil, fmt.Errorf("discovery chain node %q not found", name)
+		}
+
+		switch node.Type {
+		case api.DiscoveryGraphNodeTypeRouter:
+			// The compiler always ends the routes with the default one.
+			if len(node.Routes) == 0 {
+				return "", nil, fmt.Errorf("discovery chain router %q has no routes", node.Name)
+			}
+			name = node.Routes[len(node.Routes)-1].NextNode
+
+		case api.DiscoveryGraphNodeTypeSplitter:
+			if len(node.Splits) == 0 {
+				return "", nil, fmt.Errorf("discovery chain splitter %q has no splits", node.Name)
+			}
+			var total float32
+			for _, split := range node.Splits {
+				total += split.Weight
+			}
+			n := rand.Float32() * total
+			name = node.Splits[len(node.Splits)-1].NextNode
+			for _, split := range node.Splits {
+				if n < split.Weight {
+					name = split.NextNode
+					break
+				}
+				n -= split.Weight
+			}
+
+		case api.DiscoveryGraphNodeTypeResolver:
+			if node.Resolver == nil {
+				return "", nil, fmt.Errorf("discovery chain resolver node %q has no resolver", name)
+			}
+			targets := []string{node.Resolver.Target}
+			if node.Resolver.Failover != nil {
+				targets = append(targets, node.Resolver.Failover.Targets...)
+			}
+			return dr.resolveTargets(ctx, chain, targets)
+
+		default:
+			return "", nil, fmt.Errorf("unknown discovery chain node type %q", node.Type)
+		}
+	}
+}
+
+// resolveTargets returns an instance of the first target that has a healthy
+// one.
+func (dr *DiscoveryChainResolver) resolveTargets(ctx context.Context,
+	chain *api.CompiledDiscoveryChain, targets []string) (string, connect.CertURI, error) {
+	var err error
+	for _, id := range targets {
+		target, ok := chain.Targets[id]
+		if !ok {
+			return "", nil, fmt.Errorf("discovery chain target %q not found", id)
+		}
+		cr := &ConsulResolver{
+			Client:     dr.Client,
+			Namespace:  target.Namespace,
+			Partition:  target.Partition,
+			Name:       target.Service,
+			Type:       ConsulResolverTypeService,
+			Datacenter: target.Datacenter,
+			Filter:     target.Subset.Filter,
+		}
+		var addr string
+		var certURI connect.CertURI
+		addr, certURI, err = cr.Resolve(ctx)
+		if err == nil {
+			return addr, certURI, nil
+		}
+	}
+	return "", nil, err
+}
+
 // ConsulResolverFromAddrFunc returns a function for constructing ConsulResolver
 // from a consul DNS formatted hostname (e.g. foo.service.consul or
 // foo.query.consul).
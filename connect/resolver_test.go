@@ -264,6 +264,62 @@ func TestConsulResolver_Resolve(t *testing.T) {
 	}
 }
 
+func TestDiscoveryChainResolver_Resolve(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	agent := agent.StartTestAgent(t, agent.TestAgent{Name: "test-consul"})
+	defer agent.Shutdown()
+
+	cfg := api.DefaultConfig()
+	cfg.Address = agent.HTTPAddr()
+	client, err := api.NewClient(cfg)
+	require.NoError(t, err)
+
+	// Register a proxy for two versions of web.
+	for i, version := range []string{"v1", "v2"} {
+		err = client.Agent().ServiceRegister(&api.AgentServiceRegistration{
+			Kind: "connect-proxy",
+			ID:   "web-proxy-" + version,
+			Name: "web-proxy",
+			Port: 9090 + i,
+			Proxy: &api.AgentServiceConnectProxyConfig{
+				DestinationServiceName: "web",
+			},
+			Meta: map[string]string{"version": version},
+		})
+		require.NoError(t, err)
+	}
+
+	// Only send the connections to v2.
+	_, _, err = client.ConfigEntries().Set(&api.ServiceResolverConfigEntry{
+		Kind:          api.ServiceResolver,
+		Name:          "web",
+		DefaultSubset: "v2",
+		Subsets: map[string]api.ServiceResolverSubset{
+			"v1": {Filter: "Service.Meta.version == v1"},
+			"v2": {Filter: "Service.Meta.version == v2"},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	dr := &DiscoveryChainResolver{
+		Client: client,
+		Name:   "web",
+	}
+	for i := 0; i < 5; i++ {
+		addr, certURI, err := dr.Resolve(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, agent.Config.AdvertiseAddrLAN.String()+":9091", addr)
+		require.Equal(t, connect.TestSpiffeIDServiceWithHost(t, "web", ""), certURI)
+	}
+
+	dr.Name = "foo"
+	_, _, err = dr.Resolve(context.Background())
+	require.Error(t, err)
+}
+
 func TestConsulResolverFromAddrFunc(t *testing.T) {
 	// Don't need an actual instance since we don't do the service discovery but
 	// we do want to assert the client is pass through correctly.
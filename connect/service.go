@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/hashicorp/consul/agent/connect"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/api/watch"
 	"github.com/hashicorp/consul/logging"
@@ -53,6 +54,10 @@ type Service struct {
 	rootsWatch *watch.Plan
 	leafWatch  *watch.Plan
 
+	// grpcAuthz caches the authorization of the RPCs received by the servers
+	// configured with GRPCServerOptions.
+	grpcAuthz grpcAuthzCache
+
 	logger hclog.Logger
 }
 
@@ -182,6 +187,42 @@ func (s *Service) ServerTLSConfig() *tls.Config {
 	return s.tlsCfg.Get(newServerSideVerifier(s.logger, s.client, s.service))
 }
 
+// HTTPServerTLSConfig returns a *tls.Config like ServerTLSConfig, except that
+// clients are only authenticated during the handshake and not authorized.
+// This lets the clients matching an L7 intention connect, so each request
+// received on the connection must be authorized with AuthorizeRequest.
+func (s *Service) HTTPServerTLSConfig() *tls.Config {
+	return s.tlsCfg.Get(newAuthenticatingServerSideVerifier(s.logger))
+}
+
+// AuthorizeRequest authorizes an HTTP request received from the client
+// presenting the given leaf certificate on a listener configured with
+// HTTPServerTLSConfig. The L7 permissions of the intention matching the client
+// are evaluated against the request.
+//
+// Like for ServerTLSConfig, there is no AuthZ if the Service has no client.
+func (s *Service) AuthorizeRequest(clientCert *x509.Certificate,
+	req *api.AgentAuthorizeHTTPParams) (*api.AgentAuthorize, error) {
+	if len(clientCert.URIs) < 1 {
+		return nil, errors.New("connect: invalid leaf certificate")
+	}
+
+	if s.client == nil {
+		return &api.AgentAuthorize{Authorized: true, Reason: "nil client provided"}, nil
+	}
+
+	resp, err := s.client.Agent().ConnectAuthorize(&api.AgentAuthorizeParams{
+		Target:           s.service,
+		ClientCertURI:    clientCert.URIs[0].String(),
+		ClientCertSerial: connect.EncodeSerialNumber(clientCert.SerialNumber),
+		HTTP:             req,
+	})
+	if err != nil {
+		return nil, errors.New("connect: authz call failed: " + err.Error())
+	}
+	return resp, nil
+}
+
 // Dial connects to a remote Connect-enabled server. The passed Resolver is used
 // to discover a single candidate instance which will be dialed and have it's
 // TLS certificate verified against the expected identity. Failures are returned
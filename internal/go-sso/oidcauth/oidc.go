@@ -5,6 +5,8 @@ package oidcauth
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -51,13 +53,15 @@ func (a *Authenticator) GetAuthCodeURL(ctx context.Context, redirectURI string,
 		Scopes:       scopes,
 	}
 
-	stateID, nonce, err := a.createOIDCState(redirectURI, statePayload)
+	stateID, state, err := a.createOIDCState(redirectURI, statePayload)
 	if err != nil {
 		return "", fmt.Errorf("error generating OAuth state: %v", err)
 	}
 
 	authCodeOpts := []oauth2.AuthCodeOption{
-		oidc.Nonce(nonce),
+		oidc.Nonce(state.nonce),
+		oauth2.SetAuthURLParam("code_challenge", codeChallengeS256(state.codeVerifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
 	}
 	if len(a.config.OIDCACRValues) > 0 {
 		authCodeOpts = append(authCodeOpts, oauth2.SetAuthURLParam("acr_values", strings.Join(a.config.OIDCACRValues, " ")))
@@ -104,7 +108,9 @@ func (a *Authenticator) ClaimsFromAuthCode(ctx context.Context, stateParam, code
 		Scopes:       []string{oidc.ScopeOpenID},
 	}
 
-	oauth2Token, err := oauth2Config.Exchange(oidcCtx, code)
+	oauth2Token, err := oauth2Config.Exchange(oidcCtx, code,
+		oauth2.SetAuthURLParam("code_verifier", state.codeVerifier),
+	)
 	if err != nil {
 		return nil, nil, &ProviderLoginFailedError{
 			Err: fmt.Errorf("Error exchanging oidc code: %w", err),
@@ -260,29 +266,40 @@ func (a *Authenticator) verifyOIDCState(stateID string) *oidcState {
 // createOIDCState make an expiring state object, associated with a random state ID
 // that is passed throughout the OAuth process. A nonce is also included in the
 // auth process, and for simplicity will be identical in length/format as the state ID.
-func (a *Authenticator) createOIDCState(redirectURI string, payload interface{}) (string, string, error) {
-	// Get enough bytes for 2 160-bit IDs (per rfc6749#section-10.10)
-	bytes, err := uuid.GenerateRandomBytes(2 * 20)
+// A PKCE code verifier (rfc7636) is generated alongside them.
+func (a *Authenticator) createOIDCState(redirectURI string, payload interface{}) (string, *oidcState, error) {
+	// Get enough bytes for 2 160-bit IDs (per rfc6749#section-10.10) and a
+	// 256-bit code verifier.
+	bytes, err := uuid.GenerateRandomBytes(2*20 + 32)
 	if err != nil {
-		return "", "", err
+		return "", nil, err
 	}
 
 	stateID := fmt.Sprintf("%x", bytes[:20])
-	nonce := fmt.Sprintf("%x", bytes[20:])
+	state := &oidcState{
+		nonce:        fmt.Sprintf("%x", bytes[20:40]),
+		codeVerifier: base64.RawURLEncoding.EncodeToString(bytes[40:]),
+		redirectURI:  redirectURI,
+		payload:      payload,
+	}
+
+	a.oidcStates.SetDefault(stateID, state)
 
-	a.oidcStates.SetDefault(stateID, &oidcState{
-		nonce:       nonce,
-		redirectURI: redirectURI,
-		payload:     payload,
-	})
+	return stateID, state, nil
+}
 
-	return stateID, nonce, nil
+// codeChallengeS256 derives the PKCE code challenge from a code verifier
+// using the S256 method.
+func codeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
 }
 
 // oidcState is created when an authURL is requested. The state
 // identifier is passed throughout the OAuth process.
 type oidcState struct {
-	nonce       string
-	redirectURI string
-	payload     interface{}
+	nonce        string
+	codeVerifier string
+	redirectURI  string
+	payload      interface{}
 }
@@ -77,6 +77,8 @@ func TestOIDC_AuthURL(t *testing.T) {
 			"redirect_uri":  "https://example.com",
 			"response_type": "code",
 			"scope":         "openid",
+			// PKCE
+			"code_challenge_method": "S256",
 			// optional values
 			"acr_values": "acr1 acr2",
 		}
@@ -90,6 +92,7 @@ func TestOIDC_AuthURL(t *testing.T) {
 
 		assert.Regexp(t, `^[a-z0-9]{40}$`, au.Query().Get("nonce"))
 		assert.Regexp(t, `^[a-z0-9]{40}$`, au.Query().Get("state"))
+		assert.Regexp(t, `^[A-Za-z0-9_-]{43}$`, au.Query().Get("code_challenge"))
 
 	})
 
@@ -283,6 +286,57 @@ func TestOIDC_ClaimsFromAuthCode(t *testing.T) {
 		require.Equal(t, expectedClaims, claims)
 	})
 
+	t.Run("successful login via provider", func(t *testing.T) {
+		oa, srv := setupForOIDC(t)
+
+		authURL, err := oa.GetAuthCodeURL(
+			context.Background(),
+			"https://example.com",
+			nil,
+		)
+		require.NoError(t, err)
+
+		// The provider echoes the nonce from the authorization request.
+		customClaims := sampleClaims("")
+		delete(customClaims, "nonce")
+		srv.SetCustomClaims(customClaims)
+		srv.SetExpectedAuthCode("abc")
+
+		redirect, err := srv.Authorize(authURL)
+		require.NoError(t, err)
+		require.Equal(t, "abc", redirect.Query().Get("code"))
+
+		claims, _, err := oa.ClaimsFromAuthCode(
+			context.Background(),
+			redirect.Query().Get("state"), redirect.Query().Get("code"),
+		)
+		require.NoError(t, err)
+		require.Equal(t, "green", claims.Values["color"])
+	})
+
+	t.Run("failed login - bad code verifier", func(t *testing.T) {
+		oa, srv := setupForOIDC(t)
+
+		authURL1, err := oa.GetAuthCodeURL(context.Background(), "https://example.com", nil)
+		require.NoError(t, err)
+		authURL2, err := oa.GetAuthCodeURL(context.Background(), "https://example.com", nil)
+		require.NoError(t, err)
+
+		srv.SetExpectedAuthCode("abc")
+
+		// The provider records the code challenge of the first request, so
+		// redeeming the code with the verifier of the second must fail.
+		_, err = srv.Authorize(authURL1)
+		require.NoError(t, err)
+
+		_, _, err = oa.ClaimsFromAuthCode(
+			context.Background(),
+			getQueryParam(t, authURL2, "state"), "abc",
+		)
+		requireErrorContains(t, err, "invalid code_verifier")
+		requireProviderError(t, err)
+	})
+
 	t.Run("failed login - bad nonce", func(t *testing.T) {
 		t.Parallel()
 
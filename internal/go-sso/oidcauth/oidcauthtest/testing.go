@@ -13,7 +13,9 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
@@ -55,6 +57,11 @@ type Server struct {
 	customAudience    string
 	omitIDToken       bool
 	disableUserInfo   bool
+
+	// authNonce and codeChallenge are recorded from the last /auth request
+	// so that /token can echo the nonce and verify the PKCE code verifier.
+	authNonce     string
+	codeChallenge string
 }
 
 type TestingT interface {
@@ -168,6 +175,28 @@ func (s *Server) Stop() {
 	s.httpServer.Close()
 }
 
+// Authorize simulates a browser visiting authURL, as returned by
+// GetAuthCodeURL, and returns the redirect URI the provider sent the user back
+// to, including its state and code or error parameters.
+func (s *Server) Authorize(authURL string) (*url.URL, error) {
+	client := s.httpServer.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	resp, err := client.Get(authURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected response from /auth: %d %s", resp.StatusCode, body)
+	}
+	return resp.Location()
+}
+
 // Addr returns the current base URL for the running webserver.
 func (s *Server) Addr() string { return s.httpServer.URL }
 
@@ -254,6 +283,15 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 			return
 		}
 
+		switch method := qv.Get("code_challenge_method"); method {
+		case "", "S256":
+		default:
+			writeAuthErrorResponse(w, req, "invalid_request", "unsupported code_challenge_method")
+			return
+		}
+		s.authNonce = nonce
+		s.codeChallenge = qv.Get("code_challenge")
+
 		redirectURI += "?state=" + url.QueryEscape(state) +
 			"&code=" + url.QueryEscape(s.expectedAuthCode)
 
@@ -293,6 +331,9 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		case req.FormValue("code") != s.expectedAuthCode:
 			_ = writeTokenErrorResponse(w, req, http.StatusUnauthorized, "invalid_grant", "unexpected auth code")
 			return
+		case s.codeChallenge != "" && s.codeChallenge != codeChallengeS256(req.FormValue("code_verifier")):
+			_ = writeTokenErrorResponse(w, req, http.StatusBadRequest, "invalid_grant", "invalid code_verifier")
+			return
 		}
 
 		stdClaims := jwt.Claims{
@@ -306,7 +347,18 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 			stdClaims.Audience = jwt.Audience{s.customAudience}
 		}
 
-		jwtData, err := SignJWT("", stdClaims, s.customClaims)
+		// Echo the nonce from the authorization request unless the test
+		// configured one explicitly.
+		claims := s.customClaims
+		if _, ok := claims["nonce"]; !ok && s.authNonce != "" {
+			claims = make(map[string]interface{}, len(s.customClaims)+1)
+			for k, v := range s.customClaims {
+				claims[k] = v
+			}
+			claims["nonce"] = s.authNonce
+		}
+
+		jwtData, err := SignJWT("", stdClaims, claims)
 		if err != nil {
 			_ = writeTokenErrorResponse(w, req, http.StatusInternalServerError, "server_error", err.Error())
 			return
@@ -508,3 +560,9 @@ func GenerateKey() (pub, priv string, err error) {
 
 	return pub, priv, nil
 }
+
+// codeChallengeS256 returns the PKCE S256 code challenge for verifier.
+func codeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -44,10 +44,39 @@ type LogFile struct {
 	// Max rotated files to keep before removing them.
 	MaxFiles int
 
+	//Mode is the permission used when creating log files, 0640 if unset
+	Mode os.FileMode
+
 	//acquire is the mutex utilized to ensure we have no concurrency issues
 	acquire sync.Mutex
 }
 
+// NewLogFile returns a LogFile that writes to path, rotating it every duration
+// or once it reaches maxBytes and keeping at most maxFiles rotated files. Any
+// stale rotated files are pruned and the active file is opened before
+// returning.
+func NewLogFile(path string, duration time.Duration, maxBytes, maxFiles int, mode os.FileMode) (*LogFile, error) {
+	dir, fileName := filepath.Split(path)
+	if fileName == "" {
+		return nil, fmt.Errorf("log file path %q must include a file name", path)
+	}
+	l := &LogFile{
+		fileName: fileName,
+		logPath:  dir,
+		duration: duration,
+		MaxBytes: maxBytes,
+		MaxFiles: maxFiles,
+		Mode:     mode,
+	}
+	if err := l.pruneFiles(); err != nil {
+		return nil, fmt.Errorf("failed to prune log files: %w", err)
+	}
+	if err := l.openNew(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
 func (l *LogFile) fileNamePattern() string {
 	// Extract the file extension
 	fileExt := filepath.Ext(l.fileName)
@@ -66,7 +95,11 @@ func (l *LogFile) openNew() error {
 	// Try creating or opening the active log file. Since the active log file
 	// always has the same name, append log entries to prevent overwriting
 	// previous log data.
-	filePointer, err := os.OpenFile(newfilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0640)
+	mode := l.Mode
+	if mode == 0 {
+		mode = 0640
+	}
+	filePointer, err := os.OpenFile(newfilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, mode)
 	if err != nil {
 		return err
 	}
@@ -99,7 +132,7 @@ func (l *LogFile) rotate() error {
 	// Get the time from the last point of contact
 	timeElapsed := time.Since(l.LastCreated)
 	// Rotate if we hit the byte file limit or the time limit
-	if (l.BytesWritten >= int64(l.MaxBytes) && (l.MaxBytes > 0)) || timeElapsed >= l.duration {
+	if (l.BytesWritten >= int64(l.MaxBytes) && (l.MaxBytes > 0)) || (l.duration > 0 && timeElapsed >= l.duration) {
 		l.FileInfo.Close()
 		if err := l.renameCurrentFile(); err != nil {
 			return err
@@ -161,3 +194,15 @@ func (l *LogFile) Write(b []byte) (n int, err error) {
 	l.BytesWritten += int64(len(b))
 	return l.FileInfo.Write(b)
 }
+
+// Close closes the active log file.
+func (l *LogFile) Close() error {
+	l.acquire.Lock()
+	defer l.acquire.Unlock()
+	if l.FileInfo == nil {
+		return nil
+	}
+	err := l.FileInfo.Close()
+	l.FileInfo = nil
+	return err
+}
@@ -0,0 +1,268 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build !consulent
+
+package sentinel
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// maxCompiledConditions bounds the number of compiled conditions that are
+// cached, so that repeatedly updated policies don't grow the cache forever.
+const maxCompiledConditions = 4096
+
+// scopeVariables are the top level variables that may be referenced by a
+// condition. Which of them are set depends on the request being authorized.
+var scopeVariables = map[string]struct{}{
+	"key":        {},
+	"value":      {},
+	"value_size": {},
+	"flags":      {},
+	"node":       {},
+	"service":    {},
+	"source_ip":  {},
+	"time":       {},
+	"token":      {},
+}
+
+// conditionEvaluator evaluates policies written as HCL expressions that
+// must evaluate to a boolean, such as:
+//
+//	value_size < 65536 && time.hour >= 9 && time.hour < 17
+//
+// A policy fails if it evaluates to false or cannot be evaluated, for example
+// because it references a variable that is not in the scope.
+type conditionEvaluator struct {
+	logger hclog.Logger
+
+	lock     sync.RWMutex
+	compiled map[string]hcl.Expression
+}
+
+func newConditionEvaluator(logger hclog.Logger) *conditionEvaluator {
+	if logger == nil {
+		logger = hclog.NewNullLogger()
+	}
+	return &conditionEvaluator{
+		logger:   logger,
+		compiled: make(map[string]hcl.Expression),
+	}
+}
+
+// Compile parses the policy and checks that it only references known
+// variables and functions.
+func (e *conditionEvaluator) Compile(policy string) error {
+	_, err := e.compile(policy)
+	return err
+}
+
+func (e *conditionEvaluator) compile(policy string) (hcl.Expression, error) {
+	e.lock.RLock()
+	expr, ok := e.compiled[policy]
+	e.lock.RUnlock()
+	if ok {
+		return expr, nil
+	}
+
+	expr, diags := hclsyntax.ParseExpression([]byte(policy), "condition", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("invalid condition: %s", diags.Error())
+	}
+	for _, traversal := range expr.Variables() {
+		name := traversal.RootName()
+		if _, ok := scopeVariables[name]; !ok {
+			return nil, fmt.Errorf("invalid condition: unknown variable %q, must be one of %s",
+				name, strings.Join(knownVariables(), ", "))
+		}
+	}
+	diags = hclsyntax.VisitAll(expr.(hclsyntax.Node), func(node hclsyntax.Node) hcl.Diagnostics {
+		call, ok := node.(*hclsyntax.FunctionCallExpr)
+		if !ok {
+			return nil
+		}
+		if _, ok := conditionFunctions[call.Name]; !ok {
+			return hcl.Diagnostics{{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("unknown function %q", call.Name),
+				Subject:  call.NameRange.Ptr(),
+			}}
+		}
+		return nil
+	})
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("invalid condition: %s", diags.Error())
+	}
+
+	e.lock.Lock()
+	if len(e.compiled) >= maxCompiledConditions {
+		e.compiled = make(map[string]hcl.Expression)
+	}
+	e.compiled[policy] = expr
+	e.lock.Unlock()
+
+	return expr, nil
+}
+
+// Execute returns whether the policy passes for the given scope. Failures of
+// advisory policies are logged but still pass.
+func (e *conditionEvaluator) Execute(policy string, enforcementLevel string, data map[string]interface{}) bool {
+	expr, err := e.compile(policy)
+	if err == nil {
+		var passed bool
+		passed, err = evaluateCondition(expr, data)
+		if err == nil && passed {
+			return true
+		}
+	}
+
+	if err != nil {
+		e.logger.Warn("failed to evaluate condition", "condition", policy, "error", err)
+	}
+	if enforcementLevel == EnforcementLevelAdvisory {
+		e.logger.Warn("advisory condition failed", "condition", policy)
+		return true
+	}
+	return false
+}
+
+func (e *conditionEvaluator) Close() {
+	e.lock.Lock()
+	e.compiled = make(map[string]hcl.Expression)
+	e.lock.Unlock()
+}
+
+func evaluateCondition(expr hcl.Expression, data map[string]interface{}) (bool, error) {
+	vars, err := scopeToCty(data)
+	if err != nil {
+		return false, err
+	}
+
+	val, diags := expr.Value(&hcl.EvalContext{
+		Variables: vars,
+		Functions: conditionFunctions,
+	})
+	if diags.HasErrors() {
+		return false, diags
+	}
+
+	val, err = convert.Convert(val, cty.Bool)
+	if err != nil {
+		return false, fmt.Errorf("condition must evaluate to a boolean: %w", err)
+	}
+	if val.IsNull() || !val.IsKnown() {
+		return false, fmt.Errorf("condition evaluated to null")
+	}
+	return val.True(), nil
+}
+
+// scopeToCty converts the scope to cty values. The scope is round tripped
+// through JSON so that any value that can be encoded as JSON, including
+// structs, may be used in a scope.
+func scopeToCty(data map[string]interface{}) (map[string]cty.Value, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	buf, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode scope: %w", err)
+	}
+	ty, err := ctyjson.ImpliedType(buf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode scope: %w", err)
+	}
+	val, err := ctyjson.Unmarshal(buf, ty)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode scope: %w", err)
+	}
+	return val.AsValueMap(), nil
+}
+
+func knownVariables() []string {
+	names := make([]string, 0, len(scopeVariables))
+	for name := range scopeVariables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// conditionFunctions are the functions that may be called by a condition.
+var conditionFunctions = map[string]function.Function{
+	"cidrmatch":  cidrMatchFunc,
+	"contains":   stdlib.ContainsFunc,
+	"endswith":   endsWithFunc,
+	"length":     stdlib.LengthFunc,
+	"lookup":     stdlib.LookupFunc,
+	"lower":      stdlib.LowerFunc,
+	"regexmatch": regexMatchFunc,
+	"startswith": startsWithFunc,
+	"upper":      stdlib.UpperFunc,
+}
+
+var startsWithFunc = stringPredicateFunc("str", "prefix", strings.HasPrefix)
+
+var endsWithFunc = stringPredicateFunc("str", "suffix", strings.HasSuffix)
+
+// regexMatchFunc returns whether the string matches the regular expression.
+var regexMatchFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{Name: "pattern", Type: cty.String},
+		{Name: "str", Type: cty.String},
+	},
+	Type: function.StaticReturnType(cty.Bool),
+	Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+		re, err := regexp.Compile(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(0, "invalid regular expression: %s", err)
+		}
+		return cty.BoolVal(re.MatchString(args[1].AsString())), nil
+	},
+})
+
+// cidrMatchFunc returns whether the IP address is within the CIDR block.
+var cidrMatchFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{Name: "ip", Type: cty.String},
+		{Name: "cidr", Type: cty.String},
+	},
+	Type: function.StaticReturnType(cty.Bool),
+	Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+		_, network, err := net.ParseCIDR(args[1].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(1, "invalid CIDR block: %s", err)
+		}
+		ip := net.ParseIP(args[0].AsString())
+		return cty.BoolVal(ip != nil && network.Contains(ip)), nil
+	},
+})
+
+func stringPredicateFunc(first, second string, fn func(string, string) bool) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: first, Type: cty.String},
+			{Name: second, Type: cty.String},
+		},
+		Type: function.StaticReturnType(cty.Bool),
+		Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+			return cty.BoolVal(fn(args[0].AsString(), args[1].AsString())), nil
+		},
+	})
+}
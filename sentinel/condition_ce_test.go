@@ -0,0 +1,148 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build !consulent
+
+package sentinel
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionEvaluator_Compile(t *testing.T) {
+	cases := map[string]struct {
+		condition string
+		expectErr string
+	}{
+		"valid": {
+			condition: `startswith(key, "app/") && value_size < 65536`,
+		},
+		"nested variables": {
+			condition: `time.hour >= 9 && contains(token.policies, "ops")`,
+		},
+		"syntax error": {
+			condition: `key ==`,
+			expectErr: "invalid condition",
+		},
+		"unknown function": {
+			condition: `file("secrets") == key`,
+			expectErr: `unknown function "file"`,
+		},
+		"unknown variable": {
+			condition: `size < 10`,
+			expectErr: `unknown variable "size"`,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			err := New(nil).Compile(tc.condition)
+			if tc.expectErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectErr)
+			}
+		})
+	}
+}
+
+func TestConditionEvaluator_Execute(t *testing.T) {
+	// Monday 2023-10-02 10:30 UTC
+	businessHours := time.Date(2023, time.October, 2, 10, 30, 0, 0, time.UTC)
+	// Saturday 2023-10-07 22:00 UTC
+	weekend := time.Date(2023, time.October, 7, 22, 0, 0, 0, time.UTC)
+
+	scope := func(key string, value []byte, at time.Time) map[string]interface{} {
+		return AddRequest(ScopeKVUpsert(key, value, 0), Request{
+			SourceIP: "10.0.1.5",
+			Time:     at,
+			Token: Token{
+				AccessorID:  "a5e0b8b5-6d8f-4f77-a2b5-1c1a0e7c0d42",
+				Description: "deployer",
+				Policies:    []string{"app-write"},
+			},
+		})
+	}
+
+	const businessHoursCondition = `value_size < 65536 && time.hour >= 9 && time.hour < 17 && !contains(["Saturday", "Sunday"], time.weekday)`
+
+	cases := map[string]struct {
+		condition string
+		scope     map[string]interface{}
+		expect    bool
+	}{
+		"business hours": {
+			condition: businessHoursCondition,
+			scope:     scope("app/config", []byte("small"), businessHours),
+			expect:    true,
+		},
+		"outside of business hours": {
+			condition: businessHoursCondition,
+			scope:     scope("app/config", []byte("small"), weekend),
+			expect:    false,
+		},
+		"value too large": {
+			condition: businessHoursCondition,
+			scope:     scope("app/config", []byte(strings.Repeat("a", 65536)), businessHours),
+			expect:    false,
+		},
+		"source ip": {
+			condition: `cidrmatch(source_ip, "10.0.0.0/16")`,
+			scope:     scope("app/config", nil, businessHours),
+			expect:    true,
+		},
+		"source ip outside of block": {
+			condition: `cidrmatch(source_ip, "192.168.0.0/16")`,
+			scope:     scope("app/config", nil, businessHours),
+			expect:    false,
+		},
+		"token": {
+			condition: `contains(token.policies, "app-write") && token.description == "deployer"`,
+			scope:     scope("app/config", nil, businessHours),
+			expect:    true,
+		},
+		"no policies": {
+			condition: `contains(token.roles, "admin")`,
+			scope:     scope("app/config", nil, businessHours),
+			expect:    false,
+		},
+		"regular expression": {
+			condition: `regexmatch("^app/[a-z]+$", key) && endswith(key, "config")`,
+			scope:     scope("app/config", nil, businessHours),
+			expect:    true,
+		},
+		"missing scope": {
+			condition: `value_size < 65536`,
+			expect:    false,
+		},
+		"not a boolean": {
+			condition: `key`,
+			scope:     scope("app/config", nil, businessHours),
+			expect:    false,
+		},
+		"invalid condition": {
+			condition: `key ==`,
+			scope:     scope("app/config", nil, businessHours),
+			expect:    false,
+		},
+	}
+
+	evaluator := New(nil)
+	defer evaluator.Close()
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expect, evaluator.Execute(tc.condition, EnforcementLevelHardMandatory, tc.scope))
+		})
+	}
+
+	t.Run("advisory", func(t *testing.T) {
+		require.True(t, evaluator.Execute(`value_size < 1`, EnforcementLevelAdvisory,
+			scope("app/config", []byte("value"), businessHours)))
+	})
+}
@@ -3,6 +3,14 @@
 
 package sentinel
 
+// Enforcement levels control what happens when a policy fails. Advisory
+// policies only log the failure, while mandatory policies deny the request.
+const (
+	EnforcementLevelAdvisory      = "advisory"
+	EnforcementLevelSoftMandatory = "soft-mandatory"
+	EnforcementLevelHardMandatory = "hard-mandatory"
+)
+
 // Evaluator wraps the Sentinel evaluator from the HashiCorp Sentinel policy
 // engine.
 type Evaluator interface {
@@ -4,6 +4,8 @@
 package sentinel
 
 import (
+	"time"
+
 	"github.com/hashicorp/consul/api"
 )
 
@@ -16,9 +18,10 @@ type ScopeFn func() map[string]interface{}
 // ScopeKVUpsert returns the standard sentinel scope for a KV create or update.
 func ScopeKVUpsert(key string, value []byte, flags uint64) map[string]interface{} {
 	return map[string]interface{}{
-		"key":   key,
-		"value": string(value),
-		"flags": flags,
+		"key":        key,
+		"value":      string(value),
+		"value_size": len(value),
+		"flags":      flags,
 	}
 }
 
@@ -30,3 +33,65 @@ func ScopeCatalogUpsert(node *api.Node, service *api.AgentService) map[string]in
 		"service": service,
 	}
 }
+
+// Request describes the request being authorized.
+type Request struct {
+	// SourceIP is the IP address of the client that made the request. It is
+	// empty if unknown.
+	SourceIP string
+
+	// Time is the time the request is being authorized at.
+	Time time.Time
+
+	// Token describes the ACL token used for the request.
+	Token Token
+}
+
+// Token describes an ACL token in a sentinel scope.
+type Token struct {
+	AccessorID        string
+	Description       string
+	AuthMethod        string
+	Local             bool
+	Policies          []string
+	Roles             []string
+	ServiceIdentities []string
+	NodeIdentities    []string
+}
+
+// AddRequest adds the details of the request being authorized to the scope.
+// Times are in UTC.
+func AddRequest(scope map[string]interface{}, req Request) map[string]interface{} {
+	now := req.Time.UTC()
+
+	scope["source_ip"] = req.SourceIP
+	scope["time"] = map[string]interface{}{
+		"unix":    now.Unix(),
+		"year":    now.Year(),
+		"month":   int(now.Month()),
+		"day":     now.Day(),
+		"hour":    now.Hour(),
+		"minute":  now.Minute(),
+		"weekday": now.Weekday().String(),
+	}
+	scope["token"] = map[string]interface{}{
+		"accessor_id":        req.Token.AccessorID,
+		"description":        req.Token.Description,
+		"auth_method":        req.Token.AuthMethod,
+		"local":              req.Token.Local,
+		"policies":           nonNil(req.Token.Policies),
+		"roles":              nonNil(req.Token.Roles),
+		"service_identities": nonNil(req.Token.ServiceIdentities),
+		"node_identities":    nonNil(req.Token.NodeIdentities),
+	}
+	return scope
+}
+
+// nonNil returns an empty list for nil lists, so that they can be used with
+// list functions such as contains.
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
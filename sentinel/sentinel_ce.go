@@ -9,8 +9,9 @@ import (
 	"github.com/hashicorp/go-hclog"
 )
 
-// New returns a new instance of the Sentinel code engine. This is only available
-// in Consul Enterprise so this version always returns nil.
+// New returns a new instance of the policy engine. The Sentinel language is
+// only available in Consul Enterprise, so this version returns an evaluator
+// for conditions written as HCL expressions instead.
 func New(logger hclog.Logger) Evaluator {
-	return nil
+	return newConditionEvaluator(logger)
 }
@@ -68,17 +68,9 @@ func New(logger hclog.Logger, r *raft.Raft) (*Snapshot, error) {
 		}
 	}()
 
-	// Wrap the file writer in a gzip compressor.
-	compressor := gzip.NewWriter(archive)
-
-	// Write the archive.
-	if err := write(compressor, metadata, snap); err != nil {
-		return nil, fmt.Errorf("failed to write snapshot file: %v", err)
-	}
-
-	// Finish the compressed stream.
-	if err := compressor.Close(); err != nil {
-		return nil, fmt.Errorf("failed to compress snapshot file: %v", err)
+	// Write the compressed archive.
+	if err := Write(archive, metadata, snap); err != nil {
+		return nil, err
 	}
 
 	// Sync the compressed file and rewind it so it's ready to be streamed
@@ -94,6 +86,25 @@ func New(logger hclog.Logger, r *raft.Raft) (*Snapshot, error) {
 	return &Snapshot{archive, metadata.Index}, nil
 }
 
+// Write creates a gzipped snapshot archive in the same format as New from the
+// given metadata and raw FSM state, such as a snapshot read directly from a
+// server's data directory. Exactly metadata.Size bytes are read from snap.
+func Write(out io.Writer, metadata *raft.SnapshotMeta, snap io.Reader) error {
+	// Wrap the writer in a gzip compressor.
+	compressor := gzip.NewWriter(out)
+
+	// Write the archive.
+	if err := write(compressor, metadata, snap); err != nil {
+		return fmt.Errorf("failed to write snapshot file: %v", err)
+	}
+
+	// Finish the compressed stream.
+	if err := compressor.Close(); err != nil {
+		return fmt.Errorf("failed to compress snapshot file: %v", err)
+	}
+	return nil
+}
+
 // Index returns the index of the snapshot. This is safe to call on a nil
 // snapshot, it will just return 0.
 func (s *Snapshot) Index() uint64 {